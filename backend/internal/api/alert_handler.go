@@ -0,0 +1,114 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ofkm/arcane-backend/internal/dto"
+	"github.com/ofkm/arcane-backend/internal/middleware"
+	"github.com/ofkm/arcane-backend/internal/services"
+)
+
+type AlertHandler struct {
+	alertService *services.AlertService
+}
+
+func NewAlertHandler(group *gin.RouterGroup, alertService *services.AlertService, authMiddleware *middleware.AuthMiddleware) {
+	handler := &AlertHandler{alertService: alertService}
+
+	apiGroup := group.Group("/environments/:id/alerts")
+	apiGroup.Use(authMiddleware.WithAdminRequired().Add())
+	{
+		apiGroup.GET("", handler.ListAlerts)
+		apiGroup.GET("/rules", handler.ListRules)
+		apiGroup.POST("/rules", handler.CreateRule)
+		apiGroup.PUT("/rules/:ruleId", handler.UpdateRule)
+		apiGroup.DELETE("/rules/:ruleId", handler.DeleteRule)
+	}
+}
+
+// ListAlerts returns pending and firing alerts; pass ?resolved=true to include resolved ones.
+func (h *AlertHandler) ListAlerts(c *gin.Context) {
+	states, err := h.alertService.ListStates(c.Request.Context(), c.Query("resolved") == "true")
+	if err != nil {
+		h.writeAlertError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": states})
+}
+
+func (h *AlertHandler) ListRules(c *gin.Context) {
+	rules, err := h.alertService.ListRules(c.Request.Context())
+	if err != nil {
+		h.writeAlertError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": rules})
+}
+
+func (h *AlertHandler) CreateRule(c *gin.Context) {
+	var req dto.AlertRuleDto
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"data":    gin.H{"error": "Invalid request: " + err.Error()},
+		})
+		return
+	}
+
+	rule, err := h.alertService.CreateRule(c.Request.Context(), req)
+	if err != nil {
+		h.writeAlertError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"success": true, "data": rule})
+}
+
+func (h *AlertHandler) UpdateRule(c *gin.Context) {
+	var req dto.AlertRuleDto
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"data":    gin.H{"error": "Invalid request: " + err.Error()},
+		})
+		return
+	}
+
+	rule, err := h.alertService.UpdateRule(c.Request.Context(), c.Param("ruleId"), req)
+	if err != nil {
+		h.writeAlertError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": rule})
+}
+
+func (h *AlertHandler) DeleteRule(c *gin.Context) {
+	if err := h.alertService.DeleteRule(c.Request.Context(), c.Param("ruleId")); err != nil {
+		h.writeAlertError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    gin.H{"message": "Alert rule deleted successfully"},
+	})
+}
+
+func (h *AlertHandler) writeAlertError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, services.ErrAlertRuleNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, services.ErrInvalidAlertRule):
+		status = http.StatusBadRequest
+	}
+	c.JSON(status, gin.H{
+		"success": false,
+		"data":    gin.H{"error": err.Error()},
+	})
+}
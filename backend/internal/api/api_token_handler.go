@@ -0,0 +1,114 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ofkm/arcane-backend/internal/dto"
+	"github.com/ofkm/arcane-backend/internal/middleware"
+	"github.com/ofkm/arcane-backend/internal/models"
+	"github.com/ofkm/arcane-backend/internal/services"
+)
+
+type ApiTokenHandler struct {
+	apiTokenService *services.ApiTokenService
+}
+
+func NewApiTokenHandler(group *gin.RouterGroup, apiTokenService *services.ApiTokenService, authMiddleware *middleware.AuthMiddleware) {
+	handler := &ApiTokenHandler{apiTokenService: apiTokenService}
+
+	tokens := group.Group("/users/:id/tokens")
+	tokens.Use(authMiddleware.WithAdminNotRequired().Add())
+	{
+		tokens.GET("", handler.ListTokens)
+		tokens.POST("", handler.CreateToken)
+		tokens.DELETE("/:tokenId", handler.DeleteToken)
+	}
+}
+
+func (h *ApiTokenHandler) ListTokens(c *gin.Context) {
+	if !h.canManage(c) {
+		return
+	}
+
+	tokens, err := h.apiTokenService.ListTokens(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "data": gin.H{"error": "Failed to list API tokens: " + err.Error()}})
+		return
+	}
+
+	out, err := dto.MapSlice[models.ApiToken, dto.ApiTokenDto](tokens)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "data": gin.H{"error": "Failed to map API tokens"}})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": out})
+}
+
+func (h *ApiTokenHandler) CreateToken(c *gin.Context) {
+	if !h.canManage(c) {
+		return
+	}
+	// Tokens must not be able to mint further tokens.
+	if middleware.IsApiTokenRequest(c) {
+		c.JSON(http.StatusForbidden, gin.H{"success": false, "data": gin.H{"error": "API tokens cannot be used to create API tokens"}})
+		return
+	}
+
+	var req dto.CreateApiTokenDto
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "data": gin.H{"error": "Invalid request format"}})
+		return
+	}
+
+	token, raw, err := h.apiTokenService.CreateToken(c.Request.Context(), c.Param("id"), req)
+	if err != nil {
+		if errors.Is(err, services.ErrApiTokenRequest) {
+			c.JSON(http.StatusBadRequest, gin.H{"success": false, "data": gin.H{"error": err.Error()}})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "data": gin.H{"error": "Failed to create API token"}})
+		return
+	}
+
+	out, err := dto.MapOne[*models.ApiToken, dto.ApiTokenDto](token)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "data": gin.H{"error": "Failed to map API token"}})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"success": true, "data": dto.CreatedApiTokenDto{ApiTokenDto: out, Token: raw}})
+}
+
+func (h *ApiTokenHandler) DeleteToken(c *gin.Context) {
+	if !h.canManage(c) {
+		return
+	}
+
+	if err := h.apiTokenService.DeleteToken(c.Request.Context(), c.Param("id"), c.Param("tokenId")); err != nil {
+		if errors.Is(err, services.ErrApiTokenNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"success": false, "data": gin.H{"error": err.Error()}})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "data": gin.H{"error": "Failed to delete API token"}})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": gin.H{"message": "API token deleted successfully"}})
+}
+
+// canManage allows users to manage their own tokens and admins to manage anyone's.
+func (h *ApiTokenHandler) canManage(c *gin.Context) bool {
+	user, ok := middleware.GetCurrentUser(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "data": gin.H{"error": "Authentication required"}})
+		return false
+	}
+	if user.ID != c.Param("id") && !c.GetBool("userIsAdmin") {
+		c.JSON(http.StatusForbidden, gin.H{"success": false, "data": gin.H{"error": "You can only manage your own API tokens"}})
+		return false
+	}
+	return true
+}
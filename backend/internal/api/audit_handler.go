@@ -0,0 +1,75 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ofkm/arcane-backend/internal/middleware"
+	"github.com/ofkm/arcane-backend/internal/services"
+	"github.com/ofkm/arcane-backend/internal/utils/pagination"
+)
+
+type AuditHandler struct {
+	auditService *services.AuditService
+}
+
+func NewAuditHandler(group *gin.RouterGroup, auditService *services.AuditService, authMiddleware *middleware.AuthMiddleware) {
+	handler := &AuditHandler{auditService: auditService}
+
+	apiGroup := group.Group("/audit-logs")
+	apiGroup.Use(authMiddleware.WithAdminRequired().Add())
+	{
+		apiGroup.GET("", handler.ListAuditLogs)
+		apiGroup.GET("/export", handler.ExportAuditLogs)
+	}
+}
+
+// ListAuditLogs supports the action, targetType, targetId, userId, username, from and
+// to (RFC3339) filters and defaults to newest first.
+func (h *AuditHandler) ListAuditLogs(c *gin.Context) {
+	params := pagination.ExtractListModifiersQueryParams(c)
+
+	entries, paginationResp, err := h.auditService.ListAuditLogsPaginated(c.Request.Context(), params)
+	if err != nil {
+		h.writeAuditError(c, "Failed to list audit logs: ", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":    true,
+		"data":       entries,
+		"pagination": paginationResp,
+	})
+}
+
+// ExportAuditLogs downloads the filtered entries as ?format=csv (default) or json.
+func (h *AuditHandler) ExportAuditLogs(c *gin.Context) {
+	params := pagination.ExtractListModifiersQueryParams(c)
+	format := c.DefaultQuery("format", "csv")
+	delete(params.Filters, "format")
+
+	body, contentType, err := h.auditService.ExportAuditLogs(c.Request.Context(), params, format)
+	if err != nil {
+		h.writeAuditError(c, "Failed to export audit logs: ", err)
+		return
+	}
+
+	filename := fmt.Sprintf("arcane-audit-log-%s.%s", time.Now().UTC().Format("20060102-150405"), format)
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Data(http.StatusOK, contentType, body)
+}
+
+func (h *AuditHandler) writeAuditError(c *gin.Context, prefix string, err error) {
+	status := http.StatusInternalServerError
+	if errors.Is(err, services.ErrInvalidAuditFilter) || errors.Is(err, services.ErrInvalidAuditExportFormat) {
+		status = http.StatusBadRequest
+	}
+
+	c.JSON(status, gin.H{
+		"success": false,
+		"data":    gin.H{"error": prefix + err.Error()},
+	})
+}
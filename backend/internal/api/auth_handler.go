@@ -1,33 +1,57 @@
 package api
 
 import (
+	"context"
 	"errors"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/ofkm/arcane-backend/internal/dto"
 	"github.com/ofkm/arcane-backend/internal/middleware"
+	"github.com/ofkm/arcane-backend/internal/models"
 	"github.com/ofkm/arcane-backend/internal/services"
 	"github.com/ofkm/arcane-backend/internal/utils/cookie"
 )
 
 type AuthHandler struct {
-	userService *services.UserService
-	authService *services.AuthService
-	oidcService *services.OidcService
+	userService    *services.UserService
+	authService    *services.AuthService
+	oidcService    *services.OidcService
+	roleService    *services.RoleService
+	sessionService *services.SessionService
+	throttle       *services.LoginThrottleService
 }
 
-func NewAuthHandler(group *gin.RouterGroup, userService *services.UserService, authService *services.AuthService, oidcService *services.OidcService, authMiddleware *middleware.AuthMiddleware) {
-	ah := &AuthHandler{userService: userService, authService: authService, oidcService: oidcService}
+func NewAuthHandler(group *gin.RouterGroup, userService *services.UserService, authService *services.AuthService, oidcService *services.OidcService, roleService *services.RoleService, sessionService *services.SessionService, throttle *services.LoginThrottleService, authMiddleware *middleware.AuthMiddleware) {
+	ah := &AuthHandler{userService: userService, authService: authService, oidcService: oidcService, roleService: roleService, sessionService: sessionService, throttle: throttle}
 
 	authApiGroup := group.Group("/auth")
 	{
 		authApiGroup.POST("/login", ah.Login)
-		authApiGroup.POST("/logout", ah.Logout)
+		authApiGroup.POST("/login/totp", ah.LoginTotp)
+		authApiGroup.POST("/login/totp/enroll", ah.LoginTotpEnroll)
+		authApiGroup.POST("/login/passkey/begin", ah.BeginPasskeyLogin)
+		authApiGroup.POST("/login/passkey/finish", ah.FinishPasskeyLogin)
+		authApiGroup.POST("/logout", authMiddleware.WithAdminNotRequired().WithSuccessOptional().Add(), ah.Logout)
 		authApiGroup.GET("/me", authMiddleware.WithAdminNotRequired().Add(), ah.GetCurrentUser)
 		authApiGroup.POST("/refresh", ah.RefreshToken)
 		authApiGroup.POST("/password", authMiddleware.WithAdminNotRequired().Add(), ah.ChangePassword)
+		authApiGroup.POST("/totp/setup", authMiddleware.WithAdminNotRequired().Add(), ah.BeginTotpSetup)
+		authApiGroup.POST("/totp/enable", authMiddleware.WithAdminNotRequired().Add(), ah.EnableTotp)
+		authApiGroup.POST("/totp/disable", authMiddleware.WithAdminNotRequired().Add(), ah.DisableTotp)
+		authApiGroup.POST("/totp/recovery-codes", authMiddleware.WithAdminNotRequired().Add(), ah.RegenerateRecoveryCodes)
+		authApiGroup.GET("/passkeys", authMiddleware.WithAdminNotRequired().Add(), ah.ListPasskeys)
+		authApiGroup.POST("/passkeys/register/begin", authMiddleware.WithAdminNotRequired().Add(), ah.BeginPasskeyRegistration)
+		authApiGroup.POST("/passkeys/register/finish", authMiddleware.WithAdminNotRequired().Add(), ah.FinishPasskeyRegistration)
+		authApiGroup.PUT("/passkeys/:passkeyId", authMiddleware.WithAdminNotRequired().Add(), ah.RenamePasskey)
+		authApiGroup.DELETE("/passkeys/:passkeyId", authMiddleware.WithAdminNotRequired().Add(), ah.DeletePasskey)
+		authApiGroup.GET("/sessions", authMiddleware.WithAdminNotRequired().Add(), ah.ListSessions)
+		authApiGroup.DELETE("/sessions", authMiddleware.WithAdminNotRequired().Add(), ah.RevokeOtherSessions)
+		authApiGroup.DELETE("/sessions/:sessionId", authMiddleware.WithAdminNotRequired().Add(), ah.RevokeSession)
+		authApiGroup.GET("/lockouts", authMiddleware.WithAdminRequired().Add(), ah.ListLockouts)
+		authApiGroup.DELETE("/lockouts/:lockoutId", authMiddleware.WithAdminRequired().Add(), ah.ClearLockout)
 	}
 }
 
@@ -48,11 +72,16 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	user, tokenPair, err := h.authService.Login(c.Request.Context(), req.Username, req.Password)
+	user, tokenPair, challenge, err := h.authService.Login(sessionClientContext(c), req.Username, req.Password)
 	if err != nil {
 		var statusCode int
 		var errorMsg string
+		var throttled *services.LoginThrottledError
 		switch {
+		case errors.As(err, &throttled):
+			c.Header("Retry-After", strconv.Itoa(int(time.Until(throttled.Until).Seconds())+1))
+			statusCode = http.StatusTooManyRequests
+			errorMsg = "Too many failed sign-in attempts, please try again later"
 		case errors.Is(err, services.ErrInvalidCredentials):
 			statusCode = http.StatusUnauthorized
 			errorMsg = "Invalid username or password"
@@ -67,6 +96,32 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
+	if challenge != nil {
+		c.JSON(http.StatusOK, gin.H{
+			"success": true,
+			"data": gin.H{
+				"totpRequired":       true,
+				"enrollmentRequired": challenge.EnrollmentRequired,
+				"challengeToken":     challenge.Token,
+				"expiresAt":          challenge.ExpiresAt,
+			},
+		})
+		return
+	}
+
+	h.writeLoginResponse(c, user, tokenPair, nil)
+}
+
+// sessionClientContext records the caller's address and user agent on the new session.
+func sessionClientContext(c *gin.Context) context.Context {
+	return services.WithSessionClient(c.Request.Context(), services.SessionClient{
+		IPAddress: c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	})
+}
+
+// writeLoginResponse sets the session cookie and returns the tokens and user.
+func (h *AuthHandler) writeLoginResponse(c *gin.Context, user *models.User, tokenPair *services.TokenPair, extra gin.H) {
 	c.SetSameSite(http.SameSiteLaxMode)
 	maxAge := int(time.Until(tokenPair.ExpiresAt).Seconds())
 	if maxAge < 0 {
@@ -82,18 +137,64 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"data": gin.H{
-			"token":        tokenPair.AccessToken,
-			"refreshToken": tokenPair.RefreshToken,
-			"expiresAt":    tokenPair.ExpiresAt,
-			"user":         out,
-		},
-	})
+	data := gin.H{
+		"token":        tokenPair.AccessToken,
+		"refreshToken": tokenPair.RefreshToken,
+		"expiresAt":    tokenPair.ExpiresAt,
+		"user":         out,
+	}
+	for k, v := range extra {
+		data[k] = v
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": data})
+}
+
+func (h *AuthHandler) LoginTotp(c *gin.Context) {
+	var req dto.LoginTotpRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "data": gin.H{"error": "Invalid request format"}})
+		return
+	}
+
+	user, tokenPair, recoveryCodes, err := h.authService.VerifyLoginChallenge(sessionClientContext(c), req.ChallengeToken, req.Code)
+	if err != nil {
+		statusCode, errorMsg := totpErrorResponse(err)
+		c.JSON(statusCode, gin.H{"success": false, "data": gin.H{"error": errorMsg}})
+		return
+	}
+
+	var extra gin.H
+	if recoveryCodes != nil {
+		extra = gin.H{"recoveryCodes": recoveryCodes}
+	}
+	h.writeLoginResponse(c, user, tokenPair, extra)
+}
+
+func (h *AuthHandler) LoginTotpEnroll(c *gin.Context) {
+	var req dto.LoginTotpEnrollRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "data": gin.H{"error": "Invalid request format"}})
+		return
+	}
+
+	setup, err := h.authService.BeginLoginEnrollment(c.Request.Context(), req.ChallengeToken)
+	if err != nil {
+		statusCode, errorMsg := totpErrorResponse(err)
+		c.JSON(statusCode, gin.H{"success": false, "data": gin.H{"error": errorMsg}})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": setup})
 }
 
 func (h *AuthHandler) Logout(c *gin.Context) {
+	if user, ok := middleware.GetCurrentUser(c); ok {
+		if err := h.authService.Logout(c.Request.Context(), user, middleware.GetCurrentSessionID(c)); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"success": false, "data": gin.H{"error": "Failed to end session"}})
+			return
+		}
+	}
+
 	c.SetSameSite(http.SameSiteLaxMode)
 	cookie.ClearTokenCookie(c)
 	c.JSON(http.StatusOK, gin.H{"success": true, "data": gin.H{"message": "Logged out successfully"}})
@@ -118,6 +219,23 @@ func (h *AuthHandler) GetCurrentUser(c *gin.Context) {
 		return
 	}
 
+	bindings, err := h.roleService.ListUserBindings(c.Request.Context(), user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "data": gin.H{"error": "Failed to get user roles"}})
+		return
+	}
+	if err := dto.MapStructList(bindings, &out.RoleBindings); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "data": gin.H{"error": "Failed to map user roles"}})
+		return
+	}
+
+	permissions, err := h.roleService.EffectivePermissions(c.Request.Context(), user)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "data": gin.H{"error": "Failed to get user permissions"}})
+		return
+	}
+	out.Permissions = permissions
+
 	c.JSON(http.StatusOK, gin.H{"success": true, "data": out})
 }
 
@@ -128,11 +246,14 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 		return
 	}
 
-	tokenPair, err := h.authService.RefreshToken(c.Request.Context(), req.RefreshToken)
+	tokenPair, err := h.authService.RefreshToken(sessionClientContext(c), req.RefreshToken)
 	if err != nil {
 		var statusCode int
 		var errorMsg string
 		switch {
+		case errors.Is(err, services.ErrRefreshTokenReused):
+			statusCode = http.StatusUnauthorized
+			errorMsg = "Refresh token was already used; the session has been revoked for safety, please sign in again"
 		case errors.Is(err, services.ErrInvalidToken), errors.Is(err, services.ErrExpiredToken):
 			statusCode = http.StatusUnauthorized
 			errorMsg = "Invalid or expired refresh token"
@@ -180,7 +301,7 @@ func (h *AuthHandler) ChangePassword(c *gin.Context) {
 		return
 	}
 
-	err := h.authService.ChangePassword(c.Request.Context(), user.ID, req.CurrentPassword, req.NewPassword)
+	err := h.authService.ChangePassword(c.Request.Context(), user.ID, middleware.GetCurrentSessionID(c), req.CurrentPassword, req.NewPassword)
 	if err != nil {
 		var statusCode int
 		var errorMsg string
@@ -198,3 +319,353 @@ func (h *AuthHandler) ChangePassword(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"success": true, "data": gin.H{"message": "Password changed successfully"}})
 }
+
+func (h *AuthHandler) BeginTotpSetup(c *gin.Context) {
+	user, ok := middleware.RequireAuthentication(c)
+	if !ok {
+		return
+	}
+
+	setup, err := h.authService.BeginTotpSetup(c.Request.Context(), user.ID)
+	if err != nil {
+		statusCode, errorMsg := totpErrorResponse(err)
+		c.JSON(statusCode, gin.H{"success": false, "data": gin.H{"error": errorMsg}})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": setup})
+}
+
+func (h *AuthHandler) EnableTotp(c *gin.Context) {
+	user, ok := middleware.RequireAuthentication(c)
+	if !ok {
+		return
+	}
+
+	var req dto.TotpCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "data": gin.H{"error": "Invalid request format"}})
+		return
+	}
+
+	recoveryCodes, err := h.authService.EnableTotp(c.Request.Context(), user.ID, req.Code)
+	if err != nil {
+		statusCode, errorMsg := totpErrorResponse(err)
+		c.JSON(statusCode, gin.H{"success": false, "data": gin.H{"error": errorMsg}})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": gin.H{"recoveryCodes": recoveryCodes}})
+}
+
+func (h *AuthHandler) DisableTotp(c *gin.Context) {
+	user, ok := middleware.RequireAuthentication(c)
+	if !ok {
+		return
+	}
+
+	var req dto.TotpDisableRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "data": gin.H{"error": "Invalid request format"}})
+		return
+	}
+
+	if err := h.authService.DisableTotp(c.Request.Context(), user.ID, req.Password, req.Code); err != nil {
+		statusCode, errorMsg := totpErrorResponse(err)
+		c.JSON(statusCode, gin.H{"success": false, "data": gin.H{"error": errorMsg}})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": gin.H{"message": "Two-factor authentication disabled"}})
+}
+
+func (h *AuthHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	user, ok := middleware.RequireAuthentication(c)
+	if !ok {
+		return
+	}
+
+	var req dto.TotpCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "data": gin.H{"error": "Invalid request format"}})
+		return
+	}
+
+	recoveryCodes, err := h.authService.RegenerateRecoveryCodes(c.Request.Context(), user.ID, req.Code)
+	if err != nil {
+		statusCode, errorMsg := totpErrorResponse(err)
+		c.JSON(statusCode, gin.H{"success": false, "data": gin.H{"error": errorMsg}})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": gin.H{"recoveryCodes": recoveryCodes}})
+}
+
+func totpErrorResponse(err error) (int, string) {
+	switch {
+	case errors.Is(err, services.ErrInvalidLoginChallenge):
+		return http.StatusUnauthorized, "Login challenge is invalid or has expired, please sign in again"
+	case errors.Is(err, services.ErrInvalidTotpCode):
+		return http.StatusUnauthorized, "Invalid two-factor code"
+	case errors.Is(err, services.ErrInvalidCredentials):
+		return http.StatusUnauthorized, "Password is incorrect"
+	case errors.Is(err, services.ErrTotpAlreadyEnabled),
+		errors.Is(err, services.ErrTotpNotEnabled),
+		errors.Is(err, services.ErrTotpSetupNotStarted),
+		errors.Is(err, services.ErrTotpRequiredBySettings):
+		return http.StatusBadRequest, err.Error()
+	default:
+		return http.StatusInternalServerError, "Two-factor authentication failed"
+	}
+}
+
+func (h *AuthHandler) BeginPasskeyLogin(c *gin.Context) {
+	ceremony, err := h.authService.BeginPasskeyLogin(c.Request.Context())
+	if err != nil {
+		statusCode, errorMsg := passkeyErrorResponse(err)
+		c.JSON(statusCode, gin.H{"success": false, "data": gin.H{"error": errorMsg}})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": ceremony})
+}
+
+func (h *AuthHandler) FinishPasskeyLogin(c *gin.Context) {
+	var req dto.PasskeyLoginFinishDto
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "data": gin.H{"error": "Invalid request format"}})
+		return
+	}
+
+	user, tokenPair, err := h.authService.FinishPasskeyLogin(sessionClientContext(c), req.SessionToken, &req.Credential)
+	if err != nil {
+		statusCode, errorMsg := passkeyErrorResponse(err)
+		c.JSON(statusCode, gin.H{"success": false, "data": gin.H{"error": errorMsg}})
+		return
+	}
+
+	h.writeLoginResponse(c, user, tokenPair, nil)
+}
+
+func (h *AuthHandler) ListPasskeys(c *gin.Context) {
+	user, ok := middleware.RequireAuthentication(c)
+	if !ok {
+		return
+	}
+
+	passkeys, err := h.authService.ListPasskeys(c.Request.Context(), user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "data": gin.H{"error": "Failed to list passkeys"}})
+		return
+	}
+
+	out, err := dto.MapSlice[models.WebauthnCredential, dto.PasskeyDto](passkeys)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "data": gin.H{"error": "Failed to map passkeys"}})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": out})
+}
+
+func (h *AuthHandler) BeginPasskeyRegistration(c *gin.Context) {
+	user, ok := middleware.RequireAuthentication(c)
+	if !ok {
+		return
+	}
+
+	ceremony, err := h.authService.BeginPasskeyRegistration(c.Request.Context(), user.ID)
+	if err != nil {
+		statusCode, errorMsg := passkeyErrorResponse(err)
+		c.JSON(statusCode, gin.H{"success": false, "data": gin.H{"error": errorMsg}})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": ceremony})
+}
+
+func (h *AuthHandler) FinishPasskeyRegistration(c *gin.Context) {
+	user, ok := middleware.RequireAuthentication(c)
+	if !ok {
+		return
+	}
+
+	var req dto.PasskeyRegisterFinishDto
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "data": gin.H{"error": "Invalid request format"}})
+		return
+	}
+
+	passkey, err := h.authService.FinishPasskeyRegistration(c.Request.Context(), user.ID, req.SessionToken, req.Name, &req.Credential)
+	if err != nil {
+		statusCode, errorMsg := passkeyErrorResponse(err)
+		c.JSON(statusCode, gin.H{"success": false, "data": gin.H{"error": errorMsg}})
+		return
+	}
+
+	out, err := dto.MapOne[*models.WebauthnCredential, dto.PasskeyDto](passkey)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "data": gin.H{"error": "Failed to map passkey"}})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"success": true, "data": out})
+}
+
+func (h *AuthHandler) RenamePasskey(c *gin.Context) {
+	user, ok := middleware.RequireAuthentication(c)
+	if !ok {
+		return
+	}
+
+	var req dto.RenamePasskeyDto
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "data": gin.H{"error": "Invalid request format"}})
+		return
+	}
+
+	passkey, err := h.authService.RenamePasskey(c.Request.Context(), user.ID, c.Param("passkeyId"), req.Name)
+	if err != nil {
+		statusCode, errorMsg := passkeyErrorResponse(err)
+		c.JSON(statusCode, gin.H{"success": false, "data": gin.H{"error": errorMsg}})
+		return
+	}
+
+	out, err := dto.MapOne[*models.WebauthnCredential, dto.PasskeyDto](passkey)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "data": gin.H{"error": "Failed to map passkey"}})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": out})
+}
+
+func (h *AuthHandler) DeletePasskey(c *gin.Context) {
+	user, ok := middleware.RequireAuthentication(c)
+	if !ok {
+		return
+	}
+
+	if err := h.authService.DeletePasskey(c.Request.Context(), user.ID, c.Param("passkeyId")); err != nil {
+		statusCode, errorMsg := passkeyErrorResponse(err)
+		c.JSON(statusCode, gin.H{"success": false, "data": gin.H{"error": errorMsg}})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": gin.H{"message": "Passkey deleted successfully"}})
+}
+
+func passkeyErrorResponse(err error) (int, string) {
+	switch {
+	case errors.Is(err, services.ErrPasskeysDisabled):
+		return http.StatusBadRequest, "Passkey authentication is disabled"
+	case errors.Is(err, services.ErrPasskeyNotFound):
+		return http.StatusNotFound, "Passkey not found"
+	case errors.Is(err, services.ErrInvalidPasskeySession):
+		return http.StatusUnauthorized, "Passkey request is invalid or has expired, please try again"
+	case errors.Is(err, services.ErrPasskeyVerifyFailed), errors.Is(err, services.ErrPasskeyUserMismatch):
+		return http.StatusUnauthorized, "Passkey could not be verified"
+	case errors.Is(err, services.ErrPasskeyAlreadyExists):
+		return http.StatusConflict, "This passkey is already registered"
+	case errors.Is(err, services.ErrPasskeyNameTooLong):
+		return http.StatusBadRequest, err.Error()
+	default:
+		return http.StatusInternalServerError, "Passkey operation failed"
+	}
+}
+
+func (h *AuthHandler) ListSessions(c *gin.Context) {
+	user, ok := middleware.RequireAuthentication(c)
+	if !ok {
+		return
+	}
+
+	sessions, err := h.sessionService.ListSessions(c.Request.Context(), user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "data": gin.H{"error": "Failed to list sessions"}})
+		return
+	}
+
+	out, err := mapSessions(sessions, middleware.GetCurrentSessionID(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "data": gin.H{"error": "Failed to map sessions"}})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": out})
+}
+
+func (h *AuthHandler) RevokeSession(c *gin.Context) {
+	user, ok := middleware.RequireAuthentication(c)
+	if !ok {
+		return
+	}
+
+	if err := h.sessionService.RevokeSession(c.Request.Context(), user.ID, c.Param("sessionId"), services.SessionRevokedByUser); err != nil {
+		if errors.Is(err, services.ErrSessionNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"success": false, "data": gin.H{"error": "Session not found"}})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "data": gin.H{"error": "Failed to revoke session"}})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": gin.H{"message": "Session revoked successfully"}})
+}
+
+// RevokeOtherSessions signs the user out everywhere except the current session.
+func (h *AuthHandler) RevokeOtherSessions(c *gin.Context) {
+	user, ok := middleware.RequireAuthentication(c)
+	if !ok {
+		return
+	}
+
+	revoked, err := h.sessionService.RevokeUserSessions(c.Request.Context(), user.ID, middleware.GetCurrentSessionID(c), services.SessionRevokedByUser)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "data": gin.H{"error": "Failed to revoke sessions"}})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": gin.H{"revoked": revoked}})
+}
+
+func mapSessions(sessions []models.AuthSession, currentSessionID string) ([]dto.SessionDto, error) {
+	out, err := dto.MapSlice[models.AuthSession, dto.SessionDto](sessions)
+	if err != nil {
+		return nil, err
+	}
+	for i := range out {
+		out[i].Current = currentSessionID != "" && out[i].ID == currentSessionID
+	}
+	return out, nil
+}
+
+func (h *AuthHandler) ListLockouts(c *gin.Context) {
+	lockouts, err := h.throttle.ListLockouts(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "data": gin.H{"error": "Failed to list lockouts"}})
+		return
+	}
+
+	out, err := dto.MapSlice[models.LoginThrottle, dto.LockoutDto](lockouts)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "data": gin.H{"error": "Failed to map lockouts"}})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": out})
+}
+
+// ClearLockout lets a locked out user or address sign in again straight away.
+func (h *AuthHandler) ClearLockout(c *gin.Context) {
+	if err := h.throttle.ClearLockout(c.Request.Context(), c.Param("lockoutId")); err != nil {
+		if errors.Is(err, services.ErrLockoutNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"success": false, "data": gin.H{"error": "Lockout not found"}})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "data": gin.H{"error": "Failed to clear lockout"}})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": gin.H{"message": "Lockout cleared successfully"}})
+}
@@ -0,0 +1,105 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ofkm/arcane-backend/internal/middleware"
+	"github.com/ofkm/arcane-backend/internal/services"
+)
+
+type MetricsHandler struct {
+	metricsService *services.MetricsService
+}
+
+func NewMetricsHandler(group *gin.RouterGroup, metricsService *services.MetricsService, authMiddleware *middleware.AuthMiddleware) {
+	handler := &MetricsHandler{metricsService: metricsService}
+
+	apiGroup := group.Group("/environments/:id/metrics")
+	apiGroup.Use(authMiddleware.WithAdminNotRequired().Add())
+	{
+		apiGroup.GET("", handler.GetMetrics)
+	}
+}
+
+// GetMetrics returns a historical series for the host or, with ?container=, one container.
+// from and to accept RFC3339 timestamps, unix seconds or a duration relative to now
+// (e.g. from=6h); step accepts a duration or a number of seconds.
+func (h *MetricsHandler) GetMetrics(c *gin.Context) {
+	now := time.Now().UTC()
+
+	from, err := parseMetricsTime(c.Query("from"), now)
+	if err != nil {
+		h.writeMetricsError(c, fmt.Errorf("%w: from: %w", services.ErrInvalidMetricsQuery, err))
+		return
+	}
+	to, err := parseMetricsTime(c.Query("to"), now)
+	if err != nil {
+		h.writeMetricsError(c, fmt.Errorf("%w: to: %w", services.ErrInvalidMetricsQuery, err))
+		return
+	}
+	step, err := parseMetricsStep(c.Query("step"))
+	if err != nil {
+		h.writeMetricsError(c, fmt.Errorf("%w: step: %w", services.ErrInvalidMetricsQuery, err))
+		return
+	}
+
+	series, err := h.metricsService.Query(c.Request.Context(), services.MetricsQuery{
+		Container: c.Query("container"),
+		From:      from,
+		To:        to,
+		Step:      step,
+	})
+	if err != nil {
+		h.writeMetricsError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": series})
+}
+
+func (h *MetricsHandler) writeMetricsError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	if errors.Is(err, services.ErrInvalidMetricsQuery) {
+		status = http.StatusBadRequest
+	}
+	c.JSON(status, gin.H{"success": false, "data": gin.H{"error": err.Error()}})
+}
+
+func parseMetricsTime(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(seconds, 0).UTC(), nil
+	}
+	if d, err := time.ParseDuration(strings.TrimPrefix(value, "-")); err == nil {
+		return now.Add(-d), nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, errors.New("expected RFC3339 time, unix seconds or a relative duration")
+	}
+	return t.UTC(), nil
+}
+
+func parseMetricsStep(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, nil
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, errors.New("expected a duration or number of seconds")
+	}
+	return d, nil
+}
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -36,6 +37,21 @@ func NewNotificationHandler(group *gin.RouterGroup, notificationService *service
 	}
 }
 
+func isValidNotificationProvider(provider models.NotificationProvider) bool {
+	switch provider {
+	case models.NotificationProviderDiscord,
+		models.NotificationProviderEmail,
+		models.NotificationProviderWebhook,
+		models.NotificationProviderSlack,
+		models.NotificationProviderTeams,
+		models.NotificationProviderNtfy,
+		models.NotificationProviderGotify:
+		return true
+	default:
+		return false
+	}
+}
+
 func (h *NotificationHandler) GetAllSettings(c *gin.Context) {
 	settings, err := h.notificationService.GetAllSettings(c.Request.Context())
 	if err != nil {
@@ -61,9 +77,7 @@ func (h *NotificationHandler) GetSettings(c *gin.Context) {
 	providerStr := c.Param("provider")
 	provider := models.NotificationProvider(providerStr)
 
-	switch provider {
-	case models.NotificationProviderDiscord, models.NotificationProviderEmail:
-	default:
+	if !isValidNotificationProvider(provider) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid provider"})
 		return
 	}
@@ -91,12 +105,21 @@ func (h *NotificationHandler) CreateOrUpdateSettings(c *gin.Context) {
 		return
 	}
 
+	if !isValidNotificationProvider(req.Provider) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid provider"})
+		return
+	}
+
 	settings, err := h.notificationService.CreateOrUpdateSettings(
 		c.Request.Context(),
 		req.Provider,
 		req.Enabled,
 		req.Config,
 	)
+	if errors.Is(err, services.ErrInvalidNotificationDelivery) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -116,9 +139,7 @@ func (h *NotificationHandler) DeleteSettings(c *gin.Context) {
 	providerStr := c.Param("provider")
 	provider := models.NotificationProvider(providerStr)
 
-	switch provider {
-	case models.NotificationProviderDiscord, models.NotificationProviderEmail:
-	default:
+	if !isValidNotificationProvider(provider) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid provider"})
 		return
 	}
@@ -135,9 +156,7 @@ func (h *NotificationHandler) TestNotification(c *gin.Context) {
 	providerStr := c.Param("provider")
 	provider := models.NotificationProvider(providerStr)
 
-	switch provider {
-	case models.NotificationProviderDiscord, models.NotificationProviderEmail:
-	default:
+	if !isValidNotificationProvider(provider) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid provider"})
 		return
 	}
@@ -165,6 +184,7 @@ func (h *NotificationHandler) GetAppriseSettings(c *gin.Context) {
 		Enabled:            settings.Enabled,
 		ImageUpdateTag:     settings.ImageUpdateTag,
 		ContainerUpdateTag: settings.ContainerUpdateTag,
+		Delivery:           settings.Delivery,
 	}
 
 	c.JSON(http.StatusOK, response)
@@ -183,7 +203,12 @@ func (h *NotificationHandler) CreateOrUpdateAppriseSettings(c *gin.Context) {
 		req.Enabled,
 		req.ImageUpdateTag,
 		req.ContainerUpdateTag,
+		req.Delivery,
 	)
+	if errors.Is(err, services.ErrInvalidNotificationDelivery) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -195,6 +220,7 @@ func (h *NotificationHandler) CreateOrUpdateAppriseSettings(c *gin.Context) {
 		Enabled:            settings.Enabled,
 		ImageUpdateTag:     settings.ImageUpdateTag,
 		ContainerUpdateTag: settings.ContainerUpdateTag,
+		Delivery:           settings.Delivery,
 	}
 
 	c.JSON(http.StatusOK, response)
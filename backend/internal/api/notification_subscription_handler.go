@@ -0,0 +1,148 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ofkm/arcane-backend/internal/dto"
+	"github.com/ofkm/arcane-backend/internal/middleware"
+	"github.com/ofkm/arcane-backend/internal/services"
+)
+
+type NotificationSubscriptionHandler struct {
+	subscriptionService *services.NotificationSubscriptionService
+}
+
+func NewNotificationSubscriptionHandler(group *gin.RouterGroup, subscriptionService *services.NotificationSubscriptionService, authMiddleware *middleware.AuthMiddleware) {
+	handler := &NotificationSubscriptionHandler{subscriptionService: subscriptionService}
+
+	apiGroup := group.Group("/environments/:id/notifications")
+	apiGroup.Use(authMiddleware.WithAdminRequired().Add())
+	{
+		apiGroup.GET("/subscriptions", handler.List)
+		apiGroup.POST("/subscriptions", handler.Create)
+		apiGroup.PUT("/subscriptions/:subscriptionId", handler.Update)
+		apiGroup.DELETE("/subscriptions/:subscriptionId", handler.Delete)
+		apiGroup.POST("/subscriptions/:subscriptionId/test", handler.Test)
+		apiGroup.GET("/deliveries", handler.ListDeliveries)
+	}
+}
+
+func (h *NotificationSubscriptionHandler) List(c *gin.Context) {
+	subs, err := h.subscriptionService.ListSubscriptions(c.Request.Context())
+	if err != nil {
+		h.writeSubscriptionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": subs})
+}
+
+func (h *NotificationSubscriptionHandler) Create(c *gin.Context) {
+	var req dto.NotificationSubscriptionDto
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"data":    gin.H{"error": "Invalid request: " + err.Error()},
+		})
+		return
+	}
+
+	sub, err := h.subscriptionService.CreateSubscription(c.Request.Context(), req)
+	if err != nil {
+		h.writeSubscriptionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"success": true, "data": sub})
+}
+
+func (h *NotificationSubscriptionHandler) Update(c *gin.Context) {
+	var req dto.NotificationSubscriptionDto
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"data":    gin.H{"error": "Invalid request: " + err.Error()},
+		})
+		return
+	}
+
+	sub, err := h.subscriptionService.UpdateSubscription(c.Request.Context(), c.Param("subscriptionId"), req)
+	if err != nil {
+		h.writeSubscriptionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": sub})
+}
+
+func (h *NotificationSubscriptionHandler) Delete(c *gin.Context) {
+	if err := h.subscriptionService.DeleteSubscription(c.Request.Context(), c.Param("subscriptionId")); err != nil {
+		h.writeSubscriptionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    gin.H{"message": "Notification subscription deleted successfully"},
+	})
+}
+
+func (h *NotificationSubscriptionHandler) Test(c *gin.Context) {
+	if err := h.subscriptionService.TestSubscription(c.Request.Context(), c.Param("subscriptionId")); err != nil {
+		if errors.Is(err, services.ErrNotificationSubscriptionNotFound) {
+			h.writeSubscriptionError(c, err)
+			return
+		}
+		c.JSON(http.StatusBadGateway, gin.H{
+			"success": false,
+			"data":    gin.H{"error": err.Error()},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    gin.H{"message": "Test notification sent successfully"},
+	})
+}
+
+// ListDeliveries returns recent deliveries, filtered by ?subscriptionId= and capped by ?limit=.
+func (h *NotificationSubscriptionHandler) ListDeliveries(c *gin.Context) {
+	limit := 0
+	if raw := c.Query("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"data":    gin.H{"error": "limit must be a positive integer"},
+			})
+			return
+		}
+		limit = n
+	}
+
+	deliveries, err := h.subscriptionService.ListDeliveries(c.Request.Context(), c.Query("subscriptionId"), limit)
+	if err != nil {
+		h.writeSubscriptionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": deliveries})
+}
+
+func (h *NotificationSubscriptionHandler) writeSubscriptionError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, services.ErrNotificationSubscriptionNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, services.ErrInvalidNotificationSubscription):
+		status = http.StatusBadRequest
+	}
+	c.JSON(status, gin.H{
+		"success": false,
+		"data":    gin.H{"error": err.Error()},
+	})
+}
@@ -1,23 +1,27 @@
 package api
 
 import (
+	"errors"
 	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/ofkm/arcane-backend/internal/dto"
+	"github.com/ofkm/arcane-backend/internal/middleware"
+	"github.com/ofkm/arcane-backend/internal/models"
 	"github.com/ofkm/arcane-backend/internal/services"
 	"github.com/ofkm/arcane-backend/internal/utils/cookie"
 )
 
 type OidcHandler struct {
-	authService *services.AuthService
-	oidcService *services.OidcService
+	authService     *services.AuthService
+	oidcService     *services.OidcService
+	providerService *services.OidcProviderService
 }
 
-func NewOidcHandler(group *gin.RouterGroup, authService *services.AuthService, oidcService *services.OidcService) {
+func NewOidcHandler(group *gin.RouterGroup, authService *services.AuthService, oidcService *services.OidcService, providerService *services.OidcProviderService, authMiddleware *middleware.AuthMiddleware) {
 
-	handler := &OidcHandler{authService: authService, oidcService: oidcService}
+	handler := &OidcHandler{authService: authService, oidcService: oidcService, providerService: providerService}
 
 	apiGroup := group.Group("/oidc")
 	{
@@ -25,6 +29,25 @@ func NewOidcHandler(group *gin.RouterGroup, authService *services.AuthService, o
 		apiGroup.POST("/callback", handler.HandleOidcCallback)
 		apiGroup.GET("/config", handler.GetOidcConfig)
 		apiGroup.GET("/status", handler.GetOidcStatus)
+		apiGroup.GET("/login-providers", handler.ListLoginProviders)
+	}
+
+	providers := group.Group("/oidc/providers")
+	providers.Use(authMiddleware.WithAdminRequired().Add())
+	{
+		providers.GET("", handler.ListProviders)
+		providers.POST("", handler.CreateProvider)
+		providers.GET("/:slug", handler.GetProvider)
+		providers.PUT("/:slug", handler.UpdateProvider)
+		providers.DELETE("/:slug", handler.DeleteProvider)
+	}
+
+	mappings := group.Group("/oidc/role-mappings")
+	mappings.Use(authMiddleware.WithAdminRequired().Add())
+	{
+		mappings.GET("", handler.ListRoleMappings)
+		mappings.POST("", handler.CreateRoleMapping)
+		mappings.DELETE("/:mappingId", handler.DeleteRoleMapping)
 	}
 }
 
@@ -58,7 +81,11 @@ func (h *OidcHandler) GetOidcAuthUrl(c *gin.Context) {
 		return
 	}
 
-	authUrl, stateCookieValue, err := h.oidcService.GenerateAuthURL(c.Request.Context(), req.RedirectUri)
+	authUrl, stateCookieValue, err := h.oidcService.GenerateAuthURL(c.Request.Context(), req.Provider, req.RedirectUri)
+	if errors.Is(err, services.ErrOidcProviderNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "error": "OIDC provider not found"})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "Failed to generate OIDC auth URL: " + err.Error()})
 		return
@@ -95,7 +122,7 @@ func (h *OidcHandler) HandleOidcCallback(c *gin.Context) {
 		return
 	}
 
-	user, tokenPair, err := h.authService.OidcLogin(c.Request.Context(), *userInfo, tokenResp)
+	user, tokenPair, err := h.authService.OidcLogin(sessionClientContext(c), *userInfo, tokenResp)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Authentication failed"})
 		return
@@ -146,3 +173,170 @@ func (h *OidcHandler) GetOidcConfig(c *gin.Context) {
 		"scopes":                config.Scopes,
 	})
 }
+
+// ListLoginProviders returns the providers shown as buttons on the login page.
+func (h *OidcHandler) ListLoginProviders(c *gin.Context) {
+	enabled, err := h.authService.IsOidcEnabled(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "data": gin.H{"error": "Failed to check OIDC status"}})
+		return
+	}
+	if !enabled {
+		c.JSON(http.StatusOK, gin.H{"success": true, "data": []dto.OidcLoginProviderDto{}})
+		return
+	}
+
+	providers, err := h.oidcService.ListLoginProviders(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "data": gin.H{"error": "Failed to list OIDC providers"}})
+		return
+	}
+	if providers == nil {
+		providers = []dto.OidcLoginProviderDto{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": providers})
+}
+
+func (h *OidcHandler) ListProviders(c *gin.Context) {
+	providers, err := h.providerService.ListProviders(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "data": gin.H{"error": "Failed to list OIDC providers"}})
+		return
+	}
+
+	out := make([]dto.OidcProviderDto, 0, len(providers))
+	for i := range providers {
+		out = append(out, toOidcProviderDto(&providers[i]))
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": out})
+}
+
+func (h *OidcHandler) GetProvider(c *gin.Context) {
+	provider, err := h.providerService.GetProvider(c.Request.Context(), c.Param("slug"))
+	if err != nil {
+		writeOidcProviderError(c, err, "Failed to get OIDC provider")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": toOidcProviderDto(provider)})
+}
+
+func (h *OidcHandler) CreateProvider(c *gin.Context) {
+	var req dto.CreateOidcProviderDto
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "data": gin.H{"error": "Invalid request format"}})
+		return
+	}
+
+	provider, err := h.providerService.CreateProvider(c.Request.Context(), req)
+	if err != nil {
+		writeOidcProviderError(c, err, "Failed to create OIDC provider")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"success": true, "data": toOidcProviderDto(provider)})
+}
+
+func (h *OidcHandler) UpdateProvider(c *gin.Context) {
+	var req dto.UpdateOidcProviderDto
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "data": gin.H{"error": "Invalid request format"}})
+		return
+	}
+
+	provider, err := h.providerService.UpdateProvider(c.Request.Context(), c.Param("slug"), req)
+	if err != nil {
+		writeOidcProviderError(c, err, "Failed to update OIDC provider")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": toOidcProviderDto(provider)})
+}
+
+func (h *OidcHandler) DeleteProvider(c *gin.Context) {
+	if err := h.providerService.DeleteProvider(c.Request.Context(), c.Param("slug")); err != nil {
+		writeOidcProviderError(c, err, "Failed to delete OIDC provider")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": gin.H{"message": "OIDC provider deleted successfully"}})
+}
+
+func (h *OidcHandler) ListRoleMappings(c *gin.Context) {
+	mappings, err := h.providerService.ListRoleMappings(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "data": gin.H{"error": "Failed to list OIDC role mappings"}})
+		return
+	}
+
+	out, err := dto.MapSlice[models.OidcRoleMapping, dto.OidcRoleMappingDto](mappings)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "data": gin.H{"error": "Failed to map OIDC role mappings"}})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": out})
+}
+
+func (h *OidcHandler) CreateRoleMapping(c *gin.Context) {
+	var req dto.CreateOidcRoleMappingDto
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "data": gin.H{"error": "Invalid request format"}})
+		return
+	}
+
+	mapping, err := h.providerService.CreateRoleMapping(c.Request.Context(), req)
+	if err != nil {
+		writeOidcProviderError(c, err, "Failed to create OIDC role mapping")
+		return
+	}
+
+	out, err := dto.MapOne[*models.OidcRoleMapping, dto.OidcRoleMappingDto](mapping)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "data": gin.H{"error": "Failed to map OIDC role mapping"}})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"success": true, "data": out})
+}
+
+func (h *OidcHandler) DeleteRoleMapping(c *gin.Context) {
+	if err := h.providerService.DeleteRoleMapping(c.Request.Context(), c.Param("mappingId")); err != nil {
+		writeOidcProviderError(c, err, "Failed to delete OIDC role mapping")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": gin.H{"message": "OIDC role mapping deleted successfully"}})
+}
+
+func toOidcProviderDto(p *models.OidcProvider) dto.OidcProviderDto {
+	return dto.OidcProviderDto{
+		ID:                p.ID,
+		Slug:              p.Slug,
+		Name:              p.Name,
+		IssuerURL:         p.IssuerURL,
+		ClientID:          p.ClientID,
+		Scopes:            p.Scopes,
+		Enabled:           p.Enabled,
+		ManageDefaultRole: p.ManageDefaultRole,
+		HasClientSecret:   p.ClientSecret != "",
+		CreatedAt:         p.CreatedAt,
+	}
+}
+
+func writeOidcProviderError(c *gin.Context, err error, fallback string) {
+	status := http.StatusInternalServerError
+	msg := fallback
+	switch {
+	case errors.Is(err, services.ErrOidcProviderNotFound):
+		status, msg = http.StatusNotFound, err.Error()
+	case errors.Is(err, services.ErrOidcRoleMappingNotFound):
+		status, msg = http.StatusNotFound, err.Error()
+	case errors.Is(err, services.ErrOidcProviderExists):
+		status, msg = http.StatusConflict, err.Error()
+	case errors.Is(err, services.ErrInvalidOidcProvider), errors.Is(err, services.ErrInvalidOidcRoleMapping):
+		status, msg = http.StatusBadRequest, err.Error()
+	}
+	c.JSON(status, gin.H{"success": false, "data": gin.H{"error": msg}})
+}
@@ -0,0 +1,179 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ofkm/arcane-backend/internal/dto"
+	"github.com/ofkm/arcane-backend/internal/middleware"
+	"github.com/ofkm/arcane-backend/internal/models"
+	"github.com/ofkm/arcane-backend/internal/services"
+	"github.com/ofkm/arcane-backend/internal/utils"
+)
+
+type ProjectGitHandler struct {
+	projectService    *services.ProjectService
+	projectGitService *services.ProjectGitService
+}
+
+func NewProjectGitHandler(group *gin.RouterGroup, projectService *services.ProjectService, projectGitService *services.ProjectGitService, authMiddleware *middleware.AuthMiddleware) {
+	handler := &ProjectGitHandler{
+		projectService:    projectService,
+		projectGitService: projectGitService,
+	}
+
+	apiGroup := group.Group("/environments/:id/projects")
+	apiGroup.Use(authMiddleware.WithAdminNotRequired().Add())
+	{
+		apiGroup.POST("/git", handler.CreateGitProject)
+		apiGroup.GET("/:projectId/git", handler.GetGitSource)
+		apiGroup.PUT("/:projectId/git", handler.UpdateGitSource)
+		apiGroup.DELETE("/:projectId/git", handler.DeleteGitSource)
+		apiGroup.POST("/:projectId/git/sync", handler.SyncGitProject)
+	}
+}
+
+func (h *ProjectGitHandler) CreateGitProject(c *gin.Context) {
+	var req dto.CreateGitProjectDto
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid request: " + err.Error(),
+		})
+		return
+	}
+
+	user, _ := middleware.GetCurrentUser(c)
+	proj, src, err := h.projectGitService.CreateGitProject(c.Request.Context(), req, *user)
+	if err != nil {
+		h.writeGitError(c, err)
+		return
+	}
+
+	if req.Deploy {
+		if err := h.projectService.DeployProject(c.Request.Context(), proj.ID, *user); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"success": false,
+				"error":   "Project was cloned but deployment failed: " + err.Error(),
+				"data":    gin.H{"projectId": proj.ID},
+			})
+			return
+		}
+	}
+
+	var projectDto dto.CreateProjectReponseDto
+	if err := dto.MapStruct(proj, &projectDto); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "failed to map response"})
+		return
+	}
+	projectDto.Status = string(proj.Status)
+	projectDto.CreatedAt = proj.CreatedAt.Format(time.RFC3339)
+	projectDto.UpdatedAt = proj.UpdatedAt.Format(time.RFC3339)
+	projectDto.DirName = utils.DerefString(proj.DirName)
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data": gin.H{
+			"project": projectDto,
+			"git":     toGitSourceDto(src),
+		},
+	})
+}
+
+func (h *ProjectGitHandler) GetGitSource(c *gin.Context) {
+	src, err := h.projectGitService.GetSource(c.Request.Context(), c.Param("projectId"))
+	if err != nil {
+		h.writeGitError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": toGitSourceDto(src)})
+}
+
+func (h *ProjectGitHandler) UpdateGitSource(c *gin.Context) {
+	var req dto.UpdateGitSourceDto
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid request: " + err.Error(),
+		})
+		return
+	}
+
+	src, err := h.projectGitService.UpdateSource(c.Request.Context(), c.Param("projectId"), req)
+	if err != nil {
+		h.writeGitError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": toGitSourceDto(src)})
+}
+
+func (h *ProjectGitHandler) DeleteGitSource(c *gin.Context) {
+	if err := h.projectGitService.DeleteSource(c.Request.Context(), c.Param("projectId")); err != nil {
+		h.writeGitError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    gin.H{"message": "Project detached from git repository"},
+	})
+}
+
+func (h *ProjectGitHandler) SyncGitProject(c *gin.Context) {
+	user, _ := middleware.GetCurrentUser(c)
+	result, err := h.projectGitService.SyncProject(c.Request.Context(), c.Param("projectId"), *user)
+	if err != nil {
+		if result != nil {
+			// Sync succeeded but the deploy failed; still report what changed.
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"success": false,
+				"error":   err.Error(),
+				"data":    result,
+			})
+			return
+		}
+		h.writeGitError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": result})
+}
+
+func (h *ProjectGitHandler) writeGitError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, services.ErrGitSourceNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, services.ErrGitSyncInProgress):
+		status = http.StatusConflict
+	case errors.Is(err, services.ErrInvalidGitSource):
+		status = http.StatusBadRequest
+	}
+	c.JSON(status, gin.H{
+		"success": false,
+		"error":   err.Error(),
+	})
+}
+
+func toGitSourceDto(src *models.ProjectGitSource) dto.GitSourceDto {
+	return dto.GitSourceDto{
+		ID:            src.ID,
+		ProjectID:     src.ProjectID,
+		RepositoryURL: src.RepositoryURL,
+		Ref:           src.Ref,
+		SubPath:       src.SubPath,
+		AuthType:      string(src.AuthType),
+		Username:      src.Username,
+		HasCredential: src.Credential != "",
+		AutoSync:      src.AutoSync,
+		SyncInterval:  src.SyncInterval,
+		AutoDeploy:    src.AutoDeploy,
+		LastCommit:    src.LastCommit,
+		LastSyncAt:    src.LastSyncAt,
+		LastSyncError: src.LastSyncError,
+	}
+}
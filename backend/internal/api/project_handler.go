@@ -61,6 +61,14 @@ func NewProjectHandler(group *gin.RouterGroup, projectService *services.ProjectS
 		apiGroup.DELETE("/:projectId/destroy", handler.DestroyProject)
 		apiGroup.PUT("/:projectId", handler.UpdateProject)
 		apiGroup.PUT("/:projectId/includes", handler.UpdateProjectInclude)
+		apiGroup.GET("/:projectId/revisions", handler.ListProjectRevisions)
+		apiGroup.GET("/:projectId/revisions/:revisionId", handler.GetProjectRevision)
+		apiGroup.GET("/:projectId/revisions/:revisionId/diff", handler.DiffProjectRevision)
+		apiGroup.POST("/:projectId/revisions/:revisionId/restore", handler.RestoreProjectRevision)
+		apiGroup.POST("/:projectId/revisions/:revisionId/redeploy", handler.RedeployProjectRevision)
+		apiGroup.GET("/:projectId/deployments", handler.ListProjectDeployments)
+		apiGroup.GET("/:projectId/deployments/:deploymentId", handler.GetProjectDeployment)
+		apiGroup.POST("/:projectId/deployments/:deploymentId/rollback", handler.RollbackProjectDeployment)
 		apiGroup.POST("/:projectId/restart", handler.RestartProject)
 		apiGroup.GET("/:projectId/logs/ws", handler.GetProjectLogsWS)
 
@@ -282,7 +290,8 @@ func (h *ProjectHandler) UpdateProject(c *gin.Context) {
 		return
 	}
 
-	if _, err := h.projectService.UpdateProject(c.Request.Context(), projectID, req.Name, req.ComposeContent, req.EnvContent); err != nil {
+	user, _ := middleware.GetCurrentUser(c)
+	if _, err := h.projectService.UpdateProject(c.Request.Context(), projectID, req.Name, req.ComposeContent, req.EnvContent, utils.DerefString(req.Message), *user); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": err.Error()})
 		return
 	}
@@ -312,7 +321,8 @@ func (h *ProjectHandler) UpdateProjectInclude(c *gin.Context) {
 		return
 	}
 
-	if err := h.projectService.UpdateProjectIncludeFile(c.Request.Context(), projectID, req.RelativePath, req.Content); err != nil {
+	user, _ := middleware.GetCurrentUser(c)
+	if err := h.projectService.UpdateProjectIncludeFile(c.Request.Context(), projectID, req.RelativePath, req.Content, utils.DerefString(req.Message), *user); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": err.Error()})
 		return
 	}
@@ -329,6 +339,111 @@ func (h *ProjectHandler) UpdateProjectInclude(c *gin.Context) {
 	})
 }
 
+func (h *ProjectHandler) ListProjectRevisions(c *gin.Context) {
+	revisions, err := h.projectService.ListProjectRevisions(c.Request.Context(), c.Param("projectId"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": revisions})
+}
+
+func (h *ProjectHandler) GetProjectRevision(c *gin.Context) {
+	revision, err := h.projectService.GetProjectRevision(c.Request.Context(), c.Param("projectId"), c.Param("revisionId"))
+	if err != nil {
+		c.JSON(projectHistoryErrorStatus(err), gin.H{"success": false, "error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": revision})
+}
+
+// DiffProjectRevision compares a revision with ?from=<revisionId>, or with the revision
+// before it when from is not given.
+func (h *ProjectHandler) DiffProjectRevision(c *gin.Context) {
+	diff, err := h.projectService.DiffProjectRevisions(c.Request.Context(), c.Param("projectId"), c.Query("from"), c.Param("revisionId"))
+	if err != nil {
+		c.JSON(projectHistoryErrorStatus(err), gin.H{"success": false, "error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": diff})
+}
+
+func (h *ProjectHandler) RestoreProjectRevision(c *gin.Context) {
+	var req dto.RestoreProjectRevisionDto
+	if err := c.ShouldBindJSON(&req); err != nil {
+		req = dto.RestoreProjectRevisionDto{}
+	}
+
+	// Redeploying needs projects:deploy, which is checked on the redeploy route.
+	if req.Redeploy {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "use the revision redeploy endpoint to restore and redeploy"})
+		return
+	}
+
+	h.restoreProjectRevision(c, false)
+}
+
+// RedeployProjectRevision restores a revision and redeploys the project with it.
+func (h *ProjectHandler) RedeployProjectRevision(c *gin.Context) {
+	h.restoreProjectRevision(c, true)
+}
+
+func (h *ProjectHandler) restoreProjectRevision(c *gin.Context, redeploy bool) {
+	user, _ := middleware.GetCurrentUser(c)
+	revision, err := h.projectService.RestoreProjectRevision(c.Request.Context(), c.Param("projectId"), c.Param("revisionId"), redeploy, *user)
+	if err != nil {
+		c.JSON(projectHistoryErrorStatus(err), gin.H{"success": false, "error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": revision})
+}
+
+func projectHistoryErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrProjectRevisionNotFound), errors.Is(err, services.ErrProjectDeploymentNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrInvalidRollbackTarget):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func (h *ProjectHandler) ListProjectDeployments(c *gin.Context) {
+	deployments, err := h.projectService.ListProjectDeployments(c.Request.Context(), c.Param("projectId"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": deployments})
+}
+
+func (h *ProjectHandler) GetProjectDeployment(c *gin.Context) {
+	deployment, err := h.projectService.GetProjectDeployment(c.Request.Context(), c.Param("projectId"), c.Param("deploymentId"))
+	if err != nil {
+		c.JSON(projectHistoryErrorStatus(err), gin.H{"success": false, "error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": deployment})
+}
+
+func (h *ProjectHandler) RollbackProjectDeployment(c *gin.Context) {
+	user, _ := middleware.GetCurrentUser(c)
+	deployment, err := h.projectService.RollbackProjectDeployment(c.Request.Context(), c.Param("projectId"), c.Param("deploymentId"), *user)
+	if err != nil {
+		c.JSON(projectHistoryErrorStatus(err), gin.H{"success": false, "error": err.Error(), "data": deployment})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": deployment})
+}
+
 func (h *ProjectHandler) RestartProject(c *gin.Context) {
 	projectID := c.Param("projectId")
 	if projectID == "" {
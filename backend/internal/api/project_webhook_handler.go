@@ -0,0 +1,136 @@
+package api
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ofkm/arcane-backend/internal/config"
+	"github.com/ofkm/arcane-backend/internal/dto"
+	"github.com/ofkm/arcane-backend/internal/middleware"
+	"github.com/ofkm/arcane-backend/internal/models"
+	"github.com/ofkm/arcane-backend/internal/services"
+)
+
+// Inbound payloads are only needed for signature checks, so cap what we read.
+const maxWebhookBodySize = 1 << 20
+
+type ProjectWebhookHandler struct {
+	webhookService *services.ProjectWebhookService
+	cfg            *config.Config
+}
+
+func NewProjectWebhookHandler(group *gin.RouterGroup, webhookService *services.ProjectWebhookService, authMiddleware *middleware.AuthMiddleware, cfg *config.Config) {
+	handler := &ProjectWebhookHandler{webhookService: webhookService, cfg: cfg}
+
+	apiGroup := group.Group("/environments/:id/projects/:projectId/webhooks")
+	apiGroup.Use(authMiddleware.WithAdminNotRequired().Add())
+	{
+		apiGroup.GET("", handler.ListWebhooks)
+		apiGroup.POST("", handler.CreateWebhook)
+		apiGroup.DELETE("/:webhookId", handler.DeleteWebhook)
+	}
+
+	// Called by registries and CI; authenticated by the secret token in the URL.
+	group.POST("/webhooks/deploy/:token", handler.TriggerWebhook)
+}
+
+func (h *ProjectWebhookHandler) ListWebhooks(c *gin.Context) {
+	hooks, err := h.webhookService.ListWebhooks(c.Request.Context(), c.Param("projectId"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": err.Error()})
+		return
+	}
+
+	out := make([]dto.ProjectWebhookDto, 0, len(hooks))
+	for i := range hooks {
+		out = append(out, toProjectWebhookDto(&hooks[i]))
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": out})
+}
+
+func (h *ProjectWebhookHandler) CreateWebhook(c *gin.Context) {
+	var req dto.CreateProjectWebhookDto
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid request: " + err.Error()})
+		return
+	}
+
+	hook, token, err := h.webhookService.CreateWebhook(c.Request.Context(), c.Param("projectId"), req)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrInvalidWebhookInput) {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{"success": false, "error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data": dto.CreatedProjectWebhookDto{
+			ProjectWebhookDto: toProjectWebhookDto(hook),
+			URL:               strings.TrimRight(h.cfg.AppUrl, "/") + "/api/webhooks/deploy/" + token,
+		},
+	})
+}
+
+func (h *ProjectWebhookHandler) DeleteWebhook(c *gin.Context) {
+	if err := h.webhookService.DeleteWebhook(c.Request.Context(), c.Param("projectId"), c.Param("webhookId")); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrWebhookNotFound) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{"success": false, "error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": gin.H{"message": "Webhook deleted successfully"}})
+}
+
+func (h *ProjectWebhookHandler) TriggerWebhook(c *gin.Context) {
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxWebhookBodySize))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Failed to read request body"})
+		return
+	}
+
+	status, err := h.webhookService.Trigger(c.Request.Context(), c.Param("token"), c.Request.Header, body)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrWebhookNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"success": false, "error": "Webhook not found"})
+		case errors.Is(err, services.ErrWebhookSignature):
+			c.JSON(http.StatusUnauthorized, gin.H{"success": false, "error": "Invalid signature"})
+		default:
+			slog.ErrorContext(c.Request.Context(), "webhook trigger failed", "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "Failed to process webhook"})
+		}
+		return
+	}
+
+	code := http.StatusAccepted
+	if status == services.WebhookTriggerIgnored {
+		code = http.StatusOK
+	}
+	c.JSON(code, gin.H{"success": true, "data": gin.H{"status": status}})
+}
+
+func toProjectWebhookDto(hook *models.ProjectWebhook) dto.ProjectWebhookDto {
+	return dto.ProjectWebhookDto{
+		ID:              hook.ID,
+		ProjectID:       hook.ProjectID,
+		Name:            hook.Name,
+		Provider:        string(hook.Provider),
+		TokenPrefix:     hook.TokenPrefix,
+		HasSecret:       hook.Secret != "",
+		Enabled:         hook.Enabled,
+		LastTriggeredAt: hook.LastTriggeredAt,
+		LastStatus:      hook.LastStatus,
+		LastError:       hook.LastError,
+		CreatedAt:       hook.CreatedAt,
+	}
+}
@@ -0,0 +1,29 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/ofkm/arcane-backend/internal/middleware"
+)
+
+// NewPrometheusHandler serves /api/metrics in the Prometheus and OpenMetrics text formats.
+// Scrapers authenticate with an API token via X-API-Key or an Authorization bearer header;
+// session cookies and JWTs are rejected. The token's owner needs system:read.
+func NewPrometheusHandler(group *gin.RouterGroup, authMiddleware *middleware.AuthMiddleware, metricCollectors ...prometheus.Collector) {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	registry.MustRegister(metricCollectors...)
+
+	handler := promhttp.HandlerFor(registry, promhttp.HandlerOpts{
+		EnableOpenMetrics: true,
+		ErrorHandling:     promhttp.ContinueOnError,
+	})
+
+	group.GET("/metrics", authMiddleware.WithAdminNotRequired().WithApiTokenRequired().Add(), gin.WrapH(handler))
+}
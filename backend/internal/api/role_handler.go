@@ -0,0 +1,190 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ofkm/arcane-backend/internal/dto"
+	"github.com/ofkm/arcane-backend/internal/middleware"
+	"github.com/ofkm/arcane-backend/internal/models"
+	"github.com/ofkm/arcane-backend/internal/services"
+)
+
+type RoleHandler struct {
+	roleService *services.RoleService
+}
+
+func NewRoleHandler(group *gin.RouterGroup, roleService *services.RoleService, authMiddleware *middleware.AuthMiddleware) {
+	handler := &RoleHandler{roleService: roleService}
+
+	roles := group.Group("/roles")
+	roles.Use(authMiddleware.WithAdminRequired().Add())
+	{
+		roles.GET("", handler.ListRoles)
+		roles.POST("", handler.CreateRole)
+		roles.GET("/:name", handler.GetRole)
+		roles.PUT("/:name", handler.UpdateRole)
+		roles.DELETE("/:name", handler.DeleteRole)
+	}
+
+	bindings := group.Group("/users/:id/role-bindings")
+	bindings.Use(authMiddleware.WithAdminRequired().Add())
+	{
+		bindings.GET("", handler.ListRoleBindings)
+		bindings.POST("", handler.CreateRoleBinding)
+		bindings.DELETE("/:bindingId", handler.DeleteRoleBinding)
+	}
+}
+
+func (h *RoleHandler) ListRoles(c *gin.Context) {
+	roles, err := h.roleService.ListRoles(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "data": gin.H{"error": "Failed to list roles: " + err.Error()}})
+		return
+	}
+
+	out, err := dto.MapSlice[models.Role, dto.RoleDto](roles)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "data": gin.H{"error": "Failed to map roles"}})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": out})
+}
+
+func (h *RoleHandler) GetRole(c *gin.Context) {
+	role, err := h.roleService.GetRole(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		h.writeRoleError(c, err, "Failed to get role")
+		return
+	}
+
+	out, err := dto.MapOne[*models.Role, dto.RoleDto](role)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "data": gin.H{"error": "Failed to map role"}})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": out})
+}
+
+func (h *RoleHandler) CreateRole(c *gin.Context) {
+	var req dto.CreateRoleDto
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "data": gin.H{"error": "Invalid request format"}})
+		return
+	}
+
+	role, err := h.roleService.CreateRole(c.Request.Context(), req)
+	if err != nil {
+		h.writeRoleError(c, err, "Failed to create role")
+		return
+	}
+
+	out, err := dto.MapOne[*models.Role, dto.RoleDto](role)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "data": gin.H{"error": "Failed to map role"}})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"success": true, "data": out})
+}
+
+func (h *RoleHandler) UpdateRole(c *gin.Context) {
+	var req dto.UpdateRoleDto
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "data": gin.H{"error": "Invalid request format"}})
+		return
+	}
+
+	role, err := h.roleService.UpdateRole(c.Request.Context(), c.Param("name"), req)
+	if err != nil {
+		h.writeRoleError(c, err, "Failed to update role")
+		return
+	}
+
+	out, err := dto.MapOne[*models.Role, dto.RoleDto](role)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "data": gin.H{"error": "Failed to map role"}})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": out})
+}
+
+func (h *RoleHandler) DeleteRole(c *gin.Context) {
+	if err := h.roleService.DeleteRole(c.Request.Context(), c.Param("name")); err != nil {
+		h.writeRoleError(c, err, "Failed to delete role")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": gin.H{"message": "Role deleted successfully"}})
+}
+
+func (h *RoleHandler) ListRoleBindings(c *gin.Context) {
+	bindings, err := h.roleService.ListUserBindings(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "data": gin.H{"error": "Failed to list role bindings: " + err.Error()}})
+		return
+	}
+
+	out, err := dto.MapSlice[models.RoleBinding, dto.RoleBindingDto](bindings)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "data": gin.H{"error": "Failed to map role bindings"}})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": out})
+}
+
+func (h *RoleHandler) CreateRoleBinding(c *gin.Context) {
+	var req dto.CreateRoleBindingDto
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "data": gin.H{"error": "Invalid request format"}})
+		return
+	}
+
+	binding, err := h.roleService.CreateBinding(c.Request.Context(), c.Param("id"), req)
+	if err != nil {
+		h.writeRoleError(c, err, "Failed to create role binding")
+		return
+	}
+
+	out, err := dto.MapOne[*models.RoleBinding, dto.RoleBindingDto](binding)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "data": gin.H{"error": "Failed to map role binding"}})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"success": true, "data": out})
+}
+
+func (h *RoleHandler) DeleteRoleBinding(c *gin.Context) {
+	if err := h.roleService.DeleteBinding(c.Request.Context(), c.Param("id"), c.Param("bindingId")); err != nil {
+		h.writeRoleError(c, err, "Failed to delete role binding")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": gin.H{"message": "Role binding deleted successfully"}})
+}
+
+func (h *RoleHandler) writeRoleError(c *gin.Context, err error, fallback string) {
+	status := http.StatusInternalServerError
+	msg := fallback
+	switch {
+	case errors.Is(err, services.ErrRoleNotFound), errors.Is(err, services.ErrRoleBindingNotFound):
+		status = http.StatusNotFound
+		msg = err.Error()
+	case errors.Is(err, services.ErrRoleExists), errors.Is(err, services.ErrRoleInUse), errors.Is(err, services.ErrRoleMapped):
+		status = http.StatusConflict
+		msg = err.Error()
+	case errors.Is(err, services.ErrRoleBuiltIn):
+		status = http.StatusForbidden
+		msg = err.Error()
+	case errors.Is(err, services.ErrInvalidRoleBinding):
+		status = http.StatusBadRequest
+		msg = err.Error()
+	}
+	c.JSON(status, gin.H{"success": false, "data": gin.H{"error": msg}})
+}
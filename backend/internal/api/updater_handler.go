@@ -1,6 +1,7 @@
 package api
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -11,11 +12,12 @@ import (
 )
 
 type UpdaterHandler struct {
-	updaterService *services.UpdaterService
+	updaterService      *services.UpdaterService
+	updatePolicyService *services.UpdatePolicyService
 }
 
-func NewUpdaterHandler(group *gin.RouterGroup, updaterService *services.UpdaterService, authMiddleware *middleware.AuthMiddleware) {
-	handler := &UpdaterHandler{updaterService: updaterService}
+func NewUpdaterHandler(group *gin.RouterGroup, updaterService *services.UpdaterService, updatePolicyService *services.UpdatePolicyService, authMiddleware *middleware.AuthMiddleware) {
+	handler := &UpdaterHandler{updaterService: updaterService, updatePolicyService: updatePolicyService}
 
 	apiGroup := group.Group("/environments/:id/updater")
 	apiGroup.Use(authMiddleware.WithAdminNotRequired().Add())
@@ -23,6 +25,11 @@ func NewUpdaterHandler(group *gin.RouterGroup, updaterService *services.UpdaterS
 		apiGroup.POST("/run", handler.Run)
 		apiGroup.GET("/history", handler.History)
 		apiGroup.GET("/status", handler.Status)
+		apiGroup.POST("/history/:recordId/rollback", handler.Rollback)
+
+		apiGroup.GET("/policies", handler.ListPolicies)
+		apiGroup.PUT("/policies", handler.UpsertPolicy)
+		apiGroup.DELETE("/policies/:policyId", handler.DeletePolicy)
 	}
 }
 
@@ -59,3 +66,60 @@ func (h *UpdaterHandler) History(c *gin.Context) {
 	}
 	c.JSON(http.StatusOK, gin.H{"success": true, "data": history})
 }
+
+func (h *UpdaterHandler) Rollback(c *gin.Context) {
+	user, _ := middleware.GetCurrentUser(c)
+	rec, err := h.updaterService.Rollback(c.Request.Context(), c.Param("recordId"), *user)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, services.ErrUpdateRecordNotFound):
+			status = http.StatusNotFound
+		case errors.Is(err, services.ErrRollbackUnavailable):
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{"success": false, "error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": rec})
+}
+
+func (h *UpdaterHandler) ListPolicies(c *gin.Context) {
+	policies, err := h.updatePolicyService.ListPolicies(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": policies})
+}
+
+func (h *UpdaterHandler) UpsertPolicy(c *gin.Context) {
+	var req dto.UpsertUpdatePolicyDto
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Invalid request: " + err.Error()})
+		return
+	}
+
+	policy, err := h.updatePolicyService.UpsertPolicy(c.Request.Context(), req)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrInvalidUpdatePolicy) {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{"success": false, "error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": policy})
+}
+
+func (h *UpdaterHandler) DeletePolicy(c *gin.Context) {
+	if err := h.updatePolicyService.DeletePolicy(c.Request.Context(), c.Param("policyId")); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrUpdatePolicyNotFound) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{"success": false, "error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": gin.H{"message": "Update policy deleted successfully"}})
+}
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"errors"
 	"net/http"
 	"time"
 
@@ -13,12 +14,14 @@ import (
 )
 
 type UserHandler struct {
-	userService *services.UserService
+	userService    *services.UserService
+	authService    *services.AuthService
+	sessionService *services.SessionService
 }
 
-func NewUserHandler(group *gin.RouterGroup, userService *services.UserService, authMiddleware *middleware.AuthMiddleware) {
+func NewUserHandler(group *gin.RouterGroup, userService *services.UserService, authService *services.AuthService, sessionService *services.SessionService, authMiddleware *middleware.AuthMiddleware) {
 
-	handler := &UserHandler{userService: userService}
+	handler := &UserHandler{userService: userService, authService: authService, sessionService: sessionService}
 
 	apiGroup := group.Group("/users")
 	apiGroup.Use(authMiddleware.WithAdminRequired().Add())
@@ -28,6 +31,10 @@ func NewUserHandler(group *gin.RouterGroup, userService *services.UserService, a
 		apiGroup.GET("/:id", handler.GetUser)
 		apiGroup.PUT("/:id", handler.UpdateUser)
 		apiGroup.DELETE("/:id", handler.DeleteUser)
+		apiGroup.DELETE("/:id/totp", handler.ResetUserTotp)
+		apiGroup.GET("/:id/sessions", handler.ListUserSessions)
+		apiGroup.DELETE("/:id/sessions", handler.RevokeUserSessions)
+		apiGroup.DELETE("/:id/sessions/:sessionId", handler.RevokeUserSession)
 	}
 }
 
@@ -226,3 +233,89 @@ func (h *UserHandler) DeleteUser(c *gin.Context) {
 		"data":    gin.H{"message": "User deleted successfully"},
 	})
 }
+
+func (h *UserHandler) ResetUserTotp(c *gin.Context) {
+	userID := c.Param("id")
+
+	if err := h.authService.ResetTotp(c.Request.Context(), userID); err != nil {
+		status := http.StatusInternalServerError
+		msg := "Failed to reset two-factor authentication"
+		if errors.Is(err, services.ErrUserNotFound) {
+			status = http.StatusNotFound
+			msg = "User not found"
+		}
+		c.JSON(status, gin.H{
+			"success": false,
+			"data":    gin.H{"error": msg},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    gin.H{"message": "Two-factor authentication reset successfully"},
+	})
+}
+
+func (h *UserHandler) ListUserSessions(c *gin.Context) {
+	sessions, err := h.sessionService.ListSessions(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"data":    gin.H{"error": "Failed to list sessions"},
+		})
+		return
+	}
+
+	out, err := mapSessions(sessions, middleware.GetCurrentSessionID(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"data":    gin.H{"error": "Failed to map sessions"},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    out,
+	})
+}
+
+// RevokeUserSessions signs a user out of every session, e.g. when offboarding them.
+func (h *UserHandler) RevokeUserSessions(c *gin.Context) {
+	revoked, err := h.sessionService.RevokeUserSessions(c.Request.Context(), c.Param("id"), "", services.SessionRevokedByAdmin)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"data":    gin.H{"error": "Failed to revoke sessions"},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    gin.H{"revoked": revoked},
+	})
+}
+
+func (h *UserHandler) RevokeUserSession(c *gin.Context) {
+	if err := h.sessionService.RevokeSession(c.Request.Context(), c.Param("id"), c.Param("sessionId"), services.SessionRevokedByAdmin); err != nil {
+		status := http.StatusInternalServerError
+		msg := "Failed to revoke session"
+		if errors.Is(err, services.ErrSessionNotFound) {
+			status = http.StatusNotFound
+			msg = "Session not found"
+		}
+		c.JSON(status, gin.H{
+			"success": false,
+			"data":    gin.H{"error": msg},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    gin.H{"message": "Session revoked successfully"},
+	})
+}
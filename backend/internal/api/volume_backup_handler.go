@@ -0,0 +1,281 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ofkm/arcane-backend/internal/dto"
+	"github.com/ofkm/arcane-backend/internal/middleware"
+	"github.com/ofkm/arcane-backend/internal/models"
+	"github.com/ofkm/arcane-backend/internal/services"
+)
+
+type VolumeBackupHandler struct {
+	volumeBackupService *services.VolumeBackupService
+}
+
+func NewVolumeBackupHandler(group *gin.RouterGroup, volumeBackupService *services.VolumeBackupService, authMiddleware *middleware.AuthMiddleware) {
+	handler := &VolumeBackupHandler{volumeBackupService: volumeBackupService}
+
+	volumeGroup := group.Group("/environments/:id/volumes")
+	volumeGroup.Use(authMiddleware.WithAdminNotRequired().Add())
+	{
+		volumeGroup.POST("/:volumeName/backups", handler.CreateBackup)
+	}
+
+	apiGroup := group.Group("/environments/:id/backups")
+	apiGroup.Use(authMiddleware.WithAdminNotRequired().Add())
+	{
+		apiGroup.GET("", handler.ListBackups)
+		apiGroup.DELETE("/:backupId", handler.DeleteBackup)
+		apiGroup.POST("/:backupId/restore", handler.RestoreBackup)
+
+		apiGroup.GET("/targets", handler.ListTargets)
+		apiGroup.POST("/targets", handler.CreateTarget)
+		apiGroup.PUT("/targets/:targetId", handler.UpdateTarget)
+		apiGroup.DELETE("/targets/:targetId", handler.DeleteTarget)
+
+		apiGroup.GET("/schedules", handler.ListSchedules)
+		apiGroup.POST("/schedules", handler.CreateSchedule)
+		apiGroup.PUT("/schedules/:scheduleId", handler.UpdateSchedule)
+		apiGroup.DELETE("/schedules/:scheduleId", handler.DeleteSchedule)
+	}
+}
+
+func (h *VolumeBackupHandler) ListBackups(c *gin.Context) {
+	backups, err := h.volumeBackupService.ListBackups(c.Request.Context(), c.Query("volume"))
+	if err != nil {
+		h.writeBackupError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": backups})
+}
+
+func (h *VolumeBackupHandler) CreateBackup(c *gin.Context) {
+	var req dto.CreateVolumeBackupDto
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"data":    gin.H{"error": "Invalid request: " + err.Error()},
+			})
+			return
+		}
+	}
+
+	user, _ := middleware.GetCurrentUser(c)
+	backup, err := h.volumeBackupService.CreateBackup(c.Request.Context(), c.Param("volumeName"), req, *user)
+	if err != nil {
+		if backup != nil {
+			// The attempt was recorded; return it so the caller can see the failure entry.
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"success": false,
+				"data":    gin.H{"error": err.Error(), "backup": backup},
+			})
+			return
+		}
+		h.writeBackupError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"success": true, "data": backup})
+}
+
+func (h *VolumeBackupHandler) RestoreBackup(c *gin.Context) {
+	var req dto.RestoreVolumeBackupDto
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"data":    gin.H{"error": "Invalid request: " + err.Error()},
+			})
+			return
+		}
+	}
+
+	user, _ := middleware.GetCurrentUser(c)
+	if err := h.volumeBackupService.RestoreBackup(c.Request.Context(), c.Param("backupId"), req, *user); err != nil {
+		h.writeBackupError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    gin.H{"message": "Volume restored successfully"},
+	})
+}
+
+func (h *VolumeBackupHandler) DeleteBackup(c *gin.Context) {
+	user, _ := middleware.GetCurrentUser(c)
+	if err := h.volumeBackupService.DeleteBackup(c.Request.Context(), c.Param("backupId"), *user); err != nil {
+		h.writeBackupError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    gin.H{"message": "Backup deleted successfully"},
+	})
+}
+
+func (h *VolumeBackupHandler) ListTargets(c *gin.Context) {
+	targets, err := h.volumeBackupService.ListTargets(c.Request.Context())
+	if err != nil {
+		h.writeBackupError(c, err)
+		return
+	}
+
+	out := make([]dto.BackupTargetDto, 0, len(targets))
+	for i := range targets {
+		out = append(out, toBackupTargetDto(&targets[i]))
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": out})
+}
+
+func (h *VolumeBackupHandler) CreateTarget(c *gin.Context) {
+	var req dto.CreateBackupTargetDto
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"data":    gin.H{"error": "Invalid request: " + err.Error()},
+		})
+		return
+	}
+
+	target, err := h.volumeBackupService.CreateTarget(c.Request.Context(), req)
+	if err != nil {
+		h.writeBackupError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"success": true, "data": toBackupTargetDto(target)})
+}
+
+func (h *VolumeBackupHandler) UpdateTarget(c *gin.Context) {
+	var req dto.UpdateBackupTargetDto
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"data":    gin.H{"error": "Invalid request: " + err.Error()},
+		})
+		return
+	}
+
+	target, err := h.volumeBackupService.UpdateTarget(c.Request.Context(), c.Param("targetId"), req)
+	if err != nil {
+		h.writeBackupError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": toBackupTargetDto(target)})
+}
+
+func (h *VolumeBackupHandler) DeleteTarget(c *gin.Context) {
+	if err := h.volumeBackupService.DeleteTarget(c.Request.Context(), c.Param("targetId")); err != nil {
+		h.writeBackupError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    gin.H{"message": "Backup target deleted successfully"},
+	})
+}
+
+func (h *VolumeBackupHandler) ListSchedules(c *gin.Context) {
+	schedules, err := h.volumeBackupService.ListSchedules(c.Request.Context())
+	if err != nil {
+		h.writeBackupError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": schedules})
+}
+
+func (h *VolumeBackupHandler) CreateSchedule(c *gin.Context) {
+	var req dto.CreateVolumeBackupScheduleDto
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"data":    gin.H{"error": "Invalid request: " + err.Error()},
+		})
+		return
+	}
+
+	schedule, err := h.volumeBackupService.CreateSchedule(c.Request.Context(), req)
+	if err != nil {
+		h.writeBackupError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"success": true, "data": schedule})
+}
+
+func (h *VolumeBackupHandler) UpdateSchedule(c *gin.Context) {
+	var req dto.UpdateVolumeBackupScheduleDto
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"data":    gin.H{"error": "Invalid request: " + err.Error()},
+		})
+		return
+	}
+
+	schedule, err := h.volumeBackupService.UpdateSchedule(c.Request.Context(), c.Param("scheduleId"), req)
+	if err != nil {
+		h.writeBackupError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": schedule})
+}
+
+func (h *VolumeBackupHandler) DeleteSchedule(c *gin.Context) {
+	if err := h.volumeBackupService.DeleteSchedule(c.Request.Context(), c.Param("scheduleId")); err != nil {
+		h.writeBackupError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    gin.H{"message": "Backup schedule deleted successfully"},
+	})
+}
+
+func (h *VolumeBackupHandler) writeBackupError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, services.ErrVolumeBackupNotFound),
+		errors.Is(err, services.ErrBackupTargetNotFound),
+		errors.Is(err, services.ErrBackupScheduleNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, services.ErrVolumeBackupBusy),
+		errors.Is(err, services.ErrBackupTargetInUse):
+		status = http.StatusConflict
+	case errors.Is(err, services.ErrInvalidVolumeBackup):
+		status = http.StatusBadRequest
+	}
+	c.JSON(status, gin.H{
+		"success": false,
+		"data":    gin.H{"error": err.Error()},
+	})
+}
+
+func toBackupTargetDto(t *models.BackupTarget) dto.BackupTargetDto {
+	return dto.BackupTargetDto{
+		ID:          t.ID,
+		Name:        t.Name,
+		Type:        string(t.Type),
+		Path:        t.Path,
+		Endpoint:    t.Endpoint,
+		Region:      t.Region,
+		Bucket:      t.Bucket,
+		Prefix:      t.Prefix,
+		AccessKeyID: t.AccessKeyID,
+		HasSecret:   t.SecretAccessKey != "",
+		PathStyle:   t.PathStyle,
+		CreatedAt:   t.CreatedAt,
+	}
+}
@@ -74,13 +74,17 @@ func Bootstrap(ctx context.Context) error {
 			return err
 		})
 
+	if err := appServices.NotificationSub.ResumeDeliveries(appCtx); err != nil {
+		slog.WarnContext(appCtx, "Failed to resume notification deliveries", "error", err)
+	}
+
 	scheduler, err := initializeScheduler()
 	if err != nil {
 		return fmt.Errorf("failed to create job scheduler: %w", err)
 	}
 	registerJobs(appCtx, scheduler, appServices, cfg)
 
-	router := setupRouter(cfg, appServices) //nolint:contextcheck
+	router := setupRouter(cfg, appServices, scheduler) //nolint:contextcheck
 
 	err = runServices(appCtx, cfg, router, scheduler)
 	if err != nil {
@@ -42,10 +42,39 @@ func registerJobs(appCtx context.Context, scheduler *job.Scheduler, appServices
 		slog.ErrorContext(appCtx, "Failed to register event cleanup job", slog.Any("error", err))
 	}
 
+	if err := job.RegisterAuditLogCleanupJob(appCtx, scheduler, appServices.Audit, appServices.Settings); err != nil {
+		slog.ErrorContext(appCtx, "Failed to register audit log cleanup job", slog.Any("error", err))
+	}
+
+	if err := job.RegisterSessionCleanupJob(appCtx, scheduler, appServices.Session, appServices.LoginThrottle); err != nil {
+		slog.ErrorContext(appCtx, "Failed to register session cleanup job", slog.Any("error", err))
+	}
+
 	if err := job.RegisterFilesystemWatcherJob(appCtx, scheduler, appServices.Project, appServices.Template, appServices.Settings); err != nil {
 		slog.ErrorContext(appCtx, "Failed to register filesystem watcher job", slog.Any("error", err))
 	}
 
+	if err := job.RegisterGitSyncJob(appCtx, scheduler, appServices.ProjectGit); err != nil {
+		slog.ErrorContext(appCtx, "Failed to register git sync job", slog.Any("error", err))
+	}
+
+	if err := job.RegisterVolumeBackupJob(appCtx, scheduler, appServices.VolumeBackup); err != nil {
+		slog.ErrorContext(appCtx, "Failed to register volume backup job", slog.Any("error", err))
+	}
+
+	if err := job.RegisterAlertEvaluationJob(appCtx, scheduler, appServices.Alert); err != nil {
+		slog.ErrorContext(appCtx, "Failed to register alert evaluation job", slog.Any("error", err))
+	}
+
+	if err := job.RegisterNotificationDigestJob(appCtx, scheduler, appServices.Notification); err != nil {
+		slog.ErrorContext(appCtx, "Failed to register notification digest job", slog.Any("error", err))
+	}
+
+	metricsJob := job.NewMetricsJob(scheduler, appServices.Metrics)
+	if err := metricsJob.Register(appCtx); err != nil {
+		slog.ErrorContext(appCtx, "Failed to register metrics job", slog.Any("error", err))
+	}
+
 	appServices.Settings.OnImagePollingSettingsChanged = func(ctx context.Context) {
 		if err := imagePollingJob.Reschedule(ctx); err != nil {
 			slog.WarnContext(ctx, "Failed to reschedule image-polling job", slog.Any("error", err))
@@ -62,4 +91,12 @@ func registerJobs(appCtx context.Context, scheduler *job.Scheduler, appServices
 			slog.WarnContext(ctx, "Failed to reschedule auto-update job", slog.Any("error", err))
 		}
 	}
+	appServices.Settings.OnMetricsSettingsChanged = func(ctx context.Context) {
+		if err := metricsJob.Reschedule(ctx); err != nil {
+			slog.WarnContext(ctx, "Failed to reschedule metrics job", slog.Any("error", err))
+		}
+	}
+
+	appServices.EventSink.Start(appCtx)
+	appServices.Settings.OnEventSinkSettingsChanged = appServices.EventSink.Reload
 }
@@ -12,12 +12,13 @@ import (
 	"github.com/ofkm/arcane-backend/frontend"
 	"github.com/ofkm/arcane-backend/internal/api"
 	"github.com/ofkm/arcane-backend/internal/config"
+	"github.com/ofkm/arcane-backend/internal/job"
 	"github.com/ofkm/arcane-backend/internal/middleware"
 )
 
 var registerPlaywrightRoutes []func(apiGroup *gin.RouterGroup, services *Services)
 
-func setupRouter(cfg *config.Config, appServices *Services) *gin.Engine {
+func setupRouter(cfg *config.Config, appServices *Services, scheduler *job.Scheduler) *gin.Engine {
 
 	if cfg.Environment == "production" {
 		gin.SetMode(gin.ReleaseMode)
@@ -27,6 +28,13 @@ func setupRouter(cfg *config.Config, appServices *Services) *gin.Engine {
 	router := gin.New()
 	router.Use(gin.Recovery())
 
+	// Client IPs key the login throttle and the audit log, so X-Forwarded-For is only
+	// honoured when it comes from a configured proxy.
+	if err := router.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		slog.Warn("Ignoring invalid TRUSTED_PROXIES, no proxy will be trusted", "error", err)
+		_ = router.SetTrustedProxies(nil)
+	}
+
 	loggerSkipPatterns := []string{
 		"GET /api/environments/*/containers/*/logs/ws",
 		"GET /api/environments/*/containers/*/stats/ws",
@@ -66,21 +74,24 @@ func setupRouter(cfg *config.Config, appServices *Services) *gin.Engine {
 		},
 	}))
 
-	authMiddleware := middleware.NewAuthMiddleware(appServices.Auth, cfg)
+	authMiddleware := middleware.NewAuthMiddleware(appServices.Auth, appServices.Role, appServices.ApiToken, cfg)
 	corsMiddleware := middleware.NewCORSMiddleware(cfg).Add()
 	router.Use(corsMiddleware)
 
 	apiGroup := router.Group("/api")
 
 	api.NewApplicationImagesHandler(apiGroup, appServices.AppImages)
-	api.NewUserHandler(apiGroup, appServices.User, authMiddleware)
+	api.NewUserHandler(apiGroup, appServices.User, appServices.Auth, appServices.Session, authMiddleware)
+	api.NewRoleHandler(apiGroup, appServices.Role, authMiddleware)
+	api.NewApiTokenHandler(apiGroup, appServices.ApiToken, authMiddleware)
 	api.NewVersionHandler(apiGroup, appServices.Version)
-	api.NewAuthHandler(apiGroup, appServices.User, appServices.Auth, appServices.Oidc, authMiddleware)
+	api.NewAuthHandler(apiGroup, appServices.User, appServices.Auth, appServices.Oidc, appServices.Role, appServices.Session, appServices.LoginThrottle, authMiddleware)
 	api.NewEventHandler(apiGroup, appServices.Event, authMiddleware)
-	api.NewOidcHandler(apiGroup, appServices.Auth, appServices.Oidc)
+	api.NewOidcHandler(apiGroup, appServices.Auth, appServices.Oidc, appServices.OidcProvider, authMiddleware)
 	api.NewEnvironmentHandler(apiGroup, appServices.Environment, appServices.Settings, authMiddleware, cfg)
 	api.NewContainerRegistryHandler(apiGroup, appServices.ContainerRegistry, authMiddleware)
 	api.NewTemplateHandler(apiGroup, appServices.Template, authMiddleware)
+	api.NewPrometheusHandler(apiGroup, authMiddleware, appServices.Prometheus, scheduler.Collector())
 
 	envMiddleware := middleware.NewEnvProxyMiddlewareWithParam(
 		api.LOCAL_DOCKER_ENVIRONMENT_ID,
@@ -93,6 +104,7 @@ func setupRouter(cfg *config.Config, appServices *Services) *gin.Engine {
 			return env.ApiUrl, env.AccessToken, env.Enabled, nil
 		},
 		appServices.Environment,
+		authMiddleware.AuthorizeRemote,
 	)
 	apiGroup.Use(envMiddleware)
 
@@ -102,10 +114,17 @@ func setupRouter(cfg *config.Config, appServices *Services) *gin.Engine {
 	api.NewImageUpdateHandler(apiGroup, appServices.ImageUpdate, authMiddleware)
 	api.NewNetworkHandler(apiGroup, appServices.Docker, appServices.Network, authMiddleware)
 	api.NewProjectHandler(apiGroup, appServices.Project, authMiddleware, cfg)
+	api.NewProjectGitHandler(apiGroup, appServices.Project, appServices.ProjectGit, authMiddleware)
+	api.NewProjectWebhookHandler(apiGroup, appServices.ProjectWebhook, authMiddleware, cfg)
 	api.NewSystemHandler(apiGroup, appServices.Docker, appServices.System, appServices.SystemUpgrade, authMiddleware, cfg)
-	api.NewUpdaterHandler(apiGroup, appServices.Updater, authMiddleware)
+	api.NewMetricsHandler(apiGroup, appServices.Metrics, authMiddleware)
+	api.NewUpdaterHandler(apiGroup, appServices.Updater, appServices.UpdatePolicy, authMiddleware)
 	api.NewVolumeHandler(apiGroup, appServices.Docker, appServices.Volume, authMiddleware)
+	api.NewVolumeBackupHandler(apiGroup, appServices.VolumeBackup, authMiddleware)
+	api.NewAlertHandler(apiGroup, appServices.Alert, authMiddleware)
+	api.NewNotificationSubscriptionHandler(apiGroup, appServices.NotificationSub, authMiddleware)
 	api.NewNotificationHandler(apiGroup, appServices.Notification, appServices.Apprise, authMiddleware)
+	api.NewAuditHandler(apiGroup, appServices.Audit, authMiddleware)
 	api.NewSettingsHandler(apiGroup, appServices.Settings, appServices.SettingsSearch, authMiddleware)
 	api.NewCustomizeHandler(apiGroup, appServices.CustomizeSearch, authMiddleware)
 
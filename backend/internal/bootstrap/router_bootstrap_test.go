@@ -0,0 +1,156 @@
+package bootstrap
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	glsqlite "github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/ofkm/arcane-backend/internal/config"
+	"github.com/ofkm/arcane-backend/internal/database"
+	"github.com/ofkm/arcane-backend/internal/dto"
+	"github.com/ofkm/arcane-backend/internal/job"
+	"github.com/ofkm/arcane-backend/internal/models"
+	"github.com/ofkm/arcane-backend/internal/services"
+)
+
+var routeParamPattern = regexp.MustCompile(`[:*][A-Za-z]+`)
+
+// setupTestRouter builds the real router on an in-memory database and returns it with
+// a narrowly scoped API token of a non-admin user and the services it was built from.
+func setupTestRouter(t *testing.T, cfg *config.Config) (*gin.Engine, string, *Services) {
+	t.Helper()
+	ctx := context.Background()
+	db, err := gorm.Open(glsqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.User{}, &models.ApiToken{}, &models.Role{}, &models.RoleBinding{}, &models.Environment{}))
+	appDB := &database.DB{DB: db}
+
+	svcs := &Services{
+		User:        services.NewUserService(appDB, nil),
+		Role:        services.NewRoleService(appDB, nil),
+		Prometheus:  services.NewPrometheusService(appDB, nil, nil),
+		Environment: services.NewEnvironmentService(appDB, nil, nil, nil),
+	}
+	svcs.ApiToken = services.NewApiTokenService(appDB, svcs.User, nil)
+
+	user := &models.User{Username: "member", Roles: models.StringSlice{models.RoleUser}}
+	require.NoError(t, db.Create(user).Error)
+	_, apiKey, err := svcs.ApiToken.CreateToken(ctx, user.ID, dto.CreateApiTokenDto{Name: "scan", Scopes: []string{"routes:scan"}})
+	require.NoError(t, err)
+
+	scheduler, err := job.NewScheduler()
+	require.NoError(t, err)
+	return setupRouter(cfg, svcs, scheduler), apiKey, svcs
+}
+
+// TestRouter_NonAdminRoutesHavePermissions calls every registered route with a narrowly
+// scoped API token of a non-admin user. Admin routes refuse it for lacking admin rights
+// and mapped routes for lacking the route's permission; a route that refuses it only
+// because it has no routePermissions entry is reported.
+func TestRouter_NonAdminRoutesHavePermissions(t *testing.T) {
+	router, apiKey, _ := setupTestRouter(t, &config.Config{Environment: "production"})
+
+	var unmapped []string
+	for _, route := range router.Routes() {
+		path := routeParamPattern.ReplaceAllString(route.Path, "0")
+		req := httptest.NewRequest(route.Method, path, nil)
+		req.Header.Set("X-API-Key", apiKey)
+		w := httptest.NewRecorder()
+		func() {
+			defer func() { _ = recover() }()
+			router.ServeHTTP(w, req)
+		}()
+
+		if w.Code == http.StatusForbidden && strings.Contains(w.Body.String(), "does not cover this endpoint") {
+			unmapped = append(unmapped, route.Method+" "+route.Path)
+		}
+	}
+	require.Empty(t, unmapped, "non-admin routes missing from routePermissions")
+}
+
+func TestRouter_ForwardedForOnlyFromTrustedProxies(t *testing.T) {
+	clientIP := func(cfg *config.Config, remoteAddr string) string {
+		router, _, _ := setupTestRouter(t, cfg)
+		router.GET("/client-ip", func(c *gin.Context) { c.String(http.StatusOK, c.ClientIP()) })
+
+		req := httptest.NewRequest(http.MethodGet, "/client-ip", nil)
+		req.RemoteAddr = remoteAddr
+		req.Header.Set("X-Forwarded-For", "203.0.113.7")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Body.String()
+	}
+
+	require.Equal(t, "10.0.0.2", clientIP(&config.Config{Environment: "production"}, "10.0.0.2:5000"))
+	require.Equal(t, "203.0.113.7", clientIP(&config.Config{Environment: "production", TrustedProxies: []string{"10.0.0.0/8"}}, "10.0.0.2:5000"))
+	require.Equal(t, "192.0.2.9", clientIP(&config.Config{Environment: "production", TrustedProxies: []string{"10.0.0.0/8"}}, "192.0.2.9:5000"))
+}
+
+// TestRouter_RemoteEnvironmentRoutesRequireAuthorization sends every environment route
+// to a remote environment backed by a fake agent. Without credentials nothing may reach
+// the agent, and a non-admin user must not reach the admin-only routes that have no
+// routePermissions entry.
+func TestRouter_RemoteEnvironmentRoutesRequireAuthorization(t *testing.T) {
+	var proxied []string
+	agent := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = append(proxied, r.Method+" "+r.URL.Path)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer agent.Close()
+
+	router, _, svcs := setupTestRouter(t, &config.Config{Environment: "production"})
+	ctx := context.Background()
+	remote := &models.Environment{BaseModel: models.BaseModel{ID: "remote1"}, Name: "remote", ApiUrl: agent.URL, Enabled: true}
+	require.NoError(t, svcs.Environment.GetDB().WithContext(ctx).Create(remote).Error)
+
+	member := &models.User{Username: "operator", Roles: models.StringSlice{models.RoleUser}}
+	require.NoError(t, svcs.Environment.GetDB().WithContext(ctx).Create(member).Error)
+	_, memberKey, err := svcs.ApiToken.CreateToken(ctx, member.ID, dto.CreateApiTokenDto{Name: "full", Scopes: []string{"*"}})
+	require.NoError(t, err)
+
+	serve := func(method, path, apiKey string) int {
+		req := httptest.NewRequest(method, path, nil)
+		if apiKey != "" {
+			req.Header.Set("X-API-Key", apiKey)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	var envRoutes []gin.RouteInfo
+	for _, route := range router.Routes() {
+		if strings.HasPrefix(route.Path, "/api/environments/:id/") {
+			envRoutes = append(envRoutes, route)
+		}
+	}
+	require.NotEmpty(t, envRoutes)
+
+	for _, route := range envRoutes {
+		path := routeParamPattern.ReplaceAllString(strings.Replace(route.Path, ":id", remote.ID, 1), "0")
+		require.Equal(t, http.StatusUnauthorized, serve(route.Method, path, ""), route.Method+" "+route.Path)
+	}
+	require.Empty(t, proxied, "unauthenticated requests reached the agent")
+
+	for _, route := range envRoutes {
+		path := routeParamPattern.ReplaceAllString(strings.Replace(route.Path, ":id", remote.ID, 1), "0")
+		code := serve(route.Method, path, memberKey)
+		if !strings.HasPrefix(route.Path, "/api/environments/:id/alerts") &&
+			!strings.HasPrefix(route.Path, "/api/environments/:id/notifications") {
+			continue
+		}
+		require.Equal(t, http.StatusForbidden, code, route.Method+" "+route.Path)
+	}
+	for _, p := range proxied {
+		require.NotContains(t, p, "/alerts", "admin-only route reached the agent")
+		require.NotContains(t, p, "/notifications", "admin-only route reached the agent")
+	}
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/ofkm/arcane-backend/internal/config"
 	"github.com/ofkm/arcane-backend/internal/database"
+	"github.com/ofkm/arcane-backend/internal/models"
 	"github.com/ofkm/arcane-backend/internal/services"
 	"github.com/ofkm/arcane-backend/resources"
 )
@@ -14,7 +15,11 @@ import (
 type Services struct {
 	AppImages         *services.ApplicationImagesService
 	User              *services.UserService
+	Role              *services.RoleService
+	ApiToken          *services.ApiTokenService
 	Project           *services.ProjectService
+	ProjectGit        *services.ProjectGitService
+	ProjectWebhook    *services.ProjectWebhookService
 	Environment       *services.EnvironmentService
 	Settings          *services.SettingsService
 	SettingsSearch    *services.SettingsSearchService
@@ -22,10 +27,15 @@ type Services struct {
 	Container         *services.ContainerService
 	Image             *services.ImageService
 	Volume            *services.VolumeService
+	VolumeBackup      *services.VolumeBackupService
+	UpdatePolicy      *services.UpdatePolicyService
 	Network           *services.NetworkService
 	ImageUpdate       *services.ImageUpdateService
 	Auth              *services.AuthService
+	Session           *services.SessionService
+	LoginThrottle     *services.LoginThrottleService
 	Oidc              *services.OidcService
+	OidcProvider      *services.OidcProviderService
 	Docker            *services.DockerClientService
 	Template          *services.TemplateService
 	ContainerRegistry *services.ContainerRegistryService
@@ -35,14 +45,21 @@ type Services struct {
 	Event             *services.EventService
 	Version           *services.VersionService
 	Notification      *services.NotificationService
+	NotificationSub   *services.NotificationSubscriptionService
 	Apprise           *services.AppriseService
+	Metrics           *services.MetricsService
+	Prometheus        *services.PrometheusService
+	Alert             *services.AlertService
+	Audit             *services.AuditService
+	EventSink         *services.EventSinkService
 }
 
 func initializeServices(ctx context.Context, db *database.DB, cfg *config.Config, httpClient *http.Client) (svcs *Services, dockerSrvice *services.DockerClientService, err error) {
 	svcs = &Services{}
 
+	svcs.Audit = services.NewAuditService(db)
 	svcs.Event = services.NewEventService(db)
-	svcs.Settings, err = services.NewSettingsService(ctx, db)
+	svcs.Settings, err = services.NewSettingsService(ctx, db, svcs.Audit)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to settings service: %w", err)
 	}
@@ -51,21 +68,40 @@ func initializeServices(ctx context.Context, db *database.DB, cfg *config.Config
 	svcs.AppImages = services.NewApplicationImagesService(resources.FS, svcs.Settings)
 	dockerClient := services.NewDockerClientService(db, cfg)
 	svcs.Docker = dockerClient
-	svcs.User = services.NewUserService(db)
-	svcs.ContainerRegistry = services.NewContainerRegistryService(db)
-	svcs.Notification = services.NewNotificationService(db, cfg)
-	svcs.Apprise = services.NewAppriseService(db, cfg)
+	svcs.User = services.NewUserService(db, svcs.Audit)
+	svcs.Role = services.NewRoleService(db, svcs.Audit)
+	svcs.ApiToken = services.NewApiTokenService(db, svcs.User, svcs.Audit)
+	svcs.ContainerRegistry = services.NewContainerRegistryService(db, svcs.Audit)
+	svcs.Notification = services.NewNotificationService(db, cfg, svcs.Audit)
+	svcs.NotificationSub = services.NewNotificationSubscriptionService(db, svcs.Notification, svcs.Audit)
+	svcs.EventSink = services.NewEventSinkService(svcs.Settings)
+	svcs.Event.OnEventCreated = func(ctx context.Context, event models.Event) {
+		svcs.NotificationSub.Dispatch(ctx, event)
+		svcs.EventSink.PublishEvent(ctx, event)
+	}
+	svcs.Audit.OnRecorded = svcs.EventSink.PublishAudit
+	svcs.Apprise = services.NewAppriseService(db, cfg, svcs.Audit)
 	svcs.ImageUpdate = services.NewImageUpdateService(db, svcs.Settings, svcs.ContainerRegistry, svcs.Docker, svcs.Event, svcs.Notification)
 	svcs.Image = services.NewImageService(db, svcs.Docker, svcs.ContainerRegistry, svcs.ImageUpdate, svcs.Event)
-	svcs.Project = services.NewProjectService(db, svcs.Settings, svcs.Event, svcs.Image)
-	svcs.Environment = services.NewEnvironmentService(db, httpClient, svcs.Docker)
+	svcs.Project = services.NewProjectService(db, svcs.Settings, svcs.Event, svcs.Image, svcs.Audit)
+	svcs.ProjectGit = services.NewProjectGitService(db, svcs.Project, svcs.Settings, svcs.Event)
+	svcs.Environment = services.NewEnvironmentService(db, httpClient, svcs.Docker, svcs.Audit)
+	svcs.ProjectWebhook = services.NewProjectWebhookService(db, svcs.Project, svcs.Environment, svcs.Event)
 	svcs.Container = services.NewContainerService(db, svcs.Event, svcs.Docker, svcs.Image)
 	svcs.Volume = services.NewVolumeService(db, svcs.Docker, svcs.Event)
+	svcs.VolumeBackup = services.NewVolumeBackupService(db, svcs.Docker, svcs.Image, svcs.Settings, svcs.Event, svcs.Audit)
 	svcs.Network = services.NewNetworkService(db, svcs.Docker, svcs.Event)
-	svcs.Template = services.NewTemplateService(ctx, db, httpClient, svcs.Settings)
-	svcs.Auth = services.NewAuthService(svcs.User, svcs.Settings, svcs.Event, cfg.JWTSecret, cfg)
-	svcs.Oidc = services.NewOidcService(svcs.Auth, cfg, httpClient)
-	svcs.Updater = services.NewUpdaterService(db, svcs.Settings, svcs.Docker, svcs.Project, svcs.ImageUpdate, svcs.ContainerRegistry, svcs.Event, svcs.Image, svcs.Notification)
+	svcs.Template = services.NewTemplateService(ctx, db, httpClient, svcs.Settings, svcs.Audit)
+	svcs.Session = services.NewSessionService(db, svcs.Event)
+	svcs.LoginThrottle = services.NewLoginThrottleService(db, svcs.Settings, svcs.Event)
+	svcs.OidcProvider = services.NewOidcProviderService(db, svcs.Role, svcs.Audit)
+	svcs.Auth = services.NewAuthService(svcs.User, svcs.Settings, svcs.Event, svcs.Session, svcs.LoginThrottle, svcs.OidcProvider, cfg.JWTSecret, cfg)
+	svcs.Oidc = services.NewOidcService(svcs.Auth, svcs.OidcProvider, cfg, httpClient)
+	svcs.UpdatePolicy = services.NewUpdatePolicyService(db, svcs.Settings)
+	svcs.Updater = services.NewUpdaterService(db, svcs.Settings, svcs.Docker, svcs.Project, svcs.ImageUpdate, svcs.ContainerRegistry, svcs.Event, svcs.Image, svcs.Notification, svcs.VolumeBackup, svcs.UpdatePolicy)
+	svcs.Metrics = services.NewMetricsService(db, svcs.Docker, svcs.Settings)
+	svcs.Prometheus = services.NewPrometheusService(db, svcs.Docker, svcs.ImageUpdate)
+	svcs.Alert = services.NewAlertService(db, svcs.Docker, svcs.Notification)
 	svcs.System = services.NewSystemService(db, svcs.Docker, svcs.Container, svcs.Image, svcs.Volume, svcs.Network, svcs.Settings)
 	svcs.Version = services.NewVersionService(httpClient, cfg.UpdateCheckDisabled, config.Version, config.Revision)
 	svcs.SystemUpgrade = services.NewSystemUpgradeService(svcs.Docker, svcs.Version, svcs.Event)
@@ -40,6 +40,10 @@ type Config struct {
 	UpdateCheckDisabled     bool
 	UIConfigurationDisabled bool
 	AnalyticsDisabled       bool
+
+	// TrustedProxies lists the addresses or CIDRs of reverse proxies whose
+	// X-Forwarded-For header is believed. Empty means the peer address is always used.
+	TrustedProxies []string
 }
 
 func Load() *Config {
@@ -68,6 +72,8 @@ func Load() *Config {
 		UpdateCheckDisabled:     getBoolEnvOrDefault("UPDATE_CHECK_DISABLED", false),
 		UIConfigurationDisabled: getBoolEnvOrDefault("UI_CONFIGURATION_DISABLED", false),
 		AnalyticsDisabled:       getBoolEnvOrDefault("ANALYTICS_DISABLED", false),
+
+		TrustedProxies: getListEnv("TRUSTED_PROXIES"),
 	}
 }
 
@@ -86,6 +92,17 @@ func (a AppEnvironment) IsTestEnvironment() bool {
 	return a == AppEnvironmentTest
 }
 
+// getListEnv splits a comma-separated variable, dropping empty entries.
+func getListEnv(key string) []string {
+	var out []string
+	for _, v := range strings.Split(os.Getenv(key), ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
 func getBoolEnvOrDefault(key string, defaultValue bool) bool {
 	if v, ok := os.LookupEnv(key); ok && v != "" {
 		if b, err := strconv.ParseBool(v); err == nil {
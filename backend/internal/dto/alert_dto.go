@@ -0,0 +1,14 @@
+package dto
+
+import "github.com/ofkm/arcane-backend/internal/models"
+
+type AlertRuleDto struct {
+	Name            string               `json:"name" binding:"required"`
+	Type            models.AlertRuleType `json:"type" binding:"required"`
+	Enabled         *bool                `json:"enabled,omitempty"`
+	Target          string               `json:"target,omitempty"`
+	Threshold       float64              `json:"threshold,omitempty"`
+	DurationMinutes int                  `json:"durationMinutes,omitempty"`
+	CooldownMinutes *int                 `json:"cooldownMinutes,omitempty"`
+	NotifyResolved  *bool                `json:"notifyResolved,omitempty"`
+}
@@ -0,0 +1,26 @@
+package dto
+
+import "time"
+
+type ApiTokenDto struct {
+	ID         string     `json:"id"`
+	UserID     string     `json:"userId"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expiresAt,omitempty"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt"`
+}
+
+type CreateApiTokenDto struct {
+	Name      string     `json:"name" binding:"required"`
+	Scopes    []string   `json:"scopes,omitempty"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+}
+
+// CreatedApiTokenDto is only returned once, when the token is created.
+type CreatedApiTokenDto struct {
+	ApiTokenDto
+	Token string `json:"token"`
+}
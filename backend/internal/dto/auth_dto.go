@@ -13,3 +13,21 @@ type PasswordChangeRequest struct {
 	CurrentPassword string `json:"currentPassword"`
 	NewPassword     string `json:"newPassword" binding:"required"`
 }
+
+type LoginTotpRequest struct {
+	ChallengeToken string `json:"challengeToken" binding:"required"`
+	Code           string `json:"code" binding:"required"`
+}
+
+type LoginTotpEnrollRequest struct {
+	ChallengeToken string `json:"challengeToken" binding:"required"`
+}
+
+type TotpCodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+type TotpDisableRequest struct {
+	Password string `json:"password"`
+	Code     string `json:"code" binding:"required"`
+}
@@ -13,15 +13,16 @@ type ImagePullDto struct {
 }
 
 type ImageUpdateInfoDto struct {
-	HasUpdate      bool      `json:"hasUpdate"`
-	UpdateType     string    `json:"updateType"`
-	CurrentVersion string    `json:"currentVersion"`
-	LatestVersion  string    `json:"latestVersion"`
-	CurrentDigest  string    `json:"currentDigest"`
-	LatestDigest   string    `json:"latestDigest"`
-	CheckTime      time.Time `json:"checkTime"`
-	ResponseTimeMs int       `json:"responseTimeMs"`
-	Error          string    `json:"error"`
+	HasUpdate         bool      `json:"hasUpdate"`
+	UpdateType        string    `json:"updateType"`
+	CurrentVersion    string    `json:"currentVersion"`
+	LatestVersion     string    `json:"latestVersion"`
+	CandidateVersions []string  `json:"candidateVersions,omitempty"`
+	CurrentDigest     string    `json:"currentDigest"`
+	LatestDigest      string    `json:"latestDigest"`
+	CheckTime         time.Time `json:"checkTime"`
+	ResponseTimeMs    int       `json:"responseTimeMs"`
+	Error             string    `json:"error"`
 
 	AuthMethod     string `json:"authMethod,omitempty"`
 	AuthUsername   string `json:"authUsername,omitempty"`
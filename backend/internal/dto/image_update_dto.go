@@ -3,26 +3,43 @@ package dto
 import "time"
 
 type ImageUpdateResponse struct {
-	HasUpdate      bool      `json:"hasUpdate"`
-	UpdateType     string    `json:"updateType"`
-	CurrentVersion string    `json:"currentVersion"`
-	LatestVersion  string    `json:"latestVersion,omitempty"`
-	CurrentDigest  string    `json:"currentDigest,omitempty"`
-	LatestDigest   string    `json:"latestDigest,omitempty"`
-	CheckTime      time.Time `json:"checkTime"`
-	ResponseTimeMs int       `json:"responseTimeMs"`
-	Error          string    `json:"error,omitempty"`
+	HasUpdate      bool   `json:"hasUpdate"`
+	UpdateType     string `json:"updateType"`
+	CurrentVersion string `json:"currentVersion"`
+	LatestVersion  string `json:"latestVersion,omitempty"`
+	// Newer semver tags than CurrentVersion, newest first.
+	CandidateVersions []string  `json:"candidateVersions,omitempty"`
+	CurrentDigest     string    `json:"currentDigest,omitempty"`
+	LatestDigest      string    `json:"latestDigest,omitempty"`
+	CheckTime         time.Time `json:"checkTime"`
+	ResponseTimeMs    int       `json:"responseTimeMs"`
+	Error             string    `json:"error,omitempty"`
 
 	AuthMethod     string `json:"authMethod,omitempty"`     // "none" | "anonymous" | "credential" | "unknown"
 	AuthUsername   string `json:"authUsername,omitempty"`   // for credential method
 	AuthRegistry   string `json:"authRegistry,omitempty"`   // registry host
 	UsedCredential bool   `json:"usedCredential,omitempty"` // convenience flag
+
+	Release *ImageReleaseInfo `json:"release,omitempty"`
+}
+
+// ImageReleaseInfo describes the image an update moves to, from its OCI labels and, when
+// the source is a GitHub or Gitea repository, the matching release.
+type ImageReleaseInfo struct {
+	Source      string     `json:"source,omitempty"`
+	Version     string     `json:"version,omitempty"`
+	Revision    string     `json:"revision,omitempty"`
+	Title       string     `json:"title,omitempty"`
+	Notes       string     `json:"notes,omitempty"`
+	URL         string     `json:"url,omitempty"`
+	PublishedAt *time.Time `json:"publishedAt,omitempty"`
 }
 
 type ImageUpdateSummaryResponse struct {
 	TotalImages       int `json:"totalImages"`
 	ImagesWithUpdates int `json:"imagesWithUpdates"`
 	DigestUpdates     int `json:"digestUpdates"`
+	TagUpdates        int `json:"tagUpdates"`
 	ErrorsCount       int `json:"errorsCount"`
 }
 
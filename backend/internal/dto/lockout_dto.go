@@ -0,0 +1,12 @@
+package dto
+
+import "time"
+
+type LockoutDto struct {
+	ID            string    `json:"id"`
+	Scope         string    `json:"scope"`
+	Identifier    string    `json:"identifier"`
+	Failures      int       `json:"failures"`
+	LastFailureAt time.Time `json:"lastFailureAt"`
+	LockedUntil   time.Time `json:"lockedUntil"`
+}
@@ -0,0 +1,25 @@
+package dto
+
+import "time"
+
+type MetricPointDto struct {
+	Timestamp     time.Time `json:"timestamp"`
+	CPUPercent    float64   `json:"cpuPercent"`
+	CPUPercentMax float64   `json:"cpuPercentMax"`
+	MemoryUsed    int64     `json:"memoryUsed"`
+	MemoryUsedMax int64     `json:"memoryUsedMax"`
+	MemoryLimit   int64     `json:"memoryLimit"`
+	DiskUsed      int64     `json:"diskUsed,omitempty"`
+	DiskTotal     int64     `json:"diskTotal,omitempty"`
+	NetworkRx     int64     `json:"networkRx"`
+	NetworkTx     int64     `json:"networkTx"`
+}
+
+type MetricSeriesDto struct {
+	Scope     string           `json:"scope"`
+	Container string           `json:"container,omitempty"`
+	From      time.Time        `json:"from"`
+	To        time.Time        `json:"to"`
+	Step      int64            `json:"step"` // seconds
+	Points    []MetricPointDto `json:"points"`
+}
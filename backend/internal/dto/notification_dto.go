@@ -16,16 +16,18 @@ type NotificationSettingsResponse struct {
 }
 
 type AppriseSettingsRequest struct {
-	APIURL             string `json:"apiUrl" binding:"required"`
-	Enabled            bool   `json:"enabled"`
-	ImageUpdateTag     string `json:"imageUpdateTag"`
-	ContainerUpdateTag string `json:"containerUpdateTag"`
+	APIURL             string      `json:"apiUrl" binding:"required"`
+	Enabled            bool        `json:"enabled"`
+	ImageUpdateTag     string      `json:"imageUpdateTag"`
+	ContainerUpdateTag string      `json:"containerUpdateTag"`
+	Delivery           models.JSON `json:"delivery,omitempty"`
 }
 
 type AppriseSettingsResponse struct {
-	ID                 uint   `json:"id"`
-	APIURL             string `json:"apiUrl"`
-	Enabled            bool   `json:"enabled"`
-	ImageUpdateTag     string `json:"imageUpdateTag"`
-	ContainerUpdateTag string `json:"containerUpdateTag"`
+	ID                 uint        `json:"id"`
+	APIURL             string      `json:"apiUrl"`
+	Enabled            bool        `json:"enabled"`
+	ImageUpdateTag     string      `json:"imageUpdateTag"`
+	ContainerUpdateTag string      `json:"containerUpdateTag"`
+	Delivery           models.JSON `json:"delivery,omitempty"`
 }
@@ -0,0 +1,14 @@
+package dto
+
+import "github.com/ofkm/arcane-backend/internal/models"
+
+type NotificationSubscriptionDto struct {
+	Name             string                      `json:"name" binding:"required"`
+	Provider         models.NotificationProvider `json:"provider" binding:"required"`
+	Enabled          *bool                       `json:"enabled,omitempty"`
+	Config           models.JSON                 `json:"config,omitempty"`
+	EventTypes       []string                    `json:"eventTypes,omitempty"`
+	Severities       []string                    `json:"severities,omitempty"`
+	EnvironmentIDs   []string                    `json:"environmentIds,omitempty"`
+	ResourcePatterns []string                    `json:"resourcePatterns,omitempty"`
+}
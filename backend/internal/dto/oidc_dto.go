@@ -1,5 +1,7 @@
 package dto
 
+import "time"
+
 type OidcUserInfo struct {
 	Subject           string   `json:"sub"`
 	Name              string   `json:"name,omitempty"`
@@ -14,6 +16,8 @@ type OidcUserInfo struct {
 
 	// Raw extra claims from userinfo (not serialized back out)
 	Extra map[string]any `json:"-"`
+	// Slug of the provider the user signed in with
+	Provider string `json:"-"`
 }
 
 type OidcDiscoveryDocument struct {
@@ -43,4 +47,64 @@ type OidcStatusInfo struct {
 
 type OidcAuthUrlRequest struct {
 	RedirectUri string `json:"redirectUri"`
+	Provider    string `json:"provider,omitempty"`
+}
+
+// OidcLoginProviderDto is a provider offered on the login page. Name is empty for the
+// default provider unless one is configured.
+type OidcLoginProviderDto struct {
+	Slug string `json:"slug"`
+	Name string `json:"name"`
+}
+
+type OidcProviderDto struct {
+	ID                string    `json:"id"`
+	Slug              string    `json:"slug"`
+	Name              string    `json:"name"`
+	IssuerURL         string    `json:"issuerUrl"`
+	ClientID          string    `json:"clientId"`
+	Scopes            string    `json:"scopes"`
+	Enabled           bool      `json:"enabled"`
+	ManageDefaultRole bool      `json:"manageDefaultRole"`
+	HasClientSecret   bool      `json:"hasClientSecret"`
+	CreatedAt         time.Time `json:"createdAt"`
+}
+
+type CreateOidcProviderDto struct {
+	Slug              string `json:"slug" binding:"required"`
+	Name              string `json:"name" binding:"required"`
+	IssuerURL         string `json:"issuerUrl" binding:"required"`
+	ClientID          string `json:"clientId" binding:"required"`
+	ClientSecret      string `json:"clientSecret,omitempty"`
+	Scopes            string `json:"scopes,omitempty"`
+	Enabled           *bool  `json:"enabled,omitempty"`
+	ManageDefaultRole bool   `json:"manageDefaultRole,omitempty"`
+}
+
+// UpdateOidcProviderDto changes the fields that are set. An empty client secret keeps
+// the stored one.
+type UpdateOidcProviderDto struct {
+	Name              *string `json:"name,omitempty"`
+	IssuerURL         *string `json:"issuerUrl,omitempty"`
+	ClientID          *string `json:"clientId,omitempty"`
+	ClientSecret      *string `json:"clientSecret,omitempty"`
+	Scopes            *string `json:"scopes,omitempty"`
+	Enabled           *bool   `json:"enabled,omitempty"`
+	ManageDefaultRole *bool   `json:"manageDefaultRole,omitempty"`
+}
+
+type OidcRoleMappingDto struct {
+	ID        string    `json:"id"`
+	Provider  string    `json:"provider"`
+	Claim     string    `json:"claim"`
+	Value     string    `json:"value"`
+	RoleName  string    `json:"roleName"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type CreateOidcRoleMappingDto struct {
+	Provider string `json:"provider,omitempty"`
+	Claim    string `json:"claim" binding:"required"`
+	Value    string `json:"value" binding:"required"`
+	RoleName string `json:"roleName" binding:"required"`
 }
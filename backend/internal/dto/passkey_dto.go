@@ -0,0 +1,32 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/ofkm/arcane-backend/internal/utils/webauthn"
+)
+
+type PasskeyDto struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	AAGUID     string     `json:"aaguid,omitempty"`
+	Transports []string   `json:"transports,omitempty"`
+	BackedUp   bool       `json:"backedUp"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt"`
+}
+
+type PasskeyRegisterFinishDto struct {
+	SessionToken string                        `json:"sessionToken" binding:"required"`
+	Name         string                        `json:"name"`
+	Credential   webauthn.RegistrationResponse `json:"credential"`
+}
+
+type PasskeyLoginFinishDto struct {
+	SessionToken string                     `json:"sessionToken" binding:"required"`
+	Credential   webauthn.AssertionResponse `json:"credential"`
+}
+
+type RenamePasskeyDto struct {
+	Name string `json:"name" binding:"required"`
+}
@@ -16,11 +16,13 @@ type UpdateProjectDto struct {
 	Name           *string `json:"name,omitempty"`
 	ComposeContent *string `json:"composeContent,omitempty"`
 	EnvContent     *string `json:"envContent,omitempty"`
+	Message        *string `json:"message,omitempty"`
 }
 
 type UpdateProjectIncludeDto struct {
-	RelativePath string `json:"relativePath" binding:"required"`
-	Content      string `json:"content" binding:"required"`
+	RelativePath string  `json:"relativePath" binding:"required"`
+	Content      string  `json:"content" binding:"required"`
+	Message      *string `json:"message,omitempty"`
 }
 
 type CreateProjectReponseDto struct {
@@ -0,0 +1,54 @@
+package dto
+
+import "time"
+
+type CreateGitProjectDto struct {
+	Name          string  `json:"name" binding:"required"`
+	RepositoryURL string  `json:"repositoryUrl" binding:"required"`
+	Ref           string  `json:"ref,omitempty"`
+	SubPath       string  `json:"subPath,omitempty"`
+	AuthType      string  `json:"authType,omitempty"`
+	Username      *string `json:"username,omitempty"`
+	// Access token for "token" auth or a private key for "ssh" auth.
+	Credential   *string `json:"credential,omitempty"`
+	AutoSync     *bool   `json:"autoSync,omitempty"`
+	SyncInterval *int    `json:"syncInterval,omitempty"`
+	AutoDeploy   *bool   `json:"autoDeploy,omitempty"`
+	Deploy       bool    `json:"deploy,omitempty"`
+}
+
+type UpdateGitSourceDto struct {
+	Ref          *string `json:"ref,omitempty"`
+	AuthType     *string `json:"authType,omitempty"`
+	Username     *string `json:"username,omitempty"`
+	Credential   *string `json:"credential,omitempty"`
+	AutoSync     *bool   `json:"autoSync,omitempty"`
+	SyncInterval *int    `json:"syncInterval,omitempty"`
+	AutoDeploy   *bool   `json:"autoDeploy,omitempty"`
+}
+
+type GitSourceDto struct {
+	ID            string     `json:"id"`
+	ProjectID     string     `json:"projectId"`
+	RepositoryURL string     `json:"repositoryUrl"`
+	Ref           string     `json:"ref"`
+	SubPath       string     `json:"subPath"`
+	AuthType      string     `json:"authType"`
+	Username      *string    `json:"username,omitempty"`
+	HasCredential bool       `json:"hasCredential"`
+	AutoSync      bool       `json:"autoSync"`
+	SyncInterval  int        `json:"syncInterval"`
+	AutoDeploy    bool       `json:"autoDeploy"`
+	LastCommit    *string    `json:"lastCommit,omitempty"`
+	LastSyncAt    *time.Time `json:"lastSyncAt,omitempty"`
+	LastSyncError *string    `json:"lastSyncError,omitempty"`
+}
+
+type GitSyncResultDto struct {
+	PreviousCommit string   `json:"previousCommit"`
+	Commit         string   `json:"commit"`
+	Changed        bool     `json:"changed"`
+	ChangedFiles   []string `json:"changedFiles,omitempty"`
+	Diff           string   `json:"diff,omitempty"`
+	Deployed       bool     `json:"deployed"`
+}
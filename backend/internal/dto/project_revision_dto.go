@@ -0,0 +1,31 @@
+package dto
+
+import "time"
+
+// ProjectRevisionDto lists a revision without its file contents.
+type ProjectRevisionDto struct {
+	ID        string    `json:"id"`
+	ProjectID string    `json:"projectId"`
+	Revision  int       `json:"revision"`
+	Message   string    `json:"message"`
+	UserID    *string   `json:"userId,omitempty"`
+	Username  *string   `json:"username,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type ProjectRevisionFileDiffDto struct {
+	File string `json:"file"`
+	Diff string `json:"diff"`
+}
+
+// ProjectRevisionDiffDto holds a unified diff per file that differs between two
+// revisions. Unchanged files are left out.
+type ProjectRevisionDiffDto struct {
+	FromRevision int                          `json:"fromRevision"`
+	ToRevision   int                          `json:"toRevision"`
+	Files        []ProjectRevisionFileDiffDto `json:"files"`
+}
+
+type RestoreProjectRevisionDto struct {
+	Redeploy bool `json:"redeploy,omitempty"`
+}
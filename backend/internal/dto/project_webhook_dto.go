@@ -0,0 +1,30 @@
+package dto
+
+import "time"
+
+type CreateProjectWebhookDto struct {
+	Name     string  `json:"name" binding:"required"`
+	Provider string  `json:"provider,omitempty"`
+	Secret   *string `json:"secret,omitempty"`
+	Enabled  *bool   `json:"enabled,omitempty"`
+}
+
+type ProjectWebhookDto struct {
+	ID              string     `json:"id"`
+	ProjectID       string     `json:"projectId"`
+	Name            string     `json:"name"`
+	Provider        string     `json:"provider"`
+	TokenPrefix     string     `json:"tokenPrefix"`
+	HasSecret       bool       `json:"hasSecret"`
+	Enabled         bool       `json:"enabled"`
+	LastTriggeredAt *time.Time `json:"lastTriggeredAt,omitempty"`
+	LastStatus      *string    `json:"lastStatus,omitempty"`
+	LastError       *string    `json:"lastError,omitempty"`
+	CreatedAt       time.Time  `json:"createdAt"`
+}
+
+// CreatedProjectWebhookDto is only returned once, when the webhook is created.
+type CreatedProjectWebhookDto struct {
+	ProjectWebhookDto
+	URL string `json:"url"`
+}
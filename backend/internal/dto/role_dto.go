@@ -0,0 +1,37 @@
+package dto
+
+import "time"
+
+type RoleDto struct {
+	ID          string   `json:"id,omitempty"`
+	Name        string   `json:"name"`
+	Description *string  `json:"description,omitempty"`
+	Permissions []string `json:"permissions"`
+	BuiltIn     bool     `json:"builtIn"`
+}
+
+type CreateRoleDto struct {
+	Name        string   `json:"name" binding:"required"`
+	Description *string  `json:"description,omitempty"`
+	Permissions []string `json:"permissions" binding:"required"`
+}
+
+type UpdateRoleDto struct {
+	Description *string  `json:"description,omitempty"`
+	Permissions []string `json:"permissions,omitempty"`
+}
+
+type RoleBindingDto struct {
+	ID            string    `json:"id"`
+	UserID        string    `json:"userId"`
+	RoleName      string    `json:"roleName"`
+	EnvironmentID *string   `json:"environmentId,omitempty"`
+	ProjectID     *string   `json:"projectId,omitempty"`
+	CreatedAt     time.Time `json:"createdAt"`
+}
+
+type CreateRoleBindingDto struct {
+	RoleName      string  `json:"roleName" binding:"required"`
+	EnvironmentID *string `json:"environmentId,omitempty"`
+	ProjectID     *string `json:"projectId,omitempty"`
+}
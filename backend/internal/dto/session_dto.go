@@ -0,0 +1,14 @@
+package dto
+
+import "time"
+
+type SessionDto struct {
+	ID         string    `json:"id"`
+	Method     string    `json:"method"`
+	UserAgent  string    `json:"userAgent"`
+	IPAddress  string    `json:"ipAddress"`
+	LastSeenAt time.Time `json:"lastSeenAt"`
+	ExpiresAt  time.Time `json:"expiresAt"`
+	CreatedAt  time.Time `json:"createdAt"`
+	Current    bool      `json:"current"`
+}
@@ -16,22 +16,52 @@ type UpdateSettingsDto struct {
 	DiskUsagePath              *string `json:"diskUsagePath,omitempty"`
 	AutoUpdate                 *string `json:"autoUpdate,omitempty"`
 	AutoUpdateInterval         *string `json:"autoUpdateInterval,omitempty"`
+	AutoUpdateRollback         *string `json:"autoUpdateRollback,omitempty"`
+	AutoUpdateHealthTimeout    *string `json:"autoUpdateHealthTimeout,omitempty"`
+	AutoUpdateGracePeriod      *string `json:"autoUpdateGracePeriod,omitempty"`
+	AutoUpdatePolicy           *string `json:"autoUpdatePolicy,omitempty"`
+	AutoUpdateWindow           *string `json:"autoUpdateWindow,omitempty"`
+	AutoUpdateWindowTimezone   *string `json:"autoUpdateWindowTimezone,omitempty"`
+	AutoUpdateWindowDuration   *string `json:"autoUpdateWindowDuration,omitempty"`
 	PollingEnabled             *string `json:"pollingEnabled,omitempty"`
 	PollingInterval            *string `json:"pollingInterval,omitempty"`
 	EnvironmentHealthInterval  *string `json:"environmentHealthInterval,omitempty"`
+	MetricsEnabled             *string `json:"metricsEnabled,omitempty"`
+	MetricsInterval            *string `json:"metricsInterval,omitempty"`
+	MetricsRetentionDays       *string `json:"metricsRetentionDays,omitempty"`
+	EventSinkSyslogEnabled     *string `json:"eventSinkSyslogEnabled,omitempty"`
+	EventSinkSyslogProtocol    *string `json:"eventSinkSyslogProtocol,omitempty" binding:"omitempty,oneof=udp tcp tls"`
+	EventSinkSyslogAddress     *string `json:"eventSinkSyslogAddress,omitempty"`
+	EventSinkLokiEnabled       *string `json:"eventSinkLokiEnabled,omitempty"`
+	EventSinkLokiURL           *string `json:"eventSinkLokiUrl,omitempty"`
+	EventSinkLokiLabels        *string `json:"eventSinkLokiLabels,omitempty"`
+	EventSinkLokiTenantID      *string `json:"eventSinkLokiTenantId,omitempty"`
+	EventSinkFileEnabled       *string `json:"eventSinkFileEnabled,omitempty"`
+	EventSinkFilePath          *string `json:"eventSinkFilePath,omitempty"`
+	EventSinkFileMaxSize       *string `json:"eventSinkFileMaxSize,omitempty"`
+	EventSinkFileMaxBackups    *string `json:"eventSinkFileMaxBackups,omitempty"`
 	PruneMode                  *string `json:"dockerPruneMode,omitempty" binding:"omitempty,oneof=all dangling"`
 	MaxImageUploadSize         *string `json:"maxImageUploadSize,omitempty"`
 	BaseServerURL              *string `json:"baseServerUrl,omitempty"`
 	EnableGravatar             *string `json:"enableGravatar,omitempty"`
 	DefaultShell               *string `json:"defaultShell,omitempty"`
 	DockerHost                 *string `json:"dockerHost,omitempty"`
+	BackupDirectory            *string `json:"backupDirectory,omitempty"`
+	BackupAllowedPaths         *string `json:"backupAllowedPaths,omitempty"`
+	BackupHelperImage          *string `json:"backupHelperImage,omitempty"`
 	AccentColor                *string `json:"accentColor,omitempty"`
 	AuthLocalEnabled           *string `json:"authLocalEnabled,omitempty"`
 	AuthOidcEnabled            *string `json:"authOidcEnabled,omitempty"`
 	AuthOidcMergeAccounts      *string `json:"authOidcMergeAccounts,omitempty"`
+	AuthOidcManageDefaultRole  *string `json:"authOidcManageDefaultRole,omitempty"`
 	AuthSessionTimeout         *string `json:"authSessionTimeout,omitempty"`
 	AuthPasswordPolicy         *string `json:"authPasswordPolicy,omitempty"`
+	AuthRequireTotp            *string `json:"authRequireTotp,omitempty"`
+	AuthPasskeysEnabled        *string `json:"authPasskeysEnabled,omitempty"`
+	AuthLockoutThreshold       *string `json:"authLockoutThreshold,omitempty"`
+	AuthLockoutDuration        *string `json:"authLockoutDuration,omitempty"`
 	AuthOidcConfig             *string `json:"authOidcConfig,omitempty"`
+	AuditLogRetentionDays      *string `json:"auditLogRetentionDays,omitempty"`
 	OnboardingCompleted        *string `json:"onboardingCompleted,omitempty"`
 	OnboardingSteps            *string `json:"onboardingSteps,omitempty"`
 	MobileNavigationMode       *string `json:"mobileNavigationMode,omitempty"`
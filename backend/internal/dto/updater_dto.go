@@ -13,6 +13,7 @@ type UpdaterItem struct {
 	OldImages     map[string]string `json:"oldImages,omitempty"`
 	NewImages     map[string]string `json:"newImages,omitempty"`
 	UpdateApplied bool              `json:"updateApplied,omitempty"`
+	Details       map[string]any    `json:"details,omitempty"`
 }
 
 type UpdaterRunResult struct {
@@ -55,3 +56,12 @@ type AutoUpdateResourceResult struct {
 	Error           string                 `json:"error,omitempty"`
 	Details         map[string]interface{} `json:"details,omitempty"`
 }
+
+type UpsertUpdatePolicyDto struct {
+	ScopeType      string `json:"scopeType" binding:"required"` // "container" | "project"
+	ScopeName      string `json:"scopeName" binding:"required"`
+	Policy         string `json:"policy,omitempty"` // "pin" | "digest" | "patch" | "minor" | "major"
+	WindowCron     string `json:"windowCron,omitempty"`
+	WindowTimezone string `json:"windowTimezone,omitempty"`
+	WindowDuration int    `json:"windowDuration,omitempty"` // minutes
+}
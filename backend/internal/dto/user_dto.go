@@ -28,4 +28,9 @@ type UserResponseDto struct {
 	CreatedAt              string   `json:"createdAt,omitempty"`
 	UpdatedAt              string   `json:"updatedAt,omitempty"`
 	RequiresPasswordChange bool     `json:"requiresPasswordChange"`
+	TotpEnabled            bool     `json:"totpEnabled"`
+
+	// Populated for /auth/me only
+	RoleBindings []RoleBindingDto    `json:"roleBindings,omitempty"`
+	Permissions  map[string][]string `json:"permissions,omitempty"`
 }
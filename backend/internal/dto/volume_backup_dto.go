@@ -0,0 +1,77 @@
+package dto
+
+import "time"
+
+type BackupTargetDto struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Type        string    `json:"type"`
+	Path        string    `json:"path,omitempty"`
+	Endpoint    string    `json:"endpoint,omitempty"`
+	Region      string    `json:"region,omitempty"`
+	Bucket      string    `json:"bucket,omitempty"`
+	Prefix      string    `json:"prefix,omitempty"`
+	AccessKeyID string    `json:"accessKeyId,omitempty"`
+	HasSecret   bool      `json:"hasSecret"`
+	PathStyle   bool      `json:"pathStyle"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+type CreateBackupTargetDto struct {
+	Name            string  `json:"name" binding:"required"`
+	Type            string  `json:"type" binding:"required"`
+	Path            string  `json:"path,omitempty"`
+	Endpoint        string  `json:"endpoint,omitempty"`
+	Region          string  `json:"region,omitempty"`
+	Bucket          string  `json:"bucket,omitempty"`
+	Prefix          string  `json:"prefix,omitempty"`
+	AccessKeyID     string  `json:"accessKeyId,omitempty"`
+	SecretAccessKey *string `json:"secretAccessKey,omitempty"`
+	PathStyle       bool    `json:"pathStyle,omitempty"`
+}
+
+type UpdateBackupTargetDto struct {
+	Name            *string `json:"name,omitempty"`
+	Path            *string `json:"path,omitempty"`
+	Endpoint        *string `json:"endpoint,omitempty"`
+	Region          *string `json:"region,omitempty"`
+	Bucket          *string `json:"bucket,omitempty"`
+	Prefix          *string `json:"prefix,omitempty"`
+	AccessKeyID     *string `json:"accessKeyId,omitempty"`
+	SecretAccessKey *string `json:"secretAccessKey,omitempty"`
+	PathStyle       *bool   `json:"pathStyle,omitempty"`
+}
+
+type CreateVolumeBackupScheduleDto struct {
+	VolumeName     string  `json:"volumeName" binding:"required"`
+	CronExpression string  `json:"cronExpression" binding:"required"`
+	TargetID       *string `json:"targetId,omitempty"`
+	RetentionCount int     `json:"retentionCount,omitempty"`
+	RetentionDays  int     `json:"retentionDays,omitempty"`
+	StopContainers bool    `json:"stopContainers,omitempty"`
+	Enabled        *bool   `json:"enabled,omitempty"`
+}
+
+type UpdateVolumeBackupScheduleDto struct {
+	CronExpression *string `json:"cronExpression,omitempty"`
+	// An empty string switches the schedule back to the default local target.
+	TargetID       *string `json:"targetId,omitempty"`
+	RetentionCount *int    `json:"retentionCount,omitempty"`
+	RetentionDays  *int    `json:"retentionDays,omitempty"`
+	StopContainers *bool   `json:"stopContainers,omitempty"`
+	Enabled        *bool   `json:"enabled,omitempty"`
+}
+
+type CreateVolumeBackupDto struct {
+	TargetID       *string `json:"targetId,omitempty"`
+	StopContainers bool    `json:"stopContainers,omitempty"`
+}
+
+// RestoreVolumeBackupDto controls how an archive is written back. Mode "overwrite" (the
+// default) empties the volume in place; "recreate" removes and recreates it, which is only
+// possible while no container uses it. TargetVolume restores into a different volume,
+// creating it when missing.
+type RestoreVolumeBackupDto struct {
+	Mode         string `json:"mode,omitempty"`
+	TargetVolume string `json:"targetVolume,omitempty"`
+}
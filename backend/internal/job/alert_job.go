@@ -0,0 +1,51 @@
+package job
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/go-co-op/gocron/v2"
+	"github.com/ofkm/arcane-backend/internal/services"
+)
+
+const AlertEvaluationJobName = "AlertEvaluation"
+
+func RegisterAlertEvaluationJob(
+	ctx context.Context,
+	scheduler *Scheduler,
+	alertService *services.AlertService,
+) error {
+	slog.InfoContext(ctx, "Registering alert evaluation job", "jobName", AlertEvaluationJobName)
+
+	taskFunc := func(jobCtx context.Context) error {
+		if err := alertService.Evaluate(jobCtx, time.Now()); err != nil {
+			slog.ErrorContext(jobCtx, "Alert evaluation failed",
+				"jobName", AlertEvaluationJobName,
+				slog.Any("error", err))
+			return err
+		}
+		return nil
+	}
+
+	// Rule durations and cooldowns are expressed in minutes, so evaluate once a minute
+	jobDefinition := gocron.DurationJob(time.Minute)
+
+	err := scheduler.RegisterJob(
+		ctx,
+		AlertEvaluationJobName,
+		jobDefinition,
+		taskFunc,
+		false,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to register alert evaluation job %q: %w", AlertEvaluationJobName, err)
+	}
+
+	slog.InfoContext(ctx, "Alert evaluation job registered successfully",
+		"jobName", AlertEvaluationJobName,
+		"interval", "1m")
+	return nil
+}
@@ -0,0 +1,71 @@
+package job
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/go-co-op/gocron/v2"
+	"github.com/ofkm/arcane-backend/internal/services"
+)
+
+const AuditLogCleanupJobName = "AuditLogCleanup"
+
+const defaultAuditLogRetentionDays = 365
+
+// RegisterAuditLogCleanupJob removes audit entries older than the auditLogRetentionDays
+// setting. It is kept apart from the event cleanup job: audit history is retained for
+// compliance far longer than events, and a retention of 0 keeps it forever.
+func RegisterAuditLogCleanupJob(
+	ctx context.Context,
+	scheduler *Scheduler,
+	auditService *services.AuditService,
+	settingsService *services.SettingsService,
+) error {
+	slog.InfoContext(ctx, "Registering audit log cleanup job", "jobName", AuditLogCleanupJobName)
+
+	taskFunc := func(jobCtx context.Context) error {
+		retentionDays := settingsService.GetIntSetting(jobCtx, "auditLogRetentionDays", defaultAuditLogRetentionDays)
+		if retentionDays <= 0 {
+			slog.DebugContext(jobCtx, "Audit log retention disabled; keeping all entries", "jobName", AuditLogCleanupJobName)
+			return nil
+		}
+		retentionDays = max(retentionDays, services.MinAuditLogRetentionDays)
+
+		olderThan := time.Duration(retentionDays) * 24 * time.Hour
+		deleted, err := auditService.DeleteAuditLogsOlderThan(jobCtx, olderThan)
+		if err != nil {
+			slog.ErrorContext(jobCtx, "Failed to delete old audit logs",
+				"jobName", AuditLogCleanupJobName,
+				"retentionDays", retentionDays,
+				slog.Any("error", err))
+			return err
+		}
+
+		slog.InfoContext(jobCtx, "Audit log cleanup job completed successfully",
+			"jobName", AuditLogCleanupJobName,
+			"retentionDays", retentionDays,
+			"deleted", deleted)
+		return nil
+	}
+
+	jobDefinition := gocron.DurationJob(24 * time.Hour)
+
+	err := scheduler.RegisterJob(
+		ctx,
+		AuditLogCleanupJobName,
+		jobDefinition,
+		taskFunc,
+		false,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to register audit log cleanup job %q: %w", AuditLogCleanupJobName, err)
+	}
+
+	slog.InfoContext(ctx, "Audit log cleanup job registered successfully",
+		"jobName", AuditLogCleanupJobName,
+		"interval", "24h")
+	return nil
+}
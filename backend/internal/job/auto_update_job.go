@@ -3,16 +3,26 @@ package job
 import (
 	"context"
 	"log/slog"
+	"sync"
 	"time"
 
 	"github.com/go-co-op/gocron/v2"
 	"github.com/ofkm/arcane-backend/internal/services"
+	"github.com/ofkm/arcane-backend/internal/utils/updatepolicy"
 )
 
+// autoUpdateTick is how often the job checks whether a run is due. It is short so that
+// container and project maintenance windows are noticed soon after they open.
+const autoUpdateTick = 5 * time.Minute
+
 type AutoUpdateJob struct {
 	updaterService  *services.UpdaterService
 	settingsService *services.SettingsService
 	scheduler       *Scheduler
+
+	mu       sync.Mutex
+	interval time.Duration
+	lastRun  time.Time
 }
 
 func NewAutoUpdateJob(scheduler *Scheduler, updaterService *services.UpdaterService, settingsService *services.SettingsService) *AutoUpdateJob {
@@ -41,13 +51,17 @@ func (j *AutoUpdateJob) Register(ctx context.Context) error {
 			"effective_interval", "60m")
 		interval = 60 * time.Minute
 	}
+	j.mu.Lock()
+	j.interval = interval
+	j.lastRun = time.Now()
+	j.mu.Unlock()
 
-	slog.InfoContext(ctx, "registering auto-update job", "interval", interval.String())
+	slog.InfoContext(ctx, "registering auto-update job", "interval", interval.String(), "tick", autoUpdateTick.String())
 
 	// ensure single instance
 	j.scheduler.RemoveJobByName("auto-update")
 
-	jobDefinition := gocron.DurationJob(interval)
+	jobDefinition := gocron.DurationJob(autoUpdateTick)
 	return j.scheduler.RegisterJob(
 		ctx,
 		"auto-update",
@@ -57,9 +71,10 @@ func (j *AutoUpdateJob) Register(ctx context.Context) error {
 	)
 }
 
+// Execute runs the updater once the configured interval has passed, or earlier when a
+// container's resolved maintenance window has opened since the last run. Each container
+// is then only updated inside its own window.
 func (j *AutoUpdateJob) Execute(ctx context.Context) error {
-	slog.InfoContext(ctx, "auto-update run started")
-
 	enabled := j.settingsService.GetBoolSetting(ctx, "autoUpdate", false)
 	pollingEnabled := j.settingsService.GetBoolSetting(ctx, "pollingEnabled", true)
 	if !enabled || !pollingEnabled {
@@ -68,6 +83,28 @@ func (j *AutoUpdateJob) Execute(ctx context.Context) error {
 		return nil
 	}
 
+	resolved, err := j.updaterService.RunningUpdatePolicies(ctx)
+	if err != nil {
+		slog.WarnContext(ctx, "could not resolve maintenance windows; only interval runs apply", "err", err)
+	}
+	windows := make([]updatepolicy.Window, 0, len(resolved))
+	for _, r := range resolved {
+		windows = append(windows, r.Window)
+	}
+
+	now := time.Now()
+	j.mu.Lock()
+	due := autoUpdateDue(now, j.lastRun, j.interval, windows)
+	if due {
+		j.lastRun = now
+	}
+	j.mu.Unlock()
+	if !due {
+		slog.DebugContext(ctx, "auto-update not due; skipping run")
+		return nil
+	}
+
+	slog.InfoContext(ctx, "auto-update run started")
 	result, err := j.updaterService.ApplyPending(ctx, false)
 	if err != nil {
 		slog.ErrorContext(ctx, "auto-update run failed", "err", err)
@@ -100,7 +137,29 @@ func (j *AutoUpdateJob) Reschedule(ctx context.Context) error {
 	if interval < 5*time.Minute {
 		interval = 60 * time.Minute
 	}
+	j.mu.Lock()
+	j.interval = interval
+	if j.lastRun.IsZero() {
+		j.lastRun = time.Now()
+	}
+	j.mu.Unlock()
 	slog.InfoContext(ctx, "auto-update settings changed; rescheduling", "interval", interval.String())
 
-	return j.scheduler.RescheduleDurationJobByName(ctx, "auto-update", interval, j.Execute, false)
+	return j.scheduler.RescheduleDurationJobByName(ctx, "auto-update", autoUpdateTick, j.Execute, false)
+}
+
+// autoUpdateDue reports whether a run should start at now: either the interval has
+// passed since lastRun, or one of the maintenance windows opened after lastRun and is
+// still open. Zero windows only take part in interval runs.
+func autoUpdateDue(now, lastRun time.Time, interval time.Duration, windows []updatepolicy.Window) bool {
+	if now.Sub(lastRun) >= interval {
+		return true
+	}
+	for _, w := range windows {
+		openedAt, open, err := w.OpenedAt(now)
+		if err == nil && open && openedAt.After(lastRun) {
+			return true
+		}
+	}
+	return false
 }
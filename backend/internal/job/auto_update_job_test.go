@@ -0,0 +1,25 @@
+package job
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ofkm/arcane-backend/internal/utils/updatepolicy"
+)
+
+func TestAutoUpdateDue_ContainerWindowOpenWhileGlobalWindowClosed(t *testing.T) {
+	global := updatepolicy.Window{Cron: "0 22 * * *", Timezone: "UTC", Duration: time.Hour}
+	container := updatepolicy.Window{Cron: "0 2 * * *", Timezone: "UTC", Duration: time.Hour}
+	lastRun := time.Date(2025, 6, 1, 1, 0, 0, 0, time.UTC)
+	now := time.Date(2025, 6, 1, 2, 5, 0, 0, time.UTC)
+
+	require.False(t, autoUpdateDue(now, lastRun, 24*time.Hour, []updatepolicy.Window{global, {}}))
+	require.True(t, autoUpdateDue(now, lastRun, 24*time.Hour, []updatepolicy.Window{global, container}))
+
+	// The window already had its run.
+	require.False(t, autoUpdateDue(now.Add(5*time.Minute), now, 24*time.Hour, []updatepolicy.Window{global, container}))
+	// Interval runs still happen without any open window.
+	require.True(t, autoUpdateDue(lastRun.Add(24*time.Hour), lastRun, 24*time.Hour, []updatepolicy.Window{global}))
+}
@@ -0,0 +1,35 @@
+package job
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/go-co-op/gocron/v2"
+	"github.com/ofkm/arcane-backend/internal/services"
+)
+
+const GitSyncJobName = "GitSync"
+
+// RegisterGitSyncJob checks every minute for git-backed projects whose own sync interval
+// has elapsed and pulls them.
+func RegisterGitSyncJob(ctx context.Context, scheduler *Scheduler, projectGitService *services.ProjectGitService) error {
+	slog.InfoContext(ctx, "Registering git sync job", "jobName", GitSyncJobName)
+
+	taskFunc := func(jobCtx context.Context) error {
+		return projectGitService.SyncDueProjects(jobCtx)
+	}
+
+	err := scheduler.RegisterJob(
+		ctx,
+		GitSyncJobName,
+		gocron.DurationJob(time.Minute),
+		taskFunc,
+		false,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to register git sync job %q: %w", GitSyncJobName, err)
+	}
+	return nil
+}
@@ -8,10 +8,12 @@ import (
 
 	"github.com/go-co-op/gocron/v2"
 	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
 )
 
 type Scheduler struct {
 	scheduler gocron.Scheduler
+	metrics   *jobMetrics
 }
 
 func NewScheduler() (*Scheduler, error) {
@@ -19,7 +21,12 @@ func NewScheduler() (*Scheduler, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to create new gocron scheduler: %w", err)
 	}
-	return &Scheduler{scheduler: s}, nil
+	return &Scheduler{scheduler: s, metrics: newJobMetrics()}, nil
+}
+
+// Collector exposes job execution counts and durations for the Prometheus exporter.
+func (s *Scheduler) Collector() prometheus.Collector {
+	return s.metrics
 }
 
 func (s *Scheduler) Run(ctx context.Context) error {
@@ -64,7 +71,10 @@ func (s *Scheduler) RegisterJob(
 	}
 
 	task := gocron.NewTask(func() {
-		if err := taskFunc(ctx); err != nil {
+		started := time.Now()
+		err := taskFunc(ctx)
+		s.metrics.observe(name, started, err)
+		if err != nil {
 			slog.ErrorContext(ctx, "Error executing task function", slog.String("name", name), slog.Any("error", err))
 		}
 	})
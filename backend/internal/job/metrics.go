@@ -0,0 +1,54 @@
+package job
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// jobMetrics records every task run by the scheduler for the Prometheus exporter.
+type jobMetrics struct {
+	runs        *prometheus.CounterVec
+	duration    *prometheus.HistogramVec
+	lastSuccess *prometheus.GaugeVec
+}
+
+func newJobMetrics() *jobMetrics {
+	return &jobMetrics{
+		runs: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "arcane_job_runs_total",
+			Help: "Scheduled job executions by job and result.",
+		}, []string{"job", "result"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "arcane_job_duration_seconds",
+			Help:    "Duration of scheduled job executions.",
+			Buckets: []float64{0.05, 0.25, 1, 5, 15, 60, 300, 900},
+		}, []string{"job"}),
+		lastSuccess: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "arcane_job_last_success_timestamp_seconds",
+			Help: "Unix time of the last successful execution of a scheduled job.",
+		}, []string{"job"}),
+	}
+}
+
+func (m *jobMetrics) observe(name string, started time.Time, err error) {
+	m.duration.WithLabelValues(name).Observe(time.Since(started).Seconds())
+	if err != nil {
+		m.runs.WithLabelValues(name, "failure").Inc()
+		return
+	}
+	m.runs.WithLabelValues(name, "success").Inc()
+	m.lastSuccess.WithLabelValues(name).SetToCurrentTime()
+}
+
+func (m *jobMetrics) Describe(ch chan<- *prometheus.Desc) {
+	m.runs.Describe(ch)
+	m.duration.Describe(ch)
+	m.lastSuccess.Describe(ch)
+}
+
+func (m *jobMetrics) Collect(ch chan<- prometheus.Metric) {
+	m.runs.Collect(ch)
+	m.duration.Collect(ch)
+	m.lastSuccess.Collect(ch)
+}
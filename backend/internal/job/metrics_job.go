@@ -0,0 +1,80 @@
+package job
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/go-co-op/gocron/v2"
+	"github.com/ofkm/arcane-backend/internal/services"
+)
+
+const (
+	MetricsCollectorJobName  = "metrics-collector"
+	MetricsCompactionJobName = "metrics-compaction"
+
+	metricsCompactionInterval = 15 * time.Minute
+)
+
+type MetricsJob struct {
+	metricsService *services.MetricsService
+	scheduler      *Scheduler
+}
+
+func NewMetricsJob(scheduler *Scheduler, metricsService *services.MetricsService) *MetricsJob {
+	return &MetricsJob{
+		metricsService: metricsService,
+		scheduler:      scheduler,
+	}
+}
+
+func (j *MetricsJob) Register(ctx context.Context) error {
+	interval := j.metricsService.Interval(ctx)
+
+	slog.InfoContext(ctx, "registering metrics collector job", "interval", interval.String())
+
+	j.scheduler.RemoveJobByName(MetricsCollectorJobName)
+	if err := j.scheduler.RegisterJob(ctx, MetricsCollectorJobName, gocron.DurationJob(interval), j.Execute, true); err != nil {
+		return fmt.Errorf("failed to register metrics collector job %q: %w", MetricsCollectorJobName, err)
+	}
+
+	j.scheduler.RemoveJobByName(MetricsCompactionJobName)
+	if err := j.scheduler.RegisterJob(ctx, MetricsCompactionJobName, gocron.DurationJob(metricsCompactionInterval), j.Compact, false); err != nil {
+		return fmt.Errorf("failed to register metrics compaction job %q: %w", MetricsCompactionJobName, err)
+	}
+
+	return nil
+}
+
+func (j *MetricsJob) Reschedule(ctx context.Context) error {
+	interval := j.metricsService.Interval(ctx)
+
+	slog.InfoContext(ctx, "metrics settings changed; rescheduling", "interval", interval.String())
+
+	return j.scheduler.RescheduleDurationJobByName(ctx, MetricsCollectorJobName, interval, j.Execute, false)
+}
+
+func (j *MetricsJob) Execute(ctx context.Context) error {
+	if !j.metricsService.Enabled(ctx) {
+		return nil
+	}
+
+	count, err := j.metricsService.Collect(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "metrics collection failed", "error", err)
+		return err
+	}
+
+	slog.DebugContext(ctx, "metrics collected", "samples", count)
+	return nil
+}
+
+// Compact still runs while collection is disabled so existing history ages out.
+func (j *MetricsJob) Compact(ctx context.Context) error {
+	if err := j.metricsService.Compact(ctx, time.Now()); err != nil {
+		slog.ErrorContext(ctx, "metrics compaction failed", "error", err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package job
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/go-co-op/gocron/v2"
+	"github.com/ofkm/arcane-backend/internal/services"
+)
+
+const NotificationDigestJobName = "NotificationDigest"
+
+func RegisterNotificationDigestJob(
+	ctx context.Context,
+	scheduler *Scheduler,
+	notificationService *services.NotificationService,
+) error {
+	slog.InfoContext(ctx, "Registering notification digest job", "jobName", NotificationDigestJobName)
+
+	taskFunc := func(jobCtx context.Context) error {
+		if err := notificationService.FlushNotificationQueue(jobCtx); err != nil {
+			slog.ErrorContext(jobCtx, "Notification digest failed",
+				"jobName", NotificationDigestJobName,
+				slog.Any("error", err))
+			return err
+		}
+		return nil
+	}
+
+	// Digest times and quiet hours are set to the minute, so check the queue once a minute
+	jobDefinition := gocron.DurationJob(time.Minute)
+
+	err := scheduler.RegisterJob(
+		ctx,
+		NotificationDigestJobName,
+		jobDefinition,
+		taskFunc,
+		false,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to register notification digest job %q: %w", NotificationDigestJobName, err)
+	}
+
+	slog.InfoContext(ctx, "Notification digest job registered successfully",
+		"jobName", NotificationDigestJobName,
+		"interval", "1m")
+	return nil
+}
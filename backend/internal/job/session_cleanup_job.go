@@ -0,0 +1,44 @@
+package job
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/go-co-op/gocron/v2"
+	"github.com/ofkm/arcane-backend/internal/services"
+)
+
+const SessionCleanupJobName = "SessionCleanup"
+
+// RegisterSessionCleanupJob removes sign-in sessions that have long since expired or
+// been revoked, along with failed-login counters that no longer apply. Active sessions
+// and lockouts are never touched.
+func RegisterSessionCleanupJob(ctx context.Context, scheduler *Scheduler, sessionService *services.SessionService, throttleService *services.LoginThrottleService) error {
+	slog.InfoContext(ctx, "Registering session cleanup job", "jobName", SessionCleanupJobName)
+
+	taskFunc := func(jobCtx context.Context) error {
+		deleted, err := sessionService.DeleteInactiveSessions(jobCtx)
+		if err != nil {
+			slog.ErrorContext(jobCtx, "Failed to delete inactive sessions", "jobName", SessionCleanupJobName, slog.Any("error", err))
+			return err
+		}
+
+		throttles, err := throttleService.DeleteExpired(jobCtx)
+		if err != nil {
+			slog.ErrorContext(jobCtx, "Failed to delete expired login throttles", "jobName", SessionCleanupJobName, slog.Any("error", err))
+			return err
+		}
+
+		slog.InfoContext(jobCtx, "Session cleanup job completed successfully", "jobName", SessionCleanupJobName, "deleted", deleted, "throttlesDeleted", throttles)
+		return nil
+	}
+
+	if err := scheduler.RegisterJob(ctx, SessionCleanupJobName, gocron.DurationJob(24*time.Hour), taskFunc, false); err != nil {
+		return fmt.Errorf("failed to register session cleanup job %q: %w", SessionCleanupJobName, err)
+	}
+
+	slog.InfoContext(ctx, "Session cleanup job registered successfully", "jobName", SessionCleanupJobName, "interval", "24h")
+	return nil
+}
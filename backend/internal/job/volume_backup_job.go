@@ -0,0 +1,35 @@
+package job
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/go-co-op/gocron/v2"
+	"github.com/ofkm/arcane-backend/internal/services"
+)
+
+const VolumeBackupJobName = "VolumeBackup"
+
+// RegisterVolumeBackupJob checks every minute for backup schedules whose cron expression
+// has come due and runs them.
+func RegisterVolumeBackupJob(ctx context.Context, scheduler *Scheduler, volumeBackupService *services.VolumeBackupService) error {
+	slog.InfoContext(ctx, "Registering volume backup job", "jobName", VolumeBackupJobName)
+
+	taskFunc := func(jobCtx context.Context) error {
+		return volumeBackupService.RunDueSchedules(jobCtx)
+	}
+
+	err := scheduler.RegisterJob(
+		ctx,
+		VolumeBackupJobName,
+		gocron.DurationJob(time.Minute),
+		taskFunc,
+		false,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to register volume backup job %q: %w", VolumeBackupJobName, err)
+	}
+	return nil
+}
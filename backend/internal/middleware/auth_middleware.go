@@ -16,25 +16,31 @@ import (
 const (
 	headerAgentBootstrap = "X-Arcane-Agent-Bootstrap"
 	headerAgentToken     = "X-Arcane-Agent-Token" // #nosec G101: header name, not a credential
+	headerApiKey         = "X-API-Key"
 	agentPairingPrefix   = "/api/environments/0/agent/pair"
 )
 
 type AuthOptions struct {
-	AdminRequired   bool
-	SuccessOptional bool
+	AdminRequired    bool
+	SuccessOptional  bool
+	ApiTokenRequired bool
 }
 
 type AuthMiddleware struct {
-	authService *services.AuthService
-	cfg         *config.Config
-	options     AuthOptions
+	authService     *services.AuthService
+	roleService     *services.RoleService
+	apiTokenService *services.ApiTokenService
+	cfg             *config.Config
+	options         AuthOptions
 }
 
-func NewAuthMiddleware(authService *services.AuthService, cfg *config.Config) *AuthMiddleware {
+func NewAuthMiddleware(authService *services.AuthService, roleService *services.RoleService, apiTokenService *services.ApiTokenService, cfg *config.Config) *AuthMiddleware {
 	return &AuthMiddleware{
-		authService: authService,
-		cfg:         cfg,
-		options:     AuthOptions{},
+		authService:     authService,
+		roleService:     roleService,
+		apiTokenService: apiTokenService,
+		cfg:             cfg,
+		options:         AuthOptions{},
 	}
 }
 
@@ -54,6 +60,14 @@ func (m *AuthMiddleware) WithSuccessOptional() *AuthMiddleware {
 	return &clone
 }
 
+// WithApiTokenRequired only accepts personal access tokens, for endpoints meant for
+// machines such as scrapers rather than browser sessions.
+func (m *AuthMiddleware) WithApiTokenRequired() *AuthMiddleware {
+	clone := *m
+	clone.options.ApiTokenRequired = true
+	return &clone
+}
+
 func (m *AuthMiddleware) Add() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		if m.cfg != nil && m.cfg.AgentMode {
@@ -97,6 +111,19 @@ func (m *AuthMiddleware) agentAuth(c *gin.Context) {
 }
 
 func (m *AuthMiddleware) managerAuth(c *gin.Context) {
+	if apiKey := extractApiKey(c); apiKey != "" {
+		m.apiTokenAuth(c, apiKey)
+		return
+	}
+	if m.options.ApiTokenRequired {
+		c.JSON(http.StatusUnauthorized, models.APIError{
+			Code:    models.APIErrorCodeUnauthorized,
+			Message: "API token required",
+		})
+		c.Abort()
+		return
+	}
+
 	token := extractBearerOrCookieToken(c)
 	if token == "" {
 		if m.options.SuccessOptional {
@@ -111,7 +138,7 @@ func (m *AuthMiddleware) managerAuth(c *gin.Context) {
 		return
 	}
 
-	user, err := m.authService.VerifyToken(c.Request.Context(), token)
+	user, sessionID, err := m.authService.VerifyTokenSession(c.Request.Context(), token)
 	if err != nil {
 		if errors.Is(err, services.ErrTokenVersionMismatch) {
 			cookie.ClearTokenCookie(c)
@@ -135,20 +162,160 @@ func (m *AuthMiddleware) managerAuth(c *gin.Context) {
 		return
 	}
 
-	isAdmin := userHasRole(user, "admin")
+	c.Set("sessionID", sessionID)
+	if !m.authorize(c, user, nil) {
+		return
+	}
+	c.Next()
+}
+
+func (m *AuthMiddleware) apiTokenAuth(c *gin.Context, apiKey string) {
+	user, token, err := m.authenticateApiToken(c, apiKey)
+	if err != nil {
+		if m.options.SuccessOptional {
+			c.Next()
+			return
+		}
+		c.JSON(http.StatusUnauthorized, models.APIError{
+			Code:    models.APIErrorCodeUnauthorized,
+			Message: "Invalid or expired API token",
+		})
+		c.Abort()
+		return
+	}
+
+	c.Set("apiTokenID", token.ID)
+	if !m.authorize(c, user, token.Scopes) {
+		return
+	}
+	c.Next()
+}
+
+func (m *AuthMiddleware) authenticateApiToken(c *gin.Context, apiKey string) (*models.User, *models.ApiToken, error) {
+	if m.apiTokenService == nil {
+		return nil, nil, services.ErrApiTokenInvalid
+	}
+	return m.apiTokenService.Authenticate(c.Request.Context(), apiKey)
+}
+
+// authorize applies the admin and route permission requirements and stores the user on
+// the context. scopes, when non-empty, further restricts what an API token may do; such
+// tokens are refused on routes without a routePermissions entry unless they grant "*".
+func (m *AuthMiddleware) authorize(c *gin.Context, user *models.User, scopes []string) bool {
+	isAdmin := actsAsAdmin(user, scopes)
+
 	if m.options.AdminRequired && !isAdmin {
 		c.JSON(http.StatusForbidden, models.APIError{
 			Code:    "FORBIDDEN",
 			Message: "You don't have permission to access this resource",
 		})
 		c.Abort()
-		return
+		return false
+	}
+
+	permission, mapped := requiredPermission(c)
+	// A scoped token can only reach routes with a known permission, so a route that is
+	// added without a routePermissions entry is not silently open to every token.
+	if !mapped && len(scopes) > 0 && !models.PermissionsAllow(scopes, models.PermissionAll) {
+		message := "API token scope does not cover this endpoint"
+		if isAccountRoute(c) {
+			message = "API tokens with limited scopes cannot manage the account"
+		}
+		c.JSON(http.StatusForbidden, models.APIError{
+			Code:    "FORBIDDEN",
+			Message: message,
+		})
+		c.Abort()
+		return false
+	}
+	if mapped {
+		if len(scopes) > 0 && !models.PermissionsAllow(scopes, permission) {
+			c.JSON(http.StatusForbidden, models.APIError{
+				Code:    "FORBIDDEN",
+				Message: "API token scope does not allow this action",
+				Details: gin.H{"permission": permission},
+			})
+			c.Abort()
+			return false
+		}
+		if !isAdmin && !m.checkPermission(c, user, permission) {
+			return false
+		}
 	}
 
 	c.Set("userID", user.ID)
 	c.Set("currentUser", user)
 	c.Set("userIsAdmin", isAdmin)
-	c.Next()
+	setAuditActor(c, user)
+	return true
+}
+
+// AuthorizeRemote is called by the environment proxy before a request is forwarded to a
+// remote environment, since the agent on the other side only checks the environment's
+// agent token. The proxy bypasses the route's own middleware, so every request is
+// authenticated here and routes without a permission requirement are admin-only.
+func (m *AuthMiddleware) AuthorizeRemote(c *gin.Context) bool {
+	var user *models.User
+	var scopes []string
+	var err error
+	if apiKey := extractApiKey(c); apiKey != "" {
+		var token *models.ApiToken
+		user, token, err = m.authenticateApiToken(c, apiKey)
+		if token != nil {
+			scopes = token.Scopes
+		}
+	} else if token := extractBearerOrCookieToken(c); token != "" {
+		user, err = m.authService.VerifyToken(c.Request.Context(), token)
+	} else {
+		err = services.ErrInvalidToken
+	}
+
+	if err != nil || user == nil {
+		c.JSON(http.StatusUnauthorized, models.APIError{
+			Code:    models.APIErrorCodeUnauthorized,
+			Message: "Invalid or expired token",
+		})
+		c.Abort()
+		return false
+	}
+
+	if _, mapped := requiredPermission(c); !mapped && !actsAsAdmin(user, scopes) {
+		c.JSON(http.StatusForbidden, models.APIError{
+			Code:    "FORBIDDEN",
+			Message: "You don't have permission to access this resource",
+		})
+		c.Abort()
+		return false
+	}
+
+	return m.authorize(c, user, scopes)
+}
+
+func (m *AuthMiddleware) checkPermission(c *gin.Context, user *models.User, permission models.Permission) bool {
+	if m.roleService == nil {
+		return true
+	}
+
+	allowed, err := m.roleService.HasPermission(c.Request.Context(), user, permission, environmentScope(c), c.Param("projectId"))
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "Failed to evaluate permissions", "user", user.Username, "permission", permission, "error", err)
+		c.JSON(http.StatusInternalServerError, models.APIError{
+			Code:    models.APIErrorCodeInternalServerError,
+			Message: "Failed to evaluate permissions",
+		})
+		c.Abort()
+		return false
+	}
+	if !allowed {
+		c.JSON(http.StatusForbidden, models.APIError{
+			Code:    "FORBIDDEN",
+			Message: "You don't have permission to access this resource",
+			Details: gin.H{"permission": permission},
+		})
+		c.Abort()
+		return false
+	}
+	return true
 }
 
 func isPreflight(c *gin.Context) bool {
@@ -160,14 +327,42 @@ func agentSudo(c *gin.Context) {
 	agentUser := &models.User{
 		BaseModel: models.BaseModel{ID: "agent"},
 		Email:     &email,
-		Roles:     []string{"admin"},
+		Roles:     []string{models.RoleAdmin},
 	}
 	c.Set("userID", agentUser.ID)
 	c.Set("currentUser", agentUser)
 	c.Set("userIsAdmin", true)
+	setAuditActor(c, agentUser)
 	c.Next()
 }
 
+// setAuditActor carries the authenticated user into the request context, where services
+// read it when recording audit log entries.
+func setAuditActor(c *gin.Context, user *models.User) {
+	username := user.Username
+	if username == "" && user.Email != nil {
+		username = *user.Email
+	}
+	c.Request = c.Request.WithContext(services.WithAuditActor(c.Request.Context(), services.AuditActor{
+		UserID:    user.ID,
+		Username:  username,
+		SourceIP:  c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	}))
+}
+
+// extractApiKey returns a personal access token from X-API-Key or from an Authorization
+// bearer value carrying the API token prefix. JWTs are left to extractBearerOrCookieToken.
+func extractApiKey(c *gin.Context) string {
+	if key := strings.TrimSpace(c.GetHeader(headerApiKey)); key != "" {
+		return key
+	}
+	if tok, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer "); ok && services.IsApiToken(tok) {
+		return tok
+	}
+	return ""
+}
+
 func extractBearerOrCookieToken(c *gin.Context) string {
 	authHeader := c.GetHeader("Authorization")
 	if strings.HasPrefix(authHeader, "Bearer ") {
@@ -179,6 +374,15 @@ func extractBearerOrCookieToken(c *gin.Context) string {
 	return ""
 }
 
+// actsAsAdmin reports whether the caller has admin rights. A scoped token only acts as
+// admin if it was explicitly granted everything.
+func actsAsAdmin(user *models.User, scopes []string) bool {
+	if len(scopes) > 0 && !models.PermissionsAllow(scopes, models.PermissionAll) {
+		return false
+	}
+	return userHasRole(user, models.RoleAdmin)
+}
+
 func userHasRole(user *models.User, role string) bool {
 	for _, r := range user.Roles {
 		if r == role {
@@ -197,6 +401,21 @@ func GetCurrentUserID(c *gin.Context) (string, bool) {
 	return userIDStr, ok
 }
 
+// GetCurrentSessionID returns the session of the access token the request was made with.
+// It is empty for API token requests.
+func GetCurrentSessionID(c *gin.Context) string {
+	sessionID, _ := c.Get("sessionID")
+	id, _ := sessionID.(string)
+	return id
+}
+
+// IsApiTokenRequest reports whether the current request was authenticated with a
+// personal access token rather than an interactive session.
+func IsApiTokenRequest(c *gin.Context) bool {
+	_, ok := c.Get("apiTokenID")
+	return ok
+}
+
 func GetCurrentUser(c *gin.Context) (*models.User, bool) {
 	user, exists := c.Get("currentUser")
 	if !exists {
@@ -23,11 +23,14 @@ type EnvResolver func(ctx context.Context, id string) (apiURL string, accessToke
 // is remote. paramName is the URL param key (e.g. "id") that contains the environment id when using
 // router groups; if that param is not present the middleware will attempt to auto-detect the id
 // by parsing the request path after the first "/environments/" segment.
-func NewEnvProxyMiddlewareWithParam(localID string, paramName string, resolver EnvResolver, envService *services.EnvironmentService) gin.HandlerFunc {
+// authorize, when set, runs before a request is proxied and must abort the request itself
+// when it returns false.
+func NewEnvProxyMiddlewareWithParam(localID string, paramName string, resolver EnvResolver, envService *services.EnvironmentService, authorize func(c *gin.Context) bool) gin.HandlerFunc {
 	m := &EnvironmentMiddleware{
 		localID:    localID,
 		resolver:   resolver,
 		envService: envService,
+		authorize:  authorize,
 		httpClient: &http.Client{
 			Timeout: 60 * time.Second,
 		},
@@ -39,6 +42,7 @@ type EnvironmentMiddleware struct {
 	localID    string
 	resolver   EnvResolver
 	envService *services.EnvironmentService
+	authorize  func(c *gin.Context) bool
 	httpClient *http.Client
 }
 
@@ -63,6 +67,10 @@ func (m *EnvironmentMiddleware) handle(paramName string) gin.HandlerFunc {
 			return
 		}
 
+		if m.authorize != nil && !m.authorize(c) {
+			return
+		}
+
 		target := m.buildTargetURL(c, envID, apiURL)
 
 		if m.isWebSocketRequest(c) {
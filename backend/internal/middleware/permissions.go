@@ -0,0 +1,226 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ofkm/arcane-backend/internal/models"
+)
+
+// routePermissions maps "METHOD <route pattern>" to the permission a non-admin caller
+// needs on the environment addressed by :id (and the project addressed by :projectId).
+// It is consulted both for local requests and before proxying to remote environments,
+// so every environment-scoped route that changes state should be listed here.
+// Routes not listed keep their existing authentication requirements, except that API
+// tokens with limited scopes are refused on them and only admins may reach them on a
+// remote environment. Only routes under /api/environments/:id are checked against the
+// environment's bindings; elsewhere :id names another resource and the permission is
+// checked globally.
+var routePermissions = map[string]models.Permission{
+	// Environments
+	"GET /api/environments":                      models.PermissionEnvironmentsRead,
+	"GET /api/environments/:id":                  models.PermissionEnvironmentsRead,
+	"POST /api/environments/:id/heartbeat":       models.PermissionEnvironmentsWrite,
+	"POST /api/environments":                     models.PermissionEnvironmentsWrite,
+	"PUT /api/environments/:id":                  models.PermissionEnvironmentsWrite,
+	"DELETE /api/environments/:id":               models.PermissionEnvironmentsWrite,
+	"POST /api/environments/:id/test":            models.PermissionEnvironmentsRead,
+	"POST /api/environments/:id/agent/pair":      models.PermissionEnvironmentsWrite,
+	"POST /api/environments/:id/sync-registries": models.PermissionEnvironmentsWrite,
+
+	// Prometheus exporter
+	"GET /api/metrics": models.PermissionSystemRead,
+
+	// Containers
+	"GET /api/environments/:id/containers":                       models.PermissionContainersRead,
+	"GET /api/environments/:id/containers/counts":                models.PermissionContainersRead,
+	"GET /api/environments/:id/containers/:containerId":          models.PermissionContainersRead,
+	"GET /api/environments/:id/containers/:containerId/stats/ws": models.PermissionContainersRead,
+	"GET /api/environments/:id/containers/:containerId/logs/ws":  models.PermissionContainersRead,
+	"GET /api/environments/:id/containers/:containerId/exec/ws":  models.PermissionContainersExec,
+	"POST /api/environments/:id/containers":                      models.PermissionContainersWrite,
+	"POST /api/environments/:id/containers/:containerId/start":   models.PermissionContainersOperate,
+	"POST /api/environments/:id/containers/:containerId/stop":    models.PermissionContainersOperate,
+	"POST /api/environments/:id/containers/:containerId/restart": models.PermissionContainersOperate,
+	"DELETE /api/environments/:id/containers/:containerId":       models.PermissionContainersDelete,
+
+	// System
+	"POST /api/environments/:id/system/containers/start-all":     models.PermissionContainersOperate,
+	"POST /api/environments/:id/system/containers/start-stopped": models.PermissionContainersOperate,
+	"POST /api/environments/:id/system/containers/stop-all":      models.PermissionContainersOperate,
+	"GET /api/environments/:id/system/stats/ws":                  models.PermissionSystemRead,
+	"GET /api/environments/:id/system/docker/info":               models.PermissionSystemRead,
+	"POST /api/environments/:id/system/convert":                  models.PermissionSystemRead,
+	"POST /api/environments/:id/system/prune":                    models.PermissionSystemOperate,
+	"GET /api/environments/:id/metrics":                          models.PermissionSystemRead,
+	"HEAD /api/environments/:id/system/health":                   models.PermissionSystemRead,
+	"GET /api/environments/:id/system/upgrade/check":             models.PermissionSystemOperate,
+	"POST /api/environments/:id/system/upgrade":                  models.PermissionSystemOperate,
+
+	// Images
+	"GET /api/environments/:id/image-updates/check":           models.PermissionImagesRead,
+	"GET /api/environments/:id/image-updates/check/:imageId":  models.PermissionImagesRead,
+	"POST /api/environments/:id/image-updates/check/:imageId": models.PermissionImagesRead,
+	"POST /api/environments/:id/image-updates/check-batch":    models.PermissionImagesRead,
+	"POST /api/environments/:id/image-updates/check-all":      models.PermissionImagesRead,
+	"GET /api/environments/:id/image-updates/summary":         models.PermissionImagesRead,
+	"GET /api/environments/:id/images":                        models.PermissionImagesRead,
+	"GET /api/environments/:id/images/counts":                 models.PermissionImagesRead,
+	"GET /api/environments/:id/images/:imageId":               models.PermissionImagesRead,
+	"POST /api/environments/:id/images/pull":                  models.PermissionImagesWrite,
+	"POST /api/environments/:id/images/upload":                models.PermissionImagesWrite,
+	"DELETE /api/environments/:id/images/:imageId":            models.PermissionImagesDelete,
+	"POST /api/environments/:id/images/prune":                 models.PermissionImagesDelete,
+
+	// Networks
+	"GET /api/environments/:id/networks":               models.PermissionNetworksRead,
+	"GET /api/environments/:id/networks/counts":        models.PermissionNetworksRead,
+	"GET /api/environments/:id/networks/:networkId":    models.PermissionNetworksRead,
+	"POST /api/environments/:id/networks":              models.PermissionNetworksWrite,
+	"DELETE /api/environments/:id/networks/:networkId": models.PermissionNetworksDelete,
+	"POST /api/environments/:id/networks/prune":        models.PermissionNetworksDelete,
+
+	// Volumes
+	"GET /api/environments/:id/volumes":                          models.PermissionVolumesRead,
+	"GET /api/environments/:id/volumes/counts":                   models.PermissionVolumesRead,
+	"GET /api/environments/:id/volumes/:volumeName":              models.PermissionVolumesRead,
+	"GET /api/environments/:id/volumes/:volumeName/usage":        models.PermissionVolumesRead,
+	"POST /api/environments/:id/volumes":                         models.PermissionVolumesWrite,
+	"DELETE /api/environments/:id/volumes/:volumeName":           models.PermissionVolumesDelete,
+	"POST /api/environments/:id/volumes/prune":                   models.PermissionVolumesDelete,
+	"POST /api/environments/:id/volumes/:volumeName/backups":     models.PermissionVolumesWrite,
+	"GET /api/environments/:id/backups":                          models.PermissionVolumesRead,
+	"DELETE /api/environments/:id/backups/:backupId":             models.PermissionVolumesDelete,
+	"POST /api/environments/:id/backups/:backupId/restore":       models.PermissionVolumesDelete,
+	"GET /api/environments/:id/backups/targets":                  models.PermissionVolumesRead,
+	"POST /api/environments/:id/backups/targets":                 models.PermissionVolumesWrite,
+	"PUT /api/environments/:id/backups/targets/:targetId":        models.PermissionVolumesWrite,
+	"DELETE /api/environments/:id/backups/targets/:targetId":     models.PermissionVolumesWrite,
+	"GET /api/environments/:id/backups/schedules":                models.PermissionVolumesRead,
+	"POST /api/environments/:id/backups/schedules":               models.PermissionVolumesWrite,
+	"PUT /api/environments/:id/backups/schedules/:scheduleId":    models.PermissionVolumesWrite,
+	"DELETE /api/environments/:id/backups/schedules/:scheduleId": models.PermissionVolumesWrite,
+
+	// Projects
+	"GET /api/environments/:id/projects":                                                models.PermissionProjectsRead,
+	"GET /api/environments/:id/projects/counts":                                         models.PermissionProjectsRead,
+	"GET /api/environments/:id/projects/:projectId":                                     models.PermissionProjectsRead,
+	"GET /api/environments/:id/projects/:projectId/logs/ws":                             models.PermissionProjectsRead,
+	"POST /api/environments/:id/projects/:projectId/up":                                 models.PermissionProjectsOperate,
+	"POST /api/environments/:id/projects/:projectId/down":                               models.PermissionProjectsOperate,
+	"POST /api/environments/:id/projects/:projectId/restart":                            models.PermissionProjectsOperate,
+	"POST /api/environments/:id/projects/:projectId/pull":                               models.PermissionProjectsDeploy,
+	"POST /api/environments/:id/projects/:projectId/redeploy":                           models.PermissionProjectsDeploy,
+	"POST /api/environments/:id/projects":                                               models.PermissionProjectsWrite,
+	"PUT /api/environments/:id/projects/:projectId":                                     models.PermissionProjectsWrite,
+	"PUT /api/environments/:id/projects/:projectId/includes":                            models.PermissionProjectsWrite,
+	"DELETE /api/environments/:id/projects/:projectId/destroy":                          models.PermissionProjectsDelete,
+	"POST /api/environments/:id/projects/git":                                           models.PermissionProjectsWrite,
+	"GET /api/environments/:id/projects/:projectId/git":                                 models.PermissionProjectsRead,
+	"PUT /api/environments/:id/projects/:projectId/git":                                 models.PermissionProjectsWrite,
+	"DELETE /api/environments/:id/projects/:projectId/git":                              models.PermissionProjectsWrite,
+	"POST /api/environments/:id/projects/:projectId/git/sync":                           models.PermissionProjectsDeploy,
+	"GET /api/environments/:id/projects/:projectId/webhooks":                            models.PermissionProjectsRead,
+	"POST /api/environments/:id/projects/:projectId/webhooks":                           models.PermissionProjectsWrite,
+	"DELETE /api/environments/:id/projects/:projectId/webhooks/:webhookId":              models.PermissionProjectsWrite,
+	"GET /api/environments/:id/projects/:projectId/revisions":                           models.PermissionProjectsRead,
+	"GET /api/environments/:id/projects/:projectId/revisions/:revisionId":               models.PermissionProjectsRead,
+	"GET /api/environments/:id/projects/:projectId/revisions/:revisionId/diff":          models.PermissionProjectsRead,
+	"POST /api/environments/:id/projects/:projectId/revisions/:revisionId/restore":      models.PermissionProjectsWrite,
+	"POST /api/environments/:id/projects/:projectId/revisions/:revisionId/redeploy":     models.PermissionProjectsDeploy,
+	"GET /api/environments/:id/projects/:projectId/deployments":                         models.PermissionProjectsRead,
+	"GET /api/environments/:id/projects/:projectId/deployments/:deploymentId":           models.PermissionProjectsRead,
+	"POST /api/environments/:id/projects/:projectId/deployments/:deploymentId/rollback": models.PermissionProjectsDeploy,
+
+	// Templates
+	"GET /api/templates":                   models.PermissionProjectsRead,
+	"GET /api/templates/all":               models.PermissionProjectsRead,
+	"GET /api/templates/:id":               models.PermissionProjectsRead,
+	"GET /api/templates/:id/content":       models.PermissionProjectsRead,
+	"GET /api/templates/default":           models.PermissionProjectsRead,
+	"GET /api/templates/registries":        models.PermissionProjectsRead,
+	"GET /api/templates/variables":         models.PermissionProjectsRead,
+	"POST /api/templates":                  models.PermissionProjectsWrite,
+	"PUT /api/templates/:id":               models.PermissionProjectsWrite,
+	"DELETE /api/templates/:id":            models.PermissionProjectsWrite,
+	"POST /api/templates/:id/download":     models.PermissionProjectsWrite,
+	"POST /api/templates/default":          models.PermissionProjectsWrite,
+	"POST /api/templates/registries":       models.PermissionProjectsWrite,
+	"PUT /api/templates/registries/:id":    models.PermissionProjectsWrite,
+	"DELETE /api/templates/registries/:id": models.PermissionProjectsWrite,
+	"PUT /api/templates/variables":         models.PermissionProjectsWrite,
+
+	// Container registries
+	"GET /api/container-registries":           models.PermissionImagesRead,
+	"GET /api/container-registries/:id":       models.PermissionImagesRead,
+	"POST /api/container-registries":          models.PermissionImagesWrite,
+	"PUT /api/container-registries/:id":       models.PermissionImagesWrite,
+	"DELETE /api/container-registries/:id":    models.PermissionImagesWrite,
+	"POST /api/container-registries/:id/test": models.PermissionImagesWrite,
+	"POST /api/container-registries/sync":     models.PermissionImagesWrite,
+
+	// Updater
+	"GET /api/environments/:id/updater/history":                     models.PermissionUpdaterRead,
+	"GET /api/environments/:id/updater/status":                      models.PermissionUpdaterRead,
+	"POST /api/environments/:id/updater/run":                        models.PermissionUpdaterRun,
+	"POST /api/environments/:id/updater/history/:recordId/rollback": models.PermissionUpdaterRun,
+	"GET /api/environments/:id/updater/policies":                    models.PermissionUpdaterRead,
+	"PUT /api/environments/:id/updater/policies":                    models.PermissionUpdaterRun,
+	"DELETE /api/environments/:id/updater/policies/:policyId":       models.PermissionUpdaterRun,
+
+	// Settings
+	"GET /api/environments/:id/settings": models.PermissionSettingsRead,
+	"PUT /api/environments/:id/settings": models.PermissionSettingsWrite,
+	"GET /api/settings/categories":       models.PermissionSettingsRead,
+	"POST /api/settings/search":          models.PermissionSettingsRead,
+	"GET /api/customize/categories":      models.PermissionSettingsRead,
+	"POST /api/customize/search":         models.PermissionSettingsRead,
+}
+
+// accountRoutes act on the caller's own sign-in credentials rather than on resources, so
+// they have no permission. Any signed-in user may use them, but API tokens with limited
+// scopes may not.
+var accountRoutes = map[string]struct{}{
+	"GET /api/auth/me":                        {},
+	"POST /api/auth/logout":                   {},
+	"POST /api/auth/password":                 {},
+	"GET /api/auth/sessions":                  {},
+	"DELETE /api/auth/sessions":               {},
+	"DELETE /api/auth/sessions/:sessionId":    {},
+	"POST /api/auth/totp/setup":               {},
+	"POST /api/auth/totp/enable":              {},
+	"POST /api/auth/totp/disable":             {},
+	"POST /api/auth/totp/recovery-codes":      {},
+	"GET /api/auth/passkeys":                  {},
+	"POST /api/auth/passkeys/register/begin":  {},
+	"POST /api/auth/passkeys/register/finish": {},
+	"PUT /api/auth/passkeys/:passkeyId":       {},
+	"DELETE /api/auth/passkeys/:passkeyId":    {},
+	"GET /api/users/:id/tokens":               {},
+	"POST /api/users/:id/tokens":              {},
+	"DELETE /api/users/:id/tokens/:tokenId":   {},
+}
+
+func requiredPermission(c *gin.Context) (models.Permission, bool) {
+	fullPath := c.FullPath()
+	if fullPath == "" {
+		return "", false
+	}
+	p, ok := routePermissions[c.Request.Method+" "+fullPath]
+	return p, ok
+}
+
+func isAccountRoute(c *gin.Context) bool {
+	_, ok := accountRoutes[c.Request.Method+" "+c.FullPath()]
+	return ok
+}
+
+// environmentScope returns the environment a route addresses, or "" when its :id
+// parameter is not an environment ID.
+func environmentScope(c *gin.Context) string {
+	fullPath := c.FullPath()
+	if fullPath != "/api/environments/:id" && !strings.HasPrefix(fullPath, "/api/environments/:id/") {
+		return ""
+	}
+	return c.Param("id")
+}
@@ -0,0 +1,67 @@
+package models
+
+import "time"
+
+type AlertRuleType string
+
+const (
+	AlertRuleContainerExited      AlertRuleType = "container_exited"
+	AlertRuleContainerUnhealthy   AlertRuleType = "container_unhealthy"
+	AlertRuleContainerRestartLoop AlertRuleType = "container_restart_loop"
+	AlertRuleCPUHigh              AlertRuleType = "cpu_high"
+	AlertRuleMemoryHigh           AlertRuleType = "memory_high"
+	AlertRuleDiskUsage            AlertRuleType = "disk_usage"
+	AlertRuleEnvironmentOffline   AlertRuleType = "environment_offline"
+)
+
+type AlertStatus string
+
+const (
+	AlertStatusPending  AlertStatus = "pending"
+	AlertStatusFiring   AlertStatus = "firing"
+	AlertStatusResolved AlertStatus = "resolved"
+)
+
+// AlertRule is a user-defined condition evaluated by the alert job.
+//
+// Target narrows what the rule watches: a container name glob for container rules
+// (empty matches every container), a container name glob for cpu_high/memory_high
+// (empty watches the host), or an environment ID for environment_offline (empty
+// watches every environment). Threshold is a percentage for usage rules and a restart
+// count for container_restart_loop.
+type AlertRule struct {
+	Name            string        `json:"name"`
+	Type            AlertRuleType `json:"type"`
+	Enabled         bool          `json:"enabled"`
+	Target          string        `json:"target"`
+	Threshold       float64       `json:"threshold"`
+	DurationMinutes int           `json:"durationMinutes"` // how long the condition must hold before firing
+	CooldownMinutes int           `json:"cooldownMinutes"` // minimum gap between repeat notifications
+	NotifyResolved  bool          `json:"notifyResolved"`
+
+	BaseModel
+}
+
+func (AlertRule) TableName() string {
+	return "alert_rules"
+}
+
+// AlertState tracks one rule against one resource (Key) across evaluations so alerts are
+// deduplicated and can be resolved.
+type AlertState struct {
+	RuleID         string      `json:"ruleId"`
+	Key            string      `json:"key"`
+	Status         AlertStatus `json:"status"`
+	Value          float64     `json:"value"`
+	Message        string      `json:"message"`
+	PendingSince   *time.Time  `json:"pendingSince,omitempty"`
+	FiredAt        *time.Time  `json:"firedAt,omitempty"`
+	ResolvedAt     *time.Time  `json:"resolvedAt,omitempty"`
+	LastNotifiedAt *time.Time  `json:"lastNotifiedAt,omitempty"`
+
+	BaseModel
+}
+
+func (AlertState) TableName() string {
+	return "alert_states"
+}
@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+)
+
+type ApiToken struct {
+	UserID     string      `json:"userId" gorm:"column:user_id;index"`
+	Name       string      `json:"name" sortable:"true"`
+	TokenHash  string      `json:"-" gorm:"column:token_hash;uniqueIndex"`
+	Prefix     string      `json:"prefix" gorm:"column:prefix"`
+	Scopes     StringSlice `json:"scopes" gorm:"type:text"`
+	ExpiresAt  *time.Time  `json:"expiresAt,omitempty" gorm:"column:expires_at" sortable:"true"`
+	LastUsedAt *time.Time  `json:"lastUsedAt,omitempty" gorm:"column:last_used_at" sortable:"true"`
+	BaseModel
+}
+
+func (ApiToken) TableName() string {
+	return "api_tokens"
+}
+
+func (t ApiToken) IsExpired(now time.Time) bool {
+	return t.ExpiresAt != nil && !now.Before(*t.ExpiresAt)
+}
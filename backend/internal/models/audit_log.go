@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// AuditLog records a configuration change: who made it, from where, and a redacted
+// before/after diff of the fields that changed. Rows are append-only; the database
+// rejects updates and only the retention job deletes them.
+type AuditLog struct {
+	Timestamp  time.Time `json:"timestamp" sortable:"true"`
+	UserID     *string   `json:"userId,omitempty" sortable:"true"`
+	Username   *string   `json:"username,omitempty" sortable:"true"`
+	SourceIP   *string   `json:"sourceIp,omitempty" gorm:"column:source_ip"`
+	UserAgent  *string   `json:"userAgent,omitempty"`
+	Action     string    `json:"action" sortable:"true"`
+	TargetType string    `json:"targetType" sortable:"true"`
+	TargetID   *string   `json:"targetId,omitempty"`
+	TargetName *string   `json:"targetName,omitempty" sortable:"true"`
+	Changes    JSON      `json:"changes,omitempty" gorm:"type:text"`
+	BaseModel
+}
+
+func (AuditLog) TableName() string {
+	return "audit_logs"
+}
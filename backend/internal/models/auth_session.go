@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// AuthSession is a signed-in browser or client. Access and refresh tokens carry the
+// session ID, so revoking the session invalidates both immediately. Each session is one
+// refresh-token family: RefreshTokenID is the ID of the only refresh token that may be
+// used next, and presenting an older one revokes the session.
+type AuthSession struct {
+	UserID         string     `json:"userId" gorm:"index"`
+	Method         string     `json:"method"`
+	RefreshTokenID string     `json:"-"`
+	UserAgent      string     `json:"userAgent"`
+	IPAddress      string     `json:"ipAddress"`
+	LastSeenAt     time.Time  `json:"lastSeenAt" sortable:"true"`
+	ExpiresAt      time.Time  `json:"expiresAt"`
+	RevokedAt      *time.Time `json:"revokedAt,omitempty"`
+	RevokedReason  *string    `json:"revokedReason,omitempty"`
+
+	BaseModel
+}
+
+func (AuthSession) TableName() string {
+	return "auth_sessions"
+}
+
+// Active reports whether the session can still be used at t.
+func (s *AuthSession) Active(t time.Time) bool {
+	return s.RevokedAt == nil && t.Before(s.ExpiresAt)
+}
@@ -7,12 +7,13 @@ import (
 type AutoUpdateStatus string
 
 const (
-	AutoUpdateStatusPending   AutoUpdateStatus = "pending"
-	AutoUpdateStatusChecking  AutoUpdateStatus = "checking"
-	AutoUpdateStatusUpdating  AutoUpdateStatus = "updating"
-	AutoUpdateStatusCompleted AutoUpdateStatus = "completed"
-	AutoUpdateStatusFailed    AutoUpdateStatus = "failed"
-	AutoUpdateStatusSkipped   AutoUpdateStatus = "skipped"
+	AutoUpdateStatusPending    AutoUpdateStatus = "pending"
+	AutoUpdateStatusChecking   AutoUpdateStatus = "checking"
+	AutoUpdateStatusUpdating   AutoUpdateStatus = "updating"
+	AutoUpdateStatusCompleted  AutoUpdateStatus = "completed"
+	AutoUpdateStatusFailed     AutoUpdateStatus = "failed"
+	AutoUpdateStatusSkipped    AutoUpdateStatus = "skipped"
+	AutoUpdateStatusRolledBack AutoUpdateStatus = "rolled_back"
 )
 
 type AutoUpdateRecord struct {
@@ -32,9 +32,11 @@ const (
 	EventTypeProjectUpdate EventType = "project.update"
 	EventTypeProjectError  EventType = "project.error"
 
-	EventTypeVolumeCreate EventType = "volume.create"
-	EventTypeVolumeDelete EventType = "volume.delete"
-	EventTypeVolumeError  EventType = "volume.error"
+	EventTypeVolumeCreate  EventType = "volume.create"
+	EventTypeVolumeDelete  EventType = "volume.delete"
+	EventTypeVolumeError   EventType = "volume.error"
+	EventTypeVolumeBackup  EventType = "volume.backup"
+	EventTypeVolumeRestore EventType = "volume.restore"
 
 	EventTypeNetworkCreate EventType = "network.create"
 	EventTypeNetworkDelete EventType = "network.delete"
@@ -46,6 +48,9 @@ const (
 	EventTypeSystemAutoUpdate EventType = "system.auto_update"
 	EventTypeSystemUpgrade    EventType = "system.upgrade"
 
+	EventTypeUserSessionRevoked EventType = "user.session_revoked"
+	EventTypeUserLoginFailed    EventType = "user.login_failed"
+
 	// Event severities
 	EventSeverityInfo    EventSeverity = "info"
 	EventSeverityWarning EventSeverity = "warning"
@@ -5,18 +5,20 @@ import (
 )
 
 type ImageUpdateRecord struct {
-	ID             string    `json:"id" gorm:"primaryKey;type:text"`
-	Repository     string    `json:"repository"`
-	Tag            string    `json:"tag"`
-	HasUpdate      bool      `json:"hasUpdate" gorm:"column:has_update"`
-	UpdateType     string    `json:"updateType" gorm:"column:update_type"`
-	CurrentVersion string    `json:"currentVersion" gorm:"column:current_version"`
-	LatestVersion  *string   `json:"latestVersion,omitempty" gorm:"column:latest_version"`
-	CurrentDigest  *string   `json:"currentDigest,omitempty" gorm:"column:current_digest"`
-	LatestDigest   *string   `json:"latestDigest,omitempty" gorm:"column:latest_digest"`
-	CheckTime      time.Time `json:"checkTime" gorm:"column:check_time"`
-	ResponseTimeMs int       `json:"responseTimeMs" gorm:"column:response_time_ms"`
-	LastError      *string   `json:"lastError,omitempty" gorm:"column:last_error"`
+	ID             string  `json:"id" gorm:"primaryKey;type:text"`
+	Repository     string  `json:"repository"`
+	Tag            string  `json:"tag"`
+	HasUpdate      bool    `json:"hasUpdate" gorm:"column:has_update"`
+	UpdateType     string  `json:"updateType" gorm:"column:update_type"`
+	CurrentVersion string  `json:"currentVersion" gorm:"column:current_version"`
+	LatestVersion  *string `json:"latestVersion,omitempty" gorm:"column:latest_version"`
+	// Newer semver tags of the same shape as Tag, newest first.
+	CandidateVersions StringSlice `json:"candidateVersions,omitempty" gorm:"column:candidate_versions;type:text"`
+	CurrentDigest     *string     `json:"currentDigest,omitempty" gorm:"column:current_digest"`
+	LatestDigest      *string     `json:"latestDigest,omitempty" gorm:"column:latest_digest"`
+	CheckTime         time.Time   `json:"checkTime" gorm:"column:check_time"`
+	ResponseTimeMs    int         `json:"responseTimeMs" gorm:"column:response_time_ms"`
+	LastError         *string     `json:"lastError,omitempty" gorm:"column:last_error"`
 
 	AuthMethod     *string `json:"authMethod,omitempty" gorm:"column:auth_method"`
 	AuthUsername   *string `json:"authUsername,omitempty" gorm:"column:auth_username"`
@@ -54,3 +56,9 @@ func (i *ImageUpdateRecord) IsDigestUpdate() bool {
 func (i *ImageUpdateRecord) IsTagUpdate() bool {
 	return i.UpdateType == UpdateTypeTag
 }
+
+// HasDigestUpdate reports whether the digest behind the current tag changed, independent of
+// any newer tags.
+func (i *ImageUpdateRecord) HasDigestUpdate() bool {
+	return i.CurrentDigest != nil && i.LatestDigest != nil && *i.CurrentDigest != *i.LatestDigest
+}
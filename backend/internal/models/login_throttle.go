@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+type LoginThrottleScope string
+
+const (
+	LoginThrottleScopeUsername LoginThrottleScope = "username"
+	LoginThrottleScopeIP       LoginThrottleScope = "ip"
+)
+
+// LoginThrottle counts recent failed sign-ins for one username or client IP. While
+// LockedUntil is in the future, sign-ins for it are refused without checking the
+// password. Usernames that do not exist are tracked too, so the response does not
+// reveal which accounts exist.
+type LoginThrottle struct {
+	Scope         LoginThrottleScope `json:"scope" gorm:"uniqueIndex:idx_login_throttles_scope_identifier" sortable:"true"`
+	Identifier    string             `json:"identifier" gorm:"uniqueIndex:idx_login_throttles_scope_identifier" sortable:"true"`
+	Failures      int                `json:"failures" sortable:"true"`
+	LastFailureAt time.Time          `json:"lastFailureAt" sortable:"true"`
+	LockedUntil   *time.Time         `json:"lockedUntil,omitempty" sortable:"true"`
+
+	BaseModel
+}
+
+func (LoginThrottle) TableName() string {
+	return "login_throttles"
+}
+
+// Locked reports whether sign-ins are refused at t.
+func (l *LoginThrottle) Locked(t time.Time) bool {
+	return l.LockedUntil != nil && t.Before(*l.LockedUntil)
+}
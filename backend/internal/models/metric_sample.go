@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+type MetricScope string
+
+const (
+	MetricScopeHost      MetricScope = "host"
+	MetricScopeContainer MetricScope = "container"
+)
+
+// MetricSample is one point of a host or container resource series. Raw samples
+// (Resolution 0) are written by the metrics collector and later folded into coarser
+// rows whose Resolution is the bucket width in seconds.
+type MetricSample struct {
+	Scope         MetricScope `json:"scope"`
+	ContainerID   string      `json:"containerId,omitempty"`
+	ContainerName string      `json:"containerName,omitempty"`
+	Timestamp     time.Time   `json:"timestamp"`
+	Resolution    int         `json:"resolution"`
+	SampleCount   int         `json:"sampleCount"`
+
+	CPUPercent    float64 `json:"cpuPercent"`
+	CPUPercentMax float64 `json:"cpuPercentMax"`
+	MemoryUsed    int64   `json:"memoryUsed"`
+	MemoryUsedMax int64   `json:"memoryUsedMax"`
+	MemoryLimit   int64   `json:"memoryLimit"`
+	DiskUsed      int64   `json:"diskUsed"`
+	DiskTotal     int64   `json:"diskTotal"`
+	NetworkRx     int64   `json:"networkRx"` // cumulative bytes
+	NetworkTx     int64   `json:"networkTx"` // cumulative bytes
+
+	BaseModel
+}
+
+func (MetricSample) TableName() string {
+	return "metric_samples"
+}
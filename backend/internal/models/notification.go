@@ -9,6 +9,11 @@ type NotificationProvider string
 const (
 	NotificationProviderDiscord NotificationProvider = "discord"
 	NotificationProviderEmail   NotificationProvider = "email"
+	NotificationProviderWebhook NotificationProvider = "webhook"
+	NotificationProviderSlack   NotificationProvider = "slack"
+	NotificationProviderTeams   NotificationProvider = "teams"
+	NotificationProviderNtfy    NotificationProvider = "ntfy"
+	NotificationProviderGotify  NotificationProvider = "gotify"
 )
 
 type NotificationEventType string
@@ -16,8 +21,43 @@ type NotificationEventType string
 const (
 	NotificationEventImageUpdate     NotificationEventType = "image_update"
 	NotificationEventContainerUpdate NotificationEventType = "container_update"
+	// Sent when an updated container fails its health check and is rolled back.
+	NotificationEventContainerRollback NotificationEventType = "container_rollback"
+	// Sent when a user-defined alert rule fires, repeats after its cooldown or resolves.
+	NotificationEventAlert NotificationEventType = "alert"
 )
 
+// NotificationDeliveryMode controls when a provider's notifications are sent.
+type NotificationDeliveryMode string
+
+const (
+	NotificationDeliveryModeImmediate NotificationDeliveryMode = "immediate"
+	// Image and container updates are queued and sent as one summary at the top of each hour.
+	NotificationDeliveryModeHourly NotificationDeliveryMode = "hourly"
+	// Image and container updates are queued and sent as one summary at DigestTime each day.
+	NotificationDeliveryModeDaily NotificationDeliveryMode = "daily"
+)
+
+// NotificationDeliveryConfig is stored under the "delivery" key of any provider's config.
+// Alerts and rollbacks skip the digest, but quiet hours hold back every notification until
+// they end.
+type NotificationDeliveryConfig struct {
+	Mode NotificationDeliveryMode `json:"mode,omitempty"`
+	// DigestTime is the "HH:MM" the daily digest is sent at. Defaults to 09:00.
+	DigestTime string                  `json:"digestTime,omitempty"`
+	QuietHours *NotificationQuietHours `json:"quietHours,omitempty"`
+	// Timezone is the IANA zone DigestTime and quiet hours are read in. Defaults to the
+	// server's local time.
+	Timezone string `json:"timezone,omitempty"`
+}
+
+// NotificationQuietHours is a daily "HH:MM" window. Start after End spans midnight.
+type NotificationQuietHours struct {
+	Enabled bool   `json:"enabled"`
+	Start   string `json:"start"`
+	End     string `json:"end"`
+}
+
 type EmailTLSMode string
 
 const (
@@ -39,6 +79,22 @@ func (NotificationSettings) TableName() string {
 	return "notification_settings"
 }
 
+// NotificationQueueItem is a notification held back for a digest or until quiet hours end.
+type NotificationQueueItem struct {
+	Provider  NotificationProvider  `json:"provider" gorm:"not null;index;type:varchar(50)"`
+	EventType NotificationEventType `json:"eventType" gorm:"not null"`
+	Message   JSON                  `json:"message" gorm:"type:jsonb"`
+	// SubscriptionID and DeliveryID are set for events routed by a notification
+	// subscription, whose digest goes to the subscription's channel.
+	SubscriptionID *string `json:"subscriptionId,omitempty" gorm:"index"`
+	DeliveryID     *string `json:"deliveryId,omitempty"`
+	BaseModel
+}
+
+func (NotificationQueueItem) TableName() string {
+	return "notification_queue"
+}
+
 type NotificationLog struct {
 	ID        uint                 `json:"id" gorm:"primaryKey"`
 	Provider  NotificationProvider `json:"provider" gorm:"not null;index;type:varchar(50)"`
@@ -73,12 +129,55 @@ type EmailConfig struct {
 	Events       map[NotificationEventType]bool `json:"events,omitempty"`
 }
 
+// WebhookConfig posts every notification to an arbitrary HTTP endpoint. BodyTemplate is a
+// Go text/template rendered with the notification; when empty a default JSON document is sent.
+type WebhookConfig struct {
+	URL          string                         `json:"url"`
+	Method       string                         `json:"method,omitempty"`
+	Headers      map[string]string              `json:"headers,omitempty"`
+	ContentType  string                         `json:"contentType,omitempty"`
+	BodyTemplate string                         `json:"bodyTemplate,omitempty"`
+	Events       map[NotificationEventType]bool `json:"events,omitempty"`
+}
+
+type SlackConfig struct {
+	WebhookURL string                         `json:"webhookUrl"`
+	Channel    string                         `json:"channel,omitempty"`
+	Username   string                         `json:"username,omitempty"`
+	IconEmoji  string                         `json:"iconEmoji,omitempty"`
+	Events     map[NotificationEventType]bool `json:"events,omitempty"`
+}
+
+type TeamsConfig struct {
+	WebhookURL string                         `json:"webhookUrl"`
+	Events     map[NotificationEventType]bool `json:"events,omitempty"`
+}
+
+type NtfyConfig struct {
+	ServerURL   string                         `json:"serverUrl,omitempty"`
+	Topic       string                         `json:"topic"`
+	AccessToken string                         `json:"accessToken,omitempty"`
+	Username    string                         `json:"username,omitempty"`
+	Password    string                         `json:"password,omitempty"`
+	Priority    int                            `json:"priority,omitempty"`
+	Tags        []string                       `json:"tags,omitempty"`
+	Events      map[NotificationEventType]bool `json:"events,omitempty"`
+}
+
+type GotifyConfig struct {
+	ServerURL string                         `json:"serverUrl"`
+	AppToken  string                         `json:"appToken"`
+	Priority  int                            `json:"priority,omitempty"`
+	Events    map[NotificationEventType]bool `json:"events,omitempty"`
+}
+
 type AppriseSettings struct {
 	ID                 uint      `json:"id" gorm:"primaryKey"`
 	APIURL             string    `json:"apiUrl" gorm:"not null"`
 	Enabled            bool      `json:"enabled" gorm:"default:false"`
 	ImageUpdateTag     string    `json:"imageUpdateTag" gorm:"type:varchar(255)"`
 	ContainerUpdateTag string    `json:"containerUpdateTag" gorm:"type:varchar(255)"`
+	Delivery           JSON      `json:"delivery,omitempty" gorm:"type:jsonb"`
 	CreatedAt          time.Time `json:"createdAt"`
 	UpdatedAt          time.Time `json:"updatedAt"`
 }
@@ -0,0 +1,53 @@
+package models
+
+import "time"
+
+// NotificationSubscription routes matching events to one notification channel. Every filter
+// is optional and an empty filter matches everything. EventTypes and ResourcePatterns accept
+// glob patterns such as "project.*". When Config is empty the provider's saved notification
+// settings are used, so several subscriptions can share one channel or each bring their own
+// webhook URL.
+type NotificationSubscription struct {
+	Name             string               `json:"name" gorm:"not null" sortable:"true"`
+	Provider         NotificationProvider `json:"provider" gorm:"not null;type:varchar(50)" sortable:"true"`
+	Enabled          bool                 `json:"enabled" gorm:"default:true"`
+	Config           JSON                 `json:"config,omitempty" gorm:"type:jsonb"`
+	EventTypes       StringSlice          `json:"eventTypes" gorm:"type:text"`
+	Severities       StringSlice          `json:"severities" gorm:"type:text"`
+	EnvironmentIDs   StringSlice          `json:"environmentIds" gorm:"column:environment_ids;type:text"`
+	ResourcePatterns StringSlice          `json:"resourcePatterns" gorm:"type:text"`
+	BaseModel
+}
+
+func (NotificationSubscription) TableName() string {
+	return "notification_subscriptions"
+}
+
+type NotificationDeliveryStatus string
+
+const (
+	NotificationDeliveryPending NotificationDeliveryStatus = "pending"
+	// NotificationDeliveryQueued marks a delivery held back by quiet hours or a digest
+	// schedule. It is settled when the digest it was queued for is sent.
+	NotificationDeliveryQueued  NotificationDeliveryStatus = "queued"
+	NotificationDeliverySuccess NotificationDeliveryStatus = "success"
+	NotificationDeliveryFailed  NotificationDeliveryStatus = "failed"
+)
+
+// NotificationDelivery records one attempt sequence of delivering an event to a subscription.
+type NotificationDelivery struct {
+	SubscriptionID string                     `json:"subscriptionId" gorm:"not null;index"`
+	EventID        string                     `json:"eventId" gorm:"not null;index"`
+	EventType      EventType                  `json:"eventType" gorm:"not null"`
+	Provider       NotificationProvider       `json:"provider" gorm:"not null;type:varchar(50)"`
+	Status         NotificationDeliveryStatus `json:"status" gorm:"not null;index"`
+	Attempts       int                        `json:"attempts" gorm:"not null;default:0"`
+	LastError      *string                    `json:"lastError,omitempty"`
+	NextAttemptAt  *time.Time                 `json:"nextAttemptAt,omitempty"`
+	DeliveredAt    *time.Time                 `json:"deliveredAt,omitempty"`
+	BaseModel
+}
+
+func (NotificationDelivery) TableName() string {
+	return "notification_deliveries"
+}
@@ -0,0 +1,45 @@
+package models
+
+// OidcDefaultProvider identifies the provider configured in the authOidcConfig setting
+// (or the OIDC_* environment variables). Additional providers are stored as
+// OidcProvider rows.
+const OidcDefaultProvider = "default"
+
+// OidcProvider is an additional identity provider, shown as its own button on the login
+// page. Its users are kept apart from other providers' by prefixing their subject with
+// the provider slug.
+type OidcProvider struct {
+	Slug         string `json:"slug" gorm:"uniqueIndex" sortable:"true"`
+	Name         string `json:"name" sortable:"true"`
+	IssuerURL    string `json:"issuerUrl"`
+	ClientID     string `json:"clientId"`
+	ClientSecret string `json:"-"`
+	Scopes       string `json:"scopes"`
+	Enabled      bool   `json:"enabled" sortable:"true"`
+	// ManageDefaultRole lets the role mappings decide whether users of this provider
+	// keep the default user role; otherwise only the mapped roles are synced.
+	ManageDefaultRole bool `json:"manageDefaultRole" gorm:"column:manage_default_role"`
+	BaseModel
+}
+
+func (OidcProvider) TableName() string {
+	return "oidc_providers"
+}
+
+// OidcRoleMapping grants RoleName to users whose claim at Claim (a dotted path such as
+// "groups" or "realm_access.roles") contains or equals Value. Roles that appear in a
+// mapping are synced on every login: they are removed again when the claim no longer
+// matches. An empty Provider applies the mapping to every provider. The default user
+// role is only removed from users whose provider manages it (ManageDefaultRole, or the
+// authOidcManageDefaultRole setting for the default provider).
+type OidcRoleMapping struct {
+	Provider string `json:"provider" gorm:"index"`
+	Claim    string `json:"claim"`
+	Value    string `json:"value"`
+	RoleName string `json:"roleName"`
+	BaseModel
+}
+
+func (OidcRoleMapping) TableName() string {
+	return "oidc_role_mappings"
+}
@@ -0,0 +1,85 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+)
+
+type DeploymentTrigger string
+
+const (
+	DeploymentTriggerUser     DeploymentTrigger = "user"
+	DeploymentTriggerWebhook  DeploymentTrigger = "webhook"
+	DeploymentTriggerGit      DeploymentTrigger = "git"
+	DeploymentTriggerRollback DeploymentTrigger = "rollback"
+	DeploymentTriggerSystem   DeploymentTrigger = "system"
+)
+
+type DeploymentStatus string
+
+const (
+	DeploymentStatusSuccess DeploymentStatus = "success"
+	DeploymentStatusFailed  DeploymentStatus = "failed"
+)
+
+// DeploymentImage is the image a service ran after a deployment. Pinned is the
+// repo@sha256 digest reference, or the local image ID for images that were never
+// pushed to a registry.
+type DeploymentImage struct {
+	Image   string `json:"image"`
+	ImageID string `json:"imageId"`
+	Pinned  string `json:"pinned"`
+}
+
+// nolint:recvcheck
+type DeploymentImages map[string]DeploymentImage
+
+func (d DeploymentImages) Value() (driver.Value, error) {
+	if d == nil {
+		return nil, nil
+	}
+	return json.Marshal(d)
+}
+
+func (d *DeploymentImages) Scan(value interface{}) error {
+	if value == nil {
+		*d = nil
+		return nil
+	}
+	switch v := value.(type) {
+	case []byte:
+		return json.Unmarshal(v, d)
+	case string:
+		return json.Unmarshal([]byte(v), d)
+	default:
+		return json.Unmarshal(nil, d)
+	}
+}
+
+// ProjectDeployment records one compose up of a project: which files were deployed
+// (by revision and content hash), the image digest each service ended up running, who
+// or what triggered it, and how it went. Deployments are numbered per project.
+type ProjectDeployment struct {
+	ProjectID     string            `json:"projectId" gorm:"index;uniqueIndex:idx_project_deployments_project_number"`
+	Number        int               `json:"number" gorm:"uniqueIndex:idx_project_deployments_project_number" sortable:"true"`
+	RevisionID    *string           `json:"revisionId,omitempty"`
+	ComposeHash   string            `json:"composeHash"`
+	Images        DeploymentImages  `json:"images,omitempty" gorm:"type:text"`
+	Trigger       DeploymentTrigger `json:"trigger" gorm:"column:trigger_type"`
+	TriggerDetail *string           `json:"triggerDetail,omitempty"`
+	UserID        *string           `json:"userId,omitempty"`
+	Username      *string           `json:"username,omitempty"`
+	RollbackOf    *string           `json:"rollbackOf,omitempty"`
+	Status        DeploymentStatus  `json:"status" sortable:"true"`
+	Error         *string           `json:"error,omitempty"`
+	StartedAt     time.Time         `json:"startedAt" sortable:"true"`
+	FinishedAt    time.Time         `json:"finishedAt"`
+	DurationMs    int64             `json:"durationMs" sortable:"true"`
+
+	BaseModel
+}
+
+func (ProjectDeployment) TableName() string {
+	return "project_deployments"
+}
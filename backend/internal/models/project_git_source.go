@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+type GitAuthType string
+
+const (
+	GitAuthNone  GitAuthType = "none"
+	GitAuthToken GitAuthType = "token"
+	GitAuthSSH   GitAuthType = "ssh"
+)
+
+// ProjectGitSource links a project to the Git repository it was cloned from. The project's
+// Path points at SubPath inside CheckoutPath.
+type ProjectGitSource struct {
+	ProjectID     string      `json:"projectId" gorm:"uniqueIndex"`
+	RepositoryURL string      `json:"repositoryUrl"`
+	Ref           string      `json:"ref"`
+	SubPath       string      `json:"subPath"`
+	CheckoutPath  string      `json:"checkoutPath"`
+	AuthType      GitAuthType `json:"authType"`
+	Username      *string     `json:"username,omitempty"`
+	// Encrypted access token or SSH private key, depending on AuthType.
+	Credential    string     `json:"-"`
+	AutoSync      bool       `json:"autoSync"`
+	SyncInterval  int        `json:"syncInterval"` // minutes
+	AutoDeploy    bool       `json:"autoDeploy"`
+	LastCommit    *string    `json:"lastCommit,omitempty"`
+	LastSyncAt    *time.Time `json:"lastSyncAt,omitempty"`
+	LastSyncError *string    `json:"lastSyncError,omitempty"`
+
+	BaseModel
+}
+
+func (ProjectGitSource) TableName() string {
+	return "project_git_sources"
+}
@@ -0,0 +1,21 @@
+package models
+
+// ProjectRevision is a saved copy of a project's compose file, .env and the include
+// files inside the project directory. A revision is recorded every time those files are
+// saved from Arcane, numbered per project starting at 1.
+type ProjectRevision struct {
+	ProjectID      string  `json:"projectId" gorm:"index"`
+	Revision       int     `json:"revision" sortable:"true"`
+	ComposeContent string  `json:"composeContent"`
+	EnvContent     string  `json:"envContent"`
+	IncludeFiles   JSON    `json:"includeFiles,omitempty" gorm:"type:text"`
+	Message        string  `json:"message"`
+	UserID         *string `json:"userId,omitempty"`
+	Username       *string `json:"username,omitempty"`
+
+	BaseModel
+}
+
+func (ProjectRevision) TableName() string {
+	return "project_revisions"
+}
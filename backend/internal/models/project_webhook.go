@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+type WebhookProvider string
+
+const (
+	WebhookProviderGeneric WebhookProvider = "generic"
+	WebhookProviderGitHub  WebhookProvider = "github"
+	WebhookProviderGitea   WebhookProvider = "gitea"
+	WebhookProviderGitLab  WebhookProvider = "gitlab"
+)
+
+// ProjectWebhook is an inbound URL that pulls and redeploys a project when called.
+// The URL token is only stored hashed; Secret (encrypted) is used to verify signatures.
+type ProjectWebhook struct {
+	ProjectID       string          `json:"projectId" gorm:"index"`
+	Name            string          `json:"name"`
+	Provider        WebhookProvider `json:"provider"`
+	TokenHash       string          `json:"-" gorm:"uniqueIndex"`
+	TokenPrefix     string          `json:"tokenPrefix"`
+	Secret          string          `json:"-"`
+	Enabled         bool            `json:"enabled"`
+	LastTriggeredAt *time.Time      `json:"lastTriggeredAt,omitempty"`
+	LastStatus      *string         `json:"lastStatus,omitempty"`
+	LastError       *string         `json:"lastError,omitempty"`
+
+	BaseModel
+}
+
+func (ProjectWebhook) TableName() string {
+	return "project_webhooks"
+}
@@ -0,0 +1,178 @@
+package models
+
+import (
+	"strings"
+)
+
+type Permission string
+
+const (
+	PermissionAll Permission = "*"
+
+	PermissionContainersRead    Permission = "containers:read"
+	PermissionContainersOperate Permission = "containers:operate"
+	PermissionContainersExec    Permission = "containers:exec"
+	PermissionContainersWrite   Permission = "containers:write"
+	PermissionContainersDelete  Permission = "containers:delete"
+
+	PermissionImagesRead   Permission = "images:read"
+	PermissionImagesWrite  Permission = "images:write"
+	PermissionImagesDelete Permission = "images:delete"
+
+	PermissionNetworksRead   Permission = "networks:read"
+	PermissionNetworksWrite  Permission = "networks:write"
+	PermissionNetworksDelete Permission = "networks:delete"
+
+	PermissionVolumesRead   Permission = "volumes:read"
+	PermissionVolumesWrite  Permission = "volumes:write"
+	PermissionVolumesDelete Permission = "volumes:delete"
+
+	PermissionProjectsRead    Permission = "projects:read"
+	PermissionProjectsOperate Permission = "projects:operate"
+	PermissionProjectsDeploy  Permission = "projects:deploy"
+	PermissionProjectsWrite   Permission = "projects:write"
+	PermissionProjectsDelete  Permission = "projects:delete"
+
+	PermissionSystemRead    Permission = "system:read"
+	PermissionSystemOperate Permission = "system:operate"
+
+	PermissionUpdaterRead Permission = "updater:read"
+	PermissionUpdaterRun  Permission = "updater:run"
+
+	PermissionSettingsRead  Permission = "settings:read"
+	PermissionSettingsWrite Permission = "settings:write"
+
+	PermissionEnvironmentsRead  Permission = "environments:read"
+	PermissionEnvironmentsWrite Permission = "environments:write"
+)
+
+const (
+	RoleAdmin    = "admin"
+	RoleUser     = "user"
+	RoleViewer   = "viewer"
+	RoleOperator = "operator"
+	RoleDeployer = "deployer"
+)
+
+type Role struct {
+	Name        string      `json:"name" gorm:"column:name;uniqueIndex" sortable:"true"`
+	Description *string     `json:"description,omitempty" gorm:"column:description"`
+	Permissions StringSlice `json:"permissions" gorm:"type:text"`
+	BuiltIn     bool        `json:"builtIn" gorm:"-"`
+	BaseModel
+}
+
+func (Role) TableName() string {
+	return "roles"
+}
+
+// Allows reports whether the role grants the given permission. Permissions
+// may be the global wildcard "*" or a resource wildcard such as "containers:*".
+func (r Role) Allows(permission Permission) bool {
+	return PermissionsAllow(r.Permissions, permission)
+}
+
+func PermissionsAllow(granted []string, permission Permission) bool {
+	resource, _, _ := strings.Cut(string(permission), ":")
+	for _, g := range granted {
+		switch {
+		case g == string(PermissionAll):
+			return true
+		case g == string(permission):
+			return true
+		case strings.HasSuffix(g, ":*") && strings.TrimSuffix(g, ":*") == resource:
+			return true
+		}
+	}
+	return false
+}
+
+// RoleBinding grants a role to a user for a single environment, or for a single
+// project inside an environment. Bindings without an environment apply everywhere.
+type RoleBinding struct {
+	UserID        string  `json:"userId" gorm:"column:user_id;index"`
+	RoleName      string  `json:"roleName" gorm:"column:role_name"`
+	EnvironmentID *string `json:"environmentId,omitempty" gorm:"column:environment_id"`
+	ProjectID     *string `json:"projectId,omitempty" gorm:"column:project_id"`
+	BaseModel
+}
+
+func (RoleBinding) TableName() string {
+	return "role_bindings"
+}
+
+var viewerPermissions = []string{
+	string(PermissionContainersRead),
+	string(PermissionImagesRead),
+	string(PermissionNetworksRead),
+	string(PermissionVolumesRead),
+	string(PermissionProjectsRead),
+	string(PermissionSystemRead),
+	string(PermissionUpdaterRead),
+	string(PermissionSettingsRead),
+	string(PermissionEnvironmentsRead),
+}
+
+var operatorPermissions = append(append([]string{}, viewerPermissions...),
+	string(PermissionContainersOperate),
+	string(PermissionProjectsOperate),
+)
+
+var deployerPermissions = append(append([]string{}, operatorPermissions...),
+	string(PermissionProjectsDeploy),
+	string(PermissionProjectsWrite),
+	string(PermissionImagesWrite),
+	string(PermissionUpdaterRun),
+)
+
+// BuiltinRoles are always available and cannot be modified. The "user" role keeps
+// the access non-admin accounts had before roles were introduced.
+var BuiltinRoles = []Role{
+	{
+		Name:        RoleViewer,
+		Description: strPtr("Read-only access to containers, images, networks, volumes and projects"),
+		Permissions: viewerPermissions,
+		BuiltIn:     true,
+	},
+	{
+		Name:        RoleOperator,
+		Description: strPtr("Viewer plus starting, stopping and restarting containers and projects"),
+		Permissions: operatorPermissions,
+		BuiltIn:     true,
+	},
+	{
+		Name:        RoleDeployer,
+		Description: strPtr("Operator plus pulling images, editing and redeploying projects"),
+		Permissions: deployerPermissions,
+		BuiltIn:     true,
+	},
+	{
+		Name:        RoleUser,
+		Description: strPtr("Full access to environment resources without administrative settings"),
+		Permissions: []string{
+			"containers:*", "images:*", "networks:*", "volumes:*", "projects:*",
+			"system:*", "updater:*", "environments:*",
+			string(PermissionSettingsRead),
+		},
+		BuiltIn: true,
+	},
+	{
+		Name:        RoleAdmin,
+		Description: strPtr("Unrestricted access"),
+		Permissions: []string{string(PermissionAll)},
+		BuiltIn:     true,
+	},
+}
+
+func GetBuiltinRole(name string) (Role, bool) {
+	for _, r := range BuiltinRoles {
+		if strings.EqualFold(r.Name, name) {
+			return r, true
+		}
+	}
+	return Role{}, false
+}
+
+func strPtr(s string) *string {
+	return &s
+}
@@ -12,7 +12,8 @@ import (
 )
 
 const (
-	redactionMask     = "XXXXXXXXXX"
+	// RedactionMask replaces sensitive values in API responses and audit log diffs.
+	RedactionMask     = "XXXXXXXXXX"
 	keyAuthOidcConfig = "authOidcConfig"
 )
 
@@ -51,6 +52,22 @@ type Settings struct {
 	DefaultShell              SettingVariable `key:"defaultShell" meta:"label=Default Shell;type=text;keywords=shell,default,shellpath,path,login;category=general;description=Default shell to use for commands"`
 	EnvironmentHealthInterval SettingVariable `key:"environmentHealthInterval" meta:"label=Environment Health Check Interval;type=number;keywords=environment,health,check,interval,frequency,heartbeat,status,monitoring,uptime;category=general;description=How often to check environment connectivity in minutes (default: 2)"`
 	AccentColor               SettingVariable `key:"accentColor,public,local" meta:"label=Accent Color;type=text;keywords=color,accent,theme,css,appearance,ui;category=general;description=Primary accent color for UI"`
+	MetricsEnabled            SettingVariable `key:"metricsEnabled" meta:"label=Metrics History;type=boolean;keywords=metrics,history,monitoring,cpu,memory,disk,stats,graphs,collect;category=general;description=Record host and container resource usage for historical graphs"`
+	MetricsInterval           SettingVariable `key:"metricsInterval" meta:"label=Metrics Interval;type=number;keywords=metrics,interval,frequency,sample,collect,seconds,monitoring;category=general;description=Seconds between metric samples (minimum: 5)"`
+	MetricsRetentionDays      SettingVariable `key:"metricsRetentionDays" meta:"label=Metrics Retention;type=number;keywords=metrics,retention,history,days,keep,cleanup,monitoring;category=general;description=Days of metrics history to keep; older samples are averaged into coarser points before being removed"`
+
+	// Event sinks: copies of events and audit entries for external log systems
+	EventSinkSyslogEnabled  SettingVariable `key:"eventSinkSyslogEnabled" meta:"label=Syslog Export;type=boolean;keywords=syslog,rfc5424,siem,export,forward,events,audit,logs;category=general;description=Send events and audit log entries to a syslog server"`
+	EventSinkSyslogProtocol SettingVariable `key:"eventSinkSyslogProtocol" meta:"label=Syslog Protocol;type=select;keywords=syslog,protocol,udp,tcp,tls,transport;category=general;description=Transport used to reach the syslog server (udp, tcp or tls)"`
+	EventSinkSyslogAddress  SettingVariable `key:"eventSinkSyslogAddress" meta:"label=Syslog Address;type=text;keywords=syslog,address,host,port,server;category=general;description=host:port of the syslog server"`
+	EventSinkLokiEnabled    SettingVariable `key:"eventSinkLokiEnabled" meta:"label=Loki Export;type=boolean;keywords=loki,grafana,push,export,forward,events,audit,logs;category=general;description=Push events and audit log entries to Grafana Loki"`
+	EventSinkLokiURL        SettingVariable `key:"eventSinkLokiUrl" meta:"label=Loki URL;type=text;keywords=loki,url,push,endpoint,server;category=general;description=Loki base URL or push endpoint; credentials may be given as user:password in the URL"`
+	EventSinkLokiLabels     SettingVariable `key:"eventSinkLokiLabels" meta:"label=Loki Labels;type=text;keywords=loki,labels,stream,tags;category=general;description=Extra stream labels as comma-separated key=value pairs"`
+	EventSinkLokiTenantID   SettingVariable `key:"eventSinkLokiTenantId" meta:"label=Loki Tenant;type=text;keywords=loki,tenant,org,multi-tenant,scope;category=general;description=Tenant sent in the X-Scope-OrgID header"`
+	EventSinkFileEnabled    SettingVariable `key:"eventSinkFileEnabled" meta:"label=JSONL Export;type=boolean;keywords=file,jsonl,json,lines,export,events,audit,logs,rotate;category=general;description=Append events and audit log entries to a rotating JSON Lines file"`
+	EventSinkFilePath       SettingVariable `key:"eventSinkFilePath" meta:"label=JSONL File Path;type=text;keywords=file,jsonl,path,location;category=general;description=Path of the JSON Lines file"`
+	EventSinkFileMaxSize    SettingVariable `key:"eventSinkFileMaxSize" meta:"label=JSONL File Size;type=number;keywords=file,jsonl,size,rotate,megabytes;category=general;description=Size in MB at which the file is rotated"`
+	EventSinkFileMaxBackups SettingVariable `key:"eventSinkFileMaxBackups" meta:"label=JSONL Rotated Files;type=number;keywords=file,jsonl,backups,rotate,keep;category=general;description=Number of rotated files to keep"`
 
 	// Deprecated: OnboardingCompleted is no longer used as of the onboarding removal.
 	// This field is kept for backward compatibility and is automatically set to true on startup.
@@ -63,21 +80,37 @@ type Settings struct {
 	OnboardingSteps SettingVariable `key:"onboardingSteps" meta:"label=Onboarding Steps;type=text;keywords=onboarding,steps,progress,guide;category=general;description=Serialized onboarding steps"`
 
 	// Docker category
-	AutoUpdate         SettingVariable `key:"autoUpdate" meta:"label=Auto Update;type=boolean;keywords=auto,update,automatic,upgrade,refresh,restart,deploy;category=docker;description=Automatically update containers when new images are available" catmeta:"id=docker;title=Docker;icon=database;url=/settings/docker;description=Configure Docker settings, polling, and auto-updates"`
-	AutoUpdateInterval SettingVariable `key:"autoUpdateInterval" meta:"label=Auto Update Interval;type=number;keywords=auto,update,interval,frequency,schedule,automatic,timing;category=docker;description=Interval between automatic updates"`
-	PollingEnabled     SettingVariable `key:"pollingEnabled" meta:"label=Enable Polling;type=boolean;keywords=polling,check,monitor,watch,scan,detection,automatic;category=docker;description=Enable automatic checking for image updates"`
-	PollingInterval    SettingVariable `key:"pollingInterval" meta:"label=Polling Interval;type=number;keywords=interval,frequency,schedule,time,minutes,period,delay;category=docker;description=How often to check for image updates"`
-	PruneMode          SettingVariable `key:"dockerPruneMode" meta:"label=Docker Prune Action;type=select;keywords=prune,cleanup,clean,remove,delete,unused,dangling,space,disk;category=docker;description=Configure how unused Docker images are cleaned up"`
-	MaxImageUploadSize SettingVariable `key:"maxImageUploadSize" meta:"label=Max Image Upload Size;type=number;keywords=upload,size,limit,maximum,image,tar,file,megabytes,mb,storage;category=docker;description=Maximum size in MB for image archive uploads (default: 500)"`
-	DockerHost         SettingVariable `key:"dockerHost,public,envOverride" meta:"label=Docker Host;type=text;keywords=docker,host,daemon,socket,unix,remote;category=docker;description=URI for Docker daemon"`
+	AutoUpdate               SettingVariable `key:"autoUpdate" meta:"label=Auto Update;type=boolean;keywords=auto,update,automatic,upgrade,refresh,restart,deploy;category=docker;description=Automatically update containers when new images are available" catmeta:"id=docker;title=Docker;icon=database;url=/settings/docker;description=Configure Docker settings, polling, and auto-updates"`
+	AutoUpdateInterval       SettingVariable `key:"autoUpdateInterval" meta:"label=Auto Update Interval;type=number;keywords=auto,update,interval,frequency,schedule,automatic,timing;category=docker;description=Interval between automatic updates"`
+	AutoUpdateRollback       SettingVariable `key:"autoUpdateRollback" meta:"label=Roll Back Failed Updates;type=boolean;keywords=auto,update,rollback,revert,health,healthcheck,unhealthy,safety;category=docker;description=Recreate a container from its previous image when it fails health checks after an automatic update"`
+	AutoUpdateHealthTimeout  SettingVariable `key:"autoUpdateHealthTimeout" meta:"label=Update Health Timeout;type=number;keywords=auto,update,health,healthcheck,timeout,wait,seconds,rollback;category=docker;description=Seconds to wait for an updated container with a healthcheck to report healthy"`
+	AutoUpdateGracePeriod    SettingVariable `key:"autoUpdateGracePeriod" meta:"label=Update Grace Period;type=number;keywords=auto,update,grace,period,running,restart,seconds,rollback;category=docker;description=Seconds an updated container without a healthcheck must keep running before the update is considered successful"`
+	AutoUpdatePolicy         SettingVariable `key:"autoUpdatePolicy" meta:"label=Default Update Policy;type=select;keywords=auto,update,policy,semver,patch,minor,major,digest,pin;category=docker;description=Which updates the auto-updater may apply when a container or project has no policy of its own (pin, digest, patch, minor or major)"`
+	AutoUpdateWindow         SettingVariable `key:"autoUpdateWindow" meta:"label=Maintenance Window;type=text;keywords=auto,update,maintenance,window,cron,schedule;category=docker;description=Cron expression for when the maintenance window opens; automatic updates only run while it is open. Leave empty to allow updates at any time"`
+	AutoUpdateWindowTimezone SettingVariable `key:"autoUpdateWindowTimezone" meta:"label=Maintenance Window Timezone;type=text;keywords=auto,update,maintenance,window,timezone,tz;category=docker;description=IANA timezone the maintenance window schedule is evaluated in"`
+	AutoUpdateWindowDuration SettingVariable `key:"autoUpdateWindowDuration" meta:"label=Maintenance Window Duration;type=number;keywords=auto,update,maintenance,window,duration,minutes;category=docker;description=Minutes the maintenance window stays open"`
+	PollingEnabled           SettingVariable `key:"pollingEnabled" meta:"label=Enable Polling;type=boolean;keywords=polling,check,monitor,watch,scan,detection,automatic;category=docker;description=Enable automatic checking for image updates"`
+	PollingInterval          SettingVariable `key:"pollingInterval" meta:"label=Polling Interval;type=number;keywords=interval,frequency,schedule,time,minutes,period,delay;category=docker;description=How often to check for image updates"`
+	PruneMode                SettingVariable `key:"dockerPruneMode" meta:"label=Docker Prune Action;type=select;keywords=prune,cleanup,clean,remove,delete,unused,dangling,space,disk;category=docker;description=Configure how unused Docker images are cleaned up"`
+	MaxImageUploadSize       SettingVariable `key:"maxImageUploadSize" meta:"label=Max Image Upload Size;type=number;keywords=upload,size,limit,maximum,image,tar,file,megabytes,mb,storage;category=docker;description=Maximum size in MB for image archive uploads (default: 500)"`
+	DockerHost               SettingVariable `key:"dockerHost,public,envOverride" meta:"label=Docker Host;type=text;keywords=docker,host,daemon,socket,unix,remote;category=docker;description=URI for Docker daemon"`
+	BackupDirectory          SettingVariable `key:"backupDirectory" meta:"label=Backup Directory;type=text;keywords=backup,volume,archive,directory,path,folder,restore,storage;category=docker;description=Local directory where volume backups are stored"`
+	BackupAllowedPaths       SettingVariable `key:"backupAllowedPaths" meta:"label=Allowed Backup Paths;type=text;keywords=backup,target,local,path,directory,allowlist,folder;category=docker;description=Comma-separated absolute directories local backup targets may use besides the backup directory"`
+	BackupHelperImage        SettingVariable `key:"backupHelperImage" meta:"label=Backup Helper Image;type=text;keywords=backup,restore,volume,helper,image,container;category=docker;description=Image used for the short-lived container that reads and writes volume data"`
 
 	// Security category
-	AuthLocalEnabled      SettingVariable `key:"authLocalEnabled,public" meta:"label=Local Authentication;type=boolean;keywords=local,auth,authentication,username,password,login,credentials;category=security;description=Enable local username/password authentication" catmeta:"id=security;title=Security;icon=shield;url=/settings/security;description=Manage authentication and security settings"`
-	AuthOidcEnabled       SettingVariable `key:"authOidcEnabled,public" meta:"label=OIDC Authentication;type=boolean;keywords=oidc,openid,connect,sso,oauth,external,provider,federation;category=security;description=Enable OpenID Connect (OIDC) authentication"`
-	AuthOidcMergeAccounts SettingVariable `key:"authOidcMergeAccounts,public" meta:"label=OIDC Account Merging;type=boolean;keywords=oidc,merge,link,accounts,email,match,existing,users,combine;category=security;description=Allow OIDC logins to merge with existing accounts by email"`
-	AuthSessionTimeout    SettingVariable `key:"authSessionTimeout" meta:"label=Session Timeout;type=number;keywords=session,timeout,expire,duration,lifetime,minutes,logout;category=security;description=How long user sessions remain active"`
-	AuthPasswordPolicy    SettingVariable `key:"authPasswordPolicy" meta:"label=Password Policy;type=select;keywords=password,policy,strength,complexity,requirements,security,rules;category=security;description=Set password strength requirements"`
-	AuthOidcConfig        SettingVariable `key:"authOidcConfig,sensitive" meta:"label=OIDC Config;type=text;keywords=oidc,config,client,id,issuer,secret,oauth;category=security;description=OIDC provider configuration"`
+	AuthLocalEnabled          SettingVariable `key:"authLocalEnabled,public" meta:"label=Local Authentication;type=boolean;keywords=local,auth,authentication,username,password,login,credentials;category=security;description=Enable local username/password authentication" catmeta:"id=security;title=Security;icon=shield;url=/settings/security;description=Manage authentication and security settings"`
+	AuthOidcEnabled           SettingVariable `key:"authOidcEnabled,public" meta:"label=OIDC Authentication;type=boolean;keywords=oidc,openid,connect,sso,oauth,external,provider,federation;category=security;description=Enable OpenID Connect (OIDC) authentication"`
+	AuthOidcMergeAccounts     SettingVariable `key:"authOidcMergeAccounts,public" meta:"label=OIDC Account Merging;type=boolean;keywords=oidc,merge,link,accounts,email,match,existing,users,combine;category=security;description=Allow logins through the default OIDC provider to merge with existing accounts by email"`
+	AuthOidcManageDefaultRole SettingVariable `key:"authOidcManageDefaultRole" meta:"label=OIDC Role Mappings Manage Default Role;type=boolean;keywords=oidc,role,mapping,default,user,remove,revoke,groups;category=security;description=Remove the default user role from users of the default OIDC provider unless a role mapping grants it"`
+	AuthSessionTimeout        SettingVariable `key:"authSessionTimeout" meta:"label=Session Timeout;type=number;keywords=session,timeout,expire,duration,lifetime,minutes,logout;category=security;description=How long user sessions remain active"`
+	AuthPasswordPolicy        SettingVariable `key:"authPasswordPolicy" meta:"label=Password Policy;type=select;keywords=password,policy,strength,complexity,requirements,security,rules;category=security;description=Set password strength requirements"`
+	AuthRequireTotp           SettingVariable `key:"authRequireTotp" meta:"label=Require Two-Factor Authentication;type=boolean;keywords=2fa,mfa,totp,two,factor,authenticator,otp,code,enforce,require;category=security;description=Require every local account to sign in with an authenticator app code"`
+	AuthPasskeysEnabled       SettingVariable `key:"authPasskeysEnabled,public" meta:"label=Passkeys;type=boolean;keywords=passkey,webauthn,fido,fido2,security,key,yubikey,hardware,biometric,phishing;category=security;description=Allow users to register passkeys and security keys and sign in with them"`
+	AuthLockoutThreshold      SettingVariable `key:"authLockoutThreshold" meta:"label=Account Lockout Threshold;type=number;keywords=lockout,lock,brute,force,failed,login,attempts,throttle,rate,limit;category=security;description=Failed sign-in attempts before an account is temporarily locked; 0 disables lockout"`
+	AuthLockoutDuration       SettingVariable `key:"authLockoutDuration" meta:"label=Account Lockout Duration;type=number;keywords=lockout,lock,duration,minutes,brute,force,failed,login;category=security;description=Minutes an account stays locked after too many failed sign-in attempts"`
+	AuthOidcConfig            SettingVariable `key:"authOidcConfig,sensitive" meta:"label=OIDC Config;type=text;keywords=oidc,config,client,id,issuer,secret,oauth;category=security;description=OIDC provider configuration"`
+	AuditLogRetentionDays     SettingVariable `key:"auditLogRetentionDays" meta:"label=Audit Log Retention;type=number;keywords=audit,log,trail,retention,compliance,history,changes,days;category=security;description=Days to keep audit log entries (minimum 30); 0 keeps them forever"`
 
 	// Navigation category
 	MobileNavigationMode       SettingVariable `key:"mobileNavigationMode,public,local" meta:"label=Mobile Navigation Mode;type=select;keywords=mode,style,type,floating,docked,position,layout,design,appearance,bottom;category=navigation;description=Choose between floating or docked navigation on mobile" catmeta:"id=navigation;title=Navigation;icon=navigation;url=/settings/navigation;description=Customize navigation and interface behavior"`
@@ -202,12 +235,12 @@ func redactSettingValue(key, value, attrs string, redact bool) string {
 			if redacted, err := json.Marshal(cfg); err == nil {
 				return string(redacted)
 			}
-			return redactionMask
+			return RedactionMask
 		}
-		return redactionMask
+		return RedactionMask
 	}
 
-	return redactionMask
+	return RedactionMask
 }
 
 type SettingKeyNotFoundError struct {
@@ -237,6 +270,8 @@ func (e SettingSensitiveForbiddenError) Is(target error) bool {
 }
 
 type OidcConfig struct {
+	// Name is the label of the login button; empty shows a generic label.
+	Name         string `json:"name,omitempty"`
 	ClientID     string `json:"clientId"`
 	ClientSecret string `json:"clientSecret"`
 	IssuerURL    string `json:"issuerUrl"`
@@ -0,0 +1,26 @@
+package models
+
+type UpdatePolicyScope string
+
+const (
+	UpdatePolicyScopeContainer UpdatePolicyScope = "container"
+	UpdatePolicyScopeProject   UpdatePolicyScope = "project"
+)
+
+// UpdatePolicy overrides the global auto-update policy and maintenance window for a single
+// container (by name) or every container of a compose project. Empty fields fall through
+// to the next scope.
+type UpdatePolicy struct {
+	ScopeType      UpdatePolicyScope `json:"scopeType"`
+	ScopeName      string            `json:"scopeName"`
+	Policy         string            `json:"policy"`
+	WindowCron     string            `json:"windowCron"`
+	WindowTimezone string            `json:"windowTimezone"`
+	WindowDuration int               `json:"windowDuration"` // minutes
+
+	BaseModel
+}
+
+func (UpdatePolicy) TableName() string {
+	return "update_policies"
+}
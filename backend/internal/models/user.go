@@ -15,6 +15,13 @@ type User struct {
 	Locale                 *string     `json:"locale,omitempty" gorm:"column:locale"`
 	RequiresPasswordChange bool        `json:"requiresPasswordChange" gorm:"column:requires_password_change"`
 
+	// TOTP second factor. TotpSecret is encrypted and set at enrolment; it is only used
+	// for login once TotpEnabled is true. Recovery codes are stored hashed.
+	TotpEnabled       bool        `json:"totpEnabled" gorm:"column:totp_enabled"`
+	TotpSecret        *string     `json:"-" gorm:"column:totp_secret"`
+	TotpLastUsedStep  int64       `json:"-" gorm:"column:totp_last_used_step"`
+	TotpRecoveryCodes StringSlice `json:"-" gorm:"column:totp_recovery_codes;type:text"`
+
 	// OIDC provider tokens
 	OidcAccessToken          *string    `json:"-" gorm:"type:text"`
 	OidcRefreshToken         *string    `json:"-" gorm:"type:text"`
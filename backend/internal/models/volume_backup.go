@@ -0,0 +1,81 @@
+package models
+
+import "time"
+
+type BackupTargetType string
+
+const (
+	BackupTargetLocal BackupTargetType = "local"
+	BackupTargetS3    BackupTargetType = "s3"
+)
+
+type VolumeBackupStatus string
+
+const (
+	VolumeBackupStatusRunning VolumeBackupStatus = "running"
+	VolumeBackupStatusSuccess VolumeBackupStatus = "success"
+	VolumeBackupStatusFailed  VolumeBackupStatus = "failed"
+)
+
+// BackupTarget is a destination for volume backups. Local targets write below Path
+// (or the backupDirectory setting when empty); S3 targets upload to Bucket/Prefix.
+type BackupTarget struct {
+	Name            string           `json:"name"`
+	Type            BackupTargetType `json:"type"`
+	Path            string           `json:"path"`
+	Endpoint        string           `json:"endpoint"`
+	Region          string           `json:"region"`
+	Bucket          string           `json:"bucket"`
+	Prefix          string           `json:"prefix"`
+	AccessKeyID     string           `json:"accessKeyId"`
+	SecretAccessKey string           `json:"-"` // encrypted
+	PathStyle       bool             `json:"pathStyle"`
+
+	BaseModel
+}
+
+func (BackupTarget) TableName() string {
+	return "backup_targets"
+}
+
+// VolumeBackupSchedule backs up a volume on a cron schedule and prunes old archives.
+// A zero RetentionCount or RetentionDays disables that limit.
+type VolumeBackupSchedule struct {
+	VolumeName     string     `json:"volumeName"`
+	CronExpression string     `json:"cronExpression"`
+	TargetID       *string    `json:"targetId,omitempty"`
+	RetentionCount int        `json:"retentionCount"`
+	RetentionDays  int        `json:"retentionDays"`
+	StopContainers bool       `json:"stopContainers"`
+	Enabled        bool       `json:"enabled"`
+	LastRunAt      *time.Time `json:"lastRunAt,omitempty"`
+
+	BaseModel
+}
+
+func (VolumeBackupSchedule) TableName() string {
+	return "volume_backup_schedules"
+}
+
+// VolumeBackup is one archive in the backup history. Location is a file path for local
+// targets and an object key for S3 targets.
+type VolumeBackup struct {
+	VolumeName  string             `json:"volumeName"`
+	ScheduleID  *string            `json:"scheduleId,omitempty"`
+	TargetID    *string            `json:"targetId,omitempty"`
+	TargetType  BackupTargetType   `json:"targetType"`
+	Location    string             `json:"location"`
+	SizeBytes   int64              `json:"sizeBytes"`
+	Checksum    string             `json:"checksum"`
+	Status      VolumeBackupStatus `json:"status"`
+	Error       *string            `json:"error,omitempty"`
+	TriggeredBy string             `json:"triggeredBy"`
+	StartedAt   time.Time          `json:"startedAt"`
+	CompletedAt *time.Time         `json:"completedAt,omitempty"`
+
+	BaseModel
+}
+
+func (VolumeBackup) TableName() string {
+	return "volume_backups"
+}
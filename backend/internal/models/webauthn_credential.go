@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// WebauthnCredential is a passkey or security key registered by a user. CredentialID is
+// the base64url credential ID the authenticator returns on sign in and PublicKey the
+// COSE-encoded key its assertions are checked against.
+type WebauthnCredential struct {
+	UserID       string      `json:"userId" gorm:"index"`
+	Name         string      `json:"name"`
+	CredentialID string      `json:"credentialId" gorm:"uniqueIndex"`
+	PublicKey    []byte      `json:"-"`
+	AAGUID       string      `json:"aaguid,omitempty" gorm:"column:aaguid"`
+	SignCount    uint32      `json:"-"`
+	Transports   StringSlice `json:"transports,omitempty" gorm:"type:text"`
+	BackedUp     bool        `json:"backedUp"`
+	LastUsedAt   *time.Time  `json:"lastUsedAt,omitempty"`
+
+	BaseModel
+}
+
+func (WebauthnCredential) TableName() string {
+	return "webauthn_credentials"
+}
@@ -0,0 +1,651 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"gorm.io/gorm"
+
+	"github.com/ofkm/arcane-backend/internal/database"
+	"github.com/ofkm/arcane-backend/internal/dto"
+	"github.com/ofkm/arcane-backend/internal/models"
+)
+
+var (
+	ErrAlertRuleNotFound = errors.New("alert rule not found")
+	ErrInvalidAlertRule  = errors.New("invalid alert rule")
+)
+
+const (
+	defaultAlertCooldownMinutes = 60
+	defaultRestartLoopThreshold = 3
+	defaultRestartLoopWindow    = 10 * time.Minute
+	// metric samples older than this are ignored so a stopped collector cannot keep an alert firing
+	alertMetricsStaleness = 5 * time.Minute
+)
+
+// AlertNotification is what NotificationService and AppriseService deliver for a rule
+// that started firing, is still firing after its cooldown, or resolved.
+type AlertNotification struct {
+	RuleName  string
+	RuleType  models.AlertRuleType
+	Resource  string
+	Status    models.AlertStatus
+	Message   string
+	Value     string
+	Threshold string
+}
+
+type AlertService struct {
+	db                  *database.DB
+	dockerService       *DockerClientService
+	notificationService *NotificationService
+
+	mu sync.Mutex
+	// restart counts seen per container ID, used to detect restart loops
+	restartHistory map[string][]restartObservation
+}
+
+type restartObservation struct {
+	at    time.Time
+	count int
+}
+
+// alertObservation is the current reading of one rule against one resource.
+type alertObservation struct {
+	resource string
+	active   bool
+	value    float64
+	message  string
+}
+
+// alertSnapshot lazily loads the inputs shared by every rule in one evaluation.
+type alertSnapshot struct {
+	containers       []container.Summary
+	containersErr    error
+	containersLoaded bool
+
+	samples       map[string]models.MetricSample // "host" or "container/<name>"
+	samplesErr    error
+	samplesLoaded bool
+}
+
+func NewAlertService(db *database.DB, dockerService *DockerClientService, notificationService *NotificationService) *AlertService {
+	return &AlertService{
+		db:                  db,
+		dockerService:       dockerService,
+		notificationService: notificationService,
+		restartHistory:      make(map[string][]restartObservation),
+	}
+}
+
+func (s *AlertService) ListRules(ctx context.Context) ([]models.AlertRule, error) {
+	var rules []models.AlertRule
+	if err := s.db.WithContext(ctx).Order("name ASC").Find(&rules).Error; err != nil {
+		return nil, fmt.Errorf("failed to list alert rules: %w", err)
+	}
+	return rules, nil
+}
+
+func (s *AlertService) GetRule(ctx context.Context, id string) (*models.AlertRule, error) {
+	var rule models.AlertRule
+	if err := s.db.WithContext(ctx).Where("id = ?", id).First(&rule).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAlertRuleNotFound
+		}
+		return nil, fmt.Errorf("failed to get alert rule: %w", err)
+	}
+	return &rule, nil
+}
+
+func (s *AlertService) CreateRule(ctx context.Context, req dto.AlertRuleDto) (*models.AlertRule, error) {
+	rule := models.AlertRule{}
+	if err := applyAlertRuleDto(&rule, req); err != nil {
+		return nil, err
+	}
+	if err := s.db.WithContext(ctx).Create(&rule).Error; err != nil {
+		return nil, fmt.Errorf("failed to create alert rule: %w", err)
+	}
+	return &rule, nil
+}
+
+func (s *AlertService) UpdateRule(ctx context.Context, id string, req dto.AlertRuleDto) (*models.AlertRule, error) {
+	rule, err := s.GetRule(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	typeChanged := rule.Type != req.Type
+	targetChanged := rule.Target != strings.TrimSpace(req.Target)
+	if err := applyAlertRuleDto(rule, req); err != nil {
+		return nil, err
+	}
+
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(rule).Error; err != nil {
+			return fmt.Errorf("failed to update alert rule: %w", err)
+		}
+		// States keyed for the old condition would never resolve cleanly; start over.
+		if typeChanged || targetChanged || !rule.Enabled {
+			if err := tx.Where("rule_id = ?", rule.ID).Delete(&models.AlertState{}).Error; err != nil {
+				return fmt.Errorf("failed to reset alert states: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+func (s *AlertService) DeleteRule(ctx context.Context, id string) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("rule_id = ?", id).Delete(&models.AlertState{}).Error; err != nil {
+			return fmt.Errorf("failed to delete alert states: %w", err)
+		}
+		res := tx.Where("id = ?", id).Delete(&models.AlertRule{})
+		if res.Error != nil {
+			return fmt.Errorf("failed to delete alert rule: %w", res.Error)
+		}
+		if res.RowsAffected == 0 {
+			return ErrAlertRuleNotFound
+		}
+		return nil
+	})
+}
+
+// ListStates returns pending and firing alerts, plus resolved ones when includeResolved is set.
+func (s *AlertService) ListStates(ctx context.Context, includeResolved bool) ([]models.AlertState, error) {
+	q := s.db.WithContext(ctx).Order("updated_at DESC")
+	if !includeResolved {
+		q = q.Where("status <> ?", models.AlertStatusResolved)
+	}
+	var states []models.AlertState
+	if err := q.Find(&states).Error; err != nil {
+		return nil, fmt.Errorf("failed to list alert states: %w", err)
+	}
+	return states, nil
+}
+
+func applyAlertRuleDto(rule *models.AlertRule, req dto.AlertRuleDto) error {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidAlertRule)
+	}
+
+	target := strings.TrimSpace(req.Target)
+	if target != "" {
+		if _, err := path.Match(target, ""); err != nil {
+			return fmt.Errorf("%w: invalid target pattern: %w", ErrInvalidAlertRule, err)
+		}
+	}
+
+	threshold := req.Threshold
+	switch req.Type {
+	case models.AlertRuleContainerExited, models.AlertRuleContainerUnhealthy, models.AlertRuleEnvironmentOffline:
+	case models.AlertRuleContainerRestartLoop:
+		if threshold <= 0 {
+			threshold = defaultRestartLoopThreshold
+		}
+	case models.AlertRuleCPUHigh, models.AlertRuleMemoryHigh, models.AlertRuleDiskUsage:
+		if threshold <= 0 {
+			return fmt.Errorf("%w: threshold must be a positive percentage", ErrInvalidAlertRule)
+		}
+		if req.Type != models.AlertRuleCPUHigh && threshold > 100 {
+			return fmt.Errorf("%w: threshold cannot exceed 100%%", ErrInvalidAlertRule)
+		}
+	default:
+		return fmt.Errorf("%w: unknown rule type %q", ErrInvalidAlertRule, req.Type)
+	}
+
+	if req.DurationMinutes < 0 {
+		return fmt.Errorf("%w: durationMinutes cannot be negative", ErrInvalidAlertRule)
+	}
+	cooldown := defaultAlertCooldownMinutes
+	if req.CooldownMinutes != nil {
+		if *req.CooldownMinutes < 0 {
+			return fmt.Errorf("%w: cooldownMinutes cannot be negative", ErrInvalidAlertRule)
+		}
+		cooldown = *req.CooldownMinutes
+	}
+
+	rule.Name = name
+	rule.Type = req.Type
+	rule.Target = target
+	rule.Threshold = threshold
+	rule.DurationMinutes = req.DurationMinutes
+	rule.CooldownMinutes = cooldown
+	rule.Enabled = req.Enabled == nil || *req.Enabled
+	rule.NotifyResolved = req.NotifyResolved == nil || *req.NotifyResolved
+	return nil
+}
+
+// Evaluate checks every enabled rule once and sends firing, reminder and resolve notifications.
+func (s *AlertService) Evaluate(ctx context.Context, now time.Time) error {
+	var rules []models.AlertRule
+	if err := s.db.WithContext(ctx).Where("enabled = ?", true).Find(&rules).Error; err != nil {
+		return fmt.Errorf("failed to load alert rules: %w", err)
+	}
+	if len(rules) == 0 {
+		return nil
+	}
+
+	s.pruneRestartHistory(now)
+
+	snap := &alertSnapshot{}
+	for i := range rules {
+		rule := &rules[i]
+		observations, err := s.observe(ctx, rule, snap, now)
+		if err != nil {
+			// Without fresh data we can neither fire nor resolve; keep the current state.
+			slog.WarnContext(ctx, "Skipping alert rule evaluation", "rule", rule.Name, "type", rule.Type, "error", err)
+			continue
+		}
+		if err := s.reconcile(ctx, rule, observations, now); err != nil {
+			slog.ErrorContext(ctx, "Failed to update alert state", "rule", rule.Name, "error", err)
+		}
+	}
+	return nil
+}
+
+func (s *AlertService) observe(ctx context.Context, rule *models.AlertRule, snap *alertSnapshot, now time.Time) (map[string]alertObservation, error) {
+	switch rule.Type {
+	case models.AlertRuleContainerExited, models.AlertRuleContainerUnhealthy:
+		return s.observeContainerState(ctx, rule, snap)
+	case models.AlertRuleContainerRestartLoop:
+		return s.observeRestartLoop(ctx, rule, snap, now)
+	case models.AlertRuleCPUHigh, models.AlertRuleMemoryHigh:
+		return s.observeUsage(ctx, rule, snap, now)
+	case models.AlertRuleDiskUsage:
+		return s.observeDisk(ctx, rule, snap, now)
+	case models.AlertRuleEnvironmentOffline:
+		return s.observeEnvironments(ctx, rule)
+	default:
+		return nil, fmt.Errorf("unknown rule type %q", rule.Type)
+	}
+}
+
+func (s *AlertService) loadContainers(ctx context.Context, snap *alertSnapshot) ([]container.Summary, error) {
+	if !snap.containersLoaded {
+		snap.containersLoaded = true
+		dockerClient, err := s.dockerService.CreateConnection(ctx)
+		if err != nil {
+			snap.containersErr = fmt.Errorf("failed to connect to Docker: %w", err)
+			return nil, snap.containersErr
+		}
+		defer dockerClient.Close()
+		snap.containers, snap.containersErr = dockerClient.ContainerList(ctx, container.ListOptions{All: true})
+	}
+	return snap.containers, snap.containersErr
+}
+
+func (s *AlertService) loadSamples(ctx context.Context, snap *alertSnapshot, now time.Time) (map[string]models.MetricSample, error) {
+	if !snap.samplesLoaded {
+		snap.samplesLoaded = true
+		var rows []models.MetricSample
+		snap.samplesErr = s.db.WithContext(ctx).
+			Where("resolution = ? AND timestamp >= ?", 0, now.UTC().Add(-alertMetricsStaleness)).
+			Order("timestamp ASC").
+			Find(&rows).Error
+		snap.samples = make(map[string]models.MetricSample)
+		for _, row := range rows {
+			key := string(models.MetricScopeHost)
+			if row.Scope == models.MetricScopeContainer {
+				key = "container/" + row.ContainerName
+			}
+			snap.samples[key] = row // ascending order leaves the latest sample per series
+		}
+	}
+	return snap.samples, snap.samplesErr
+}
+
+func containerDisplayName(c container.Summary) string {
+	if len(c.Names) > 0 {
+		return strings.TrimPrefix(c.Names[0], "/")
+	}
+	return c.ID
+}
+
+func alertTargetMatches(pattern, name string) bool {
+	if pattern == "" {
+		return true
+	}
+	ok, _ := path.Match(pattern, name)
+	return ok
+}
+
+func (s *AlertService) observeContainerState(ctx context.Context, rule *models.AlertRule, snap *alertSnapshot) (map[string]alertObservation, error) {
+	containers, err := s.loadContainers(ctx, snap)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(map[string]alertObservation)
+	for _, c := range containers {
+		name := containerDisplayName(c)
+		if !alertTargetMatches(rule.Target, name) {
+			continue
+		}
+		obs := alertObservation{resource: name}
+		switch rule.Type {
+		case models.AlertRuleContainerExited:
+			obs.active = c.State == "exited" || c.State == "dead"
+			obs.message = fmt.Sprintf("Container %s is not running: %s", name, c.Status)
+		case models.AlertRuleContainerUnhealthy:
+			obs.active = strings.Contains(c.Status, "(unhealthy)")
+			obs.message = fmt.Sprintf("Container %s is failing its health check", name)
+		case models.AlertRuleContainerRestartLoop, models.AlertRuleCPUHigh, models.AlertRuleMemoryHigh,
+			models.AlertRuleDiskUsage, models.AlertRuleEnvironmentOffline:
+		}
+		out[name] = obs
+	}
+	return out, nil
+}
+
+// observeRestartLoop fires when a container's restart count grew by at least Threshold
+// within the rule's window (DurationMinutes, default 10). The window replaces the
+// pending delay, so these alerts fire as soon as the loop is detected.
+func (s *AlertService) observeRestartLoop(ctx context.Context, rule *models.AlertRule, snap *alertSnapshot, now time.Time) (map[string]alertObservation, error) {
+	containers, err := s.loadContainers(ctx, snap)
+	if err != nil {
+		return nil, err
+	}
+
+	dockerClient, err := s.dockerService.CreateConnection(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to Docker: %w", err)
+	}
+	defer dockerClient.Close()
+
+	window := time.Duration(rule.DurationMinutes) * time.Minute
+	if window <= 0 {
+		window = defaultRestartLoopWindow
+	}
+
+	out := make(map[string]alertObservation)
+	for _, c := range containers {
+		name := containerDisplayName(c)
+		if !alertTargetMatches(rule.Target, name) {
+			continue
+		}
+		inspect, err := dockerClient.ContainerInspect(ctx, c.ID)
+		if err != nil {
+			slog.DebugContext(ctx, "Failed to inspect container for restart loop check", "container", name, "error", err)
+			continue
+		}
+		restarts := s.recordRestarts(c.ID, inspect.RestartCount, now, window)
+		out[name] = alertObservation{
+			resource: name,
+			active:   float64(restarts) >= rule.Threshold,
+			value:    float64(restarts),
+			message:  fmt.Sprintf("Container %s restarted %d times in the last %s", name, restarts, window),
+		}
+	}
+	return out, nil
+}
+
+// recordRestarts stores the latest restart count and returns how much it grew within window.
+func (s *AlertService) recordRestarts(containerID string, count int, now time.Time, window time.Duration) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	history := append(s.restartHistory[containerID], restartObservation{at: now, count: count})
+	cutoff := now.Add(-window)
+	for len(history) > 1 && history[0].at.Before(cutoff) {
+		history = history[1:]
+	}
+	s.restartHistory[containerID] = history
+
+	delta := count - history[0].count
+	if delta < 0 {
+		// The container was recreated and its counter reset.
+		s.restartHistory[containerID] = history[len(history)-1:]
+		return 0
+	}
+	return delta
+}
+
+// pruneRestartHistory forgets containers that have not been observed for a day, e.g. removed ones.
+func (s *AlertService) pruneRestartHistory(now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, history := range s.restartHistory {
+		if len(history) == 0 || now.Sub(history[len(history)-1].at) > 24*time.Hour {
+			delete(s.restartHistory, id)
+		}
+	}
+}
+
+func (s *AlertService) observeUsage(ctx context.Context, rule *models.AlertRule, snap *alertSnapshot, now time.Time) (map[string]alertObservation, error) {
+	samples, err := s.loadSamples(ctx, snap, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load metric samples: %w", err)
+	}
+
+	label := "CPU"
+	if rule.Type == models.AlertRuleMemoryHigh {
+		label = "Memory"
+	}
+
+	out := make(map[string]alertObservation)
+	observe := func(key, resource string, sample models.MetricSample) {
+		value := sample.CPUPercent
+		if rule.Type == models.AlertRuleMemoryHigh {
+			if sample.MemoryLimit <= 0 {
+				return
+			}
+			value = float64(sample.MemoryUsed) / float64(sample.MemoryLimit) * 100
+		}
+		out[key] = alertObservation{
+			resource: resource,
+			active:   value > rule.Threshold,
+			value:    value,
+			message:  fmt.Sprintf("%s usage of %s is %.1f%% (threshold %.1f%%)", label, resource, value, rule.Threshold),
+		}
+	}
+
+	if rule.Target == "" {
+		if sample, ok := samples[string(models.MetricScopeHost)]; ok {
+			observe(string(models.MetricScopeHost), "host", sample)
+		}
+		return out, nil
+	}
+	for key, sample := range samples {
+		if sample.Scope != models.MetricScopeContainer || !alertTargetMatches(rule.Target, sample.ContainerName) {
+			continue
+		}
+		observe(key, sample.ContainerName, sample)
+	}
+	return out, nil
+}
+
+func (s *AlertService) observeDisk(ctx context.Context, rule *models.AlertRule, snap *alertSnapshot, now time.Time) (map[string]alertObservation, error) {
+	samples, err := s.loadSamples(ctx, snap, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load metric samples: %w", err)
+	}
+
+	out := make(map[string]alertObservation)
+	sample, ok := samples[string(models.MetricScopeHost)]
+	if !ok || sample.DiskTotal <= 0 {
+		return out, nil
+	}
+	value := float64(sample.DiskUsed) / float64(sample.DiskTotal) * 100
+	out[string(models.MetricScopeHost)] = alertObservation{
+		resource: "host",
+		active:   value > rule.Threshold,
+		value:    value,
+		message:  fmt.Sprintf("Disk usage is %.1f%% (threshold %.1f%%)", value, rule.Threshold),
+	}
+	return out, nil
+}
+
+func (s *AlertService) observeEnvironments(ctx context.Context, rule *models.AlertRule) (map[string]alertObservation, error) {
+	var environments []models.Environment
+	q := s.db.WithContext(ctx).Where("enabled = ?", true)
+	if rule.Target != "" {
+		q = q.Where("id = ?", rule.Target)
+	}
+	if err := q.Find(&environments).Error; err != nil {
+		return nil, fmt.Errorf("failed to list environments: %w", err)
+	}
+
+	out := make(map[string]alertObservation)
+	for _, env := range environments {
+		out[env.ID] = alertObservation{
+			resource: env.Name,
+			active:   env.Status != string(models.EnvironmentStatusOnline),
+			message:  fmt.Sprintf("Environment %s is %s", env.Name, env.Status),
+		}
+	}
+	return out, nil
+}
+
+// reconcile advances the stored state of every resource the rule watches and sends the
+// notifications those transitions call for.
+func (s *AlertService) reconcile(ctx context.Context, rule *models.AlertRule, observations map[string]alertObservation, now time.Time) error {
+	var existing []models.AlertState
+	if err := s.db.WithContext(ctx).Where("rule_id = ?", rule.ID).Find(&existing).Error; err != nil {
+		return fmt.Errorf("failed to load alert states: %w", err)
+	}
+	states := make(map[string]*models.AlertState, len(existing))
+	for i := range existing {
+		states[existing[i].Key] = &existing[i]
+	}
+
+	pendingFor := time.Duration(rule.DurationMinutes) * time.Minute
+	if rule.Type == models.AlertRuleContainerRestartLoop {
+		pendingFor = 0
+	}
+	cooldown := time.Duration(rule.CooldownMinutes) * time.Minute
+
+	for key, obs := range observations {
+		if !obs.active {
+			continue
+		}
+		state := states[key]
+		if state == nil {
+			state = &models.AlertState{RuleID: rule.ID, Key: key}
+			states[key] = state
+		}
+		transitioned := false
+		if state.Status == "" || state.Status == models.AlertStatusResolved {
+			state.Status = models.AlertStatusPending
+			state.PendingSince = &now
+			state.FiredAt = nil
+			state.ResolvedAt = nil
+		}
+		state.Value = obs.value
+		state.Message = obs.message
+
+		if state.Status == models.AlertStatusPending && state.PendingSince != nil && now.Sub(*state.PendingSince) >= pendingFor {
+			state.Status = models.AlertStatusFiring
+			state.FiredAt = &now
+			transitioned = true
+		}
+
+		if state.Status == models.AlertStatusFiring {
+			due := state.LastNotifiedAt == nil ||
+				(transitioned && (cooldown == 0 || now.Sub(*state.LastNotifiedAt) >= cooldown)) ||
+				(!transitioned && cooldown > 0 && now.Sub(*state.LastNotifiedAt) >= cooldown)
+			if due {
+				s.notify(ctx, rule, obs, models.AlertStatusFiring)
+				state.LastNotifiedAt = &now
+			}
+		}
+
+		if err := s.db.WithContext(ctx).Save(state).Error; err != nil {
+			return fmt.Errorf("failed to save alert state: %w", err)
+		}
+	}
+
+	for key, state := range states {
+		if obs, ok := observations[key]; ok && obs.active {
+			continue
+		}
+		switch state.Status {
+		case models.AlertStatusPending:
+			// Never fired, so there is nothing to resolve.
+			if err := s.db.WithContext(ctx).Delete(state).Error; err != nil {
+				return fmt.Errorf("failed to clear pending alert: %w", err)
+			}
+		case models.AlertStatusFiring:
+			notified := state.LastNotifiedAt != nil && state.FiredAt != nil && !state.LastNotifiedAt.Before(*state.FiredAt)
+			state.Status = models.AlertStatusResolved
+			state.ResolvedAt = &now
+			if rule.NotifyResolved && notified {
+				obs, ok := observations[key]
+				if !ok {
+					obs = alertObservation{resource: key, message: "The resource is no longer present"}
+				}
+				obs.value = state.Value
+				s.notify(ctx, rule, obs, models.AlertStatusResolved)
+				state.LastNotifiedAt = &now
+			}
+			if err := s.db.WithContext(ctx).Save(state).Error; err != nil {
+				return fmt.Errorf("failed to save alert state: %w", err)
+			}
+		case models.AlertStatusResolved:
+		}
+	}
+	return nil
+}
+
+func (s *AlertService) notify(ctx context.Context, rule *models.AlertRule, obs alertObservation, status models.AlertStatus) {
+	if s.notificationService == nil {
+		return
+	}
+	alert := AlertNotification{
+		RuleName:  rule.Name,
+		RuleType:  rule.Type,
+		Resource:  obs.resource,
+		Status:    status,
+		Message:   obs.message,
+		Value:     formatAlertValue(rule, obs.value),
+		Threshold: formatAlertThreshold(rule),
+	}
+	if err := s.notificationService.SendAlertNotification(ctx, alert); err != nil {
+		slog.WarnContext(ctx, "Failed to send alert notification", "rule", rule.Name, "resource", obs.resource, "status", status, "error", err)
+	}
+}
+
+func formatAlertValue(rule *models.AlertRule, value float64) string {
+	switch rule.Type {
+	case models.AlertRuleCPUHigh, models.AlertRuleMemoryHigh, models.AlertRuleDiskUsage:
+		return fmt.Sprintf("%.1f%%", value)
+	case models.AlertRuleContainerRestartLoop:
+		return fmt.Sprintf("%.0f restarts", value)
+	case models.AlertRuleContainerExited, models.AlertRuleContainerUnhealthy, models.AlertRuleEnvironmentOffline:
+	}
+	return "-"
+}
+
+func formatAlertThreshold(rule *models.AlertRule) string {
+	switch rule.Type {
+	case models.AlertRuleCPUHigh, models.AlertRuleMemoryHigh, models.AlertRuleDiskUsage:
+		if rule.DurationMinutes > 0 {
+			return fmt.Sprintf("%.1f%% for %d min", rule.Threshold, rule.DurationMinutes)
+		}
+		return fmt.Sprintf("%.1f%%", rule.Threshold)
+	case models.AlertRuleContainerRestartLoop:
+		window := rule.DurationMinutes
+		if window <= 0 {
+			window = int(defaultRestartLoopWindow / time.Minute)
+		}
+		return fmt.Sprintf("%.0f restarts in %d min", rule.Threshold, window)
+	case models.AlertRuleContainerExited, models.AlertRuleContainerUnhealthy, models.AlertRuleEnvironmentOffline:
+		if rule.DurationMinutes > 0 {
+			return fmt.Sprintf("for %d min", rule.DurationMinutes)
+		}
+		return "-"
+	default:
+		return "-"
+	}
+}
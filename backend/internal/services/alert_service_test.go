@@ -0,0 +1,124 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ofkm/arcane-backend/internal/dto"
+	"github.com/ofkm/arcane-backend/internal/models"
+)
+
+func setupAlertService(t *testing.T) *AlertService {
+	t.Helper()
+	db := setupSettingsTestDB(t)
+	require.NoError(t, db.AutoMigrate(&models.AlertRule{}, &models.AlertState{}, &models.MetricSample{}, &models.Environment{}))
+	return NewAlertService(db, nil, nil)
+}
+
+func TestAlertService_CPUHighLifecycle(t *testing.T) {
+	ctx := context.Background()
+	svc := setupAlertService(t)
+
+	rule, err := svc.CreateRule(ctx, dto.AlertRuleDto{Name: "Host CPU", Type: models.AlertRuleCPUHigh, Threshold: 80, DurationMinutes: 5})
+	require.NoError(t, err)
+	require.Equal(t, 60, rule.CooldownMinutes)
+
+	start := time.Date(2025, 6, 10, 12, 0, 0, 0, time.UTC)
+	addHostSample := func(at time.Time, cpu float64) {
+		require.NoError(t, svc.db.Create(&models.MetricSample{Scope: models.MetricScopeHost, Timestamp: at, SampleCount: 1, CPUPercent: cpu}).Error)
+	}
+
+	addHostSample(start, 95)
+	require.NoError(t, svc.Evaluate(ctx, start))
+	states, err := svc.ListStates(ctx, false)
+	require.NoError(t, err)
+	require.Len(t, states, 1)
+	require.Equal(t, models.AlertStatusPending, states[0].Status)
+
+	fired := start.Add(5 * time.Minute)
+	addHostSample(fired, 92)
+	require.NoError(t, svc.Evaluate(ctx, fired))
+	states, err = svc.ListStates(ctx, false)
+	require.NoError(t, err)
+	require.Len(t, states, 1)
+	require.Equal(t, models.AlertStatusFiring, states[0].Status)
+	require.NotNil(t, states[0].LastNotifiedAt)
+	require.InDelta(t, 92, states[0].Value, 0.001)
+
+	recovered := fired.Add(time.Minute)
+	addHostSample(recovered, 10)
+	require.NoError(t, svc.Evaluate(ctx, recovered))
+	states, err = svc.ListStates(ctx, false)
+	require.NoError(t, err)
+	require.Empty(t, states)
+
+	states, err = svc.ListStates(ctx, true)
+	require.NoError(t, err)
+	require.Len(t, states, 1)
+	require.Equal(t, models.AlertStatusResolved, states[0].Status)
+	require.NotNil(t, states[0].ResolvedAt)
+}
+
+func TestAlertService_PendingAlertClearsWithoutFiring(t *testing.T) {
+	ctx := context.Background()
+	svc := setupAlertService(t)
+
+	_, err := svc.CreateRule(ctx, dto.AlertRuleDto{Name: "Host CPU", Type: models.AlertRuleCPUHigh, Threshold: 80, DurationMinutes: 5})
+	require.NoError(t, err)
+
+	start := time.Date(2025, 6, 10, 12, 0, 0, 0, time.UTC)
+	require.NoError(t, svc.db.Create(&models.MetricSample{Scope: models.MetricScopeHost, Timestamp: start, SampleCount: 1, CPUPercent: 95}).Error)
+	require.NoError(t, svc.Evaluate(ctx, start))
+	require.NoError(t, svc.db.Create(&models.MetricSample{Scope: models.MetricScopeHost, Timestamp: start.Add(time.Minute), SampleCount: 1, CPUPercent: 20}).Error)
+	require.NoError(t, svc.Evaluate(ctx, start.Add(time.Minute)))
+
+	states, err := svc.ListStates(ctx, true)
+	require.NoError(t, err)
+	require.Empty(t, states)
+}
+
+func TestAlertService_EnvironmentOffline(t *testing.T) {
+	ctx := context.Background()
+	svc := setupAlertService(t)
+
+	env := models.Environment{Name: "edge", Status: string(models.EnvironmentStatusOffline), Enabled: true}
+	require.NoError(t, svc.db.Create(&env).Error)
+
+	_, err := svc.CreateRule(ctx, dto.AlertRuleDto{Name: "Offline", Type: models.AlertRuleEnvironmentOffline})
+	require.NoError(t, err)
+
+	now := time.Date(2025, 6, 10, 12, 0, 0, 0, time.UTC)
+	require.NoError(t, svc.Evaluate(ctx, now))
+	states, err := svc.ListStates(ctx, false)
+	require.NoError(t, err)
+	require.Len(t, states, 1)
+	require.Equal(t, models.AlertStatusFiring, states[0].Status)
+	require.Equal(t, env.ID, states[0].Key)
+
+	require.NoError(t, svc.db.Model(&env).Update("status", string(models.EnvironmentStatusOnline)).Error)
+	require.NoError(t, svc.Evaluate(ctx, now.Add(time.Minute)))
+	states, err = svc.ListStates(ctx, false)
+	require.NoError(t, err)
+	require.Empty(t, states)
+}
+
+func TestAlertService_RuleValidation(t *testing.T) {
+	ctx := context.Background()
+	svc := setupAlertService(t)
+
+	_, err := svc.CreateRule(ctx, dto.AlertRuleDto{Name: "bad", Type: "unknown"})
+	require.ErrorIs(t, err, ErrInvalidAlertRule)
+
+	_, err = svc.CreateRule(ctx, dto.AlertRuleDto{Name: "bad", Type: models.AlertRuleMemoryHigh, Threshold: 150})
+	require.ErrorIs(t, err, ErrInvalidAlertRule)
+
+	_, err = svc.CreateRule(ctx, dto.AlertRuleDto{Name: "bad", Type: models.AlertRuleDiskUsage})
+	require.ErrorIs(t, err, ErrInvalidAlertRule)
+
+	_, err = svc.UpdateRule(ctx, "missing", dto.AlertRuleDto{Name: "x", Type: models.AlertRuleContainerExited})
+	require.ErrorIs(t, err, ErrAlertRuleNotFound)
+	require.ErrorIs(t, svc.DeleteRule(ctx, "missing"), ErrAlertRuleNotFound)
+}
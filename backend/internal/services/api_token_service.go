@@ -0,0 +1,166 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/ofkm/arcane-backend/internal/database"
+	"github.com/ofkm/arcane-backend/internal/dto"
+	"github.com/ofkm/arcane-backend/internal/models"
+)
+
+const (
+	// ApiTokenPrefix marks personal access tokens so they can be told apart from JWTs
+	// in the Authorization header.
+	ApiTokenPrefix = "arc_"
+
+	apiTokenDisplayLength = 12
+	// Only persist last-used timestamps this often to avoid a write per request.
+	apiTokenLastUsedInterval = time.Minute
+)
+
+var (
+	ErrApiTokenNotFound = errors.New("api token not found")
+	ErrApiTokenExpired  = errors.New("api token expired")
+	ErrApiTokenInvalid  = errors.New("invalid api token")
+	ErrApiTokenRequest  = errors.New("invalid api token request")
+)
+
+type ApiTokenService struct {
+	db          *database.DB
+	userService *UserService
+	audit       *AuditService
+}
+
+func NewApiTokenService(db *database.DB, userService *UserService, audit *AuditService) *ApiTokenService {
+	return &ApiTokenService{db: db, userService: userService, audit: audit}
+}
+
+func IsApiToken(raw string) bool {
+	return strings.HasPrefix(raw, ApiTokenPrefix)
+}
+
+func (s *ApiTokenService) ListTokens(ctx context.Context, userID string) ([]models.ApiToken, error) {
+	var tokens []models.ApiToken
+	if err := s.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at DESC").Find(&tokens).Error; err != nil {
+		return nil, fmt.Errorf("failed to list api tokens: %w", err)
+	}
+	return tokens, nil
+}
+
+// CreateToken stores a new token for the user and returns it together with the plaintext
+// value. Only the SHA-256 hash is persisted, so the plaintext cannot be recovered later.
+func (s *ApiTokenService) CreateToken(ctx context.Context, userID string, req dto.CreateApiTokenDto) (*models.ApiToken, string, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, "", fmt.Errorf("%w: token name is required", ErrApiTokenRequest)
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return nil, "", fmt.Errorf("%w: expiry must be in the future", ErrApiTokenRequest)
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	raw := ApiTokenPrefix + base64.RawURLEncoding.EncodeToString(secret)
+
+	token := &models.ApiToken{
+		UserID:    userID,
+		Name:      name,
+		TokenHash: hashApiToken(raw),
+		Prefix:    raw[:apiTokenDisplayLength],
+		Scopes:    normalizePermissions(req.Scopes),
+		ExpiresAt: req.ExpiresAt,
+	}
+	if err := s.db.WithContext(ctx).Create(token).Error; err != nil {
+		return nil, "", fmt.Errorf("failed to create api token: %w", err)
+	}
+
+	s.audit.Record(ctx, AuditChange{
+		Action:     auditActionCreate,
+		TargetType: auditTargetApiToken,
+		TargetID:   token.ID,
+		TargetName: token.Name,
+		After:      token,
+	})
+
+	return token, raw, nil
+}
+
+func (s *ApiTokenService) DeleteToken(ctx context.Context, userID, tokenID string) error {
+	var before models.ApiToken
+	if err := s.db.WithContext(ctx).Where("id = ? AND user_id = ?", tokenID, userID).First(&before).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrApiTokenNotFound
+		}
+		return fmt.Errorf("failed to get api token: %w", err)
+	}
+
+	result := s.db.WithContext(ctx).Where("id = ? AND user_id = ?", tokenID, userID).Delete(&models.ApiToken{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete api token: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrApiTokenNotFound
+	}
+
+	s.audit.Record(ctx, AuditChange{
+		Action:     auditActionDelete,
+		TargetType: auditTargetApiToken,
+		TargetID:   tokenID,
+		TargetName: before.Name,
+		Before:     &before,
+	})
+	return nil
+}
+
+// Authenticate resolves a plaintext token to its owner. The returned user is loaded from
+// the database so role changes take effect immediately.
+func (s *ApiTokenService) Authenticate(ctx context.Context, raw string) (*models.User, *models.ApiToken, error) {
+	if !IsApiToken(raw) {
+		return nil, nil, ErrApiTokenInvalid
+	}
+
+	var token models.ApiToken
+	if err := s.db.WithContext(ctx).Where("token_hash = ?", hashApiToken(raw)).First(&token).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, ErrApiTokenInvalid
+		}
+		return nil, nil, fmt.Errorf("failed to look up api token: %w", err)
+	}
+
+	now := time.Now()
+	if token.IsExpired(now) {
+		return nil, nil, ErrApiTokenExpired
+	}
+
+	user, err := s.userService.GetUserByID(ctx, token.UserID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) >= apiTokenLastUsedInterval {
+		token.LastUsedAt = &now
+		if err := s.db.WithContext(ctx).Model(&models.ApiToken{}).Where("id = ?", token.ID).Update("last_used_at", now).Error; err != nil {
+			slog.WarnContext(ctx, "Failed to update api token last used time", "token", token.Prefix, "error", err)
+		}
+	}
+
+	return user, &token, nil
+}
+
+func hashApiToken(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
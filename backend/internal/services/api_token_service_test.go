@@ -0,0 +1,71 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	glsqlite "github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/ofkm/arcane-backend/internal/database"
+	"github.com/ofkm/arcane-backend/internal/dto"
+	"github.com/ofkm/arcane-backend/internal/models"
+	"github.com/ofkm/arcane-backend/internal/utils"
+)
+
+func setupApiTokenTestDB(t *testing.T) *database.DB {
+	t.Helper()
+	db, err := gorm.Open(glsqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.User{}, &models.ApiToken{}))
+	return &database.DB{DB: db}
+}
+
+func TestApiTokenService_CreateAndAuthenticate(t *testing.T) {
+	ctx := context.Background()
+	db := setupApiTokenTestDB(t)
+	svc := NewApiTokenService(db, NewUserService(db, nil), nil)
+
+	user := &models.User{Username: "ci", Roles: models.StringSlice{models.RoleUser}}
+	require.NoError(t, db.Create(user).Error)
+
+	token, raw, err := svc.CreateToken(ctx, user.ID, dto.CreateApiTokenDto{Name: "deploy", Scopes: []string{" Projects:Deploy "}})
+	require.NoError(t, err)
+	require.True(t, IsApiToken(raw))
+	require.NotEqual(t, raw, token.TokenHash, "plaintext must not be stored")
+	require.Equal(t, models.StringSlice{"projects:deploy"}, token.Scopes)
+
+	got, authed, err := svc.Authenticate(ctx, raw)
+	require.NoError(t, err)
+	require.Equal(t, user.ID, got.ID)
+	require.Equal(t, token.ID, authed.ID)
+	require.NotNil(t, authed.LastUsedAt)
+
+	_, _, err = svc.Authenticate(ctx, raw+"x")
+	require.ErrorIs(t, err, ErrApiTokenInvalid)
+
+	require.NoError(t, svc.DeleteToken(ctx, user.ID, token.ID))
+	_, _, err = svc.Authenticate(ctx, raw)
+	require.ErrorIs(t, err, ErrApiTokenInvalid)
+}
+
+func TestApiTokenService_Expiry(t *testing.T) {
+	ctx := context.Background()
+	db := setupApiTokenTestDB(t)
+	svc := NewApiTokenService(db, NewUserService(db, nil), nil)
+
+	user := &models.User{Username: "ops"}
+	require.NoError(t, db.Create(user).Error)
+
+	_, _, err := svc.CreateToken(ctx, user.ID, dto.CreateApiTokenDto{Name: "old", ExpiresAt: utils.Ptr(time.Now().Add(-time.Hour))})
+	require.ErrorIs(t, err, ErrApiTokenRequest)
+
+	token, raw, err := svc.CreateToken(ctx, user.ID, dto.CreateApiTokenDto{Name: "short", ExpiresAt: utils.Ptr(time.Now().Add(time.Hour))})
+	require.NoError(t, err)
+	require.NoError(t, db.Model(token).Update("expires_at", time.Now().Add(-time.Minute)).Error)
+
+	_, _, err = svc.Authenticate(ctx, raw)
+	require.ErrorIs(t, err, ErrApiTokenExpired)
+}
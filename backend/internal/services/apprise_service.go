@@ -19,12 +19,14 @@ import (
 type AppriseService struct {
 	db     *database.DB
 	config *config.Config
+	audit  *AuditService
 }
 
-func NewAppriseService(db *database.DB, cfg *config.Config) *AppriseService {
+func NewAppriseService(db *database.DB, cfg *config.Config, audit *AuditService) *AppriseService {
 	return &AppriseService{
 		db:     db,
 		config: cfg,
+		audit:  audit,
 	}
 }
 
@@ -44,7 +46,13 @@ func (s *AppriseService) GetSettings(ctx context.Context) (*models.AppriseSettin
 	return &settings, nil
 }
 
-func (s *AppriseService) CreateOrUpdateSettings(ctx context.Context, apiURL string, enabled bool, imageUpdateTag, containerUpdateTag string) (*models.AppriseSettings, error) {
+func (s *AppriseService) CreateOrUpdateSettings(ctx context.Context, apiURL string, enabled bool, imageUpdateTag, containerUpdateTag string, delivery models.JSON) (*models.AppriseSettings, error) {
+	if len(delivery) > 0 {
+		if err := validateDeliveryConfig(models.JSON{"delivery": delivery}); err != nil {
+			return nil, err
+		}
+	}
+
 	var settings models.AppriseSettings
 
 	err := s.db.WithContext(ctx).First(&settings).Error
@@ -54,18 +62,36 @@ func (s *AppriseService) CreateOrUpdateSettings(ctx context.Context, apiURL stri
 			Enabled:            enabled,
 			ImageUpdateTag:     imageUpdateTag,
 			ContainerUpdateTag: containerUpdateTag,
+			Delivery:           delivery,
 		}
 		if err := s.db.WithContext(ctx).Create(&settings).Error; err != nil {
 			return nil, fmt.Errorf("failed to create apprise settings: %w", err)
 		}
+
+		s.audit.Record(ctx, AuditChange{
+			Action:     auditActionCreate,
+			TargetType: auditTargetApprise,
+			TargetName: "apprise",
+			After:      &settings,
+		})
 	} else {
+		before := settings
 		settings.APIURL = apiURL
 		settings.Enabled = enabled
 		settings.ImageUpdateTag = imageUpdateTag
 		settings.ContainerUpdateTag = containerUpdateTag
+		settings.Delivery = delivery
 		if err := s.db.WithContext(ctx).Save(&settings).Error; err != nil {
 			return nil, fmt.Errorf("failed to update apprise settings: %w", err)
 		}
+
+		s.audit.Record(ctx, AuditChange{
+			Action:     auditActionUpdate,
+			TargetType: auditTargetApprise,
+			TargetName: "apprise",
+			Before:     &before,
+			After:      &settings,
+		})
 	}
 
 	return &settings, nil
@@ -91,10 +117,11 @@ func (s *AppriseService) SendNotification(ctx context.Context, title, body, form
 		if settings.ImageUpdateTag != "" {
 			tags = []string{settings.ImageUpdateTag}
 		}
-	case models.NotificationEventContainerUpdate:
+	case models.NotificationEventContainerUpdate, models.NotificationEventContainerRollback:
 		if settings.ContainerUpdateTag != "" {
 			tags = []string{settings.ContainerUpdateTag}
 		}
+	case models.NotificationEventAlert:
 	}
 
 	payload := AppriseNotificationPayload{
@@ -157,6 +184,17 @@ func (s *AppriseService) SendImageUpdateNotification(ctx context.Context, imageR
 		truncateDigest(updateInfo.CurrentDigest),
 		truncateDigest(updateInfo.LatestDigest),
 	)
+	if version := updateVersion(updateInfo); version != "" {
+		body += "\nNew Version: " + version
+	}
+	if rel := updateInfo.Release; rel != nil {
+		if link := releaseLink(rel); link != "" {
+			body += "\nChangelog: " + link
+		}
+		if rel.Notes != "" {
+			body += "\n\n" + rel.Notes
+		}
+	}
 	return s.SendNotification(ctx, title, body, "text", models.NotificationEventImageUpdate)
 }
 
@@ -172,6 +210,38 @@ func (s *AppriseService) SendContainerUpdateNotification(ctx context.Context, co
 	return s.SendNotification(ctx, title, body, "text", models.NotificationEventContainerUpdate)
 }
 
+func (s *AppriseService) SendContainerRollbackNotification(ctx context.Context, containerName, imageRef, failedDigest, restoredDigest, reason string, rolledBack bool) error {
+	title := fmt.Sprintf("Container Rolled Back: %s", containerName)
+	status := "Rolled back to previous image"
+	if !rolledBack {
+		title = fmt.Sprintf("Container Update Failed: %s", containerName)
+		status = "Rollback failed, manual action required"
+	}
+	body := fmt.Sprintf(
+		"Container: %s\nImage: %s\nFailed Version: %s\nRestored Version: %s\nReason: %s\nStatus: %s",
+		containerName,
+		imageRef,
+		truncateDigest(failedDigest),
+		truncateDigest(restoredDigest),
+		reason,
+		status,
+	)
+	return s.SendNotification(ctx, title, body, "text", models.NotificationEventContainerRollback)
+}
+
+func (s *AppriseService) SendAlertNotification(ctx context.Context, alert AlertNotification) error {
+	title := alertTitle(alert)
+	body := fmt.Sprintf(
+		"%s\nResource: %s\nStatus: %s\nValue: %s\nThreshold: %s",
+		alert.Message,
+		alert.Resource,
+		alert.Status,
+		alert.Value,
+		alert.Threshold,
+	)
+	return s.SendNotification(ctx, title, body, "text", models.NotificationEventAlert)
+}
+
 func (s *AppriseService) SendBatchImageUpdateNotification(ctx context.Context, updates map[string]*dto.ImageUpdateResponse) error {
 	if len(updates) == 0 {
 		return nil
@@ -0,0 +1,429 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ofkm/arcane-backend/internal/database"
+	"github.com/ofkm/arcane-backend/internal/models"
+	"github.com/ofkm/arcane-backend/internal/utils/pagination"
+	"gorm.io/gorm"
+)
+
+const (
+	auditActionCreate = "create"
+	auditActionUpdate = "update"
+	auditActionDelete = "delete"
+
+	auditTargetSettings          = "settings"
+	auditTargetUser              = "user"
+	auditTargetContainerRegistry = "container_registry"
+	auditTargetEnvironment       = "environment"
+	auditTargetTemplate          = "template"
+	auditTargetTemplateRegistry  = "template_registry"
+	auditTargetTemplateVariables = "template_variables"
+	auditTargetProject           = "project"
+	auditTargetNotification      = "notification"
+	auditTargetApprise           = "apprise"
+	auditTargetNotificationSub   = "notification_subscription"
+	auditTargetRole              = "role"
+	auditTargetRoleBinding       = "role_binding"
+	auditTargetApiToken          = "api_token"
+	auditTargetOidcProvider      = "oidc_provider"
+	auditTargetOidcRoleMapping   = "oidc_role_mapping"
+	auditTargetBackupTarget      = "backup_target"
+	auditTargetBackupSchedule    = "backup_schedule"
+
+	auditExportLimit = 10000
+
+	// MinAuditLogRetentionDays keeps a misconfigured retention setting from wiping
+	// recent history; 0 disables cleanup entirely.
+	MinAuditLogRetentionDays = 30
+)
+
+var (
+	ErrInvalidAuditExportFormat = errors.New("export format must be csv or json")
+	ErrInvalidAuditFilter       = errors.New("invalid audit log filter")
+)
+
+// Fields that change on every save and carry no information about what an admin did.
+var auditIgnoredFields = map[string]struct{}{
+	"createdAt": {},
+	"updatedAt": {},
+	"lastLogin": {},
+}
+
+// Field names containing any of these are always redacted, whatever the caller declares.
+var auditSensitiveFragments = []string{"password", "secret", "token", "apikey", "privatekey", "credential", "webhookurl"}
+
+// AuditActor identifies who made a change. The auth middleware attaches it to the
+// request context; changes made without one (jobs, startup) are recorded as system.
+type AuditActor struct {
+	UserID    string
+	Username  string
+	SourceIP  string
+	UserAgent string
+}
+
+type auditActorKey struct{}
+
+func WithAuditActor(ctx context.Context, actor AuditActor) context.Context {
+	return context.WithValue(ctx, auditActorKey{}, actor)
+}
+
+func auditActorFromContext(ctx context.Context) (AuditActor, bool) {
+	actor, ok := ctx.Value(auditActorKey{}).(AuditActor)
+	return actor, ok
+}
+
+// AuditChange describes one change to record. Before is nil for creates and After is
+// nil for deletes; both are marshalled to JSON and compared field by field. Sensitive
+// lists extra dotted field paths (or path prefixes) whose values must never be stored.
+type AuditChange struct {
+	Action     string
+	TargetType string
+	TargetID   string
+	TargetName string
+	Before     any
+	After      any
+	Sensitive  []string
+}
+
+type AuditService struct {
+	db *database.DB
+
+	// OnRecorded is called after every stored entry; event sinks use it to export the
+	// audit trail. It must not block.
+	OnRecorded func(ctx context.Context, entry models.AuditLog)
+}
+
+func NewAuditService(db *database.DB) *AuditService {
+	return &AuditService{db: db}
+}
+
+// Record stores an audit entry for change. Auditing never fails the change it
+// describes, so errors are logged rather than returned. Updates that leave every
+// audited field untouched are not recorded. A nil service records nothing.
+func (s *AuditService) Record(ctx context.Context, change AuditChange) {
+	if s == nil {
+		return
+	}
+
+	changes, err := auditDiff(change.Before, change.After, change.Sensitive)
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to compute audit diff", "action", change.Action, "target", change.TargetType, "error", err)
+		return
+	}
+	if change.Before != nil && change.After != nil && len(changes) == 0 {
+		return
+	}
+
+	entry := models.AuditLog{
+		Timestamp:  time.Now(),
+		Action:     change.TargetType + "." + change.Action,
+		TargetType: change.TargetType,
+		TargetID:   auditOptional(change.TargetID),
+		TargetName: auditOptional(change.TargetName),
+		Changes:    changes,
+	}
+	if actor, ok := auditActorFromContext(ctx); ok {
+		entry.UserID = auditOptional(actor.UserID)
+		entry.Username = auditOptional(actor.Username)
+		entry.SourceIP = auditOptional(actor.SourceIP)
+		entry.UserAgent = auditOptional(actor.UserAgent)
+	} else {
+		entry.Username = auditOptional("system")
+	}
+
+	if err := s.db.WithContext(ctx).Create(&entry).Error; err != nil {
+		slog.ErrorContext(ctx, "Failed to record audit log entry", "action", entry.Action, "targetId", change.TargetID, "error", err)
+		return
+	}
+
+	if s.OnRecorded != nil {
+		s.OnRecorded(ctx, entry)
+	}
+}
+
+func (s *AuditService) ListAuditLogsPaginated(ctx context.Context, params pagination.QueryParams) ([]models.AuditLog, pagination.Response, error) {
+	q, err := s.filteredQuery(ctx, params)
+	if err != nil {
+		return nil, pagination.Response{}, err
+	}
+
+	var entries []models.AuditLog
+	paginationResp, err := pagination.PaginateAndSortDB(params.WithDefaultSort("timestamp", pagination.SortDesc), q, &entries)
+	if err != nil {
+		return nil, pagination.Response{}, fmt.Errorf("failed to paginate audit logs: %w", err)
+	}
+
+	return entries, paginationResp, nil
+}
+
+// ExportAuditLogs renders the entries matching params' filters, newest first, as csv
+// or json. It returns the content type alongside the body.
+func (s *AuditService) ExportAuditLogs(ctx context.Context, params pagination.QueryParams, format string) ([]byte, string, error) {
+	if format != "csv" && format != "json" {
+		return nil, "", ErrInvalidAuditExportFormat
+	}
+
+	q, err := s.filteredQuery(ctx, params)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var entries []models.AuditLog
+	if err := q.Order("timestamp DESC").Limit(auditExportLimit).Find(&entries).Error; err != nil {
+		return nil, "", fmt.Errorf("failed to load audit logs: %w", err)
+	}
+
+	if format == "json" {
+		out, err := json.MarshalIndent(entries, "", "  ")
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to encode audit logs: %w", err)
+		}
+		return out, "application/json", nil
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	_ = w.Write([]string{"timestamp", "username", "userId", "sourceIp", "userAgent", "action", "targetType", "targetId", "targetName", "changes"})
+	for _, e := range entries {
+		changes, _ := json.Marshal(e.Changes)
+		row := []string{
+			e.Timestamp.UTC().Format(time.RFC3339),
+			auditValue(e.Username),
+			auditValue(e.UserID),
+			auditValue(e.SourceIP),
+			auditValue(e.UserAgent),
+			e.Action,
+			e.TargetType,
+			auditValue(e.TargetID),
+			auditValue(e.TargetName),
+			string(changes),
+		}
+		for i := range row {
+			row[i] = csvSafeCell(row[i])
+		}
+		_ = w.Write(row)
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, "", fmt.Errorf("failed to encode audit logs: %w", err)
+	}
+
+	return buf.Bytes(), "text/csv", nil
+}
+
+// DeleteAuditLogsOlderThan enforces the retention policy. It is the only code path
+// that removes audit entries.
+func (s *AuditService) DeleteAuditLogsOlderThan(ctx context.Context, olderThan time.Duration) (int64, error) {
+	cutoff := time.Now().Add(-olderThan)
+	result := s.db.WithContext(ctx).Where("timestamp < ?", cutoff).Delete(&models.AuditLog{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to delete old audit logs: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+func (s *AuditService) filteredQuery(ctx context.Context, params pagination.QueryParams) (*gorm.DB, error) {
+	q := s.db.WithContext(ctx).Model(&models.AuditLog{})
+
+	if term := strings.TrimSpace(params.Search); term != "" {
+		searchPattern := "%" + term + "%"
+		q = q.Where(
+			"action LIKE ? OR COALESCE(target_name, '') LIKE ? OR COALESCE(username, '') LIKE ? OR COALESCE(source_ip, '') LIKE ?",
+			searchPattern, searchPattern, searchPattern, searchPattern,
+		)
+	}
+
+	if action := params.Filters["action"]; action != "" {
+		q = q.Where("action = ?", action)
+	}
+	if targetType := params.Filters["targetType"]; targetType != "" {
+		q = q.Where("target_type = ?", targetType)
+	}
+	if targetID := params.Filters["targetId"]; targetID != "" {
+		q = q.Where("target_id = ?", targetID)
+	}
+	if userID := params.Filters["userId"]; userID != "" {
+		q = q.Where("user_id = ?", userID)
+	}
+	if username := params.Filters["username"]; username != "" {
+		q = q.Where("username = ?", username)
+	}
+	if from := params.Filters["from"]; from != "" {
+		t, err := time.Parse(time.RFC3339, from)
+		if err != nil {
+			return nil, fmt.Errorf("%w: from must be RFC3339, got %q", ErrInvalidAuditFilter, from)
+		}
+		q = q.Where("timestamp >= ?", t)
+	}
+	if to := params.Filters["to"]; to != "" {
+		t, err := time.Parse(time.RFC3339, to)
+		if err != nil {
+			return nil, fmt.Errorf("%w: to must be RFC3339, got %q", ErrInvalidAuditFilter, to)
+		}
+		q = q.Where("timestamp <= ?", t)
+	}
+
+	return q, nil
+}
+
+// auditDiff flattens before and after into dotted JSON paths and returns
+// {path: {"before": ..., "after": ...}} for every path whose value differs. Arrays are
+// compared as a whole. Sensitive values are replaced by the redaction mask after
+// comparison, so a changed secret still shows up without being stored.
+func auditDiff(before, after any, sensitive []string) (models.JSON, error) {
+	beforeFields, err := auditFlatten(before)
+	if err != nil {
+		return nil, err
+	}
+	afterFields, err := auditFlatten(after)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make(map[string]struct{}, len(beforeFields)+len(afterFields))
+	for p := range beforeFields {
+		paths[p] = struct{}{}
+	}
+	for p := range afterFields {
+		paths[p] = struct{}{}
+	}
+
+	keys := make([]string, 0, len(paths))
+	for p := range paths {
+		keys = append(keys, p)
+	}
+	sort.Strings(keys)
+
+	diff := models.JSON{}
+	for _, path := range keys {
+		if _, ignored := auditIgnoredFields[auditLeaf(path)]; ignored {
+			continue
+		}
+
+		b, hasBefore := beforeFields[path]
+		a, hasAfter := afterFields[path]
+		if hasBefore && hasAfter && reflect.DeepEqual(a, b) {
+			continue
+		}
+
+		redact := auditIsSensitive(path, sensitive)
+		change := map[string]any{}
+		if hasBefore {
+			change["before"] = auditRedact(b, redact)
+		}
+		if hasAfter {
+			change["after"] = auditRedact(a, redact)
+		}
+		diff[path] = change
+	}
+
+	return diff, nil
+}
+
+func auditFlatten(v any) (map[string]any, error) {
+	out := map[string]any{}
+	if v == nil {
+		return out, nil
+	}
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Ptr && rv.IsNil() {
+		return out, nil
+	}
+
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode audit snapshot: %w", err)
+	}
+	var decoded any
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		return nil, fmt.Errorf("failed to decode audit snapshot: %w", err)
+	}
+
+	auditFlattenInto(out, "", decoded)
+	return out, nil
+}
+
+func auditFlattenInto(out map[string]any, prefix string, v any) {
+	m, ok := v.(map[string]any)
+	if !ok {
+		out[prefix] = v
+		return
+	}
+	for k, child := range m {
+		path := k
+		if prefix != "" {
+			path = prefix + "." + k
+		}
+		auditFlattenInto(out, path, child)
+	}
+}
+
+func auditLeaf(path string) string {
+	if i := strings.LastIndex(path, "."); i >= 0 {
+		return path[i+1:]
+	}
+	return path
+}
+
+func auditIsSensitive(path string, sensitive []string) bool {
+	for _, p := range sensitive {
+		if path == p || strings.HasPrefix(path, p+".") {
+			return true
+		}
+	}
+	for _, segment := range strings.Split(strings.ToLower(path), ".") {
+		// OIDC endpoints such as tokenEndpoint are public discovery URLs.
+		if strings.HasSuffix(segment, "endpoint") {
+			continue
+		}
+		for _, fragment := range auditSensitiveFragments {
+			if strings.Contains(segment, fragment) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func auditRedact(v any, redact bool) any {
+	if !redact || v == nil || v == "" {
+		return v
+	}
+	return models.RedactionMask
+}
+
+func auditOptional(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// csvSafeCell prefixes values a spreadsheet would evaluate as a formula with a quote.
+// Usernames, user agents and target names are user-controlled, so an export opened in
+// a spreadsheet must not run what they contain.
+func csvSafeCell(v string) string {
+	if v != "" && strings.ContainsRune("=+-@", rune(v[0])) {
+		return "'" + v
+	}
+	return v
+}
+
+func auditValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,181 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ofkm/arcane-backend/internal/dto"
+	"github.com/ofkm/arcane-backend/internal/models"
+	"github.com/ofkm/arcane-backend/internal/utils/pagination"
+	"github.com/ofkm/arcane-backend/resources"
+)
+
+// setupAuditService creates the audit_logs table from the real migration so that the
+// immutability trigger is in place.
+func setupAuditService(t *testing.T) *AuditService {
+	t.Helper()
+	db := setupSettingsTestDB(t)
+	migration, err := resources.FS.ReadFile("migrations/sqlite/035_add_audit_logs.up.sql")
+	require.NoError(t, err)
+	require.NoError(t, db.Exec(string(migration)).Error)
+	return NewAuditService(db)
+}
+
+func auditTestContext() context.Context {
+	return WithAuditActor(context.Background(), AuditActor{
+		UserID:    "user-1",
+		Username:  "alice",
+		SourceIP:  "10.0.0.5",
+		UserAgent: "test-agent",
+	})
+}
+
+func TestAuditDiff_RedactsSensitiveValues(t *testing.T) {
+	before := map[string]any{
+		"name":      "prod",
+		"createdAt": "2025-01-01T00:00:00Z",
+		"config": map[string]any{
+			"smtpPassword":  "old-pass",
+			"tokenEndpoint": "https://idp/token",
+			"headers":       map[string]any{"Authorization": "Bearer a"},
+		},
+		"envContent": "DB_PASS=a",
+	}
+	after := map[string]any{
+		"name":      "production",
+		"createdAt": "2025-06-01T00:00:00Z",
+		"config": map[string]any{
+			"smtpPassword":  "new-pass",
+			"tokenEndpoint": "https://idp/oauth/token",
+			"headers":       map[string]any{"Authorization": "Bearer b"},
+		},
+		"envContent": "DB_PASS=a",
+	}
+
+	diff, err := auditDiff(before, after, []string{"config.headers", "envContent"})
+	require.NoError(t, err)
+
+	require.Equal(t, map[string]any{"before": "prod", "after": "production"}, diff["name"])
+	require.Equal(t, map[string]any{"before": models.RedactionMask, "after": models.RedactionMask}, diff["config.smtpPassword"])
+	require.Equal(t, map[string]any{"before": models.RedactionMask, "after": models.RedactionMask}, diff["config.headers.Authorization"])
+	require.Equal(t, map[string]any{"before": "https://idp/token", "after": "https://idp/oauth/token"}, diff["config.tokenEndpoint"])
+	require.NotContains(t, diff, "createdAt")
+	require.NotContains(t, diff, "envContent", "unchanged fields are not recorded")
+}
+
+func TestAuditService_RecordsSettingsChanges(t *testing.T) {
+	ctx := auditTestContext()
+	audit := setupAuditService(t)
+	settings, err := NewSettingsService(ctx, audit.db, audit)
+	require.NoError(t, err)
+	require.NoError(t, settings.EnsureDefaultSettings(ctx))
+
+	interval := "120"
+	oidc := `{"clientId":"arcane","clientSecret":"s3cret","issuerUrl":"https://idp.example.com"}`
+	_, err = settings.UpdateSettings(ctx, dto.UpdateSettingsDto{PollingInterval: &interval, AuthOidcConfig: &oidc})
+	require.NoError(t, err)
+
+	entries, _, err := audit.ListAuditLogsPaginated(ctx, pagination.QueryParams{})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+
+	entry := entries[0]
+	require.Equal(t, "settings.update", entry.Action)
+	require.Equal(t, "alice", *entry.Username)
+	require.Equal(t, "10.0.0.5", *entry.SourceIP)
+	require.Equal(t, "test-agent", *entry.UserAgent)
+	require.Equal(t, map[string]any{"before": "60", "after": "120"}, entry.Changes["pollingInterval"])
+	require.Equal(t, map[string]any{"before": "", "after": "https://idp.example.com"}, entry.Changes["authOidcConfig.issuerUrl"])
+	require.Equal(t, map[string]any{"before": "", "after": models.RedactionMask}, entry.Changes["authOidcConfig.clientSecret"])
+
+	// Saving the same values again changes nothing and is not recorded.
+	_, err = settings.UpdateSettings(ctx, dto.UpdateSettingsDto{PollingInterval: &interval})
+	require.NoError(t, err)
+	entries, _, err = audit.ListAuditLogsPaginated(ctx, pagination.QueryParams{})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+}
+
+func TestAuditService_EntriesAreImmutable(t *testing.T) {
+	ctx := context.Background()
+	audit := setupAuditService(t)
+
+	audit.Record(ctx, AuditChange{Action: auditActionCreate, TargetType: auditTargetUser, TargetID: "u1", TargetName: "bob", After: map[string]any{"username": "bob"}})
+
+	var entry models.AuditLog
+	require.NoError(t, audit.db.First(&entry).Error)
+	require.Equal(t, "system", *entry.Username)
+
+	err := audit.db.Model(&models.AuditLog{}).Where("id = ?", entry.ID).Update("action", "user.delete").Error
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "immutable")
+
+	// Retention is the only way entries leave the table.
+	deleted, err := audit.DeleteAuditLogsOlderThan(ctx, time.Hour)
+	require.NoError(t, err)
+	require.Zero(t, deleted)
+	deleted, err = audit.DeleteAuditLogsOlderThan(ctx, -time.Hour)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, deleted)
+}
+
+func TestAuditService_FiltersAndExport(t *testing.T) {
+	ctx := auditTestContext()
+	audit := setupAuditService(t)
+
+	audit.Record(ctx, AuditChange{Action: auditActionCreate, TargetType: auditTargetContainerRegistry, TargetID: "r1", TargetName: "ghcr.io", After: map[string]any{"url": "ghcr.io", "token": "abc"}})
+	audit.Record(ctx, AuditChange{Action: auditActionDelete, TargetType: auditTargetTemplate, TargetID: "t1", TargetName: "nginx", Before: map[string]any{"name": "nginx"}})
+	var nilAudit *AuditService
+	nilAudit.Record(ctx, AuditChange{Action: auditActionDelete, TargetType: auditTargetTemplate})
+
+	filtered, resp, err := audit.ListAuditLogsPaginated(ctx, pagination.QueryParams{Filters: map[string]string{"targetType": auditTargetContainerRegistry}})
+	require.NoError(t, err)
+	require.EqualValues(t, 1, resp.TotalItems)
+	require.Equal(t, "container_registry.create", filtered[0].Action)
+	require.Equal(t, map[string]any{"after": models.RedactionMask}, filtered[0].Changes["token"])
+
+	_, _, err = audit.ListAuditLogsPaginated(ctx, pagination.QueryParams{Filters: map[string]string{"from": "yesterday"}})
+	require.ErrorIs(t, err, ErrInvalidAuditFilter)
+
+	body, contentType, err := audit.ExportAuditLogs(ctx, pagination.QueryParams{Filters: map[string]string{"username": "alice"}}, "csv")
+	require.NoError(t, err)
+	require.Equal(t, "text/csv", contentType)
+	rows, err := csv.NewReader(bytes.NewReader(body)).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+	require.Equal(t, "action", rows[0][5])
+	require.NotContains(t, string(body), "abc")
+
+	_, _, err = audit.ExportAuditLogs(ctx, pagination.QueryParams{}, "xml")
+	require.ErrorIs(t, err, ErrInvalidAuditExportFormat)
+}
+
+func TestAuditService_ExportEscapesFormulas(t *testing.T) {
+	audit := setupAuditService(t)
+	ctx := WithAuditActor(context.Background(), AuditActor{Username: "@evil", UserAgent: "-2+3"})
+
+	audit.Record(ctx, AuditChange{Action: auditActionCreate, TargetType: auditTargetTemplate, TargetID: "+t1", TargetName: `=HYPERLINK("http://evil.example","x")`})
+	audit.Record(ctx, AuditChange{Action: auditActionDelete, TargetType: auditTargetTemplate, TargetID: "t2", TargetName: "a=b"})
+
+	body, _, err := audit.ExportAuditLogs(ctx, pagination.QueryParams{}, "csv")
+	require.NoError(t, err)
+	rows, err := csv.NewReader(bytes.NewReader(body)).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+
+	byTarget := map[string][]string{}
+	for _, row := range rows[1:] {
+		byTarget[row[7]] = row
+	}
+	formula := byTarget["'+t1"]
+	require.NotNil(t, formula)
+	require.Equal(t, "'@evil", formula[1])
+	require.Equal(t, "'-2+3", formula[4])
+	require.Equal(t, `'=HYPERLINK("http://evil.example","x")`, formula[8])
+	require.Equal(t, "a=b", byTarget["t2"][8], "only a leading formula character is escaped")
+}
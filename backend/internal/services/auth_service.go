@@ -6,6 +6,8 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"maps"
+	"slices"
 	"strings"
 	"time"
 
@@ -48,22 +50,38 @@ type UserClaims struct {
 	DisplayName string   `json:"display_name,omitempty"`
 	Roles       []string `json:"roles"`
 	AppVersion  string   `json:"app_version,omitempty"`
+	SessionID   string   `json:"sid,omitempty"`
+}
+
+// refreshClaims identify a refresh token by its own ID, so each token of a session's
+// refresh-token family can be told apart.
+type refreshClaims struct {
+	jwt.RegisteredClaims
+	UserID    string `json:"user_id"`
+	SessionID string `json:"sid,omitempty"`
 }
 
 type AuthService struct {
 	userService     *UserService
 	settingsService *SettingsService
 	eventService    *EventService
+	sessionService  *SessionService
+	throttle        *LoginThrottleService
+	oidcProviders   *OidcProviderService
 	jwtSecret       []byte
 	refreshExpiry   time.Duration
 	config          *config.Config
+	challenges      loginChallenges
 }
 
-func NewAuthService(userService *UserService, settingsService *SettingsService, eventService *EventService, jwtSecret string, cfg *config.Config) *AuthService {
+func NewAuthService(userService *UserService, settingsService *SettingsService, eventService *EventService, sessionService *SessionService, throttle *LoginThrottleService, oidcProviders *OidcProviderService, jwtSecret string, cfg *config.Config) *AuthService {
 	return &AuthService{
 		userService:     userService,
 		settingsService: settingsService,
 		eventService:    eventService,
+		sessionService:  sessionService,
+		throttle:        throttle,
+		oidcProviders:   oidcProviders,
 		jwtSecret:       utils.CheckOrGenerateJwtSecret(jwtSecret),
 		refreshExpiry:   7 * 24 * time.Hour,
 		config:          cfg,
@@ -167,27 +185,45 @@ func (s *AuthService) GetOidcConfig(ctx context.Context) (*models.OidcConfig, er
 	return authSettings.Oidc, nil
 }
 
-func (s *AuthService) Login(ctx context.Context, username, password string) (*models.User, *TokenPair, error) {
+// Login checks the username and password. Accounts that use two-factor authentication
+// (or must enrol because the administrator requires it) get a LoginChallenge instead of
+// tokens, to be completed with VerifyLoginChallenge. Repeated failures for a username or
+// client IP are throttled with a *LoginThrottledError; each attempt is counted before the
+// password is checked, so concurrent guesses cannot slip past the limit.
+func (s *AuthService) Login(ctx context.Context, username, password string) (*models.User, *TokenPair, *LoginChallenge, error) {
 	localEnabled, err := s.IsLocalAuthEnabled(ctx)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 
 	if !localEnabled {
-		return nil, nil, ErrLocalAuthDisabled
+		return nil, nil, nil, ErrLocalAuthDisabled
+	}
+
+	// The attempt counts as a failure until the password has been checked.
+	var attempt *LoginAttempt
+	if s.throttle != nil {
+		attempt, err = s.throttle.Reserve(ctx, username, sessionClientFromContext(ctx).IPAddress)
+		if err != nil {
+			return nil, nil, nil, err
+		}
 	}
 
 	user, err := s.userService.GetUserByUsername(ctx, username)
 	if err != nil {
 		if strings.Contains(err.Error(), "user not found") {
-			return nil, nil, ErrInvalidCredentials
+			attempt.Fail(ctx, "", username, "unknown_user")
+			return nil, nil, nil, ErrInvalidCredentials
 		}
-		return nil, nil, err
+		attempt.Release(ctx)
+		return nil, nil, nil, err
 	}
 
 	if err := s.userService.ValidatePassword(user.PasswordHash, password); err != nil {
-		return nil, nil, ErrInvalidCredentials
+		attempt.Fail(ctx, user.ID, user.Username, "invalid_password")
+		return nil, nil, nil, ErrInvalidCredentials
 	}
+	attempt.Release(ctx)
 
 	if s.userService.NeedsPasswordUpgrade(user.PasswordHash) {
 		if err := s.userService.UpgradePasswordHash(ctx, user.ID, password); err != nil {
@@ -197,26 +233,50 @@ func (s *AuthService) Login(ctx context.Context, username, password string) (*mo
 		}
 	}
 
+	if s.totpRequired(ctx, user) {
+		challenge, err := s.newLoginChallenge(user)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		return user, nil, challenge, nil
+	}
+
+	tokenPair, err := s.completeLogin(ctx, user, models.JSON{"action": "login", "method": "local"})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return user, tokenPair, nil, nil
+}
+
+func (s *AuthService) recordLoginFailure(ctx context.Context, userID, username, reason string) {
+	if s.throttle != nil {
+		s.throttle.RecordFailure(ctx, userID, username, sessionClientFromContext(ctx).IPAddress, reason)
+	}
+}
+
+// completeLogin records the login and issues tokens once every factor has been checked.
+func (s *AuthService) completeLogin(ctx context.Context, user *models.User, metadata models.JSON) (*TokenPair, error) {
+	if s.throttle != nil {
+		s.throttle.RecordSuccess(ctx, user.Username)
+	}
+
 	now := time.Now()
 	user.LastLogin = &now
 	if _, err := s.userService.UpdateUser(ctx, user); err != nil {
 		fmt.Printf("Failed to update user's last login time: %v\n", err)
 	}
 
-	tokenPair, err := s.generateTokenPair(ctx, user)
+	method, _ := metadata["method"].(string)
+	tokenPair, err := s.startSession(ctx, user, method)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
-	metadata := models.JSON{
-		"action": "login",
-		"method": "local",
-	}
 	if logErr := s.eventService.LogUserEvent(ctx, models.EventTypeUserLogin, user.ID, user.Username, metadata); logErr != nil {
 		fmt.Printf("Could not log user login action: %s\n", logErr)
 	}
 
-	return user, tokenPair, nil
+	return tokenPair, nil
 }
 
 func (s *AuthService) OidcLogin(ctx context.Context, userInfo dto.OidcUserInfo, tokenResp *dto.OidcTokenResponse) (*models.User, *TokenPair, error) {
@@ -229,7 +289,7 @@ func (s *AuthService) OidcLogin(ctx context.Context, userInfo dto.OidcUserInfo,
 		return nil, nil, err
 	}
 
-	tokenPair, err := s.generateTokenPair(ctx, user)
+	tokenPair, err := s.startSession(ctx, user, "oidc")
 	if err != nil {
 		return nil, nil, err
 	}
@@ -240,6 +300,9 @@ func (s *AuthService) OidcLogin(ctx context.Context, userInfo dto.OidcUserInfo,
 		"newUser": isNewUser,
 		"subject": userInfo.Subject,
 	}
+	if userInfo.Provider != "" {
+		metadata["provider"] = userInfo.Provider
+	}
 	if logErr := s.eventService.LogUserEvent(ctx, models.EventTypeUserLogin, user.ID, user.Username, metadata); logErr != nil {
 		fmt.Printf("Could not log OIDC user login action: %s\n", logErr)
 	}
@@ -248,15 +311,19 @@ func (s *AuthService) OidcLogin(ctx context.Context, userInfo dto.OidcUserInfo,
 }
 
 func (s *AuthService) findOrCreateOidcUser(ctx context.Context, userInfo dto.OidcUserInfo, tokenResp *dto.OidcTokenResponse) (*models.User, bool, error) {
+	userInfo.Subject = oidcSubjectKey(userInfo.Provider, userInfo.Subject)
 	user, err := s.userService.GetUserByOidcSubjectId(ctx, userInfo.Subject)
 	if err != nil && !errors.Is(err, ErrUserNotFound) {
 		return nil, false, err
 	}
 
 	if user == nil {
-		// Check if merge accounts is enabled in settings
+		// Check if merge accounts is enabled in settings. Merging only applies to the
+		// default provider: an additional provider may assert any email address, so it
+		// must not be able to take over a local account.
 		settings, settingsErr := s.settingsService.GetSettings(ctx)
-		mergeEnabled := settingsErr == nil && settings.AuthOidcMergeAccounts.IsTrue()
+		defaultProvider := userInfo.Provider == "" || userInfo.Provider == models.OidcDefaultProvider
+		mergeEnabled := defaultProvider && settingsErr == nil && settings.AuthOidcMergeAccounts.IsTrue()
 
 		// If merge accounts is enabled, try to find existing user by email
 		if mergeEnabled && userInfo.Email != "" {
@@ -313,10 +380,9 @@ func (s *AuthService) createOidcUser(ctx context.Context, userInfo dto.OidcUserI
 
 	email := userInfo.Email
 
-	roles := models.StringSlice{"user"}
-	if s.isAdminFromOidc(ctx, userInfo, tokenResp) {
-		roles = append(roles, "admin")
-	}
+	// New users get the default user role, which syncOidcRoles drops again when the
+	// provider manages it and its role mappings do not grant it.
+	roles := s.syncOidcRoles(ctx, models.StringSlice{models.RoleUser}, userInfo, tokenResp)
 
 	user := &models.User{
 		BaseModel:     models.BaseModel{ID: uuid.NewString()},
@@ -344,14 +410,7 @@ func (s *AuthService) updateOidcUser(ctx context.Context, user *models.User, use
 		user.Email = &userInfo.Email
 	}
 
-	wantAdmin := s.isAdminFromOidc(ctx, userInfo, tokenResp)
-	hasAdmin := hasRole(user.Roles, "admin")
-	switch {
-	case wantAdmin && !hasAdmin:
-		user.Roles = addRole(user.Roles, "admin")
-	case !wantAdmin && hasAdmin:
-		user.Roles = removeRole(user.Roles, "admin")
-	}
+	user.Roles = s.syncOidcRoles(ctx, user.Roles, userInfo, tokenResp)
 
 	s.persistOidcTokens(user, tokenResp)
 
@@ -369,15 +428,8 @@ func (s *AuthService) mergeOidcWithExistingUser(ctx context.Context, user *model
 			u.DisplayName = &userInfo.Name
 		}
 
-		// Update admin role based on OIDC claims
-		wantAdmin := s.isAdminFromOidc(ctx, userInfo, tokenResp)
-		hasAdmin := hasRole(u.Roles, "admin")
-		switch {
-		case wantAdmin && !hasAdmin:
-			u.Roles = addRole(u.Roles, "admin")
-		case !wantAdmin && hasAdmin:
-			u.Roles = removeRole(u.Roles, "admin")
-		}
+		// Sync mapped roles based on OIDC claims
+		u.Roles = s.syncOidcRoles(ctx, u.Roles, userInfo, tokenResp)
 
 		// Persist OIDC tokens
 		s.persistOidcTokens(u, tokenResp)
@@ -414,25 +466,80 @@ func removeRole(roles models.StringSlice, role string) models.StringSlice {
 	return out
 }
 
-func (s *AuthService) isAdminFromOidc(ctx context.Context, userInfo dto.OidcUserInfo, tokenResp *dto.OidcTokenResponse) bool {
-	claimKey, values := s.getAdminClaimConfig(ctx)
-	if claimKey == "" {
+// oidcSubjectKey is the subject stored on users. Subjects from additional providers are
+// prefixed with the provider slug so two providers cannot sign in as the same account.
+func oidcSubjectKey(provider, subject string) string {
+	if provider == "" || provider == models.OidcDefaultProvider {
+		return subject
+	}
+	return provider + "|" + subject
+}
+
+// syncOidcRoles applies the role mappings of the user's provider to roles. Every role
+// that appears in a mapping, and admin, is managed by the identity provider: it is added
+// when one of its mappings matches the user's claims and removed otherwise. Once the
+// provider has any mappings the default user role is managed the same way, so a group
+// mapped to viewer does not keep the broader access every new user starts with. Other
+// roles are left alone.
+func (s *AuthService) syncOidcRoles(ctx context.Context, roles models.StringSlice, userInfo dto.OidcUserInfo, tokenResp *dto.OidcTokenResponse) models.StringSlice {
+	var idTokenClaims map[string]any
+	if tokenResp != nil && tokenResp.IDToken != "" {
+		idTokenClaims = utils.ParseJWTClaims(tokenResp.IDToken)
+	}
+	matches := func(claim string, values []string) bool {
+		if v, ok := utils.GetByPath(userInfo.Extra, claim); ok && utils.EvalMatch(v, values) {
+			return true
+		}
+		if v, ok := utils.GetByPath(idTokenClaims, claim); ok && utils.EvalMatch(v, values) {
+			return true
+		}
 		return false
 	}
 
-	if v, ok := utils.GetByPath(userInfo.Extra, claimKey); ok && utils.EvalMatch(v, values) {
-		return true
+	want := map[string]bool{models.RoleAdmin: false}
+	if userInfo.Provider == "" || userInfo.Provider == models.OidcDefaultProvider {
+		if claim, values := s.getAdminClaimConfig(ctx); claim != "" && matches(claim, values) {
+			want[models.RoleAdmin] = true
+		}
+	}
+	if s.oidcProviders != nil {
+		provider := userInfo.Provider
+		if provider == "" {
+			provider = models.OidcDefaultProvider
+		}
+		mappings, err := s.oidcProviders.RoleMappingsFor(ctx, provider)
+		if err != nil {
+			slog.WarnContext(ctx, "Failed to load OIDC role mappings", "provider", provider, "error", err)
+			// Keep the user's roles rather than revoking them on a database error.
+			return roles
+		}
+		if len(mappings) > 0 && s.oidcManagesDefaultRole(ctx, provider) {
+			want[models.RoleUser] = false
+		}
+		for _, m := range mappings {
+			want[m.RoleName] = want[m.RoleName] || matches(m.Claim, []string{m.Value})
+		}
 	}
 
-	if tokenResp != nil && tokenResp.IDToken != "" {
-		if claims := utils.ParseJWTClaims(tokenResp.IDToken); claims != nil {
-			if v, ok := utils.GetByPath(claims, claimKey); ok && utils.EvalMatch(v, values) {
-				return true
-			}
+	for _, role := range slices.Sorted(maps.Keys(want)) {
+		if want[role] {
+			roles = addRole(roles, role)
+		} else {
+			roles = removeRole(roles, role)
 		}
 	}
+	return roles
+}
 
-	return false
+// oidcManagesDefaultRole reports whether the role mappings decide if users of provider
+// keep the default user role. It is opt-in per provider, since a single mapping would
+// otherwise take the role away from every user who does not match it.
+func (s *AuthService) oidcManagesDefaultRole(ctx context.Context, provider string) bool {
+	if provider == models.OidcDefaultProvider {
+		return s.settingsService.GetBoolSetting(ctx, "authOidcManageDefaultRole", false)
+	}
+	p, err := s.oidcProviders.GetProvider(ctx, provider)
+	return err == nil && p.ManageDefaultRole
 }
 
 func (s *AuthService) getAdminClaimConfig(ctx context.Context) (claim string, values []string) {
@@ -474,8 +581,11 @@ func (s *AuthService) persistOidcTokens(user *models.User, tokenResp *dto.OidcTo
 	}
 }
 
+// RefreshToken exchanges a refresh token for a new token pair. Refresh tokens are
+// single use; see SessionService.RotateRefreshToken.
 func (s *AuthService) RefreshToken(ctx context.Context, refreshToken string) (*TokenPair, error) {
-	token, err := jwt.ParseWithClaims(refreshToken, &jwt.RegisteredClaims{},
+	claims := &refreshClaims{}
+	token, err := jwt.ParseWithClaims(refreshToken, claims,
 		func(t *jwt.Token) (interface{}, error) {
 			return s.jwtSecret, nil
 		})
@@ -488,26 +598,38 @@ func (s *AuthService) RefreshToken(ctx context.Context, refreshToken string) (*T
 		return nil, ErrInvalidToken
 	}
 
-	claims, ok := token.Claims.(*jwt.RegisteredClaims)
-	if !ok {
-		return nil, errors.New("invalid token claims")
-	}
-
 	if claims.Subject != "refresh" {
 		return nil, errors.New("not a refresh token")
 	}
 
-	userId := claims.ID
-	if userId == "" {
+	if claims.UserID == "" {
 		return nil, errors.New("missing user ID in token")
 	}
 
-	user, err := s.userService.GetUserByID(ctx, userId)
+	sessionID, refreshID := "", ""
+	if s.sessionService != nil {
+		if claims.SessionID == "" || claims.ID == "" {
+			return nil, ErrInvalidToken
+		}
+		session, next, err := s.sessionService.RotateRefreshToken(ctx, claims.SessionID, claims.ID, time.Now().Add(s.refreshExpiry))
+		if err != nil {
+			if errors.Is(err, ErrSessionNotFound) || errors.Is(err, ErrSessionRevoked) || errors.Is(err, ErrRefreshTokenReused) {
+				return nil, fmt.Errorf("%w: %w", ErrInvalidToken, err)
+			}
+			return nil, err
+		}
+		if session.UserID != claims.UserID {
+			return nil, ErrInvalidToken
+		}
+		sessionID, refreshID = session.ID, next
+	}
+
+	user, err := s.userService.GetUserByID(ctx, claims.UserID)
 	if err != nil {
 		return nil, err
 	}
 
-	tokenPair, err := s.generateTokenPair(ctx, user)
+	tokenPair, err := s.generateTokenPair(ctx, user, sessionID, refreshID)
 	if err != nil {
 		return nil, err
 	}
@@ -515,7 +637,28 @@ func (s *AuthService) RefreshToken(ctx context.Context, refreshToken string) (*T
 	return tokenPair, nil
 }
 
+// Logout revokes the session the access token belongs to.
+func (s *AuthService) Logout(ctx context.Context, user *models.User, sessionID string) error {
+	if s.sessionService == nil || sessionID == "" {
+		return nil
+	}
+	if err := s.sessionService.RevokeSession(ctx, user.ID, sessionID, SessionRevokedLogout); err != nil && !errors.Is(err, ErrSessionNotFound) {
+		return err
+	}
+	if logErr := s.eventService.LogUserEvent(ctx, models.EventTypeUserLogout, user.ID, user.Username, models.JSON{"action": "logout", "session": sessionID}); logErr != nil {
+		slog.WarnContext(ctx, "Could not log user logout action", "error", logErr)
+	}
+	return nil
+}
+
 func (s *AuthService) VerifyToken(ctx context.Context, accessToken string) (*models.User, error) {
+	user, _, err := s.VerifyTokenSession(ctx, accessToken)
+	return user, err
+}
+
+// VerifyTokenSession verifies an access token and also returns the ID of the session it
+// was issued for. Tokens of revoked or expired sessions are rejected.
+func (s *AuthService) VerifyTokenSession(ctx context.Context, accessToken string) (*models.User, string, error) {
 	token, err := jwt.ParseWithClaims(accessToken, &UserClaims{},
 		func(t *jwt.Token) (interface{}, error) {
 			return s.jwtSecret, nil
@@ -523,26 +666,26 @@ func (s *AuthService) VerifyToken(ctx context.Context, accessToken string) (*mod
 
 	if err != nil {
 		if strings.Contains(err.Error(), "token is expired") {
-			return nil, ErrExpiredToken
+			return nil, "", ErrExpiredToken
 		}
-		return nil, ErrInvalidToken
+		return nil, "", ErrInvalidToken
 	}
 
 	if !token.Valid {
-		return nil, ErrInvalidToken
+		return nil, "", ErrInvalidToken
 	}
 
 	claims, ok := token.Claims.(*UserClaims)
 	if !ok {
-		return nil, errors.New("invalid token claims")
+		return nil, "", errors.New("invalid token claims")
 	}
 
 	if claims.Subject != "access" {
-		return nil, errors.New("not an access token")
+		return nil, "", errors.New("not an access token")
 	}
 
 	if claims.ID == "" {
-		return nil, errors.New("missing user ID in token")
+		return nil, "", errors.New("missing user ID in token")
 	}
 
 	if claims.AppVersion != "" && claims.AppVersion != config.Version {
@@ -550,7 +693,19 @@ func (s *AuthService) VerifyToken(ctx context.Context, accessToken string) (*mod
 			"tokenVersion", claims.AppVersion,
 			"currentVersion", config.Version,
 			"user", claims.Username)
-		return nil, ErrTokenVersionMismatch
+		return nil, "", ErrTokenVersionMismatch
+	}
+
+	if s.sessionService != nil {
+		if claims.SessionID == "" {
+			return nil, "", ErrInvalidToken
+		}
+		if err := s.sessionService.ValidateSession(ctx, claims.SessionID, claims.ID); err != nil {
+			if errors.Is(err, ErrSessionNotFound) || errors.Is(err, ErrSessionRevoked) {
+				return nil, "", fmt.Errorf("%w: %w", ErrInvalidToken, err)
+			}
+			return nil, "", err
+		}
 	}
 
 	user := &models.User{
@@ -567,10 +722,11 @@ func (s *AuthService) VerifyToken(ctx context.Context, accessToken string) (*mod
 		user.DisplayName = &claims.DisplayName
 	}
 
-	return user, nil
+	return user, claims.SessionID, nil
 }
 
-func (s *AuthService) ChangePassword(ctx context.Context, userID, currentPassword, newPassword string) error {
+// ChangePassword sets a new password and signs out every other session of the user.
+func (s *AuthService) ChangePassword(ctx context.Context, userID, currentSessionID, currentPassword, newPassword string) error {
 	user, err := s.userService.GetUserByID(ctx, userID)
 	if err != nil {
 		return err
@@ -589,11 +745,31 @@ func (s *AuthService) ChangePassword(ctx context.Context, userID, currentPasswor
 
 	user.PasswordHash = hashedPassword
 	user.RequiresPasswordChange = false
-	_, err = s.userService.UpdateUser(ctx, user)
-	return err
+	if _, err := s.userService.UpdateUser(ctx, user); err != nil {
+		return err
+	}
+
+	if s.sessionService != nil {
+		if _, err := s.sessionService.RevokeUserSessions(ctx, user.ID, currentSessionID, SessionRevokedPassword); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// startSession records a new session for user and issues its first token pair.
+func (s *AuthService) startSession(ctx context.Context, user *models.User, method string) (*TokenPair, error) {
+	if s.sessionService == nil {
+		return s.generateTokenPair(ctx, user, "", utils.GenerateRandomString(32))
+	}
+	session, refreshID, err := s.sessionService.CreateSession(ctx, user, method, time.Now().Add(s.refreshExpiry))
+	if err != nil {
+		return nil, err
+	}
+	return s.generateTokenPair(ctx, user, session.ID, refreshID)
 }
 
-func (s *AuthService) generateTokenPair(ctx context.Context, user *models.User) (*TokenPair, error) {
+func (s *AuthService) generateTokenPair(ctx context.Context, user *models.User, sessionID, refreshID string) (*TokenPair, error) {
 	sessionTimeout, _ := s.GetSessionTimeout(ctx)
 
 	accessTokenExpiry := time.Now().Add(time.Duration(sessionTimeout) * time.Minute)
@@ -610,6 +786,7 @@ func (s *AuthService) generateTokenPair(ctx context.Context, user *models.User)
 		Username:   user.Username,
 		Roles:      []string(user.Roles),
 		AppVersion: config.Version,
+		SessionID:  sessionID,
 	}
 
 	if user.Email != nil {
@@ -627,11 +804,15 @@ func (s *AuthService) generateTokenPair(ctx context.Context, user *models.User)
 		return nil, err
 	}
 
-	refreshToken := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
-		ID:        user.ID,
-		Subject:   "refresh",
-		IssuedAt:  jwt.NewNumericDate(time.Now()),
-		ExpiresAt: jwt.NewNumericDate(time.Now().Add(s.refreshExpiry)),
+	refreshToken := jwt.NewWithClaims(jwt.SigningMethodHS256, refreshClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        refreshID,
+			Subject:   "refresh",
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(s.refreshExpiry)),
+		},
+		UserID:    user.ID,
+		SessionID: sessionID,
 	})
 
 	refreshTokenString, err := refreshToken.SignedString(s.jwtSecret)
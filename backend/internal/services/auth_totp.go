@@ -0,0 +1,406 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/ofkm/arcane-backend/internal/models"
+	"github.com/ofkm/arcane-backend/internal/utils"
+	"github.com/ofkm/arcane-backend/internal/utils/totp"
+)
+
+const (
+	totpIssuer = "Arcane"
+
+	loginChallengeSubject     = "totp_challenge"
+	loginChallengeExpiry      = 5 * time.Minute
+	loginChallengeMaxAttempts = 5
+
+	recoveryCodeCount = 10
+)
+
+var (
+	ErrInvalidTotpCode        = errors.New("invalid two-factor code")
+	ErrInvalidLoginChallenge  = errors.New("invalid or expired login challenge")
+	ErrTotpAlreadyEnabled     = errors.New("two-factor authentication is already enabled")
+	ErrTotpNotEnabled         = errors.New("two-factor authentication is not enabled")
+	ErrTotpSetupNotStarted    = errors.New("two-factor setup has not been started")
+	ErrTotpRequiredBySettings = errors.New("two-factor authentication is required by the administrator")
+)
+
+// LoginChallenge is returned by Login instead of tokens when the account needs a second
+// factor. The token is passed back with the code to VerifyLoginChallenge. When
+// EnrollmentRequired is set the account has no authenticator yet and must enrol one
+// (BeginLoginEnrollment) before it can finish signing in.
+type LoginChallenge struct {
+	Token              string    `json:"challengeToken"`
+	EnrollmentRequired bool      `json:"enrollmentRequired"`
+	ExpiresAt          time.Time `json:"expiresAt"`
+}
+
+// TotpSetup is what an authenticator app needs to enrol: the secret and the otpauth://
+// URI to show as a QR code.
+type TotpSetup struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+}
+
+// loginChallenges counts failed codes per challenge so a challenge cannot be used to
+// brute-force the 6-digit code. Challenges are single use: a successful verification
+// uses up the remaining attempts.
+type loginChallenges struct {
+	mu       sync.Mutex
+	attempts map[string]int
+	expires  map[string]time.Time
+}
+
+func (c *loginChallenges) allow(id string, expiresAt time.Time) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.attempts == nil {
+		c.attempts = map[string]int{}
+		c.expires = map[string]time.Time{}
+	}
+	now := time.Now()
+	for key, exp := range c.expires {
+		if now.After(exp) {
+			delete(c.attempts, key)
+			delete(c.expires, key)
+		}
+	}
+	c.expires[id] = expiresAt
+	return c.attempts[id] < loginChallengeMaxAttempts
+}
+
+func (c *loginChallenges) fail(id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.attempts[id]++
+}
+
+func (c *loginChallenges) consume(id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.attempts[id] = loginChallengeMaxAttempts
+}
+
+func (s *AuthService) totpRequired(ctx context.Context, user *models.User) bool {
+	return user.TotpEnabled || s.settingsService.GetBoolSetting(ctx, "authRequireTotp", false)
+}
+
+func (s *AuthService) newLoginChallenge(user *models.User) (*LoginChallenge, error) {
+	expiresAt := time.Now().Add(loginChallengeExpiry)
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+		ID:        utils.GenerateRandomString(24),
+		Subject:   loginChallengeSubject,
+		Audience:  jwt.ClaimStrings{user.ID},
+		IssuedAt:  jwt.NewNumericDate(time.Now()),
+		ExpiresAt: jwt.NewNumericDate(expiresAt),
+	})
+	signed, err := token.SignedString(s.jwtSecret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign login challenge: %w", err)
+	}
+	return &LoginChallenge{Token: signed, EnrollmentRequired: !user.TotpEnabled, ExpiresAt: expiresAt}, nil
+}
+
+// parseLoginChallenge returns the challenge ID and the user it was issued to, provided it
+// is valid and has attempts left.
+func (s *AuthService) parseLoginChallenge(ctx context.Context, challengeToken string) (string, *models.User, error) {
+	claims := &jwt.RegisteredClaims{}
+	token, err := jwt.ParseWithClaims(challengeToken, claims, func(t *jwt.Token) (interface{}, error) {
+		return s.jwtSecret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	if err != nil || !token.Valid || claims.Subject != loginChallengeSubject || claims.ID == "" || len(claims.Audience) != 1 || claims.ExpiresAt == nil {
+		return "", nil, ErrInvalidLoginChallenge
+	}
+	if !s.challenges.allow(claims.ID, claims.ExpiresAt.Time) {
+		return "", nil, ErrInvalidLoginChallenge
+	}
+
+	user, err := s.userService.GetUserByID(ctx, claims.Audience[0])
+	if err != nil {
+		return "", nil, ErrInvalidLoginChallenge
+	}
+	return claims.ID, user, nil
+}
+
+// BeginLoginEnrollment starts TOTP enrolment for an account that has to enrol before it
+// can sign in. The enrolment is finished by VerifyLoginChallenge with a code from the app.
+func (s *AuthService) BeginLoginEnrollment(ctx context.Context, challengeToken string) (*TotpSetup, error) {
+	_, user, err := s.parseLoginChallenge(ctx, challengeToken)
+	if err != nil {
+		return nil, err
+	}
+	if user.TotpEnabled {
+		return nil, ErrTotpAlreadyEnabled
+	}
+	return s.startTotpSetup(ctx, user)
+}
+
+// VerifyLoginChallenge finishes a two-step login with a TOTP code or a recovery code and
+// issues the tokens. For accounts that enrolled during this login the new recovery codes
+// are returned as well; they are never shown again.
+func (s *AuthService) VerifyLoginChallenge(ctx context.Context, challengeToken, code string) (*models.User, *TokenPair, []string, error) {
+	challengeID, user, err := s.parseLoginChallenge(ctx, challengeToken)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var recoveryCodes []string
+	method := "totp"
+	if user.TotpEnabled {
+		method, err = s.verifySecondFactor(ctx, user, code)
+	} else {
+		recoveryCodes, err = s.finishTotpSetup(ctx, user, code)
+	}
+	if err != nil {
+		s.challenges.fail(challengeID)
+		if errors.Is(err, ErrInvalidTotpCode) {
+			s.recordLoginFailure(ctx, user.ID, user.Username, "invalid_totp_code")
+		}
+		return nil, nil, nil, err
+	}
+	s.challenges.consume(challengeID)
+
+	tokenPair, err := s.completeLogin(ctx, user, models.JSON{"action": "login", "method": "local", "secondFactor": method})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return user, tokenPair, recoveryCodes, nil
+}
+
+// verifySecondFactor accepts a current TOTP code or an unused recovery code and reports
+// which one was used.
+func (s *AuthService) verifySecondFactor(ctx context.Context, user *models.User, code string) (string, error) {
+	if err := s.verifyTotpCode(ctx, user, code); err == nil {
+		return "totp", nil
+	}
+	if err := s.consumeRecoveryCode(ctx, user, code); err == nil {
+		return "recovery_code", nil
+	}
+	return "", ErrInvalidTotpCode
+}
+
+func (s *AuthService) verifyTotpCode(ctx context.Context, user *models.User, code string) error {
+	if user.TotpSecret == nil {
+		return ErrTotpNotEnabled
+	}
+	secret, err := utils.Decrypt(*user.TotpSecret)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt TOTP secret: %w", err)
+	}
+
+	step, ok := totp.Validate(secret, code, time.Now(), user.TotpLastUsedStep)
+	if !ok {
+		return ErrInvalidTotpCode
+	}
+
+	// The conditional update makes each code single use even when two logins race.
+	res := s.userService.db.WithContext(ctx).Model(&models.User{}).
+		Where("id = ? AND totp_last_used_step < ?", user.ID, step).
+		Update("totp_last_used_step", step)
+	if res.Error != nil {
+		return fmt.Errorf("failed to record TOTP use: %w", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return ErrInvalidTotpCode
+	}
+	user.TotpLastUsedStep = step
+	return nil
+}
+
+func (s *AuthService) consumeRecoveryCode(ctx context.Context, user *models.User, code string) error {
+	hash := hashRecoveryCode(code)
+	remaining := make(models.StringSlice, 0, len(user.TotpRecoveryCodes))
+	found := false
+	for _, stored := range user.TotpRecoveryCodes {
+		if !found && subtle.ConstantTimeCompare([]byte(stored), []byte(hash)) == 1 {
+			found = true
+			continue
+		}
+		remaining = append(remaining, stored)
+	}
+	if !found {
+		return ErrInvalidTotpCode
+	}
+
+	res := s.userService.db.WithContext(ctx).Model(&models.User{}).
+		Where("id = ? AND totp_recovery_codes = ?", user.ID, user.TotpRecoveryCodes).
+		Update("totp_recovery_codes", remaining)
+	if res.Error != nil {
+		return fmt.Errorf("failed to use recovery code: %w", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return ErrInvalidTotpCode
+	}
+	user.TotpRecoveryCodes = remaining
+	return nil
+}
+
+// BeginTotpSetup generates a new secret for the signed-in user. Two-factor login is not
+// enforced until EnableTotp confirms the app produces valid codes.
+func (s *AuthService) BeginTotpSetup(ctx context.Context, userID string) (*TotpSetup, error) {
+	user, err := s.userService.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.TotpEnabled {
+		return nil, ErrTotpAlreadyEnabled
+	}
+	return s.startTotpSetup(ctx, user)
+}
+
+// EnableTotp confirms setup with a code from the authenticator app and returns the
+// recovery codes.
+func (s *AuthService) EnableTotp(ctx context.Context, userID, code string) ([]string, error) {
+	user, err := s.userService.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.TotpEnabled {
+		return nil, ErrTotpAlreadyEnabled
+	}
+	return s.finishTotpSetup(ctx, user, code)
+}
+
+// DisableTotp turns off two-factor login after checking the password (for accounts that
+// have one) and a current code or recovery code.
+func (s *AuthService) DisableTotp(ctx context.Context, userID, password, code string) error {
+	if s.settingsService.GetBoolSetting(ctx, "authRequireTotp", false) {
+		return ErrTotpRequiredBySettings
+	}
+	user, err := s.userService.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !user.TotpEnabled {
+		return ErrTotpNotEnabled
+	}
+	if user.PasswordHash != "" {
+		if err := s.userService.ValidatePassword(user.PasswordHash, password); err != nil {
+			return ErrInvalidCredentials
+		}
+	}
+	if _, err := s.verifySecondFactor(ctx, user, code); err != nil {
+		return err
+	}
+	return s.clearTotp(ctx, user)
+}
+
+// RegenerateRecoveryCodes replaces all recovery codes after checking a current code.
+func (s *AuthService) RegenerateRecoveryCodes(ctx context.Context, userID, code string) ([]string, error) {
+	user, err := s.userService.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.TotpEnabled {
+		return nil, ErrTotpNotEnabled
+	}
+	if err := s.verifyTotpCode(ctx, user, code); err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.userService.db.WithContext(ctx).Model(&models.User{}).Where("id = ?", user.ID).Update("totp_recovery_codes", hashes).Error; err != nil {
+		return nil, fmt.Errorf("failed to save recovery codes: %w", err)
+	}
+	return codes, nil
+}
+
+// ResetTotp removes a user's second factor, for admins helping someone who lost their
+// device and recovery codes. If two-factor is required the user enrols again at their
+// next login.
+func (s *AuthService) ResetTotp(ctx context.Context, userID string) error {
+	user, err := s.userService.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	return s.clearTotp(ctx, user)
+}
+
+func (s *AuthService) startTotpSetup(ctx context.Context, user *models.User) (*TotpSetup, error) {
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	encrypted, err := utils.Encrypt(secret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt TOTP secret: %w", err)
+	}
+
+	user.TotpSecret = &encrypted
+	user.TotpLastUsedStep = 0
+	if _, err := s.userService.UpdateUser(ctx, user); err != nil {
+		return nil, err
+	}
+	return &TotpSetup{Secret: secret, URI: totp.ProvisioningURI(totpIssuer, user.Username, secret)}, nil
+}
+
+func (s *AuthService) finishTotpSetup(ctx context.Context, user *models.User, code string) ([]string, error) {
+	if user.TotpSecret == nil {
+		return nil, ErrTotpSetupNotStarted
+	}
+	if err := s.verifyTotpCode(ctx, user, code); err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	user.TotpEnabled = true
+	user.TotpRecoveryCodes = hashes
+	if _, err := s.userService.UpdateUser(ctx, user); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+func (s *AuthService) clearTotp(ctx context.Context, user *models.User) error {
+	user.TotpEnabled = false
+	user.TotpSecret = nil
+	user.TotpLastUsedStep = 0
+	user.TotpRecoveryCodes = nil
+	_, err := s.userService.UpdateUser(ctx, user)
+	return err
+}
+
+// generateRecoveryCodes returns recovery codes formatted for display (xxxxx-xxxxx) and
+// the hashes to store.
+func generateRecoveryCodes() ([]string, models.StringSlice, error) {
+	enc := base32.StdEncoding.WithPadding(base32.NoPadding)
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make(models.StringSlice, 0, recoveryCodeCount)
+	for range recoveryCodeCount {
+		b := make([]byte, 7)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, fmt.Errorf("failed to generate recovery codes: %w", err)
+		}
+		raw := strings.ToLower(enc.EncodeToString(b))[:10]
+		code := raw[:5] + "-" + raw[5:]
+		codes = append(codes, code)
+		hashes = append(hashes, hashRecoveryCode(code))
+	}
+	return codes, hashes, nil
+}
+
+// hashRecoveryCode normalises case, spaces and dashes so codes can be typed loosely.
+func hashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(strings.TrimSpace(code)))
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,145 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ofkm/arcane-backend/internal/config"
+	"github.com/ofkm/arcane-backend/internal/models"
+	"github.com/ofkm/arcane-backend/internal/utils"
+	"github.com/ofkm/arcane-backend/internal/utils/totp"
+)
+
+func setupTotpAuthService(t *testing.T) (*AuthService, *models.User) {
+	t.Helper()
+	ctx := context.Background()
+	utils.InitEncryption(&config.Config{})
+
+	db := setupSettingsTestDB(t)
+	require.NoError(t, db.AutoMigrate(&models.User{}, &models.Event{}))
+	settings, err := NewSettingsService(ctx, db, nil)
+	require.NoError(t, err)
+	require.NoError(t, settings.EnsureDefaultSettings(ctx))
+
+	users := NewUserService(db, nil)
+	hash, err := users.HashPassword("secret-password")
+	require.NoError(t, err)
+	user, err := users.CreateUser(ctx, &models.User{Username: "alice", PasswordHash: hash, Roles: models.StringSlice{"admin"}})
+	require.NoError(t, err)
+
+	svc := newTestAuthService("")
+	svc.userService = users
+	svc.settingsService = settings
+	svc.eventService = NewEventService(db)
+	return svc, user
+}
+
+func currentTotpCode(t *testing.T, secret string, offset int64) string {
+	t.Helper()
+	code, err := totp.Code(secret, totp.Step(time.Now())+offset)
+	require.NoError(t, err)
+	return code
+}
+
+func TestTotp_EnableAndLogin(t *testing.T) {
+	ctx := context.Background()
+	svc, user := setupTotpAuthService(t)
+
+	_, tokens, challenge, err := svc.Login(ctx, "alice", "secret-password")
+	require.NoError(t, err)
+	require.NotNil(t, tokens, "accounts without TOTP sign in directly")
+	require.Nil(t, challenge)
+
+	setup, err := svc.BeginTotpSetup(ctx, user.ID)
+	require.NoError(t, err)
+	require.Contains(t, setup.URI, "otpauth://totp/Arcane:alice")
+
+	_, err = svc.EnableTotp(ctx, user.ID, "000000")
+	require.ErrorIs(t, err, ErrInvalidTotpCode)
+	recoveryCodes, err := svc.EnableTotp(ctx, user.ID, currentTotpCode(t, setup.Secret, -1))
+	require.NoError(t, err)
+	require.Len(t, recoveryCodes, recoveryCodeCount)
+
+	_, tokens, challenge, err = svc.Login(ctx, "alice", "secret-password")
+	require.NoError(t, err)
+	require.Nil(t, tokens)
+	require.NotNil(t, challenge)
+	require.False(t, challenge.EnrollmentRequired)
+
+	_, _, _, err = svc.VerifyLoginChallenge(ctx, challenge.Token, currentTotpCode(t, setup.Secret, -1))
+	require.ErrorIs(t, err, ErrInvalidTotpCode, "the code used to enable TOTP cannot be replayed")
+
+	_, tokens, _, err = svc.VerifyLoginChallenge(ctx, challenge.Token, currentTotpCode(t, setup.Secret, 0))
+	require.NoError(t, err)
+	require.NotEmpty(t, tokens.AccessToken)
+
+	_, _, _, err = svc.VerifyLoginChallenge(ctx, challenge.Token, currentTotpCode(t, setup.Secret, 1))
+	require.ErrorIs(t, err, ErrInvalidLoginChallenge, "a challenge can only be completed once")
+
+	// Recovery codes work once, in any case and without the dash.
+	_, _, challenge, err = svc.Login(ctx, "alice", "secret-password")
+	require.NoError(t, err)
+	loose := recoveryCodes[0][:5] + recoveryCodes[0][6:]
+	_, _, _, err = svc.VerifyLoginChallenge(ctx, challenge.Token, loose)
+	require.NoError(t, err)
+
+	_, _, challenge, err = svc.Login(ctx, "alice", "secret-password")
+	require.NoError(t, err)
+	_, _, _, err = svc.VerifyLoginChallenge(ctx, challenge.Token, recoveryCodes[0])
+	require.ErrorIs(t, err, ErrInvalidTotpCode)
+
+	require.ErrorIs(t, svc.DisableTotp(ctx, user.ID, "wrong", recoveryCodes[1]), ErrInvalidCredentials)
+	require.NoError(t, svc.DisableTotp(ctx, user.ID, "secret-password", recoveryCodes[1]))
+	reloaded, err := svc.userService.GetUserByID(ctx, user.ID)
+	require.NoError(t, err)
+	require.False(t, reloaded.TotpEnabled)
+	require.Nil(t, reloaded.TotpSecret)
+}
+
+func TestTotp_ChallengeAttemptLimit(t *testing.T) {
+	ctx := context.Background()
+	svc, user := setupTotpAuthService(t)
+
+	setup, err := svc.BeginTotpSetup(ctx, user.ID)
+	require.NoError(t, err)
+	_, err = svc.EnableTotp(ctx, user.ID, currentTotpCode(t, setup.Secret, -1))
+	require.NoError(t, err)
+
+	_, _, challenge, err := svc.Login(ctx, "alice", "secret-password")
+	require.NoError(t, err)
+	for range loginChallengeMaxAttempts {
+		_, _, _, err = svc.VerifyLoginChallenge(ctx, challenge.Token, "000000")
+		require.ErrorIs(t, err, ErrInvalidTotpCode)
+	}
+	_, _, _, err = svc.VerifyLoginChallenge(ctx, challenge.Token, currentTotpCode(t, setup.Secret, 0))
+	require.ErrorIs(t, err, ErrInvalidLoginChallenge)
+}
+
+func TestTotp_RequiredSettingForcesEnrollment(t *testing.T) {
+	ctx := context.Background()
+	svc, user := setupTotpAuthService(t)
+	require.NoError(t, svc.settingsService.UpdateSetting(ctx, "authRequireTotp", "true"))
+
+	_, tokens, challenge, err := svc.Login(ctx, "alice", "secret-password")
+	require.NoError(t, err)
+	require.Nil(t, tokens)
+	require.True(t, challenge.EnrollmentRequired)
+
+	setup, err := svc.BeginLoginEnrollment(ctx, challenge.Token)
+	require.NoError(t, err)
+
+	_, tokens, recoveryCodes, err := svc.VerifyLoginChallenge(ctx, challenge.Token, currentTotpCode(t, setup.Secret, 0))
+	require.NoError(t, err)
+	require.NotNil(t, tokens)
+	require.Len(t, recoveryCodes, recoveryCodeCount)
+
+	require.ErrorIs(t, svc.DisableTotp(ctx, user.ID, "secret-password", recoveryCodes[0]), ErrTotpRequiredBySettings)
+
+	require.NoError(t, svc.ResetTotp(ctx, user.ID))
+	_, _, challenge, err = svc.Login(ctx, "alice", "secret-password")
+	require.NoError(t, err)
+	require.True(t, challenge.EnrollmentRequired, "after an admin reset the user enrols again")
+}
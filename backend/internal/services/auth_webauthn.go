@@ -0,0 +1,344 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/ofkm/arcane-backend/internal/models"
+	"github.com/ofkm/arcane-backend/internal/utils"
+	"github.com/ofkm/arcane-backend/internal/utils/webauthn"
+)
+
+const (
+	passkeyRegistrationSubject = "webauthn_registration"
+	passkeyLoginSubject        = "webauthn_login"
+	passkeyRelyingPartyName    = "Arcane"
+	defaultPasskeyName         = "Passkey"
+	maxPasskeyNameLength       = 64
+)
+
+var (
+	ErrPasskeysDisabled      = errors.New("passkey authentication is disabled")
+	ErrPasskeyNotFound       = errors.New("passkey not found")
+	ErrPasskeyAlreadyExists  = errors.New("this passkey is already registered")
+	ErrInvalidPasskeySession = errors.New("passkey request is invalid or has expired")
+	ErrPasskeyVerifyFailed   = errors.New("passkey verification failed")
+	ErrPasskeyUserMismatch   = errors.New("passkey does not belong to this account")
+	ErrPasskeyNameTooLong    = errors.New("passkey name must be at most 64 characters")
+
+	errUnknownPasskey = fmt.Errorf("%w: unknown credential", ErrPasskeyVerifyFailed)
+)
+
+// PasskeyCeremony is handed to the browser to start a registration or sign in. Options
+// go to navigator.credentials.create/get and SessionToken comes back with the result.
+type PasskeyCeremony struct {
+	Options      any    `json:"options"`
+	SessionToken string `json:"sessionToken"`
+}
+
+type passkeySessionClaims struct {
+	jwt.RegisteredClaims
+	Challenge string `json:"challenge"`
+}
+
+func (s *AuthService) relyingParty(ctx context.Context) (*webauthn.RelyingParty, error) {
+	if !s.settingsService.GetBoolSetting(ctx, "authPasskeysEnabled", true) {
+		return nil, ErrPasskeysDisabled
+	}
+	return webauthn.NewRelyingParty(s.config.AppUrl, passkeyRelyingPartyName)
+}
+
+func (s *AuthService) newPasskeySession(subject, userID, challenge string) (string, error) {
+	claims := passkeySessionClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        utils.GenerateRandomString(24),
+			Subject:   subject,
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(webauthn.Timeout)),
+		},
+		Challenge: challenge,
+	}
+	if userID != "" {
+		claims.Audience = jwt.ClaimStrings{userID}
+	}
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(s.jwtSecret)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign passkey session: %w", err)
+	}
+	return signed, nil
+}
+
+// parsePasskeySession checks a session token and that it has not been used yet. For
+// registration sessions userID must match the user the session was issued to.
+func (s *AuthService) parsePasskeySession(sessionToken, subject, userID string) (*passkeySessionClaims, error) {
+	claims := &passkeySessionClaims{}
+	token, err := jwt.ParseWithClaims(sessionToken, claims, func(t *jwt.Token) (interface{}, error) {
+		return s.jwtSecret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	if err != nil || !token.Valid || claims.Subject != subject || claims.ID == "" || claims.Challenge == "" || claims.ExpiresAt == nil {
+		return nil, ErrInvalidPasskeySession
+	}
+	if userID != "" && (len(claims.Audience) != 1 || claims.Audience[0] != userID) {
+		return nil, ErrInvalidPasskeySession
+	}
+	if !s.challenges.allow(claims.ID, claims.ExpiresAt.Time) {
+		return nil, ErrInvalidPasskeySession
+	}
+	return claims, nil
+}
+
+// BeginPasskeyRegistration returns the options for creating a new passkey for the
+// signed-in user. Passkeys they already registered are excluded so the same
+// authenticator is not registered twice.
+func (s *AuthService) BeginPasskeyRegistration(ctx context.Context, userID string) (*PasskeyCeremony, error) {
+	rp, err := s.relyingParty(ctx)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.userService.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := s.ListPasskeys(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	exclude := make([]webauthn.CredentialDescriptor, 0, len(existing))
+	for _, cred := range existing {
+		exclude = append(exclude, webauthn.CredentialDescriptor{Type: "public-key", ID: cred.CredentialID, Transports: cred.Transports})
+	}
+
+	challenge, err := webauthn.NewChallenge()
+	if err != nil {
+		return nil, err
+	}
+	session, err := s.newPasskeySession(passkeyRegistrationSubject, user.ID, challenge)
+	if err != nil {
+		return nil, err
+	}
+
+	displayName := user.Username
+	if user.DisplayName != nil && *user.DisplayName != "" {
+		displayName = *user.DisplayName
+	}
+	options := rp.CreationOptions(challenge, []byte(user.ID), user.Username, displayName, exclude)
+	return &PasskeyCeremony{Options: options, SessionToken: session}, nil
+}
+
+// FinishPasskeyRegistration verifies the browser's response and stores the passkey.
+func (s *AuthService) FinishPasskeyRegistration(ctx context.Context, userID, sessionToken, name string, resp *webauthn.RegistrationResponse) (*models.WebauthnCredential, error) {
+	rp, err := s.relyingParty(ctx)
+	if err != nil {
+		return nil, err
+	}
+	name, err = normalizePasskeyName(name)
+	if err != nil {
+		return nil, err
+	}
+	claims, err := s.parsePasskeySession(sessionToken, passkeyRegistrationSubject, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	verified, err := rp.VerifyRegistration(resp, claims.Challenge)
+	if err != nil {
+		s.challenges.fail(claims.ID)
+		return nil, fmt.Errorf("%w: %w", ErrPasskeyVerifyFailed, err)
+	}
+	s.challenges.consume(claims.ID)
+
+	credentialID := webauthn.EncodeID(verified.ID)
+	var count int64
+	if err := s.userService.db.WithContext(ctx).Model(&models.WebauthnCredential{}).Where("credential_id = ?", credentialID).Count(&count).Error; err != nil {
+		return nil, fmt.Errorf("failed to check existing passkeys: %w", err)
+	}
+	if count > 0 {
+		return nil, ErrPasskeyAlreadyExists
+	}
+
+	cred := &models.WebauthnCredential{
+		UserID:       userID,
+		Name:         name,
+		CredentialID: credentialID,
+		PublicKey:    verified.PublicKey,
+		AAGUID:       formatAAGUID(verified.AAGUID),
+		SignCount:    verified.SignCount,
+		Transports:   models.StringSlice(verified.Transports),
+		BackedUp:     verified.BackedUp,
+	}
+	if err := s.userService.db.WithContext(ctx).Create(cred).Error; err != nil {
+		return nil, fmt.Errorf("failed to save passkey: %w", err)
+	}
+	return cred, nil
+}
+
+// ListPasskeys returns the user's passkeys, newest first.
+func (s *AuthService) ListPasskeys(ctx context.Context, userID string) ([]models.WebauthnCredential, error) {
+	var creds []models.WebauthnCredential
+	if err := s.userService.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at DESC").Find(&creds).Error; err != nil {
+		return nil, fmt.Errorf("failed to list passkeys: %w", err)
+	}
+	return creds, nil
+}
+
+func (s *AuthService) RenamePasskey(ctx context.Context, userID, id, name string) (*models.WebauthnCredential, error) {
+	name, err := normalizePasskeyName(name)
+	if err != nil {
+		return nil, err
+	}
+	cred, err := s.getUserPasskey(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	cred.Name = name
+	if err := s.userService.db.WithContext(ctx).Model(cred).Update("name", name).Error; err != nil {
+		return nil, fmt.Errorf("failed to rename passkey: %w", err)
+	}
+	return cred, nil
+}
+
+func (s *AuthService) DeletePasskey(ctx context.Context, userID, id string) error {
+	cred, err := s.getUserPasskey(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+	if err := s.userService.db.WithContext(ctx).Delete(cred).Error; err != nil {
+		return fmt.Errorf("failed to delete passkey: %w", err)
+	}
+	return nil
+}
+
+func (s *AuthService) getUserPasskey(ctx context.Context, userID, id string) (*models.WebauthnCredential, error) {
+	var creds []models.WebauthnCredential
+	if err := s.userService.db.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).Limit(1).Find(&creds).Error; err != nil {
+		return nil, fmt.Errorf("failed to get passkey: %w", err)
+	}
+	if len(creds) == 0 {
+		return nil, ErrPasskeyNotFound
+	}
+	return &creds[0], nil
+}
+
+// BeginPasskeyLogin returns the options for signing in with any passkey registered for
+// this site; the browser lets the user pick one.
+func (s *AuthService) BeginPasskeyLogin(ctx context.Context) (*PasskeyCeremony, error) {
+	rp, err := s.relyingParty(ctx)
+	if err != nil {
+		return nil, err
+	}
+	challenge, err := webauthn.NewChallenge()
+	if err != nil {
+		return nil, err
+	}
+	session, err := s.newPasskeySession(passkeyLoginSubject, "", challenge)
+	if err != nil {
+		return nil, err
+	}
+	return &PasskeyCeremony{Options: rp.RequestOptions(challenge), SessionToken: session}, nil
+}
+
+// FinishPasskeyLogin verifies a passkey assertion and issues tokens like Login. Passkeys
+// are created and used with user verification, so they already satisfy the two-factor
+// requirement and no TOTP challenge follows.
+func (s *AuthService) FinishPasskeyLogin(ctx context.Context, sessionToken string, resp *webauthn.AssertionResponse) (*models.User, *TokenPair, error) {
+	rp, err := s.relyingParty(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	claims, err := s.parsePasskeySession(sessionToken, passkeyLoginSubject, "")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cred, err := s.passkeyForAssertion(ctx, resp)
+	if err != nil {
+		s.challenges.fail(claims.ID)
+		return nil, nil, err
+	}
+	assertion, err := rp.VerifyAssertion(resp, claims.Challenge, cred.PublicKey, cred.SignCount)
+	if err != nil {
+		s.challenges.fail(claims.ID)
+		return nil, nil, fmt.Errorf("%w: %w", ErrPasskeyVerifyFailed, err)
+	}
+	s.challenges.consume(claims.ID)
+
+	// The sign count is only advanced from the value that was checked, so two concurrent
+	// logins with a cloned authenticator cannot both succeed.
+	now := time.Now()
+	res := s.userService.db.WithContext(ctx).Model(&models.WebauthnCredential{}).
+		Where("id = ? AND sign_count = ?", cred.ID, cred.SignCount).
+		Updates(map[string]any{"sign_count": assertion.SignCount, "backed_up": assertion.BackedUp, "last_used_at": now})
+	if res.Error != nil {
+		return nil, nil, fmt.Errorf("failed to update passkey: %w", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return nil, nil, fmt.Errorf("%w: %w", ErrPasskeyVerifyFailed, webauthn.ErrSignCountRegressed)
+	}
+
+	user, err := s.userService.GetUserByID(ctx, cred.UserID)
+	if err != nil {
+		return nil, nil, err
+	}
+	tokenPair, err := s.completeLogin(ctx, user, models.JSON{"action": "login", "method": "passkey", "passkey": cred.Name})
+	if err != nil {
+		return nil, nil, err
+	}
+	return user, tokenPair, nil
+}
+
+func (s *AuthService) passkeyForAssertion(ctx context.Context, resp *webauthn.AssertionResponse) (*models.WebauthnCredential, error) {
+	rawID, err := webauthn.DecodeID(resp.RawID)
+	if err != nil {
+		return nil, errUnknownPasskey
+	}
+	var creds []models.WebauthnCredential
+	if err := s.userService.db.WithContext(ctx).Where("credential_id = ?", webauthn.EncodeID(rawID)).Limit(1).Find(&creds).Error; err != nil {
+		return nil, fmt.Errorf("failed to get passkey: %w", err)
+	}
+	if len(creds) == 0 {
+		return nil, errUnknownPasskey
+	}
+	cred := &creds[0]
+
+	if resp.Response.UserHandle != "" {
+		handle, err := webauthn.DecodeID(resp.Response.UserHandle)
+		if err != nil || string(handle) != cred.UserID {
+			return nil, ErrPasskeyUserMismatch
+		}
+	}
+	return cred, nil
+}
+
+func normalizePasskeyName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return defaultPasskeyName, nil
+	}
+	if len([]rune(name)) > maxPasskeyNameLength {
+		return "", ErrPasskeyNameTooLong
+	}
+	return name, nil
+}
+
+// formatAAGUID renders the authenticator model ID as a UUID, or "" when the
+// authenticator does not disclose it (all zeroes).
+func formatAAGUID(b []byte) string {
+	if len(b) != 16 {
+		return ""
+	}
+	zero := true
+	for _, c := range b {
+		if c != 0 {
+			zero = false
+			break
+		}
+	}
+	if zero {
+		return ""
+	}
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
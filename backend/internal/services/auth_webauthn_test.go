@@ -0,0 +1,130 @@
+package services
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ofkm/arcane-backend/internal/models"
+	"github.com/ofkm/arcane-backend/internal/utils/webauthn"
+)
+
+const testAppURL = "https://arcane.example.com"
+
+// testPasskey signs WebAuthn ceremonies like a platform authenticator with user
+// verification and no sign counter.
+type testPasskey struct {
+	key *ecdsa.PrivateKey
+	id  []byte
+}
+
+func (p *testPasskey) authData(flags byte, attested []byte) []byte {
+	rpIDHash := sha256.Sum256([]byte("arcane.example.com"))
+	b := append(rpIDHash[:], flags, 0, 0, 0, 0)
+	return append(b, attested...)
+}
+
+func (p *testPasskey) clientData(t *testing.T, typ, challenge string) string {
+	b, err := json.Marshal(map[string]string{"type": typ, "challenge": challenge, "origin": testAppURL})
+	require.NoError(t, err)
+	return webauthn.EncodeID(b)
+}
+
+func (p *testPasskey) register(t *testing.T, challenge string) webauthn.RegistrationResponse {
+	pub, err := p.key.PublicKey.Bytes()
+	require.NoError(t, err)
+	coseKey, err := cbor.Marshal(map[int64]any{1: 2, 3: -7, -1: 1, -2: pub[1:33], -3: pub[33:]})
+	require.NoError(t, err)
+	attested := binary.BigEndian.AppendUint16(make([]byte, 16), uint16(len(p.id)))
+	attested = append(append(attested, p.id...), coseKey...)
+	attObj, err := cbor.Marshal(map[string]any{"fmt": "none", "attStmt": map[string]any{}, "authData": p.authData(0x45, attested)})
+	require.NoError(t, err)
+
+	resp := webauthn.RegistrationResponse{ID: webauthn.EncodeID(p.id), RawID: webauthn.EncodeID(p.id), Type: "public-key"}
+	resp.Response.ClientDataJSON = p.clientData(t, "webauthn.create", challenge)
+	resp.Response.AttestationObject = webauthn.EncodeID(attObj)
+	return resp
+}
+
+func (p *testPasskey) assert(t *testing.T, challenge string, userHandle string) webauthn.AssertionResponse {
+	authData := p.authData(0x05, nil)
+	clientData := p.clientData(t, "webauthn.get", challenge)
+	raw, err := webauthn.DecodeID(clientData)
+	require.NoError(t, err)
+	clientHash := sha256.Sum256(raw)
+	digest := sha256.Sum256(append(append([]byte{}, authData...), clientHash[:]...))
+	sig, err := ecdsa.SignASN1(rand.Reader, p.key, digest[:])
+	require.NoError(t, err)
+
+	resp := webauthn.AssertionResponse{ID: webauthn.EncodeID(p.id), RawID: webauthn.EncodeID(p.id), Type: "public-key"}
+	resp.Response.ClientDataJSON = clientData
+	resp.Response.AuthenticatorData = webauthn.EncodeID(authData)
+	resp.Response.Signature = webauthn.EncodeID(sig)
+	resp.Response.UserHandle = webauthn.EncodeID([]byte(userHandle))
+	return resp
+}
+
+func TestPasskeys_RegisterAndLogin(t *testing.T) {
+	ctx := context.Background()
+	svc, user := setupTotpAuthService(t)
+	svc.config.AppUrl = testAppURL
+	require.NoError(t, svc.userService.db.AutoMigrate(&models.WebauthnCredential{}))
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	passkey := &testPasskey{key: key, id: []byte("passkey-1")}
+
+	ceremony, err := svc.BeginPasskeyRegistration(ctx, user.ID)
+	require.NoError(t, err)
+	creation := ceremony.Options.(*webauthn.CreationOptions)
+	require.Equal(t, "arcane.example.com", creation.RP.ID)
+
+	resp := passkey.register(t, creation.Challenge)
+	_, err = svc.FinishPasskeyRegistration(ctx, "someone-else", ceremony.SessionToken, "", &resp)
+	require.ErrorIs(t, err, ErrInvalidPasskeySession)
+	cred, err := svc.FinishPasskeyRegistration(ctx, user.ID, ceremony.SessionToken, "YubiKey", &resp)
+	require.NoError(t, err)
+	require.Equal(t, "YubiKey", cred.Name)
+	_, err = svc.FinishPasskeyRegistration(ctx, user.ID, ceremony.SessionToken, "YubiKey", &resp)
+	require.ErrorIs(t, err, ErrInvalidPasskeySession, "a registration session is single use")
+
+	// Passkeys satisfy the two-factor requirement on their own.
+	require.NoError(t, svc.settingsService.UpdateSetting(ctx, "authRequireTotp", "true"))
+
+	login, err := svc.BeginPasskeyLogin(ctx)
+	require.NoError(t, err)
+	challenge := login.Options.(*webauthn.RequestOptions).Challenge
+
+	wrongUser := passkey.assert(t, challenge, "someone-else")
+	_, _, err = svc.FinishPasskeyLogin(ctx, login.SessionToken, &wrongUser)
+	require.ErrorIs(t, err, ErrPasskeyUserMismatch)
+
+	assertion := passkey.assert(t, challenge, user.ID)
+	loggedIn, tokens, err := svc.FinishPasskeyLogin(ctx, login.SessionToken, &assertion)
+	require.NoError(t, err)
+	require.Equal(t, user.ID, loggedIn.ID)
+	require.NotEmpty(t, tokens.AccessToken)
+
+	_, _, err = svc.FinishPasskeyLogin(ctx, login.SessionToken, &assertion)
+	require.ErrorIs(t, err, ErrInvalidPasskeySession, "a login challenge cannot be replayed")
+
+	passkeys, err := svc.ListPasskeys(ctx, user.ID)
+	require.NoError(t, err)
+	require.Len(t, passkeys, 1)
+	require.NotNil(t, passkeys[0].LastUsedAt)
+
+	require.ErrorIs(t, svc.DeletePasskey(ctx, "someone-else", cred.ID), ErrPasskeyNotFound)
+	require.NoError(t, svc.DeletePasskey(ctx, user.ID, cred.ID))
+
+	require.NoError(t, svc.settingsService.UpdateSetting(ctx, "authPasskeysEnabled", "false"))
+	_, err = svc.BeginPasskeyLogin(ctx)
+	require.ErrorIs(t, err, ErrPasskeysDisabled)
+}
@@ -14,11 +14,12 @@ import (
 )
 
 type ContainerRegistryService struct {
-	db *database.DB
+	db    *database.DB
+	audit *AuditService
 }
 
-func NewContainerRegistryService(db *database.DB) *ContainerRegistryService {
-	return &ContainerRegistryService{db: db}
+func NewContainerRegistryService(db *database.DB, audit *AuditService) *ContainerRegistryService {
+	return &ContainerRegistryService{db: db, audit: audit}
 }
 
 func (s *ContainerRegistryService) GetAllRegistries(ctx context.Context) ([]models.ContainerRegistry, error) {
@@ -102,6 +103,14 @@ func (s *ContainerRegistryService) CreateRegistry(ctx context.Context, req model
 		return nil, fmt.Errorf("failed to create registry: %w", err)
 	}
 
+	s.audit.Record(ctx, AuditChange{
+		Action:     auditActionCreate,
+		TargetType: auditTargetContainerRegistry,
+		TargetID:   registry.ID,
+		TargetName: registry.URL,
+		After:      registry,
+	})
+
 	return registry, nil
 }
 
@@ -110,6 +119,7 @@ func (s *ContainerRegistryService) UpdateRegistry(ctx context.Context, id string
 	if err != nil {
 		return nil, err
 	}
+	before := *registry
 
 	// Update fields
 	utils.UpdateIfChanged(&registry.URL, req.URL)
@@ -134,13 +144,34 @@ func (s *ContainerRegistryService) UpdateRegistry(ctx context.Context, id string
 		return nil, fmt.Errorf("failed to update registry: %w", err)
 	}
 
+	s.audit.Record(ctx, AuditChange{
+		Action:     auditActionUpdate,
+		TargetType: auditTargetContainerRegistry,
+		TargetID:   registry.ID,
+		TargetName: registry.URL,
+		Before:     &before,
+		After:      registry,
+	})
+
 	return registry, nil
 }
 
 func (s *ContainerRegistryService) DeleteRegistry(ctx context.Context, id string) error {
+	before, _ := s.GetRegistryByID(ctx, id)
+
 	if err := s.db.WithContext(ctx).Where("id = ?", id).Delete(&models.ContainerRegistry{}).Error; err != nil {
 		return fmt.Errorf("failed to delete container registry: %w", err)
 	}
+
+	if before != nil {
+		s.audit.Record(ctx, AuditChange{
+			Action:     auditActionDelete,
+			TargetType: auditTargetContainerRegistry,
+			TargetID:   id,
+			TargetName: before.URL,
+			Before:     before,
+		})
+	}
 	return nil
 }
 
@@ -26,13 +26,14 @@ type EnvironmentService struct {
 	db            *database.DB
 	httpClient    *http.Client
 	dockerService *DockerClientService
+	audit         *AuditService
 }
 
-func NewEnvironmentService(db *database.DB, httpClient *http.Client, dockerService *DockerClientService) *EnvironmentService {
+func NewEnvironmentService(db *database.DB, httpClient *http.Client, dockerService *DockerClientService, audit *AuditService) *EnvironmentService {
 	if httpClient == nil {
 		httpClient = http.DefaultClient
 	}
-	return &EnvironmentService{db: db, httpClient: httpClient, dockerService: dockerService}
+	return &EnvironmentService{db: db, httpClient: httpClient, dockerService: dockerService, audit: audit}
 }
 
 func (s *EnvironmentService) EnsureLocalEnvironment(ctx context.Context, appUrl string) error {
@@ -83,6 +84,14 @@ func (s *EnvironmentService) CreateEnvironment(ctx context.Context, environment
 		return nil, fmt.Errorf("failed to create environment: %w", err)
 	}
 
+	s.audit.Record(ctx, AuditChange{
+		Action:     auditActionCreate,
+		TargetType: auditTargetEnvironment,
+		TargetID:   environment.ID,
+		TargetName: environment.Name,
+		After:      auditEnvironmentSnapshot(environment),
+	})
+
 	return environment, nil
 }
 
@@ -135,6 +144,8 @@ func (s *EnvironmentService) ListEnvironmentsPaginated(ctx context.Context, para
 }
 
 func (s *EnvironmentService) UpdateEnvironment(ctx context.Context, id string, updates map[string]interface{}) (*models.Environment, error) {
+	before, _ := s.GetEnvironmentByID(ctx, id)
+
 	now := time.Now()
 	updates["updated_at"] = &now
 
@@ -142,16 +153,56 @@ func (s *EnvironmentService) UpdateEnvironment(ctx context.Context, id string, u
 		return nil, fmt.Errorf("failed to update environment: %w", err)
 	}
 
-	return s.GetEnvironmentByID(ctx, id)
+	after, err := s.GetEnvironmentByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if before != nil {
+		s.audit.Record(ctx, AuditChange{
+			Action:     auditActionUpdate,
+			TargetType: auditTargetEnvironment,
+			TargetID:   id,
+			TargetName: after.Name,
+			Before:     auditEnvironmentSnapshot(before),
+			After:      auditEnvironmentSnapshot(after),
+		})
+	}
+
+	return after, nil
 }
 
 func (s *EnvironmentService) DeleteEnvironment(ctx context.Context, id string) error {
+	before, _ := s.GetEnvironmentByID(ctx, id)
+
 	if err := s.db.WithContext(ctx).Delete(&models.Environment{}, "id = ?", id).Error; err != nil {
 		return fmt.Errorf("failed to delete environment: %w", err)
 	}
+
+	if before != nil {
+		s.audit.Record(ctx, AuditChange{
+			Action:     auditActionDelete,
+			TargetType: auditTargetEnvironment,
+			TargetID:   id,
+			TargetName: before.Name,
+			Before:     auditEnvironmentSnapshot(before),
+		})
+	}
 	return nil
 }
 
+// auditEnvironmentSnapshot exposes the agent access token, which the environment's JSON
+// omits, so that a re-pairing appears (redacted) in the audit diff. Heartbeat fields are
+// left out.
+func auditEnvironmentSnapshot(environment *models.Environment) any {
+	return map[string]any{
+		"name":        environment.Name,
+		"apiUrl":      environment.ApiUrl,
+		"enabled":     environment.Enabled,
+		"accessToken": environment.AccessToken,
+	}
+}
+
 func (s *EnvironmentService) TestConnection(ctx context.Context, id string, customApiUrl *string) (string, error) {
 	environment, err := s.GetEnvironmentByID(ctx, id)
 	if err != nil {
@@ -16,6 +16,10 @@ import (
 
 type EventService struct {
 	db *database.DB
+
+	// OnEventCreated is called after every stored event. It must not block; notification
+	// subscriptions and event sinks use it to fan events out in the background.
+	OnEventCreated func(ctx context.Context, event models.Event)
 }
 
 func NewEventService(db *database.DB) *EventService {
@@ -64,6 +68,10 @@ func (s *EventService) CreateEvent(ctx context.Context, req CreateEventRequest)
 		return nil, fmt.Errorf("failed to create event: %w", err)
 	}
 
+	if s.OnEventCreated != nil {
+		s.OnEventCreated(ctx, *event)
+	}
+
 	return event, nil
 }
 
@@ -435,6 +443,10 @@ func (s *EventService) generateEventTitle(eventType models.EventType, resourceNa
 		return fmt.Sprintf("Volume deleted: %s", resourceName)
 	case models.EventTypeVolumeError:
 		return fmt.Sprintf("Volume error: %s", resourceName)
+	case models.EventTypeVolumeBackup:
+		return fmt.Sprintf("Volume backed up: %s", resourceName)
+	case models.EventTypeVolumeRestore:
+		return fmt.Sprintf("Volume restored: %s", resourceName)
 	case models.EventTypeNetworkCreate:
 		return fmt.Sprintf("Network created: %s", resourceName)
 	case models.EventTypeNetworkDelete:
@@ -451,6 +463,10 @@ func (s *EventService) generateEventTitle(eventType models.EventType, resourceNa
 		return fmt.Sprintf("User logged in: %s", resourceName)
 	case models.EventTypeUserLogout:
 		return fmt.Sprintf("User logged out: %s", resourceName)
+	case models.EventTypeUserSessionRevoked:
+		return fmt.Sprintf("Session revoked: %s", resourceName)
+	case models.EventTypeUserLoginFailed:
+		return fmt.Sprintf("Failed login: %s", resourceName)
 	default:
 		return fmt.Sprintf("Event: %s", string(eventType))
 	}
@@ -502,6 +518,10 @@ func (s *EventService) generateEventDescription(eventType models.EventType, reso
 		return fmt.Sprintf("Volume '%s' has been deleted", resourceName)
 	case models.EventTypeVolumeError:
 		return fmt.Sprintf("An error occurred with volume '%s'", resourceName)
+	case models.EventTypeVolumeBackup:
+		return fmt.Sprintf("Volume '%s' has been backed up", resourceName)
+	case models.EventTypeVolumeRestore:
+		return fmt.Sprintf("Volume '%s' has been restored from a backup", resourceName)
 	case models.EventTypeNetworkCreate:
 		return fmt.Sprintf("Network '%s' has been created", resourceName)
 	case models.EventTypeNetworkDelete:
@@ -518,6 +538,10 @@ func (s *EventService) generateEventDescription(eventType models.EventType, reso
 		return fmt.Sprintf("User '%s' has logged in", resourceName)
 	case models.EventTypeUserLogout:
 		return fmt.Sprintf("User '%s' has logged out", resourceName)
+	case models.EventTypeUserSessionRevoked:
+		return fmt.Sprintf("One or more sessions of user '%s' have been revoked", resourceName)
+	case models.EventTypeUserLoginFailed:
+		return fmt.Sprintf("A sign-in attempt for '%s' has failed", resourceName)
 	default:
 		return fmt.Sprintf("%s operation performed on %s '%s'", string(eventType), resourceType, resourceName)
 	}
@@ -525,9 +549,9 @@ func (s *EventService) generateEventDescription(eventType models.EventType, reso
 
 func (s *EventService) getEventSeverity(eventType models.EventType) models.EventSeverity {
 	switch eventType {
-	case models.EventTypeContainerDelete, models.EventTypeImageDelete, models.EventTypeProjectDelete, models.EventTypeVolumeDelete, models.EventTypeNetworkDelete:
+	case models.EventTypeContainerDelete, models.EventTypeImageDelete, models.EventTypeProjectDelete, models.EventTypeVolumeDelete, models.EventTypeNetworkDelete, models.EventTypeUserSessionRevoked, models.EventTypeUserLoginFailed:
 		return models.EventSeverityWarning
-	case models.EventTypeContainerStart, models.EventTypeContainerCreate, models.EventTypeImagePull, models.EventTypeImageLoad, models.EventTypeProjectDeploy, models.EventTypeProjectStart, models.EventTypeProjectCreate, models.EventTypeVolumeCreate, models.EventTypeNetworkCreate:
+	case models.EventTypeContainerStart, models.EventTypeContainerCreate, models.EventTypeImagePull, models.EventTypeImageLoad, models.EventTypeProjectDeploy, models.EventTypeProjectStart, models.EventTypeProjectCreate, models.EventTypeVolumeCreate, models.EventTypeVolumeBackup, models.EventTypeVolumeRestore, models.EventTypeNetworkCreate:
 		return models.EventSeveritySuccess
 	case models.EventTypeContainerStop, models.EventTypeContainerRestart, models.EventTypeContainerScan, models.EventTypeContainerUpdate, models.EventTypeImageScan, models.EventTypeProjectStop, models.EventTypeProjectUpdate, models.EventTypeSystemPrune, models.EventTypeSystemAutoUpdate, models.EventTypeSystemUpgrade, models.EventTypeUserLogin, models.EventTypeUserLogout:
 		return models.EventSeverityInfo
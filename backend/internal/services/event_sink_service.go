@@ -0,0 +1,197 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ofkm/arcane-backend/internal/models"
+)
+
+const (
+	eventSinkQueueSize = 1024
+	eventSinkBatchSize = 100
+
+	eventSinkKindEvent = "event"
+	eventSinkKindAudit = "audit"
+)
+
+// eventSinkRecord is what every sink receives: one event or one audit entry, with the
+// fields log systems index on pulled to the top level.
+type eventSinkRecord struct {
+	Kind      string           `json:"kind"`
+	Timestamp time.Time        `json:"timestamp"`
+	Type      string           `json:"type"`
+	Severity  string           `json:"severity"`
+	Message   string           `json:"message"`
+	Event     *models.Event    `json:"event,omitempty"`
+	Audit     *models.AuditLog `json:"audit,omitempty"`
+}
+
+// eventSink delivers batches of records to one external system. Write is only called
+// from the sink worker, so implementations need no locking of their own.
+type eventSink interface {
+	Name() string
+	Write(ctx context.Context, records []eventSinkRecord) error
+	Close() error
+}
+
+// EventSinkService copies events and audit entries to the syslog, Loki and JSONL sinks
+// enabled in settings. Records are queued and written by a single background worker so
+// that a slow or unreachable sink never delays the request that produced them; when the
+// queue is full new records are dropped.
+type EventSinkService struct {
+	settingsService *SettingsService
+
+	queue   chan eventSinkRecord
+	active  atomic.Bool
+	dropped atomic.Int64
+
+	// mu guards sinks and is held for every write, so Reload never closes a sink the
+	// worker is using. Publishing does not take it.
+	mu    sync.Mutex
+	sinks []eventSink
+}
+
+func NewEventSinkService(settingsService *SettingsService) *EventSinkService {
+	return &EventSinkService{
+		settingsService: settingsService,
+		queue:           make(chan eventSinkRecord, eventSinkQueueSize),
+	}
+}
+
+// Start builds the configured sinks and runs the worker until ctx is cancelled.
+func (s *EventSinkService) Start(ctx context.Context) {
+	s.Reload(ctx)
+	go s.run(ctx)
+}
+
+// Reload replaces the sinks with the ones currently enabled in settings. A sink whose
+// settings are invalid is logged and left out.
+func (s *EventSinkService) Reload(ctx context.Context) {
+	var sinks []eventSink
+	for _, build := range []func(context.Context, *SettingsService) (eventSink, error){
+		newSyslogEventSink,
+		newLokiEventSink,
+		newFileEventSink,
+	} {
+		sink, err := build(ctx, s.settingsService)
+		if err != nil {
+			slog.WarnContext(ctx, "Event sink disabled: invalid settings", "error", err)
+			continue
+		}
+		if sink != nil {
+			sinks = append(sinks, sink)
+		}
+	}
+
+	s.mu.Lock()
+	old := s.sinks
+	s.sinks = sinks
+	s.active.Store(len(sinks) > 0)
+	s.mu.Unlock()
+
+	closeEventSinks(ctx, old)
+
+	names := make([]string, 0, len(sinks))
+	for _, sink := range sinks {
+		names = append(names, sink.Name())
+	}
+	slog.InfoContext(ctx, "Event sinks configured", "sinks", names)
+}
+
+func (s *EventSinkService) PublishEvent(ctx context.Context, event models.Event) {
+	title := event.Title
+	if title == "" {
+		title = string(event.Type)
+	}
+	s.enqueue(ctx, eventSinkRecord{
+		Kind:      eventSinkKindEvent,
+		Timestamp: event.Timestamp,
+		Type:      string(event.Type),
+		Severity:  string(event.Severity),
+		Message:   title,
+		Event:     &event,
+	})
+}
+
+func (s *EventSinkService) PublishAudit(ctx context.Context, entry models.AuditLog) {
+	actor := "system"
+	if entry.Username != nil {
+		actor = *entry.Username
+	}
+	message := actor + " " + entry.Action
+	if entry.TargetName != nil {
+		message += " " + *entry.TargetName
+	}
+	s.enqueue(ctx, eventSinkRecord{
+		Kind:      eventSinkKindAudit,
+		Timestamp: entry.Timestamp,
+		Type:      entry.Action,
+		Severity:  "notice",
+		Message:   message,
+		Audit:     &entry,
+	})
+}
+
+func (s *EventSinkService) enqueue(ctx context.Context, record eventSinkRecord) {
+	if !s.active.Load() {
+		return
+	}
+
+	select {
+	case s.queue <- record:
+	default:
+		if dropped := s.dropped.Add(1); dropped == 1 || dropped%100 == 0 {
+			slog.WarnContext(ctx, "Event sink queue full; dropping records", "dropped", dropped)
+		}
+	}
+}
+
+func (s *EventSinkService) run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			s.mu.Lock()
+			sinks := s.sinks
+			s.sinks = nil
+			s.active.Store(false)
+			s.mu.Unlock()
+			closeEventSinks(context.WithoutCancel(ctx), sinks)
+			return
+		case record := <-s.queue:
+			batch := []eventSinkRecord{record}
+		drain:
+			for len(batch) < eventSinkBatchSize {
+				select {
+				case next := <-s.queue:
+					batch = append(batch, next)
+				default:
+					break drain
+				}
+			}
+			s.write(ctx, batch)
+		}
+	}
+}
+
+func (s *EventSinkService) write(ctx context.Context, batch []eventSinkRecord) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, sink := range s.sinks {
+		if err := sink.Write(ctx, batch); err != nil {
+			slog.WarnContext(ctx, "Failed to write to event sink", "sink", sink.Name(), "records", len(batch), "error", err)
+		}
+	}
+}
+
+func closeEventSinks(ctx context.Context, sinks []eventSink) {
+	for _, sink := range sinks {
+		if err := sink.Close(); err != nil {
+			slog.WarnContext(ctx, "Failed to close event sink", "sink", sink.Name(), "error", err)
+		}
+	}
+}
@@ -0,0 +1,428 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ofkm/arcane-backend/internal/models"
+	appfs "github.com/ofkm/arcane-backend/internal/utils/fs"
+)
+
+const (
+	eventSinkTimeout = 10 * time.Second
+
+	syslogAppName = "arcane"
+	// RFC 5424 facilities: local0 for operational events, log audit for audit entries.
+	syslogFacilityLocal0   = 16
+	syslogFacilityLogAudit = 13
+	syslogMsgIDMaxLen      = 32
+
+	lokiPushPath = "/loki/api/v1/push"
+)
+
+// syslogEventSink sends one RFC 5424 message per record. Over TCP and TLS messages are
+// framed by octet counting (RFC 6587 / RFC 5425).
+type syslogEventSink struct {
+	protocol string
+	address  string
+	hostname string
+	conn     net.Conn
+}
+
+func newSyslogEventSink(ctx context.Context, settings *SettingsService) (eventSink, error) {
+	if !settings.GetBoolSetting(ctx, "eventSinkSyslogEnabled", false) {
+		return nil, nil
+	}
+
+	protocol := strings.ToLower(settings.GetStringSetting(ctx, "eventSinkSyslogProtocol", "udp"))
+	switch protocol {
+	case "udp", "tcp", "tls":
+	default:
+		return nil, fmt.Errorf("syslog protocol must be udp, tcp or tls, got %q", protocol)
+	}
+
+	address := strings.TrimSpace(settings.GetStringSetting(ctx, "eventSinkSyslogAddress", ""))
+	if _, _, err := net.SplitHostPort(address); err != nil {
+		return nil, fmt.Errorf("syslog address must be host:port: %w", err)
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "-"
+	}
+
+	return &syslogEventSink{protocol: protocol, address: address, hostname: hostname}, nil
+}
+
+func (s *syslogEventSink) Name() string { return "syslog" }
+
+func (s *syslogEventSink) Write(ctx context.Context, records []eventSinkRecord) error {
+	for i := range records {
+		msg, err := s.format(&records[i])
+		if err != nil {
+			return err
+		}
+		if s.protocol != "udp" {
+			msg = append([]byte(strconv.Itoa(len(msg))+" "), msg...)
+		}
+
+		// A dropped connection is only noticed on write; reconnect once before giving up.
+		if err := s.send(ctx, msg); err != nil {
+			s.reset()
+			if err := s.send(ctx, msg); err != nil {
+				s.reset()
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (s *syslogEventSink) send(ctx context.Context, msg []byte) error {
+	if s.conn == nil {
+		conn, err := s.dial(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to connect to syslog server %s: %w", s.address, err)
+		}
+		s.conn = conn
+	}
+
+	_ = s.conn.SetWriteDeadline(time.Now().Add(eventSinkTimeout))
+	if _, err := s.conn.Write(msg); err != nil {
+		return fmt.Errorf("failed to write to syslog server %s: %w", s.address, err)
+	}
+	return nil
+}
+
+func (s *syslogEventSink) dial(ctx context.Context) (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: eventSinkTimeout}
+	if s.protocol == "tls" {
+		host, _, _ := net.SplitHostPort(s.address)
+		tlsDialer := &tls.Dialer{NetDialer: dialer, Config: &tls.Config{ServerName: host, MinVersion: tls.VersionTLS12}}
+		return tlsDialer.DialContext(ctx, "tcp", s.address)
+	}
+	return dialer.DialContext(ctx, s.protocol, s.address)
+}
+
+func (s *syslogEventSink) reset() {
+	if s.conn != nil {
+		_ = s.conn.Close()
+		s.conn = nil
+	}
+}
+
+func (s *syslogEventSink) Close() error {
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
+
+// format renders <PRI>1 TIMESTAMP HOSTNAME APP-NAME PROCID MSGID - MSG, with the record
+// as JSON in MSG so that the SIEM receives every field.
+func (s *syslogEventSink) format(record *eventSinkRecord) ([]byte, error) {
+	body, err := json.Marshal(record)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode record: %w", err)
+	}
+
+	facility := syslogFacilityLocal0
+	if record.Kind == eventSinkKindAudit {
+		facility = syslogFacilityLogAudit
+	}
+	pri := facility*8 + syslogSeverity(record.Severity)
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "<%d>1 %s %s %s %d %s - ",
+		pri,
+		record.Timestamp.UTC().Format("2006-01-02T15:04:05.000000Z07:00"),
+		syslogHeaderField(s.hostname, 255),
+		syslogAppName,
+		os.Getpid(),
+		syslogHeaderField(record.Type, syslogMsgIDMaxLen),
+	)
+	buf.Write(body)
+	return buf.Bytes(), nil
+}
+
+func syslogSeverity(severity string) int {
+	switch severity {
+	case string(models.EventSeverityError):
+		return 3
+	case string(models.EventSeverityWarning):
+		return 4
+	case "notice":
+		return 5
+	default:
+		return 6
+	}
+}
+
+// syslogHeaderField keeps a header field to printable ASCII without spaces, as RFC 5424
+// requires, using the nil value "-" when nothing is left.
+func syslogHeaderField(value string, maxLen int) string {
+	var b strings.Builder
+	for _, r := range value {
+		if r > 32 && r < 127 {
+			b.WriteRune(r)
+		}
+		if b.Len() == maxLen {
+			break
+		}
+	}
+	if b.Len() == 0 {
+		return "-"
+	}
+	return b.String()
+}
+
+// lokiEventSink pushes each batch to Loki's push API, one stream per kind and severity.
+type lokiEventSink struct {
+	pushURL  string
+	tenantID string
+	labels   map[string]string
+	client   *http.Client
+}
+
+func newLokiEventSink(ctx context.Context, settings *SettingsService) (eventSink, error) {
+	if !settings.GetBoolSetting(ctx, "eventSinkLokiEnabled", false) {
+		return nil, nil
+	}
+
+	pushURL, err := lokiPushURL(settings.GetStringSetting(ctx, "eventSinkLokiUrl", ""))
+	if err != nil {
+		return nil, err
+	}
+
+	labels, err := parseLokiLabels(settings.GetStringSetting(ctx, "eventSinkLokiLabels", ""))
+	if err != nil {
+		return nil, err
+	}
+
+	return &lokiEventSink{
+		pushURL:  pushURL,
+		tenantID: strings.TrimSpace(settings.GetStringSetting(ctx, "eventSinkLokiTenantId", "")),
+		labels:   labels,
+		client:   &http.Client{Timeout: eventSinkTimeout},
+	}, nil
+}
+
+func lokiPushURL(raw string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", fmt.Errorf("loki URL must be an http(s) URL, got %q", raw)
+	}
+	if !strings.HasSuffix(u.Path, lokiPushPath) {
+		u.Path = strings.TrimSuffix(u.Path, "/") + lokiPushPath
+	}
+	return u.String(), nil
+}
+
+func parseLokiLabels(raw string) (map[string]string, error) {
+	labels := map[string]string{}
+	for _, pair := range strings.Split(raw, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(pair, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("loki labels must be key=value pairs, got %q", pair)
+		}
+		labels[key] = strings.TrimSpace(value)
+	}
+	return labels, nil
+}
+
+func (s *lokiEventSink) Name() string { return "loki" }
+
+type lokiStream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
+
+func (s *lokiEventSink) Write(ctx context.Context, records []eventSinkRecord) error {
+	streams := map[string]*lokiStream{}
+	var order []string
+	for i := range records {
+		record := &records[i]
+		line, err := json.Marshal(record)
+		if err != nil {
+			return fmt.Errorf("failed to encode record: %w", err)
+		}
+
+		key := record.Kind + "/" + record.Severity
+		stream, ok := streams[key]
+		if !ok {
+			labels := map[string]string{"job": syslogAppName, "kind": record.Kind, "severity": record.Severity}
+			for k, v := range s.labels {
+				labels[k] = v
+			}
+			stream = &lokiStream{Stream: labels}
+			streams[key] = stream
+			order = append(order, key)
+		}
+		stream.Values = append(stream.Values, [2]string{strconv.FormatInt(record.Timestamp.UnixNano(), 10), string(line)})
+	}
+
+	payload := struct {
+		Streams []*lokiStream `json:"streams"`
+	}{}
+	for _, key := range order {
+		payload.Streams = append(payload.Streams, streams[key])
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode loki payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.pushURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create loki request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.tenantID != "" {
+		req.Header.Set("X-Scope-OrgID", s.tenantID)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to push to loki: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("loki push returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(snippet)))
+	}
+	return nil
+}
+
+func (s *lokiEventSink) Close() error {
+	s.client.CloseIdleConnections()
+	return nil
+}
+
+// fileEventSink appends one JSON object per line and rotates the file once it would
+// grow past maxBytes, keeping maxBackups older files as path.1 (newest) to path.N.
+type fileEventSink struct {
+	path       string
+	maxBytes   int64
+	maxBackups int
+	file       *os.File
+	size       int64
+}
+
+func newFileEventSink(ctx context.Context, settings *SettingsService) (eventSink, error) {
+	if !settings.GetBoolSetting(ctx, "eventSinkFileEnabled", false) {
+		return nil, nil
+	}
+
+	path := strings.TrimSpace(settings.GetStringSetting(ctx, "eventSinkFilePath", "data/events/events.jsonl"))
+	if path == "" {
+		return nil, fmt.Errorf("JSONL file path is required")
+	}
+	maxSizeMB := settings.GetIntSetting(ctx, "eventSinkFileMaxSize", 100)
+	if maxSizeMB <= 0 {
+		return nil, fmt.Errorf("JSONL file size must be positive, got %d", maxSizeMB)
+	}
+	maxBackups := max(settings.GetIntSetting(ctx, "eventSinkFileMaxBackups", 5), 0)
+
+	return &fileEventSink{path: path, maxBytes: int64(maxSizeMB) << 20, maxBackups: maxBackups}, nil
+}
+
+func (s *fileEventSink) Name() string { return "file" }
+
+func (s *fileEventSink) Write(_ context.Context, records []eventSinkRecord) error {
+	if s.file == nil {
+		if err := s.open(); err != nil {
+			return err
+		}
+	}
+
+	for i := range records {
+		line, err := json.Marshal(&records[i])
+		if err != nil {
+			return fmt.Errorf("failed to encode record: %w", err)
+		}
+		line = append(line, '\n')
+
+		if s.size > 0 && s.size+int64(len(line)) > s.maxBytes {
+			if err := s.rotate(); err != nil {
+				return err
+			}
+		}
+
+		n, err := s.file.Write(line)
+		s.size += int64(n)
+		if err != nil {
+			return fmt.Errorf("failed to write %s: %w", s.path, err)
+		}
+	}
+	return nil
+}
+
+func (s *fileEventSink) open() error {
+	if err := os.MkdirAll(filepath.Dir(s.path), appfs.DirPerm); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", s.path, err)
+	}
+	f, err := os.OpenFile(s.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, appfs.FilePerm)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", s.path, err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to stat %s: %w", s.path, err)
+	}
+	s.file = f
+	s.size = info.Size()
+	return nil
+}
+
+func (s *fileEventSink) rotate() error {
+	if err := s.Close(); err != nil {
+		return fmt.Errorf("failed to close %s: %w", s.path, err)
+	}
+
+	if s.maxBackups == 0 {
+		if err := os.Remove(s.path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove %s: %w", s.path, err)
+		}
+		return s.open()
+	}
+
+	_ = os.Remove(fmt.Sprintf("%s.%d", s.path, s.maxBackups))
+	for i := s.maxBackups - 1; i >= 1; i-- {
+		if err := os.Rename(fmt.Sprintf("%s.%d", s.path, i), fmt.Sprintf("%s.%d", s.path, i+1)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to rotate %s: %w", s.path, err)
+		}
+	}
+	if err := os.Rename(s.path, s.path+".1"); err != nil {
+		return fmt.Errorf("failed to rotate %s: %w", s.path, err)
+	}
+	return s.open()
+}
+
+func (s *fileEventSink) Close() error {
+	if s.file == nil {
+		return nil
+	}
+	err := s.file.Close()
+	s.file = nil
+	return err
+}
@@ -0,0 +1,215 @@
+package services
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ofkm/arcane-backend/internal/models"
+)
+
+func setupEventSinkSettings(t *testing.T, values map[string]string) *SettingsService {
+	t.Helper()
+	ctx := context.Background()
+	settings, err := NewSettingsService(ctx, setupSettingsTestDB(t), nil)
+	require.NoError(t, err)
+	for key, value := range values {
+		require.NoError(t, settings.UpdateSetting(ctx, key, value))
+	}
+	return settings
+}
+
+func testSinkEvent() models.Event {
+	resource := "web"
+	return models.Event{
+		Type:         models.EventTypeContainerStart,
+		Severity:     models.EventSeverityError,
+		Title:        "Container started: web",
+		ResourceName: &resource,
+		Timestamp:    time.Date(2025, 6, 10, 12, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestSyslogEventSink_UDPMessageIsRFC5424(t *testing.T) {
+	ctx := context.Background()
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	settings := setupEventSinkSettings(t, map[string]string{
+		"eventSinkSyslogEnabled":  "true",
+		"eventSinkSyslogProtocol": "udp",
+		"eventSinkSyslogAddress":  conn.LocalAddr().String(),
+	})
+	sink, err := newSyslogEventSink(ctx, settings)
+	require.NoError(t, err)
+	defer sink.Close()
+
+	svc := &EventSinkService{}
+	svc.queue = make(chan eventSinkRecord, 1)
+	svc.active.Store(true)
+	svc.PublishEvent(ctx, testSinkEvent())
+	require.NoError(t, sink.Write(ctx, []eventSinkRecord{<-svc.queue}))
+
+	buf := make([]byte, 4096)
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	require.NoError(t, err)
+
+	// local0 (16) * 8 + err (3) = 131
+	msg := string(buf[:n])
+	require.Regexp(t, regexp.MustCompile(`^<131>1 2025-06-10T12:00:00\.000000Z \S+ arcane \d+ container\.start - \{`), msg)
+
+	var record eventSinkRecord
+	require.NoError(t, json.Unmarshal([]byte(msg[strings.Index(msg, "{"):]), &record))
+	require.Equal(t, "event", record.Kind)
+	require.Equal(t, "web", *record.Event.ResourceName)
+}
+
+func TestSyslogEventSink_TCPUsesOctetCounting(t *testing.T) {
+	ctx := context.Background()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+
+	received := make(chan string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		length, _ := r.ReadString(' ')
+		n, _ := strconv.Atoi(strings.TrimSpace(length))
+		frame := make([]byte, n)
+		_, _ = io.ReadFull(r, frame)
+		received <- string(frame)
+	}()
+
+	settings := setupEventSinkSettings(t, map[string]string{
+		"eventSinkSyslogEnabled":  "true",
+		"eventSinkSyslogProtocol": "tcp",
+		"eventSinkSyslogAddress":  ln.Addr().String(),
+	})
+	sink, err := newSyslogEventSink(ctx, settings)
+	require.NoError(t, err)
+	defer sink.Close()
+
+	username := "alice"
+	require.NoError(t, sink.Write(ctx, []eventSinkRecord{{
+		Kind:      eventSinkKindAudit,
+		Timestamp: time.Now(),
+		Type:      "settings.update",
+		Severity:  "notice",
+		Audit:     &models.AuditLog{Action: "settings.update", Username: &username},
+	}}))
+
+	select {
+	case frame := <-received:
+		// log audit (13) * 8 + notice (5) = 109
+		require.True(t, strings.HasPrefix(frame, "<109>1 "), frame)
+		require.Contains(t, frame, " settings.update - ")
+		require.True(t, strings.HasSuffix(frame, "}"), "frame must hold exactly one message")
+	case <-time.After(5 * time.Second):
+		t.Fatal("no syslog frame received")
+	}
+}
+
+func TestSyslogEventSink_RejectsInvalidSettings(t *testing.T) {
+	ctx := context.Background()
+	settings := setupEventSinkSettings(t, map[string]string{
+		"eventSinkSyslogEnabled":  "true",
+		"eventSinkSyslogProtocol": "relp",
+		"eventSinkSyslogAddress":  "127.0.0.1:514",
+	})
+	_, err := newSyslogEventSink(ctx, settings)
+	require.Error(t, err)
+
+	require.NoError(t, settings.UpdateSetting(ctx, "eventSinkSyslogEnabled", "false"))
+	sink, err := newSyslogEventSink(ctx, settings)
+	require.NoError(t, err)
+	require.Nil(t, sink)
+}
+
+func TestLokiEventSink_PushesStreams(t *testing.T) {
+	ctx := context.Background()
+
+	var gotPath, gotTenant string
+	var payload struct {
+		Streams []lokiStream `json:"streams"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotTenant = r.Header.Get("X-Scope-OrgID")
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	settings := setupEventSinkSettings(t, map[string]string{
+		"eventSinkLokiEnabled":  "true",
+		"eventSinkLokiUrl":      server.URL,
+		"eventSinkLokiLabels":   "env=prod, site=eu",
+		"eventSinkLokiTenantId": "ops",
+	})
+	sink, err := newLokiEventSink(ctx, settings)
+	require.NoError(t, err)
+
+	event := testSinkEvent()
+	require.NoError(t, sink.Write(ctx, []eventSinkRecord{
+		{Kind: eventSinkKindEvent, Timestamp: event.Timestamp, Type: string(event.Type), Severity: "error", Event: &event},
+		{Kind: eventSinkKindEvent, Timestamp: event.Timestamp, Type: string(event.Type), Severity: "error", Event: &event},
+		{Kind: eventSinkKindAudit, Timestamp: event.Timestamp, Type: "user.create", Severity: "notice"},
+	}))
+
+	require.Equal(t, lokiPushPath, gotPath)
+	require.Equal(t, "ops", gotTenant)
+	require.Len(t, payload.Streams, 2)
+	require.Equal(t, map[string]string{"job": "arcane", "kind": "event", "severity": "error", "env": "prod", "site": "eu"}, payload.Streams[0].Stream)
+	require.Len(t, payload.Streams[0].Values, 2)
+	require.Equal(t, strconv.FormatInt(event.Timestamp.UnixNano(), 10), payload.Streams[0].Values[0][0])
+	require.Equal(t, "audit", payload.Streams[1].Stream["kind"])
+
+	server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "ingestion rate limit exceeded", http.StatusTooManyRequests)
+	})
+	err = sink.Write(ctx, []eventSinkRecord{{Kind: eventSinkKindEvent, Timestamp: time.Now(), Severity: "info"}})
+	require.ErrorContains(t, err, "status 429")
+}
+
+func TestFileEventSink_RotatesAndKeepsBackups(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events", "events.jsonl")
+	sink := &fileEventSink{path: path, maxBytes: 300, maxBackups: 2}
+	defer sink.Close()
+
+	record := eventSinkRecord{Kind: eventSinkKindEvent, Timestamp: time.Now(), Type: "container.start", Severity: "info", Message: strings.Repeat("x", 100)}
+	for i := 0; i < 10; i++ {
+		require.NoError(t, sink.Write(context.Background(), []eventSinkRecord{record}))
+	}
+
+	for _, name := range []string{path, path + ".1", path + ".2"} {
+		data, err := os.ReadFile(name)
+		require.NoError(t, err, name)
+		require.LessOrEqual(t, len(data), 300)
+		for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+			var decoded eventSinkRecord
+			require.NoError(t, json.Unmarshal([]byte(line), &decoded))
+		}
+	}
+	_, err := os.Stat(path + ".3")
+	require.True(t, os.IsNotExist(err), "only maxBackups rotated files are kept")
+}
@@ -235,6 +235,49 @@ func (s *ImageService) ImageExistsLocally(ctx context.Context, imageName string)
 	return false, fmt.Errorf("failed to inspect image %s: %w", imageName, err)
 }
 
+// ContainerImageDigest resolves the image a container is running to a reference that
+// always means the same bytes: the registry digest matching the container's configured
+// repository (repo@sha256:...), or the local image ID when the image has no digest.
+func (s *ImageService) ContainerImageDigest(ctx context.Context, containerID string) (models.DeploymentImage, error) {
+	dockerClient, err := s.dockerService.CreateConnection(ctx)
+	if err != nil {
+		return models.DeploymentImage{}, fmt.Errorf("failed to connect to Docker: %w", err)
+	}
+	defer dockerClient.Close()
+
+	ctr, err := dockerClient.ContainerInspect(ctx, containerID)
+	if err != nil {
+		return models.DeploymentImage{}, fmt.Errorf("failed to inspect container %s: %w", containerID, err)
+	}
+	out := models.DeploymentImage{ImageID: ctr.Image, Pinned: ctr.Image}
+	if ctr.Config != nil {
+		out.Image = ctr.Config.Image
+	}
+
+	inspect, err := dockerClient.ImageInspect(ctx, ctr.Image)
+	if err != nil {
+		return models.DeploymentImage{}, fmt.Errorf("failed to inspect image %s: %w", ctr.Image, err)
+	}
+	out.Pinned = pickRepoDigest(out.Image, inspect.RepoDigests, out.Pinned)
+	return out, nil
+}
+
+// pickRepoDigest returns the digest from repoDigests for the same repository as
+// imageRef, the first digest if none match, or fallback when there are none.
+func pickRepoDigest(imageRef string, repoDigests []string, fallback string) string {
+	if len(repoDigests) == 0 {
+		return fallback
+	}
+	if named, err := ref.ParseNormalizedNamed(imageRef); err == nil {
+		for _, digest := range repoDigests {
+			if d, err := ref.ParseNormalizedNamed(digest); err == nil && d.Name() == named.Name() {
+				return digest
+			}
+		}
+	}
+	return repoDigests[0]
+}
+
 func (s *ImageService) getPullOptionsWithAuth(ctx context.Context, imageRef string, externalCreds []dto.ContainerRegistryCredential) (image.PullOptions, error) {
 	pullOptions := image.PullOptions{}
 
@@ -632,19 +675,20 @@ func stringPtrValue(p *string) string {
 
 func buildUpdateInfo(updateRecord *models.ImageUpdateRecord) *dto.ImageUpdateInfoDto {
 	return &dto.ImageUpdateInfoDto{
-		HasUpdate:      updateRecord.HasUpdate,
-		UpdateType:     updateRecord.UpdateType,
-		CurrentVersion: updateRecord.CurrentVersion,
-		LatestVersion:  stringPtrValue(updateRecord.LatestVersion),
-		CurrentDigest:  stringPtrValue(updateRecord.CurrentDigest),
-		LatestDigest:   stringPtrValue(updateRecord.LatestDigest),
-		CheckTime:      updateRecord.CheckTime,
-		ResponseTimeMs: updateRecord.ResponseTimeMs,
-		Error:          stringPtrValue(updateRecord.LastError),
-		AuthMethod:     stringPtrValue(updateRecord.AuthMethod),
-		AuthUsername:   stringPtrValue(updateRecord.AuthUsername),
-		AuthRegistry:   stringPtrValue(updateRecord.AuthRegistry),
-		UsedCredential: updateRecord.UsedCredential,
+		HasUpdate:         updateRecord.HasUpdate,
+		UpdateType:        updateRecord.UpdateType,
+		CurrentVersion:    updateRecord.CurrentVersion,
+		LatestVersion:     stringPtrValue(updateRecord.LatestVersion),
+		CandidateVersions: updateRecord.CandidateVersions,
+		CurrentDigest:     stringPtrValue(updateRecord.CurrentDigest),
+		LatestDigest:      stringPtrValue(updateRecord.LatestDigest),
+		CheckTime:         updateRecord.CheckTime,
+		ResponseTimeMs:    updateRecord.ResponseTimeMs,
+		Error:             stringPtrValue(updateRecord.LastError),
+		AuthMethod:        stringPtrValue(updateRecord.AuthMethod),
+		AuthUsername:      stringPtrValue(updateRecord.AuthUsername),
+		AuthRegistry:      stringPtrValue(updateRecord.AuthRegistry),
+		UsedCredential:    updateRecord.UsedCredential,
 	}
 }
 
@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -16,6 +17,8 @@ import (
 	"github.com/ofkm/arcane-backend/internal/models"
 	"github.com/ofkm/arcane-backend/internal/utils"
 	registry "github.com/ofkm/arcane-backend/internal/utils/registry"
+	"github.com/ofkm/arcane-backend/internal/utils/releasenotes"
+	"github.com/ofkm/arcane-backend/internal/utils/updatepolicy"
 )
 
 type ImageUpdateService struct {
@@ -25,6 +28,7 @@ type ImageUpdateService struct {
 	dockerService       *DockerClientService
 	eventService        *EventService
 	notificationService *NotificationService
+	releaseNotes        *releasenotes.Client
 }
 
 type ImageParts struct {
@@ -41,6 +45,7 @@ func NewImageUpdateService(db *database.DB, settingsService *SettingsService, re
 		dockerService:       dockerService,
 		eventService:        eventService,
 		notificationService: notificationService,
+		releaseNotes:        releasenotes.NewClient(nil),
 	}
 }
 
@@ -89,9 +94,10 @@ func (s *ImageUpdateService) CheckImageUpdate(ctx context.Context, imageRef stri
 		"action":         "check_update",
 		"imageRef":       imageRef,
 		"hasUpdate":      digestResult.HasUpdate,
-		"updateType":     "digest",
+		"updateType":     digestResult.UpdateType,
 		"currentDigest":  digestResult.CurrentDigest,
 		"latestDigest":   digestResult.LatestDigest,
+		"latestVersion":  digestResult.LatestVersion,
 		"responseTimeMs": digestResult.ResponseTimeMs,
 	}
 	if logErr := s.eventService.LogImageEvent(ctx, models.EventTypeImageScan, "", imageRef, systemUser.ID, systemUser.Username, "0", metadata); logErr != nil {
@@ -219,6 +225,7 @@ func (s *ImageUpdateService) checkDigestUpdate(ctx context.Context, parts *Image
 			break
 		}
 	}
+	candidates := s.findNewerTags(ctx, rc, parts, token)
 
 	slog.DebugContext(ctx, "digest comparison",
 		slog.String("imageRef", fmt.Sprintf("%s/%s:%s", parts.Registry, parts.Repository, parts.Tag)),
@@ -227,7 +234,7 @@ func (s *ImageUpdateService) checkDigestUpdate(ctx context.Context, parts *Image
 		slog.String("remoteDigest", remoteDigest),
 		slog.Bool("hasUpdate", hasUpdate))
 
-	return &dto.ImageUpdateResponse{
+	result := &dto.ImageUpdateResponse{
 		HasUpdate:      hasUpdate,
 		UpdateType:     "digest",
 		CurrentDigest:  localDigest,
@@ -238,7 +245,125 @@ func (s *ImageUpdateService) checkDigestUpdate(ctx context.Context, parts *Image
 		AuthUsername:   auth.Username,
 		AuthRegistry:   auth.Registry,
 		UsedCredential: auth.Method == "credential",
-	}, nil
+	}
+	applyTagCandidates(result, candidates)
+	s.attachReleaseInfo(ctx, rc, parts, token, result)
+	return result, nil
+}
+
+// maxCandidateVersions caps how many newer tags are kept per image.
+const maxCandidateVersions = 10
+
+// findNewerTags lists the repository's tags and returns the semver tags newer than the
+// current one, newest first. Failures only cost the tag check, so they are logged and
+// reported as "no newer tags".
+func (s *ImageUpdateService) findNewerTags(ctx context.Context, rc *registry.Client, parts *ImageParts, token string) []string {
+	if !updatepolicy.IsVersionTag(parts.Tag) {
+		return nil
+	}
+	tags, err := rc.GetImageTags(ctx, parts.Registry, s.normalizeRepository(parts.Registry, parts.Repository), token)
+	if err != nil {
+		slog.DebugContext(ctx, "Failed to list image tags",
+			slog.String("registry", parts.Registry),
+			slog.String("repository", parts.Repository),
+			slog.String("error", err.Error()))
+		return nil
+	}
+	newer := updatepolicy.NewerTags(parts.Tag, tags)
+	if len(newer) > maxCandidateVersions {
+		newer = newer[:maxCandidateVersions]
+	}
+	return newer
+}
+
+// attachReleaseInfo reads the OCI labels of the image an update moves to and, when they
+// name a supported source repository, fetches the release notes for its version. Errors are
+// logged only; they never fail the update check.
+func (s *ImageUpdateService) attachReleaseInfo(ctx context.Context, rc *registry.Client, parts *ImageParts, token string, result *dto.ImageUpdateResponse) {
+	if !result.HasUpdate || s.releaseNotes == nil {
+		return
+	}
+
+	reference := parts.Tag
+	switch {
+	case result.UpdateType == models.UpdateTypeTag && result.LatestVersion != "":
+		reference = result.LatestVersion
+	case result.LatestDigest != "":
+		reference = result.LatestDigest
+	}
+
+	meta, err := rc.GetImageMetadata(ctx, parts.Registry, s.normalizeRepository(parts.Registry, parts.Repository), reference, token)
+	if err != nil {
+		slog.DebugContext(ctx, "Failed to read image metadata",
+			slog.String("repository", parts.Repository),
+			slog.String("reference", reference),
+			slog.String("error", err.Error()))
+	}
+	if meta == nil || (meta.Source == "" && meta.Version == "" && meta.Revision == "") {
+		return
+	}
+
+	info := &dto.ImageReleaseInfo{
+		Source:   meta.Source,
+		Version:  meta.Version,
+		Revision: meta.Revision,
+	}
+	version := meta.Version
+	if version == "" && result.UpdateType == models.UpdateTypeTag {
+		version = result.LatestVersion
+	}
+	if meta.Source != "" && version != "" {
+		rel, err := s.releaseNotes.Fetch(ctx, meta.Source, version)
+		switch {
+		case err == nil:
+			info.Title = rel.Name
+			if info.Title == "" {
+				info.Title = rel.TagName
+			}
+			info.Notes = rel.Body
+			info.URL = rel.URL
+			info.PublishedAt = rel.PublishedAt
+		case !errors.Is(err, releasenotes.ErrNotFound) && !errors.Is(err, releasenotes.ErrUnsupportedSource):
+			slog.DebugContext(ctx, "Failed to fetch release notes",
+				slog.String("source", meta.Source),
+				slog.String("version", version),
+				slog.String("error", err.Error()))
+		}
+	}
+	result.Release = info
+}
+
+// applyTagCandidates turns result into a tag update when newer versions exist. The digest
+// fields are kept so a changed digest for the current tag is still visible.
+func applyTagCandidates(result *dto.ImageUpdateResponse, candidates []string) {
+	if len(candidates) == 0 {
+		return
+	}
+	result.HasUpdate = true
+	result.UpdateType = models.UpdateTypeTag
+	result.LatestVersion = candidates[0]
+	result.CandidateVersions = candidates
+}
+
+// ListImageTags returns every tag the registry publishes for the repository of imageRef.
+func (s *ImageUpdateService) ListImageTags(ctx context.Context, imageRef string) ([]string, error) {
+	parts := s.parseImageReference(imageRef)
+	if parts == nil {
+		return nil, fmt.Errorf("failed to parse image reference: %s", imageRef)
+	}
+
+	registries := s.getRegistriesForImage(ctx, parts.Registry)
+	token, _, err := s.getRegistryToken(ctx, parts.Registry, parts.Repository, registries)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get registry token: %w", err)
+	}
+
+	rc := registry.NewClient()
+	tags, err := rc.GetImageTags(ctx, parts.Registry, s.normalizeRepository(parts.Registry, parts.Repository), token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+	return tags, nil
 }
 
 func (s *ImageUpdateService) parseImageReference(imageRef string) *ImageParts {
@@ -583,22 +708,23 @@ func buildImageUpdateRecord(imageID, repo, tag string, result *dto.ImageUpdateRe
 	}
 
 	return &models.ImageUpdateRecord{
-		ID:             imageID,
-		Repository:     repo,
-		Tag:            tag,
-		HasUpdate:      result.HasUpdate,
-		UpdateType:     result.UpdateType,
-		CurrentVersion: currentVersion,
-		LatestVersion:  stringToPtr(result.LatestVersion),
-		CurrentDigest:  stringToPtr(result.CurrentDigest),
-		LatestDigest:   stringToPtr(result.LatestDigest),
-		CheckTime:      result.CheckTime,
-		ResponseTimeMs: result.ResponseTimeMs,
-		LastError:      stringToPtr(result.Error),
-		AuthMethod:     stringToPtr(result.AuthMethod),
-		AuthUsername:   stringToPtr(result.AuthUsername),
-		AuthRegistry:   stringToPtr(result.AuthRegistry),
-		UsedCredential: result.UsedCredential,
+		ID:                imageID,
+		Repository:        repo,
+		Tag:               tag,
+		HasUpdate:         result.HasUpdate,
+		UpdateType:        result.UpdateType,
+		CurrentVersion:    currentVersion,
+		LatestVersion:     stringToPtr(result.LatestVersion),
+		CandidateVersions: result.CandidateVersions,
+		CurrentDigest:     stringToPtr(result.CurrentDigest),
+		LatestDigest:      stringToPtr(result.LatestDigest),
+		CheckTime:         result.CheckTime,
+		ResponseTimeMs:    result.ResponseTimeMs,
+		LastError:         stringToPtr(result.Error),
+		AuthMethod:        stringToPtr(result.AuthMethod),
+		AuthUsername:      stringToPtr(result.AuthUsername),
+		AuthRegistry:      stringToPtr(result.AuthRegistry),
+		UsedCredential:    result.UsedCredential,
 	}
 }
 
@@ -892,8 +1018,9 @@ func (s *ImageUpdateService) checkSingleImageInBatch(
 			break
 		}
 	}
+	candidates := s.findNewerTags(ctx, rc, parts, token)
 
-	return &dto.ImageUpdateResponse{
+	result := &dto.ImageUpdateResponse{
 		HasUpdate:      hasDigestUpdate,
 		UpdateType:     "digest",
 		CurrentDigest:  localDigest,
@@ -905,6 +1032,9 @@ func (s *ImageUpdateService) checkSingleImageInBatch(
 		AuthRegistry:   auth.Registry,
 		UsedCredential: auth.Method == "credential",
 	}
+	applyTagCandidates(result, candidates)
+	s.attachReleaseInfo(ctx, rc, parts, token, result)
+	return result
 }
 
 func (s *ImageUpdateService) CheckMultipleImages(ctx context.Context, imageRefs []string, externalCreds []dto.ContainerRegistryCredential) (map[string]*dto.ImageUpdateResponse, error) {
@@ -1046,6 +1176,7 @@ func (s *ImageUpdateService) GetUpdateSummary(ctx context.Context) (*dto.ImageUp
 		TotalImages:       int(totalImages),
 		ImagesWithUpdates: int(imagesWithUpdates),
 		DigestUpdates:     int(digestUpdates),
+		TagUpdates:        int(tagUpdates),
 		ErrorsCount:       int(errorsCount),
 	}, nil
 }
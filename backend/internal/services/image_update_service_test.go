@@ -6,6 +6,9 @@ import (
 	ref "github.com/distribution/reference"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/ofkm/arcane-backend/internal/dto"
+	"github.com/ofkm/arcane-backend/internal/models"
 )
 
 // TestParseImageReference tests the parseImageReference function with various image formats
@@ -269,3 +272,21 @@ func TestImageUpdateService_DockerReferenceCompatibility(t *testing.T) {
 		})
 	}
 }
+
+func TestImageUpdateService_ApplyTagCandidates(t *testing.T) {
+	result := &dto.ImageUpdateResponse{UpdateType: "digest", CurrentDigest: "sha256:a", LatestDigest: "sha256:a"}
+	applyTagCandidates(result, nil)
+	assert.False(t, result.HasUpdate)
+	assert.Equal(t, "digest", result.UpdateType)
+
+	applyTagCandidates(result, []string{"1.5.0", "1.4.3"})
+	assert.True(t, result.HasUpdate)
+	assert.Equal(t, models.UpdateTypeTag, result.UpdateType)
+	assert.Equal(t, "1.5.0", result.LatestVersion)
+
+	record := buildImageUpdateRecord("sha256:img", "nginx", "1.4.2", result)
+	assert.True(t, record.IsTagUpdate())
+	assert.False(t, record.HasDigestUpdate())
+	assert.Equal(t, models.StringSlice{"1.5.0", "1.4.3"}, record.CandidateVersions)
+	assert.Equal(t, "1.4.2", record.CurrentVersion)
+}
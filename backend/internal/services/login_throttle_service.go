@@ -0,0 +1,330 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/ofkm/arcane-backend/internal/database"
+	"github.com/ofkm/arcane-backend/internal/models"
+)
+
+const (
+	// Failed attempts allowed before each further attempt has to wait.
+	loginThrottleFreeAttempts = 2
+	// Client IPs get this many times the username limits, since several people may
+	// share one address.
+	loginThrottleIPFactor = 4
+	// Used when authLockoutDuration is not a positive number of minutes.
+	defaultLockoutWindow = 15 * time.Minute
+)
+
+var (
+	ErrLoginThrottled  = errors.New("too many failed sign-in attempts")
+	ErrLockoutNotFound = errors.New("lockout not found")
+)
+
+// LoginThrottledError is returned while a username or client IP has to wait before the
+// next sign-in attempt. It unwraps to ErrLoginThrottled.
+type LoginThrottledError struct {
+	Until time.Time
+}
+
+func (e *LoginThrottledError) Error() string {
+	return fmt.Sprintf("%s, try again after %s", ErrLoginThrottled, e.Until.Format(time.RFC3339))
+}
+
+func (e *LoginThrottledError) Unwrap() error {
+	return ErrLoginThrottled
+}
+
+// LoginThrottleService slows down password guessing. Each failed sign-in makes the next
+// attempt for the same username and client IP wait exponentially longer, and after
+// authLockoutThreshold failures the username is locked for authLockoutDuration minutes.
+// Failures older than the lockout duration are forgotten.
+type LoginThrottleService struct {
+	db              *database.DB
+	settingsService *SettingsService
+	eventService    *EventService
+}
+
+func NewLoginThrottleService(db *database.DB, settingsService *SettingsService, eventService *EventService) *LoginThrottleService {
+	return &LoginThrottleService{db: db, settingsService: settingsService, eventService: eventService}
+}
+
+type loginThrottleLimits struct {
+	threshold int
+	duration  time.Duration
+}
+
+func (s *LoginThrottleService) limits(ctx context.Context) loginThrottleLimits {
+	limits := loginThrottleLimits{
+		threshold: s.settingsService.GetIntSetting(ctx, "authLockoutThreshold", 5),
+		duration:  time.Duration(s.settingsService.GetIntSetting(ctx, "authLockoutDuration", 15)) * time.Minute,
+	}
+	if limits.threshold < 0 {
+		limits.threshold = 0
+	}
+	if limits.duration <= 0 {
+		limits.duration = defaultLockoutWindow
+	}
+	return limits
+}
+
+// throttleKey is one username or client IP that sign-in attempts are counted against.
+type throttleKey struct {
+	scope      models.LoginThrottleScope
+	identifier string
+	threshold  int
+	factor     int
+}
+
+func throttleKeys(username, ipAddress string, limits loginThrottleLimits) []throttleKey {
+	var keys []throttleKey
+	if username = normalizeLoginUsername(username); username != "" {
+		keys = append(keys, throttleKey{models.LoginThrottleScopeUsername, username, limits.threshold, 1})
+	}
+	if ipAddress != "" {
+		keys = append(keys, throttleKey{models.LoginThrottleScopeIP, ipAddress, limits.threshold * loginThrottleIPFactor, loginThrottleIPFactor})
+	}
+	return keys
+}
+
+// LoginAttempt is a sign-in attempt that has been counted as a failure before its
+// credentials were checked. It is settled with Fail or Release.
+type LoginAttempt struct {
+	service   *LoginThrottleService
+	username  string
+	ipAddress string
+	// failures and locked describe the username after this attempt was counted.
+	failures int
+	locked   bool
+}
+
+// Reserve counts a sign-in attempt against the username and client IP before the
+// credentials are checked, so parallel guesses cannot all get past the throttle before
+// the first of them fails. It fails with a *LoginThrottledError, without counting the
+// attempt, if the username or client IP must wait before trying again.
+func (s *LoginThrottleService) Reserve(ctx context.Context, username, ipAddress string) (*LoginAttempt, error) {
+	return s.count(ctx, username, ipAddress, true)
+}
+
+// RecordFailure counts a failed sign-in against the username and client IP and logs it.
+// userID is empty when the username does not exist.
+func (s *LoginThrottleService) RecordFailure(ctx context.Context, userID, username, ipAddress, reason string) {
+	attempt, err := s.count(ctx, username, ipAddress, false)
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to record failed login", "username", username, "ip", ipAddress, "error", err)
+		attempt = &LoginAttempt{service: s, username: username, ipAddress: ipAddress}
+	}
+	attempt.Fail(ctx, userID, username, reason)
+}
+
+// count adds a failure for every key of the attempt in one transaction. With refuseLocked
+// it first refuses the attempt if any key is locked.
+func (s *LoginThrottleService) count(ctx context.Context, username, ipAddress string, refuseLocked bool) (*LoginAttempt, error) {
+	limits := s.limits(ctx)
+	keys := throttleKeys(username, ipAddress, limits)
+	attempt := &LoginAttempt{service: s, username: username, ipAddress: ipAddress}
+	now := time.Now()
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		throttles := make([]*models.LoginThrottle, len(keys))
+		var until time.Time
+		for i, key := range keys {
+			throttle, err := lockThrottle(tx, key, now)
+			if err != nil {
+				return err
+			}
+			throttles[i] = throttle
+			if throttle.Locked(now) && throttle.LockedUntil.After(until) {
+				until = *throttle.LockedUntil
+			}
+		}
+		if refuseLocked && !until.IsZero() {
+			return &LoginThrottledError{Until: until}
+		}
+
+		for i, key := range keys {
+			throttle := throttles[i]
+			if now.Sub(throttle.LastFailureAt) > limits.duration {
+				throttle.Failures = 0
+			}
+			throttle.Failures++
+			throttle.LastFailureAt = now
+			throttle.LockedUntil = nil
+			if until := throttleUntil(now, throttle.Failures, key.threshold, key.factor, limits.duration); !until.IsZero() {
+				throttle.LockedUntil = &until
+			}
+			if err := tx.Save(throttle).Error; err != nil {
+				return err
+			}
+			if key.scope == models.LoginThrottleScopeUsername {
+				attempt.failures = throttle.Failures
+				attempt.locked = key.threshold > 0 && throttle.Failures >= key.threshold
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		var throttled *LoginThrottledError
+		if errors.As(err, &throttled) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to count login attempt: %w", err)
+	}
+	return attempt, nil
+}
+
+// lockThrottle loads the throttle of key, creating it if needed. The upsert takes the
+// row's write lock, so concurrent attempts on the same key are counted one after another
+// and two first failures cannot both insert it.
+func lockThrottle(tx *gorm.DB, key throttleKey, now time.Time) (*models.LoginThrottle, error) {
+	row := models.LoginThrottle{Scope: key.scope, Identifier: key.identifier, LastFailureAt: now}
+	err := tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "scope"}, {Name: "identifier"}},
+		DoUpdates: clause.Assignments(map[string]any{"updated_at": now}),
+	}).Create(&row).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var throttle models.LoginThrottle
+	if err := tx.Where("scope = ? AND identifier = ?", key.scope, key.identifier).First(&throttle).Error; err != nil {
+		return nil, err
+	}
+	return &throttle, nil
+}
+
+// Fail logs the attempt as a failed sign-in; it was already counted by Reserve. userID is
+// empty when the username does not exist.
+func (a *LoginAttempt) Fail(ctx context.Context, userID, username, reason string) {
+	if a == nil || a.service.eventService == nil {
+		return
+	}
+	metadata := models.JSON{"reason": reason, "ipAddress": a.ipAddress}
+	if normalizeLoginUsername(a.username) != "" {
+		metadata["failures"] = a.failures
+		metadata["locked"] = a.locked
+	}
+	if err := a.service.eventService.LogUserEvent(ctx, models.EventTypeUserLoginFailed, userID, username, metadata); err != nil {
+		slog.WarnContext(ctx, "Could not log failed login", "username", username, "error", err)
+	}
+}
+
+// Release takes back an attempt whose credentials were valid, or that could not be
+// checked, so it does not count as a failure.
+func (a *LoginAttempt) Release(ctx context.Context) {
+	if a == nil {
+		return
+	}
+	s := a.service
+	limits := s.limits(ctx)
+	now := time.Now()
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, key := range throttleKeys(a.username, a.ipAddress, limits) {
+			res := tx.Model(&models.LoginThrottle{}).
+				Where("scope = ? AND identifier = ? AND failures > 0", key.scope, key.identifier).
+				Update("failures", gorm.Expr("failures - 1"))
+			if res.Error != nil {
+				return res.Error
+			}
+			if res.RowsAffected == 0 {
+				continue
+			}
+
+			var throttle models.LoginThrottle
+			if err := tx.Where("scope = ? AND identifier = ?", key.scope, key.identifier).First(&throttle).Error; err != nil {
+				return err
+			}
+			var lockedUntil *time.Time
+			if until := throttleUntil(throttle.LastFailureAt, throttle.Failures, key.threshold, key.factor, limits.duration); until.After(now) {
+				lockedUntil = &until
+			}
+			if err := tx.Model(&throttle).Update("locked_until", lockedUntil).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to release login attempt", "username", a.username, "error", err)
+	}
+}
+
+// RecordSuccess forgets the username's failures. The client IP's failures are left to
+// expire, so one valid account cannot be used to reset guessing at others.
+func (s *LoginThrottleService) RecordSuccess(ctx context.Context, username string) {
+	err := s.db.WithContext(ctx).
+		Where("scope = ? AND identifier = ?", models.LoginThrottleScopeUsername, normalizeLoginUsername(username)).
+		Delete(&models.LoginThrottle{}).Error
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to reset login throttle", "username", username, "error", err)
+	}
+}
+
+// throttleUntil returns when the next attempt is allowed after failures, or the zero time
+// if it is allowed right away. After the free attempts the wait doubles with every
+// failure, up to window; reaching threshold locks for the whole window.
+func throttleUntil(now time.Time, failures, threshold, factor int, window time.Duration) time.Time {
+	if threshold > 0 && failures >= threshold {
+		return now.Add(window)
+	}
+	over := failures - loginThrottleFreeAttempts*factor
+	if over <= 0 {
+		return time.Time{}
+	}
+	wait := window
+	if over < 20 {
+		wait = min(time.Duration(1<<(over-1))*time.Second, window)
+	}
+	return now.Add(wait)
+}
+
+// ListLockouts returns the usernames and client IPs that currently cannot sign in.
+func (s *LoginThrottleService) ListLockouts(ctx context.Context) ([]models.LoginThrottle, error) {
+	var throttles []models.LoginThrottle
+	err := s.db.WithContext(ctx).
+		Where("locked_until > ?", time.Now()).
+		Order("locked_until DESC").
+		Find(&throttles).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list lockouts: %w", err)
+	}
+	return throttles, nil
+}
+
+// ClearLockout lifts a lockout and forgets its failures.
+func (s *LoginThrottleService) ClearLockout(ctx context.Context, id string) error {
+	res := s.db.WithContext(ctx).Where("id = ?", id).Delete(&models.LoginThrottle{})
+	if res.Error != nil {
+		return fmt.Errorf("failed to clear lockout: %w", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return ErrLockoutNotFound
+	}
+	return nil
+}
+
+// DeleteExpired removes throttles whose failures have been forgotten.
+func (s *LoginThrottleService) DeleteExpired(ctx context.Context) (int64, error) {
+	cutoff := time.Now().Add(-s.limits(ctx).duration)
+	res := s.db.WithContext(ctx).
+		Where("last_failure_at < ? AND (locked_until IS NULL OR locked_until < ?)", cutoff, time.Now()).
+		Delete(&models.LoginThrottle{})
+	if res.Error != nil {
+		return 0, fmt.Errorf("failed to delete expired login throttles: %w", res.Error)
+	}
+	return res.RowsAffected, nil
+}
+
+func normalizeLoginUsername(username string) string {
+	return strings.ToLower(strings.TrimSpace(username))
+}
@@ -0,0 +1,146 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ofkm/arcane-backend/internal/dto"
+	"github.com/ofkm/arcane-backend/internal/models"
+)
+
+func TestThrottleUntil(t *testing.T) {
+	now := time.Now()
+	window := 15 * time.Minute
+
+	require.True(t, throttleUntil(now, 2, 5, 1, window).IsZero())
+	require.Equal(t, now.Add(time.Second), throttleUntil(now, 3, 5, 1, window))
+	require.Equal(t, now.Add(2*time.Second), throttleUntil(now, 4, 5, 1, window))
+	require.Equal(t, now.Add(window), throttleUntil(now, 5, 5, 1, window))
+	require.Equal(t, now.Add(window), throttleUntil(now, 40, 0, 1, window), "backoff is capped even without lockout")
+	require.True(t, throttleUntil(now, 8, 20, 4, window).IsZero())
+}
+
+func TestLoginThrottle_LockoutAndClear(t *testing.T) {
+	ctx := WithSessionClient(context.Background(), SessionClient{IPAddress: "10.0.0.9"})
+	svc, user := setupTotpAuthService(t)
+	db := svc.userService.db
+	require.NoError(t, db.AutoMigrate(&models.LoginThrottle{}))
+	svc.throttle = NewLoginThrottleService(db, svc.settingsService, svc.eventService)
+	require.NoError(t, svc.settingsService.UpdateSetting(ctx, "authLockoutThreshold", "3"))
+
+	// Usernames are counted case-insensitively.
+	for range 2 {
+		_, _, _, err := svc.Login(ctx, "Alice", "wrong-password")
+		require.ErrorIs(t, err, ErrInvalidCredentials)
+	}
+	_, _, _, err := svc.Login(ctx, "alice", "secret-password")
+	require.NoError(t, err, "a success before the threshold resets the count")
+
+	for range 3 {
+		_, _, _, err = svc.Login(ctx, "alice", "wrong-password")
+		require.ErrorIs(t, err, ErrInvalidCredentials)
+	}
+	_, _, _, err = svc.Login(ctx, "alice", "secret-password")
+	require.ErrorIs(t, err, ErrLoginThrottled, "the right password is refused while locked")
+
+	// Unknown usernames are throttled the same way.
+	_, _, _, err = svc.Login(ctx, "mallory", "whatever")
+	require.ErrorIs(t, err, ErrInvalidCredentials)
+
+	lockouts, err := svc.throttle.ListLockouts(ctx)
+	require.NoError(t, err)
+	require.Len(t, lockouts, 1)
+	require.Equal(t, models.LoginThrottleScopeUsername, lockouts[0].Scope)
+	require.Equal(t, "alice", lockouts[0].Identifier)
+	out, err := dto.MapSlice[models.LoginThrottle, dto.LockoutDto](lockouts)
+	require.NoError(t, err)
+	require.Equal(t, "username", out[0].Scope)
+	require.Equal(t, *lockouts[0].LockedUntil, out[0].LockedUntil)
+
+	require.NoError(t, svc.throttle.ClearLockout(ctx, lockouts[0].ID))
+	require.ErrorIs(t, svc.throttle.ClearLockout(ctx, lockouts[0].ID), ErrLockoutNotFound)
+	loggedIn, _, _, err := svc.Login(ctx, "alice", "secret-password")
+	require.NoError(t, err)
+	require.Equal(t, user.ID, loggedIn.ID)
+
+	var events []models.Event
+	require.NoError(t, db.Where("type = ?", models.EventTypeUserLoginFailed).Find(&events).Error)
+	require.Len(t, events, 6)
+	reasons := map[any]int{}
+	locked := 0
+	for _, e := range events {
+		require.Equal(t, models.EventSeverityWarning, e.Severity)
+		reasons[e.Metadata["reason"]]++
+		if e.Metadata["locked"] == true {
+			locked++
+		}
+	}
+	require.Equal(t, map[any]int{"invalid_password": 3, "unknown_user": 3}, reasons)
+	require.Equal(t, 1, locked)
+}
+
+func TestLoginThrottle_ConcurrentAttemptsAreCountedUpFront(t *testing.T) {
+	ctx := WithSessionClient(context.Background(), SessionClient{IPAddress: "10.0.0.9"})
+	svc, _ := setupTotpAuthService(t)
+	db := svc.userService.db
+	require.NoError(t, db.AutoMigrate(&models.LoginThrottle{}))
+	// Every connection to ":memory:" is a new database, so share one.
+	sqlDB, err := db.DB.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+	svc.throttle = NewLoginThrottleService(db, svc.settingsService, svc.eventService)
+	require.NoError(t, svc.settingsService.UpdateSetting(ctx, "authLockoutThreshold", "3"))
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _, _, err := svc.Login(ctx, "alice", "wrong-password")
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	checked := 0
+	for err := range errs {
+		if errors.Is(err, ErrInvalidCredentials) {
+			checked++
+			continue
+		}
+		require.ErrorIs(t, err, ErrLoginThrottled)
+	}
+	require.LessOrEqual(t, checked, 3, "more passwords were checked than the threshold allows")
+
+	var throttle models.LoginThrottle
+	require.NoError(t, db.Where("scope = ? AND identifier = ?", models.LoginThrottleScopeUsername, "alice").First(&throttle).Error)
+	require.Equal(t, checked, throttle.Failures)
+}
+
+func TestLoginThrottle_ReleasedAttemptIsNotCounted(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := setupTotpAuthService(t)
+	db := svc.userService.db
+	require.NoError(t, db.AutoMigrate(&models.LoginThrottle{}))
+	throttle := NewLoginThrottleService(db, svc.settingsService, nil)
+
+	throttle.RecordFailure(ctx, "", "bob", "10.0.0.7", "unknown_user")
+	attempt, err := throttle.Reserve(ctx, "bob", "10.0.0.7")
+	require.NoError(t, err)
+	attempt.Release(ctx)
+
+	var rows []models.LoginThrottle
+	require.NoError(t, db.Order("scope ASC").Find(&rows).Error)
+	require.Len(t, rows, 2, "one row per username and client IP")
+	for _, row := range rows {
+		require.Equal(t, 1, row.Failures, row.Scope)
+		require.Nil(t, row.LockedUntil)
+	}
+}
@@ -0,0 +1,494 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/client"
+	"github.com/shirou/gopsutil/v4/cpu"
+	"github.com/shirou/gopsutil/v4/disk"
+	"github.com/shirou/gopsutil/v4/mem"
+	"github.com/shirou/gopsutil/v4/net"
+	"gorm.io/gorm"
+
+	"github.com/ofkm/arcane-backend/internal/database"
+	"github.com/ofkm/arcane-backend/internal/dto"
+	"github.com/ofkm/arcane-backend/internal/models"
+	"github.com/ofkm/arcane-backend/internal/utils"
+)
+
+var ErrInvalidMetricsQuery = errors.New("invalid metrics query")
+
+const (
+	DefaultMetricsInterval      = 30 * time.Second
+	MinMetricsInterval          = 5 * time.Second
+	defaultMetricsRetentionDays = 7
+
+	defaultMetricsRange  = time.Hour
+	defaultMetricPoints  = 300
+	maxMetricPoints      = 2000
+	metricsInsertBatch   = 100
+	containerStatsWait   = 10 * time.Second
+	containerStatsWorker = 4
+)
+
+// metricTiers describes how samples are folded as they age: rows stored at resolution
+// `from` that are older than `after` are averaged into buckets of width `to`.
+var metricTiers = []struct {
+	from  time.Duration
+	to    time.Duration
+	after time.Duration
+}{
+	{from: 0, to: 5 * time.Minute, after: 24 * time.Hour},
+	{from: 5 * time.Minute, to: time.Hour, after: 7 * 24 * time.Hour},
+}
+
+type MetricsService struct {
+	db              *database.DB
+	dockerService   *DockerClientService
+	settingsService *SettingsService
+
+	mu sync.Mutex
+	// host CPU times from the previous collection, used to compute utilisation
+	lastCPUTimes *cpu.TimesStat
+}
+
+type MetricsQuery struct {
+	// Container is a container name or ID; empty selects the host series.
+	Container string
+	From      time.Time
+	To        time.Time
+	Step      time.Duration
+}
+
+func NewMetricsService(db *database.DB, dockerService *DockerClientService, settingsService *SettingsService) *MetricsService {
+	return &MetricsService{
+		db:              db,
+		dockerService:   dockerService,
+		settingsService: settingsService,
+	}
+}
+
+func (s *MetricsService) Enabled(ctx context.Context) bool {
+	return s.settingsService.GetBoolSetting(ctx, "metricsEnabled", true)
+}
+
+// Interval returns the configured collection interval, never below MinMetricsInterval.
+func (s *MetricsService) Interval(ctx context.Context) time.Duration {
+	seconds := s.settingsService.GetIntSetting(ctx, "metricsInterval", int(DefaultMetricsInterval/time.Second))
+	interval := time.Duration(seconds) * time.Second
+	if interval < MinMetricsInterval {
+		interval = MinMetricsInterval
+	}
+	return interval
+}
+
+// Collect samples the host and every running container once and stores the raw points.
+func (s *MetricsService) Collect(ctx context.Context) (int, error) {
+	now := time.Now().UTC()
+
+	samples := []models.MetricSample{s.sampleHost(ctx, now)}
+
+	containerSamples, err := s.sampleContainers(ctx, now)
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to collect container metrics", "error", err)
+	}
+	samples = append(samples, containerSamples...)
+
+	if err := s.db.WithContext(ctx).CreateInBatches(&samples, metricsInsertBatch).Error; err != nil {
+		return 0, fmt.Errorf("failed to store metric samples: %w", err)
+	}
+	return len(samples), nil
+}
+
+// Compact folds aged samples into coarser buckets and deletes everything past retention.
+func (s *MetricsService) Compact(ctx context.Context, now time.Time) error {
+	now = now.UTC()
+	for _, tier := range metricTiers {
+		cutoff := now.Add(-tier.after).Truncate(tier.to)
+		if err := s.downsample(ctx, tier.from, tier.to, cutoff); err != nil {
+			return err
+		}
+	}
+
+	retentionDays := s.settingsService.GetIntSetting(ctx, "metricsRetentionDays", defaultMetricsRetentionDays)
+	if retentionDays < 1 {
+		retentionDays = 1
+	}
+	cutoff := now.Add(-time.Duration(retentionDays) * 24 * time.Hour)
+	if err := s.db.WithContext(ctx).Where("timestamp < ?", cutoff).Delete(&models.MetricSample{}).Error; err != nil {
+		return fmt.Errorf("failed to prune metric samples: %w", err)
+	}
+	return nil
+}
+
+func (s *MetricsService) downsample(ctx context.Context, from, to time.Duration, cutoff time.Time) error {
+	fromResolution := int(from / time.Second)
+	toResolution := int(to / time.Second)
+
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var rows []models.MetricSample
+		if err := tx.Where("resolution = ? AND timestamp < ?", fromResolution, cutoff).
+			Order("timestamp ASC").
+			Find(&rows).Error; err != nil {
+			return fmt.Errorf("failed to load metric samples for downsampling: %w", err)
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+
+		folded := foldMetricSamples(rows, to)
+		for i := range folded {
+			folded[i].Resolution = toResolution
+		}
+		if err := tx.CreateInBatches(&folded, metricsInsertBatch).Error; err != nil {
+			return fmt.Errorf("failed to store downsampled metric samples: %w", err)
+		}
+		if err := tx.Where("resolution = ? AND timestamp < ?", fromResolution, cutoff).Delete(&models.MetricSample{}).Error; err != nil {
+			return fmt.Errorf("failed to delete downsampled metric samples: %w", err)
+		}
+		return nil
+	})
+}
+
+// Query returns the host series, or a single container's series, between From and To
+// averaged into Step-wide buckets.
+func (s *MetricsService) Query(ctx context.Context, query MetricsQuery) (*dto.MetricSeriesDto, error) {
+	to := query.To.UTC()
+	if query.To.IsZero() {
+		to = time.Now().UTC()
+	}
+	from := query.From.UTC()
+	if query.From.IsZero() {
+		from = to.Add(-defaultMetricsRange)
+	}
+	if !from.Before(to) {
+		return nil, fmt.Errorf("%w: from must be before to", ErrInvalidMetricsQuery)
+	}
+
+	step := query.Step
+	if step <= 0 {
+		step = max(to.Sub(from)/defaultMetricPoints, s.Interval(ctx))
+	}
+	step = step.Truncate(time.Second)
+	if step < time.Second {
+		return nil, fmt.Errorf("%w: step must be at least 1s", ErrInvalidMetricsQuery)
+	}
+	if to.Sub(from)/step > maxMetricPoints {
+		return nil, fmt.Errorf("%w: range and step would return more than %d points", ErrInvalidMetricsQuery, maxMetricPoints)
+	}
+
+	containerRef := strings.TrimSpace(query.Container)
+	q := s.db.WithContext(ctx).Where("timestamp >= ? AND timestamp < ?", from, to)
+	if containerRef == "" {
+		q = q.Where("scope = ?", models.MetricScopeHost)
+	} else {
+		q = q.Where("scope = ? AND (container_name = ? OR container_id = ?)", models.MetricScopeContainer, containerRef, containerRef)
+	}
+
+	var rows []models.MetricSample
+	if err := q.Order("timestamp ASC").Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to query metric samples: %w", err)
+	}
+
+	// A container may have been renamed inside the range; treat it as one series.
+	for i := range rows {
+		rows[i].ContainerName = ""
+	}
+
+	series := &dto.MetricSeriesDto{
+		Scope:     string(models.MetricScopeHost),
+		Container: containerRef,
+		From:      from,
+		To:        to,
+		Step:      int64(step / time.Second),
+		Points:    []dto.MetricPointDto{},
+	}
+	if containerRef != "" {
+		series.Scope = string(models.MetricScopeContainer)
+	}
+
+	for _, b := range foldMetricSamples(rows, step) {
+		series.Points = append(series.Points, dto.MetricPointDto{
+			Timestamp:     b.Timestamp,
+			CPUPercent:    b.CPUPercent,
+			CPUPercentMax: b.CPUPercentMax,
+			MemoryUsed:    b.MemoryUsed,
+			MemoryUsedMax: b.MemoryUsedMax,
+			MemoryLimit:   b.MemoryLimit,
+			DiskUsed:      b.DiskUsed,
+			DiskTotal:     b.DiskTotal,
+			NetworkRx:     b.NetworkRx,
+			NetworkTx:     b.NetworkTx,
+		})
+	}
+
+	return series, nil
+}
+
+func (s *MetricsService) sampleHost(ctx context.Context, now time.Time) models.MetricSample {
+	sample := models.MetricSample{
+		Scope:       models.MetricScopeHost,
+		Timestamp:   now,
+		SampleCount: 1,
+	}
+
+	sample.CPUPercent = s.hostCPUPercent(ctx)
+	sample.CPUPercentMax = sample.CPUPercent
+
+	if memInfo, err := mem.VirtualMemoryWithContext(ctx); err == nil && memInfo != nil {
+		sample.MemoryUsed = int64(memInfo.Used)
+		sample.MemoryLimit = int64(memInfo.Total)
+	}
+	if cgroupLimits, err := utils.DetectCgroupLimits(); err == nil {
+		// Prefer cgroup limits and usage when running inside a constrained container
+		if limit := cgroupLimits.MemoryLimit; limit > 0 && (sample.MemoryLimit == 0 || limit < sample.MemoryLimit) {
+			sample.MemoryLimit = limit
+		}
+		if usage := cgroupLimits.MemoryUsage; usage > 0 {
+			sample.MemoryUsed = usage
+		}
+	}
+	sample.MemoryUsedMax = sample.MemoryUsed
+
+	diskUsagePath := s.settingsService.GetStringSetting(ctx, "diskUsagePath", "/")
+	if diskUsagePath == "" {
+		diskUsagePath = "/"
+	}
+	if usage, err := disk.UsageWithContext(ctx, diskUsagePath); err == nil && usage != nil {
+		sample.DiskUsed = int64(usage.Used)
+		sample.DiskTotal = int64(usage.Total)
+	}
+
+	if counters, err := net.IOCountersWithContext(ctx, false); err == nil && len(counters) > 0 {
+		sample.NetworkRx = int64(counters[0].BytesRecv)
+		sample.NetworkTx = int64(counters[0].BytesSent)
+	}
+
+	return sample
+}
+
+func (s *MetricsService) hostCPUPercent(ctx context.Context) float64 {
+	times, err := cpu.TimesWithContext(ctx, false)
+	if err != nil || len(times) == 0 {
+		return 0
+	}
+	current := times[0]
+
+	s.mu.Lock()
+	previous := s.lastCPUTimes
+	s.lastCPUTimes = &current
+	s.mu.Unlock()
+
+	if previous == nil {
+		// No baseline yet; take a short measurement instead of reporting zero.
+		if vals, err := cpu.PercentWithContext(ctx, 500*time.Millisecond, false); err == nil && len(vals) > 0 {
+			return vals[0]
+		}
+		return 0
+	}
+	return cpuBusyPercent(*previous, current)
+}
+
+func cpuBusyPercent(previous, current cpu.TimesStat) float64 {
+	total := current.Total() - previous.Total()
+	if total <= 0 {
+		return 0
+	}
+	idle := (current.Idle + current.Iowait) - (previous.Idle + previous.Iowait)
+	busy := (total - idle) / total * 100
+	return min(max(busy, 0), 100)
+}
+
+func (s *MetricsService) sampleContainers(ctx context.Context, now time.Time) ([]models.MetricSample, error) {
+	dockerClient, err := s.dockerService.CreateConnection(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to Docker: %w", err)
+	}
+	defer dockerClient.Close()
+
+	containers, err := dockerClient.ContainerList(ctx, container.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list containers: %w", err)
+	}
+
+	samples := make([]*models.MetricSample, len(containers))
+	sem := make(chan struct{}, containerStatsWorker)
+	var wg sync.WaitGroup
+	for i, c := range containers {
+		wg.Add(1)
+		go func(i int, c container.Summary) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			statsCtx, cancel := context.WithTimeout(ctx, containerStatsWait)
+			defer cancel()
+
+			stats, err := readContainerStats(statsCtx, dockerClient, c.ID)
+			if err != nil {
+				slog.DebugContext(ctx, "Failed to read container stats", "containerId", c.ID, "error", err)
+				return
+			}
+			sample := containerMetricSample(c, stats, now)
+			samples[i] = &sample
+		}(i, c)
+	}
+	wg.Wait()
+
+	out := make([]models.MetricSample, 0, len(samples))
+	for _, sample := range samples {
+		if sample != nil {
+			out = append(out, *sample)
+		}
+	}
+	return out, nil
+}
+
+func readContainerStats(ctx context.Context, dockerClient *client.Client, containerID string) (*container.StatsResponse, error) {
+	// A non-streaming request waits for a second reading so precpu_stats is populated.
+	resp, err := dockerClient.ContainerStats(ctx, containerID, false)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var stats container.StatsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&stats); err != nil {
+		return nil, fmt.Errorf("failed to decode stats: %w", err)
+	}
+	return &stats, nil
+}
+
+// containerMetricSample converts a Docker stats reading using the same formulas as `docker stats`.
+func containerMetricSample(c container.Summary, stats *container.StatsResponse, now time.Time) models.MetricSample {
+	name := c.ID
+	if len(c.Names) > 0 {
+		name = strings.TrimPrefix(c.Names[0], "/")
+	}
+
+	sample := models.MetricSample{
+		Scope:         models.MetricScopeContainer,
+		ContainerID:   c.ID,
+		ContainerName: name,
+		Timestamp:     now,
+		SampleCount:   1,
+	}
+
+	cpuDelta := float64(stats.CPUStats.CPUUsage.TotalUsage) - float64(stats.PreCPUStats.CPUUsage.TotalUsage)
+	systemDelta := float64(stats.CPUStats.SystemUsage) - float64(stats.PreCPUStats.SystemUsage)
+	onlineCPUs := float64(stats.CPUStats.OnlineCPUs)
+	if onlineCPUs == 0 {
+		onlineCPUs = float64(len(stats.CPUStats.CPUUsage.PercpuUsage))
+	}
+	if cpuDelta > 0 && systemDelta > 0 {
+		sample.CPUPercent = cpuDelta / systemDelta * onlineCPUs * 100
+	}
+	sample.CPUPercentMax = sample.CPUPercent
+
+	// Page cache is reclaimable, so it is excluded from usage like the Docker CLI does.
+	usage := stats.MemoryStats.Usage
+	if inactive, ok := stats.MemoryStats.Stats["total_inactive_file"]; ok && inactive < usage {
+		usage -= inactive
+	} else if inactive, ok := stats.MemoryStats.Stats["inactive_file"]; ok && inactive < usage {
+		usage -= inactive
+	}
+	sample.MemoryUsed = int64(usage)
+	sample.MemoryUsedMax = sample.MemoryUsed
+	sample.MemoryLimit = int64(stats.MemoryStats.Limit)
+
+	for _, network := range stats.Networks {
+		sample.NetworkRx += int64(network.RxBytes)
+		sample.NetworkTx += int64(network.TxBytes)
+	}
+
+	return sample
+}
+
+type metricBucket struct {
+	sample models.MetricSample
+	cpuSum float64
+	memSum float64
+	latest time.Time
+}
+
+func (b *metricBucket) add(row models.MetricSample) {
+	weight := row.SampleCount
+	if weight <= 0 {
+		weight = 1
+	}
+	b.sample.SampleCount += weight
+	b.cpuSum += row.CPUPercent * float64(weight)
+	b.memSum += float64(row.MemoryUsed) * float64(weight)
+	b.sample.CPUPercentMax = max(b.sample.CPUPercentMax, row.CPUPercentMax, row.CPUPercent)
+	b.sample.MemoryUsedMax = max(b.sample.MemoryUsedMax, row.MemoryUsedMax, row.MemoryUsed)
+
+	// Gauges without a meaningful average (limits, disk, cumulative counters) keep the latest value.
+	if b.latest.IsZero() || !row.Timestamp.Before(b.latest) {
+		b.latest = row.Timestamp
+		b.sample.ContainerID = row.ContainerID
+		b.sample.MemoryLimit = row.MemoryLimit
+		b.sample.DiskUsed = row.DiskUsed
+		b.sample.DiskTotal = row.DiskTotal
+		b.sample.NetworkRx = row.NetworkRx
+		b.sample.NetworkTx = row.NetworkTx
+	}
+}
+
+func (b *metricBucket) result() models.MetricSample {
+	out := b.sample
+	if out.SampleCount > 0 {
+		out.CPUPercent = b.cpuSum / float64(out.SampleCount)
+		out.MemoryUsed = int64(b.memSum / float64(out.SampleCount))
+	}
+	return out
+}
+
+// foldMetricSamples averages rows into width-aligned buckets per series, ordered by
+// bucket start and then series.
+func foldMetricSamples(rows []models.MetricSample, width time.Duration) []models.MetricSample {
+	type bucketKey struct {
+		scope models.MetricScope
+		name  string
+		start int64
+	}
+
+	buckets := make(map[bucketKey]*metricBucket)
+	for _, row := range rows {
+		start := row.Timestamp.UTC().Truncate(width)
+		key := bucketKey{scope: row.Scope, name: row.ContainerName, start: start.UnixNano()}
+		b, ok := buckets[key]
+		if !ok {
+			b = &metricBucket{sample: models.MetricSample{
+				Scope:         row.Scope,
+				ContainerName: row.ContainerName,
+				Timestamp:     start,
+			}}
+			buckets[key] = b
+		}
+		b.add(row)
+	}
+
+	out := make([]models.MetricSample, 0, len(buckets))
+	for _, b := range buckets {
+		out = append(out, b.result())
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].Timestamp.Equal(out[j].Timestamp) {
+			return out[i].Timestamp.Before(out[j].Timestamp)
+		}
+		if out[i].Scope != out[j].Scope {
+			return out[i].Scope < out[j].Scope
+		}
+		return out[i].ContainerName < out[j].ContainerName
+	})
+	return out
+}
@@ -0,0 +1,102 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ofkm/arcane-backend/internal/models"
+)
+
+func setupMetricsService(t *testing.T) *MetricsService {
+	t.Helper()
+	ctx := context.Background()
+	db := setupSettingsTestDB(t)
+	require.NoError(t, db.AutoMigrate(&models.MetricSample{}))
+	settings, err := NewSettingsService(ctx, db, nil)
+	require.NoError(t, err)
+	require.NoError(t, settings.EnsureDefaultSettings(ctx))
+	return NewMetricsService(db, nil, settings)
+}
+
+func TestMetricsService_CompactAndQuery(t *testing.T) {
+	ctx := context.Background()
+	svc := setupMetricsService(t)
+
+	now := time.Date(2025, 6, 10, 12, 0, 0, 0, time.UTC)
+	old := now.Add(-30 * time.Hour).Truncate(5 * time.Minute)
+
+	var samples []models.MetricSample
+	for i := range 20 {
+		samples = append(samples, models.MetricSample{
+			Scope:         models.MetricScopeContainer,
+			ContainerID:   "abc",
+			ContainerName: "web",
+			Timestamp:     old.Add(time.Duration(i) * 30 * time.Second),
+			SampleCount:   1,
+			CPUPercent:    float64(i % 10 * 10),
+			CPUPercentMax: float64(i % 10 * 10),
+			MemoryUsed:    100,
+			MemoryUsedMax: 100,
+			NetworkRx:     int64(i),
+		})
+	}
+	// Recent host sample stays raw; one far past retention is pruned.
+	samples = append(samples,
+		models.MetricSample{Scope: models.MetricScopeHost, Timestamp: now.Add(-time.Minute), SampleCount: 1, CPUPercent: 5},
+		models.MetricSample{Scope: models.MetricScopeHost, Timestamp: now.Add(-30 * 24 * time.Hour), SampleCount: 1},
+	)
+	require.NoError(t, svc.db.Create(&samples).Error)
+
+	require.NoError(t, svc.Compact(ctx, now))
+
+	var rows []models.MetricSample
+	require.NoError(t, svc.db.Order("timestamp ASC").Find(&rows).Error)
+	require.Len(t, rows, 3)
+
+	// Twenty 30s samples land in two 5m buckets.
+	require.Equal(t, 300, rows[0].Resolution)
+	require.Equal(t, 10, rows[0].SampleCount)
+	require.InDelta(t, 45, rows[0].CPUPercent, 0.001)
+	require.InDelta(t, 90, rows[0].CPUPercentMax, 0.001)
+	require.Equal(t, int64(9), rows[0].NetworkRx)
+	require.Equal(t, 0, rows[2].Resolution)
+
+	series, err := svc.Query(ctx, MetricsQuery{Container: "abc", From: old.Add(-time.Hour), To: old.Add(time.Hour), Step: time.Hour})
+	require.NoError(t, err)
+	require.Equal(t, "container", series.Scope)
+	require.Len(t, series.Points, 1)
+	require.InDelta(t, 45, series.Points[0].CPUPercent, 0.001)
+
+	series, err = svc.Query(ctx, MetricsQuery{From: now.Add(-time.Hour), To: now, Step: time.Minute})
+	require.NoError(t, err)
+	require.Len(t, series.Points, 1)
+	require.InDelta(t, 5, series.Points[0].CPUPercent, 0.001)
+
+	_, err = svc.Query(ctx, MetricsQuery{From: now.Add(-24 * time.Hour), To: now, Step: time.Second})
+	require.ErrorIs(t, err, ErrInvalidMetricsQuery)
+}
+
+func TestContainerMetricSample(t *testing.T) {
+	stats := &container.StatsResponse{}
+	stats.CPUStats.CPUUsage.TotalUsage = 400
+	stats.CPUStats.SystemUsage = 2000
+	stats.CPUStats.OnlineCPUs = 2
+	stats.PreCPUStats.CPUUsage.TotalUsage = 200
+	stats.PreCPUStats.SystemUsage = 1000
+	stats.MemoryStats.Usage = 1000
+	stats.MemoryStats.Limit = 4000
+	stats.MemoryStats.Stats = map[string]uint64{"inactive_file": 250}
+	stats.Networks = map[string]container.NetworkStats{"eth0": {RxBytes: 10, TxBytes: 20}, "eth1": {RxBytes: 1, TxBytes: 2}}
+
+	sample := containerMetricSample(container.Summary{ID: "abc", Names: []string{"/web"}}, stats, time.Now())
+	require.Equal(t, "web", sample.ContainerName)
+	require.InDelta(t, 40, sample.CPUPercent, 0.001)
+	require.Equal(t, int64(750), sample.MemoryUsed)
+	require.Equal(t, int64(4000), sample.MemoryLimit)
+	require.Equal(t, int64(11), sample.NetworkRx)
+	require.Equal(t, int64(22), sample.NetworkTx)
+}
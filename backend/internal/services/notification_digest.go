@@ -0,0 +1,569 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/ofkm/arcane-backend/internal/models"
+)
+
+var ErrInvalidNotificationDelivery = errors.New("invalid notification delivery settings")
+
+const (
+	defaultDigestTime = "09:00"
+	// digestItemLimit caps how many queued notifications a digest lists one by one.
+	digestItemLimit = 25
+	// discordDescriptionLimit is the maximum length of a Discord embed description.
+	discordDescriptionLimit = 4096
+
+	notificationEventDigest models.NotificationEventType = "digest"
+	// appriseQueueProvider marks queued notifications that are meant for Apprise.
+	appriseQueueProvider models.NotificationProvider = "apprise"
+)
+
+// deliveryConfig reads the "delivery" block of a provider config. A missing or unreadable
+// block means immediate delivery.
+func deliveryConfig(config models.JSON) models.NotificationDeliveryConfig {
+	var wrapper struct {
+		Delivery models.NotificationDeliveryConfig `json:"delivery"`
+	}
+	if err := decodeProviderConfig(config, &wrapper, "delivery"); err != nil {
+		return models.NotificationDeliveryConfig{}
+	}
+	return wrapper.Delivery
+}
+
+func validateDeliveryConfig(config models.JSON) error {
+	if config["delivery"] == nil {
+		return nil
+	}
+	var wrapper struct {
+		Delivery models.NotificationDeliveryConfig `json:"delivery"`
+	}
+	if err := decodeProviderConfig(config, &wrapper, "delivery"); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidNotificationDelivery, err)
+	}
+	delivery := wrapper.Delivery
+
+	switch delivery.Mode {
+	case "", models.NotificationDeliveryModeImmediate, models.NotificationDeliveryModeHourly, models.NotificationDeliveryModeDaily:
+	default:
+		return fmt.Errorf("%w: unknown mode %q", ErrInvalidNotificationDelivery, delivery.Mode)
+	}
+	if delivery.DigestTime != "" {
+		if _, err := parseClock(delivery.DigestTime); err != nil {
+			return fmt.Errorf("%w: digest time: %w", ErrInvalidNotificationDelivery, err)
+		}
+	}
+	if q := delivery.QuietHours; q != nil && q.Enabled {
+		if _, err := parseClock(q.Start); err != nil {
+			return fmt.Errorf("%w: quiet hours start: %w", ErrInvalidNotificationDelivery, err)
+		}
+		if _, err := parseClock(q.End); err != nil {
+			return fmt.Errorf("%w: quiet hours end: %w", ErrInvalidNotificationDelivery, err)
+		}
+	}
+	if _, err := deliveryLocation(delivery); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidNotificationDelivery, err)
+	}
+	return nil
+}
+
+// parseClock returns the minutes past midnight of an "HH:MM" time.
+func parseClock(value string) (int, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(value))
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", value)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+func deliveryLocation(delivery models.NotificationDeliveryConfig) (*time.Location, error) {
+	tz := strings.TrimSpace(delivery.Timezone)
+	if tz == "" {
+		return time.Local, nil
+	}
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone %q: %w", tz, err)
+	}
+	return loc, nil
+}
+
+func inQuietHours(delivery models.NotificationDeliveryConfig, now time.Time) bool {
+	q := delivery.QuietHours
+	if q == nil || !q.Enabled {
+		return false
+	}
+	start, err := parseClock(q.Start)
+	if err != nil {
+		return false
+	}
+	end, err := parseClock(q.End)
+	if err != nil {
+		return false
+	}
+	loc, err := deliveryLocation(delivery)
+	if err != nil {
+		loc = time.Local
+	}
+
+	local := now.In(loc)
+	current := local.Hour()*60 + local.Minute()
+	if start <= end {
+		return current >= start && current < end
+	}
+	return current >= start || current < end
+}
+
+// isDigestible reports whether an event may wait for the next digest. Alerts and failed
+// updates need attention and are only held back by quiet hours.
+func isDigestible(event models.NotificationEventType) bool {
+	switch event {
+	case models.NotificationEventImageUpdate, models.NotificationEventContainerUpdate:
+		return true
+	case models.NotificationEventContainerRollback, models.NotificationEventAlert:
+		return false
+	default:
+		return false
+	}
+}
+
+func shouldDeferNotification(delivery models.NotificationDeliveryConfig, event models.NotificationEventType, now time.Time) bool {
+	if inQuietHours(delivery, now) {
+		return true
+	}
+	switch delivery.Mode {
+	case models.NotificationDeliveryModeHourly, models.NotificationDeliveryModeDaily:
+		return isDigestible(event)
+	case models.NotificationDeliveryModeImmediate:
+	}
+	return false
+}
+
+// digestDue reports whether a provider's queue, oldest item first, should be sent at now.
+func digestDue(delivery models.NotificationDeliveryConfig, queued []models.NotificationQueueItem, now time.Time) bool {
+	if len(queued) == 0 || inQuietHours(delivery, now) {
+		return false
+	}
+	for _, item := range queued {
+		if !isDigestible(item.EventType) {
+			return true
+		}
+	}
+
+	loc, err := deliveryLocation(delivery)
+	if err != nil {
+		loc = time.Local
+	}
+	oldest := queued[0].CreatedAt.In(loc)
+
+	switch delivery.Mode {
+	case models.NotificationDeliveryModeHourly:
+		next := time.Date(oldest.Year(), oldest.Month(), oldest.Day(), oldest.Hour(), 0, 0, 0, loc).Add(time.Hour)
+		return !now.Before(next)
+	case models.NotificationDeliveryModeDaily:
+		digestTime := delivery.DigestTime
+		if digestTime == "" {
+			digestTime = defaultDigestTime
+		}
+		minutes, err := parseClock(digestTime)
+		if err != nil {
+			minutes, _ = parseClock(defaultDigestTime)
+		}
+		next := time.Date(oldest.Year(), oldest.Month(), oldest.Day(), minutes/60, minutes%60, 0, 0, loc)
+		if !next.After(oldest) {
+			next = next.AddDate(0, 0, 1)
+		}
+		return !now.Before(next)
+	case models.NotificationDeliveryModeImmediate:
+	}
+	// Held back only by quiet hours, which are over.
+	return true
+}
+
+// deferNotification queues msgs for setting's provider when its delivery settings hold them
+// back, and reports whether it did. If queueing fails the caller sends right away.
+func (s *NotificationService) deferNotification(ctx context.Context, setting models.NotificationSettings, msgs ...notificationMessage) bool {
+	if len(msgs) == 0 || !shouldDeferNotification(deliveryConfig(setting.Config), msgs[0].Event, time.Now()) {
+		return false
+	}
+
+	items := make([]models.NotificationQueueItem, 0, len(msgs))
+	for _, msg := range msgs {
+		item, err := newQueueItem(setting.Provider, msg)
+		if err != nil {
+			slog.WarnContext(ctx, "Failed to encode queued notification", "provider", setting.Provider, "error", err)
+			return false
+		}
+		items = append(items, item)
+	}
+	if err := s.db.WithContext(ctx).Create(&items).Error; err != nil {
+		slog.WarnContext(ctx, "Failed to queue notification", "provider", setting.Provider, "error", err)
+		return false
+	}
+	return true
+}
+
+// deferSubscriptionDelivery queues msg for a subscription's channel when the delivery
+// settings that apply to the subscription hold it back, and reports whether it did. The
+// queued item remembers deliveryID so the delivery is settled when the digest is sent.
+func (s *NotificationService) deferSubscriptionDelivery(ctx context.Context, sub *models.NotificationSubscription, deliveryID string, msg notificationMessage) bool {
+	if !shouldDeferNotification(s.subscriptionDeliveryConfig(ctx, sub), msg.Event, time.Now()) {
+		return false
+	}
+
+	item, err := newQueueItem(sub.Provider, msg)
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to encode queued notification", "subscription", sub.Name, "error", err)
+		return false
+	}
+	item.SubscriptionID = &sub.ID
+	if deliveryID != "" {
+		item.DeliveryID = &deliveryID
+	}
+	if err := s.db.WithContext(ctx).Create(&item).Error; err != nil {
+		slog.WarnContext(ctx, "Failed to queue notification", "subscription", sub.Name, "error", err)
+		return false
+	}
+	return true
+}
+
+// appriseQueueSetting returns Apprise as a provider setting for the notification queue, or
+// false when Apprise is not enabled.
+func (s *NotificationService) appriseQueueSetting(ctx context.Context) (models.NotificationSettings, bool) {
+	settings, err := s.appriseService.GetSettings(ctx)
+	if err != nil || !settings.Enabled {
+		return models.NotificationSettings{}, false
+	}
+	config := models.JSON{}
+	if len(settings.Delivery) > 0 {
+		config["delivery"] = settings.Delivery
+	}
+	return models.NotificationSettings{Provider: appriseQueueProvider, Enabled: true, Config: config}, true
+}
+
+// sendApprise calls send unless Apprise's delivery settings queue msgs for later. Apprise
+// errors are logged and never fail the notification.
+func (s *NotificationService) sendApprise(ctx context.Context, send func() error, msgs ...notificationMessage) {
+	if setting, ok := s.appriseQueueSetting(ctx); ok && s.deferNotification(ctx, setting, msgs...) {
+		return
+	}
+	if err := send(); err != nil {
+		slog.WarnContext(ctx, "Failed to send Apprise notification", "error", err)
+	}
+}
+
+func newQueueItem(provider models.NotificationProvider, msg notificationMessage) (models.NotificationQueueItem, error) {
+	var payload models.JSON
+	raw, err := json.Marshal(msg)
+	if err == nil {
+		err = json.Unmarshal(raw, &payload)
+	}
+	if err != nil {
+		return models.NotificationQueueItem{}, err
+	}
+	return models.NotificationQueueItem{Provider: provider, EventType: msg.Event, Message: payload}, nil
+}
+
+// subscriptionChannel returns the config a subscription sends with: its own channel config,
+// falling back to the provider's saved notification settings.
+func (s *NotificationService) subscriptionChannel(ctx context.Context, sub *models.NotificationSubscription) (models.JSON, error) {
+	if len(sub.Config) > 0 {
+		return sub.Config, nil
+	}
+	setting, err := s.GetSettingsByProvider(ctx, sub.Provider)
+	if err != nil || !setting.Enabled {
+		return nil, fmt.Errorf("subscription has no channel config and %s notifications are not configured", sub.Provider)
+	}
+	return setting.Config, nil
+}
+
+// subscriptionDeliveryConfig returns the delivery settings of a subscription. A subscription
+// without its own "delivery" block follows the quiet hours and digest schedule of its
+// provider.
+func (s *NotificationService) subscriptionDeliveryConfig(ctx context.Context, sub *models.NotificationSubscription) models.NotificationDeliveryConfig {
+	if sub.Config["delivery"] != nil {
+		return deliveryConfig(sub.Config)
+	}
+	setting, err := s.GetSettingsByProvider(ctx, sub.Provider)
+	if err != nil {
+		return models.NotificationDeliveryConfig{}
+	}
+	return deliveryConfig(setting.Config)
+}
+
+// FlushNotificationQueue sends one digest for every provider whose queued notifications are
+// due. Queued notifications for disabled or deleted providers are dropped.
+func (s *NotificationService) FlushNotificationQueue(ctx context.Context) error {
+	return s.flushNotificationQueue(ctx, time.Now())
+}
+
+func (s *NotificationService) flushNotificationQueue(ctx context.Context, now time.Time) error {
+	var queued []models.NotificationQueueItem
+	if err := s.db.WithContext(ctx).Order("created_at ASC").Find(&queued).Error; err != nil {
+		return fmt.Errorf("failed to load notification queue: %w", err)
+	}
+	if len(queued) == 0 {
+		return nil
+	}
+
+	settings, err := s.GetAllSettings(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get notification settings: %w", err)
+	}
+	settingsByProvider := make(map[models.NotificationProvider]models.NotificationSettings, len(settings))
+	for _, setting := range settings {
+		settingsByProvider[setting.Provider] = setting
+	}
+	if setting, ok := s.appriseQueueSetting(ctx); ok {
+		settingsByProvider[appriseQueueProvider] = setting
+	}
+
+	// Subscription deliveries are digested per subscription, everything else per provider.
+	type queueTarget struct {
+		provider       models.NotificationProvider
+		subscriptionID string
+	}
+	var targets []queueTarget
+	byTarget := make(map[queueTarget][]models.NotificationQueueItem)
+	for _, item := range queued {
+		target := queueTarget{provider: item.Provider}
+		if item.SubscriptionID != nil {
+			target.subscriptionID = *item.SubscriptionID
+		}
+		if _, ok := byTarget[target]; !ok {
+			targets = append(targets, target)
+		}
+		byTarget[target] = append(byTarget[target], item)
+	}
+
+	var errs []string
+	for _, target := range targets {
+		provider := target.provider
+		items := byTarget[target]
+
+		var setting models.NotificationSettings
+		var delivery models.NotificationDeliveryConfig
+		if target.subscriptionID == "" {
+			var ok bool
+			setting, ok = settingsByProvider[provider]
+			if !ok || !setting.Enabled {
+				s.removeQueued(ctx, items)
+				continue
+			}
+			delivery = deliveryConfig(setting.Config)
+		} else {
+			var sub models.NotificationSubscription
+			err := s.db.WithContext(ctx).Where("id = ? AND enabled = ?", target.subscriptionID, true).First(&sub).Error
+			var config models.JSON
+			if err == nil {
+				config, err = s.subscriptionChannel(ctx, &sub)
+			}
+			if err != nil {
+				s.removeQueued(ctx, items)
+				s.settleQueuedDeliveries(ctx, items, fmt.Errorf("queued notification dropped: %w", err))
+				continue
+			}
+			setting = models.NotificationSettings{Provider: provider, Enabled: true, Config: config}
+			delivery = s.subscriptionDeliveryConfig(ctx, &sub)
+		}
+		if !digestDue(delivery, items, now) {
+			continue
+		}
+
+		msgs := make([]notificationMessage, 0, len(items))
+		for _, item := range items {
+			var msg notificationMessage
+			if err := decodeProviderConfig(item.Message, &msg, "queued notification"); err != nil {
+				slog.WarnContext(ctx, "Dropping unreadable queued notification", "provider", provider, "error", err)
+				continue
+			}
+			msgs = append(msgs, msg)
+		}
+
+		// Queued notifications are sent once; a failed digest is logged like any other
+		// failed notification instead of being retried every minute.
+		s.removeQueued(ctx, items)
+		if len(msgs) == 0 {
+			continue
+		}
+
+		status := "success"
+		var errMsg *string
+		sendErr := s.sendDigest(ctx, setting, msgs, now)
+		if sendErr != nil {
+			status = "failed"
+			msg := sendErr.Error()
+			errMsg = &msg
+			errs = append(errs, fmt.Sprintf("%s: %s", provider, msg))
+		}
+		s.settleQueuedDeliveries(ctx, items, sendErr)
+		s.logNotification(ctx, provider, "digest", status, errMsg, models.JSON{
+			"eventType": string(notificationEventDigest),
+			"count":     len(msgs),
+		})
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("notification errors: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+func (s *NotificationService) removeQueued(ctx context.Context, items []models.NotificationQueueItem) {
+	ids := make([]string, len(items))
+	for i, item := range items {
+		ids[i] = item.ID
+	}
+	if err := s.db.WithContext(ctx).Where("id IN ?", ids).Delete(&models.NotificationQueueItem{}).Error; err != nil {
+		slog.WarnContext(ctx, "Failed to remove queued notifications", "error", err)
+	}
+}
+
+// settleQueuedDeliveries records the outcome of the digest that carried items on the
+// subscription deliveries they were queued for.
+func (s *NotificationService) settleQueuedDeliveries(ctx context.Context, items []models.NotificationQueueItem, sendErr error) {
+	var ids []string
+	for _, item := range items {
+		if item.DeliveryID != nil {
+			ids = append(ids, *item.DeliveryID)
+		}
+	}
+	if len(ids) == 0 {
+		return
+	}
+
+	updates := map[string]any{"attempts": gorm.Expr("attempts + 1"), "next_attempt_at": nil}
+	if sendErr == nil {
+		updates["status"] = models.NotificationDeliverySuccess
+		updates["delivered_at"] = time.Now()
+		updates["last_error"] = nil
+	} else {
+		updates["status"] = models.NotificationDeliveryFailed
+		updates["last_error"] = sendErr.Error()
+	}
+	err := s.db.WithContext(ctx).Model(&models.NotificationDelivery{}).
+		Where("id IN ? AND status = ?", ids, models.NotificationDeliveryQueued).
+		Updates(updates).Error
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to update queued notification deliveries", "error", err)
+	}
+}
+
+func (s *NotificationService) sendDigest(ctx context.Context, setting models.NotificationSettings, msgs []notificationMessage, now time.Time) error {
+	switch setting.Provider {
+	case models.NotificationProviderDiscord:
+		return s.sendDiscordMessage(ctx, digestMessage(msgs, now), setting.Config)
+	case models.NotificationProviderEmail:
+		return s.sendEmailDigestNotification(ctx, msgs, now, setting.Config)
+	case models.NotificationProviderWebhook, models.NotificationProviderSlack, models.NotificationProviderTeams,
+		models.NotificationProviderNtfy, models.NotificationProviderGotify:
+		return s.sendProviderMessage(ctx, setting.Provider, digestMessage(msgs, now), setting.Config)
+	case appriseQueueProvider:
+		digest := digestMessage(msgs, now)
+		return s.appriseService.SendNotification(ctx, digest.Title, digest.Message, "text", notificationEventDigest)
+	default:
+		return fmt.Errorf("unknown provider: %s", setting.Provider)
+	}
+}
+
+// digestMessage summarises queued notifications in a single message.
+func digestMessage(msgs []notificationMessage, now time.Time) notificationMessage {
+	var lines []string
+	for i, msg := range msgs {
+		if i == digestItemLimit {
+			lines = append(lines, fmt.Sprintf("…and %d more", len(msgs)-digestItemLimit))
+			break
+		}
+		lines = append(lines, "• "+msg.Title)
+	}
+
+	var fields []notificationField
+	for _, count := range digestCounts(msgs) {
+		fields = append(fields, notificationField{Name: count.label, Value: fmt.Sprintf("%d", count.count)})
+	}
+
+	return notificationMessage{
+		Event:     notificationEventDigest,
+		Title:     fmt.Sprintf("Arcane Digest: %d Notification(s)", len(msgs)),
+		Message:   truncateText(strings.Join(lines, "\n"), discordDescriptionLimit),
+		Severity:  digestSeverity(msgs),
+		Fields:    fields,
+		Data:      map[string]any{"count": len(msgs), "notifications": msgs},
+		Timestamp: now,
+	}
+}
+
+type digestCount struct {
+	label string
+	count int
+}
+
+// digestCounts counts msgs per event type, in order of first appearance.
+func digestCounts(msgs []notificationMessage) []digestCount {
+	var counts []digestCount
+	index := make(map[models.NotificationEventType]int)
+	for _, msg := range msgs {
+		i, ok := index[msg.Event]
+		if !ok {
+			i = len(counts)
+			index[msg.Event] = i
+			counts = append(counts, digestCount{label: notificationEventLabel(msg.Event)})
+		}
+		counts[i].count++
+	}
+	return counts
+}
+
+func digestSummary(msgs []notificationMessage) string {
+	parts := make([]string, 0, 4)
+	for _, count := range digestCounts(msgs) {
+		parts = append(parts, fmt.Sprintf("%s: %d", count.label, count.count))
+	}
+	return strings.Join(parts, " · ")
+}
+
+func notificationEventLabel(event models.NotificationEventType) string {
+	switch event {
+	case models.NotificationEventImageUpdate:
+		return "Image Updates"
+	case models.NotificationEventContainerUpdate:
+		return "Container Updates"
+	case models.NotificationEventContainerRollback:
+		return "Container Rollbacks"
+	case models.NotificationEventAlert:
+		return "Alerts"
+	default:
+		return string(event)
+	}
+}
+
+func digestSeverity(msgs []notificationMessage) notificationSeverity {
+	rank := func(sev notificationSeverity) int {
+		switch sev {
+		case notificationSeverityError:
+			return 3
+		case notificationSeverityWarning:
+			return 2
+		case notificationSeveritySuccess:
+			return 1
+		case notificationSeverityInfo:
+		}
+		return 0
+	}
+	severity := notificationSeverityInfo
+	for _, msg := range msgs {
+		if rank(msg.Severity) > rank(severity) {
+			severity = msg.Severity
+		}
+	}
+	return severity
+}
@@ -0,0 +1,177 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ofkm/arcane-backend/internal/dto"
+	"github.com/ofkm/arcane-backend/internal/models"
+)
+
+func TestInQuietHours(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	delivery := models.NotificationDeliveryConfig{
+		QuietHours: &models.NotificationQuietHours{Enabled: true, Start: "22:00", End: "07:00"},
+		Timezone:   "Europe/Berlin",
+	}
+	require.True(t, inQuietHours(delivery, time.Date(2025, 6, 1, 23, 30, 0, 0, berlin)))
+	require.True(t, inQuietHours(delivery, time.Date(2025, 6, 2, 6, 59, 0, 0, berlin)))
+	require.False(t, inQuietHours(delivery, time.Date(2025, 6, 2, 7, 0, 0, 0, berlin)))
+	// 21:30 UTC is 23:30 in Berlin during summer time.
+	require.True(t, inQuietHours(delivery, time.Date(2025, 6, 1, 21, 30, 0, 0, time.UTC)))
+
+	delivery.QuietHours = &models.NotificationQuietHours{Enabled: true, Start: "12:00", End: "13:00"}
+	require.True(t, inQuietHours(delivery, time.Date(2025, 6, 1, 12, 15, 0, 0, berlin)))
+	require.False(t, inQuietHours(delivery, time.Date(2025, 6, 1, 13, 15, 0, 0, berlin)))
+
+	delivery.QuietHours.Enabled = false
+	require.False(t, inQuietHours(delivery, time.Date(2025, 6, 1, 12, 15, 0, 0, berlin)))
+}
+
+func TestDigestDue(t *testing.T) {
+	queuedAt := time.Date(2025, 6, 1, 10, 20, 0, 0, time.UTC)
+	queued := []models.NotificationQueueItem{{EventType: models.NotificationEventImageUpdate, BaseModel: models.BaseModel{CreatedAt: queuedAt}}}
+
+	hourly := models.NotificationDeliveryConfig{Mode: models.NotificationDeliveryModeHourly, Timezone: "UTC"}
+	require.False(t, digestDue(hourly, queued, queuedAt.Add(30*time.Minute)))
+	require.True(t, digestDue(hourly, queued, time.Date(2025, 6, 1, 11, 0, 0, 0, time.UTC)))
+
+	daily := models.NotificationDeliveryConfig{Mode: models.NotificationDeliveryModeDaily, DigestTime: "08:00", Timezone: "UTC"}
+	require.False(t, digestDue(daily, queued, time.Date(2025, 6, 1, 23, 0, 0, 0, time.UTC)))
+	require.True(t, digestDue(daily, queued, time.Date(2025, 6, 2, 8, 0, 0, 0, time.UTC)))
+
+	// Queued during quiet hours in immediate mode: sent once they end.
+	quiet := models.NotificationDeliveryConfig{
+		QuietHours: &models.NotificationQuietHours{Enabled: true, Start: "22:00", End: "07:00"},
+		Timezone:   "UTC",
+	}
+	require.False(t, digestDue(quiet, queued, time.Date(2025, 6, 1, 23, 0, 0, 0, time.UTC)))
+	require.True(t, digestDue(quiet, queued, time.Date(2025, 6, 2, 7, 0, 0, 0, time.UTC)))
+
+	// An alert held back by quiet hours does not wait for the daily digest.
+	daily.QuietHours = quiet.QuietHours
+	withAlert := append(queued, models.NotificationQueueItem{EventType: models.NotificationEventAlert, BaseModel: models.BaseModel{CreatedAt: queuedAt}})
+	require.False(t, digestDue(daily, withAlert, time.Date(2025, 6, 1, 23, 0, 0, 0, time.UTC)))
+	require.True(t, digestDue(daily, withAlert, time.Date(2025, 6, 2, 7, 0, 0, 0, time.UTC)))
+}
+
+func TestNotificationService_BatchesImageUpdatesIntoDigest(t *testing.T) {
+	ctx := context.Background()
+	svc := setupNotificationService(t)
+	require.NoError(t, svc.db.AutoMigrate(&models.NotificationQueueItem{}))
+	server, requests := newCaptureServer(t)
+
+	_, err := svc.CreateOrUpdateSettings(ctx, models.NotificationProviderWebhook, true, models.JSON{
+		"url":      server.URL,
+		"delivery": map[string]any{"mode": "hourly"},
+	})
+	require.NoError(t, err)
+
+	updates := make(map[string]*dto.ImageUpdateResponse)
+	for i := range 3 {
+		updates[fmt.Sprintf("app-%d:latest", i)] = &dto.ImageUpdateResponse{HasUpdate: true, UpdateType: "digest"}
+	}
+	require.NoError(t, svc.SendBatchImageUpdateNotification(ctx, updates))
+	require.Empty(t, requests)
+
+	var items []models.NotificationQueueItem
+	require.NoError(t, svc.db.Order("created_at ASC").Find(&items).Error)
+	require.Len(t, items, 3)
+	queuedAt := items[0].CreatedAt
+
+	// Not due before the top of the next hour.
+	require.NoError(t, svc.flushNotificationQueue(ctx, queuedAt))
+	require.Empty(t, requests)
+
+	require.NoError(t, svc.flushNotificationQueue(ctx, queuedAt.Add(time.Hour)))
+	req := <-requests
+	var body map[string]any
+	require.NoError(t, json.Unmarshal(req.body, &body))
+	require.Equal(t, "digest", body["event"])
+	require.Equal(t, "Arcane Digest: 3 Notification(s)", body["title"])
+	require.Contains(t, body["message"], "app-0:latest")
+
+	var queued int64
+	require.NoError(t, svc.db.Model(&models.NotificationQueueItem{}).Count(&queued).Error)
+	require.Zero(t, queued)
+
+	// Alerts are not held for the digest.
+	require.NoError(t, svc.SendAlertNotification(ctx, AlertNotification{RuleName: "CPU", Resource: "web", Status: models.AlertStatusFiring}))
+	req = <-requests
+	require.NoError(t, json.Unmarshal(req.body, &body))
+	require.Equal(t, "alert", body["event"])
+}
+
+func TestNotificationService_QuietHoursHoldBackApprise(t *testing.T) {
+	ctx := context.Background()
+	svc := setupNotificationService(t)
+	require.NoError(t, svc.db.AutoMigrate(&models.NotificationQueueItem{}))
+	server, requests := newCaptureServer(t)
+
+	// Quiet hours around the current time, read in UTC.
+	now := time.Now().UTC()
+	_, err := svc.appriseService.CreateOrUpdateSettings(ctx, server.URL, true, "", "", models.JSON{
+		"quietHours": map[string]any{"enabled": true, "start": now.Add(-time.Hour).Format("15:04"), "end": now.Add(time.Hour).Format("15:04")},
+		"timezone":   "UTC",
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, svc.SendAlertNotification(ctx, AlertNotification{RuleName: "CPU", Resource: "web", Status: models.AlertStatusFiring}))
+	require.Empty(t, requests, "Apprise notified during quiet hours")
+
+	var items []models.NotificationQueueItem
+	require.NoError(t, svc.db.Find(&items).Error)
+	require.Len(t, items, 1)
+	require.Equal(t, appriseQueueProvider, items[0].Provider)
+
+	require.NoError(t, svc.flushNotificationQueue(ctx, now))
+	require.Empty(t, requests)
+	require.NoError(t, svc.flushNotificationQueue(ctx, now.Add(2*time.Hour)))
+	req := <-requests
+	var body map[string]any
+	require.NoError(t, json.Unmarshal(req.body, &body))
+	require.Equal(t, "Arcane Digest: 1 Notification(s)", body["title"])
+	require.Contains(t, body["body"], "Alert Firing: CPU")
+
+	_, err = svc.appriseService.CreateOrUpdateSettings(ctx, server.URL, true, "", "", models.JSON{"mode": "weekly"})
+	require.ErrorIs(t, err, ErrInvalidNotificationDelivery)
+}
+
+func TestNotificationService_RejectsInvalidDeliverySettings(t *testing.T) {
+	ctx := context.Background()
+	svc := setupNotificationService(t)
+
+	for _, delivery := range []map[string]any{
+		{"mode": "weekly"},
+		{"mode": "daily", "digestTime": "25:00"},
+		{"quietHours": map[string]any{"enabled": true, "start": "22:00", "end": "soon"}},
+		{"timezone": "Mars/Olympus"},
+	} {
+		_, err := svc.CreateOrUpdateSettings(ctx, models.NotificationProviderSlack, true, models.JSON{"delivery": delivery})
+		require.ErrorIs(t, err, ErrInvalidNotificationDelivery, delivery)
+	}
+}
+
+func TestRenderDigestEmailTemplate(t *testing.T) {
+	svc := &NotificationService{}
+	msgs := make([]notificationMessage, 0, 30)
+	for i := range 30 {
+		msgs = append(msgs, imageUpdateMessage(fmt.Sprintf("app-%d:<latest>", i), &dto.ImageUpdateResponse{HasUpdate: true}, models.NotificationEventImageUpdate))
+	}
+
+	html, text, err := svc.renderDigestEmailTemplate(msgs, time.Now())
+	require.NoError(t, err)
+	require.Contains(t, html, "app-0:&lt;latest&gt;")
+	require.NotContains(t, html, "app-0:<latest>")
+	require.Contains(t, html, "…and 5 more")
+	require.Contains(t, text, "ARCANE DIGEST: 30 NOTIFICATION(S)")
+	require.Contains(t, text, "Image Updates: 30")
+	require.Contains(t, text, "...and 5 more")
+}
@@ -0,0 +1,757 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/ofkm/arcane-backend/internal/dto"
+	"github.com/ofkm/arcane-backend/internal/models"
+	"github.com/ofkm/arcane-backend/internal/utils"
+)
+
+// notificationSeverity drives colours and priorities on providers that support them.
+type notificationSeverity string
+
+const (
+	notificationSeverityInfo    notificationSeverity = "info"
+	notificationSeveritySuccess notificationSeverity = "success"
+	notificationSeverityWarning notificationSeverity = "warning"
+	notificationSeverityError   notificationSeverity = "error"
+)
+
+const (
+	defaultNtfyServerURL = "https://ntfy.sh"
+	// slackSectionFieldLimit is the maximum number of fields in a Slack section block.
+	slackSectionFieldLimit = 10
+	slackFieldTextLimit    = 2000
+)
+
+type notificationField struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// notificationMessage is the provider-neutral form of a notification. The webhook, Slack,
+// Teams, ntfy and Gotify providers each render it in their own payload format. Its exported
+// fields are also what custom webhook body templates see.
+type notificationMessage struct {
+	Event     models.NotificationEventType `json:"event"`
+	Title     string                       `json:"title"`
+	Message   string                       `json:"message"`
+	Severity  notificationSeverity         `json:"severity"`
+	URL       string                       `json:"url,omitempty"`
+	Fields    []notificationField          `json:"fields,omitempty"`
+	Data      map[string]any               `json:"data,omitempty"`
+	Timestamp time.Time                    `json:"timestamp"`
+}
+
+// sendProviderMessage delivers msg through one of the message-based providers.
+func (s *NotificationService) sendProviderMessage(ctx context.Context, provider models.NotificationProvider, msg notificationMessage, config models.JSON) error {
+	switch provider {
+	case models.NotificationProviderWebhook:
+		return s.sendWebhookMessage(ctx, msg, config)
+	case models.NotificationProviderSlack:
+		return s.sendSlackMessage(ctx, msg, config)
+	case models.NotificationProviderTeams:
+		return s.sendTeamsMessage(ctx, msg, config)
+	case models.NotificationProviderNtfy:
+		return s.sendNtfyMessage(ctx, msg, config)
+	case models.NotificationProviderGotify:
+		return s.sendGotifyMessage(ctx, msg, config)
+	case models.NotificationProviderDiscord, models.NotificationProviderEmail:
+		return fmt.Errorf("%s notifications use event-specific templates", provider)
+	default:
+		return fmt.Errorf("unknown provider: %s", provider)
+	}
+}
+
+func imageUpdateMessage(imageRef string, updateInfo *dto.ImageUpdateResponse, eventType models.NotificationEventType) notificationMessage {
+	msg := notificationMessage{
+		Event:    eventType,
+		Title:    fmt.Sprintf("Container Image Update Available: %s", imageRef),
+		Message:  fmt.Sprintf("A new update has been detected for %s", imageRef),
+		Severity: notificationSeverityInfo,
+		Fields: []notificationField{
+			{Name: "Image", Value: imageRef},
+			{Name: "Update Type", Value: updateInfo.UpdateType},
+		},
+		Data: map[string]any{
+			"imageRef":      imageRef,
+			"hasUpdate":     updateInfo.HasUpdate,
+			"updateType":    updateInfo.UpdateType,
+			"currentDigest": updateInfo.CurrentDigest,
+			"latestDigest":  updateInfo.LatestDigest,
+		},
+		Timestamp: time.Now(),
+	}
+	if updateInfo.CurrentDigest != "" {
+		msg.Fields = append(msg.Fields, notificationField{Name: "Current Digest", Value: truncateDigest(updateInfo.CurrentDigest)})
+	}
+	if updateInfo.LatestDigest != "" {
+		msg.Fields = append(msg.Fields, notificationField{Name: "Latest Digest", Value: truncateDigest(updateInfo.LatestDigest)})
+	}
+	if version := updateVersion(updateInfo); version != "" {
+		msg.Fields = append(msg.Fields, notificationField{Name: "New Version", Value: version})
+		msg.Data["latestVersion"] = version
+	}
+	if rel := updateInfo.Release; rel != nil {
+		if link := releaseLink(rel); link != "" {
+			msg.URL = link
+		}
+		if rel.Notes != "" {
+			msg.Fields = append(msg.Fields, notificationField{Name: "Release Notes", Value: truncateText(rel.Notes, discordFieldLimit)})
+			msg.Data["releaseNotes"] = rel.Notes
+		}
+	}
+	return msg
+}
+
+func batchImageUpdateMessage(updates map[string]*dto.ImageUpdateResponse) notificationMessage {
+	imageRefs := make([]string, 0, len(updates))
+	for ref := range updates {
+		imageRefs = append(imageRefs, ref)
+	}
+	sort.Strings(imageRefs)
+
+	fields := make([]notificationField, 0, len(imageRefs))
+	images := make([]map[string]any, 0, len(imageRefs))
+	for _, ref := range imageRefs {
+		update := updates[ref]
+		value := update.UpdateType
+		if version := updateVersion(update); version != "" {
+			value += " → " + version
+		}
+		fields = append(fields, notificationField{Name: ref, Value: value})
+		images = append(images, map[string]any{
+			"imageRef":      ref,
+			"updateType":    update.UpdateType,
+			"currentDigest": update.CurrentDigest,
+			"latestDigest":  update.LatestDigest,
+		})
+	}
+
+	return notificationMessage{
+		Event:     models.NotificationEventImageUpdate,
+		Title:     fmt.Sprintf("%d Container Image Update(s) Available", len(updates)),
+		Message:   "The following images have updates available.",
+		Severity:  notificationSeverityInfo,
+		Fields:    fields,
+		Data:      map[string]any{"updateCount": len(updates), "images": images},
+		Timestamp: time.Now(),
+	}
+}
+
+// imageUpdateMessages builds one image update message per image, sorted by reference.
+func imageUpdateMessages(updates map[string]*dto.ImageUpdateResponse) []notificationMessage {
+	imageRefs := make([]string, 0, len(updates))
+	for ref := range updates {
+		imageRefs = append(imageRefs, ref)
+	}
+	sort.Strings(imageRefs)
+
+	msgs := make([]notificationMessage, 0, len(imageRefs))
+	for _, ref := range imageRefs {
+		msgs = append(msgs, imageUpdateMessage(ref, updates[ref], models.NotificationEventImageUpdate))
+	}
+	return msgs
+}
+
+func containerUpdateMessage(containerName, imageRef, oldDigest, newDigest string) notificationMessage {
+	msg := notificationMessage{
+		Event:    models.NotificationEventContainerUpdate,
+		Title:    fmt.Sprintf("Container Updated: %s", containerName),
+		Message:  "Your container has been updated with the latest image version.",
+		Severity: notificationSeveritySuccess,
+		Fields: []notificationField{
+			{Name: "Container", Value: containerName},
+			{Name: "Image", Value: imageRef},
+		},
+		Data: map[string]any{
+			"containerName": containerName,
+			"imageRef":      imageRef,
+			"oldDigest":     oldDigest,
+			"newDigest":     newDigest,
+		},
+		Timestamp: time.Now(),
+	}
+	if oldDigest != "" {
+		msg.Fields = append(msg.Fields, notificationField{Name: "Previous Version", Value: truncateDigest(oldDigest)})
+	}
+	if newDigest != "" {
+		msg.Fields = append(msg.Fields, notificationField{Name: "Current Version", Value: truncateDigest(newDigest)})
+	}
+	return msg
+}
+
+func containerRollbackMessage(containerName, imageRef, failedDigest, restoredDigest, reason string, rolledBack bool) notificationMessage {
+	msg := notificationMessage{
+		Event:    models.NotificationEventContainerRollback,
+		Title:    fmt.Sprintf("Container Rolled Back: %s", containerName),
+		Message:  "The updated container did not pass its health check and was rolled back to the previous image.",
+		Severity: notificationSeverityWarning,
+		Fields: []notificationField{
+			{Name: "Container", Value: containerName},
+			{Name: "Image", Value: imageRef},
+			{Name: "Reason", Value: reason},
+		},
+		Data: map[string]any{
+			"containerName":  containerName,
+			"imageRef":       imageRef,
+			"failedDigest":   failedDigest,
+			"restoredDigest": restoredDigest,
+			"reason":         reason,
+			"rolledBack":     rolledBack,
+		},
+		Timestamp: time.Now(),
+	}
+	if !rolledBack {
+		msg.Title = fmt.Sprintf("Container Update Failed: %s", containerName)
+		msg.Message = "The updated container did not pass its health check and the rollback failed. Manual action is required."
+		msg.Severity = notificationSeverityError
+	}
+	if failedDigest != "" {
+		msg.Fields = append(msg.Fields, notificationField{Name: "Failed Version", Value: truncateDigest(failedDigest)})
+	}
+	if restoredDigest != "" {
+		msg.Fields = append(msg.Fields, notificationField{Name: "Restored Version", Value: truncateDigest(restoredDigest)})
+	}
+	return msg
+}
+
+func alertMessage(alert AlertNotification) notificationMessage {
+	severity := notificationSeverityError
+	if alert.Status == models.AlertStatusResolved {
+		severity = notificationSeveritySuccess
+	}
+	return notificationMessage{
+		Event:    models.NotificationEventAlert,
+		Title:    alertTitle(alert),
+		Message:  alert.Message,
+		Severity: severity,
+		Fields: []notificationField{
+			{Name: "Resource", Value: alert.Resource},
+			{Name: "Status", Value: string(alert.Status)},
+			{Name: "Value", Value: alert.Value},
+			{Name: "Threshold", Value: alert.Threshold},
+		},
+		Data: map[string]any{
+			"ruleName":  alert.RuleName,
+			"ruleType":  string(alert.RuleType),
+			"resource":  alert.Resource,
+			"status":    string(alert.Status),
+			"value":     alert.Value,
+			"threshold": alert.Threshold,
+		},
+		Timestamp: time.Now(),
+	}
+}
+
+func testMessage() notificationMessage {
+	return notificationMessage{
+		Event:     "test",
+		Title:     "Test Notification from Arcane",
+		Message:   "If you're reading this, your notification provider is working correctly!",
+		Severity:  notificationSeverityInfo,
+		Fields:    []notificationField{{Name: "Image", Value: "test/image:latest"}},
+		Data:      map[string]any{"test": true},
+		Timestamp: time.Now(),
+	}
+}
+
+// Text renders the message body with one "Name: Value" line per field. It is exported so
+// webhook body templates can use {{.Text}}.
+func (m notificationMessage) Text() string {
+	var b strings.Builder
+	b.WriteString(m.Message)
+	if len(m.Fields) > 0 {
+		b.WriteString("\n")
+	}
+	for _, f := range m.Fields {
+		fmt.Fprintf(&b, "\n%s: %s", f.Name, f.Value)
+	}
+	if m.URL != "" {
+		fmt.Fprintf(&b, "\n\n%s", m.URL)
+	}
+	return b.String()
+}
+
+// Markdown renders the message body with bold field names.
+func (m notificationMessage) Markdown() string {
+	var b strings.Builder
+	b.WriteString(m.Message)
+	if len(m.Fields) > 0 {
+		b.WriteString("\n")
+	}
+	for _, f := range m.Fields {
+		fmt.Fprintf(&b, "\n**%s:** %s", f.Name, f.Value)
+	}
+	if m.URL != "" {
+		fmt.Fprintf(&b, "\n\n[Details](%s)", m.URL)
+	}
+	return b.String()
+}
+
+func decodeProviderConfig(config models.JSON, target any, name string) error {
+	configBytes, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s config: %w", name, err)
+	}
+	if err := json.Unmarshal(configBytes, target); err != nil {
+		return fmt.Errorf("failed to unmarshal %s config: %w", name, err)
+	}
+	return nil
+}
+
+// decryptSecret returns the plaintext of an encrypted config value, or the value itself
+// when it was stored unencrypted.
+func decryptSecret(value string) string {
+	if decrypted, err := utils.Decrypt(value); err == nil {
+		return decrypted
+	}
+	return value
+}
+
+// validateProviderURL checks that a user-supplied provider URL is an absolute http(s) URL.
+// Self-hosted services such as ntfy and Gotify commonly live on the local network, so
+// unlike Discord the host is not restricted.
+func validateProviderURL(rawURL string, requireHTTPS bool) (*url.URL, error) {
+	parsedURL, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse URL: %w", err)
+	}
+	switch parsedURL.Scheme {
+	case "https":
+	case "http":
+		if requireHTTPS {
+			return nil, fmt.Errorf("URL must use HTTPS")
+		}
+	default:
+		return nil, fmt.Errorf("URL must use HTTP or HTTPS")
+	}
+	if parsedURL.Host == "" {
+		return nil, fmt.Errorf("URL must include a host")
+	}
+	return parsedURL, nil
+}
+
+func (s *NotificationService) postNotification(ctx context.Context, method, target string, headers map[string]string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, method, target, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send notification: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		bodyBytes, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("endpoint returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(bodyBytes)))
+	}
+	return nil
+}
+
+var webhookTemplateFuncs = template.FuncMap{
+	// json renders a value as a JSON literal, so templates can embed strings safely.
+	"json": func(v any) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+}
+
+func renderWebhookBody(bodyTemplate string, msg notificationMessage) ([]byte, error) {
+	if strings.TrimSpace(bodyTemplate) == "" {
+		return json.Marshal(msg)
+	}
+	tmpl, err := template.New("webhook").Funcs(webhookTemplateFuncs).Option("missingkey=zero").Parse(bodyTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid body template: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, msg); err != nil {
+		return nil, fmt.Errorf("failed to render body template: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func (s *NotificationService) sendWebhookMessage(ctx context.Context, msg notificationMessage, config models.JSON) error {
+	var webhookConfig models.WebhookConfig
+	if err := decodeProviderConfig(config, &webhookConfig, "webhook"); err != nil {
+		return err
+	}
+	if webhookConfig.URL == "" {
+		return fmt.Errorf("webhook URL not configured")
+	}
+
+	webhookURL := decryptSecret(webhookConfig.URL)
+	if _, err := validateProviderURL(webhookURL, false); err != nil {
+		return fmt.Errorf("invalid webhook URL: %w", err)
+	}
+
+	method := strings.ToUpper(webhookConfig.Method)
+	switch method {
+	case "":
+		method = http.MethodPost
+	case http.MethodPost, http.MethodPut, http.MethodPatch:
+	default:
+		return fmt.Errorf("unsupported webhook method %q", webhookConfig.Method)
+	}
+
+	body, err := renderWebhookBody(webhookConfig.BodyTemplate, msg)
+	if err != nil {
+		return err
+	}
+
+	contentType := webhookConfig.ContentType
+	if contentType == "" {
+		contentType = "application/json"
+	}
+	headers := map[string]string{"Content-Type": contentType}
+	for key, value := range webhookConfig.Headers {
+		headers[key] = decryptSecret(value)
+	}
+
+	return s.postNotification(ctx, method, webhookURL, headers, body)
+}
+
+func (s *NotificationService) sendSlackMessage(ctx context.Context, msg notificationMessage, config models.JSON) error {
+	var slackConfig models.SlackConfig
+	if err := decodeProviderConfig(config, &slackConfig, "Slack"); err != nil {
+		return err
+	}
+	if slackConfig.WebhookURL == "" {
+		return fmt.Errorf("slack webhook URL not configured")
+	}
+
+	webhookURL := decryptSecret(slackConfig.WebhookURL)
+	parsedURL, err := validateProviderURL(webhookURL, true)
+	if err != nil {
+		return fmt.Errorf("invalid webhook URL: %w", err)
+	}
+	if parsedURL.Host != "hooks.slack.com" {
+		return fmt.Errorf("invalid webhook URL: must be a Slack incoming webhook URL")
+	}
+
+	payloadBytes, err := json.Marshal(slackPayload(msg, slackConfig))
+	if err != nil {
+		return fmt.Errorf("failed to marshal Slack payload: %w", err)
+	}
+	return s.postNotification(ctx, http.MethodPost, webhookURL, map[string]string{"Content-Type": "application/json"}, payloadBytes)
+}
+
+func slackPayload(msg notificationMessage, cfg models.SlackConfig) map[string]any {
+	blocks := []map[string]any{
+		{
+			"type": "header",
+			"text": map[string]any{"type": "plain_text", "text": truncateText(msg.Title, 150), "emoji": true},
+		},
+	}
+	if msg.Message != "" {
+		blocks = append(blocks, map[string]any{
+			"type": "section",
+			"text": map[string]any{"type": "mrkdwn", "text": truncateText(msg.Message, 3000)},
+		})
+	}
+	for start := 0; start < len(msg.Fields); start += slackSectionFieldLimit {
+		end := min(start+slackSectionFieldLimit, len(msg.Fields))
+		fields := make([]map[string]any, 0, end-start)
+		for _, f := range msg.Fields[start:end] {
+			fields = append(fields, map[string]any{
+				"type": "mrkdwn",
+				"text": truncateText(fmt.Sprintf("*%s*\n%s", f.Name, f.Value), slackFieldTextLimit),
+			})
+		}
+		blocks = append(blocks, map[string]any{"type": "section", "fields": fields})
+	}
+	if msg.URL != "" {
+		blocks = append(blocks, map[string]any{
+			"type": "actions",
+			"elements": []map[string]any{{
+				"type": "button",
+				"text": map[string]any{"type": "plain_text", "text": "View Details"},
+				"url":  msg.URL,
+			}},
+		})
+	}
+	blocks = append(blocks, map[string]any{
+		"type":     "context",
+		"elements": []map[string]any{{"type": "mrkdwn", "text": fmt.Sprintf("Arcane • %s", msg.Timestamp.Format(time.RFC1123))}},
+	})
+
+	// text is the fallback shown in push notifications and clients without Block Kit.
+	payload := map[string]any{
+		"text":   msg.Title,
+		"blocks": blocks,
+	}
+	if cfg.Channel != "" {
+		payload["channel"] = cfg.Channel
+	}
+	if cfg.Username != "" {
+		payload["username"] = cfg.Username
+	}
+	if cfg.IconEmoji != "" {
+		payload["icon_emoji"] = cfg.IconEmoji
+	}
+	return payload
+}
+
+func (s *NotificationService) sendTeamsMessage(ctx context.Context, msg notificationMessage, config models.JSON) error {
+	var teamsConfig models.TeamsConfig
+	if err := decodeProviderConfig(config, &teamsConfig, "Teams"); err != nil {
+		return err
+	}
+	if teamsConfig.WebhookURL == "" {
+		return fmt.Errorf("teams webhook URL not configured")
+	}
+
+	webhookURL := decryptSecret(teamsConfig.WebhookURL)
+	if _, err := validateProviderURL(webhookURL, true); err != nil {
+		return fmt.Errorf("invalid webhook URL: %w", err)
+	}
+
+	payloadBytes, err := json.Marshal(teamsPayload(msg))
+	if err != nil {
+		return fmt.Errorf("failed to marshal Teams payload: %w", err)
+	}
+	return s.postNotification(ctx, http.MethodPost, webhookURL, map[string]string{"Content-Type": "application/json"}, payloadBytes)
+}
+
+// teamsPayload wraps an Adaptive Card in a message, which both Teams incoming webhooks
+// and Power Automate "post to a channel when a webhook request is received" flows accept.
+func teamsPayload(msg notificationMessage) map[string]any {
+	color := "Accent"
+	switch msg.Severity {
+	case notificationSeveritySuccess:
+		color = "Good"
+	case notificationSeverityWarning:
+		color = "Warning"
+	case notificationSeverityError:
+		color = "Attention"
+	case notificationSeverityInfo:
+	}
+
+	body := []map[string]any{
+		{"type": "TextBlock", "text": msg.Title, "weight": "Bolder", "size": "Medium", "color": color, "wrap": true},
+	}
+	if msg.Message != "" {
+		body = append(body, map[string]any{"type": "TextBlock", "text": msg.Message, "wrap": true})
+	}
+	if len(msg.Fields) > 0 {
+		facts := make([]map[string]any, 0, len(msg.Fields))
+		for _, f := range msg.Fields {
+			facts = append(facts, map[string]any{"title": f.Name, "value": f.Value})
+		}
+		body = append(body, map[string]any{"type": "FactSet", "facts": facts})
+	}
+
+	card := map[string]any{
+		"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+		"type":    "AdaptiveCard",
+		"version": "1.4",
+		"body":    body,
+	}
+	if msg.URL != "" {
+		card["actions"] = []map[string]any{{"type": "Action.OpenUrl", "title": "View Details", "url": msg.URL}}
+	}
+
+	return map[string]any{
+		"type": "message",
+		"attachments": []map[string]any{{
+			"contentType": "application/vnd.microsoft.card.adaptive",
+			"content":     card,
+		}},
+	}
+}
+
+func (s *NotificationService) sendNtfyMessage(ctx context.Context, msg notificationMessage, config models.JSON) error {
+	var ntfyConfig models.NtfyConfig
+	if err := decodeProviderConfig(config, &ntfyConfig, "ntfy"); err != nil {
+		return err
+	}
+	if ntfyConfig.Topic == "" {
+		return fmt.Errorf("ntfy topic not configured")
+	}
+
+	serverURL := strings.TrimRight(ntfyConfig.ServerURL, "/")
+	if serverURL == "" {
+		serverURL = defaultNtfyServerURL
+	}
+	if _, err := validateProviderURL(serverURL, false); err != nil {
+		return fmt.Errorf("invalid ntfy server URL: %w", err)
+	}
+
+	priority := ntfyConfig.Priority
+	if priority == 0 {
+		priority = 3
+		if msg.Severity == notificationSeverityError {
+			priority = 4
+		}
+	}
+	if priority < 1 || priority > 5 {
+		return fmt.Errorf("ntfy priority must be between 1 and 5")
+	}
+
+	tags := append([]string{}, ntfyConfig.Tags...)
+	switch msg.Severity {
+	case notificationSeveritySuccess:
+		tags = append(tags, "white_check_mark")
+	case notificationSeverityWarning:
+		tags = append(tags, "warning")
+	case notificationSeverityError:
+		tags = append(tags, "rotating_light")
+	case notificationSeverityInfo:
+	}
+
+	// Publishing JSON to the server root lets titles and messages contain any characters.
+	payload := map[string]any{
+		"topic":    ntfyConfig.Topic,
+		"title":    msg.Title,
+		"message":  msg.Markdown(),
+		"markdown": true,
+		"priority": priority,
+	}
+	if len(tags) > 0 {
+		payload["tags"] = tags
+	}
+	if msg.URL != "" {
+		payload["click"] = msg.URL
+	}
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal ntfy payload: %w", err)
+	}
+
+	headers := map[string]string{"Content-Type": "application/json"}
+	switch {
+	case ntfyConfig.AccessToken != "":
+		headers["Authorization"] = "Bearer " + decryptSecret(ntfyConfig.AccessToken)
+	case ntfyConfig.Username != "":
+		credentials := ntfyConfig.Username + ":" + decryptSecret(ntfyConfig.Password)
+		headers["Authorization"] = "Basic " + base64.StdEncoding.EncodeToString([]byte(credentials))
+	}
+
+	return s.postNotification(ctx, http.MethodPost, serverURL+"/", headers, payloadBytes)
+}
+
+func (s *NotificationService) sendGotifyMessage(ctx context.Context, msg notificationMessage, config models.JSON) error {
+	var gotifyConfig models.GotifyConfig
+	if err := decodeProviderConfig(config, &gotifyConfig, "Gotify"); err != nil {
+		return err
+	}
+	if gotifyConfig.ServerURL == "" || gotifyConfig.AppToken == "" {
+		return fmt.Errorf("gotify server URL or application token not configured")
+	}
+
+	serverURL := strings.TrimRight(gotifyConfig.ServerURL, "/")
+	if _, err := validateProviderURL(serverURL, false); err != nil {
+		return fmt.Errorf("invalid Gotify server URL: %w", err)
+	}
+
+	priority := gotifyConfig.Priority
+	if priority == 0 {
+		priority = 5
+		if msg.Severity == notificationSeverityError {
+			priority = 8
+		}
+	}
+
+	extras := map[string]any{
+		"client::display": map[string]any{"contentType": "text/markdown"},
+	}
+	if msg.URL != "" {
+		extras["client::notification"] = map[string]any{"click": map[string]any{"url": msg.URL}}
+	}
+	payloadBytes, err := json.Marshal(map[string]any{
+		"title":    msg.Title,
+		"message":  msg.Markdown(),
+		"priority": priority,
+		"extras":   extras,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal Gotify payload: %w", err)
+	}
+
+	headers := map[string]string{
+		"Content-Type": "application/json",
+		"X-Gotify-Key": decryptSecret(gotifyConfig.AppToken),
+	}
+	return s.postNotification(ctx, http.MethodPost, serverURL+"/message", headers, payloadBytes)
+}
+
+// sendDiscordMessage renders msg as a single Discord embed. The event-specific senders
+// above it build richer embeds; this one serves event subscriptions.
+func (s *NotificationService) sendDiscordMessage(ctx context.Context, msg notificationMessage, config models.JSON) error {
+	var discordConfig models.DiscordConfig
+	if err := decodeProviderConfig(config, &discordConfig, "Discord"); err != nil {
+		return err
+	}
+	if discordConfig.WebhookURL == "" {
+		return fmt.Errorf("discord webhook URL not configured")
+	}
+
+	webhookURL := decryptSecret(discordConfig.WebhookURL)
+	if err := validateWebhookURL(webhookURL); err != nil {
+		return fmt.Errorf("invalid webhook URL: %w", err)
+	}
+
+	username := discordConfig.Username
+	if username == "" {
+		username = "Arcane"
+	}
+
+	color := 3447003 // Blue
+	switch msg.Severity {
+	case notificationSeveritySuccess:
+		color = 5025616 // Green
+	case notificationSeverityWarning:
+		color = 16753920 // Orange
+	case notificationSeverityError:
+		color = 15158332 // Red
+	case notificationSeverityInfo:
+	}
+
+	fields := make([]map[string]any, 0, len(msg.Fields))
+	for _, f := range msg.Fields {
+		fields = append(fields, map[string]any{"name": f.Name, "value": truncateText(f.Value, discordFieldLimit), "inline": true})
+	}
+	embed := map[string]any{
+		"title":       truncateText(msg.Title, 256),
+		"description": msg.Message,
+		"color":       color,
+		"fields":      fields,
+		"timestamp":   msg.Timestamp.Format(time.RFC3339),
+	}
+	if msg.URL != "" {
+		embed["url"] = msg.URL
+	}
+
+	payload := map[string]any{
+		"username": username,
+		"embeds":   []map[string]any{embed},
+	}
+	if discordConfig.AvatarURL != "" {
+		payload["avatar_url"] = discordConfig.AvatarURL
+	}
+
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal Discord payload: %w", err)
+	}
+	return s.postNotification(ctx, http.MethodPost, webhookURL, map[string]string{"Content-Type": "application/json"}, payloadBytes)
+}
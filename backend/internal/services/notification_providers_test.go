@@ -0,0 +1,158 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ofkm/arcane-backend/internal/models"
+)
+
+type capturedRequest struct {
+	method string
+	path   string
+	header http.Header
+	body   []byte
+}
+
+func newCaptureServer(t *testing.T) (*httptest.Server, <-chan capturedRequest) {
+	t.Helper()
+	requests := make(chan capturedRequest, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests <- capturedRequest{method: r.Method, path: r.URL.Path, header: r.Header.Clone(), body: body}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+	return server, requests
+}
+
+func setupNotificationService(t *testing.T) *NotificationService {
+	t.Helper()
+	db := setupSettingsTestDB(t)
+	require.NoError(t, db.AutoMigrate(&models.NotificationSettings{}, &models.NotificationLog{}, &models.AppriseSettings{}))
+	return NewNotificationService(db, nil, nil)
+}
+
+func TestNotificationService_WebhookTemplateAndHeaders(t *testing.T) {
+	ctx := context.Background()
+	svc := setupNotificationService(t)
+	server, requests := newCaptureServer(t)
+
+	_, err := svc.CreateOrUpdateSettings(ctx, models.NotificationProviderWebhook, true, models.JSON{
+		"url":          server.URL + "/hook",
+		"method":       "put",
+		"headers":      map[string]any{"X-Token": "secret"},
+		"bodyTemplate": `{"text": {{json .Title}}, "container": {{json (index .Data "containerName")}}}`,
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, svc.SendContainerUpdateNotification(ctx, "web", "nginx:latest", "sha256:old", "sha256:new"))
+
+	req := <-requests
+	require.Equal(t, http.MethodPut, req.method)
+	require.Equal(t, "/hook", req.path)
+	require.Equal(t, "secret", req.header.Get("X-Token"))
+	require.Equal(t, "application/json", req.header.Get("Content-Type"))
+	require.JSONEq(t, `{"text": "Container Updated: web", "container": "web"}`, string(req.body))
+
+	var logs []models.NotificationLog
+	require.NoError(t, svc.db.Find(&logs).Error)
+	require.Len(t, logs, 1)
+	require.Equal(t, models.NotificationProviderWebhook, logs[0].Provider)
+	require.Equal(t, "success", logs[0].Status)
+}
+
+func TestNotificationService_WebhookDefaultBodyRespectsEvents(t *testing.T) {
+	ctx := context.Background()
+	svc := setupNotificationService(t)
+	server, requests := newCaptureServer(t)
+
+	_, err := svc.CreateOrUpdateSettings(ctx, models.NotificationProviderWebhook, true, models.JSON{
+		"url":    server.URL,
+		"events": map[string]any{string(models.NotificationEventContainerUpdate): false},
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, svc.SendContainerUpdateNotification(ctx, "web", "nginx:latest", "", ""))
+	require.NoError(t, svc.SendAlertNotification(ctx, AlertNotification{
+		RuleName: "CPU", RuleType: models.AlertRuleCPUHigh, Resource: "host", Status: models.AlertStatusFiring, Message: "CPU is high",
+	}))
+
+	req := <-requests
+	var body map[string]any
+	require.NoError(t, json.Unmarshal(req.body, &body))
+	require.Equal(t, string(models.NotificationEventAlert), body["event"])
+	require.Equal(t, "Alert Firing: CPU", body["title"])
+	require.Equal(t, "error", body["severity"])
+	require.Empty(t, requests)
+}
+
+func TestNotificationService_NtfyAndGotifyPayloads(t *testing.T) {
+	ctx := context.Background()
+	svc := setupNotificationService(t)
+	server, requests := newCaptureServer(t)
+	msg := containerRollbackMessage("web", "nginx:latest", "sha256:bad", "", "health check failed", false)
+
+	require.NoError(t, svc.sendNtfyMessage(ctx, msg, models.JSON{
+		"serverUrl":   server.URL,
+		"topic":       "arcane",
+		"accessToken": "tk_abc",
+	}))
+	req := <-requests
+	require.Equal(t, "/", req.path)
+	require.Equal(t, "Bearer tk_abc", req.header.Get("Authorization"))
+	var ntfy map[string]any
+	require.NoError(t, json.Unmarshal(req.body, &ntfy))
+	require.Equal(t, "arcane", ntfy["topic"])
+	require.Equal(t, "Container Update Failed: web", ntfy["title"])
+	require.EqualValues(t, 4, ntfy["priority"])
+	require.Contains(t, ntfy["message"], "**Reason:** health check failed")
+
+	require.NoError(t, svc.sendGotifyMessage(ctx, msg, models.JSON{
+		"serverUrl": server.URL + "/",
+		"appToken":  "app-token",
+		"priority":  3,
+	}))
+	req = <-requests
+	require.Equal(t, "/message", req.path)
+	require.Equal(t, "app-token", req.header.Get("X-Gotify-Key"))
+	var gotify map[string]any
+	require.NoError(t, json.Unmarshal(req.body, &gotify))
+	require.EqualValues(t, 3, gotify["priority"])
+
+	require.Error(t, svc.sendNtfyMessage(ctx, msg, models.JSON{"serverUrl": server.URL}))
+	require.Error(t, svc.sendGotifyMessage(ctx, msg, models.JSON{"serverUrl": "ftp://example.com", "appToken": "x"}))
+}
+
+func TestNotificationService_SlackAndTeamsPayloads(t *testing.T) {
+	ctx := context.Background()
+	svc := setupNotificationService(t)
+
+	err := svc.sendSlackMessage(ctx, testMessage(), models.JSON{"webhookUrl": "https://example.com/services/x"})
+	require.ErrorContains(t, err, "Slack incoming webhook")
+
+	msg := testMessage()
+	for range 12 {
+		msg.Fields = append(msg.Fields, notificationField{Name: "k", Value: "v"})
+	}
+	msg.URL = "https://example.com/release"
+	payload := slackPayload(msg, models.SlackConfig{Channel: "#ops"})
+	blocks := payload["blocks"].([]map[string]any)
+	// header, message, two field sections (13 fields), actions, context
+	require.Len(t, blocks, 6)
+	require.Len(t, blocks[2]["fields"], slackSectionFieldLimit)
+	require.Equal(t, "#ops", payload["channel"])
+	require.Equal(t, msg.Title, payload["text"])
+
+	card := teamsPayload(alertMessage(AlertNotification{RuleName: "Disk", Status: models.AlertStatusResolved}))
+	content := card["attachments"].([]map[string]any)[0]["content"].(map[string]any)
+	title := content["body"].([]map[string]any)[0]
+	require.Equal(t, "Good", title["color"])
+	require.Equal(t, "Alert Resolved: Disk", title["text"])
+}
@@ -27,16 +27,22 @@ type NotificationService struct {
 	db             *database.DB
 	config         *config.Config
 	appriseService *AppriseService
+	audit          *AuditService
 }
 
-func NewNotificationService(db *database.DB, cfg *config.Config) *NotificationService {
+func NewNotificationService(db *database.DB, cfg *config.Config, audit *AuditService) *NotificationService {
 	return &NotificationService{
 		db:             db,
 		config:         cfg,
-		appriseService: NewAppriseService(db, cfg),
+		appriseService: NewAppriseService(db, cfg, audit),
+		audit:          audit,
 	}
 }
 
+// Generic webhook URLs and headers routinely carry credentials outside the field names
+// the audit log redacts on its own.
+var auditNotificationSensitive = []string{"config.headers", "config.url"}
+
 func (s *NotificationService) GetAllSettings(ctx context.Context) ([]models.NotificationSettings, error) {
 	var settings []models.NotificationSettings
 	if err := s.db.WithContext(ctx).Find(&settings).Error; err != nil {
@@ -60,6 +66,9 @@ func (s *NotificationService) CreateOrUpdateSettings(ctx context.Context, provid
 	if !enabled {
 		config = models.JSON{}
 	}
+	if err := validateDeliveryConfig(config); err != nil {
+		return nil, err
+	}
 
 	err := s.db.WithContext(ctx).Where("provider = ?", provider).First(&setting).Error
 	if err != nil {
@@ -71,29 +80,63 @@ func (s *NotificationService) CreateOrUpdateSettings(ctx context.Context, provid
 		if err := s.db.WithContext(ctx).Create(&setting).Error; err != nil {
 			return nil, fmt.Errorf("failed to create notification settings: %w", err)
 		}
+
+		s.audit.Record(ctx, AuditChange{
+			Action:     auditActionCreate,
+			TargetType: auditTargetNotification,
+			TargetID:   string(provider),
+			TargetName: string(provider),
+			After:      &setting,
+			Sensitive:  auditNotificationSensitive,
+		})
 	} else {
+		before := setting
 		setting.Enabled = enabled
 		setting.Config = config
 		if err := s.db.WithContext(ctx).Save(&setting).Error; err != nil {
 			return nil, fmt.Errorf("failed to update notification settings: %w", err)
 		}
+
+		s.audit.Record(ctx, AuditChange{
+			Action:     auditActionUpdate,
+			TargetType: auditTargetNotification,
+			TargetID:   string(provider),
+			TargetName: string(provider),
+			Before:     &before,
+			After:      &setting,
+			Sensitive:  auditNotificationSensitive,
+		})
 	}
 
 	return &setting, nil
 }
 
 func (s *NotificationService) DeleteSettings(ctx context.Context, provider models.NotificationProvider) error {
+	var before models.NotificationSettings
+	found := s.db.WithContext(ctx).Where("provider = ?", provider).First(&before).Error == nil
+
 	if err := s.db.WithContext(ctx).Where("provider = ?", provider).Delete(&models.NotificationSettings{}).Error; err != nil {
 		return fmt.Errorf("failed to delete notification settings: %w", err)
 	}
+
+	if found {
+		s.audit.Record(ctx, AuditChange{
+			Action:     auditActionDelete,
+			TargetType: auditTargetNotification,
+			TargetID:   string(provider),
+			TargetName: string(provider),
+			Before:     &before,
+			Sensitive:  auditNotificationSensitive,
+		})
+	}
 	return nil
 }
 
 func (s *NotificationService) SendImageUpdateNotification(ctx context.Context, imageRef string, updateInfo *dto.ImageUpdateResponse, eventType models.NotificationEventType) error {
 	// Send to Apprise if enabled (don't block on error)
-	if appriseErr := s.appriseService.SendImageUpdateNotification(ctx, imageRef, updateInfo); appriseErr != nil {
-		slog.WarnContext(ctx, "Failed to send Apprise notification", "error", appriseErr)
-	}
+	s.sendApprise(ctx, func() error {
+		return s.appriseService.SendImageUpdateNotification(ctx, imageRef, updateInfo)
+	}, imageUpdateMessage(imageRef, updateInfo, eventType))
 
 	settings, err := s.GetAllSettings(ctx)
 	if err != nil {
@@ -110,6 +153,9 @@ func (s *NotificationService) SendImageUpdateNotification(ctx context.Context, i
 		if !s.isEventEnabled(setting.Config, eventType) {
 			continue
 		}
+		if s.deferNotification(ctx, setting, imageUpdateMessage(imageRef, updateInfo, eventType)) {
+			continue
+		}
 
 		var sendErr error
 		switch setting.Provider {
@@ -117,6 +163,9 @@ func (s *NotificationService) SendImageUpdateNotification(ctx context.Context, i
 			sendErr = s.sendDiscordNotification(ctx, imageRef, updateInfo, setting.Config)
 		case models.NotificationProviderEmail:
 			sendErr = s.sendEmailNotification(ctx, imageRef, updateInfo, setting.Config)
+		case models.NotificationProviderWebhook, models.NotificationProviderSlack, models.NotificationProviderTeams,
+			models.NotificationProviderNtfy, models.NotificationProviderGotify:
+			sendErr = s.sendProviderMessage(ctx, setting.Provider, imageUpdateMessage(imageRef, updateInfo, eventType), setting.Config)
 		default:
 			slog.WarnContext(ctx, "Unknown notification provider", "provider", setting.Provider)
 			continue
@@ -174,9 +223,9 @@ func (s *NotificationService) isEventEnabled(config models.JSON, eventType model
 
 func (s *NotificationService) SendContainerUpdateNotification(ctx context.Context, containerName, imageRef, oldDigest, newDigest string) error {
 	// Send to Apprise if enabled (don't block on error)
-	if appriseErr := s.appriseService.SendContainerUpdateNotification(ctx, containerName, imageRef, oldDigest, newDigest); appriseErr != nil {
-		slog.WarnContext(ctx, "Failed to send Apprise notification", "error", appriseErr)
-	}
+	s.sendApprise(ctx, func() error {
+		return s.appriseService.SendContainerUpdateNotification(ctx, containerName, imageRef, oldDigest, newDigest)
+	}, containerUpdateMessage(containerName, imageRef, oldDigest, newDigest))
 
 	settings, err := s.GetAllSettings(ctx)
 	if err != nil {
@@ -193,6 +242,9 @@ func (s *NotificationService) SendContainerUpdateNotification(ctx context.Contex
 		if !s.isEventEnabled(setting.Config, models.NotificationEventContainerUpdate) {
 			continue
 		}
+		if s.deferNotification(ctx, setting, containerUpdateMessage(containerName, imageRef, oldDigest, newDigest)) {
+			continue
+		}
 
 		var sendErr error
 		switch setting.Provider {
@@ -200,6 +252,9 @@ func (s *NotificationService) SendContainerUpdateNotification(ctx context.Contex
 			sendErr = s.sendDiscordContainerUpdateNotification(ctx, containerName, imageRef, oldDigest, newDigest, setting.Config)
 		case models.NotificationProviderEmail:
 			sendErr = s.sendEmailContainerUpdateNotification(ctx, containerName, imageRef, oldDigest, newDigest, setting.Config)
+		case models.NotificationProviderWebhook, models.NotificationProviderSlack, models.NotificationProviderTeams,
+			models.NotificationProviderNtfy, models.NotificationProviderGotify:
+			sendErr = s.sendProviderMessage(ctx, setting.Provider, containerUpdateMessage(containerName, imageRef, oldDigest, newDigest), setting.Config)
 		default:
 			slog.WarnContext(ctx, "Unknown notification provider", "provider", setting.Provider)
 			continue
@@ -229,6 +284,69 @@ func (s *NotificationService) SendContainerUpdateNotification(ctx context.Contex
 	return nil
 }
 
+// SendContainerRollbackNotification reports an automatic update whose container failed its
+// health check. rolledBack tells whether the previous image could be restored.
+func (s *NotificationService) SendContainerRollbackNotification(ctx context.Context, containerName, imageRef, failedDigest, restoredDigest, reason string, rolledBack bool) error {
+	s.sendApprise(ctx, func() error {
+		return s.appriseService.SendContainerRollbackNotification(ctx, containerName, imageRef, failedDigest, restoredDigest, reason, rolledBack)
+	}, containerRollbackMessage(containerName, imageRef, failedDigest, restoredDigest, reason, rolledBack))
+
+	settings, err := s.GetAllSettings(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get notification settings: %w", err)
+	}
+
+	var errors []string
+	for _, setting := range settings {
+		if !setting.Enabled {
+			continue
+		}
+		if !s.isEventEnabled(setting.Config, models.NotificationEventContainerRollback) {
+			continue
+		}
+		if s.deferNotification(ctx, setting, containerRollbackMessage(containerName, imageRef, failedDigest, restoredDigest, reason, rolledBack)) {
+			continue
+		}
+
+		var sendErr error
+		switch setting.Provider {
+		case models.NotificationProviderDiscord:
+			sendErr = s.sendDiscordContainerRollbackNotification(ctx, containerName, imageRef, failedDigest, restoredDigest, reason, rolledBack, setting.Config)
+		case models.NotificationProviderEmail:
+			sendErr = s.sendEmailContainerRollbackNotification(ctx, containerName, imageRef, failedDigest, restoredDigest, reason, rolledBack, setting.Config)
+		case models.NotificationProviderWebhook, models.NotificationProviderSlack, models.NotificationProviderTeams,
+			models.NotificationProviderNtfy, models.NotificationProviderGotify:
+			sendErr = s.sendProviderMessage(ctx, setting.Provider, containerRollbackMessage(containerName, imageRef, failedDigest, restoredDigest, reason, rolledBack), setting.Config)
+		default:
+			slog.WarnContext(ctx, "Unknown notification provider", "provider", setting.Provider)
+			continue
+		}
+
+		status := "success"
+		var errMsg *string
+		if sendErr != nil {
+			status = "failed"
+			msg := sendErr.Error()
+			errMsg = &msg
+			errors = append(errors, fmt.Sprintf("%s: %s", setting.Provider, msg))
+		}
+
+		s.logNotification(ctx, setting.Provider, imageRef, status, errMsg, models.JSON{
+			"containerName":  containerName,
+			"failedDigest":   failedDigest,
+			"restoredDigest": restoredDigest,
+			"rolledBack":     rolledBack,
+			"eventType":      string(models.NotificationEventContainerRollback),
+		})
+	}
+
+	if len(errors) > 0 {
+		return fmt.Errorf("notification errors: %s", strings.Join(errors, "; "))
+	}
+
+	return nil
+}
+
 func (s *NotificationService) sendDiscordNotification(ctx context.Context, imageRef string, updateInfo *dto.ImageUpdateResponse, config models.JSON) error {
 	var discordConfig models.DiscordConfig
 	configBytes, err := json.Marshal(config)
@@ -296,18 +414,42 @@ func (s *NotificationService) sendDiscordNotification(ctx context.Context, image
 			"inline": true,
 		})
 	}
+	if version := updateVersion(updateInfo); version != "" {
+		fields = append(fields, map[string]interface{}{
+			"name":   "New Version",
+			"value":  version,
+			"inline": true,
+		})
+	}
+
+	embed := map[string]interface{}{
+		"title":       "🔔 Container Image Update Available",
+		"description": fmt.Sprintf("A new update has been detected for **%s**", imageRef),
+		"color":       color,
+		"timestamp":   time.Now().Format(time.RFC3339),
+	}
+	if rel := updateInfo.Release; rel != nil {
+		if rel.Notes != "" {
+			fields = append(fields, map[string]interface{}{
+				"name":   "Release Notes",
+				"value":  truncateText(rel.Notes, discordFieldLimit),
+				"inline": false,
+			})
+		}
+		if link := releaseLink(rel); link != "" {
+			embed["url"] = link
+			fields = append(fields, map[string]interface{}{
+				"name":   "Changelog",
+				"value":  link,
+				"inline": false,
+			})
+		}
+	}
+	embed["fields"] = fields
 
 	payload := map[string]interface{}{
 		"username": username,
-		"embeds": []map[string]interface{}{
-			{
-				"title":       "🔔 Container Image Update Available",
-				"description": fmt.Sprintf("A new update has been detected for **%s**", imageRef),
-				"color":       color,
-				"fields":      fields,
-				"timestamp":   time.Now().Format(time.RFC3339),
-			},
-		},
+		"embeds":   []map[string]interface{}{embed},
 	}
 
 	if discordConfig.AvatarURL != "" {
@@ -402,6 +544,12 @@ func (s *NotificationService) renderEmailTemplate(imageRef string, updateInfo *d
 		"CurrentDigest": truncateDigest(updateInfo.CurrentDigest),
 		"LatestDigest":  truncateDigest(updateInfo.LatestDigest),
 		"CheckTime":     updateInfo.CheckTime.Format(time.RFC1123),
+		"NewVersion":    updateVersion(updateInfo),
+	}
+	if rel := updateInfo.Release; rel != nil {
+		data["ReleaseTitle"] = rel.Title
+		data["ReleaseNotes"] = rel.Notes
+		data["ReleaseURL"] = releaseLink(rel)
 	}
 
 	htmlContent, err := resources.FS.ReadFile("email-templates/image-update_html.tmpl")
@@ -638,35 +786,100 @@ func (s *NotificationService) renderContainerUpdateEmailTemplate(containerName,
 	return htmlBuf.String(), textBuf.String(), nil
 }
 
-func (s *NotificationService) TestNotification(ctx context.Context, provider models.NotificationProvider, testType string) error {
-	setting, err := s.GetSettingsByProvider(ctx, provider)
+func (s *NotificationService) sendDiscordContainerRollbackNotification(ctx context.Context, containerName, imageRef, failedDigest, restoredDigest, reason string, rolledBack bool, config models.JSON) error {
+	var discordConfig models.DiscordConfig
+	configBytes, err := json.Marshal(config)
 	if err != nil {
-		return fmt.Errorf("please save your %s settings before testing", provider)
+		return fmt.Errorf("failed to marshal Discord config: %w", err)
+	}
+	if err := json.Unmarshal(configBytes, &discordConfig); err != nil {
+		return fmt.Errorf("failed to unmarshal Discord config: %w", err)
 	}
 
-	testUpdate := &dto.ImageUpdateResponse{
-		HasUpdate:      true,
-		UpdateType:     "digest",
-		CurrentDigest:  "sha256:abc123def456789012345678901234567890",
-		LatestDigest:   "sha256:xyz789ghi012345678901234567890123456",
-		CheckTime:      time.Now(),
-		ResponseTimeMs: 100,
+	if discordConfig.WebhookURL == "" {
+		return fmt.Errorf("discord webhook URL not configured")
 	}
 
-	switch provider {
-	case models.NotificationProviderDiscord:
-		return s.sendDiscordNotification(ctx, "test/image:latest", testUpdate, setting.Config)
-	case models.NotificationProviderEmail:
-		if testType == "image-update" {
-			return s.sendEmailNotification(ctx, "nginx:latest", testUpdate, setting.Config)
-		}
-		return s.sendTestEmail(ctx, setting.Config)
-	default:
-		return fmt.Errorf("unknown provider: %s", provider)
+	webhookURL := discordConfig.WebhookURL
+	if decrypted, err := utils.Decrypt(webhookURL); err == nil {
+		webhookURL = decrypted
+	}
+
+	if err := validateWebhookURL(webhookURL); err != nil {
+		return fmt.Errorf("invalid webhook URL: %w", err)
+	}
+
+	username := discordConfig.Username
+	if username == "" {
+		username = "Arcane"
+	}
+
+	title := "Container Update Rolled Back"
+	status := "↩️ Rolled back to previous image"
+	color := 16753920 // Orange: the update failed but the service was restored
+	if !rolledBack {
+		title = "Container Update Failed"
+		status = "❌ Rollback failed, manual action required"
+		color = 15158332 // Red
+	}
+
+	fields := []map[string]interface{}{
+		{"name": "Container", "value": containerName, "inline": false},
+		{"name": "Image", "value": imageRef, "inline": false},
+		{"name": "Status", "value": status, "inline": false},
+		{"name": "Reason", "value": reason, "inline": false},
+	}
+	if failedDigest != "" {
+		fields = append(fields, map[string]interface{}{"name": "Failed Version", "value": truncateDigest(failedDigest), "inline": true})
+	}
+	if restoredDigest != "" {
+		fields = append(fields, map[string]interface{}{"name": "Restored Version", "value": truncateDigest(restoredDigest), "inline": true})
+	}
+
+	embed := map[string]interface{}{
+		"title":       title,
+		"description": "The updated container did not pass its health check.",
+		"color":       color,
+		"fields":      fields,
+		"timestamp":   time.Now().Format(time.RFC3339),
+	}
+
+	payload := map[string]interface{}{
+		"username": username,
+		"embeds":   []map[string]interface{}{embed},
+	}
+
+	if discordConfig.AvatarURL != "" {
+		payload["avatar_url"] = discordConfig.AvatarURL
+	}
+
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal Discord payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewBuffer(payloadBytes))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("webhook returned status %d: %s", resp.StatusCode, string(bodyBytes))
 	}
+
+	return nil
 }
 
-func (s *NotificationService) sendTestEmail(ctx context.Context, config models.JSON) error {
+func (s *NotificationService) sendEmailContainerRollbackNotification(ctx context.Context, containerName, imageRef, failedDigest, restoredDigest, reason string, rolledBack bool, config models.JSON) error {
 	var emailConfig models.EmailConfig
 	configBytes, err := json.Marshal(config)
 	if err != nil {
@@ -698,12 +911,15 @@ func (s *NotificationService) sendTestEmail(ctx context.Context, config models.J
 		}
 	}
 
-	htmlBody, textBody, err := s.renderTestEmailTemplate()
+	htmlBody, textBody, err := s.renderContainerRollbackEmailTemplate(containerName, imageRef, failedDigest, restoredDigest, reason, rolledBack)
 	if err != nil {
-		return fmt.Errorf("failed to render test email template: %w", err)
+		return fmt.Errorf("failed to render email template: %w", err)
 	}
 
-	subject := "Test Email from Arcane"
+	subject := fmt.Sprintf("Container Rolled Back: %s", notifications.SanitizeForEmail(containerName))
+	if !rolledBack {
+		subject = fmt.Sprintf("Container Update Failed: %s", notifications.SanitizeForEmail(containerName))
+	}
 	message := notifications.BuildMultipartMessage(emailConfig.FromAddress, emailConfig.ToAddresses, subject, htmlBody, textBody)
 
 	client, err := notifications.ConnectSMTP(ctx, emailConfig)
@@ -719,13 +935,32 @@ func (s *NotificationService) sendTestEmail(ctx context.Context, config models.J
 	return nil
 }
 
-func (s *NotificationService) renderTestEmailTemplate() (string, string, error) {
-	data := map[string]interface{}{
-		"LogoURL": "https://raw.githubusercontent.com/getarcaneapp/arcane/main/backend/resources/images/logo-full.svg",
-		"AppURL":  s.config.AppUrl,
+func (s *NotificationService) renderContainerRollbackEmailTemplate(containerName, imageRef, failedDigest, restoredDigest, reason string, rolledBack bool) (string, string, error) {
+	title := "Container Update Rolled Back"
+	status := "↩ Rolled back to previous image"
+	footer := "The container has been recreated from its previous image."
+	if !rolledBack {
+		title = "Container Update Failed"
+		status = "✗ Rollback failed"
+		footer = "The previous image could not be restored. Please check the container manually."
 	}
 
-	htmlContent, err := resources.FS.ReadFile("email-templates/test_html.tmpl")
+	data := map[string]interface{}{
+		"LogoURL":        "https://raw.githubusercontent.com/getarcaneapp/arcane/main/backend/resources/images/logo-full.svg",
+		"AppURL":         s.config.AppUrl,
+		"Title":          title,
+		"TitleUpper":     strings.ToUpper(title),
+		"ContainerName":  containerName,
+		"ImageRef":       imageRef,
+		"Status":         status,
+		"Reason":         reason,
+		"FailedDigest":   truncateDigest(failedDigest),
+		"RestoredDigest": truncateDigest(restoredDigest),
+		"EventTime":      time.Now().Format(time.RFC1123),
+		"Footer":         footer,
+	}
+
+	htmlContent, err := resources.FS.ReadFile("email-templates/container-rollback_html.tmpl")
 	if err != nil {
 		return "", "", fmt.Errorf("failed to read HTML template: %w", err)
 	}
@@ -740,7 +975,7 @@ func (s *NotificationService) renderTestEmailTemplate() (string, string, error)
 		return "", "", fmt.Errorf("failed to execute HTML template: %w", err)
 	}
 
-	textContent, err := resources.FS.ReadFile("email-templates/test_text.tmpl")
+	textContent, err := resources.FS.ReadFile("email-templates/container-rollback_text.tmpl")
 	if err != nil {
 		return "", "", fmt.Errorf("failed to read text template: %w", err)
 	}
@@ -758,43 +993,11 @@ func (s *NotificationService) renderTestEmailTemplate() (string, string, error)
 	return htmlBuf.String(), textBuf.String(), nil
 }
 
-func (s *NotificationService) logNotification(ctx context.Context, provider models.NotificationProvider, imageRef, status string, errMsg *string, metadata models.JSON) {
-	log := &models.NotificationLog{
-		Provider: provider,
-		ImageRef: imageRef,
-		Status:   status,
-		Error:    errMsg,
-		Metadata: metadata,
-		SentAt:   time.Now(),
-	}
-
-	if err := s.db.WithContext(ctx).Create(log).Error; err != nil {
-		slog.WarnContext(ctx, "Failed to log notification",
-			slog.String("provider", string(provider)),
-			slog.String("error", err.Error()))
-	}
-}
-
-func (s *NotificationService) SendBatchImageUpdateNotification(ctx context.Context, updates map[string]*dto.ImageUpdateResponse) error {
-	if len(updates) == 0 {
-		return nil
-	}
-
-	updatesWithChanges := make(map[string]*dto.ImageUpdateResponse)
-	for imageRef, update := range updates {
-		if update != nil && update.HasUpdate {
-			updatesWithChanges[imageRef] = update
-		}
-	}
-
-	if len(updatesWithChanges) == 0 {
-		return nil
-	}
-
-	// Send to Apprise if enabled
-	if appriseErr := s.appriseService.SendBatchImageUpdateNotification(ctx, updatesWithChanges); appriseErr != nil {
-		slog.WarnContext(ctx, "Failed to send Apprise notification", "error", appriseErr)
-	}
+// SendAlertNotification delivers a firing or resolved alert to every enabled provider.
+func (s *NotificationService) SendAlertNotification(ctx context.Context, alert AlertNotification) error {
+	s.sendApprise(ctx, func() error {
+		return s.appriseService.SendAlertNotification(ctx, alert)
+	}, alertMessage(alert))
 
 	settings, err := s.GetAllSettings(ctx)
 	if err != nil {
@@ -806,17 +1009,22 @@ func (s *NotificationService) SendBatchImageUpdateNotification(ctx context.Conte
 		if !setting.Enabled {
 			continue
 		}
-
-		if !s.isEventEnabled(setting.Config, models.NotificationEventImageUpdate) {
+		if !s.isEventEnabled(setting.Config, models.NotificationEventAlert) {
+			continue
+		}
+		if s.deferNotification(ctx, setting, alertMessage(alert)) {
 			continue
 		}
 
 		var sendErr error
 		switch setting.Provider {
 		case models.NotificationProviderDiscord:
-			sendErr = s.sendBatchDiscordNotification(ctx, updatesWithChanges, setting.Config)
+			sendErr = s.sendDiscordAlertNotification(ctx, alert, setting.Config)
 		case models.NotificationProviderEmail:
-			sendErr = s.sendBatchEmailNotification(ctx, updatesWithChanges, setting.Config)
+			sendErr = s.sendEmailAlertNotification(ctx, alert, setting.Config)
+		case models.NotificationProviderWebhook, models.NotificationProviderSlack, models.NotificationProviderTeams,
+			models.NotificationProviderNtfy, models.NotificationProviderGotify:
+			sendErr = s.sendProviderMessage(ctx, setting.Provider, alertMessage(alert), setting.Config)
 		default:
 			slog.WarnContext(ctx, "Unknown notification provider", "provider", setting.Provider)
 			continue
@@ -831,15 +1039,11 @@ func (s *NotificationService) SendBatchImageUpdateNotification(ctx context.Conte
 			errors = append(errors, fmt.Sprintf("%s: %s", setting.Provider, msg))
 		}
 
-		imageRefs := make([]string, 0, len(updatesWithChanges))
-		for ref := range updatesWithChanges {
-			imageRefs = append(imageRefs, ref)
-		}
-
-		s.logNotification(ctx, setting.Provider, strings.Join(imageRefs, ", "), status, errMsg, models.JSON{
-			"updateCount": len(updatesWithChanges),
-			"eventType":   string(models.NotificationEventImageUpdate),
-			"batch":       true,
+		s.logNotification(ctx, setting.Provider, alert.Resource, status, errMsg, models.JSON{
+			"ruleName":    alert.RuleName,
+			"ruleType":    string(alert.RuleType),
+			"alertStatus": string(alert.Status),
+			"eventType":   string(models.NotificationEventAlert),
 		})
 	}
 
@@ -850,37 +1054,760 @@ func (s *NotificationService) SendBatchImageUpdateNotification(ctx context.Conte
 	return nil
 }
 
-func (s *NotificationService) sendBatchDiscordNotification(ctx context.Context, updates map[string]*dto.ImageUpdateResponse, config models.JSON) error {
+func alertTitle(alert AlertNotification) string {
+	if alert.Status == models.AlertStatusResolved {
+		return fmt.Sprintf("Alert Resolved: %s", alert.RuleName)
+	}
+	return fmt.Sprintf("Alert Firing: %s", alert.RuleName)
+}
+
+func (s *NotificationService) sendDiscordAlertNotification(ctx context.Context, alert AlertNotification, config models.JSON) error {
 	var discordConfig models.DiscordConfig
 	configBytes, err := json.Marshal(config)
 	if err != nil {
-		return fmt.Errorf("failed to marshal discord config: %w", err)
+		return fmt.Errorf("failed to marshal Discord config: %w", err)
 	}
 	if err := json.Unmarshal(configBytes, &discordConfig); err != nil {
-		return fmt.Errorf("failed to unmarshal discord config: %w", err)
+		return fmt.Errorf("failed to unmarshal Discord config: %w", err)
 	}
 
-	if err := validateWebhookURL(discordConfig.WebhookURL); err != nil {
-		return fmt.Errorf("invalid webhook URL: %w", err)
+	if discordConfig.WebhookURL == "" {
+		return fmt.Errorf("discord webhook URL not configured")
 	}
 
-	fields := make([]map[string]interface{}, 0, len(updates))
-	for imageRef, update := range updates {
-		fields = append(fields, map[string]interface{}{
-			"name": imageRef,
-			"value": fmt.Sprintf(
-				"**Type:** %s\n**Current:** `%s`\n**Latest:** `%s`",
-				update.UpdateType,
-				truncateDigest(update.CurrentDigest),
-				truncateDigest(update.LatestDigest),
-			),
-			"inline": false,
-		})
+	webhookURL := discordConfig.WebhookURL
+	if decrypted, err := utils.Decrypt(webhookURL); err == nil {
+		webhookURL = decrypted
 	}
 
-	title := "Container Image Updates Available"
-	description := fmt.Sprintf("%d container image(s) have updates available.", len(updates))
-	if len(updates) == 1 {
+	if err := validateWebhookURL(webhookURL); err != nil {
+		return fmt.Errorf("invalid webhook URL: %w", err)
+	}
+
+	username := discordConfig.Username
+	if username == "" {
+		username = "Arcane"
+	}
+
+	status := "🔥 Firing"
+	color := 15158332 // Red
+	if alert.Status == models.AlertStatusResolved {
+		status = "✅ Resolved"
+		color = 3066993 // Green
+	}
+
+	fields := []map[string]interface{}{
+		{"name": "Resource", "value": alert.Resource, "inline": true},
+		{"name": "Status", "value": status, "inline": true},
+		{"name": "Value", "value": alert.Value, "inline": true},
+		{"name": "Threshold", "value": alert.Threshold, "inline": true},
+	}
+
+	embed := map[string]interface{}{
+		"title":       alertTitle(alert),
+		"description": alert.Message,
+		"color":       color,
+		"fields":      fields,
+		"timestamp":   time.Now().Format(time.RFC3339),
+	}
+
+	payload := map[string]interface{}{
+		"username": username,
+		"embeds":   []map[string]interface{}{embed},
+	}
+
+	if discordConfig.AvatarURL != "" {
+		payload["avatar_url"] = discordConfig.AvatarURL
+	}
+
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal Discord payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewBuffer(payloadBytes))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("webhook returned status %d: %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	return nil
+}
+
+func (s *NotificationService) sendEmailAlertNotification(ctx context.Context, alert AlertNotification, config models.JSON) error {
+	var emailConfig models.EmailConfig
+	configBytes, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("failed to marshal email config: %w", err)
+	}
+	if err := json.Unmarshal(configBytes, &emailConfig); err != nil {
+		return fmt.Errorf("failed to unmarshal email config: %w", err)
+	}
+
+	if emailConfig.SMTPHost == "" || emailConfig.SMTPPort == 0 {
+		return fmt.Errorf("SMTP host or port not configured")
+	}
+	if len(emailConfig.ToAddresses) == 0 {
+		return fmt.Errorf("no recipient email addresses configured")
+	}
+
+	if _, err := mail.ParseAddress(emailConfig.FromAddress); err != nil {
+		return fmt.Errorf("invalid from address: %w", err)
+	}
+	for _, addr := range emailConfig.ToAddresses {
+		if _, err := mail.ParseAddress(addr); err != nil {
+			return fmt.Errorf("invalid to address %s: %w", addr, err)
+		}
+	}
+
+	if emailConfig.SMTPPassword != "" {
+		if decrypted, err := utils.Decrypt(emailConfig.SMTPPassword); err == nil {
+			emailConfig.SMTPPassword = decrypted
+		}
+	}
+
+	htmlBody, textBody, err := s.renderAlertEmailTemplate(alert)
+	if err != nil {
+		return fmt.Errorf("failed to render email template: %w", err)
+	}
+
+	subject := fmt.Sprintf("%s (%s)", alertTitle(alert), notifications.SanitizeForEmail(alert.Resource))
+	message := notifications.BuildMultipartMessage(emailConfig.FromAddress, emailConfig.ToAddresses, subject, htmlBody, textBody)
+
+	client, err := notifications.ConnectSMTP(ctx, emailConfig)
+	if err != nil {
+		return fmt.Errorf("failed to connect to SMTP server: %w", err)
+	}
+	defer client.Close()
+
+	if err := client.SendMessage(emailConfig.FromAddress, emailConfig.ToAddresses, message); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+
+	return nil
+}
+
+func (s *NotificationService) renderAlertEmailTemplate(alert AlertNotification) (string, string, error) {
+	title := alertTitle(alert)
+	status := "● Firing"
+	statusColor := "#f87171"
+	footer := "You will be notified again when the alert resolves."
+	if alert.Status == models.AlertStatusResolved {
+		status = "✓ Resolved"
+		statusColor = "#4ade80"
+		footer = "The condition is no longer met."
+	}
+
+	data := map[string]interface{}{
+		"LogoURL":     "https://raw.githubusercontent.com/getarcaneapp/arcane/main/backend/resources/images/logo-full.svg",
+		"AppURL":      s.config.AppUrl,
+		"Title":       title,
+		"TitleUpper":  strings.ToUpper(title),
+		"RuleName":    alert.RuleName,
+		"Resource":    alert.Resource,
+		"Status":      status,
+		"StatusColor": statusColor,
+		"Message":     alert.Message,
+		"Value":       alert.Value,
+		"Threshold":   alert.Threshold,
+		"EventTime":   time.Now().Format(time.RFC1123),
+		"Footer":      footer,
+	}
+
+	htmlContent, err := resources.FS.ReadFile("email-templates/alert_html.tmpl")
+	if err != nil {
+		return "", "", fmt.Errorf("failed to read HTML template: %w", err)
+	}
+
+	htmlTmpl, err := template.New("html").Parse(string(htmlContent))
+	if err != nil {
+		return "", "", fmt.Errorf("failed to parse HTML template: %w", err)
+	}
+
+	var htmlBuf bytes.Buffer
+	if err := htmlTmpl.ExecuteTemplate(&htmlBuf, "root", data); err != nil {
+		return "", "", fmt.Errorf("failed to execute HTML template: %w", err)
+	}
+
+	textContent, err := resources.FS.ReadFile("email-templates/alert_text.tmpl")
+	if err != nil {
+		return "", "", fmt.Errorf("failed to read text template: %w", err)
+	}
+
+	textTmpl, err := template.New("text").Parse(string(textContent))
+	if err != nil {
+		return "", "", fmt.Errorf("failed to parse text template: %w", err)
+	}
+
+	var textBuf bytes.Buffer
+	if err := textTmpl.ExecuteTemplate(&textBuf, "root", data); err != nil {
+		return "", "", fmt.Errorf("failed to execute text template: %w", err)
+	}
+
+	return htmlBuf.String(), textBuf.String(), nil
+}
+
+// eventEmail carries the values shown in the generic event email sent to subscriptions.
+type eventEmail struct {
+	Event            models.Event
+	EnvironmentName  string
+	SubscriptionName string
+}
+
+func (s *NotificationService) sendEmailEventNotification(ctx context.Context, email eventEmail, config models.JSON) error {
+	var emailConfig models.EmailConfig
+	configBytes, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("failed to marshal email config: %w", err)
+	}
+	if err := json.Unmarshal(configBytes, &emailConfig); err != nil {
+		return fmt.Errorf("failed to unmarshal email config: %w", err)
+	}
+
+	if emailConfig.SMTPHost == "" || emailConfig.SMTPPort == 0 {
+		return fmt.Errorf("SMTP host or port not configured")
+	}
+	if len(emailConfig.ToAddresses) == 0 {
+		return fmt.Errorf("no recipient email addresses configured")
+	}
+
+	if _, err := mail.ParseAddress(emailConfig.FromAddress); err != nil {
+		return fmt.Errorf("invalid from address: %w", err)
+	}
+	for _, addr := range emailConfig.ToAddresses {
+		if _, err := mail.ParseAddress(addr); err != nil {
+			return fmt.Errorf("invalid to address %s: %w", addr, err)
+		}
+	}
+
+	if emailConfig.SMTPPassword != "" {
+		if decrypted, err := utils.Decrypt(emailConfig.SMTPPassword); err == nil {
+			emailConfig.SMTPPassword = decrypted
+		}
+	}
+
+	htmlBody, textBody, err := s.renderEventEmailTemplate(email)
+	if err != nil {
+		return fmt.Errorf("failed to render email template: %w", err)
+	}
+
+	subject := notifications.SanitizeForEmail(email.Event.Title)
+	message := notifications.BuildMultipartMessage(emailConfig.FromAddress, emailConfig.ToAddresses, subject, htmlBody, textBody)
+
+	client, err := notifications.ConnectSMTP(ctx, emailConfig)
+	if err != nil {
+		return fmt.Errorf("failed to connect to SMTP server: %w", err)
+	}
+	defer client.Close()
+
+	if err := client.SendMessage(emailConfig.FromAddress, emailConfig.ToAddresses, message); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+
+	return nil
+}
+
+func (s *NotificationService) renderEventEmailTemplate(email eventEmail) (string, string, error) {
+	event := email.Event
+	severityColor := "#60a5fa"
+	switch event.Severity {
+	case models.EventSeveritySuccess:
+		severityColor = "#4ade80"
+	case models.EventSeverityWarning:
+		severityColor = "#fbbf24"
+	case models.EventSeverityError:
+		severityColor = "#f87171"
+	case models.EventSeverityInfo:
+	}
+
+	valueOrDash := func(v *string) string {
+		if v == nil || *v == "" {
+			return "-"
+		}
+		return *v
+	}
+	environment := email.EnvironmentName
+	if environment == "" {
+		environment = valueOrDash(event.EnvironmentID)
+	}
+	message := event.Description
+	if message == "" {
+		message = event.Title
+	}
+
+	appURL := ""
+	if s.config != nil {
+		appURL = s.config.AppUrl
+	}
+
+	data := map[string]interface{}{
+		"LogoURL":       "https://raw.githubusercontent.com/getarcaneapp/arcane/main/backend/resources/images/logo-full.svg",
+		"AppURL":        appURL,
+		"Title":         event.Title,
+		"TitleUpper":    strings.ToUpper(event.Title),
+		"Message":       message,
+		"EventType":     string(event.Type),
+		"Resource":      valueOrDash(event.ResourceName),
+		"Severity":      string(event.Severity),
+		"SeverityColor": severityColor,
+		"Environment":   environment,
+		"User":          valueOrDash(event.Username),
+		"EventTime":     event.Timestamp.Format(time.RFC1123),
+		"Footer":        fmt.Sprintf("You are receiving this because of the %q notification subscription.", email.SubscriptionName),
+	}
+
+	htmlContent, err := resources.FS.ReadFile("email-templates/event_html.tmpl")
+	if err != nil {
+		return "", "", fmt.Errorf("failed to read HTML template: %w", err)
+	}
+
+	htmlTmpl, err := template.New("html").Parse(string(htmlContent))
+	if err != nil {
+		return "", "", fmt.Errorf("failed to parse HTML template: %w", err)
+	}
+
+	var htmlBuf bytes.Buffer
+	if err := htmlTmpl.ExecuteTemplate(&htmlBuf, "root", data); err != nil {
+		return "", "", fmt.Errorf("failed to execute HTML template: %w", err)
+	}
+
+	textContent, err := resources.FS.ReadFile("email-templates/event_text.tmpl")
+	if err != nil {
+		return "", "", fmt.Errorf("failed to read text template: %w", err)
+	}
+
+	textTmpl, err := template.New("text").Parse(string(textContent))
+	if err != nil {
+		return "", "", fmt.Errorf("failed to parse text template: %w", err)
+	}
+
+	var textBuf bytes.Buffer
+	if err := textTmpl.ExecuteTemplate(&textBuf, "root", data); err != nil {
+		return "", "", fmt.Errorf("failed to execute text template: %w", err)
+	}
+
+	return htmlBuf.String(), textBuf.String(), nil
+}
+
+func (s *NotificationService) sendEmailDigestNotification(ctx context.Context, msgs []notificationMessage, now time.Time, config models.JSON) error {
+	var emailConfig models.EmailConfig
+	configBytes, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("failed to marshal email config: %w", err)
+	}
+	if err := json.Unmarshal(configBytes, &emailConfig); err != nil {
+		return fmt.Errorf("failed to unmarshal email config: %w", err)
+	}
+
+	if emailConfig.SMTPHost == "" || emailConfig.SMTPPort == 0 {
+		return fmt.Errorf("SMTP host or port not configured")
+	}
+	if len(emailConfig.ToAddresses) == 0 {
+		return fmt.Errorf("no recipient email addresses configured")
+	}
+
+	if _, err := mail.ParseAddress(emailConfig.FromAddress); err != nil {
+		return fmt.Errorf("invalid from address: %w", err)
+	}
+	for _, addr := range emailConfig.ToAddresses {
+		if _, err := mail.ParseAddress(addr); err != nil {
+			return fmt.Errorf("invalid to address %s: %w", addr, err)
+		}
+	}
+
+	if emailConfig.SMTPPassword != "" {
+		if decrypted, err := utils.Decrypt(emailConfig.SMTPPassword); err == nil {
+			emailConfig.SMTPPassword = decrypted
+		}
+	}
+
+	htmlBody, textBody, err := s.renderDigestEmailTemplate(msgs, now)
+	if err != nil {
+		return fmt.Errorf("failed to render email template: %w", err)
+	}
+
+	subject := digestMessage(msgs, now).Title
+	message := notifications.BuildMultipartMessage(emailConfig.FromAddress, emailConfig.ToAddresses, subject, htmlBody, textBody)
+
+	client, err := notifications.ConnectSMTP(ctx, emailConfig)
+	if err != nil {
+		return fmt.Errorf("failed to connect to SMTP server: %w", err)
+	}
+	defer client.Close()
+
+	if err := client.SendMessage(emailConfig.FromAddress, emailConfig.ToAddresses, message); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+
+	return nil
+}
+
+// digestEmailItem is one queued notification listed in the digest email.
+type digestEmailItem struct {
+	Title         string
+	Message       string
+	EventLabel    string
+	SeverityColor string
+	Time          string
+}
+
+func (s *NotificationService) renderDigestEmailTemplate(msgs []notificationMessage, now time.Time) (string, string, error) {
+	items := make([]digestEmailItem, 0, min(len(msgs), digestItemLimit))
+	for _, msg := range msgs[:min(len(msgs), digestItemLimit)] {
+		items = append(items, digestEmailItem{
+			Title:         msg.Title,
+			Message:       msg.Message,
+			EventLabel:    notificationEventLabel(msg.Event),
+			SeverityColor: notificationSeverityColor(msg.Severity),
+			Time:          msg.Timestamp.Format(time.RFC1123),
+		})
+	}
+
+	appURL := ""
+	if s.config != nil {
+		appURL = s.config.AppUrl
+	}
+
+	title := digestMessage(msgs, now).Title
+	data := map[string]interface{}{
+		"LogoURL":    "https://raw.githubusercontent.com/getarcaneapp/arcane/main/backend/resources/images/logo-full.svg",
+		"AppURL":     appURL,
+		"Title":      title,
+		"TitleUpper": strings.ToUpper(title),
+		"Summary":    digestSummary(msgs),
+		"Items":      items,
+		"MoreCount":  len(msgs) - len(items),
+		"SentAt":     now.Format(time.RFC1123),
+	}
+
+	htmlContent, err := resources.FS.ReadFile("email-templates/digest_html.tmpl")
+	if err != nil {
+		return "", "", fmt.Errorf("failed to read HTML template: %w", err)
+	}
+
+	htmlTmpl, err := template.New("html").Parse(string(htmlContent))
+	if err != nil {
+		return "", "", fmt.Errorf("failed to parse HTML template: %w", err)
+	}
+
+	var htmlBuf bytes.Buffer
+	if err := htmlTmpl.ExecuteTemplate(&htmlBuf, "root", data); err != nil {
+		return "", "", fmt.Errorf("failed to execute HTML template: %w", err)
+	}
+
+	textContent, err := resources.FS.ReadFile("email-templates/digest_text.tmpl")
+	if err != nil {
+		return "", "", fmt.Errorf("failed to read text template: %w", err)
+	}
+
+	textTmpl, err := template.New("text").Parse(string(textContent))
+	if err != nil {
+		return "", "", fmt.Errorf("failed to parse text template: %w", err)
+	}
+
+	var textBuf bytes.Buffer
+	if err := textTmpl.ExecuteTemplate(&textBuf, "root", data); err != nil {
+		return "", "", fmt.Errorf("failed to execute text template: %w", err)
+	}
+
+	return htmlBuf.String(), textBuf.String(), nil
+}
+
+func notificationSeverityColor(severity notificationSeverity) string {
+	switch severity {
+	case notificationSeveritySuccess:
+		return "#4ade80"
+	case notificationSeverityWarning:
+		return "#fbbf24"
+	case notificationSeverityError:
+		return "#f87171"
+	case notificationSeverityInfo:
+	}
+	return "#60a5fa"
+}
+
+func (s *NotificationService) TestNotification(ctx context.Context, provider models.NotificationProvider, testType string) error {
+	setting, err := s.GetSettingsByProvider(ctx, provider)
+	if err != nil {
+		return fmt.Errorf("please save your %s settings before testing", provider)
+	}
+
+	testUpdate := &dto.ImageUpdateResponse{
+		HasUpdate:      true,
+		UpdateType:     "digest",
+		CurrentDigest:  "sha256:abc123def456789012345678901234567890",
+		LatestDigest:   "sha256:xyz789ghi012345678901234567890123456",
+		CheckTime:      time.Now(),
+		ResponseTimeMs: 100,
+	}
+
+	switch provider {
+	case models.NotificationProviderDiscord:
+		return s.sendDiscordNotification(ctx, "test/image:latest", testUpdate, setting.Config)
+	case models.NotificationProviderEmail:
+		if testType == "image-update" {
+			return s.sendEmailNotification(ctx, "nginx:latest", testUpdate, setting.Config)
+		}
+		return s.sendTestEmail(ctx, setting.Config)
+	case models.NotificationProviderWebhook, models.NotificationProviderSlack, models.NotificationProviderTeams,
+		models.NotificationProviderNtfy, models.NotificationProviderGotify:
+		msg := testMessage()
+		if testType == "image-update" {
+			msg = imageUpdateMessage("nginx:latest", testUpdate, models.NotificationEventImageUpdate)
+		}
+		sendErr := s.sendProviderMessage(ctx, provider, msg, setting.Config)
+
+		status := "success"
+		var errMsg *string
+		if sendErr != nil {
+			status = "failed"
+			text := sendErr.Error()
+			errMsg = &text
+		}
+		s.logNotification(ctx, provider, "test", status, errMsg, models.JSON{
+			"eventType": string(msg.Event),
+			"test":      true,
+		})
+		return sendErr
+	default:
+		return fmt.Errorf("unknown provider: %s", provider)
+	}
+}
+
+func (s *NotificationService) sendTestEmail(ctx context.Context, config models.JSON) error {
+	var emailConfig models.EmailConfig
+	configBytes, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("failed to marshal email config: %w", err)
+	}
+	if err := json.Unmarshal(configBytes, &emailConfig); err != nil {
+		return fmt.Errorf("failed to unmarshal email config: %w", err)
+	}
+
+	if emailConfig.SMTPHost == "" || emailConfig.SMTPPort == 0 {
+		return fmt.Errorf("SMTP host or port not configured")
+	}
+	if len(emailConfig.ToAddresses) == 0 {
+		return fmt.Errorf("no recipient email addresses configured")
+	}
+
+	if _, err := mail.ParseAddress(emailConfig.FromAddress); err != nil {
+		return fmt.Errorf("invalid from address: %w", err)
+	}
+	for _, addr := range emailConfig.ToAddresses {
+		if _, err := mail.ParseAddress(addr); err != nil {
+			return fmt.Errorf("invalid to address %s: %w", addr, err)
+		}
+	}
+
+	if emailConfig.SMTPPassword != "" {
+		if decrypted, err := utils.Decrypt(emailConfig.SMTPPassword); err == nil {
+			emailConfig.SMTPPassword = decrypted
+		}
+	}
+
+	htmlBody, textBody, err := s.renderTestEmailTemplate()
+	if err != nil {
+		return fmt.Errorf("failed to render test email template: %w", err)
+	}
+
+	subject := "Test Email from Arcane"
+	message := notifications.BuildMultipartMessage(emailConfig.FromAddress, emailConfig.ToAddresses, subject, htmlBody, textBody)
+
+	client, err := notifications.ConnectSMTP(ctx, emailConfig)
+	if err != nil {
+		return fmt.Errorf("failed to connect to SMTP server: %w", err)
+	}
+	defer client.Close()
+
+	if err := client.SendMessage(emailConfig.FromAddress, emailConfig.ToAddresses, message); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+
+	return nil
+}
+
+func (s *NotificationService) renderTestEmailTemplate() (string, string, error) {
+	data := map[string]interface{}{
+		"LogoURL": "https://raw.githubusercontent.com/getarcaneapp/arcane/main/backend/resources/images/logo-full.svg",
+		"AppURL":  s.config.AppUrl,
+	}
+
+	htmlContent, err := resources.FS.ReadFile("email-templates/test_html.tmpl")
+	if err != nil {
+		return "", "", fmt.Errorf("failed to read HTML template: %w", err)
+	}
+
+	htmlTmpl, err := template.New("html").Parse(string(htmlContent))
+	if err != nil {
+		return "", "", fmt.Errorf("failed to parse HTML template: %w", err)
+	}
+
+	var htmlBuf bytes.Buffer
+	if err := htmlTmpl.ExecuteTemplate(&htmlBuf, "root", data); err != nil {
+		return "", "", fmt.Errorf("failed to execute HTML template: %w", err)
+	}
+
+	textContent, err := resources.FS.ReadFile("email-templates/test_text.tmpl")
+	if err != nil {
+		return "", "", fmt.Errorf("failed to read text template: %w", err)
+	}
+
+	textTmpl, err := template.New("text").Parse(string(textContent))
+	if err != nil {
+		return "", "", fmt.Errorf("failed to parse text template: %w", err)
+	}
+
+	var textBuf bytes.Buffer
+	if err := textTmpl.ExecuteTemplate(&textBuf, "root", data); err != nil {
+		return "", "", fmt.Errorf("failed to execute text template: %w", err)
+	}
+
+	return htmlBuf.String(), textBuf.String(), nil
+}
+
+func (s *NotificationService) logNotification(ctx context.Context, provider models.NotificationProvider, imageRef, status string, errMsg *string, metadata models.JSON) {
+	log := &models.NotificationLog{
+		Provider: provider,
+		ImageRef: imageRef,
+		Status:   status,
+		Error:    errMsg,
+		Metadata: metadata,
+		SentAt:   time.Now(),
+	}
+
+	if err := s.db.WithContext(ctx).Create(log).Error; err != nil {
+		slog.WarnContext(ctx, "Failed to log notification",
+			slog.String("provider", string(provider)),
+			slog.String("error", err.Error()))
+	}
+}
+
+func (s *NotificationService) SendBatchImageUpdateNotification(ctx context.Context, updates map[string]*dto.ImageUpdateResponse) error {
+	if len(updates) == 0 {
+		return nil
+	}
+
+	updatesWithChanges := make(map[string]*dto.ImageUpdateResponse)
+	for imageRef, update := range updates {
+		if update != nil && update.HasUpdate {
+			updatesWithChanges[imageRef] = update
+		}
+	}
+
+	if len(updatesWithChanges) == 0 {
+		return nil
+	}
+
+	// Send to Apprise if enabled
+	s.sendApprise(ctx, func() error {
+		return s.appriseService.SendBatchImageUpdateNotification(ctx, updatesWithChanges)
+	}, imageUpdateMessages(updatesWithChanges)...)
+
+	settings, err := s.GetAllSettings(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get notification settings: %w", err)
+	}
+
+	var errors []string
+	for _, setting := range settings {
+		if !setting.Enabled {
+			continue
+		}
+
+		if !s.isEventEnabled(setting.Config, models.NotificationEventImageUpdate) {
+			continue
+		}
+		// Queued one per image so the digest lists each of them.
+		if s.deferNotification(ctx, setting, imageUpdateMessages(updatesWithChanges)...) {
+			continue
+		}
+
+		var sendErr error
+		switch setting.Provider {
+		case models.NotificationProviderDiscord:
+			sendErr = s.sendBatchDiscordNotification(ctx, updatesWithChanges, setting.Config)
+		case models.NotificationProviderEmail:
+			sendErr = s.sendBatchEmailNotification(ctx, updatesWithChanges, setting.Config)
+		case models.NotificationProviderWebhook, models.NotificationProviderSlack, models.NotificationProviderTeams,
+			models.NotificationProviderNtfy, models.NotificationProviderGotify:
+			sendErr = s.sendProviderMessage(ctx, setting.Provider, batchImageUpdateMessage(updatesWithChanges), setting.Config)
+		default:
+			slog.WarnContext(ctx, "Unknown notification provider", "provider", setting.Provider)
+			continue
+		}
+
+		status := "success"
+		var errMsg *string
+		if sendErr != nil {
+			status = "failed"
+			msg := sendErr.Error()
+			errMsg = &msg
+			errors = append(errors, fmt.Sprintf("%s: %s", setting.Provider, msg))
+		}
+
+		imageRefs := make([]string, 0, len(updatesWithChanges))
+		for ref := range updatesWithChanges {
+			imageRefs = append(imageRefs, ref)
+		}
+
+		s.logNotification(ctx, setting.Provider, strings.Join(imageRefs, ", "), status, errMsg, models.JSON{
+			"updateCount": len(updatesWithChanges),
+			"eventType":   string(models.NotificationEventImageUpdate),
+			"batch":       true,
+		})
+	}
+
+	if len(errors) > 0 {
+		return fmt.Errorf("notification errors: %s", strings.Join(errors, "; "))
+	}
+
+	return nil
+}
+
+func (s *NotificationService) sendBatchDiscordNotification(ctx context.Context, updates map[string]*dto.ImageUpdateResponse, config models.JSON) error {
+	var discordConfig models.DiscordConfig
+	configBytes, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("failed to marshal discord config: %w", err)
+	}
+	if err := json.Unmarshal(configBytes, &discordConfig); err != nil {
+		return fmt.Errorf("failed to unmarshal discord config: %w", err)
+	}
+
+	if err := validateWebhookURL(discordConfig.WebhookURL); err != nil {
+		return fmt.Errorf("invalid webhook URL: %w", err)
+	}
+
+	fields := make([]map[string]interface{}, 0, len(updates))
+	for imageRef, update := range updates {
+		fields = append(fields, map[string]interface{}{
+			"name": imageRef,
+			"value": fmt.Sprintf(
+				"**Type:** %s\n**Current:** `%s`\n**Latest:** `%s`",
+				update.UpdateType,
+				truncateDigest(update.CurrentDigest),
+				truncateDigest(update.LatestDigest),
+			),
+			"inline": false,
+		})
+	}
+
+	title := "Container Image Updates Available"
+	description := fmt.Sprintf("%d container image(s) have updates available.", len(updates))
+	if len(updates) == 1 {
 		description = "1 container image has an update available."
 	}
 
@@ -1029,6 +1956,41 @@ func (s *NotificationService) renderBatchEmailTemplate(updates map[string]*dto.I
 	return htmlBuf.String(), textBuf.String(), nil
 }
 
+// discordFieldLimit is the maximum length of a Discord embed field value.
+const discordFieldLimit = 1024
+
+// updateVersion is the version an update moves to: the newer tag, or the version label of
+// the image behind a changed digest.
+func updateVersion(updateInfo *dto.ImageUpdateResponse) string {
+	if updateInfo.UpdateType == models.UpdateTypeTag && updateInfo.LatestVersion != "" {
+		return updateInfo.LatestVersion
+	}
+	if updateInfo.Release != nil {
+		return updateInfo.Release.Version
+	}
+	return ""
+}
+
+// releaseLink returns the release page, or the source repository when there is no release.
+// Only http(s) links are returned since these values come from image labels.
+func releaseLink(rel *dto.ImageReleaseInfo) string {
+	for _, link := range []string{rel.URL, rel.Source} {
+		u, err := url.Parse(link)
+		if err == nil && (u.Scheme == "https" || u.Scheme == "http") && u.Host != "" {
+			return link
+		}
+	}
+	return ""
+}
+
+func truncateText(s string, limit int) string {
+	r := []rune(s)
+	if len(r) <= limit {
+		return s
+	}
+	return string(r[:limit-1]) + "…"
+}
+
 func truncateDigest(digest string) string {
 	if len(digest) > 19 {
 		return digest[:19] + "..."
@@ -0,0 +1,529 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/ofkm/arcane-backend/internal/database"
+	"github.com/ofkm/arcane-backend/internal/dto"
+	"github.com/ofkm/arcane-backend/internal/models"
+)
+
+var (
+	ErrNotificationSubscriptionNotFound = errors.New("notification subscription not found")
+	ErrInvalidNotificationSubscription  = errors.New("invalid notification subscription")
+)
+
+const (
+	// notificationDeliveryWorkers bounds how many deliveries send at the same time.
+	notificationDeliveryWorkers      = 4
+	defaultNotificationDeliveryLimit = 100
+	maxNotificationDeliveryLimit     = 1000
+)
+
+// defaultNotificationRetryDelays are the waits before the second, third and fourth attempt.
+var defaultNotificationRetryDelays = []time.Duration{10 * time.Second, time.Minute, 5 * time.Minute}
+
+// NotificationSubscriptionService routes stored events to notification channels.
+type NotificationSubscriptionService struct {
+	db                  *database.DB
+	notificationService *NotificationService
+	audit               *AuditService
+
+	retryDelays []time.Duration
+	workers     chan struct{}
+	wg          sync.WaitGroup
+	// startedAt separates the deliveries left over from an earlier run, which
+	// ResumeDeliveries picks up, from the ones this process is already working on.
+	startedAt time.Time
+}
+
+func NewNotificationSubscriptionService(db *database.DB, notificationService *NotificationService, audit *AuditService) *NotificationSubscriptionService {
+	return &NotificationSubscriptionService{
+		db:                  db,
+		notificationService: notificationService,
+		audit:               audit,
+		retryDelays:         defaultNotificationRetryDelays,
+		workers:             make(chan struct{}, notificationDeliveryWorkers),
+		startedAt:           time.Now(),
+	}
+}
+
+func (s *NotificationSubscriptionService) ListSubscriptions(ctx context.Context) ([]models.NotificationSubscription, error) {
+	var subs []models.NotificationSubscription
+	if err := s.db.WithContext(ctx).Order("name ASC").Find(&subs).Error; err != nil {
+		return nil, fmt.Errorf("failed to list notification subscriptions: %w", err)
+	}
+	return subs, nil
+}
+
+func (s *NotificationSubscriptionService) GetSubscription(ctx context.Context, id string) (*models.NotificationSubscription, error) {
+	var sub models.NotificationSubscription
+	if err := s.db.WithContext(ctx).Where("id = ?", id).First(&sub).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotificationSubscriptionNotFound
+		}
+		return nil, fmt.Errorf("failed to get notification subscription: %w", err)
+	}
+	return &sub, nil
+}
+
+func (s *NotificationSubscriptionService) CreateSubscription(ctx context.Context, req dto.NotificationSubscriptionDto) (*models.NotificationSubscription, error) {
+	var sub models.NotificationSubscription
+	if err := applyNotificationSubscriptionDto(&sub, req); err != nil {
+		return nil, err
+	}
+	if err := s.db.WithContext(ctx).Create(&sub).Error; err != nil {
+		return nil, fmt.Errorf("failed to create notification subscription: %w", err)
+	}
+
+	s.audit.Record(ctx, AuditChange{
+		Action:     auditActionCreate,
+		TargetType: auditTargetNotificationSub,
+		TargetID:   sub.ID,
+		TargetName: sub.Name,
+		After:      &sub,
+		Sensitive:  auditNotificationSensitive,
+	})
+	return &sub, nil
+}
+
+func (s *NotificationSubscriptionService) UpdateSubscription(ctx context.Context, id string, req dto.NotificationSubscriptionDto) (*models.NotificationSubscription, error) {
+	sub, err := s.GetSubscription(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	before := *sub
+	if err := applyNotificationSubscriptionDto(sub, req); err != nil {
+		return nil, err
+	}
+	if err := s.db.WithContext(ctx).Save(sub).Error; err != nil {
+		return nil, fmt.Errorf("failed to update notification subscription: %w", err)
+	}
+
+	s.audit.Record(ctx, AuditChange{
+		Action:     auditActionUpdate,
+		TargetType: auditTargetNotificationSub,
+		TargetID:   sub.ID,
+		TargetName: sub.Name,
+		Before:     &before,
+		After:      sub,
+		Sensitive:  auditNotificationSensitive,
+	})
+	return sub, nil
+}
+
+func (s *NotificationSubscriptionService) DeleteSubscription(ctx context.Context, id string) error {
+	before, _ := s.GetSubscription(ctx, id)
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("subscription_id = ?", id).Delete(&models.NotificationDelivery{}).Error; err != nil {
+			return fmt.Errorf("failed to delete notification deliveries: %w", err)
+		}
+		res := tx.Where("id = ?", id).Delete(&models.NotificationSubscription{})
+		if res.Error != nil {
+			return fmt.Errorf("failed to delete notification subscription: %w", res.Error)
+		}
+		if res.RowsAffected == 0 {
+			return ErrNotificationSubscriptionNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if before != nil {
+		s.audit.Record(ctx, AuditChange{
+			Action:     auditActionDelete,
+			TargetType: auditTargetNotificationSub,
+			TargetID:   id,
+			TargetName: before.Name,
+			Before:     before,
+			Sensitive:  auditNotificationSensitive,
+		})
+	}
+	return nil
+}
+
+// ListDeliveries returns the newest deliveries first, optionally for one subscription.
+func (s *NotificationSubscriptionService) ListDeliveries(ctx context.Context, subscriptionID string, limit int) ([]models.NotificationDelivery, error) {
+	if limit <= 0 {
+		limit = defaultNotificationDeliveryLimit
+	}
+	limit = min(limit, maxNotificationDeliveryLimit)
+
+	q := s.db.WithContext(ctx).Order("created_at DESC").Limit(limit)
+	if subscriptionID != "" {
+		q = q.Where("subscription_id = ?", subscriptionID)
+	}
+	var deliveries []models.NotificationDelivery
+	if err := q.Find(&deliveries).Error; err != nil {
+		return nil, fmt.Errorf("failed to list notification deliveries: %w", err)
+	}
+	return deliveries, nil
+}
+
+// TestSubscription sends a sample event through the subscription once, without retries.
+func (s *NotificationSubscriptionService) TestSubscription(ctx context.Context, id string) error {
+	sub, err := s.GetSubscription(ctx, id)
+	if err != nil {
+		return err
+	}
+	resource := "arcane"
+	event := models.Event{
+		Type:         "notification.test",
+		Severity:     models.EventSeverityInfo,
+		Title:        "Test notification from Arcane",
+		Description:  fmt.Sprintf("If you're reading this, the %q subscription is working correctly!", sub.Name),
+		ResourceName: &resource,
+		Timestamp:    time.Now(),
+	}
+	return s.send(ctx, sub, &event, "")
+}
+
+func applyNotificationSubscriptionDto(sub *models.NotificationSubscription, req dto.NotificationSubscriptionDto) error {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidNotificationSubscription)
+	}
+
+	switch req.Provider {
+	case models.NotificationProviderDiscord, models.NotificationProviderEmail, models.NotificationProviderWebhook,
+		models.NotificationProviderSlack, models.NotificationProviderTeams, models.NotificationProviderNtfy,
+		models.NotificationProviderGotify:
+	default:
+		return fmt.Errorf("%w: unknown provider %q", ErrInvalidNotificationSubscription, req.Provider)
+	}
+
+	if err := validateDeliveryConfig(req.Config); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidNotificationSubscription, err)
+	}
+
+	eventTypes, err := normalizeSubscriptionPatterns(req.EventTypes, "event type")
+	if err != nil {
+		return err
+	}
+	resourcePatterns, err := normalizeSubscriptionPatterns(req.ResourcePatterns, "resource pattern")
+	if err != nil {
+		return err
+	}
+
+	var severities models.StringSlice
+	for _, raw := range req.Severities {
+		severity := models.EventSeverity(strings.ToLower(strings.TrimSpace(raw)))
+		switch severity {
+		case models.EventSeverityInfo, models.EventSeveritySuccess, models.EventSeverityWarning, models.EventSeverityError:
+			severities = append(severities, string(severity))
+		default:
+			return fmt.Errorf("%w: unknown severity %q", ErrInvalidNotificationSubscription, raw)
+		}
+	}
+
+	var environmentIDs models.StringSlice
+	for _, id := range req.EnvironmentIDs {
+		if id = strings.TrimSpace(id); id != "" {
+			environmentIDs = append(environmentIDs, id)
+		}
+	}
+
+	sub.Name = name
+	sub.Provider = req.Provider
+	sub.Enabled = req.Enabled == nil || *req.Enabled
+	sub.Config = req.Config
+	sub.EventTypes = eventTypes
+	sub.Severities = severities
+	sub.EnvironmentIDs = environmentIDs
+	sub.ResourcePatterns = resourcePatterns
+	return nil
+}
+
+func normalizeSubscriptionPatterns(raw []string, kind string) (models.StringSlice, error) {
+	var out models.StringSlice
+	for _, pattern := range raw {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" {
+			continue
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("%w: invalid %s %q", ErrInvalidNotificationSubscription, kind, pattern)
+		}
+		out = append(out, pattern)
+	}
+	return out, nil
+}
+
+// subscriptionMatches reports whether event passes every filter of sub.
+func subscriptionMatches(sub *models.NotificationSubscription, event *models.Event) bool {
+	if !sub.Enabled {
+		return false
+	}
+	if len(sub.EventTypes) > 0 && !matchesAnyPattern(sub.EventTypes, string(event.Type)) {
+		return false
+	}
+	if len(sub.Severities) > 0 && !slices.Contains(sub.Severities, string(event.Severity)) {
+		return false
+	}
+	if len(sub.EnvironmentIDs) > 0 && (event.EnvironmentID == nil || !slices.Contains(sub.EnvironmentIDs, *event.EnvironmentID)) {
+		return false
+	}
+	if len(sub.ResourcePatterns) > 0 && (event.ResourceName == nil || !matchesAnyPattern(sub.ResourcePatterns, *event.ResourceName)) {
+		return false
+	}
+	return true
+}
+
+func matchesAnyPattern(patterns []string, value string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, value); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Dispatch delivers event to every matching subscription in the background. It is wired to
+// EventService.OnEventCreated and returns immediately.
+func (s *NotificationSubscriptionService) Dispatch(ctx context.Context, event models.Event) {
+	// The event usually comes from a request whose context ends before delivery does.
+	ctx = context.WithoutCancel(ctx)
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		var subs []models.NotificationSubscription
+		if err := s.db.WithContext(ctx).Where("enabled = ?", true).Find(&subs).Error; err != nil {
+			slog.WarnContext(ctx, "Failed to load notification subscriptions", "event", event.Type, "error", err)
+			return
+		}
+
+		var environmentName string
+		for i := range subs {
+			sub := &subs[i]
+			if !subscriptionMatches(sub, &event) {
+				continue
+			}
+			if environmentName == "" && event.EnvironmentID != nil {
+				environmentName = s.environmentName(ctx, *event.EnvironmentID)
+			}
+			name := environmentName
+			s.wg.Add(1)
+			go func() {
+				defer s.wg.Done()
+				s.deliver(ctx, sub, &event, name)
+			}()
+		}
+	}()
+}
+
+func (s *NotificationSubscriptionService) environmentName(ctx context.Context, id string) string {
+	var env models.Environment
+	if err := s.db.WithContext(ctx).Select("name").Where("id = ?", id).First(&env).Error; err != nil || env.Name == "" {
+		return id
+	}
+	return env.Name
+}
+
+// deliver records a delivery of event to sub and works through its attempts.
+func (s *NotificationSubscriptionService) deliver(ctx context.Context, sub *models.NotificationSubscription, event *models.Event, environmentName string) {
+	delivery := &models.NotificationDelivery{
+		SubscriptionID: sub.ID,
+		EventID:        event.ID,
+		EventType:      event.Type,
+		Provider:       sub.Provider,
+		Status:         models.NotificationDeliveryPending,
+	}
+	if err := s.db.WithContext(ctx).Create(delivery).Error; err != nil {
+		slog.WarnContext(ctx, "Failed to record notification delivery", "subscription", sub.Name, "error", err)
+	}
+	s.run(ctx, sub, event, environmentName, delivery)
+}
+
+// run sends event to sub, retrying with the configured backoff, and records the outcome.
+// Quiet hours and digest schedules queue the event instead of sending it. Between attempts
+// the delivery stays pending with its next attempt time saved, so ResumeDeliveries can
+// carry on after a restart.
+func (s *NotificationSubscriptionService) run(ctx context.Context, sub *models.NotificationSubscription, event *models.Event, environmentName string, delivery *models.NotificationDelivery) {
+	for {
+		if delivery.NextAttemptAt != nil {
+			select {
+			case <-time.After(time.Until(*delivery.NextAttemptAt)):
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		if s.notificationService.deferSubscriptionDelivery(ctx, sub, delivery.ID, eventMessage(event, environmentName)) {
+			delivery.Status = models.NotificationDeliveryQueued
+			delivery.NextAttemptAt = nil
+			s.saveDelivery(ctx, delivery)
+			return
+		}
+
+		s.workers <- struct{}{}
+		err := s.send(ctx, sub, event, environmentName)
+		<-s.workers
+
+		delivery.Attempts++
+		delivery.NextAttemptAt = nil
+		if err == nil {
+			now := time.Now()
+			delivery.Status = models.NotificationDeliverySuccess
+			delivery.DeliveredAt = &now
+			delivery.LastError = nil
+			s.saveDelivery(ctx, delivery)
+			return
+		}
+
+		msg := err.Error()
+		delivery.LastError = &msg
+		if delivery.Attempts > len(s.retryDelays) {
+			delivery.Status = models.NotificationDeliveryFailed
+			s.saveDelivery(ctx, delivery)
+			slog.WarnContext(ctx, "Notification delivery failed",
+				"subscription", sub.Name,
+				"provider", sub.Provider,
+				"event", event.Type,
+				"attempts", delivery.Attempts,
+				"error", err)
+			return
+		}
+		next := time.Now().Add(s.retryDelays[delivery.Attempts-1])
+		delivery.NextAttemptAt = &next
+		s.saveDelivery(ctx, delivery)
+	}
+}
+
+// ResumeDeliveries carries on with the deliveries that were still pending when Arcane last
+// stopped. Deliveries whose subscription or event is gone are marked failed.
+func (s *NotificationSubscriptionService) ResumeDeliveries(ctx context.Context) error {
+	var pending []models.NotificationDelivery
+	err := s.db.WithContext(ctx).
+		Where("status = ? AND created_at < ?", models.NotificationDeliveryPending, s.startedAt).
+		Order("created_at ASC").
+		Find(&pending).Error
+	if err != nil {
+		return fmt.Errorf("failed to load pending notification deliveries: %w", err)
+	}
+
+	for i := range pending {
+		delivery := &pending[i]
+		sub, err := s.GetSubscription(ctx, delivery.SubscriptionID)
+		if err == nil && !sub.Enabled {
+			err = errors.New("subscription is disabled")
+		}
+		var event models.Event
+		if err == nil {
+			err = s.db.WithContext(ctx).Where("id = ?", delivery.EventID).First(&event).Error
+		}
+		if err != nil {
+			msg := fmt.Sprintf("delivery could not be resumed: %v", err)
+			delivery.Status = models.NotificationDeliveryFailed
+			delivery.NextAttemptAt = nil
+			delivery.LastError = &msg
+			s.saveDelivery(ctx, delivery)
+			continue
+		}
+
+		var environmentName string
+		if event.EnvironmentID != nil {
+			environmentName = s.environmentName(ctx, *event.EnvironmentID)
+		}
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.run(ctx, sub, &event, environmentName, delivery)
+		}()
+	}
+	return nil
+}
+
+func (s *NotificationSubscriptionService) saveDelivery(ctx context.Context, delivery *models.NotificationDelivery) {
+	if delivery.ID == "" {
+		return
+	}
+	if err := s.db.WithContext(ctx).Save(delivery).Error; err != nil {
+		slog.WarnContext(ctx, "Failed to update notification delivery", "delivery", delivery.ID, "error", err)
+	}
+}
+
+// send renders event for the subscription's provider and delivers it once.
+func (s *NotificationSubscriptionService) send(ctx context.Context, sub *models.NotificationSubscription, event *models.Event, environmentName string) error {
+	config, err := s.notificationService.subscriptionChannel(ctx, sub)
+	if err != nil {
+		return err
+	}
+
+	ns := s.notificationService
+	switch sub.Provider {
+	case models.NotificationProviderDiscord:
+		return ns.sendDiscordMessage(ctx, eventMessage(event, environmentName), config)
+	case models.NotificationProviderEmail:
+		return ns.sendEmailEventNotification(ctx, eventEmail{Event: *event, EnvironmentName: environmentName, SubscriptionName: sub.Name}, config)
+	case models.NotificationProviderWebhook, models.NotificationProviderSlack, models.NotificationProviderTeams,
+		models.NotificationProviderNtfy, models.NotificationProviderGotify:
+		return ns.sendProviderMessage(ctx, sub.Provider, eventMessage(event, environmentName), config)
+	default:
+		return fmt.Errorf("unknown provider: %s", sub.Provider)
+	}
+}
+
+func eventMessage(event *models.Event, environmentName string) notificationMessage {
+	severity := notificationSeverityInfo
+	switch event.Severity {
+	case models.EventSeveritySuccess:
+		severity = notificationSeveritySuccess
+	case models.EventSeverityWarning:
+		severity = notificationSeverityWarning
+	case models.EventSeverityError:
+		severity = notificationSeverityError
+	case models.EventSeverityInfo:
+	}
+
+	msg := notificationMessage{
+		Event:     models.NotificationEventType(event.Type),
+		Title:     event.Title,
+		Message:   event.Description,
+		Severity:  severity,
+		Fields:    []notificationField{{Name: "Event", Value: string(event.Type)}},
+		Timestamp: event.Timestamp,
+		Data: map[string]any{
+			"eventId":  event.ID,
+			"metadata": event.Metadata,
+		},
+	}
+	if event.ResourceName != nil && *event.ResourceName != "" {
+		msg.Fields = append(msg.Fields, notificationField{Name: "Resource", Value: *event.ResourceName})
+		msg.Data["resourceName"] = *event.ResourceName
+	}
+	if event.ResourceType != nil {
+		msg.Data["resourceType"] = *event.ResourceType
+	}
+	if event.ResourceID != nil {
+		msg.Data["resourceId"] = *event.ResourceID
+	}
+	if environmentName != "" {
+		msg.Fields = append(msg.Fields, notificationField{Name: "Environment", Value: environmentName})
+	}
+	if event.EnvironmentID != nil {
+		msg.Data["environmentId"] = *event.EnvironmentID
+	}
+	if event.Username != nil && *event.Username != "" {
+		msg.Fields = append(msg.Fields, notificationField{Name: "User", Value: *event.Username})
+		msg.Data["username"] = *event.Username
+	}
+	if msg.Timestamp.IsZero() {
+		msg.Timestamp = time.Now()
+	}
+	return msg
+}
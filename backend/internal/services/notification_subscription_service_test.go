@@ -0,0 +1,260 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ofkm/arcane-backend/internal/dto"
+	"github.com/ofkm/arcane-backend/internal/models"
+)
+
+func setupNotificationSubscriptionService(t *testing.T) (*NotificationSubscriptionService, *EventService) {
+	t.Helper()
+	notificationService := setupNotificationService(t)
+	require.NoError(t, notificationService.db.AutoMigrate(
+		&models.NotificationSubscription{},
+		&models.NotificationDelivery{},
+		&models.NotificationQueueItem{},
+		&models.Event{},
+		&models.Environment{},
+	))
+	svc := NewNotificationSubscriptionService(notificationService.db, notificationService, nil)
+	svc.retryDelays = []time.Duration{time.Millisecond, time.Millisecond}
+
+	events := NewEventService(notificationService.db)
+	events.OnEventCreated = svc.Dispatch
+	return svc, events
+}
+
+func strPtr(s string) *string { return &s }
+
+func TestSubscriptionMatches(t *testing.T) {
+	sub := &models.NotificationSubscription{
+		Enabled:          true,
+		EventTypes:       models.StringSlice{"project.*", "container.error"},
+		Severities:       models.StringSlice{"error"},
+		EnvironmentIDs:   models.StringSlice{"0"},
+		ResourcePatterns: models.StringSlice{"prod-*"},
+	}
+	event := &models.Event{
+		Type:          models.EventTypeProjectError,
+		Severity:      models.EventSeverityError,
+		EnvironmentID: strPtr("0"),
+		ResourceName:  strPtr("prod-api"),
+	}
+	require.True(t, subscriptionMatches(sub, event))
+
+	other := *event
+	other.Type = models.EventTypeUserLogin
+	require.False(t, subscriptionMatches(sub, &other))
+
+	other = *event
+	other.Severity = models.EventSeveritySuccess
+	require.False(t, subscriptionMatches(sub, &other))
+
+	other = *event
+	other.EnvironmentID = nil
+	require.False(t, subscriptionMatches(sub, &other))
+
+	other = *event
+	other.ResourceName = strPtr("staging-api")
+	require.False(t, subscriptionMatches(sub, &other))
+
+	require.True(t, subscriptionMatches(&models.NotificationSubscription{Enabled: true}, &other))
+	require.False(t, subscriptionMatches(&models.NotificationSubscription{}, event))
+}
+
+func TestNotificationSubscriptionService_DispatchesMatchingEvents(t *testing.T) {
+	ctx := context.Background()
+	svc, events := setupNotificationSubscriptionService(t)
+	server, requests := newCaptureServer(t)
+
+	sub, err := svc.CreateSubscription(ctx, dto.NotificationSubscriptionDto{
+		Name:       "Failed deploys",
+		Provider:   models.NotificationProviderWebhook,
+		Config:     models.JSON{"url": server.URL},
+		EventTypes: []string{"project.*"},
+		Severities: []string{"error"},
+	})
+	require.NoError(t, err)
+
+	_, err = events.CreateEvent(ctx, CreateEventRequest{Type: models.EventTypeUserLogin, Title: "User logged in"})
+	require.NoError(t, err)
+	event, err := events.CreateEvent(ctx, CreateEventRequest{
+		Type:         models.EventTypeProjectError,
+		Severity:     models.EventSeverityError,
+		Title:        "Project deployment failed",
+		Description:  "image pull failed",
+		ResourceName: strPtr("my-stack"),
+	})
+	require.NoError(t, err)
+	svc.wg.Wait()
+
+	req := <-requests
+	require.Empty(t, requests)
+	var body map[string]any
+	require.NoError(t, json.Unmarshal(req.body, &body))
+	require.Equal(t, "project.error", body["event"])
+	require.Equal(t, "Project deployment failed", body["title"])
+	require.Equal(t, "error", body["severity"])
+
+	deliveries, err := svc.ListDeliveries(ctx, sub.ID, 0)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	require.Equal(t, event.ID, deliveries[0].EventID)
+	require.Equal(t, models.NotificationDeliverySuccess, deliveries[0].Status)
+	require.Equal(t, 1, deliveries[0].Attempts)
+}
+
+func TestNotificationSubscriptionService_RetriesFailedDeliveries(t *testing.T) {
+	ctx := context.Background()
+	svc, events := setupNotificationSubscriptionService(t)
+
+	var calls atomic.Int32
+	flaky := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(flaky.Close)
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	t.Cleanup(down.Close)
+
+	flakySub, err := svc.CreateSubscription(ctx, dto.NotificationSubscriptionDto{Name: "flaky", Provider: models.NotificationProviderWebhook, Config: models.JSON{"url": flaky.URL}})
+	require.NoError(t, err)
+	downSub, err := svc.CreateSubscription(ctx, dto.NotificationSubscriptionDto{Name: "down", Provider: models.NotificationProviderWebhook, Config: models.JSON{"url": down.URL}})
+	require.NoError(t, err)
+
+	_, err = events.CreateEvent(ctx, CreateEventRequest{Type: models.EventTypeSystemPrune, Title: "System pruned"})
+	require.NoError(t, err)
+	svc.wg.Wait()
+
+	deliveries, err := svc.ListDeliveries(ctx, flakySub.ID, 0)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	require.Equal(t, models.NotificationDeliverySuccess, deliveries[0].Status)
+	require.Equal(t, 2, deliveries[0].Attempts)
+	require.Nil(t, deliveries[0].LastError)
+
+	deliveries, err = svc.ListDeliveries(ctx, downSub.ID, 0)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	require.Equal(t, models.NotificationDeliveryFailed, deliveries[0].Status)
+	require.Equal(t, 3, deliveries[0].Attempts)
+	require.NotNil(t, deliveries[0].LastError)
+	require.Contains(t, *deliveries[0].LastError, "status 500")
+}
+
+func TestNotificationSubscriptionService_Validation(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := setupNotificationSubscriptionService(t)
+
+	_, err := svc.CreateSubscription(ctx, dto.NotificationSubscriptionDto{Name: "x", Provider: "pager"})
+	require.ErrorIs(t, err, ErrInvalidNotificationSubscription)
+	_, err = svc.CreateSubscription(ctx, dto.NotificationSubscriptionDto{Name: "x", Provider: models.NotificationProviderSlack, Severities: []string{"fatal"}})
+	require.ErrorIs(t, err, ErrInvalidNotificationSubscription)
+	_, err = svc.CreateSubscription(ctx, dto.NotificationSubscriptionDto{Name: "x", Provider: models.NotificationProviderSlack, EventTypes: []string{"project.["}})
+	require.ErrorIs(t, err, ErrInvalidNotificationSubscription)
+	_, err = svc.CreateSubscription(ctx, dto.NotificationSubscriptionDto{Name: "x", Provider: models.NotificationProviderSlack, Config: models.JSON{"delivery": map[string]any{"mode": "weekly"}}})
+	require.ErrorIs(t, err, ErrInvalidNotificationSubscription)
+
+	sub, err := svc.CreateSubscription(ctx, dto.NotificationSubscriptionDto{Name: "Security", Provider: models.NotificationProviderDiscord, EventTypes: []string{"user.*"}})
+	require.NoError(t, err)
+	// No config of its own and no saved Discord settings.
+	require.ErrorContains(t, svc.TestSubscription(ctx, sub.ID), "not configured")
+
+	require.ErrorIs(t, svc.DeleteSubscription(ctx, "missing"), ErrNotificationSubscriptionNotFound)
+	require.NoError(t, svc.DeleteSubscription(ctx, sub.ID))
+}
+
+func TestNotificationSubscriptionService_QuietHoursQueueDeliveries(t *testing.T) {
+	ctx := context.Background()
+	svc, events := setupNotificationSubscriptionService(t)
+	server, requests := newCaptureServer(t)
+
+	// Quiet hours around the current time, read in UTC.
+	now := time.Now().UTC()
+	_, err := svc.notificationService.CreateOrUpdateSettings(ctx, models.NotificationProviderWebhook, true, models.JSON{
+		"url": server.URL,
+		"delivery": map[string]any{
+			"quietHours": map[string]any{"enabled": true, "start": now.Add(-time.Hour).Format("15:04"), "end": now.Add(time.Hour).Format("15:04")},
+			"timezone":   "UTC",
+		},
+	})
+	require.NoError(t, err)
+	sub, err := svc.CreateSubscription(ctx, dto.NotificationSubscriptionDto{Name: "Everything", Provider: models.NotificationProviderWebhook})
+	require.NoError(t, err)
+
+	_, err = events.CreateEvent(ctx, CreateEventRequest{Type: models.EventTypeSystemPrune, Title: "System pruned"})
+	require.NoError(t, err)
+	svc.wg.Wait()
+	require.Empty(t, requests, "delivered during quiet hours")
+
+	deliveries, err := svc.ListDeliveries(ctx, sub.ID, 0)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	require.Equal(t, models.NotificationDeliveryQueued, deliveries[0].Status)
+
+	require.NoError(t, svc.notificationService.flushNotificationQueue(ctx, now))
+	require.Empty(t, requests)
+	require.NoError(t, svc.notificationService.flushNotificationQueue(ctx, now.Add(2*time.Hour)))
+	req := <-requests
+	var body map[string]any
+	require.NoError(t, json.Unmarshal(req.body, &body))
+	require.Equal(t, "digest", body["event"])
+
+	deliveries, err = svc.ListDeliveries(ctx, sub.ID, 0)
+	require.NoError(t, err)
+	require.Equal(t, models.NotificationDeliverySuccess, deliveries[0].Status)
+	require.NotNil(t, deliveries[0].DeliveredAt)
+}
+
+func TestNotificationSubscriptionService_ResumesPendingDeliveries(t *testing.T) {
+	ctx := context.Background()
+	svc, events := setupNotificationSubscriptionService(t)
+	server, requests := newCaptureServer(t)
+
+	sub, err := svc.CreateSubscription(ctx, dto.NotificationSubscriptionDto{Name: "Prunes", Provider: models.NotificationProviderWebhook, Config: models.JSON{"url": server.URL}, EventTypes: []string{"system.*"}})
+	require.NoError(t, err)
+	event, err := events.CreateEvent(ctx, CreateEventRequest{Type: models.EventTypeProjectError, Title: "Not for this subscription"})
+	require.NoError(t, err)
+	svc.wg.Wait()
+
+	// Deliveries left pending by an earlier run, one of them for an event that is gone.
+	retryAt := time.Now().Add(-time.Minute)
+	lastError := "status 503"
+	pending := &models.NotificationDelivery{SubscriptionID: sub.ID, EventID: event.ID, EventType: models.EventTypeSystemPrune, Provider: sub.Provider,
+		Status: models.NotificationDeliveryPending, Attempts: 1, LastError: &lastError, NextAttemptAt: &retryAt}
+	orphan := &models.NotificationDelivery{SubscriptionID: sub.ID, EventID: "missing", EventType: models.EventTypeSystemPrune, Provider: sub.Provider,
+		Status: models.NotificationDeliveryPending}
+	require.NoError(t, svc.db.WithContext(ctx).Create(pending).Error)
+	require.NoError(t, svc.db.WithContext(ctx).Create(orphan).Error)
+
+	svc.startedAt = time.Now().Add(time.Second)
+	require.NoError(t, svc.ResumeDeliveries(ctx))
+	svc.wg.Wait()
+
+	req := <-requests
+	var body map[string]any
+	require.NoError(t, json.Unmarshal(req.body, &body))
+	require.Equal(t, "Not for this subscription", body["title"])
+
+	var resumed, dropped models.NotificationDelivery
+	require.NoError(t, svc.db.WithContext(ctx).Where("id = ?", pending.ID).First(&resumed).Error)
+	require.Equal(t, models.NotificationDeliverySuccess, resumed.Status)
+	require.Equal(t, 2, resumed.Attempts)
+	require.Nil(t, resumed.NextAttemptAt)
+	require.NoError(t, svc.db.WithContext(ctx).Where("id = ?", orphan.ID).First(&dropped).Error)
+	require.Equal(t, models.NotificationDeliveryFailed, dropped.Status)
+}
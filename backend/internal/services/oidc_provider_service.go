@@ -0,0 +1,330 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"github.com/ofkm/arcane-backend/internal/database"
+	"github.com/ofkm/arcane-backend/internal/dto"
+	"github.com/ofkm/arcane-backend/internal/models"
+	"github.com/ofkm/arcane-backend/internal/utils"
+)
+
+var (
+	ErrOidcProviderNotFound    = errors.New("OIDC provider not found")
+	ErrOidcProviderExists      = errors.New("an OIDC provider with this slug already exists")
+	ErrInvalidOidcProvider     = errors.New("invalid OIDC provider")
+	ErrOidcRoleMappingNotFound = errors.New("OIDC role mapping not found")
+	ErrInvalidOidcRoleMapping  = errors.New("invalid OIDC role mapping")
+)
+
+var oidcProviderSlugPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,31}$`)
+
+// OidcProviderService manages the identity providers configured in addition to the
+// default one, and the mappings from provider claims to Arcane roles.
+type OidcProviderService struct {
+	db          *database.DB
+	roleService *RoleService
+	audit       *AuditService
+}
+
+func NewOidcProviderService(db *database.DB, roleService *RoleService, audit *AuditService) *OidcProviderService {
+	return &OidcProviderService{db: db, roleService: roleService, audit: audit}
+}
+
+func (s *OidcProviderService) ListProviders(ctx context.Context) ([]models.OidcProvider, error) {
+	var providers []models.OidcProvider
+	if err := s.db.WithContext(ctx).Order("name ASC").Find(&providers).Error; err != nil {
+		return nil, fmt.Errorf("failed to list OIDC providers: %w", err)
+	}
+	return providers, nil
+}
+
+func (s *OidcProviderService) GetProvider(ctx context.Context, slug string) (*models.OidcProvider, error) {
+	var provider models.OidcProvider
+	if err := s.db.WithContext(ctx).Where("slug = ?", slug).First(&provider).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrOidcProviderNotFound
+		}
+		return nil, fmt.Errorf("failed to get OIDC provider: %w", err)
+	}
+	return &provider, nil
+}
+
+func (s *OidcProviderService) CreateProvider(ctx context.Context, req dto.CreateOidcProviderDto) (*models.OidcProvider, error) {
+	slug := strings.ToLower(strings.TrimSpace(req.Slug))
+	if !oidcProviderSlugPattern.MatchString(slug) || slug == models.OidcDefaultProvider {
+		return nil, fmt.Errorf("%w: slug must be up to 32 lowercase letters, digits and dashes and not %q", ErrInvalidOidcProvider, models.OidcDefaultProvider)
+	}
+	if _, err := s.GetProvider(ctx, slug); err == nil {
+		return nil, ErrOidcProviderExists
+	}
+
+	provider := &models.OidcProvider{
+		Slug:      slug,
+		Name:      strings.TrimSpace(req.Name),
+		IssuerURL: strings.TrimSpace(req.IssuerURL),
+		ClientID:  strings.TrimSpace(req.ClientID),
+		Scopes:    strings.TrimSpace(req.Scopes),
+		Enabled:   req.Enabled == nil || *req.Enabled,
+
+		ManageDefaultRole: req.ManageDefaultRole,
+	}
+	if err := s.setClientSecret(provider, req.ClientSecret); err != nil {
+		return nil, err
+	}
+	if err := validateOidcProvider(provider); err != nil {
+		return nil, err
+	}
+
+	if err := s.db.WithContext(ctx).Create(provider).Error; err != nil {
+		return nil, fmt.Errorf("failed to create OIDC provider: %w", err)
+	}
+
+	s.audit.Record(ctx, AuditChange{
+		Action:     auditActionCreate,
+		TargetType: auditTargetOidcProvider,
+		TargetID:   provider.ID,
+		TargetName: provider.Slug,
+		After:      auditOidcProviderSnapshot(provider),
+		Sensitive:  auditOidcProviderSensitive,
+	})
+	return provider, nil
+}
+
+func (s *OidcProviderService) UpdateProvider(ctx context.Context, slug string, req dto.UpdateOidcProviderDto) (*models.OidcProvider, error) {
+	provider, err := s.GetProvider(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	before := *provider
+
+	if req.Name != nil {
+		provider.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.IssuerURL != nil {
+		provider.IssuerURL = strings.TrimSpace(*req.IssuerURL)
+	}
+	if req.ClientID != nil {
+		provider.ClientID = strings.TrimSpace(*req.ClientID)
+	}
+	if req.Scopes != nil {
+		provider.Scopes = strings.TrimSpace(*req.Scopes)
+	}
+	if req.Enabled != nil {
+		provider.Enabled = *req.Enabled
+	}
+	if req.ManageDefaultRole != nil {
+		provider.ManageDefaultRole = *req.ManageDefaultRole
+	}
+	if req.ClientSecret != nil && *req.ClientSecret != "" {
+		if err := s.setClientSecret(provider, *req.ClientSecret); err != nil {
+			return nil, err
+		}
+	}
+	if err := validateOidcProvider(provider); err != nil {
+		return nil, err
+	}
+
+	if err := s.db.WithContext(ctx).Save(provider).Error; err != nil {
+		return nil, fmt.Errorf("failed to update OIDC provider: %w", err)
+	}
+
+	s.audit.Record(ctx, AuditChange{
+		Action:     auditActionUpdate,
+		TargetType: auditTargetOidcProvider,
+		TargetID:   provider.ID,
+		TargetName: provider.Slug,
+		Before:     auditOidcProviderSnapshot(&before),
+		After:      auditOidcProviderSnapshot(provider),
+		Sensitive:  auditOidcProviderSensitive,
+	})
+	return provider, nil
+}
+
+// DeleteProvider removes the provider and its role mappings. Users who signed in with it
+// are kept but can no longer sign in through it.
+func (s *OidcProviderService) DeleteProvider(ctx context.Context, slug string) error {
+	before, err := s.GetProvider(ctx, slug)
+	if err != nil {
+		return err
+	}
+
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("slug = ?", slug).Delete(&models.OidcProvider{})
+		if result.Error != nil {
+			return fmt.Errorf("failed to delete OIDC provider: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return ErrOidcProviderNotFound
+		}
+		if err := tx.Where("provider = ?", slug).Delete(&models.OidcRoleMapping{}).Error; err != nil {
+			return fmt.Errorf("failed to delete OIDC role mappings: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	s.audit.Record(ctx, AuditChange{
+		Action:     auditActionDelete,
+		TargetType: auditTargetOidcProvider,
+		TargetID:   before.ID,
+		TargetName: before.Slug,
+		Before:     auditOidcProviderSnapshot(before),
+		Sensitive:  auditOidcProviderSensitive,
+	})
+	return nil
+}
+
+// GetProviderConfig returns the sign-in configuration of an enabled provider.
+func (s *OidcProviderService) GetProviderConfig(ctx context.Context, slug string) (*models.OidcConfig, error) {
+	provider, err := s.GetProvider(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	if !provider.Enabled {
+		return nil, ErrOidcProviderNotFound
+	}
+
+	cfg := &models.OidcConfig{
+		Name:      provider.Name,
+		ClientID:  provider.ClientID,
+		IssuerURL: provider.IssuerURL,
+		Scopes:    provider.Scopes,
+	}
+	if provider.ClientSecret != "" {
+		secret, err := utils.Decrypt(provider.ClientSecret)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt OIDC client secret: %w", err)
+		}
+		cfg.ClientSecret = secret
+	}
+	return cfg, nil
+}
+
+func (s *OidcProviderService) setClientSecret(provider *models.OidcProvider, secret string) error {
+	if secret == "" {
+		provider.ClientSecret = ""
+		return nil
+	}
+	encrypted, err := utils.Encrypt(secret)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt OIDC client secret: %w", err)
+	}
+	provider.ClientSecret = encrypted
+	return nil
+}
+
+// auditOidcProviderSnapshot exposes the encrypted client secret, which the provider's
+// JSON omits, so that secret changes appear (redacted) in the audit diff.
+func auditOidcProviderSnapshot(provider *models.OidcProvider) any {
+	return struct {
+		*models.OidcProvider
+		ClientSecret string `json:"clientSecret,omitempty"`
+	}{provider, provider.ClientSecret}
+}
+
+var auditOidcProviderSensitive = []string{"clientSecret"}
+
+func validateOidcProvider(provider *models.OidcProvider) error {
+	switch {
+	case provider.Name == "":
+		return fmt.Errorf("%w: name is required", ErrInvalidOidcProvider)
+	case !strings.HasPrefix(provider.IssuerURL, "https://") && !strings.HasPrefix(provider.IssuerURL, "http://"):
+		return fmt.Errorf("%w: issuer URL must be an http(s) URL", ErrInvalidOidcProvider)
+	case provider.ClientID == "":
+		return fmt.Errorf("%w: client ID is required", ErrInvalidOidcProvider)
+	}
+	return nil
+}
+
+func (s *OidcProviderService) ListRoleMappings(ctx context.Context) ([]models.OidcRoleMapping, error) {
+	var mappings []models.OidcRoleMapping
+	if err := s.db.WithContext(ctx).Order("provider ASC, claim ASC, value ASC").Find(&mappings).Error; err != nil {
+		return nil, fmt.Errorf("failed to list OIDC role mappings: %w", err)
+	}
+	return mappings, nil
+}
+
+// RoleMappingsFor returns the mappings that apply to users of the provider.
+func (s *OidcProviderService) RoleMappingsFor(ctx context.Context, provider string) ([]models.OidcRoleMapping, error) {
+	var mappings []models.OidcRoleMapping
+	if err := s.db.WithContext(ctx).Where("provider = '' OR provider = ?", provider).Find(&mappings).Error; err != nil {
+		return nil, fmt.Errorf("failed to list OIDC role mappings: %w", err)
+	}
+	return mappings, nil
+}
+
+func (s *OidcProviderService) CreateRoleMapping(ctx context.Context, req dto.CreateOidcRoleMappingDto) (*models.OidcRoleMapping, error) {
+	mapping := &models.OidcRoleMapping{
+		Provider: strings.TrimSpace(req.Provider),
+		Claim:    strings.TrimSpace(req.Claim),
+		Value:    strings.TrimSpace(req.Value),
+		RoleName: strings.TrimSpace(req.RoleName),
+	}
+	if mapping.Claim == "" || mapping.Value == "" {
+		return nil, fmt.Errorf("%w: claim and value are required", ErrInvalidOidcRoleMapping)
+	}
+	if mapping.Provider != "" && mapping.Provider != models.OidcDefaultProvider {
+		if _, err := s.GetProvider(ctx, mapping.Provider); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidOidcRoleMapping, err)
+		}
+	}
+	role, err := s.roleService.GetRole(ctx, mapping.RoleName)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidOidcRoleMapping, err)
+	}
+	mapping.RoleName = role.Name
+
+	if err := s.db.WithContext(ctx).Create(mapping).Error; err != nil {
+		return nil, fmt.Errorf("failed to create OIDC role mapping: %w", err)
+	}
+
+	s.audit.Record(ctx, AuditChange{
+		Action:     auditActionCreate,
+		TargetType: auditTargetOidcRoleMapping,
+		TargetID:   mapping.ID,
+		TargetName: oidcRoleMappingName(mapping),
+		After:      mapping,
+	})
+	return mapping, nil
+}
+
+func (s *OidcProviderService) DeleteRoleMapping(ctx context.Context, id string) error {
+	var before models.OidcRoleMapping
+	if err := s.db.WithContext(ctx).Where("id = ?", id).First(&before).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrOidcRoleMappingNotFound
+		}
+		return fmt.Errorf("failed to get OIDC role mapping: %w", err)
+	}
+
+	result := s.db.WithContext(ctx).Where("id = ?", id).Delete(&models.OidcRoleMapping{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete OIDC role mapping: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrOidcRoleMappingNotFound
+	}
+
+	s.audit.Record(ctx, AuditChange{
+		Action:     auditActionDelete,
+		TargetType: auditTargetOidcRoleMapping,
+		TargetID:   id,
+		TargetName: oidcRoleMappingName(&before),
+		Before:     &before,
+	})
+	return nil
+}
+
+// oidcRoleMappingName describes a mapping as "claim=value -> role".
+func oidcRoleMappingName(mapping *models.OidcRoleMapping) string {
+	return fmt.Sprintf("%s=%s -> %s", mapping.Claim, mapping.Value, mapping.RoleName)
+}
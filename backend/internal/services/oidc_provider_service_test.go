@@ -0,0 +1,219 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ofkm/arcane-backend/internal/config"
+	"github.com/ofkm/arcane-backend/internal/dto"
+	"github.com/ofkm/arcane-backend/internal/models"
+	"github.com/ofkm/arcane-backend/internal/utils"
+	"github.com/ofkm/arcane-backend/internal/utils/pagination"
+)
+
+func TestOidcProviders_RoleMappingSync(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := setupTotpAuthService(t)
+	db := svc.userService.db
+	require.NoError(t, db.AutoMigrate(&models.Role{}, &models.OidcProvider{}, &models.OidcRoleMapping{}))
+	providers := NewOidcProviderService(db, NewRoleService(db, nil), nil)
+	svc.oidcProviders = providers
+
+	_, err := providers.CreateProvider(ctx, dto.CreateOidcProviderDto{Slug: "default", Name: "GitHub", IssuerURL: "https://github.example.com", ClientID: "id"})
+	require.ErrorIs(t, err, ErrInvalidOidcProvider)
+	github, err := providers.CreateProvider(ctx, dto.CreateOidcProviderDto{Slug: "GitHub", Name: "GitHub", IssuerURL: "https://github.example.com", ClientID: "id", ClientSecret: "s3cret", ManageDefaultRole: true})
+	require.NoError(t, err)
+	require.Equal(t, "github", github.Slug)
+	require.NotEqual(t, "s3cret", github.ClientSecret)
+	cfg, err := providers.GetProviderConfig(ctx, "github")
+	require.NoError(t, err)
+	require.Equal(t, "s3cret", cfg.ClientSecret)
+
+	_, err = providers.CreateRoleMapping(ctx, dto.CreateOidcRoleMappingDto{Claim: "groups", Value: "ops", RoleName: "missing"})
+	require.ErrorIs(t, err, ErrInvalidOidcRoleMapping)
+	_, err = providers.CreateRoleMapping(ctx, dto.CreateOidcRoleMappingDto{Provider: "github", Claim: "groups", Value: "contractors", RoleName: models.RoleDeployer})
+	require.NoError(t, err)
+	_, err = providers.CreateRoleMapping(ctx, dto.CreateOidcRoleMappingDto{Claim: "groups", Value: "ops", RoleName: models.RoleOperator})
+	require.NoError(t, err)
+
+	require.NoError(t, svc.settingsService.UpdateSetting(ctx, "authOidcManageDefaultRole", "true"))
+
+	login := func(provider, username string, groups ...any) *models.User {
+		t.Helper()
+		user, _, err := svc.OidcLogin(ctx, dto.OidcUserInfo{
+			Subject:           "42",
+			PreferredUsername: username,
+			Provider:          provider,
+			Extra:             map[string]any{"groups": groups},
+		}, nil)
+		require.NoError(t, err)
+		return user
+	}
+
+	contractor := login("github", "carol", "contractors")
+	require.Equal(t, "github|42", *contractor.OidcSubjectId)
+	require.ElementsMatch(t, []string{models.RoleDeployer}, contractor.Roles)
+
+	// Roles follow the groups on every login.
+	contractor = login("github", "carol", "ops")
+	require.ElementsMatch(t, []string{models.RoleOperator}, contractor.Roles)
+
+	// The same subject at another provider is another account, and the GitHub-only
+	// mapping does not apply to it.
+	employee := login("", "dave", "contractors", "ops")
+	require.NotEqual(t, contractor.ID, employee.ID)
+	require.Equal(t, "42", *employee.OidcSubjectId)
+	require.ElementsMatch(t, []string{models.RoleOperator}, employee.Roles)
+
+	require.NoError(t, providers.DeleteProvider(ctx, "github"))
+	mappings, err := providers.ListRoleMappings(ctx)
+	require.NoError(t, err)
+	require.Len(t, mappings, 1)
+	_, err = providers.GetProviderConfig(ctx, "github")
+	require.ErrorIs(t, err, ErrOidcProviderNotFound)
+}
+
+func TestOidcProviders_MappedUsersDoNotKeepDefaultRole(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := setupTotpAuthService(t)
+	db := svc.userService.db
+	require.NoError(t, db.AutoMigrate(&models.Role{}, &models.OidcProvider{}, &models.OidcRoleMapping{}))
+	svc.oidcProviders = NewOidcProviderService(db, NewRoleService(db, nil), nil)
+	require.NoError(t, svc.settingsService.UpdateSetting(ctx, "authOidcManageDefaultRole", "true"))
+
+	login := func(username string, groups ...any) *models.User {
+		t.Helper()
+		user, _, err := svc.OidcLogin(ctx, dto.OidcUserInfo{
+			Subject:           username,
+			PreferredUsername: username,
+			Extra:             map[string]any{"groups": groups},
+		}, nil)
+		require.NoError(t, err)
+		return user
+	}
+
+	// Without mappings every user gets the default role.
+	require.ElementsMatch(t, []string{models.RoleUser}, login("erin", "auditors").Roles)
+
+	_, err := svc.oidcProviders.CreateRoleMapping(ctx, dto.CreateOidcRoleMappingDto{Claim: "groups", Value: "auditors", RoleName: models.RoleViewer})
+	require.NoError(t, err)
+
+	require.ElementsMatch(t, []string{models.RoleViewer}, login("frank", "auditors").Roles)
+	require.ElementsMatch(t, []string{models.RoleViewer}, login("erin", "auditors").Roles, "existing users lose the default role on their next login")
+
+	_, err = svc.oidcProviders.CreateRoleMapping(ctx, dto.CreateOidcRoleMappingDto{Claim: "groups", Value: "staff", RoleName: models.RoleUser})
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{models.RoleViewer, models.RoleUser}, login("frank", "auditors", "staff").Roles)
+}
+
+func TestOidcProviders_GlobalMappingKeepsDefaultRoleUnlessManaged(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := setupTotpAuthService(t)
+	db := svc.userService.db
+	require.NoError(t, db.AutoMigrate(&models.Role{}, &models.OidcProvider{}, &models.OidcRoleMapping{}))
+	svc.oidcProviders = NewOidcProviderService(db, NewRoleService(db, nil), nil)
+
+	_, err := svc.oidcProviders.CreateProvider(ctx, dto.CreateOidcProviderDto{Slug: "gitlab", Name: "GitLab", IssuerURL: "https://gitlab.example.com", ClientID: "id"})
+	require.NoError(t, err)
+	// A mapping for every provider, e.g. added with only the default provider in mind.
+	_, err = svc.oidcProviders.CreateRoleMapping(ctx, dto.CreateOidcRoleMappingDto{Claim: "groups", Value: "auditors", RoleName: models.RoleViewer})
+	require.NoError(t, err)
+
+	login := func(provider, username string, groups ...any) *models.User {
+		t.Helper()
+		user, _, err := svc.OidcLogin(ctx, dto.OidcUserInfo{
+			Subject:           username,
+			PreferredUsername: username,
+			Provider:          provider,
+			Extra:             map[string]any{"groups": groups},
+		}, nil)
+		require.NoError(t, err)
+		return user
+	}
+
+	require.ElementsMatch(t, []string{models.RoleUser}, login("gitlab", "grace", "developers").Roles)
+	require.ElementsMatch(t, []string{models.RoleUser}, login("", "heidi", "developers").Roles)
+	require.ElementsMatch(t, []string{models.RoleUser, models.RoleViewer}, login("gitlab", "ivan", "auditors").Roles)
+
+	manage := true
+	_, err = svc.oidcProviders.UpdateProvider(ctx, "gitlab", dto.UpdateOidcProviderDto{ManageDefaultRole: &manage})
+	require.NoError(t, err)
+	require.Empty(t, login("gitlab", "grace", "developers").Roles)
+	require.ElementsMatch(t, []string{models.RoleUser}, login("", "heidi", "developers").Roles, "the default provider is managed separately")
+}
+
+func TestOidcProviders_OnlyDefaultProviderMergesAccounts(t *testing.T) {
+	ctx := context.Background()
+	svc, alice := setupTotpAuthService(t)
+	db := svc.userService.db
+	require.NoError(t, db.AutoMigrate(&models.Role{}, &models.OidcProvider{}, &models.OidcRoleMapping{}))
+	svc.oidcProviders = NewOidcProviderService(db, NewRoleService(db, nil), nil)
+	require.NoError(t, svc.settingsService.UpdateSetting(ctx, "authOidcMergeAccounts", "true"))
+
+	localUser := func(u *models.User, email string) {
+		t.Helper()
+		u.Email = &email
+		_, err := svc.userService.UpdateUser(ctx, u)
+		require.NoError(t, err)
+	}
+	login := func(provider, email string) (*models.User, error) {
+		user, _, err := svc.OidcLogin(ctx, dto.OidcUserInfo{
+			Subject:           "sub-" + email,
+			PreferredUsername: provider + "-" + email,
+			Email:             email,
+			EmailVerified:     true,
+			Provider:          provider,
+		}, nil)
+		return user, err
+	}
+
+	localUser(alice, "alice@example.com")
+	user, err := login("github", "alice@example.com")
+	if err == nil {
+		require.NotEqual(t, alice.ID, user.ID, "an additional provider must not merge into a local account")
+	}
+	reloaded, err := svc.userService.GetUserByID(ctx, alice.ID)
+	require.NoError(t, err)
+	require.Nil(t, reloaded.OidcSubjectId)
+
+	bob, err := svc.userService.CreateUser(ctx, &models.User{Username: "bob"})
+	require.NoError(t, err)
+	localUser(bob, "bob@example.com")
+	user, err = login("", "bob@example.com")
+	require.NoError(t, err)
+	require.Equal(t, bob.ID, user.ID, "the default provider merges by verified email")
+}
+
+func TestOidcProviders_AuditsChanges(t *testing.T) {
+	ctx := auditTestContext()
+	utils.InitEncryption(&config.Config{})
+	audit := setupAuditService(t)
+	require.NoError(t, audit.db.AutoMigrate(&models.Role{}, &models.OidcProvider{}, &models.OidcRoleMapping{}))
+	providers := NewOidcProviderService(audit.db, NewRoleService(audit.db, nil), audit)
+
+	_, err := providers.CreateProvider(ctx, dto.CreateOidcProviderDto{Slug: "github", Name: "GitHub", IssuerURL: "https://github.example.com", ClientID: "id", ClientSecret: "s3cret"})
+	require.NoError(t, err)
+	secret := "rotated"
+	_, err = providers.UpdateProvider(ctx, "github", dto.UpdateOidcProviderDto{ClientSecret: &secret})
+	require.NoError(t, err)
+	mapping, err := providers.CreateRoleMapping(ctx, dto.CreateOidcRoleMappingDto{Provider: "github", Claim: "groups", Value: "ops", RoleName: models.RoleOperator})
+	require.NoError(t, err)
+	require.NoError(t, providers.DeleteRoleMapping(ctx, mapping.ID))
+	require.NoError(t, providers.DeleteProvider(ctx, "github"))
+
+	entries, _, err := audit.ListAuditLogsPaginated(ctx, pagination.QueryParams{})
+	require.NoError(t, err)
+	byAction := map[string]models.AuditLog{}
+	for _, e := range entries {
+		byAction[e.Action] = e
+	}
+	require.Len(t, byAction, 5)
+
+	require.Equal(t, map[string]any{"after": models.RedactionMask}, byAction["oidc_provider.create"].Changes["clientSecret"])
+	require.Equal(t, map[string]any{"before": models.RedactionMask, "after": models.RedactionMask}, byAction["oidc_provider.update"].Changes["clientSecret"])
+	require.Equal(t, "github", *byAction["oidc_provider.delete"].TargetName)
+	require.Equal(t, "groups=ops -> operator", *byAction["oidc_role_mapping.create"].TargetName)
+	require.Equal(t, map[string]any{"before": models.RoleOperator}, byAction["oidc_role_mapping.delete"].Changes["roleName"])
+}
@@ -24,11 +24,12 @@ import (
 
 type OidcService struct {
 	authService   *AuthService
+	providers     *OidcProviderService
 	config        *config.Config
 	httpClient    *http.Client
-	providerCache *oidc.Provider
 	providerMutex sync.RWMutex
-	cachedIssuer  string
+	// issuer URL -> discovered provider
+	providerCache map[string]*oidc.Provider
 }
 
 type OidcState struct {
@@ -36,21 +37,33 @@ type OidcState struct {
 	Nonce        string    `json:"nonce"`
 	CodeVerifier string    `json:"code_verifier"`
 	RedirectTo   string    `json:"redirect_to"`
+	Provider     string    `json:"provider,omitempty"`
 	CreatedAt    time.Time `json:"created_at"`
 }
 
-func NewOidcService(authService *AuthService, cfg *config.Config, httpClient *http.Client) *OidcService {
+func NewOidcService(authService *AuthService, providers *OidcProviderService, cfg *config.Config, httpClient *http.Client) *OidcService {
 	if httpClient == nil {
 		httpClient = http.DefaultClient
 	}
 	return &OidcService{
-		authService: authService,
-		config:      cfg,
-		httpClient:  httpClient,
+		authService:   authService,
+		providers:     providers,
+		config:        cfg,
+		httpClient:    httpClient,
+		providerCache: map[string]*oidc.Provider{},
 	}
 }
 
-func (s *OidcService) getEffectiveConfig(ctx context.Context) (*models.OidcConfig, error) {
+// getEffectiveConfig returns the configuration of the provider with the given slug; an
+// empty slug selects the default provider from the settings.
+func (s *OidcService) getEffectiveConfig(ctx context.Context, provider string) (*models.OidcConfig, error) {
+	if provider != "" && provider != models.OidcDefaultProvider {
+		if s.providers == nil {
+			return nil, ErrOidcProviderNotFound
+		}
+		return s.providers.GetProviderConfig(ctx, provider)
+	}
+
 	config, err := s.authService.GetOidcConfig(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get OIDC config: %w", err)
@@ -75,8 +88,31 @@ func (s *OidcService) ensureOpenIDScope(scopes []string) []string {
 	return scopes
 }
 
-func (s *OidcService) GenerateAuthURL(ctx context.Context, redirectTo string) (string, string, error) {
-	config, err := s.getEffectiveConfig(ctx)
+// ListLoginProviders returns the providers to offer on the login page: the default
+// provider if it is configured, followed by the enabled additional providers.
+func (s *OidcService) ListLoginProviders(ctx context.Context) ([]dto.OidcLoginProviderDto, error) {
+	var out []dto.OidcLoginProviderDto
+	if cfg, err := s.getEffectiveConfig(ctx, ""); err == nil {
+		out = append(out, dto.OidcLoginProviderDto{Slug: models.OidcDefaultProvider, Name: cfg.Name})
+	}
+	if s.providers == nil {
+		return out, nil
+	}
+
+	providers, err := s.providers.ListProviders(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range providers {
+		if p.Enabled {
+			out = append(out, dto.OidcLoginProviderDto{Slug: p.Slug, Name: p.Name})
+		}
+	}
+	return out, nil
+}
+
+func (s *OidcService) GenerateAuthURL(ctx context.Context, providerSlug, redirectTo string) (string, string, error) {
+	config, err := s.getEffectiveConfig(ctx, providerSlug)
 	if err != nil {
 		slog.Error("GenerateAuthURL: failed to get OIDC config", "error", err)
 		return "", "", err
@@ -116,6 +152,7 @@ func (s *OidcService) GenerateAuthURL(ctx context.Context, redirectTo string) (s
 		Nonce:        nonce,
 		CodeVerifier: codeVerifier,
 		RedirectTo:   redirectTo,
+		Provider:     providerSlug,
 		CreatedAt:    time.Now(),
 	}
 
@@ -137,8 +174,7 @@ func (s *OidcService) GetOidcRedirectURL() string {
 
 func (s *OidcService) getOrDiscoverProvider(ctx context.Context, issuer string) (*oidc.Provider, error) {
 	s.providerMutex.RLock()
-	if s.providerCache != nil && s.cachedIssuer == issuer {
-		provider := s.providerCache
+	if provider, ok := s.providerCache[issuer]; ok {
 		s.providerMutex.RUnlock()
 		return provider, nil
 	}
@@ -147,8 +183,8 @@ func (s *OidcService) getOrDiscoverProvider(ctx context.Context, issuer string)
 	s.providerMutex.Lock()
 	defer s.providerMutex.Unlock()
 
-	if s.providerCache != nil && s.cachedIssuer == issuer {
-		return s.providerCache, nil
+	if provider, ok := s.providerCache[issuer]; ok {
+		return provider, nil
 	}
 
 	providerCtx := oidc.ClientContext(ctx, s.httpClient)
@@ -158,8 +194,7 @@ func (s *OidcService) getOrDiscoverProvider(ctx context.Context, issuer string)
 		return nil, fmt.Errorf("failed to discover provider at %s: %w", issuer, err)
 	}
 
-	s.providerCache = provider
-	s.cachedIssuer = issuer
+	s.providerCache[issuer] = provider
 	slog.Debug("getOrDiscoverProvider: provider cached", "issuer", issuer)
 
 	return provider, nil
@@ -254,9 +289,9 @@ func (s *OidcService) HandleCallback(ctx context.Context, code, state, storedSta
 		return nil, nil, errors.New("authentication state has expired")
 	}
 
-	cfg, err := s.getEffectiveConfig(ctx)
+	cfg, err := s.getEffectiveConfig(ctx, stateData.Provider)
 	if err != nil {
-		slog.Error("HandleCallback: failed to get OIDC config", "error", err)
+		slog.Error("HandleCallback: failed to get OIDC config", "provider", stateData.Provider, "error", err)
 		return nil, nil, err
 	}
 
@@ -339,6 +374,7 @@ func (s *OidcService) HandleCallback(ctx context.Context, code, state, storedSta
 		Roles:             utils.GetStringSliceClaim(claims, "roles"),
 		Groups:            utils.GetStringSliceClaim(claims, "groups"),
 		Extra:             claims,
+		Provider:          stateData.Provider,
 	}
 
 	tokenType := token.TokenType
@@ -369,7 +405,7 @@ func (s *OidcService) RefreshToken(ctx context.Context, refreshToken string) (*d
 		return nil, errors.New("refresh token is required")
 	}
 
-	cfg, err := s.getEffectiveConfig(ctx)
+	cfg, err := s.getEffectiveConfig(ctx, "")
 	if err != nil {
 		slog.Error("RefreshToken: failed to get OIDC config", "error", err)
 		return nil, err
@@ -0,0 +1,253 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	composetypes "github.com/compose-spec/compose-go/v2/types"
+	"github.com/ofkm/arcane-backend/internal/models"
+	"github.com/ofkm/arcane-backend/internal/utils/projects"
+	"gorm.io/gorm"
+)
+
+// deploymentNumberAttempts bounds how often recordDeployment retries when another
+// deployment of the same project took the number it picked.
+const deploymentNumberAttempts = 5
+
+var (
+	ErrProjectDeploymentNotFound = errors.New("project deployment not found")
+	ErrInvalidRollbackTarget     = errors.New("only successful deployments can be rolled back to")
+)
+
+// deploymentSource describes what started a deployment. Callers that deploy on behalf of
+// something other than the user passed to DeployProject (webhooks, git sync, rollbacks)
+// attach one to the context.
+type deploymentSource struct {
+	trigger    models.DeploymentTrigger
+	detail     string
+	user       *models.User
+	rollbackOf string
+}
+
+type deploymentSourceKey struct{}
+
+func withDeploymentSource(ctx context.Context, source deploymentSource) context.Context {
+	return context.WithValue(ctx, deploymentSourceKey{}, source)
+}
+
+// deploymentSourceFor returns the source attached to ctx, or one naming user.
+func deploymentSourceFor(ctx context.Context, user models.User) deploymentSource {
+	source, ok := ctx.Value(deploymentSourceKey{}).(deploymentSource)
+	if !ok {
+		source = deploymentSource{trigger: models.DeploymentTriggerUser}
+	}
+	if source.user == nil {
+		source.user = &user
+	}
+	if source.trigger == models.DeploymentTriggerUser && source.user.ID == "" {
+		source.trigger = models.DeploymentTriggerSystem
+	}
+	return source
+}
+
+// hash identifies the deployed file contents, so deployments of identical files can be
+// recognised even when no revision was recorded in between.
+func (snapshot projectFilesSnapshot) hash() string {
+	h := sha256.New()
+	h.Write([]byte(snapshot.composeContent))
+	h.Write([]byte{0})
+	h.Write([]byte(snapshot.envContent))
+	paths := make([]string, 0, len(snapshot.includeFiles))
+	for path := range snapshot.includeFiles {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		h.Write([]byte{0})
+		h.Write([]byte(path))
+		h.Write([]byte{0})
+		h.Write([]byte(snapshot.includeFiles[path]))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// pinComposeImages replaces service images with the given references.
+func pinComposeImages(project *composetypes.Project, pinned map[string]string) {
+	for name, image := range pinned {
+		if svc, ok := project.Services[name]; ok {
+			svc.Image = image
+			project.Services[name] = svc
+		}
+	}
+}
+
+// recordDeployment stores the outcome of a deployment. The files deployed are linked to
+// a revision, recording one first if they were changed outside Arcane, and the image
+// each service container runs is resolved to its digest. Failures to record are logged;
+// they never change the result of the deployment itself.
+func (s *ProjectService) recordDeployment(ctx context.Context, proj *models.Project, project *composetypes.Project, user models.User, started time.Time, deployErr error) *models.ProjectDeployment {
+	source := deploymentSourceFor(ctx, user)
+	finished := time.Now()
+
+	deployment := &models.ProjectDeployment{
+		ProjectID:   proj.ID,
+		ComposeHash: readProjectFilesSnapshot(proj).hash(),
+		Trigger:     source.trigger,
+		Status:      models.DeploymentStatusSuccess,
+		StartedAt:   started,
+		FinishedAt:  finished,
+		DurationMs:  finished.Sub(started).Milliseconds(),
+	}
+	if source.detail != "" {
+		deployment.TriggerDetail = &source.detail
+	}
+	if source.rollbackOf != "" {
+		deployment.RollbackOf = &source.rollbackOf
+	}
+	if source.user.ID != "" {
+		deployment.UserID = &source.user.ID
+		deployment.Username = &source.user.Username
+	}
+	if deployErr != nil {
+		msg := deployErr.Error()
+		deployment.Status = models.DeploymentStatusFailed
+		deployment.Error = &msg
+	}
+
+	if rev, err := s.recordProjectRevision(ctx, proj, nil, "Changed outside Arcane"); err == nil {
+		deployment.RevisionID = &rev.ID
+	} else {
+		slog.WarnContext(ctx, "Failed to record revision for deployment", "projectID", proj.ID, "error", err)
+	}
+	if project != nil {
+		deployment.Images = s.deployedImages(ctx, project)
+	}
+
+	// Numbers are unique per project. Deployments finishing at the same moment can read
+	// the same latest number; the one whose insert loses takes the next number.
+	for attempt := 1; ; attempt++ {
+		latest := 0
+		if err := s.db.WithContext(ctx).Model(&models.ProjectDeployment{}).
+			Where("project_id = ?", proj.ID).
+			Select("COALESCE(MAX(number), 0)").
+			Scan(&latest).Error; err != nil {
+			slog.ErrorContext(ctx, "Failed to number project deployment", "projectID", proj.ID, "error", err)
+			return nil
+		}
+		deployment.Number = latest + 1
+
+		err := s.db.WithContext(ctx).Create(deployment).Error
+		if err == nil {
+			return deployment
+		}
+		if attempt < deploymentNumberAttempts && isUniqueViolation(err) {
+			continue
+		}
+		slog.ErrorContext(ctx, "Failed to record project deployment", "projectID", proj.ID, "error", err)
+		return nil
+	}
+}
+
+// isUniqueViolation reports whether err is a unique constraint violation on SQLite or
+// PostgreSQL.
+func isUniqueViolation(err error) bool {
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "unique constraint") || strings.Contains(msg, "duplicate key")
+}
+
+// deployedImages resolves the image digest of every container compose reports for the
+// project. Services without a container (failed or scaled to zero) are left out.
+func (s *ProjectService) deployedImages(ctx context.Context, project *composetypes.Project) models.DeploymentImages {
+	if s.imageService == nil {
+		return nil
+	}
+	containers, err := projects.ComposePs(ctx, project, nil, true)
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to list containers for deployment images", "project", project.Name, "error", err)
+		return nil
+	}
+
+	images := models.DeploymentImages{}
+	for _, c := range containers {
+		if _, seen := images[c.Service]; seen || c.Service == "" {
+			continue
+		}
+		image, err := s.imageService.ContainerImageDigest(ctx, c.ID)
+		if err != nil {
+			slog.WarnContext(ctx, "Failed to resolve deployed image digest", "project", project.Name, "service", c.Service, "error", err)
+			continue
+		}
+		images[c.Service] = image
+	}
+	return images
+}
+
+func (s *ProjectService) ListProjectDeployments(ctx context.Context, projectID string) ([]models.ProjectDeployment, error) {
+	if _, err := s.GetProjectFromDatabaseByID(ctx, projectID); err != nil {
+		return nil, err
+	}
+
+	var deployments []models.ProjectDeployment
+	if err := s.db.WithContext(ctx).Where("project_id = ?", projectID).Order("number DESC").Find(&deployments).Error; err != nil {
+		return nil, fmt.Errorf("failed to list project deployments: %w", err)
+	}
+	return deployments, nil
+}
+
+func (s *ProjectService) GetProjectDeployment(ctx context.Context, projectID, deploymentID string) (*models.ProjectDeployment, error) {
+	var deployment models.ProjectDeployment
+	err := s.db.WithContext(ctx).Where("project_id = ? AND id = ?", projectID, deploymentID).First(&deployment).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrProjectDeploymentNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project deployment: %w", err)
+	}
+	return &deployment, nil
+}
+
+// RollbackProjectDeployment restores the files of an earlier successful deployment and
+// deploys them with every service pinned to the image digest it ran then, so a tag that
+// has since moved (for example a bad latest) is not pulled again. The rollback is
+// recorded as a new deployment.
+func (s *ProjectService) RollbackProjectDeployment(ctx context.Context, projectID, deploymentID string, user models.User) (*models.ProjectDeployment, error) {
+	target, err := s.GetProjectDeployment(ctx, projectID, deploymentID)
+	if err != nil {
+		return nil, err
+	}
+	if target.Status != models.DeploymentStatusSuccess {
+		return nil, ErrInvalidRollbackTarget
+	}
+
+	if target.RevisionID != nil {
+		if _, err := s.RestoreProjectRevision(ctx, projectID, *target.RevisionID, false, user); err != nil {
+			return nil, fmt.Errorf("failed to restore files of deployment %d: %w", target.Number, err)
+		}
+	}
+
+	pinned := make(map[string]string, len(target.Images))
+	for service, image := range target.Images {
+		if image.Pinned != "" {
+			pinned[service] = image.Pinned
+		}
+	}
+
+	ctx = withDeploymentSource(ctx, deploymentSource{
+		trigger:    models.DeploymentTriggerRollback,
+		detail:     fmt.Sprintf("Rollback to deployment %d", target.Number),
+		user:       &user,
+		rollbackOf: target.ID,
+	})
+	deployment, err := s.deployProject(ctx, projectID, user, pinned)
+	if err != nil {
+		return deployment, fmt.Errorf("rollback to deployment %d failed: %w", target.Number, err)
+	}
+	return deployment, nil
+}
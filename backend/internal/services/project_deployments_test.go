@@ -0,0 +1,107 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	composetypes "github.com/compose-spec/compose-go/v2/types"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/ofkm/arcane-backend/internal/models"
+)
+
+func TestPickRepoDigest(t *testing.T) {
+	mirror := "mirror.example.com/library/nginx@sha256:" + strings.Repeat("a", 64)
+	hub := "nginx@sha256:" + strings.Repeat("b", 64)
+	digests := []string{mirror, hub}
+	require.Equal(t, hub, pickRepoDigest("nginx:latest", digests, "sha256:id"))
+	require.Equal(t, hub, pickRepoDigest("docker.io/library/nginx", digests, "sha256:id"))
+	require.Equal(t, mirror, pickRepoDigest("redis:7", digests, "sha256:id"))
+	require.Equal(t, "sha256:id", pickRepoDigest("local/build:dev", nil, "sha256:id"))
+}
+
+func TestPinComposeImages(t *testing.T) {
+	project := &composetypes.Project{Services: composetypes.Services{
+		"web": {Name: "web", Image: "nginx:latest"},
+		"db":  {Name: "db", Image: "postgres:16"},
+	}}
+	pinComposeImages(project, map[string]string{"web": "nginx@sha256:bbb", "gone": "redis@sha256:ccc"})
+
+	require.Equal(t, "nginx@sha256:bbb", project.Services["web"].Image)
+	require.Equal(t, "postgres:16", project.Services["db"].Image)
+	require.NotContains(t, project.Services, "gone")
+}
+
+func TestProjectDeployments_RecordsHistory(t *testing.T) {
+	ctx := context.Background()
+	svc, proj := setupProjectRevisionService(t)
+	require.NoError(t, svc.db.AutoMigrate(&models.ProjectDeployment{}))
+	alice := models.User{BaseModel: models.BaseModel{ID: "u1"}, Username: "alice"}
+
+	started := time.Now().Add(-2 * time.Second)
+	first := svc.recordDeployment(ctx, proj, nil, alice, started, nil)
+	require.NotNil(t, first)
+	require.Equal(t, 1, first.Number)
+	require.Equal(t, models.DeploymentTriggerUser, first.Trigger)
+	require.Equal(t, "alice", *first.Username)
+	require.Equal(t, models.DeploymentStatusSuccess, first.Status)
+	require.GreaterOrEqual(t, first.DurationMs, int64(2000))
+	require.NotNil(t, first.RevisionID, "files found on disk are recorded as a revision")
+
+	webhookCtx := withDeploymentSource(ctx, deploymentSource{trigger: models.DeploymentTriggerWebhook, detail: "ci"})
+	second := svc.recordDeployment(webhookCtx, proj, nil, systemUser, time.Now(), errors.New("compose up failed"))
+	require.NotNil(t, second)
+	require.Equal(t, 2, second.Number)
+	require.Equal(t, models.DeploymentTriggerWebhook, second.Trigger)
+	require.Equal(t, "ci", *second.TriggerDetail)
+	require.Nil(t, second.UserID)
+	require.Equal(t, models.DeploymentStatusFailed, second.Status)
+	require.Equal(t, first.ComposeHash, second.ComposeHash)
+	require.Equal(t, *first.RevisionID, *second.RevisionID, "unchanged files reuse the revision")
+
+	system := svc.recordDeployment(ctx, proj, nil, systemUser, time.Now(), nil)
+	require.Equal(t, models.DeploymentTriggerSystem, system.Trigger)
+
+	deployments, err := svc.ListProjectDeployments(ctx, proj.ID)
+	require.NoError(t, err)
+	require.Len(t, deployments, 3)
+	require.Equal(t, 3, deployments[0].Number)
+
+	_, err = svc.RollbackProjectDeployment(ctx, proj.ID, second.ID, alice)
+	require.ErrorIs(t, err, ErrInvalidRollbackTarget)
+	_, err = svc.RollbackProjectDeployment(ctx, proj.ID, "missing", alice)
+	require.ErrorIs(t, err, ErrProjectDeploymentNotFound)
+}
+
+func TestProjectDeployments_NumberTakenConcurrentlyIsRetried(t *testing.T) {
+	ctx := context.Background()
+	svc, proj := setupProjectRevisionService(t)
+	require.NoError(t, svc.db.AutoMigrate(&models.ProjectDeployment{}))
+
+	// Another deployment of the project is stored with number 1 between this one reading
+	// the latest number and inserting. Without the default transaction around the insert,
+	// the other deployment is committed first.
+	svc.db.DB = svc.db.Session(&gorm.Session{SkipDefaultTransaction: true})
+	raced := false
+	require.NoError(t, svc.db.Callback().Create().Before("gorm:create").Register("test:race", func(tx *gorm.DB) {
+		if _, ok := tx.Statement.Dest.(*models.ProjectDeployment); !ok || raced {
+			return
+		}
+		raced = true
+		other := &models.ProjectDeployment{ProjectID: proj.ID, Number: 1, Status: models.DeploymentStatusSuccess}
+		require.NoError(t, tx.Session(&gorm.Session{NewDB: true}).Create(other).Error)
+	}))
+
+	deployment := svc.recordDeployment(ctx, proj, nil, systemUser, time.Now(), nil)
+	require.True(t, raced)
+	require.NotNil(t, deployment, "the deployment is recorded despite the conflict")
+	require.Equal(t, 2, deployment.Number)
+
+	deployments, err := svc.ListProjectDeployments(ctx, proj.ID)
+	require.NoError(t, err)
+	require.Len(t, deployments, 2)
+}
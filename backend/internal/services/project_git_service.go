@@ -0,0 +1,475 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/ofkm/arcane-backend/internal/database"
+	"github.com/ofkm/arcane-backend/internal/dto"
+	"github.com/ofkm/arcane-backend/internal/models"
+	"github.com/ofkm/arcane-backend/internal/utils"
+	"github.com/ofkm/arcane-backend/internal/utils/fs"
+	"github.com/ofkm/arcane-backend/internal/utils/git"
+	"github.com/ofkm/arcane-backend/internal/utils/projects"
+)
+
+var (
+	ErrGitSourceNotFound = errors.New("project is not backed by a git repository")
+	ErrGitSyncInProgress = errors.New("a git sync is already running for this project")
+	ErrInvalidGitSource  = errors.New("invalid git source")
+)
+
+const (
+	defaultGitSyncInterval = 5 // minutes
+	// Diffs are returned to the UI and stored in events, so keep them bounded.
+	maxGitDiffLength = 64 * 1024
+)
+
+// validateGitRepositoryURL is a variable so tests can clone local fixture repositories.
+var validateGitRepositoryURL = git.ValidateURL
+
+type ProjectGitService struct {
+	db              *database.DB
+	projectService  *ProjectService
+	settingsService *SettingsService
+	eventService    *EventService
+
+	syncing sync.Map // projectID -> struct{}
+}
+
+func NewProjectGitService(db *database.DB, projectService *ProjectService, settingsService *SettingsService, eventService *EventService) *ProjectGitService {
+	return &ProjectGitService{
+		db:              db,
+		projectService:  projectService,
+		settingsService: settingsService,
+		eventService:    eventService,
+	}
+}
+
+// CreateGitProject clones the repository into a new folder under the projects directory
+// and registers a project pointing at the compose file in SubPath.
+func (s *ProjectGitService) CreateGitProject(ctx context.Context, req dto.CreateGitProjectDto, user models.User) (*models.Project, *models.ProjectGitSource, error) {
+	repoURL := strings.TrimSpace(req.RepositoryURL)
+	if repoURL == "" {
+		return nil, nil, fmt.Errorf("%w: repository URL is required", ErrInvalidGitSource)
+	}
+	if err := validateGitRepositoryURL(repoURL); err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", ErrInvalidGitSource, err)
+	}
+	ref := strings.TrimSpace(req.Ref)
+	if err := git.ValidateRef(ctx, ref); err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", ErrInvalidGitSource, err)
+	}
+	subPath, err := cleanGitSubPath(req.SubPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	authType, err := parseGitAuthType(req.AuthType, req.Credential)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	src := &models.ProjectGitSource{
+		RepositoryURL: repoURL,
+		Ref:           ref,
+		SubPath:       subPath,
+		AuthType:      authType,
+		Username:      trimmedOrNil(req.Username),
+		AutoSync:      req.AutoSync != nil && *req.AutoSync,
+		SyncInterval:  defaultGitSyncInterval,
+		AutoDeploy:    req.AutoDeploy == nil || *req.AutoDeploy,
+	}
+	if req.SyncInterval != nil {
+		if *req.SyncInterval < 1 {
+			return nil, nil, fmt.Errorf("%w: sync interval must be at least 1 minute", ErrInvalidGitSource)
+		}
+		src.SyncInterval = *req.SyncInterval
+	}
+	if authType != models.GitAuthNone && req.Credential != nil {
+		encrypted, err := utils.Encrypt(*req.Credential)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to encrypt git credential: %w", err)
+		}
+		src.Credential = encrypted
+	}
+
+	projectsDirectory, err := fs.GetProjectsDirectory(ctx, s.settingsService.GetStringSetting(ctx, "projectsDirectory", "data/projects"))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get projects directory: %w", err)
+	}
+
+	basePath := filepath.Join(projectsDirectory, fs.SanitizeProjectName(req.Name))
+	checkoutPath, folderName, err := fs.CreateUniqueDir(projectsDirectory, basePath, req.Name, 0755)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create project directory: %w", err)
+	}
+	src.CheckoutPath = checkoutPath
+
+	cleanup := func() {
+		if rmErr := os.RemoveAll(checkoutPath); rmErr != nil {
+			slog.WarnContext(ctx, "failed to clean up git checkout", "path", checkoutPath, "error", rmErr)
+		}
+	}
+
+	auth, err := s.gitAuth(src)
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	if err := git.Clone(ctx, repoURL, src.Ref, checkoutPath, auth); err != nil {
+		cleanup()
+		return nil, nil, fmt.Errorf("failed to clone repository: %w", err)
+	}
+
+	projectPath := filepath.Join(checkoutPath, subPath)
+	if !fs.IsSafeSubdirectory(checkoutPath, projectPath) {
+		cleanup()
+		return nil, nil, fmt.Errorf("%w: sub-path escapes the repository", ErrInvalidGitSource)
+	}
+	if _, err := projects.DetectComposeFile(projectPath); err != nil {
+		cleanup()
+		return nil, nil, fmt.Errorf("%w: no compose file found in %q", ErrInvalidGitSource, subPath)
+	}
+
+	commit, err := git.Head(ctx, checkoutPath)
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	now := time.Now()
+	src.LastCommit = &commit
+	src.LastSyncAt = &now
+
+	proj := &models.Project{
+		Name:         req.Name,
+		DirName:      &folderName,
+		Path:         projectPath,
+		Status:       models.ProjectStatusStopped,
+		ServiceCount: 0,
+		RunningCount: 0,
+	}
+
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(proj).Error; err != nil {
+			return fmt.Errorf("failed to create project: %w", err)
+		}
+		src.ProjectID = proj.ID
+		if err := tx.Create(src).Error; err != nil {
+			return fmt.Errorf("failed to save git source: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+
+	metadata := models.JSON{"action": "create", "source": "git", "projectID": proj.ID, "projectName": proj.Name, "repository": repoURL, "ref": src.Ref, "commit": commit}
+	if logErr := s.eventService.LogProjectEvent(ctx, models.EventTypeProjectCreate, proj.ID, proj.Name, user.ID, user.Username, "0", metadata); logErr != nil {
+		slog.ErrorContext(ctx, "could not log git project creation", "error", logErr)
+	}
+
+	return proj, src, nil
+}
+
+func (s *ProjectGitService) GetSource(ctx context.Context, projectID string) (*models.ProjectGitSource, error) {
+	var src models.ProjectGitSource
+	if err := s.db.WithContext(ctx).Where("project_id = ?", projectID).First(&src).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrGitSourceNotFound
+		}
+		return nil, fmt.Errorf("failed to get git source: %w", err)
+	}
+	return &src, nil
+}
+
+func (s *ProjectGitService) UpdateSource(ctx context.Context, projectID string, req dto.UpdateGitSourceDto) (*models.ProjectGitSource, error) {
+	src, err := s.GetSource(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Ref != nil {
+		ref := strings.TrimSpace(*req.Ref)
+		if err := git.ValidateRef(ctx, ref); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidGitSource, err)
+		}
+		src.Ref = ref
+	}
+	if req.Username != nil {
+		src.Username = trimmedOrNil(req.Username)
+	}
+	if req.AuthType != nil || req.Credential != nil {
+		current := string(src.AuthType)
+		if req.AuthType != nil {
+			current = *req.AuthType
+		}
+		authType, err := parseGitAuthType(current, req.Credential)
+		if err != nil {
+			return nil, err
+		}
+		src.AuthType = authType
+		switch {
+		case authType == models.GitAuthNone:
+			src.Credential = ""
+		case req.Credential != nil:
+			encrypted, err := utils.Encrypt(*req.Credential)
+			if err != nil {
+				return nil, fmt.Errorf("failed to encrypt git credential: %w", err)
+			}
+			src.Credential = encrypted
+		}
+	}
+	if req.AutoSync != nil {
+		src.AutoSync = *req.AutoSync
+	}
+	if req.SyncInterval != nil {
+		if *req.SyncInterval < 1 {
+			return nil, fmt.Errorf("%w: sync interval must be at least 1 minute", ErrInvalidGitSource)
+		}
+		src.SyncInterval = *req.SyncInterval
+	}
+	if req.AutoDeploy != nil {
+		src.AutoDeploy = *req.AutoDeploy
+	}
+
+	if err := s.db.WithContext(ctx).Save(src).Error; err != nil {
+		return nil, fmt.Errorf("failed to update git source: %w", err)
+	}
+	return src, nil
+}
+
+// DeleteSource detaches the project from its repository. The checkout stays on disk and
+// the project continues to work as a regular folder-based project.
+func (s *ProjectGitService) DeleteSource(ctx context.Context, projectID string) error {
+	result := s.db.WithContext(ctx).Where("project_id = ?", projectID).Delete(&models.ProjectGitSource{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete git source: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrGitSourceNotFound
+	}
+	return nil
+}
+
+// SyncProject fetches the configured ref, moves the checkout to it and, when files under
+// the project's sub-path changed and AutoDeploy is enabled, redeploys the project.
+// Local edits to files tracked by the repository are discarded.
+func (s *ProjectGitService) SyncProject(ctx context.Context, projectID string, user models.User) (*dto.GitSyncResultDto, error) {
+	if _, busy := s.syncing.LoadOrStore(projectID, struct{}{}); busy {
+		return nil, ErrGitSyncInProgress
+	}
+	defer s.syncing.Delete(projectID)
+
+	src, err := s.GetSource(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	proj, err := s.projectService.GetProjectFromDatabaseByID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.pullChanges(ctx, src)
+	if err != nil {
+		s.recordSync(ctx, src, nil, err)
+		s.eventService.LogErrorEvent(ctx, models.EventTypeProjectError, "project", proj.ID, proj.Name, user.ID, user.Username, "0", err, models.JSON{"action": "git_sync", "repository": src.RepositoryURL})
+		return nil, err
+	}
+	s.recordSync(ctx, src, &result.Commit, nil)
+
+	if !result.Changed {
+		return result, nil
+	}
+
+	metadata := models.JSON{
+		"action":         "git_sync",
+		"projectID":      proj.ID,
+		"projectName":    proj.Name,
+		"repository":     src.RepositoryURL,
+		"previousCommit": result.PreviousCommit,
+		"commit":         result.Commit,
+		"changedFiles":   result.ChangedFiles,
+	}
+	if logErr := s.eventService.LogProjectEvent(ctx, models.EventTypeProjectUpdate, proj.ID, proj.Name, user.ID, user.Username, "0", metadata); logErr != nil {
+		slog.ErrorContext(ctx, "could not log git sync", "error", logErr)
+	}
+
+	if !src.AutoDeploy {
+		return result, nil
+	}
+
+	deployCtx := withDeploymentSource(ctx, deploymentSource{
+		trigger: models.DeploymentTriggerGit,
+		detail:  src.RepositoryURL + "@" + shortCommit(result.Commit),
+		user:    &user,
+	})
+	if err := s.projectService.DeployProject(deployCtx, projectID, user); err != nil {
+		s.recordSync(ctx, src, &result.Commit, err)
+		return result, fmt.Errorf("synced to %s but deploy failed: %w", shortCommit(result.Commit), err)
+	}
+	result.Deployed = true
+	return result, nil
+}
+
+// SyncDueProjects syncs every project with AutoSync enabled whose interval has elapsed.
+func (s *ProjectGitService) SyncDueProjects(ctx context.Context) error {
+	var sources []models.ProjectGitSource
+	if err := s.db.WithContext(ctx).Where("auto_sync = ?", true).Find(&sources).Error; err != nil {
+		return fmt.Errorf("failed to list git sources: %w", err)
+	}
+
+	now := time.Now()
+	for _, src := range sources {
+		interval := time.Duration(max(src.SyncInterval, 1)) * time.Minute
+		if src.LastSyncAt != nil && now.Sub(*src.LastSyncAt) < interval {
+			continue
+		}
+
+		result, err := s.SyncProject(ctx, src.ProjectID, systemUser)
+		if err != nil {
+			if !errors.Is(err, ErrGitSyncInProgress) {
+				slog.WarnContext(ctx, "git sync failed", "projectID", src.ProjectID, "repository", src.RepositoryURL, "error", err)
+			}
+			continue
+		}
+		if result.Changed {
+			slog.InfoContext(ctx, "git project updated", "projectID", src.ProjectID, "commit", shortCommit(result.Commit), "deployed", result.Deployed)
+		}
+	}
+	return nil
+}
+
+func (s *ProjectGitService) pullChanges(ctx context.Context, src *models.ProjectGitSource) (*dto.GitSyncResultDto, error) {
+	auth, err := s.gitAuth(src)
+	if err != nil {
+		return nil, err
+	}
+
+	previous, err := git.Head(ctx, src.CheckoutPath)
+	if err != nil {
+		return nil, err
+	}
+	commit, err := git.Fetch(ctx, src.CheckoutPath, src.Ref, auth)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch repository: %w", err)
+	}
+
+	result := &dto.GitSyncResultDto{PreviousCommit: previous, Commit: commit}
+	if previous == commit {
+		return result, nil
+	}
+
+	files, err := git.ChangedFiles(ctx, src.CheckoutPath, previous, commit, src.SubPath)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) > 0 {
+		diff, err := git.Diff(ctx, src.CheckoutPath, previous, commit, src.SubPath)
+		if err != nil {
+			return nil, err
+		}
+		if len(diff) > maxGitDiffLength {
+			diff = diff[:maxGitDiffLength] + "\n... diff truncated ...\n"
+		}
+		result.Changed = true
+		result.ChangedFiles = files
+		result.Diff = diff
+	}
+
+	// Always move the checkout so commits outside the sub-path don't pile up.
+	if err := git.ResetHard(ctx, src.CheckoutPath, commit); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (s *ProjectGitService) recordSync(ctx context.Context, src *models.ProjectGitSource, commit *string, syncErr error) {
+	now := time.Now()
+	updates := map[string]interface{}{
+		"last_sync_at": now,
+		"updated_at":   now,
+	}
+	if commit != nil {
+		updates["last_commit"] = *commit
+	}
+	if syncErr != nil {
+		updates["last_sync_error"] = syncErr.Error()
+	} else {
+		updates["last_sync_error"] = nil
+	}
+	if err := s.db.WithContext(ctx).Model(&models.ProjectGitSource{}).Where("id = ?", src.ID).Updates(updates).Error; err != nil {
+		slog.WarnContext(ctx, "failed to record git sync state", "projectID", src.ProjectID, "error", err)
+	}
+}
+
+func (s *ProjectGitService) gitAuth(src *models.ProjectGitSource) (git.Auth, error) {
+	auth := git.Auth{}
+	if src.Username != nil {
+		auth.Username = *src.Username
+	}
+	if src.AuthType == models.GitAuthNone || src.Credential == "" {
+		return auth, nil
+	}
+
+	credential, err := utils.Decrypt(src.Credential)
+	if err != nil {
+		return auth, fmt.Errorf("failed to decrypt git credential: %w", err)
+	}
+	switch src.AuthType {
+	case models.GitAuthToken:
+		auth.Token = credential
+	case models.GitAuthSSH:
+		auth.SSHKey = credential
+	case models.GitAuthNone:
+	}
+	return auth, nil
+}
+
+func parseGitAuthType(raw string, credential *string) (models.GitAuthType, error) {
+	authType := models.GitAuthType(strings.ToLower(strings.TrimSpace(raw)))
+	if authType == "" {
+		authType = models.GitAuthNone
+		if credential != nil && *credential != "" {
+			authType = models.GitAuthToken
+		}
+	}
+
+	switch authType {
+	case models.GitAuthNone, models.GitAuthToken, models.GitAuthSSH:
+		return authType, nil
+	default:
+		return "", fmt.Errorf("%w: unsupported auth type %q", ErrInvalidGitSource, raw)
+	}
+}
+
+func cleanGitSubPath(raw string) (string, error) {
+	p := strings.TrimSpace(raw)
+	if p == "" {
+		return "", nil
+	}
+	p = filepath.Clean(filepath.FromSlash(p))
+	if p == "." {
+		return "", nil
+	}
+	if filepath.IsAbs(p) || p == ".." || strings.HasPrefix(p, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%w: sub-path must be relative to the repository root", ErrInvalidGitSource)
+	}
+	return p, nil
+}
+
+func shortCommit(commit string) string {
+	if len(commit) > 12 {
+		return commit[:12]
+	}
+	return commit
+}
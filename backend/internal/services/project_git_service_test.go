@@ -0,0 +1,102 @@
+package services
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	glsqlite "github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/ofkm/arcane-backend/internal/database"
+	"github.com/ofkm/arcane-backend/internal/dto"
+	"github.com/ofkm/arcane-backend/internal/models"
+	"github.com/ofkm/arcane-backend/internal/utils/git"
+)
+
+func commitToRepo(t *testing.T, repo, name, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(repo, name)), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(repo, name), []byte(content), 0o600))
+	for _, args := range [][]string{
+		{"add", "-A"},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "-m", "update " + name},
+	} {
+		out, err := exec.Command("git", append([]string{"-C", repo}, args...)...).CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+}
+
+func TestProjectGitService_CreateAndSync(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	ctx := context.Background()
+
+	gdb, err := gorm.Open(glsqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, gdb.AutoMigrate(&models.SettingVariable{}, &models.Project{}, &models.ProjectGitSource{}, &models.Event{}))
+	db := &database.DB{DB: gdb}
+
+	projectsDir := t.TempDir()
+	require.NoError(t, gdb.Create(&models.SettingVariable{Key: "projectsDirectory", Value: projectsDir}).Error)
+
+	settings := &SettingsService{db: db}
+	events := NewEventService(db)
+	svc := NewProjectGitService(db, NewProjectService(db, settings, events, nil, nil), settings, events)
+
+	origin := t.TempDir()
+	out, err := exec.Command("git", "init", "-q", "-b", "main", origin).CombinedOutput()
+	require.NoError(t, err, string(out))
+	commitToRepo(t, origin, "stacks/web/compose.yaml", "services:\n  web:\n    image: nginx:1.27\n")
+
+	_, _, err = svc.CreateGitProject(ctx, dto.CreateGitProjectDto{Name: "web", RepositoryURL: "file://" + origin}, systemUser)
+	require.ErrorIs(t, err, ErrInvalidGitSource, "local repositories are refused")
+
+	validateGitRepositoryURL = func(string) error { return nil }
+	t.Cleanup(func() { validateGitRepositoryURL = git.ValidateURL })
+
+	_, _, err = svc.CreateGitProject(ctx, dto.CreateGitProjectDto{Name: "web", RepositoryURL: "file://" + origin, Ref: "--upload-pack=touch /tmp/pwned"}, systemUser)
+	require.ErrorIs(t, err, ErrInvalidGitSource)
+
+	autoDeploy := false
+	proj, src, err := svc.CreateGitProject(ctx, dto.CreateGitProjectDto{
+		Name:          "web",
+		RepositoryURL: "file://" + origin,
+		Ref:           "main",
+		SubPath:       "stacks/web",
+		AutoDeploy:    &autoDeploy,
+	}, systemUser)
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(src.CheckoutPath, "stacks", "web"), proj.Path)
+	require.FileExists(t, filepath.Join(proj.Path, "compose.yaml"))
+
+	result, err := svc.SyncProject(ctx, proj.ID, systemUser)
+	require.NoError(t, err)
+	require.False(t, result.Changed)
+
+	commitToRepo(t, origin, "README.md", "docs only\n")
+	result, err = svc.SyncProject(ctx, proj.ID, systemUser)
+	require.NoError(t, err)
+	require.NotEqual(t, result.PreviousCommit, result.Commit)
+	require.False(t, result.Changed, "changes outside the sub-path don't affect the project")
+
+	commitToRepo(t, origin, "stacks/web/compose.yaml", "services:\n  web:\n    image: nginx:1.28\n")
+	result, err = svc.SyncProject(ctx, proj.ID, systemUser)
+	require.NoError(t, err)
+	require.True(t, result.Changed)
+	require.False(t, result.Deployed)
+	require.Equal(t, []string{"stacks/web/compose.yaml"}, result.ChangedFiles)
+	require.Contains(t, result.Diff, "+    image: nginx:1.28")
+
+	stored, err := svc.GetSource(ctx, proj.ID)
+	require.NoError(t, err)
+	require.Equal(t, result.Commit, *stored.LastCommit)
+	require.Nil(t, stored.LastSyncError)
+
+	_, _, err = svc.CreateGitProject(ctx, dto.CreateGitProjectDto{Name: "bad", RepositoryURL: "file://" + origin, SubPath: "../etc"}, systemUser)
+	require.ErrorIs(t, err, ErrInvalidGitSource)
+}
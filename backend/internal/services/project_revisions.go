@@ -0,0 +1,343 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"github.com/ofkm/arcane-backend/internal/dto"
+	"github.com/ofkm/arcane-backend/internal/models"
+	"github.com/ofkm/arcane-backend/internal/utils/fs"
+	"github.com/ofkm/arcane-backend/internal/utils/projects"
+	"github.com/pmezard/go-difflib/difflib"
+	"gorm.io/gorm"
+)
+
+const (
+	revisionComposeFile = "compose.yaml"
+	revisionEnvFile     = ".env"
+
+	revisionDiffContextLines = 3
+)
+
+var ErrProjectRevisionNotFound = errors.New("project revision not found")
+
+// projectFilesSnapshot is the on-disk state a revision captures.
+type projectFilesSnapshot struct {
+	composeContent string
+	envContent     string
+	includeFiles   map[string]string
+}
+
+// readProjectFilesSnapshot reads the compose file, .env and every include file that
+// lives inside the project directory. Includes outside the project are not writable
+// from Arcane, so they cannot be restored and are not captured.
+func readProjectFilesSnapshot(proj *models.Project) projectFilesSnapshot {
+	composeContent, envContent, _ := fs.ReadProjectFiles(proj.Path)
+	snapshot := projectFilesSnapshot{
+		composeContent: composeContent,
+		envContent:     envContent,
+		includeFiles:   map[string]string{},
+	}
+
+	composeFile, err := projects.DetectComposeFile(proj.Path)
+	if err != nil {
+		return snapshot
+	}
+	includes, err := projects.ParseIncludes(composeFile)
+	if err != nil {
+		return snapshot
+	}
+	for _, inc := range includes {
+		if _, err := projects.ValidateIncludePathForWrite(proj.Path, inc.RelativePath); err != nil {
+			continue
+		}
+		// ParseIncludes fills in placeholder content for files that do not exist yet.
+		if _, err := os.Stat(inc.Path); err != nil {
+			continue
+		}
+		snapshot.includeFiles[inc.RelativePath] = inc.Content
+	}
+	return snapshot
+}
+
+func revisionSnapshot(rev *models.ProjectRevision) projectFilesSnapshot {
+	snapshot := projectFilesSnapshot{
+		composeContent: rev.ComposeContent,
+		envContent:     rev.EnvContent,
+		includeFiles:   map[string]string{},
+	}
+	for path, content := range rev.IncludeFiles {
+		if s, ok := content.(string); ok {
+			snapshot.includeFiles[path] = s
+		}
+	}
+	return snapshot
+}
+
+func (a projectFilesSnapshot) equal(b projectFilesSnapshot) bool {
+	if a.composeContent != b.composeContent || a.envContent != b.envContent || len(a.includeFiles) != len(b.includeFiles) {
+		return false
+	}
+	for path, content := range a.includeFiles {
+		if other, ok := b.includeFiles[path]; !ok || other != content {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *ProjectService) latestProjectRevision(ctx context.Context, projectID string) (*models.ProjectRevision, error) {
+	var revisions []models.ProjectRevision
+	if err := s.db.WithContext(ctx).Where("project_id = ?", projectID).Order("revision DESC").Limit(1).Find(&revisions).Error; err != nil {
+		return nil, fmt.Errorf("failed to get latest project revision: %w", err)
+	}
+	if len(revisions) == 0 {
+		return nil, nil
+	}
+	return &revisions[0], nil
+}
+
+// recordProjectRevision stores the project's current files as a new revision, unless
+// they are identical to the latest one. user may be nil for changes made by Arcane.
+func (s *ProjectService) recordProjectRevision(ctx context.Context, proj *models.Project, user *models.User, message string) (*models.ProjectRevision, error) {
+	latest, err := s.latestProjectRevision(ctx, proj.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := readProjectFilesSnapshot(proj)
+	if latest != nil && revisionSnapshot(latest).equal(snapshot) {
+		return latest, nil
+	}
+
+	includeFiles := models.JSON{}
+	for path, content := range snapshot.includeFiles {
+		includeFiles[path] = content
+	}
+	rev := &models.ProjectRevision{
+		ProjectID:      proj.ID,
+		Revision:       1,
+		ComposeContent: snapshot.composeContent,
+		EnvContent:     snapshot.envContent,
+		IncludeFiles:   includeFiles,
+		Message:        message,
+	}
+	if latest != nil {
+		rev.Revision = latest.Revision + 1
+	}
+	if user != nil && user.ID != "" {
+		rev.UserID = &user.ID
+		rev.Username = &user.Username
+	}
+
+	if err := s.db.WithContext(ctx).Create(rev).Error; err != nil {
+		return nil, fmt.Errorf("failed to save project revision: %w", err)
+	}
+	return rev, nil
+}
+
+// ensureBaselineRevision records the files as they are before the first edit made
+// through Arcane, so projects created on disk or before revisions existed can still be
+// rolled back to their original state.
+func (s *ProjectService) ensureBaselineRevision(ctx context.Context, proj *models.Project) {
+	latest, err := s.latestProjectRevision(ctx, proj.ID)
+	if err == nil && latest == nil {
+		_, err = s.recordProjectRevision(ctx, proj, nil, "Initial version")
+	}
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to record baseline project revision", "projectID", proj.ID, "error", err)
+	}
+}
+
+// recordProjectRevisionAfterSave is called once files have been written. The save has
+// already happened, so a failure here is logged rather than returned.
+func (s *ProjectService) recordProjectRevisionAfterSave(ctx context.Context, proj *models.Project, user models.User, message string) {
+	if _, err := s.recordProjectRevision(ctx, proj, &user, message); err != nil {
+		slog.ErrorContext(ctx, "Failed to record project revision", "projectID", proj.ID, "error", err)
+	}
+}
+
+func (s *ProjectService) ListProjectRevisions(ctx context.Context, projectID string) ([]dto.ProjectRevisionDto, error) {
+	if _, err := s.GetProjectFromDatabaseByID(ctx, projectID); err != nil {
+		return nil, err
+	}
+
+	var revisions []models.ProjectRevision
+	if err := s.db.WithContext(ctx).
+		Select("id", "project_id", "revision", "message", "user_id", "username", "created_at").
+		Where("project_id = ?", projectID).
+		Order("revision DESC").
+		Find(&revisions).Error; err != nil {
+		return nil, fmt.Errorf("failed to list project revisions: %w", err)
+	}
+
+	out := make([]dto.ProjectRevisionDto, 0, len(revisions))
+	for _, rev := range revisions {
+		out = append(out, dto.ProjectRevisionDto{
+			ID:        rev.ID,
+			ProjectID: rev.ProjectID,
+			Revision:  rev.Revision,
+			Message:   rev.Message,
+			UserID:    rev.UserID,
+			Username:  rev.Username,
+			CreatedAt: rev.CreatedAt,
+		})
+	}
+	return out, nil
+}
+
+func (s *ProjectService) GetProjectRevision(ctx context.Context, projectID, revisionID string) (*models.ProjectRevision, error) {
+	var rev models.ProjectRevision
+	err := s.db.WithContext(ctx).Where("project_id = ? AND id = ?", projectID, revisionID).First(&rev).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrProjectRevisionNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project revision: %w", err)
+	}
+	return &rev, nil
+}
+
+// DiffProjectRevisions returns unified diffs from one revision to another. With an
+// empty fromID the revision is compared with the one before it; the first revision is
+// compared with empty files.
+func (s *ProjectService) DiffProjectRevisions(ctx context.Context, projectID, fromID, toID string) (dto.ProjectRevisionDiffDto, error) {
+	to, err := s.GetProjectRevision(ctx, projectID, toID)
+	if err != nil {
+		return dto.ProjectRevisionDiffDto{}, err
+	}
+
+	from := &models.ProjectRevision{}
+	if fromID != "" {
+		if from, err = s.GetProjectRevision(ctx, projectID, fromID); err != nil {
+			return dto.ProjectRevisionDiffDto{}, err
+		}
+	} else {
+		var previous []models.ProjectRevision
+		if err := s.db.WithContext(ctx).
+			Where("project_id = ? AND revision < ?", projectID, to.Revision).
+			Order("revision DESC").
+			Limit(1).
+			Find(&previous).Error; err != nil {
+			return dto.ProjectRevisionDiffDto{}, fmt.Errorf("failed to get previous project revision: %w", err)
+		}
+		if len(previous) > 0 {
+			from = &previous[0]
+		}
+	}
+
+	files, err := diffProjectFilesSnapshots(revisionSnapshot(from), revisionSnapshot(to), from.Revision, to.Revision)
+	if err != nil {
+		return dto.ProjectRevisionDiffDto{}, err
+	}
+	return dto.ProjectRevisionDiffDto{FromRevision: from.Revision, ToRevision: to.Revision, Files: files}, nil
+}
+
+func diffProjectFilesSnapshots(from, to projectFilesSnapshot, fromRevision, toRevision int) ([]dto.ProjectRevisionFileDiffDto, error) {
+	includePaths := map[string]struct{}{}
+	for path := range from.includeFiles {
+		includePaths[path] = struct{}{}
+	}
+	for path := range to.includeFiles {
+		includePaths[path] = struct{}{}
+	}
+	sortedIncludes := make([]string, 0, len(includePaths))
+	for path := range includePaths {
+		sortedIncludes = append(sortedIncludes, path)
+	}
+	sort.Strings(sortedIncludes)
+
+	type filePair struct{ name, before, after string }
+	pairs := []filePair{
+		{revisionComposeFile, from.composeContent, to.composeContent},
+		{revisionEnvFile, from.envContent, to.envContent},
+	}
+	for _, path := range sortedIncludes {
+		pairs = append(pairs, filePair{path, from.includeFiles[path], to.includeFiles[path]})
+	}
+
+	files := []dto.ProjectRevisionFileDiffDto{}
+	for _, pair := range pairs {
+		if pair.before == pair.after {
+			continue
+		}
+		diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        difflib.SplitLines(pair.before),
+			B:        difflib.SplitLines(pair.after),
+			FromFile: filepath.ToSlash(pair.name) + "@" + strconv.Itoa(fromRevision),
+			ToFile:   filepath.ToSlash(pair.name) + "@" + strconv.Itoa(toRevision),
+			Context:  revisionDiffContextLines,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to diff %s: %w", pair.name, err)
+		}
+		files = append(files, dto.ProjectRevisionFileDiffDto{File: pair.name, Diff: diff})
+	}
+	return files, nil
+}
+
+// RestoreProjectRevision writes a revision's files back to the project directory and
+// records the result as a new revision, so a restore can itself be undone. Include
+// files added after the revision are left on disk; the restored compose file no longer
+// references them.
+func (s *ProjectService) RestoreProjectRevision(ctx context.Context, projectID, revisionID string, redeploy bool, user models.User) (*models.ProjectRevision, error) {
+	proj, err := s.GetProjectFromDatabaseByID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	rev, err := s.GetProjectRevision(ctx, projectID, revisionID)
+	if err != nil {
+		return nil, err
+	}
+
+	projectsDirectory, err := fs.GetProjectsDirectory(ctx, s.settingsService.GetStringSetting(ctx, "projectsDirectory", "data/projects"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get projects directory: %w", err)
+	}
+
+	s.ensureBaselineRevision(ctx, proj)
+	before := auditProjectSnapshot(proj)
+
+	if err := fs.SaveOrUpdateProjectFiles(projectsDirectory, proj.Path, rev.ComposeContent, &rev.EnvContent); err != nil {
+		return nil, fmt.Errorf("failed to restore project files: %w", err)
+	}
+	if rev.EnvContent == "" {
+		if err := os.Remove(filepath.Join(proj.Path, ".env")); err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to remove env file: %w", err)
+		}
+	}
+	for path, content := range revisionSnapshot(rev).includeFiles {
+		if err := projects.WriteIncludeFile(proj.Path, path, content); err != nil {
+			return nil, fmt.Errorf("failed to restore include file %s: %w", path, err)
+		}
+	}
+
+	restored, err := s.recordProjectRevision(ctx, proj, &user, fmt.Sprintf("Restored revision %d", rev.Revision))
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(ctx, AuditChange{
+		Action:     auditActionUpdate,
+		TargetType: auditTargetProject,
+		TargetID:   proj.ID,
+		TargetName: proj.Name,
+		Before:     before,
+		After:      auditProjectSnapshot(proj),
+		Sensitive:  []string{"envContent"},
+	})
+	slog.InfoContext(ctx, "project revision restored", "projectID", proj.ID, "revision", rev.Revision)
+
+	if redeploy {
+		if err := s.RedeployProject(ctx, projectID, user); err != nil {
+			return restored, fmt.Errorf("revision restored but redeploy failed: %w", err)
+		}
+	}
+	return restored, nil
+}
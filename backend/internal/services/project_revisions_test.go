@@ -0,0 +1,100 @@
+package services
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ofkm/arcane-backend/internal/models"
+	"github.com/ofkm/arcane-backend/internal/utils"
+)
+
+func setupProjectRevisionService(t *testing.T) (*ProjectService, *models.Project) {
+	t.Helper()
+	ctx := context.Background()
+	db := setupSettingsTestDB(t)
+	require.NoError(t, db.AutoMigrate(&models.Project{}, &models.ProjectRevision{}))
+
+	projectsDir := t.TempDir()
+	settings, err := NewSettingsService(ctx, db, nil)
+	require.NoError(t, err)
+	require.NoError(t, settings.UpdateSetting(ctx, "projectsDirectory", projectsDir))
+
+	projectPath := filepath.Join(projectsDir, "web")
+	require.NoError(t, os.MkdirAll(projectPath, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(projectPath, "compose.yaml"), []byte("services:\n  web:\n    image: nginx:1.25\n"), 0o600))
+
+	proj := &models.Project{Name: "web", Path: projectPath}
+	require.NoError(t, db.Create(proj).Error)
+	return NewProjectService(db, settings, nil, nil, nil), proj
+}
+
+func TestProjectRevisions_UpdateRecordsHistory(t *testing.T) {
+	ctx := context.Background()
+	svc, proj := setupProjectRevisionService(t)
+	alice := models.User{BaseModel: models.BaseModel{ID: "u1"}, Username: "alice"}
+
+	compose := "include:\n  - db.yaml\nservices:\n  web:\n    image: nginx:1.27\n"
+	_, err := svc.UpdateProject(ctx, proj.ID, nil, &compose, utils.Ptr("TAG=1\n"), "bump nginx", alice)
+	require.NoError(t, err)
+	require.NoError(t, svc.UpdateProjectIncludeFile(ctx, proj.ID, "db.yaml", "services:\n  db:\n    image: postgres:16\n", "", alice))
+
+	// Saving identical content does not add a revision.
+	_, err = svc.UpdateProject(ctx, proj.ID, nil, &compose, nil, "no-op", alice)
+	require.NoError(t, err)
+
+	revisions, err := svc.ListProjectRevisions(ctx, proj.ID)
+	require.NoError(t, err)
+	require.Len(t, revisions, 3)
+	require.Equal(t, 3, revisions[0].Revision)
+	require.Equal(t, 1, revisions[2].Revision)
+	require.Equal(t, "Initial version", revisions[2].Message)
+	require.Nil(t, revisions[2].Username)
+	require.Equal(t, "bump nginx", revisions[1].Message)
+	require.Equal(t, "alice", *revisions[1].Username)
+
+	diff, err := svc.DiffProjectRevisions(ctx, proj.ID, "", revisions[1].ID)
+	require.NoError(t, err)
+	require.Equal(t, 1, diff.FromRevision)
+	require.Len(t, diff.Files, 2)
+	require.Equal(t, "compose.yaml", diff.Files[0].File)
+	require.Contains(t, diff.Files[0].Diff, "-    image: nginx:1.25\n")
+	require.Contains(t, diff.Files[0].Diff, "+    image: nginx:1.27\n")
+	require.Equal(t, ".env", diff.Files[1].File)
+
+	diff, err = svc.DiffProjectRevisions(ctx, proj.ID, "", revisions[0].ID)
+	require.NoError(t, err)
+	require.Len(t, diff.Files, 1)
+	require.Equal(t, "db.yaml", diff.Files[0].File)
+
+	_, err = svc.DiffProjectRevisions(ctx, proj.ID, "", "missing")
+	require.ErrorIs(t, err, ErrProjectRevisionNotFound)
+}
+
+func TestProjectRevisions_RestoreWritesFilesAndAddsRevision(t *testing.T) {
+	ctx := context.Background()
+	svc, proj := setupProjectRevisionService(t)
+	alice := models.User{BaseModel: models.BaseModel{ID: "u1"}, Username: "alice"}
+
+	broken := "services:\n  web:\n    image: [\n"
+	_, err := svc.UpdateProject(ctx, proj.ID, nil, &broken, utils.Ptr("TAG=2\n"), "", alice)
+	require.NoError(t, err)
+
+	revisions, err := svc.ListProjectRevisions(ctx, proj.ID)
+	require.NoError(t, err)
+	require.Len(t, revisions, 2)
+
+	restored, err := svc.RestoreProjectRevision(ctx, proj.ID, revisions[1].ID, false, alice)
+	require.NoError(t, err)
+	require.Equal(t, 3, restored.Revision)
+	require.Equal(t, "Restored revision 1", restored.Message)
+
+	composeContent, err := os.ReadFile(filepath.Join(proj.Path, "compose.yaml"))
+	require.NoError(t, err)
+	require.Contains(t, string(composeContent), "nginx:1.25")
+	_, err = os.Stat(filepath.Join(proj.Path, ".env"))
+	require.True(t, os.IsNotExist(err), "the restored revision had no .env")
+}
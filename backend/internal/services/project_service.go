@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/compose-spec/compose-go/v2/loader"
+	composetypes "github.com/compose-spec/compose-go/v2/types"
 	"github.com/docker/compose/v2/pkg/api"
 	"github.com/ofkm/arcane-backend/internal/database"
 	"github.com/ofkm/arcane-backend/internal/dto"
@@ -29,14 +30,16 @@ type ProjectService struct {
 	settingsService *SettingsService
 	eventService    *EventService
 	imageService    *ImageService
+	audit           *AuditService
 }
 
-func NewProjectService(db *database.DB, settingsService *SettingsService, eventService *EventService, imageService *ImageService) *ProjectService {
+func NewProjectService(db *database.DB, settingsService *SettingsService, eventService *EventService, imageService *ImageService, audit *AuditService) *ProjectService {
 	return &ProjectService{
 		db:              db,
 		settingsService: settingsService,
 		eventService:    eventService,
 		imageService:    imageService,
+		audit:           audit,
 	}
 }
 
@@ -338,7 +341,8 @@ func (s *ProjectService) upsertProjectForDir(ctx context.Context, dirName, dirPa
 	}
 
 	updates := map[string]interface{}{}
-	if existing.Path != dirPath {
+	// Git-backed projects may point at a sub-path inside the checkout; keep that path.
+	if existing.Path != dirPath && !fs.IsSafeSubdirectory(dirPath, existing.Path) {
 		updates["path"] = dirPath
 	}
 	if existing.DirName == nil || *existing.DirName != dirName {
@@ -519,14 +523,28 @@ func (s *ProjectService) GetProjectStatusCounts(ctx context.Context) (folderCoun
 // Project Actions
 
 func (s *ProjectService) DeployProject(ctx context.Context, projectID string, user models.User) error {
+	_, err := s.deployProject(ctx, projectID, user, nil)
+	return err
+}
+
+// deployProject runs compose up and records the attempt as a deployment. pinnedImages
+// maps service names to image references that replace the ones in the compose file,
+// which is how rollbacks redeploy the exact images of an earlier deployment.
+func (s *ProjectService) deployProject(ctx context.Context, projectID string, user models.User, pinnedImages map[string]string) (deployment *models.ProjectDeployment, err error) {
 	projectFromDb, err := s.GetProjectFromDatabaseByID(ctx, projectID)
 	if err != nil {
-		return fmt.Errorf("failed to get project: %w", err)
+		return nil, fmt.Errorf("failed to get project: %w", err)
 	}
 
+	started := time.Now()
+	var project *composetypes.Project
+	defer func() {
+		deployment = s.recordDeployment(ctx, projectFromDb, project, user, started, err)
+	}()
+
 	composeFileFullPath, derr := projects.DetectComposeFile(projectFromDb.Path)
 	if derr != nil {
-		return fmt.Errorf("no compose file found in project directory: %s", projectFromDb.Path)
+		return nil, fmt.Errorf("no compose file found in project directory: %s", projectFromDb.Path)
 	}
 
 	// Get configured projects directory from settings
@@ -537,16 +555,18 @@ func (s *ProjectService) DeployProject(ctx context.Context, projectID string, us
 		projectsDirectory = "data/projects"
 	}
 
-	project, loadErr := projects.LoadComposeProject(ctx, composeFileFullPath, normalizeComposeProjectName(projectFromDb.Name), projectsDirectory)
+	loaded, loadErr := projects.LoadComposeProject(ctx, composeFileFullPath, normalizeComposeProjectName(projectFromDb.Name), projectsDirectory)
 	if loadErr != nil {
-		return fmt.Errorf("failed to load compose project from %s: %w", projectFromDb.Path, loadErr)
+		return nil, fmt.Errorf("failed to load compose project from %s: %w", projectFromDb.Path, loadErr)
 	}
+	project = loaded
+	pinComposeImages(project, pinnedImages)
 
 	if err := s.updateProjectStatusInternal(ctx, projectID, models.ProjectStatusDeploying); err != nil {
-		return fmt.Errorf("failed to update project status to deploying: %w", err)
+		return nil, fmt.Errorf("failed to update project status to deploying: %w", err)
 	}
 
-	if perr := s.EnsureProjectImagesPresent(ctx, projectID, io.Discard, nil); perr != nil {
+	if perr := s.ensureComposeImagesPresent(ctx, project, io.Discard, nil); perr != nil {
 		slog.Warn("ensure images present failed (continuing to compose up)", "projectID", projectID, "error", perr)
 	}
 
@@ -556,7 +576,7 @@ func (s *ProjectService) DeployProject(ctx context.Context, projectID string, us
 			slog.Info("containers after failed deploy", "projectID", projectID, "containers", containers)
 		}
 		_ = s.updateProjectStatusandCountsInternal(ctx, projectID, models.ProjectStatusStopped)
-		return fmt.Errorf("failed to deploy project: %w", err)
+		return nil, fmt.Errorf("failed to deploy project: %w", err)
 	}
 
 	metadata := models.JSON{"action": "deploy", "projectID": projectID, "projectName": project.Name}
@@ -568,7 +588,7 @@ func (s *ProjectService) DeployProject(ctx context.Context, projectID string, us
 	if err != nil {
 		slog.Error("failed to update project status and counts after deploy", "projectID", projectID, "error", err)
 	}
-	return err
+	return nil, err
 }
 
 func (s *ProjectService) DownProject(ctx context.Context, projectID string, user models.User) error {
@@ -644,6 +664,8 @@ func (s *ProjectService) CreateProject(ctx context.Context, name, composeContent
 		return nil, fmt.Errorf("failed to save project files: %w", err)
 	}
 
+	s.recordProjectRevisionAfterSave(ctx, proj, user, "Project created")
+
 	metadata := models.JSON{"action": "create", "projectID": proj.ID, "projectName": name, "path": projectPath}
 	if logErr := s.eventService.LogProjectEvent(ctx, models.EventTypeProjectCreate, proj.ID, name, user.ID, user.Username, "0", metadata); logErr != nil {
 		slog.ErrorContext(ctx, "could not log project creation", "error", logErr)
@@ -680,12 +702,34 @@ func (s *ProjectService) DestroyProject(ctx context.Context, projectID string, r
 		}
 	}
 
+	var gitSource models.ProjectGitSource
+	hasGitSource := s.db.WithContext(ctx).Where("project_id = ?", projectID).Limit(1).Find(&gitSource).RowsAffected > 0
+
 	if removeFiles {
-		if err := os.RemoveAll(proj.Path); err != nil {
+		removePath := proj.Path
+		if hasGitSource {
+			removePath = gitSource.CheckoutPath
+		}
+		if err := os.RemoveAll(removePath); err != nil {
 			return fmt.Errorf("failed to remove project files: %w", err)
 		}
 	}
 
+	if hasGitSource {
+		if err := s.db.WithContext(ctx).Delete(&gitSource).Error; err != nil {
+			return fmt.Errorf("failed to delete project git source: %w", err)
+		}
+	}
+	if err := s.db.WithContext(ctx).Delete(&models.ProjectWebhook{}, "project_id = ?", projectID).Error; err != nil {
+		return fmt.Errorf("failed to delete project webhooks: %w", err)
+	}
+	if err := s.db.WithContext(ctx).Delete(&models.ProjectRevision{}, "project_id = ?", projectID).Error; err != nil {
+		return fmt.Errorf("failed to delete project revisions: %w", err)
+	}
+	if err := s.db.WithContext(ctx).Delete(&models.ProjectDeployment{}, "project_id = ?", projectID).Error; err != nil {
+		return fmt.Errorf("failed to delete project deployments: %w", err)
+	}
+
 	if err := s.db.WithContext(ctx).Delete(proj).Error; err != nil {
 		return fmt.Errorf("failed to delete project from database: %w", err)
 	}
@@ -713,6 +757,9 @@ func (s *ProjectService) RedeployProject(ctx context.Context, projectID string,
 		slog.ErrorContext(ctx, "could not log project redeploy action", "error", logErr)
 	}
 
+	if _, ok := ctx.Value(deploymentSourceKey{}).(deploymentSource); !ok {
+		ctx = withDeploymentSource(ctx, deploymentSource{trigger: models.DeploymentTriggerUser, user: &user})
+	}
 	return s.DeployProject(ctx, projectID, systemUser)
 }
 
@@ -773,6 +820,10 @@ func (s *ProjectService) EnsureProjectImagesPresent(ctx context.Context, project
 		return fmt.Errorf("failed to load compose project: %w", lerr)
 	}
 
+	return s.ensureComposeImagesPresent(ctx, compProj, progressWriter, credentials)
+}
+
+func (s *ProjectService) ensureComposeImagesPresent(ctx context.Context, compProj *composetypes.Project, progressWriter io.Writer, credentials []dto.ContainerRegistryCredential) error {
 	images := map[string]struct{}{}
 	for _, svc := range compProj.Services {
 		img := strings.TrimSpace(svc.Image)
@@ -840,7 +891,9 @@ func (s *ProjectService) RestartProject(ctx context.Context, projectID string, u
 	return s.updateProjectStatusandCountsInternal(ctx, projectID, models.ProjectStatusRunning)
 }
 
-func (s *ProjectService) UpdateProject(ctx context.Context, projectID string, name *string, composeContent, envContent *string) (*models.Project, error) {
+// UpdateProject renames the project and/or overwrites its compose and env files. Any
+// change to the files is recorded as a revision attributed to user.
+func (s *ProjectService) UpdateProject(ctx context.Context, projectID string, name *string, composeContent, envContent *string, message string, user models.User) (*models.Project, error) {
 	var proj models.Project
 	if err := s.db.WithContext(ctx).First(&proj, "id = ?", projectID).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -855,6 +908,11 @@ func (s *ProjectService) UpdateProject(ctx context.Context, projectID string, na
 		return nil, fmt.Errorf("failed to get projects directory: %w", err)
 	}
 
+	before := auditProjectSnapshot(&proj)
+	if composeContent != nil || envContent != nil {
+		s.ensureBaselineRevision(ctx, &proj)
+	}
+
 	if name != nil {
 		if newName := strings.TrimSpace(*name); newName != "" && proj.Name != newName {
 			proj.Name = newName
@@ -883,20 +941,64 @@ func (s *ProjectService) UpdateProject(ctx context.Context, projectID string, na
 		return nil, fmt.Errorf("failed to update project: %w", err)
 	}
 
+	if composeContent != nil || envContent != nil {
+		s.recordProjectRevisionAfterSave(ctx, &proj, user, message)
+	}
+
+	s.audit.Record(ctx, AuditChange{
+		Action:     auditActionUpdate,
+		TargetType: auditTargetProject,
+		TargetID:   proj.ID,
+		TargetName: proj.Name,
+		Before:     before,
+		After:      auditProjectSnapshot(&proj),
+		Sensitive:  []string{"envContent"},
+	})
+
 	slog.InfoContext(ctx, "project updated", "projectID", proj.ID, "name", proj.Name)
 	return &proj, nil
 }
 
-func (s *ProjectService) UpdateProjectIncludeFile(ctx context.Context, projectID, relativePath, content string) error {
+// auditProjectSnapshot reads the project's compose and env files from disk, since they
+// are not stored in the database.
+func auditProjectSnapshot(proj *models.Project) map[string]any {
+	composeContent, envContent, _ := fs.ReadProjectFiles(proj.Path)
+	return map[string]any{
+		"name":           proj.Name,
+		"composeContent": composeContent,
+		"envContent":     envContent,
+	}
+}
+
+func (s *ProjectService) UpdateProjectIncludeFile(ctx context.Context, projectID, relativePath, content, message string, user models.User) error {
 	proj, err := s.GetProjectFromDatabaseByID(ctx, projectID)
 	if err != nil {
 		return err
 	}
+	s.ensureBaselineRevision(ctx, proj)
+
+	var previous string
+	if path, err := projects.ValidateIncludePathForWrite(proj.Path, relativePath); err == nil {
+		if data, err := os.ReadFile(path); err == nil {
+			previous = string(data)
+		}
+	}
 
 	if err := projects.WriteIncludeFile(proj.Path, relativePath, content); err != nil {
 		return fmt.Errorf("failed to update include file: %w", err)
 	}
 
+	s.recordProjectRevisionAfterSave(ctx, proj, user, message)
+
+	s.audit.Record(ctx, AuditChange{
+		Action:     auditActionUpdate,
+		TargetType: auditTargetProject,
+		TargetID:   proj.ID,
+		TargetName: proj.Name,
+		Before:     map[string]any{"includeFiles": map[string]any{relativePath: previous}},
+		After:      map[string]any{"includeFiles": map[string]any{relativePath: content}},
+	})
+
 	slog.InfoContext(ctx, "project include file updated", "projectID", proj.ID, "file", relativePath)
 	return nil
 }
@@ -0,0 +1,309 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/ofkm/arcane-backend/internal/database"
+	"github.com/ofkm/arcane-backend/internal/dto"
+	"github.com/ofkm/arcane-backend/internal/models"
+	"github.com/ofkm/arcane-backend/internal/utils"
+)
+
+var (
+	ErrWebhookNotFound     = errors.New("webhook not found")
+	ErrWebhookSignature    = errors.New("webhook signature verification failed")
+	ErrInvalidWebhookInput = errors.New("invalid webhook")
+)
+
+// WebhookTriggerStatus describes what an inbound webhook call resulted in.
+type WebhookTriggerStatus string
+
+const (
+	WebhookTriggerStarted WebhookTriggerStatus = "started"
+	// Another deploy for the project is running; one more run follows it.
+	WebhookTriggerQueued WebhookTriggerStatus = "queued"
+	// Provider pings and similar events that should not deploy anything.
+	WebhookTriggerIgnored WebhookTriggerStatus = "ignored"
+)
+
+const (
+	webhookTokenPrefixLength = 8
+	webhookDeployTimeout     = 30 * time.Minute
+)
+
+type ProjectWebhookService struct {
+	db                 *database.DB
+	projectService     *ProjectService
+	environmentService *EnvironmentService
+	eventService       *EventService
+
+	mu sync.Mutex
+	// projectID -> whether another run was requested while one is in progress
+	running map[string]bool
+}
+
+func NewProjectWebhookService(db *database.DB, projectService *ProjectService, environmentService *EnvironmentService, eventService *EventService) *ProjectWebhookService {
+	return &ProjectWebhookService{
+		db:                 db,
+		projectService:     projectService,
+		environmentService: environmentService,
+		eventService:       eventService,
+		running:            map[string]bool{},
+	}
+}
+
+func (s *ProjectWebhookService) ListWebhooks(ctx context.Context, projectID string) ([]models.ProjectWebhook, error) {
+	var hooks []models.ProjectWebhook
+	if err := s.db.WithContext(ctx).Where("project_id = ?", projectID).Order("created_at ASC").Find(&hooks).Error; err != nil {
+		return nil, fmt.Errorf("failed to list webhooks: %w", err)
+	}
+	return hooks, nil
+}
+
+// CreateWebhook stores a new webhook and returns it with the plaintext URL token, which
+// cannot be recovered afterwards.
+func (s *ProjectWebhookService) CreateWebhook(ctx context.Context, projectID string, req dto.CreateProjectWebhookDto) (*models.ProjectWebhook, string, error) {
+	if _, err := s.projectService.GetProjectFromDatabaseByID(ctx, projectID); err != nil {
+		return nil, "", err
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, "", fmt.Errorf("%w: name is required", ErrInvalidWebhookInput)
+	}
+
+	provider := models.WebhookProvider(strings.ToLower(strings.TrimSpace(req.Provider)))
+	switch provider {
+	case "":
+		provider = models.WebhookProviderGeneric
+	case models.WebhookProviderGeneric, models.WebhookProviderGitHub, models.WebhookProviderGitea, models.WebhookProviderGitLab:
+	default:
+		return nil, "", fmt.Errorf("%w: unsupported provider %q", ErrInvalidWebhookInput, req.Provider)
+	}
+
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, "", fmt.Errorf("failed to generate webhook token: %w", err)
+	}
+	token := hex.EncodeToString(raw)
+
+	hook := &models.ProjectWebhook{
+		ProjectID:   projectID,
+		Name:        name,
+		Provider:    provider,
+		TokenHash:   hashWebhookToken(token),
+		TokenPrefix: token[:webhookTokenPrefixLength],
+		Enabled:     req.Enabled == nil || *req.Enabled,
+	}
+	if req.Secret != nil && *req.Secret != "" {
+		encrypted, err := utils.Encrypt(*req.Secret)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to encrypt webhook secret: %w", err)
+		}
+		hook.Secret = encrypted
+	}
+
+	if err := s.db.WithContext(ctx).Create(hook).Error; err != nil {
+		return nil, "", fmt.Errorf("failed to create webhook: %w", err)
+	}
+	return hook, token, nil
+}
+
+func (s *ProjectWebhookService) DeleteWebhook(ctx context.Context, projectID, webhookID string) error {
+	result := s.db.WithContext(ctx).Where("id = ? AND project_id = ?", webhookID, projectID).Delete(&models.ProjectWebhook{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete webhook: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrWebhookNotFound
+	}
+	return nil
+}
+
+// Trigger verifies an inbound call and starts a pull and redeploy of the webhook's project
+// in the background. Disabled and unknown tokens are indistinguishable to the caller.
+func (s *ProjectWebhookService) Trigger(ctx context.Context, token string, header http.Header, body []byte) (WebhookTriggerStatus, error) {
+	var hook models.ProjectWebhook
+	if err := s.db.WithContext(ctx).Where("token_hash = ?", hashWebhookToken(token)).First(&hook).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", ErrWebhookNotFound
+		}
+		return "", fmt.Errorf("failed to look up webhook: %w", err)
+	}
+	if !hook.Enabled {
+		return "", ErrWebhookNotFound
+	}
+
+	if hook.Secret != "" {
+		secret, err := utils.Decrypt(hook.Secret)
+		if err != nil {
+			return "", fmt.Errorf("failed to decrypt webhook secret: %w", err)
+		}
+		if !verifyWebhookSignature(hook.Provider, secret, header, body) {
+			return "", ErrWebhookSignature
+		}
+	}
+
+	if isWebhookPing(hook.Provider, header) {
+		return WebhookTriggerIgnored, nil
+	}
+
+	metadata := models.JSON{
+		"action":      "webhook_deploy",
+		"source":      "webhook",
+		"provider":    string(hook.Provider),
+		"webhookId":   hook.ID,
+		"webhookName": hook.Name,
+	}
+	if delivery := webhookDeliveryID(header); delivery != "" {
+		metadata["delivery"] = delivery
+	}
+
+	s.mu.Lock()
+	if _, busy := s.running[hook.ProjectID]; busy {
+		s.running[hook.ProjectID] = true
+		s.mu.Unlock()
+		return WebhookTriggerQueued, nil
+	}
+	s.running[hook.ProjectID] = false
+	s.mu.Unlock()
+
+	go s.runDeploys(context.WithoutCancel(ctx), hook, metadata)
+	return WebhookTriggerStarted, nil
+}
+
+func (s *ProjectWebhookService) runDeploys(ctx context.Context, hook models.ProjectWebhook, metadata models.JSON) {
+	for {
+		s.deploy(ctx, hook, metadata)
+
+		s.mu.Lock()
+		if s.running[hook.ProjectID] {
+			s.running[hook.ProjectID] = false
+			s.mu.Unlock()
+			continue
+		}
+		delete(s.running, hook.ProjectID)
+		s.mu.Unlock()
+		return
+	}
+}
+
+func (s *ProjectWebhookService) deploy(parent context.Context, hook models.ProjectWebhook, metadata models.JSON) {
+	ctx, cancel := context.WithTimeout(parent, webhookDeployTimeout)
+	defer cancel()
+
+	projectName := hook.ProjectID
+	if proj, err := s.projectService.GetProjectFromDatabaseByID(ctx, hook.ProjectID); err == nil {
+		projectName = proj.Name
+	}
+
+	creds, err := s.environmentService.GetEnabledRegistryCredentials(ctx)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to load registry credentials for webhook deploy", "webhook", hook.ID, "error", err)
+		creds = nil
+	}
+
+	err = s.projectService.PullProjectImages(ctx, hook.ProjectID, io.Discard, creds)
+	if err != nil {
+		err = fmt.Errorf("failed to pull project images: %w", err)
+	} else {
+		deployCtx := withDeploymentSource(ctx, deploymentSource{trigger: models.DeploymentTriggerWebhook, detail: hook.Name})
+		err = s.projectService.RedeployProject(deployCtx, hook.ProjectID, systemUser)
+	}
+	s.recordRun(ctx, hook.ID, err)
+
+	if err != nil {
+		slog.ErrorContext(ctx, "webhook deploy failed", "webhook", hook.ID, "projectID", hook.ProjectID, "error", err)
+		s.eventService.LogErrorEvent(ctx, models.EventTypeProjectDeploy, "project", hook.ProjectID, projectName, systemUser.ID, systemUser.Username, "0", err, metadata)
+		return
+	}
+
+	if logErr := s.eventService.LogProjectEvent(ctx, models.EventTypeProjectDeploy, hook.ProjectID, projectName, systemUser.ID, systemUser.Username, "0", metadata); logErr != nil {
+		slog.ErrorContext(ctx, "could not log webhook deploy", "error", logErr)
+	}
+}
+
+func (s *ProjectWebhookService) recordRun(ctx context.Context, webhookID string, runErr error) {
+	now := time.Now()
+	updates := map[string]interface{}{
+		"last_triggered_at": now,
+		"last_status":       "success",
+		"last_error":        nil,
+		"updated_at":        now,
+	}
+	if runErr != nil {
+		updates["last_status"] = "failed"
+		updates["last_error"] = runErr.Error()
+	}
+	if err := s.db.WithContext(ctx).Model(&models.ProjectWebhook{}).Where("id = ?", webhookID).Updates(updates).Error; err != nil {
+		slog.WarnContext(ctx, "failed to record webhook run", "webhook", webhookID, "error", err)
+	}
+}
+
+// verifyWebhookSignature checks the provider's signature header against secret. GitHub,
+// Gitea and generic callers send an HMAC-SHA256 of the body; GitLab sends the secret
+// itself in X-Gitlab-Token.
+func verifyWebhookSignature(provider models.WebhookProvider, secret string, header http.Header, body []byte) bool {
+	switch provider {
+	case models.WebhookProviderGitLab:
+		token := header.Get("X-Gitlab-Token")
+		return token != "" && hmac.Equal([]byte(token), []byte(secret))
+	case models.WebhookProviderGitea:
+		if sig := header.Get("X-Gitea-Signature"); sig != "" {
+			return validHMAC(secret, body, sig)
+		}
+	case models.WebhookProviderGitHub, models.WebhookProviderGeneric:
+	}
+
+	sig, ok := strings.CutPrefix(header.Get("X-Hub-Signature-256"), "sha256=")
+	return ok && validHMAC(secret, body, sig)
+}
+
+func validHMAC(secret string, body []byte, signatureHex string) bool {
+	expected, err := hex.DecodeString(strings.TrimSpace(signatureHex))
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(mac.Sum(nil), expected)
+}
+
+func isWebhookPing(provider models.WebhookProvider, header http.Header) bool {
+	switch provider {
+	case models.WebhookProviderGitHub:
+		return header.Get("X-GitHub-Event") == "ping"
+	case models.WebhookProviderGitea:
+		return header.Get("X-Gitea-Event") == "ping"
+	case models.WebhookProviderGitLab, models.WebhookProviderGeneric:
+	}
+	return false
+}
+
+func webhookDeliveryID(header http.Header) string {
+	for _, key := range []string{"X-GitHub-Delivery", "X-Gitea-Delivery", "X-Gitlab-Event-UUID", "X-Request-Id"} {
+		if v := header.Get(key); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func hashWebhookToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
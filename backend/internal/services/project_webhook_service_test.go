@@ -0,0 +1,84 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"testing"
+
+	glsqlite "github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/ofkm/arcane-backend/internal/config"
+	"github.com/ofkm/arcane-backend/internal/database"
+	"github.com/ofkm/arcane-backend/internal/dto"
+	"github.com/ofkm/arcane-backend/internal/models"
+	"github.com/ofkm/arcane-backend/internal/utils"
+)
+
+func signBody(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestVerifyWebhookSignature(t *testing.T) {
+	body := []byte(`{"ref":"refs/heads/main"}`)
+	secret := "s3cret"
+
+	github := http.Header{}
+	github.Set("X-Hub-Signature-256", "sha256="+signBody(secret, body))
+	require.True(t, verifyWebhookSignature(models.WebhookProviderGitHub, secret, github, body))
+	require.False(t, verifyWebhookSignature(models.WebhookProviderGitHub, "other", github, body))
+	require.False(t, verifyWebhookSignature(models.WebhookProviderGitHub, secret, github, []byte("tampered")))
+
+	gitea := http.Header{}
+	gitea.Set("X-Gitea-Signature", signBody(secret, body))
+	require.True(t, verifyWebhookSignature(models.WebhookProviderGitea, secret, gitea, body))
+
+	gitlab := http.Header{}
+	gitlab.Set("X-Gitlab-Token", secret)
+	require.True(t, verifyWebhookSignature(models.WebhookProviderGitLab, secret, gitlab, body))
+	require.False(t, verifyWebhookSignature(models.WebhookProviderGitLab, secret, http.Header{}, body))
+
+	require.False(t, verifyWebhookSignature(models.WebhookProviderGeneric, secret, http.Header{}, body))
+}
+
+func TestProjectWebhookService_TriggerRejectsBadCalls(t *testing.T) {
+	ctx := context.Background()
+	utils.InitEncryption(&config.Config{})
+
+	gdb, err := gorm.Open(glsqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, gdb.AutoMigrate(&models.Project{}, &models.ProjectWebhook{}))
+	db := &database.DB{DB: gdb}
+
+	proj := &models.Project{Name: "web", Path: t.TempDir()}
+	require.NoError(t, gdb.Create(proj).Error)
+
+	svc := NewProjectWebhookService(db, NewProjectService(db, nil, nil, nil, nil), nil, nil)
+	hook, token, err := svc.CreateWebhook(ctx, proj.ID, dto.CreateProjectWebhookDto{Name: "ci", Provider: "github", Secret: utils.Ptr("s3cret")})
+	require.NoError(t, err)
+	require.NotEqual(t, token, hook.TokenHash)
+	require.Equal(t, token[:webhookTokenPrefixLength], hook.TokenPrefix)
+
+	_, err = svc.Trigger(ctx, "nope", http.Header{}, nil)
+	require.ErrorIs(t, err, ErrWebhookNotFound)
+
+	_, err = svc.Trigger(ctx, token, http.Header{}, []byte("{}"))
+	require.ErrorIs(t, err, ErrWebhookSignature)
+
+	body := []byte(`{"zen":"hi"}`)
+	ping := http.Header{}
+	ping.Set("X-GitHub-Event", "ping")
+	ping.Set("X-Hub-Signature-256", "sha256="+signBody("s3cret", body))
+	status, err := svc.Trigger(ctx, token, ping, body)
+	require.NoError(t, err)
+	require.Equal(t, WebhookTriggerIgnored, status)
+
+	_, _, err = svc.CreateWebhook(ctx, proj.ID, dto.CreateProjectWebhookDto{Name: "x", Provider: "bitbucket"})
+	require.ErrorIs(t, err, ErrInvalidWebhookInput)
+}
@@ -0,0 +1,163 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ofkm/arcane-backend/internal/database"
+	"github.com/ofkm/arcane-backend/internal/models"
+)
+
+const prometheusScrapeTimeout = 10 * time.Second
+
+// PrometheusService is a prometheus.Collector that reads Docker, image update,
+// auto-update and environment state at scrape time.
+type PrometheusService struct {
+	db                 *database.DB
+	dockerService      *DockerClientService
+	imageUpdateService *ImageUpdateService
+
+	dockerUp          *prometheus.Desc
+	containers        *prometheus.Desc
+	images            *prometheus.Desc
+	volumes           *prometheus.Desc
+	networks          *prometheus.Desc
+	imagesTracked     *prometheus.Desc
+	imageUpdates      *prometheus.Desc
+	imageUpdateErrors *prometheus.Desc
+	autoUpdates       *prometheus.Desc
+	autoUpdateLastRun *prometheus.Desc
+	environmentUp     *prometheus.Desc
+	environmentSeen   *prometheus.Desc
+}
+
+func NewPrometheusService(db *database.DB, dockerService *DockerClientService, imageUpdateService *ImageUpdateService) *PrometheusService {
+	return &PrometheusService{
+		db:                 db,
+		dockerService:      dockerService,
+		imageUpdateService: imageUpdateService,
+
+		dockerUp:          prometheus.NewDesc("arcane_docker_up", "Whether the local Docker daemon answered the last scrape.", nil, nil),
+		containers:        prometheus.NewDesc("arcane_containers", "Containers by state.", []string{"state"}, nil),
+		images:            prometheus.NewDesc("arcane_images", "Images by usage.", []string{"usage"}, nil),
+		volumes:           prometheus.NewDesc("arcane_volumes", "Volumes by usage.", []string{"usage"}, nil),
+		networks:          prometheus.NewDesc("arcane_networks", "Networks by usage.", []string{"usage"}, nil),
+		imagesTracked:     prometheus.NewDesc("arcane_image_update_tracked_images", "Images with a stored update check result.", nil, nil),
+		imageUpdates:      prometheus.NewDesc("arcane_image_updates_pending", "Images with an available update by update type.", []string{"type"}, nil),
+		imageUpdateErrors: prometheus.NewDesc("arcane_image_update_check_errors", "Images whose last update check failed.", nil, nil),
+		autoUpdates:       prometheus.NewDesc("arcane_auto_update_records", "Auto-update records by status.", []string{"status"}, nil),
+		autoUpdateLastRun: prometheus.NewDesc("arcane_auto_update_last_run_timestamp_seconds", "Unix time the most recent auto-update run started.", nil, nil),
+		environmentUp:     prometheus.NewDesc("arcane_environment_up", "Whether an environment was online at its last health check.", []string{"environment_id", "environment"}, nil),
+		environmentSeen:   prometheus.NewDesc("arcane_environment_last_seen_timestamp_seconds", "Unix time an environment was last reachable.", []string{"environment_id", "environment"}, nil),
+	}
+}
+
+func (s *PrometheusService) Describe(ch chan<- *prometheus.Desc) {
+	for _, d := range []*prometheus.Desc{
+		s.dockerUp, s.containers, s.images, s.volumes, s.networks,
+		s.imagesTracked, s.imageUpdates, s.imageUpdateErrors,
+		s.autoUpdates, s.autoUpdateLastRun, s.environmentUp, s.environmentSeen,
+	} {
+		ch <- d
+	}
+}
+
+func (s *PrometheusService) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), prometheusScrapeTimeout)
+	defer cancel()
+
+	s.collectDocker(ctx, ch)
+	s.collectImageUpdates(ctx, ch)
+	s.collectAutoUpdates(ctx, ch)
+	s.collectEnvironments(ctx, ch)
+}
+
+func (s *PrometheusService) collectDocker(ctx context.Context, ch chan<- prometheus.Metric) {
+	containers, _, _, _, err := s.dockerService.GetAllContainers(ctx)
+	if err != nil {
+		slog.WarnContext(ctx, "Prometheus scrape could not reach Docker", "error", err)
+		ch <- prometheus.MustNewConstMetric(s.dockerUp, prometheus.GaugeValue, 0)
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(s.dockerUp, prometheus.GaugeValue, 1)
+
+	states := map[string]int{"running": 0, "exited": 0, "paused": 0, "restarting": 0, "created": 0, "dead": 0}
+	for _, c := range containers {
+		states[c.State]++
+	}
+	for state, count := range states {
+		ch <- prometheus.MustNewConstMetric(s.containers, prometheus.GaugeValue, float64(count), state)
+	}
+
+	if _, inuse, unused, _, err := s.dockerService.GetAllImages(ctx); err == nil {
+		s.collectUsage(ch, s.images, inuse, unused)
+	}
+	if _, inuse, unused, _, err := s.dockerService.GetAllVolumes(ctx); err == nil {
+		s.collectUsage(ch, s.volumes, inuse, unused)
+	}
+	if _, inuse, unused, _, err := s.dockerService.GetAllNetworks(ctx); err == nil {
+		s.collectUsage(ch, s.networks, inuse, unused)
+	}
+}
+
+func (s *PrometheusService) collectUsage(ch chan<- prometheus.Metric, desc *prometheus.Desc, inuse, unused int) {
+	ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, float64(inuse), "in_use")
+	ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, float64(unused), "unused")
+}
+
+func (s *PrometheusService) collectImageUpdates(ctx context.Context, ch chan<- prometheus.Metric) {
+	summary, err := s.imageUpdateService.GetUpdateSummary(ctx)
+	if err != nil {
+		slog.WarnContext(ctx, "Prometheus scrape failed to load image update summary", "error", err)
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(s.imagesTracked, prometheus.GaugeValue, float64(summary.TotalImages))
+	ch <- prometheus.MustNewConstMetric(s.imageUpdates, prometheus.GaugeValue, float64(summary.DigestUpdates), "digest")
+	ch <- prometheus.MustNewConstMetric(s.imageUpdates, prometheus.GaugeValue, float64(summary.TagUpdates), "tag")
+	ch <- prometheus.MustNewConstMetric(s.imageUpdateErrors, prometheus.GaugeValue, float64(summary.ErrorsCount))
+}
+
+func (s *PrometheusService) collectAutoUpdates(ctx context.Context, ch chan<- prometheus.Metric) {
+	var rows []struct {
+		Status string
+		Count  int64
+	}
+	if err := s.db.WithContext(ctx).Model(&models.AutoUpdateRecord{}).
+		Select("status, COUNT(*) AS count").
+		Group("status").
+		Scan(&rows).Error; err != nil {
+		slog.WarnContext(ctx, "Prometheus scrape failed to count auto-update records", "error", err)
+		return
+	}
+	for _, row := range rows {
+		ch <- prometheus.MustNewConstMetric(s.autoUpdates, prometheus.GaugeValue, float64(row.Count), row.Status)
+	}
+
+	var latest models.AutoUpdateRecord
+	err := s.db.WithContext(ctx).Order("start_time DESC").Limit(1).Find(&latest).Error
+	if err == nil && !latest.StartTime.IsZero() {
+		ch <- prometheus.MustNewConstMetric(s.autoUpdateLastRun, prometheus.GaugeValue, float64(latest.StartTime.Unix()))
+	}
+}
+
+// collectEnvironments reports the status last written by the environment health job.
+func (s *PrometheusService) collectEnvironments(ctx context.Context, ch chan<- prometheus.Metric) {
+	var environments []models.Environment
+	if err := s.db.WithContext(ctx).Where("enabled = ?", true).Find(&environments).Error; err != nil {
+		slog.WarnContext(ctx, "Prometheus scrape failed to list environments", "error", err)
+		return
+	}
+	for _, env := range environments {
+		up := 0.0
+		if env.Status == string(models.EnvironmentStatusOnline) {
+			up = 1
+		}
+		ch <- prometheus.MustNewConstMetric(s.environmentUp, prometheus.GaugeValue, up, env.ID, env.Name)
+		if env.LastSeen != nil {
+			ch <- prometheus.MustNewConstMetric(s.environmentSeen, prometheus.GaugeValue, float64(env.LastSeen.Unix()), env.ID, env.Name)
+		}
+	}
+}
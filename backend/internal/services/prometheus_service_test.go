@@ -0,0 +1,66 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ofkm/arcane-backend/internal/models"
+)
+
+func drainMetrics(t *testing.T, collect func(ch chan<- prometheus.Metric)) []*dto.Metric {
+	t.Helper()
+	ch := make(chan prometheus.Metric, 64)
+	collect(ch)
+	close(ch)
+
+	var out []*dto.Metric
+	for m := range ch {
+		var pb dto.Metric
+		require.NoError(t, m.Write(&pb))
+		out = append(out, &pb)
+	}
+	return out
+}
+
+func TestPrometheusService_AutoUpdatesAndEnvironments(t *testing.T) {
+	ctx := context.Background()
+	db := setupSettingsTestDB(t)
+	require.NoError(t, db.AutoMigrate(&models.AutoUpdateRecord{}, &models.Environment{}))
+	svc := NewPrometheusService(db, nil, nil)
+
+	started := time.Date(2025, 6, 1, 3, 0, 0, 0, time.UTC)
+	require.NoError(t, db.Create(&[]models.AutoUpdateRecord{
+		{ResourceName: "web", Status: models.AutoUpdateStatusCompleted, StartTime: started.Add(-time.Hour)},
+		{ResourceName: "db", Status: models.AutoUpdateStatusCompleted, StartTime: started},
+		{ResourceName: "api", Status: models.AutoUpdateStatusFailed, StartTime: started.Add(-2 * time.Hour)},
+	}).Error)
+
+	metrics := drainMetrics(t, func(ch chan<- prometheus.Metric) { svc.collectAutoUpdates(ctx, ch) })
+	require.Len(t, metrics, 3)
+	counts := map[string]float64{}
+	for _, m := range metrics[:2] {
+		counts[m.GetLabel()[0].GetValue()] = m.GetGauge().GetValue()
+	}
+	require.Equal(t, map[string]float64{"completed": 2, "failed": 1}, counts)
+	require.Equal(t, float64(started.Unix()), metrics[2].GetGauge().GetValue())
+
+	seen := started
+	require.NoError(t, db.Create(&[]models.Environment{
+		{Name: "local", Status: string(models.EnvironmentStatusOnline), Enabled: true, LastSeen: &seen},
+		{Name: "edge", Status: string(models.EnvironmentStatusOffline), Enabled: true},
+	}).Error)
+
+	metrics = drainMetrics(t, func(ch chan<- prometheus.Metric) { svc.collectEnvironments(ctx, ch) })
+	require.Len(t, metrics, 3)
+	// local reports up plus last-seen, edge only up; labels are sorted so [0] is the name.
+	require.Equal(t, "local", metrics[0].GetLabel()[0].GetValue())
+	require.InDelta(t, 1, metrics[0].GetGauge().GetValue(), 0)
+	require.Equal(t, float64(seen.Unix()), metrics[1].GetGauge().GetValue())
+	require.Equal(t, "edge", metrics[2].GetLabel()[0].GetValue())
+	require.InDelta(t, 0, metrics[2].GetGauge().GetValue(), 0)
+}
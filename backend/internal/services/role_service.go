@@ -0,0 +1,381 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"github.com/ofkm/arcane-backend/internal/database"
+	"github.com/ofkm/arcane-backend/internal/dto"
+	"github.com/ofkm/arcane-backend/internal/models"
+)
+
+var (
+	ErrRoleNotFound        = errors.New("role not found")
+	ErrRoleBuiltIn         = errors.New("built-in roles cannot be modified")
+	ErrRoleExists          = errors.New("role already exists")
+	ErrRoleInUse           = errors.New("role is still assigned to users")
+	ErrRoleMapped          = errors.New("role is still used by OIDC role mappings")
+	ErrRoleBindingNotFound = errors.New("role binding not found")
+	ErrInvalidRoleBinding  = errors.New("invalid role binding")
+)
+
+// GlobalPermissionScope is the key used for permissions that apply to every environment.
+const GlobalPermissionScope = "*"
+
+type RoleService struct {
+	db    *database.DB
+	audit *AuditService
+}
+
+func NewRoleService(db *database.DB, audit *AuditService) *RoleService {
+	return &RoleService{db: db, audit: audit}
+}
+
+func (s *RoleService) ListRoles(ctx context.Context) ([]models.Role, error) {
+	var custom []models.Role
+	if err := s.db.WithContext(ctx).Order("name ASC").Find(&custom).Error; err != nil {
+		return nil, fmt.Errorf("failed to list roles: %w", err)
+	}
+
+	roles := make([]models.Role, 0, len(models.BuiltinRoles)+len(custom))
+	roles = append(roles, models.BuiltinRoles...)
+	roles = append(roles, custom...)
+	return roles, nil
+}
+
+func (s *RoleService) GetRole(ctx context.Context, name string) (*models.Role, error) {
+	if role, ok := models.GetBuiltinRole(name); ok {
+		return &role, nil
+	}
+
+	var role models.Role
+	if err := s.db.WithContext(ctx).Where("name = ?", name).First(&role).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRoleNotFound
+		}
+		return nil, fmt.Errorf("failed to get role: %w", err)
+	}
+	return &role, nil
+}
+
+func (s *RoleService) CreateRole(ctx context.Context, req dto.CreateRoleDto) (*models.Role, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, fmt.Errorf("role name is required")
+	}
+	if _, ok := models.GetBuiltinRole(name); ok {
+		return nil, ErrRoleExists
+	}
+	if _, err := s.GetRole(ctx, name); err == nil {
+		return nil, ErrRoleExists
+	}
+
+	role := &models.Role{
+		Name:        name,
+		Description: req.Description,
+		Permissions: normalizePermissions(req.Permissions),
+	}
+	if err := s.db.WithContext(ctx).Create(role).Error; err != nil {
+		return nil, fmt.Errorf("failed to create role: %w", err)
+	}
+
+	s.audit.Record(ctx, AuditChange{
+		Action:     auditActionCreate,
+		TargetType: auditTargetRole,
+		TargetID:   role.ID,
+		TargetName: role.Name,
+		After:      role,
+	})
+	return role, nil
+}
+
+func (s *RoleService) UpdateRole(ctx context.Context, name string, req dto.UpdateRoleDto) (*models.Role, error) {
+	if _, ok := models.GetBuiltinRole(name); ok {
+		return nil, ErrRoleBuiltIn
+	}
+
+	role, err := s.GetRole(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	before := *role
+
+	if req.Description != nil {
+		role.Description = req.Description
+	}
+	if req.Permissions != nil {
+		role.Permissions = normalizePermissions(req.Permissions)
+	}
+
+	if err := s.db.WithContext(ctx).Save(role).Error; err != nil {
+		return nil, fmt.Errorf("failed to update role: %w", err)
+	}
+
+	s.audit.Record(ctx, AuditChange{
+		Action:     auditActionUpdate,
+		TargetType: auditTargetRole,
+		TargetID:   role.ID,
+		TargetName: role.Name,
+		Before:     &before,
+		After:      role,
+	})
+	return role, nil
+}
+
+func (s *RoleService) DeleteRole(ctx context.Context, name string) error {
+	if _, ok := models.GetBuiltinRole(name); ok {
+		return ErrRoleBuiltIn
+	}
+	before, err := s.GetRole(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	var count int64
+	if err := s.db.WithContext(ctx).Model(&models.RoleBinding{}).Where("role_name = ?", name).Count(&count).Error; err != nil {
+		return fmt.Errorf("failed to check role bindings: %w", err)
+	}
+	if count > 0 {
+		return ErrRoleInUse
+	}
+	if err := s.db.WithContext(ctx).Model(&models.OidcRoleMapping{}).Where("role_name = ?", name).Count(&count).Error; err != nil {
+		return fmt.Errorf("failed to check OIDC role mappings: %w", err)
+	}
+	if count > 0 {
+		return ErrRoleMapped
+	}
+
+	result := s.db.WithContext(ctx).Where("name = ?", name).Delete(&models.Role{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete role: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrRoleNotFound
+	}
+
+	s.audit.Record(ctx, AuditChange{
+		Action:     auditActionDelete,
+		TargetType: auditTargetRole,
+		TargetID:   before.ID,
+		TargetName: before.Name,
+		Before:     before,
+	})
+	return nil
+}
+
+func (s *RoleService) ListUserBindings(ctx context.Context, userID string) ([]models.RoleBinding, error) {
+	var bindings []models.RoleBinding
+	if err := s.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at ASC").Find(&bindings).Error; err != nil {
+		return nil, fmt.Errorf("failed to list role bindings: %w", err)
+	}
+	return bindings, nil
+}
+
+func (s *RoleService) CreateBinding(ctx context.Context, userID string, req dto.CreateRoleBindingDto) (*models.RoleBinding, error) {
+	if _, err := s.GetRole(ctx, req.RoleName); err != nil {
+		return nil, err
+	}
+
+	envID := trimmedOrNil(req.EnvironmentID)
+	projectID := trimmedOrNil(req.ProjectID)
+	if projectID != nil && envID == nil {
+		return nil, fmt.Errorf("%w: a project binding requires an environment", ErrInvalidRoleBinding)
+	}
+
+	binding := &models.RoleBinding{
+		UserID:        userID,
+		RoleName:      req.RoleName,
+		EnvironmentID: envID,
+		ProjectID:     projectID,
+	}
+	if err := s.db.WithContext(ctx).Create(binding).Error; err != nil {
+		return nil, fmt.Errorf("failed to create role binding: %w", err)
+	}
+
+	s.audit.Record(ctx, AuditChange{
+		Action:     auditActionCreate,
+		TargetType: auditTargetRoleBinding,
+		TargetID:   binding.ID,
+		TargetName: binding.RoleName,
+		After:      binding,
+	})
+	return binding, nil
+}
+
+func (s *RoleService) DeleteBinding(ctx context.Context, userID, bindingID string) error {
+	var before models.RoleBinding
+	if err := s.db.WithContext(ctx).Where("id = ? AND user_id = ?", bindingID, userID).First(&before).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrRoleBindingNotFound
+		}
+		return fmt.Errorf("failed to get role binding: %w", err)
+	}
+
+	result := s.db.WithContext(ctx).Where("id = ? AND user_id = ?", bindingID, userID).Delete(&models.RoleBinding{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete role binding: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrRoleBindingNotFound
+	}
+
+	s.audit.Record(ctx, AuditChange{
+		Action:     auditActionDelete,
+		TargetType: auditTargetRoleBinding,
+		TargetID:   bindingID,
+		TargetName: before.RoleName,
+		Before:     &before,
+	})
+	return nil
+}
+
+// HasPermission checks the user's global roles and any bindings that cover the
+// given environment and project. An empty environmentID only considers global grants;
+// project bindings only apply when a projectID is given.
+func (s *RoleService) HasPermission(ctx context.Context, user *models.User, permission models.Permission, environmentID, projectID string) (bool, error) {
+	if user == nil {
+		return false, nil
+	}
+	if hasRole(user.Roles, models.RoleAdmin) {
+		return true, nil
+	}
+
+	granted, err := s.grantedPermissions(ctx, user, environmentID, projectID)
+	if err != nil {
+		return false, err
+	}
+	return models.PermissionsAllow(granted, permission), nil
+}
+
+// EffectivePermissions returns the permissions a user holds keyed by scope:
+// "*" for global grants, "<envId>" for environment grants and "<envId>/<projectId>"
+// for project grants. Scoped entries already include the global permissions.
+func (s *RoleService) EffectivePermissions(ctx context.Context, user *models.User) (map[string][]string, error) {
+	global, err := s.permissionsForRoles(ctx, user.Roles)
+	if err != nil {
+		return nil, err
+	}
+
+	bindings, err := s.ListUserBindings(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	scoped := map[string][]string{}
+	for _, b := range bindings {
+		perms, err := s.permissionsForRoles(ctx, []string{b.RoleName})
+		if err != nil {
+			return nil, err
+		}
+		if b.EnvironmentID == nil {
+			global = mergePermissions(global, perms)
+			continue
+		}
+		scope := *b.EnvironmentID
+		if b.ProjectID != nil {
+			scope += "/" + *b.ProjectID
+		}
+		scoped[scope] = mergePermissions(scoped[scope], perms)
+	}
+
+	out := map[string][]string{GlobalPermissionScope: global}
+	for scope, perms := range scoped {
+		combined := mergePermissions(append([]string{}, global...), perms)
+		// Environment grants also apply to every project inside that environment.
+		if envID, _, isProject := strings.Cut(scope, "/"); isProject {
+			combined = mergePermissions(combined, scoped[envID])
+		}
+		out[scope] = combined
+	}
+
+	return out, nil
+}
+
+func (s *RoleService) grantedPermissions(ctx context.Context, user *models.User, environmentID, projectID string) ([]string, error) {
+	roleNames := append([]string{}, user.Roles...)
+
+	if user.ID != "" {
+		q := s.db.WithContext(ctx).Model(&models.RoleBinding{}).Where("user_id = ?", user.ID)
+		if environmentID == "" {
+			q = q.Where("environment_id IS NULL")
+		} else {
+			q = q.Where("environment_id IS NULL OR environment_id = ?", environmentID)
+		}
+		if projectID == "" {
+			q = q.Where("project_id IS NULL")
+		} else {
+			q = q.Where("project_id IS NULL OR project_id = ?", projectID)
+		}
+
+		var bindings []models.RoleBinding
+		if err := q.Find(&bindings).Error; err != nil {
+			return nil, fmt.Errorf("failed to load role bindings: %w", err)
+		}
+		for _, b := range bindings {
+			roleNames = append(roleNames, b.RoleName)
+		}
+	}
+
+	return s.permissionsForRoles(ctx, roleNames)
+}
+
+func (s *RoleService) permissionsForRoles(ctx context.Context, roleNames []string) ([]string, error) {
+	out := []string{}
+	var custom []string
+	for _, name := range roleNames {
+		if role, ok := models.GetBuiltinRole(name); ok {
+			out = mergePermissions(out, role.Permissions)
+			continue
+		}
+		custom = append(custom, name)
+	}
+
+	if len(custom) == 0 {
+		return out, nil
+	}
+
+	var roles []models.Role
+	if err := s.db.WithContext(ctx).Where("name IN ?", custom).Find(&roles).Error; err != nil {
+		return nil, fmt.Errorf("failed to load roles: %w", err)
+	}
+	for _, r := range roles {
+		out = mergePermissions(out, r.Permissions)
+	}
+	return out, nil
+}
+
+func mergePermissions(dst []string, src []string) []string {
+	for _, p := range src {
+		if !slices.Contains(dst, p) {
+			dst = append(dst, p)
+		}
+	}
+	return dst
+}
+
+func normalizePermissions(perms []string) models.StringSlice {
+	out := models.StringSlice{}
+	for _, p := range perms {
+		p = strings.ToLower(strings.TrimSpace(p))
+		if p != "" && !slices.Contains(out, p) {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+func trimmedOrNil(s *string) *string {
+	if s == nil {
+		return nil
+	}
+	v := strings.TrimSpace(*s)
+	if v == "" {
+		return nil
+	}
+	return &v
+}
@@ -0,0 +1,126 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	glsqlite "github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/ofkm/arcane-backend/internal/database"
+	"github.com/ofkm/arcane-backend/internal/dto"
+	"github.com/ofkm/arcane-backend/internal/models"
+	"github.com/ofkm/arcane-backend/internal/utils"
+	"github.com/ofkm/arcane-backend/internal/utils/pagination"
+)
+
+func setupRoleTestDB(t *testing.T) *database.DB {
+	t.Helper()
+	db, err := gorm.Open(glsqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.Role{}, &models.RoleBinding{}, &models.OidcRoleMapping{}))
+	return &database.DB{DB: db}
+}
+
+func TestPermissionsAllow_Wildcards(t *testing.T) {
+	require.True(t, models.PermissionsAllow([]string{"*"}, models.PermissionProjectsDelete))
+	require.True(t, models.PermissionsAllow([]string{"projects:*"}, models.PermissionProjectsDelete))
+	require.False(t, models.PermissionsAllow([]string{"containers:*"}, models.PermissionProjectsDelete))
+	require.False(t, models.PermissionsAllow([]string{"projects:read"}, models.PermissionProjectsDelete))
+}
+
+func TestRoleService_HasPermission_EnvironmentScoped(t *testing.T) {
+	ctx := context.Background()
+	svc := NewRoleService(setupRoleTestDB(t), nil)
+
+	user := &models.User{BaseModel: models.BaseModel{ID: "u1"}, Username: "oncall", Roles: models.StringSlice{models.RoleViewer}}
+	_, err := svc.CreateBinding(ctx, user.ID, dto.CreateRoleBindingDto{RoleName: models.RoleOperator, EnvironmentID: utils.Ptr("prod")})
+	require.NoError(t, err)
+
+	ok, err := svc.HasPermission(ctx, user, models.PermissionContainersOperate, "prod", "")
+	require.NoError(t, err)
+	require.True(t, ok, "operator binding on prod should allow restarts there")
+
+	ok, err = svc.HasPermission(ctx, user, models.PermissionContainersOperate, "staging", "")
+	require.NoError(t, err)
+	require.False(t, ok, "binding must not leak into other environments")
+
+	ok, err = svc.HasPermission(ctx, user, models.PermissionProjectsDelete, "prod", "web")
+	require.NoError(t, err)
+	require.False(t, ok, "operator cannot destroy projects")
+
+	ok, err = svc.HasPermission(ctx, user, models.PermissionContainersRead, "staging", "")
+	require.NoError(t, err)
+	require.True(t, ok, "global viewer role applies everywhere")
+}
+
+func TestRoleService_HasPermission_ProjectScopedAndCustomRole(t *testing.T) {
+	ctx := context.Background()
+	svc := NewRoleService(setupRoleTestDB(t), nil)
+
+	_, err := svc.CreateRole(ctx, dto.CreateRoleDto{Name: "web-deployer", Permissions: []string{"projects:deploy", " Projects:Read "}})
+	require.NoError(t, err)
+
+	user := &models.User{BaseModel: models.BaseModel{ID: "u2"}, Username: "ci"}
+	_, err = svc.CreateBinding(ctx, user.ID, dto.CreateRoleBindingDto{RoleName: "web-deployer", EnvironmentID: utils.Ptr("0"), ProjectID: utils.Ptr("web")})
+	require.NoError(t, err)
+
+	ok, err := svc.HasPermission(ctx, user, models.PermissionProjectsDeploy, "0", "web")
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	ok, err = svc.HasPermission(ctx, user, models.PermissionProjectsDeploy, "0", "db")
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	perms, err := svc.EffectivePermissions(ctx, user)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"projects:deploy", "projects:read"}, perms["0/web"])
+	require.Empty(t, perms[GlobalPermissionScope])
+
+	_, err = svc.CreateBinding(ctx, user.ID, dto.CreateRoleBindingDto{RoleName: models.RoleViewer, ProjectID: utils.Ptr("web")})
+	require.ErrorIs(t, err, ErrInvalidRoleBinding)
+
+	require.ErrorIs(t, svc.DeleteRole(ctx, "web-deployer"), ErrRoleInUse)
+	require.ErrorIs(t, svc.DeleteRole(ctx, models.RoleAdmin), ErrRoleBuiltIn)
+}
+
+func TestRoleService_AuditsRolesBindingsAndTokens(t *testing.T) {
+	ctx := auditTestContext()
+	audit := setupAuditService(t)
+	require.NoError(t, audit.db.AutoMigrate(&models.User{}, &models.Role{}, &models.RoleBinding{}, &models.OidcRoleMapping{}, &models.ApiToken{}))
+	roles := NewRoleService(audit.db, audit)
+	tokens := NewApiTokenService(audit.db, NewUserService(audit.db, nil), audit)
+
+	_, err := roles.CreateRole(ctx, dto.CreateRoleDto{Name: "auditor", Permissions: []string{"projects:read"}})
+	require.NoError(t, err)
+	_, err = roles.UpdateRole(ctx, "auditor", dto.UpdateRoleDto{Permissions: []string{"projects:read", "containers:read"}})
+	require.NoError(t, err)
+	binding, err := roles.CreateBinding(ctx, "u1", dto.CreateRoleBindingDto{RoleName: "auditor", EnvironmentID: utils.Ptr("prod")})
+	require.NoError(t, err)
+	require.NoError(t, roles.DeleteBinding(ctx, "u1", binding.ID))
+	require.NoError(t, roles.DeleteRole(ctx, "auditor"))
+
+	token, raw, err := tokens.CreateToken(ctx, "u1", dto.CreateApiTokenDto{Name: "ci", Scopes: []string{"projects:read"}})
+	require.NoError(t, err)
+	require.NoError(t, tokens.DeleteToken(ctx, "u1", token.ID))
+
+	entries, _, err := audit.ListAuditLogsPaginated(ctx, pagination.QueryParams{})
+	require.NoError(t, err)
+	byAction := map[string]models.AuditLog{}
+	for _, e := range entries {
+		byAction[e.Action] = e
+		require.NotContains(t, fmt.Sprint(e.Changes), raw, "the plaintext token is never stored")
+	}
+	require.Len(t, byAction, 7)
+
+	require.Equal(t, "auditor", *byAction["role.update"].TargetName)
+	require.Equal(t, map[string]any{"before": []any{"projects:read"}, "after": []any{"projects:read", "containers:read"}}, byAction["role.update"].Changes["permissions"])
+	require.Equal(t, map[string]any{"after": "prod"}, byAction["role_binding.create"].Changes["environmentId"])
+	require.Equal(t, map[string]any{"before": "auditor"}, byAction["role_binding.delete"].Changes["roleName"])
+	require.Contains(t, byAction, "role.delete")
+	require.Equal(t, "ci", *byAction["api_token.create"].TargetName)
+	require.Contains(t, byAction, "api_token.delete")
+}
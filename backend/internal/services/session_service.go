@@ -0,0 +1,239 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/ofkm/arcane-backend/internal/database"
+	"github.com/ofkm/arcane-backend/internal/models"
+	"github.com/ofkm/arcane-backend/internal/utils"
+)
+
+const (
+	// Only persist last-seen timestamps this often to avoid a write per request.
+	sessionLastSeenInterval = time.Minute
+	// Revoked and expired sessions are kept this long so they still show up when
+	// investigating a login, then removed by the cleanup job.
+	sessionRetention = 30 * 24 * time.Hour
+
+	SessionRevokedLogout       = "logout"
+	SessionRevokedByUser       = "revoked_by_user"
+	SessionRevokedByAdmin      = "revoked_by_admin"
+	SessionRevokedPassword     = "password_changed"
+	SessionRevokedRefreshReuse = "refresh_token_reused"
+)
+
+var (
+	ErrSessionNotFound    = errors.New("session not found")
+	ErrSessionRevoked     = errors.New("session has been revoked")
+	ErrRefreshTokenReused = errors.New("refresh token has already been used")
+)
+
+// SessionClient describes where a login or refresh came from. Handlers attach it to the
+// request context with WithSessionClient before calling the auth service.
+type SessionClient struct {
+	IPAddress string
+	UserAgent string
+}
+
+type sessionClientKey struct{}
+
+func WithSessionClient(ctx context.Context, client SessionClient) context.Context {
+	return context.WithValue(ctx, sessionClientKey{}, client)
+}
+
+func sessionClientFromContext(ctx context.Context) SessionClient {
+	client, _ := ctx.Value(sessionClientKey{}).(SessionClient)
+	return client
+}
+
+type SessionService struct {
+	db           *database.DB
+	eventService *EventService
+}
+
+func NewSessionService(db *database.DB, eventService *EventService) *SessionService {
+	return &SessionService{db: db, eventService: eventService}
+}
+
+// CreateSession starts a session for user and returns it with the ID of its first
+// refresh token.
+func (s *SessionService) CreateSession(ctx context.Context, user *models.User, method string, expiresAt time.Time) (*models.AuthSession, string, error) {
+	client := sessionClientFromContext(ctx)
+	refreshID := utils.GenerateRandomString(32)
+	session := &models.AuthSession{
+		UserID:         user.ID,
+		Method:         method,
+		RefreshTokenID: refreshID,
+		UserAgent:      client.UserAgent,
+		IPAddress:      client.IPAddress,
+		LastSeenAt:     time.Now(),
+		ExpiresAt:      expiresAt,
+	}
+	if err := s.db.WithContext(ctx).Create(session).Error; err != nil {
+		return nil, "", fmt.Errorf("failed to create session: %w", err)
+	}
+	return session, refreshID, nil
+}
+
+// ValidateSession is called for every request made with an access token. It fails once
+// the session is revoked or expired, or if it belongs to another user.
+func (s *SessionService) ValidateSession(ctx context.Context, sessionID, userID string) error {
+	session, err := s.getSession(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	if session.UserID != userID {
+		return ErrSessionNotFound
+	}
+	if !session.Active(now) {
+		return ErrSessionRevoked
+	}
+
+	if now.Sub(session.LastSeenAt) >= sessionLastSeenInterval {
+		if err := s.db.WithContext(ctx).Model(&models.AuthSession{}).Where("id = ?", session.ID).Update("last_seen_at", now).Error; err != nil {
+			slog.WarnContext(ctx, "Failed to update session last seen time", "session", session.ID, "error", err)
+		}
+	}
+	return nil
+}
+
+// RotateRefreshToken exchanges refreshID for a new refresh token ID. A refresh token
+// may only be used once: presenting one that was already exchanged means it was copied,
+// so the whole session is revoked and the reuse is logged.
+func (s *SessionService) RotateRefreshToken(ctx context.Context, sessionID, refreshID string, expiresAt time.Time) (*models.AuthSession, string, error) {
+	session, err := s.getSession(ctx, sessionID)
+	if err != nil {
+		return nil, "", err
+	}
+	if !session.Active(time.Now()) {
+		return nil, "", ErrSessionRevoked
+	}
+	if session.RefreshTokenID != refreshID {
+		if err := s.revoke(ctx, s.db.WithContext(ctx).Where("id = ?", session.ID), SessionRevokedRefreshReuse); err != nil {
+			return nil, "", err
+		}
+		s.logRevoked(ctx, session.UserID, models.JSON{"session": session.ID, "reason": SessionRevokedRefreshReuse, "ipAddress": sessionClientFromContext(ctx).IPAddress})
+		return nil, "", ErrRefreshTokenReused
+	}
+
+	client := sessionClientFromContext(ctx)
+	next := utils.GenerateRandomString(32)
+	now := time.Now()
+	updates := map[string]any{"refresh_token_id": next, "last_seen_at": now, "expires_at": expiresAt}
+	if client.IPAddress != "" {
+		updates["ip_address"] = client.IPAddress
+	}
+	if client.UserAgent != "" {
+		updates["user_agent"] = client.UserAgent
+	}
+	// Conditional on the old ID so two concurrent refreshes cannot both succeed.
+	res := s.db.WithContext(ctx).Model(&models.AuthSession{}).
+		Where("id = ? AND refresh_token_id = ? AND revoked_at IS NULL", session.ID, refreshID).
+		Updates(updates)
+	if res.Error != nil {
+		return nil, "", fmt.Errorf("failed to rotate refresh token: %w", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return nil, "", ErrSessionRevoked
+	}
+	return session, next, nil
+}
+
+// ListSessions returns the user's active sessions, most recently used first.
+func (s *SessionService) ListSessions(ctx context.Context, userID string) ([]models.AuthSession, error) {
+	var sessions []models.AuthSession
+	err := s.db.WithContext(ctx).
+		Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Order("last_seen_at DESC").
+		Find(&sessions).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+	return sessions, nil
+}
+
+// RevokeSession revokes one of the user's sessions.
+func (s *SessionService) RevokeSession(ctx context.Context, userID, sessionID, reason string) error {
+	session, err := s.getSession(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+	if session.UserID != userID {
+		return ErrSessionNotFound
+	}
+	if session.RevokedAt != nil {
+		return nil
+	}
+	return s.revoke(ctx, s.db.WithContext(ctx).Where("id = ?", session.ID), reason)
+}
+
+// RevokeUserSessions revokes every active session of the user except keepSessionID
+// (pass "" to revoke all) and returns how many were revoked.
+func (s *SessionService) RevokeUserSessions(ctx context.Context, userID, keepSessionID, reason string) (int64, error) {
+	q := s.db.WithContext(ctx).Where("user_id = ?", userID)
+	if keepSessionID != "" {
+		q = q.Where("id <> ?", keepSessionID)
+	}
+	res := q.Model(&models.AuthSession{}).Where("revoked_at IS NULL").Updates(map[string]any{"revoked_at": time.Now(), "revoked_reason": reason})
+	if res.Error != nil {
+		return 0, fmt.Errorf("failed to revoke sessions: %w", res.Error)
+	}
+	if res.RowsAffected > 0 && reason != SessionRevokedLogout {
+		s.logRevoked(ctx, userID, models.JSON{"reason": reason, "count": res.RowsAffected})
+	}
+	return res.RowsAffected, nil
+}
+
+// DeleteInactiveSessions removes sessions that expired or were revoked more than the
+// retention period ago.
+func (s *SessionService) DeleteInactiveSessions(ctx context.Context) (int64, error) {
+	cutoff := time.Now().Add(-sessionRetention)
+	res := s.db.WithContext(ctx).
+		Where("expires_at < ? OR revoked_at < ?", cutoff, cutoff).
+		Delete(&models.AuthSession{})
+	if res.Error != nil {
+		return 0, fmt.Errorf("failed to delete inactive sessions: %w", res.Error)
+	}
+	return res.RowsAffected, nil
+}
+
+func (s *SessionService) getSession(ctx context.Context, sessionID string) (*models.AuthSession, error) {
+	var session models.AuthSession
+	if err := s.db.WithContext(ctx).Where("id = ?", sessionID).First(&session).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSessionNotFound
+		}
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+	return &session, nil
+}
+
+func (s *SessionService) revoke(ctx context.Context, q *gorm.DB, reason string) error {
+	err := q.Model(&models.AuthSession{}).Where("revoked_at IS NULL").
+		Updates(map[string]any{"revoked_at": time.Now(), "revoked_reason": reason}).Error
+	if err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+	return nil
+}
+
+func (s *SessionService) logRevoked(ctx context.Context, userID string, metadata models.JSON) {
+	if s.eventService == nil {
+		return
+	}
+	username := ""
+	var user models.User
+	if err := s.db.WithContext(ctx).Select("username").Where("id = ?", userID).Limit(1).Find(&user).Error; err == nil {
+		username = user.Username
+	}
+	if err := s.eventService.LogUserEvent(ctx, models.EventTypeUserSessionRevoked, userID, username, metadata); err != nil {
+		slog.WarnContext(ctx, "Could not log session revocation", "user", userID, "error", err)
+	}
+}
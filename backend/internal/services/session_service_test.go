@@ -0,0 +1,86 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ofkm/arcane-backend/internal/models"
+)
+
+func setupSessionAuthService(t *testing.T) (*AuthService, *models.User) {
+	t.Helper()
+	svc, user := setupTotpAuthService(t)
+	require.NoError(t, svc.userService.db.AutoMigrate(&models.AuthSession{}))
+	svc.sessionService = NewSessionService(svc.userService.db, svc.eventService)
+	return svc, user
+}
+
+func TestSessions_RefreshRotationAndReuse(t *testing.T) {
+	ctx := WithSessionClient(context.Background(), SessionClient{IPAddress: "10.0.0.5", UserAgent: "Firefox"})
+	svc, user := setupSessionAuthService(t)
+
+	_, first, _, err := svc.Login(ctx, "alice", "secret-password")
+	require.NoError(t, err)
+	_, sessionID, err := svc.VerifyTokenSession(ctx, first.AccessToken)
+	require.NoError(t, err)
+
+	sessions, err := svc.sessionService.ListSessions(ctx, user.ID)
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	require.Equal(t, sessionID, sessions[0].ID)
+	require.Equal(t, "local", sessions[0].Method)
+	require.Equal(t, "10.0.0.5", sessions[0].IPAddress)
+	require.Equal(t, "Firefox", sessions[0].UserAgent)
+
+	second, err := svc.RefreshToken(ctx, first.RefreshToken)
+	require.NoError(t, err)
+	_, err = svc.VerifyToken(ctx, second.AccessToken)
+	require.NoError(t, err)
+
+	// Replaying the first refresh token revokes the whole family.
+	_, err = svc.RefreshToken(ctx, first.RefreshToken)
+	require.ErrorIs(t, err, ErrRefreshTokenReused)
+	require.ErrorIs(t, err, ErrInvalidToken)
+	_, err = svc.VerifyToken(ctx, second.AccessToken)
+	require.ErrorIs(t, err, ErrInvalidToken)
+	_, err = svc.RefreshToken(ctx, second.RefreshToken)
+	require.ErrorIs(t, err, ErrSessionRevoked)
+
+	var events []models.Event
+	require.NoError(t, svc.userService.db.Where("type = ?", models.EventTypeUserSessionRevoked).Find(&events).Error)
+	require.Len(t, events, 1)
+	require.Equal(t, models.EventSeverityWarning, events[0].Severity)
+}
+
+func TestSessions_Revocation(t *testing.T) {
+	ctx := context.Background()
+	svc, user := setupSessionAuthService(t)
+
+	_, laptop, _, err := svc.Login(ctx, "alice", "secret-password")
+	require.NoError(t, err)
+	_, phone, _, err := svc.Login(ctx, "alice", "secret-password")
+	require.NoError(t, err)
+	_, laptopSession, err := svc.VerifyTokenSession(ctx, laptop.AccessToken)
+	require.NoError(t, err)
+
+	// Changing the password keeps the current session and signs out the others.
+	require.NoError(t, svc.ChangePassword(ctx, user.ID, laptopSession, "secret-password", "new-secret-password"))
+	_, err = svc.VerifyToken(ctx, laptop.AccessToken)
+	require.NoError(t, err)
+	_, err = svc.VerifyToken(ctx, phone.AccessToken)
+	require.ErrorIs(t, err, ErrInvalidToken)
+
+	require.ErrorIs(t, svc.sessionService.RevokeSession(ctx, "someone-else", laptopSession, SessionRevokedByAdmin), ErrSessionNotFound)
+
+	revoked, err := svc.sessionService.RevokeUserSessions(ctx, user.ID, "", SessionRevokedByAdmin)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), revoked)
+	_, err = svc.VerifyToken(ctx, laptop.AccessToken)
+	require.ErrorIs(t, err, ErrInvalidToken)
+
+	sessions, err := svc.sessionService.ListSessions(ctx, user.ID)
+	require.NoError(t, err)
+	require.Empty(t, sessions)
+}
@@ -27,14 +27,18 @@ import (
 type SettingsService struct {
 	db     *database.DB
 	config atomic.Pointer[models.Settings]
+	audit  *AuditService
 
 	OnImagePollingSettingsChanged func(ctx context.Context)
 	OnAutoUpdateSettingsChanged   func(ctx context.Context)
+	OnMetricsSettingsChanged      func(ctx context.Context)
+	OnEventSinkSettingsChanged    func(ctx context.Context)
 }
 
-func NewSettingsService(ctx context.Context, db *database.DB) (*SettingsService, error) {
+func NewSettingsService(ctx context.Context, db *database.DB, audit *AuditService) (*SettingsService, error) {
 	svc := &SettingsService{
-		db: db,
+		db:    db,
+		audit: audit,
 	}
 
 	err := svc.LoadDatabaseSettings(ctx)
@@ -80,6 +84,13 @@ func (s *SettingsService) getDefaultSettings() *models.Settings {
 		DiskUsagePath:              models.SettingVariable{Value: "data/projects"},
 		AutoUpdate:                 models.SettingVariable{Value: "false"},
 		AutoUpdateInterval:         models.SettingVariable{Value: "1440"},
+		AutoUpdateRollback:         models.SettingVariable{Value: "true"},
+		AutoUpdateHealthTimeout:    models.SettingVariable{Value: "120"},
+		AutoUpdateGracePeriod:      models.SettingVariable{Value: "30"},
+		AutoUpdatePolicy:           models.SettingVariable{Value: "digest"},
+		AutoUpdateWindow:           models.SettingVariable{Value: ""},
+		AutoUpdateWindowTimezone:   models.SettingVariable{Value: "UTC"},
+		AutoUpdateWindowDuration:   models.SettingVariable{Value: "60"},
 		PollingEnabled:             models.SettingVariable{Value: "true"},
 		PollingInterval:            models.SettingVariable{Value: "60"},
 		PruneMode:                  models.SettingVariable{Value: "dangling"},
@@ -90,9 +101,15 @@ func (s *SettingsService) getDefaultSettings() *models.Settings {
 		AuthLocalEnabled:           models.SettingVariable{Value: "true"},
 		AuthOidcEnabled:            models.SettingVariable{Value: "false"},
 		AuthOidcMergeAccounts:      models.SettingVariable{Value: "false"},
+		AuthOidcManageDefaultRole:  models.SettingVariable{Value: "false"},
 		AuthSessionTimeout:         models.SettingVariable{Value: "1440"},
 		AuthPasswordPolicy:         models.SettingVariable{Value: "strong"},
+		AuthRequireTotp:            models.SettingVariable{Value: "false"},
+		AuthPasskeysEnabled:        models.SettingVariable{Value: "true"},
+		AuthLockoutThreshold:       models.SettingVariable{Value: "5"},
+		AuthLockoutDuration:        models.SettingVariable{Value: "15"},
 		AuthOidcConfig:             models.SettingVariable{Value: "{}"},
+		AuditLogRetentionDays:      models.SettingVariable{Value: "365"},
 		OnboardingCompleted:        models.SettingVariable{Value: "false"},
 		OnboardingSteps:            models.SettingVariable{Value: "[]"},
 		MobileNavigationMode:       models.SettingVariable{Value: "floating"},
@@ -102,6 +119,23 @@ func (s *SettingsService) getDefaultSettings() *models.Settings {
 		AccentColor:                models.SettingVariable{Value: "oklch(0.606 0.25 292.717)"},
 		MaxImageUploadSize:         models.SettingVariable{Value: "500"},
 		EnvironmentHealthInterval:  models.SettingVariable{Value: "2"},
+		MetricsEnabled:             models.SettingVariable{Value: "true"},
+		MetricsInterval:            models.SettingVariable{Value: "30"},
+		MetricsRetentionDays:       models.SettingVariable{Value: "7"},
+		EventSinkSyslogEnabled:     models.SettingVariable{Value: "false"},
+		EventSinkSyslogProtocol:    models.SettingVariable{Value: "udp"},
+		EventSinkSyslogAddress:     models.SettingVariable{Value: ""},
+		EventSinkLokiEnabled:       models.SettingVariable{Value: "false"},
+		EventSinkLokiURL:           models.SettingVariable{Value: ""},
+		EventSinkLokiLabels:        models.SettingVariable{Value: ""},
+		EventSinkLokiTenantID:      models.SettingVariable{Value: ""},
+		EventSinkFileEnabled:       models.SettingVariable{Value: "false"},
+		EventSinkFilePath:          models.SettingVariable{Value: "data/events/events.jsonl"},
+		EventSinkFileMaxSize:       models.SettingVariable{Value: "100"},
+		EventSinkFileMaxBackups:    models.SettingVariable{Value: "5"},
+		BackupDirectory:            models.SettingVariable{Value: "data/backups"},
+		BackupAllowedPaths:         models.SettingVariable{Value: ""},
+		BackupHelperImage:          models.SettingVariable{Value: "busybox:stable"},
 
 		InstanceID: models.SettingVariable{Value: ""},
 	}
@@ -300,6 +334,12 @@ func (s *SettingsService) UpdateSettings(ctx context.Context, updates dto.Update
 
 	changedPolling := false
 	changedAutoUpdate := false
+	changedMetrics := false
+	changedEventSinks := false
+
+	auditBefore := map[string]any{}
+	auditAfter := map[string]any{}
+	var auditSensitive []string
 
 	// Iterate through fields using reflection
 	for i := 0; i < rt.NumField(); i++ {
@@ -318,6 +358,8 @@ func (s *SettingsService) UpdateSettings(ctx context.Context, updates dto.Update
 			value = fieldValue.Elem().String()
 		}
 
+		previousValue, _, isSensitive, _ := cfg.FieldByKey(key)
+
 		// Determine the actual value to use and save
 		var valueToSave string
 		var err error
@@ -346,11 +388,23 @@ func (s *SettingsService) UpdateSettings(ctx context.Context, updates dto.Update
 			Value: valueToSave,
 		})
 
+		auditBefore[key] = previousValue
+		auditAfter[key] = valueToSave
+		if isSensitive {
+			auditSensitive = append(auditSensitive, key)
+		}
+
 		switch key {
 		case "pollingEnabled", "pollingInterval":
 			changedPolling = true
-		case "autoUpdate", "autoUpdateInterval":
+		case "autoUpdate", "autoUpdateInterval", "autoUpdateWindow", "autoUpdateWindowTimezone", "autoUpdateWindowDuration":
 			changedAutoUpdate = true
+		case "metricsEnabled", "metricsInterval":
+			changedMetrics = true
+		case "eventSinkSyslogEnabled", "eventSinkSyslogProtocol", "eventSinkSyslogAddress",
+			"eventSinkLokiEnabled", "eventSinkLokiUrl", "eventSinkLokiLabels", "eventSinkLokiTenantId",
+			"eventSinkFileEnabled", "eventSinkFilePath", "eventSinkFileMaxSize", "eventSinkFileMaxBackups":
+			changedEventSinks = true
 		}
 	}
 
@@ -398,7 +452,23 @@ func (s *SettingsService) UpdateSettings(ctx context.Context, updates dto.Update
 		if err := s.UpdateSetting(ctx, "authOidcConfig", string(mergedBytes)); err != nil {
 			return nil, fmt.Errorf("failed to update authOidcConfig: %w", err)
 		}
-	}
+
+		// Like the settings API, the OIDC config is shown with only its client secret
+		// hidden; the secret is redacted by name in the audit diff.
+		var previous models.OidcConfig
+		_ = json.Unmarshal([]byte(current.AuthOidcConfig.Value), &previous)
+		auditBefore["authOidcConfig"] = previous
+		auditAfter["authOidcConfig"] = incoming
+	}
+
+	s.audit.Record(ctx, AuditChange{
+		Action:     auditActionUpdate,
+		TargetType: auditTargetSettings,
+		TargetName: "settings",
+		Before:     auditBefore,
+		After:      auditAfter,
+		Sensitive:  auditSensitive,
+	})
 
 	if changedPolling && s.OnImagePollingSettingsChanged != nil {
 		s.OnImagePollingSettingsChanged(ctx)
@@ -406,6 +476,12 @@ func (s *SettingsService) UpdateSettings(ctx context.Context, updates dto.Update
 	if changedAutoUpdate && s.OnAutoUpdateSettingsChanged != nil {
 		s.OnAutoUpdateSettingsChanged(ctx)
 	}
+	if changedMetrics && s.OnMetricsSettingsChanged != nil {
+		s.OnMetricsSettingsChanged(ctx)
+	}
+	if changedEventSinks && s.OnEventSinkSettingsChanged != nil {
+		s.OnEventSinkSettingsChanged(ctx)
+	}
 
 	settings, err := s.GetSettings(ctx)
 	if err != nil {
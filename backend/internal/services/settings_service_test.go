@@ -26,7 +26,7 @@ func setupSettingsTestDB(t *testing.T) *database.DB {
 func TestSettingsService_EnsureDefaultSettings_Idempotent(t *testing.T) {
 	ctx := context.Background()
 	db := setupSettingsTestDB(t)
-	svc, err := NewSettingsService(ctx, db)
+	svc, err := NewSettingsService(ctx, db, nil)
 	require.NoError(t, err)
 
 	require.NoError(t, svc.EnsureDefaultSettings(ctx))
@@ -52,7 +52,7 @@ func TestSettingsService_EnsureDefaultSettings_Idempotent(t *testing.T) {
 func TestSettingsService_GetSettings_UnknownKeysIgnored(t *testing.T) {
 	ctx := context.Background()
 	db := setupSettingsTestDB(t)
-	svc, err := NewSettingsService(ctx, db)
+	svc, err := NewSettingsService(ctx, db, nil)
 	require.NoError(t, err)
 
 	require.NoError(t, svc.db.WithContext(ctx).
@@ -65,7 +65,7 @@ func TestSettingsService_GetSettings_UnknownKeysIgnored(t *testing.T) {
 func TestSettingsService_GetSetHelpers(t *testing.T) {
 	ctx := context.Background()
 	db := setupSettingsTestDB(t)
-	svc, err := NewSettingsService(ctx, db)
+	svc, err := NewSettingsService(ctx, db, nil)
 	require.NoError(t, err)
 
 	// Defaults for missing keys
@@ -87,7 +87,7 @@ func TestSettingsService_GetSetHelpers(t *testing.T) {
 func TestSettingsService_UpdateSetting(t *testing.T) {
 	ctx := context.Background()
 	db := setupSettingsTestDB(t)
-	svc, err := NewSettingsService(ctx, db)
+	svc, err := NewSettingsService(ctx, db, nil)
 	require.NoError(t, err)
 
 	// Use an existing key ("pruneMode") instead of a non-existent one
@@ -101,7 +101,7 @@ func TestSettingsService_UpdateSetting(t *testing.T) {
 func TestSettingsService_EnsureEncryptionKey(t *testing.T) {
 	ctx := context.Background()
 	db := setupSettingsTestDB(t)
-	svc, err := NewSettingsService(ctx, db)
+	svc, err := NewSettingsService(ctx, db, nil)
 	require.NoError(t, err)
 
 	k1, err := svc.EnsureEncryptionKey(ctx)
@@ -129,7 +129,7 @@ func TestSettingsService_SyncOidcEnvToDatabase(t *testing.T) {
 
 	ctx := context.Background()
 	db := setupSettingsTestDB(t)
-	svc, err := NewSettingsService(ctx, db)
+	svc, err := NewSettingsService(ctx, db, nil)
 	require.NoError(t, err)
 
 	// (Re)load settings after env prepared
@@ -157,7 +157,7 @@ func TestSettingsService_SyncOidcEnvToDatabase(t *testing.T) {
 func TestSettingsService_UpdateSettings_MergeOidcSecret(t *testing.T) {
 	ctx := context.Background()
 	db := setupSettingsTestDB(t)
-	svc, err := NewSettingsService(ctx, db)
+	svc, err := NewSettingsService(ctx, db, nil)
 	require.NoError(t, err)
 
 	// Seed existing OIDC config with a secret
@@ -197,7 +197,7 @@ func TestSettingsService_UpdateSettings_MergeOidcSecret(t *testing.T) {
 func TestSettingsService_LoadDatabaseSettings_ReloadsChanges(t *testing.T) {
 	ctx := context.Background()
 	db := setupSettingsTestDB(t)
-	svc, err := NewSettingsService(ctx, db)
+	svc, err := NewSettingsService(ctx, db, nil)
 	require.NoError(t, err)
 
 	// Initially empty DB -> defaults (not persisted yet)
@@ -224,7 +224,7 @@ func TestSettingsService_LoadDatabaseSettings_UIConfigurationDisabled_Env(t *tes
 
 	ctx := context.Background()
 	db := setupSettingsTestDB(t)
-	svc, err := NewSettingsService(ctx, db)
+	svc, err := NewSettingsService(ctx, db, nil)
 	require.NoError(t, err)
 
 	// Reload explicitly (NewSettingsService already did, but explicit for clarity)
@@ -238,7 +238,7 @@ func TestSettingsService_LoadDatabaseSettings_UIConfigurationDisabled_Env(t *tes
 func TestSettingsService_UpdateSettings_RefreshesCache(t *testing.T) {
 	ctx := context.Background()
 	db := setupSettingsTestDB(t)
-	svc, err := NewSettingsService(ctx, db)
+	svc, err := NewSettingsService(ctx, db, nil)
 	require.NoError(t, err)
 	require.NoError(t, svc.EnsureDefaultSettings(ctx))
 
@@ -42,6 +42,7 @@ type TemplateService struct {
 	db              *database.DB
 	httpClient      *http.Client
 	settingsService *SettingsService
+	audit           *AuditService
 
 	remoteMu    sync.RWMutex
 	remoteCache remoteCache
@@ -58,7 +59,7 @@ func makeRemoteID(registryID, slug string) string {
 	return fmt.Sprintf("%s:%s:%s", remoteIDPrefix, registryID, slug)
 }
 
-func NewTemplateService(ctx context.Context, db *database.DB, httpClient *http.Client, settingsService *SettingsService) *TemplateService {
+func NewTemplateService(ctx context.Context, db *database.DB, httpClient *http.Client, settingsService *SettingsService, audit *AuditService) *TemplateService {
 	if httpClient == nil {
 		httpClient = http.DefaultClient
 	}
@@ -71,6 +72,7 @@ func NewTemplateService(ctx context.Context, db *database.DB, httpClient *http.C
 		db:                db,
 		httpClient:        httpClient,
 		settingsService:   settingsService,
+		audit:             audit,
 		remoteCache:       remoteCache{},
 		registryFetchMeta: make(map[string]*registryFetchMeta),
 	}
@@ -285,6 +287,15 @@ func (s *TemplateService) CreateTemplate(ctx context.Context, template *models.C
 	if err := s.db.WithContext(ctx).Create(template).Error; err != nil {
 		return fmt.Errorf("failed to create template: %w", err)
 	}
+
+	s.audit.Record(ctx, AuditChange{
+		Action:     auditActionCreate,
+		TargetType: auditTargetTemplate,
+		TargetID:   template.ID,
+		TargetName: template.Name,
+		After:      template,
+		Sensitive:  []string{"envContent"},
+	})
 	return nil
 }
 
@@ -301,6 +312,7 @@ func (s *TemplateService) UpdateTemplate(ctx context.Context, id string, updates
 	if existing.IsRemote {
 		return fmt.Errorf("cannot update remote template")
 	}
+	before := existing
 
 	existing.Name = updates.Name
 	existing.Description = updates.Description
@@ -312,6 +324,16 @@ func (s *TemplateService) UpdateTemplate(ctx context.Context, id string, updates
 		return fmt.Errorf("failed to update template: %w", err)
 	}
 
+	s.audit.Record(ctx, AuditChange{
+		Action:     auditActionUpdate,
+		TargetType: auditTargetTemplate,
+		TargetID:   existing.ID,
+		TargetName: existing.Name,
+		Before:     &before,
+		After:      &existing,
+		Sensitive:  []string{"envContent"},
+	})
+
 	return nil
 }
 
@@ -352,6 +374,15 @@ func (s *TemplateService) DeleteTemplate(ctx context.Context, id string) error {
 	if result.RowsAffected == 0 {
 		return fmt.Errorf("template not found after finding it")
 	}
+
+	s.audit.Record(ctx, AuditChange{
+		Action:     auditActionDelete,
+		TargetType: auditTargetTemplate,
+		TargetID:   existing.ID,
+		TargetName: existing.Name,
+		Before:     &existing,
+		Sensitive:  []string{"envContent"},
+	})
 	return nil
 }
 
@@ -422,6 +453,14 @@ func (s *TemplateService) CreateRegistry(ctx context.Context, registry *models.T
 		return fmt.Errorf("failed to create registry: %w", err)
 	}
 
+	s.audit.Record(ctx, AuditChange{
+		Action:     auditActionCreate,
+		TargetType: auditTargetTemplateRegistry,
+		TargetID:   registry.ID,
+		TargetName: registry.Name,
+		After:      registry,
+	})
+
 	s.invalidateRemoteCache()
 	return nil
 }
@@ -464,11 +503,26 @@ func (s *TemplateService) UpdateRegistry(ctx context.Context, id string, updates
 		return errors.New("registry not found")
 	}
 
+	var updated models.TemplateRegistry
+	if err := s.db.WithContext(ctx).Where("id = ?", id).First(&updated).Error; err == nil {
+		s.audit.Record(ctx, AuditChange{
+			Action:     auditActionUpdate,
+			TargetType: auditTargetTemplateRegistry,
+			TargetID:   id,
+			TargetName: updated.Name,
+			Before:     &existing,
+			After:      &updated,
+		})
+	}
+
 	s.invalidateRemoteCache()
 	return nil
 }
 
 func (s *TemplateService) DeleteRegistry(ctx context.Context, id string) error {
+	var existing models.TemplateRegistry
+	_ = s.db.WithContext(ctx).Where("id = ?", id).First(&existing).Error
+
 	result := s.db.WithContext(ctx).Where("id = ?", id).Delete(&models.TemplateRegistry{})
 	if result.Error != nil {
 		return result.Error
@@ -477,6 +531,14 @@ func (s *TemplateService) DeleteRegistry(ctx context.Context, id string) error {
 		return errors.New("registry not found")
 	}
 
+	s.audit.Record(ctx, AuditChange{
+		Action:     auditActionDelete,
+		TargetType: auditTargetTemplateRegistry,
+		TargetID:   id,
+		TargetName: existing.Name,
+		Before:     &existing,
+	})
+
 	s.invalidateRemoteCache()
 	return nil
 }
@@ -922,6 +984,11 @@ func (s *TemplateService) UpdateGlobalVariables(ctx context.Context, vars []dto.
 		return err
 	}
 
+	var previous []dto.VariableDto
+	if s.audit != nil {
+		previous, _ = s.GetGlobalVariables(ctx)
+	}
+
 	projectsDirectory := filepath.Dir(envPath)
 	if err := os.MkdirAll(projectsDirectory, 0755); err != nil {
 		return fmt.Errorf("failed to create projects directory: %w", err)
@@ -961,9 +1028,29 @@ func (s *TemplateService) UpdateGlobalVariables(ctx context.Context, vars []dto.
 		"path", envPath,
 		"count", len(sortedVars))
 
+	// Variables commonly hold credentials, so only which keys changed is recorded.
+	s.audit.Record(ctx, AuditChange{
+		Action:     auditActionUpdate,
+		TargetType: auditTargetTemplateVariables,
+		TargetName: "global variables",
+		Before:     auditVariablesSnapshot(previous),
+		After:      auditVariablesSnapshot(sortedVars),
+		Sensitive:  []string{"variables"},
+	})
+
 	return nil
 }
 
+func auditVariablesSnapshot(vars []dto.VariableDto) map[string]any {
+	values := make(map[string]any, len(vars))
+	for _, v := range vars {
+		if key := strings.TrimSpace(v.Key); key != "" {
+			values[key] = strings.TrimSpace(v.Value)
+		}
+	}
+	return map[string]any{"variables": values}
+}
+
 // ParseComposeServices extracts service names from a compose file content using compose-go
 func (s *TemplateService) ParseComposeServices(ctx context.Context, composeContent string) []string {
 	if composeContent == "" {
@@ -0,0 +1,207 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/ofkm/arcane-backend/internal/database"
+	"github.com/ofkm/arcane-backend/internal/dto"
+	"github.com/ofkm/arcane-backend/internal/models"
+	"github.com/ofkm/arcane-backend/internal/utils/updatepolicy"
+)
+
+var (
+	ErrUpdatePolicyNotFound = errors.New("update policy not found")
+	ErrInvalidUpdatePolicy  = errors.New("invalid update policy")
+)
+
+const (
+	arcaneUpdaterPolicyLabel         = "com.ofkm.arcane.updater.policy"
+	arcaneUpdaterWindowLabel         = "com.ofkm.arcane.updater.window"
+	arcaneUpdaterWindowTimezoneLabel = "com.ofkm.arcane.updater.window.timezone"
+	arcaneUpdaterWindowDurationLabel = "com.ofkm.arcane.updater.window.duration"
+
+	composeProjectLabel = "com.docker.compose.project"
+)
+
+// ResolvedUpdatePolicy is the effective policy and maintenance window for one container,
+// with the scope each came from ("label", "container", "project" or "global").
+type ResolvedUpdatePolicy struct {
+	Policy       updatepolicy.Policy
+	PolicySource string
+	Window       updatepolicy.Window
+	WindowSource string
+}
+
+type UpdatePolicyService struct {
+	db              *database.DB
+	settingsService *SettingsService
+}
+
+func NewUpdatePolicyService(db *database.DB, settingsService *SettingsService) *UpdatePolicyService {
+	return &UpdatePolicyService{
+		db:              db,
+		settingsService: settingsService,
+	}
+}
+
+func (s *UpdatePolicyService) ListPolicies(ctx context.Context) ([]models.UpdatePolicy, error) {
+	var policies []models.UpdatePolicy
+	if err := s.db.WithContext(ctx).Order("scope_type ASC, scope_name ASC").Find(&policies).Error; err != nil {
+		return nil, fmt.Errorf("failed to list update policies: %w", err)
+	}
+	return policies, nil
+}
+
+// UpsertPolicy creates or replaces the policy for a container or project.
+func (s *UpdatePolicyService) UpsertPolicy(ctx context.Context, req dto.UpsertUpdatePolicyDto) (*models.UpdatePolicy, error) {
+	scope := models.UpdatePolicyScope(strings.ToLower(strings.TrimSpace(req.ScopeType)))
+	if scope != models.UpdatePolicyScopeContainer && scope != models.UpdatePolicyScopeProject {
+		return nil, fmt.Errorf("%w: scope type must be container or project", ErrInvalidUpdatePolicy)
+	}
+	name := strings.TrimPrefix(strings.TrimSpace(req.ScopeName), "/")
+	if name == "" {
+		return nil, fmt.Errorf("%w: scope name is required", ErrInvalidUpdatePolicy)
+	}
+
+	policy := ""
+	if strings.TrimSpace(req.Policy) != "" {
+		p, ok := updatepolicy.Parse(req.Policy)
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown policy %q", ErrInvalidUpdatePolicy, req.Policy)
+		}
+		policy = string(p)
+	}
+	if req.WindowDuration < 0 {
+		return nil, fmt.Errorf("%w: window duration must not be negative", ErrInvalidUpdatePolicy)
+	}
+	window := updatepolicy.Window{Cron: req.WindowCron, Timezone: req.WindowTimezone}
+	if err := window.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidUpdatePolicy, err)
+	}
+
+	var existing models.UpdatePolicy
+	err := s.db.WithContext(ctx).Where("scope_type = ? AND scope_name = ?", scope, name).First(&existing).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to load update policy: %w", err)
+	}
+
+	existing.ScopeType = scope
+	existing.ScopeName = name
+	existing.Policy = policy
+	existing.WindowCron = strings.TrimSpace(req.WindowCron)
+	existing.WindowTimezone = strings.TrimSpace(req.WindowTimezone)
+	existing.WindowDuration = req.WindowDuration
+	if err := s.db.WithContext(ctx).Save(&existing).Error; err != nil {
+		return nil, fmt.Errorf("failed to save update policy: %w", err)
+	}
+	return &existing, nil
+}
+
+func (s *UpdatePolicyService) DeletePolicy(ctx context.Context, id string) error {
+	res := s.db.WithContext(ctx).Where("id = ?", id).Delete(&models.UpdatePolicy{})
+	if res.Error != nil {
+		return fmt.Errorf("failed to delete update policy: %w", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return ErrUpdatePolicyNotFound
+	}
+	return nil
+}
+
+// GlobalWindow returns the maintenance window configured in settings.
+func (s *UpdatePolicyService) GlobalWindow(ctx context.Context) updatepolicy.Window {
+	return updatepolicy.Window{
+		Cron:     s.settingsService.GetStringSetting(ctx, "autoUpdateWindow", ""),
+		Timezone: s.settingsService.GetStringSetting(ctx, "autoUpdateWindowTimezone", "UTC"),
+		Duration: time.Duration(s.settingsService.GetIntSetting(ctx, "autoUpdateWindowDuration", 60)) * time.Minute,
+	}
+}
+
+// Resolve determines the policy and window for a container. Container labels win over a
+// stored container policy, which wins over the policy of its compose project, which wins
+// over the global settings. Policy and window are resolved independently.
+func (s *UpdatePolicyService) Resolve(ctx context.Context, containerName string, labels map[string]string) ResolvedUpdatePolicy {
+	global, ok := updatepolicy.Parse(s.settingsService.GetStringSetting(ctx, "autoUpdatePolicy", string(updatepolicy.PolicyDigest)))
+	if !ok {
+		global = updatepolicy.PolicyDigest
+	}
+	out := ResolvedUpdatePolicy{
+		Policy:       global,
+		PolicySource: "global",
+		Window:       s.GlobalWindow(ctx),
+		WindowSource: "global",
+	}
+
+	// Apply from the weakest scope to the strongest so later ones override.
+	var rows []models.UpdatePolicy
+	name := strings.TrimPrefix(containerName, "/")
+	project := labelValue(labels, composeProjectLabel)
+	q := s.db.WithContext(ctx).Where("scope_type = ? AND scope_name = ?", models.UpdatePolicyScopeContainer, name)
+	if project != "" {
+		q = q.Or("scope_type = ? AND scope_name = ?", models.UpdatePolicyScopeProject, project)
+	}
+	if err := q.Find(&rows).Error; err == nil {
+		for _, scope := range []models.UpdatePolicyScope{models.UpdatePolicyScopeProject, models.UpdatePolicyScopeContainer} {
+			for _, row := range rows {
+				if row.ScopeType != scope {
+					continue
+				}
+				if p, ok := updatepolicy.Parse(row.Policy); ok {
+					out.Policy, out.PolicySource = p, string(scope)
+				}
+				if strings.TrimSpace(row.WindowCron) != "" {
+					out.Window = updatepolicy.Window{
+						Cron:     row.WindowCron,
+						Timezone: row.WindowTimezone,
+						Duration: time.Duration(row.WindowDuration) * time.Minute,
+					}
+					out.WindowSource = string(scope)
+				}
+			}
+		}
+	}
+
+	if p, ok := updatepolicy.Parse(labelValue(labels, arcaneUpdaterPolicyLabel)); ok {
+		out.Policy, out.PolicySource = p, "label"
+	}
+	if cronExpr := labelValue(labels, arcaneUpdaterWindowLabel); cronExpr != "" {
+		out.Window = updatepolicy.Window{
+			Cron:     cronExpr,
+			Timezone: labelValue(labels, arcaneUpdaterWindowTimezoneLabel),
+			Duration: parseWindowDuration(labelValue(labels, arcaneUpdaterWindowDurationLabel)),
+		}
+		out.WindowSource = "label"
+	}
+	return out
+}
+
+// parseWindowDuration accepts a Go duration ("90m", "2h") or a plain number of minutes.
+func parseWindowDuration(v string) time.Duration {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return 0
+	}
+	if d, err := time.ParseDuration(v); err == nil {
+		return d
+	}
+	if n, err := strconv.Atoi(v); err == nil {
+		return time.Duration(n) * time.Minute
+	}
+	return 0
+}
+
+func labelValue(labels map[string]string, key string) string {
+	for k, v := range labels {
+		if strings.EqualFold(k, key) {
+			return strings.TrimSpace(v)
+		}
+	}
+	return ""
+}
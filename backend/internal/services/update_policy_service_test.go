@@ -0,0 +1,100 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ofkm/arcane-backend/internal/dto"
+	"github.com/ofkm/arcane-backend/internal/models"
+	"github.com/ofkm/arcane-backend/internal/utils/updatepolicy"
+)
+
+func setupUpdatePolicyService(t *testing.T) *UpdatePolicyService {
+	t.Helper()
+	ctx := context.Background()
+	db := setupSettingsTestDB(t)
+	require.NoError(t, db.AutoMigrate(&models.UpdatePolicy{}))
+	settings, err := NewSettingsService(ctx, db, nil)
+	require.NoError(t, err)
+	require.NoError(t, settings.EnsureDefaultSettings(ctx))
+	return NewUpdatePolicyService(db, settings)
+}
+
+func TestUpdatePolicyService_ResolvePrecedence(t *testing.T) {
+	ctx := context.Background()
+	svc := setupUpdatePolicyService(t)
+
+	got := svc.Resolve(ctx, "db", nil)
+	require.Equal(t, updatepolicy.PolicyDigest, got.Policy)
+	require.Equal(t, "global", got.PolicySource)
+	require.True(t, got.Window.IsZero())
+
+	_, err := svc.UpsertPolicy(ctx, dto.UpsertUpdatePolicyDto{ScopeType: "project", ScopeName: "stack", Policy: "minor", WindowCron: "0 3 * * 0", WindowTimezone: "UTC", WindowDuration: 120})
+	require.NoError(t, err)
+	_, err = svc.UpsertPolicy(ctx, dto.UpsertUpdatePolicyDto{ScopeType: "container", ScopeName: "db", Policy: "pin"})
+	require.NoError(t, err)
+
+	labels := map[string]string{composeProjectLabel: "stack"}
+
+	got = svc.Resolve(ctx, "web", labels)
+	require.Equal(t, updatepolicy.PolicyMinor, got.Policy)
+	require.Equal(t, "project", got.PolicySource)
+	require.Equal(t, "project", got.WindowSource)
+	require.Equal(t, 2*time.Hour, got.Window.Duration)
+
+	// The container row overrides the policy but inherits the project window.
+	got = svc.Resolve(ctx, "/db", labels)
+	require.Equal(t, updatepolicy.PolicyPin, got.Policy)
+	require.Equal(t, "container", got.PolicySource)
+	require.Equal(t, "project", got.WindowSource)
+
+	labels[arcaneUpdaterPolicyLabel] = "patch"
+	labels[arcaneUpdaterWindowLabel] = "0 1 * * *"
+	labels[arcaneUpdaterWindowDurationLabel] = "30"
+	got = svc.Resolve(ctx, "db", labels)
+	require.Equal(t, updatepolicy.PolicyPatch, got.Policy)
+	require.Equal(t, "label", got.PolicySource)
+	require.Equal(t, "label", got.WindowSource)
+	require.Equal(t, 30*time.Minute, got.Window.Duration)
+}
+
+func TestUpdatePolicyService_UpsertValidatesAndReplaces(t *testing.T) {
+	ctx := context.Background()
+	svc := setupUpdatePolicyService(t)
+
+	_, err := svc.UpsertPolicy(ctx, dto.UpsertUpdatePolicyDto{ScopeType: "volume", ScopeName: "x", Policy: "pin"})
+	require.ErrorIs(t, err, ErrInvalidUpdatePolicy)
+	_, err = svc.UpsertPolicy(ctx, dto.UpsertUpdatePolicyDto{ScopeType: "container", ScopeName: "x", Policy: "sometimes"})
+	require.ErrorIs(t, err, ErrInvalidUpdatePolicy)
+	_, err = svc.UpsertPolicy(ctx, dto.UpsertUpdatePolicyDto{ScopeType: "container", ScopeName: "x", WindowCron: "0 2 * * *", WindowTimezone: "Nowhere/Special"})
+	require.ErrorIs(t, err, ErrInvalidUpdatePolicy)
+
+	first, err := svc.UpsertPolicy(ctx, dto.UpsertUpdatePolicyDto{ScopeType: "container", ScopeName: "x", Policy: "pin"})
+	require.NoError(t, err)
+	second, err := svc.UpsertPolicy(ctx, dto.UpsertUpdatePolicyDto{ScopeType: "Container", ScopeName: "x", Policy: "major"})
+	require.NoError(t, err)
+	require.Equal(t, first.ID, second.ID)
+
+	policies, err := svc.ListPolicies(ctx)
+	require.NoError(t, err)
+	require.Len(t, policies, 1)
+	require.Equal(t, "major", policies[0].Policy)
+
+	require.NoError(t, svc.DeletePolicy(ctx, first.ID))
+	require.ErrorIs(t, svc.DeletePolicy(ctx, first.ID), ErrUpdatePolicyNotFound)
+}
+
+func TestUpdatePolicySkipReason(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	require.Contains(t, updatePolicySkipReason(ResolvedUpdatePolicy{Policy: updatepolicy.PolicyPin, PolicySource: "label"}, false, now), "pinned")
+	require.Contains(t, updatePolicySkipReason(ResolvedUpdatePolicy{Policy: updatepolicy.PolicyDigest}, true, now), "tag changes")
+	require.Empty(t, updatePolicySkipReason(ResolvedUpdatePolicy{Policy: updatepolicy.PolicyDigest}, false, now))
+
+	nightly := updatepolicy.Window{Cron: "0 2 * * *", Timezone: "UTC", Duration: time.Hour}
+	require.Contains(t, updatePolicySkipReason(ResolvedUpdatePolicy{Policy: updatepolicy.PolicyMinor, Window: nightly, WindowSource: "project"}, true, now), "maintenance window")
+	require.Empty(t, updatePolicySkipReason(ResolvedUpdatePolicy{Policy: updatepolicy.PolicyMinor, Window: nightly}, true, now.Add(-9*time.Hour-30*time.Minute)))
+}
@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
@@ -11,12 +12,20 @@ import (
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/mount"
 	"github.com/docker/docker/api/types/network"
 	"github.com/docker/docker/client"
 	"github.com/ofkm/arcane-backend/internal/models"
+	"gorm.io/gorm"
 
 	"github.com/ofkm/arcane-backend/internal/database"
 	"github.com/ofkm/arcane-backend/internal/dto"
+	"github.com/ofkm/arcane-backend/internal/utils/updatepolicy"
+)
+
+var (
+	ErrUpdateRecordNotFound = errors.New("update record not found")
+	ErrRollbackUnavailable  = errors.New("rollback is not available for this update")
 )
 
 type UpdaterService struct {
@@ -29,6 +38,8 @@ type UpdaterService struct {
 	eventService        *EventService
 	imageService        *ImageService
 	notificationService *NotificationService
+	volumeBackupService *VolumeBackupService
+	updatePolicyService *UpdatePolicyService
 
 	updatingContainers map[string]bool
 	updatingProjects   map[string]bool
@@ -44,6 +55,8 @@ func NewUpdaterService(
 	events *EventService,
 	imageSvc *ImageService,
 	notifications *NotificationService,
+	volumeBackups *VolumeBackupService,
+	updatePolicies *UpdatePolicyService,
 ) *UpdaterService {
 	return &UpdaterService{
 		db:                  db,
@@ -55,26 +68,53 @@ func NewUpdaterService(
 		eventService:        events,
 		imageService:        imageSvc,
 		notificationService: notifications,
+		volumeBackupService: volumeBackups,
+		updatePolicyService: updatePolicies,
 		updatingContainers:  map[string]bool{},
 		updatingProjects:    map[string]bool{},
 	}
 }
 
-//nolint:gocognit
 func (s *UpdaterService) ApplyPending(ctx context.Context, dryRun bool) (*dto.UpdaterRunResult, error) {
 	start := time.Now()
 	out := &dto.UpdaterRunResult{Items: []dto.UpdaterItem{}}
 
+	if err := s.applyPendingRecords(ctx, dryRun, out); err != nil {
+		return nil, err
+	}
+	s.applyTagUpdates(ctx, dryRun, out)
+
+	duration := time.Since(start).String()
+	out.Duration = duration
+	if len(out.Items) > 0 {
+		s.logAutoUpdate(ctx, models.EventSeverityInfo, models.JSON{
+			"phase":    "complete",
+			"checked":  out.Checked,
+			"updated":  out.Updated,
+			"skipped":  out.Skipped,
+			"failed":   out.Failed,
+			"duration": duration,
+			"time":     time.Now().UTC().Format(time.RFC3339),
+		})
+	}
+
+	return out, nil
+}
+
+// applyPendingRecords pulls every image with a pending update record and recreates the
+// containers running the old image.
+//
+//nolint:gocognit
+func (s *UpdaterService) applyPendingRecords(ctx context.Context, dryRun bool, out *dto.UpdaterRunResult) error {
 	var records []models.ImageUpdateRecord
 	if err := s.db.WithContext(ctx).Where("has_update = ?", true).Find(&records).Error; err != nil {
-		return nil, fmt.Errorf("query pending image updates: %w", err)
+		return fmt.Errorf("query pending image updates: %w", err)
 	}
 	// debug: how many pending records and dryRun flag
 	slog.DebugContext(ctx, "ApplyPending: found pending image update records", "records", len(records), "dryRun", dryRun)
 
 	if len(records) == 0 {
-		out.Duration = time.Since(start).String()
-		return out, nil
+		return nil
 	}
 
 	// Only update images that are actually used by running resources
@@ -108,10 +148,12 @@ func (s *UpdaterService) ApplyPending(ctx context.Context, dryRun bool) (*dto.Up
 			}
 		}
 
-		newRef := oldRef
-		if r.IsTagUpdate() && r.LatestVersion != nil && *r.LatestVersion != "" {
-			newRef = fmt.Sprintf("%s:%s", r.Repository, *r.LatestVersion)
+		// Moving to a newer tag is left to applyTagUpdates, which honours update policies;
+		// here only a changed digest behind the current tag counts.
+		if r.IsTagUpdate() && !r.HasDigestUpdate() {
+			continue
 		}
+		newRef := oldRef
 
 		oldIDs, _ := s.resolveLocalImageIDsForRef(ctx, oldRef)
 		for _, id := range oldIDs {
@@ -123,8 +165,7 @@ func (s *UpdaterService) ApplyPending(ctx context.Context, dryRun bool) (*dto.Up
 	}
 
 	if len(plans) == 0 {
-		out.Duration = time.Since(start).String()
-		return out, nil
+		return nil
 	}
 
 	// Log run start
@@ -200,37 +241,13 @@ func (s *UpdaterService) ApplyPending(ctx context.Context, dryRun bool) (*dto.Up
 			slog.Warn("container restarts had errors", "err", err)
 		}
 		for _, r := range results {
-			item := dto.UpdaterItem{
-				ResourceID:    r.ResourceID,
-				ResourceType:  "container",
-				ResourceName:  r.ResourceName,
-				Status:        r.Status,
-				Error:         r.Error,
-				OldImages:     r.OldImages,
-				NewImages:     r.NewImages,
-				UpdateApplied: r.UpdateApplied,
-			}
-			out.Items = append(out.Items, item)
-			out.Checked++
-			switch {
-			case r.UpdateApplied:
-				out.Updated++
-			case r.Error != "":
-				out.Failed++
-			default:
-				out.Skipped++
+			// Keep the previous image of snapshotted containers so the update can be rolled back.
+			if _, ok := r.Details["volumeSnapshots"]; ok {
+				if prev, ok := r.Details["previousImageId"].(string); ok {
+					delete(oldIDSet, prev)
+				}
 			}
-			_ = s.recordRun(ctx, item)
-
-			s.logAutoUpdate(ctx, s.severityFromStatus(item.Status), models.JSON{
-				"phase":        "container",
-				"containerId":  r.ResourceID,
-				"container":    r.ResourceName,
-				"status":       r.Status,
-				"oldImageMain": r.OldImages["main"],
-				"newImageMain": r.NewImages["main"],
-				"error":        r.Error,
-			})
+			s.addContainerResult(ctx, out, r)
 		}
 	}
 
@@ -273,22 +290,208 @@ func (s *UpdaterService) ApplyPending(ctx context.Context, dryRun bool) (*dto.Up
 		}
 	}
 
-	// Log run complete
-	duration := time.Since(start).String()
-	out.Duration = duration
-	s.logAutoUpdate(ctx, models.EventSeverityInfo, models.JSON{
-		"phase":    "complete",
-		"checked":  out.Checked,
-		"updated":  out.Updated,
-		"skipped":  out.Skipped,
-		"failed":   out.Failed,
-		"duration": duration,
-		"time":     time.Now().UTC().Format(time.RFC3339),
+	return nil
+}
+
+// applyTagUpdates moves containers whose policy allows semver bumps (patch, minor or major)
+// to the newest allowed tag published by the registry.
+func (s *UpdaterService) applyTagUpdates(ctx context.Context, dryRun bool, out *dto.UpdaterRunResult) {
+	if s.updatePolicyService == nil || s.imageUpdateService == nil {
+		return
+	}
+
+	dcli, err := s.dockerService.CreateConnection(ctx)
+	if err != nil {
+		slog.WarnContext(ctx, "applyTagUpdates: docker connect failed", "err", err)
+		return
+	}
+	defer dcli.Close()
+
+	list, err := dcli.ContainerList(ctx, container.ListOptions{All: false})
+	if err != nil {
+		slog.WarnContext(ctx, "applyTagUpdates: list containers failed", "err", err)
+		return
+	}
+
+	// Containers handled by the digest pass were just recreated; leave them for the next run.
+	handled := map[string]struct{}{}
+	for _, item := range out.Items {
+		if item.ResourceType == "container" {
+			handled[item.ResourceName] = struct{}{}
+		}
+	}
+	tagsByRepo := map[string][]string{}
+
+	for _, c := range list {
+		name := s.getContainerName(c)
+		if _, ok := handled[name]; ok || s.isUpdateDisabled(c.Labels) {
+			continue
+		}
+		inspect, err := dcli.ContainerInspect(ctx, c.ID)
+		if err != nil || inspect.Config == nil || s.isUpdateDisabled(inspect.Config.Labels) {
+			continue
+		}
+		currentRef := inspect.Config.Image
+		if strings.Contains(currentRef, "@") {
+			// Referenced by digest; there is no tag to move.
+			continue
+		}
+
+		resolved := s.resolveUpdatePolicy(ctx, name, inspect.Config.Labels)
+		if !resolved.Policy.AllowsTagChange() {
+			continue
+		}
+
+		repo, tag := s.parseRepoAndTag(currentRef)
+		tags, ok := tagsByRepo[repo]
+		if !ok {
+			tags, err = s.imageUpdateService.ListImageTags(ctx, currentRef)
+			if err != nil {
+				slog.WarnContext(ctx, "applyTagUpdates: list tags failed", "image", currentRef, "err", err)
+			}
+			tagsByRepo[repo] = tags
+		}
+		newTag, ok := updatepolicy.LatestAllowedTag(tag, tags, resolved.Policy)
+		if !ok {
+			continue
+		}
+		newRef := repo + ":" + newTag
+
+		res := dto.AutoUpdateResourceResult{
+			ResourceID:      c.ID,
+			ResourceName:    name,
+			ResourceType:    "container",
+			Status:          "checked",
+			UpdateAvailable: true,
+			OldImages:       map[string]string{"main": s.normalizeRef(currentRef), "imageId": inspect.Image},
+			NewImages:       map[string]string{"main": s.normalizeRef(newRef)},
+		}
+		switch reason := updatePolicySkipReason(resolved, true, time.Now()); {
+		case reason != "":
+			res.Status = "skipped"
+			res.Details = policyDetails(resolved, reason)
+		case dryRun:
+			res.Status = "skipped"
+			res.Details = policyDetails(resolved, "dry run")
+		default:
+			if err := s.imageService.PullImage(ctx, newRef, io.Discard, systemUser, nil); err != nil {
+				res.Status = "failed"
+				res.Error = "pull: " + err.Error()
+				break
+			}
+			s.applyContainerUpdate(ctx, dcli, c, inspect, &res, currentRef, newRef)
+			if res.Details != nil {
+				res.Details["policy"] = string(resolved.Policy)
+				res.Details["policySource"] = resolved.PolicySource
+			}
+		}
+		s.addContainerResult(ctx, out, res)
+	}
+}
+
+// addContainerResult records a container update result in the run output, the history and
+// the event log.
+func (s *UpdaterService) addContainerResult(ctx context.Context, out *dto.UpdaterRunResult, r dto.AutoUpdateResourceResult) {
+	item := dto.UpdaterItem{
+		ResourceID:    r.ResourceID,
+		ResourceType:  "container",
+		ResourceName:  r.ResourceName,
+		Status:        r.Status,
+		Error:         r.Error,
+		OldImages:     r.OldImages,
+		NewImages:     r.NewImages,
+		UpdateApplied: r.UpdateApplied,
+		Details:       r.Details,
+	}
+	out.Items = append(out.Items, item)
+	out.Checked++
+	switch {
+	case r.UpdateApplied:
+		out.Updated++
+	case r.Error != "":
+		out.Failed++
+	default:
+		out.Skipped++
+	}
+	_ = s.recordRun(ctx, item)
+
+	s.logAutoUpdate(ctx, s.severityFromStatus(item.Status), models.JSON{
+		"phase":        "container",
+		"containerId":  r.ResourceID,
+		"container":    r.ResourceName,
+		"status":       r.Status,
+		"oldImageMain": r.OldImages["main"],
+		"newImageMain": r.NewImages["main"],
+		"error":        r.Error,
+		"reason":       r.Details["reason"],
 	})
+}
+
+// resolveUpdatePolicy returns the effective policy for a container, falling back to digest
+// updates at any time when no policy service is configured.
+func (s *UpdaterService) resolveUpdatePolicy(ctx context.Context, name string, labels map[string]string) ResolvedUpdatePolicy {
+	if s.updatePolicyService == nil {
+		return ResolvedUpdatePolicy{Policy: updatepolicy.PolicyDigest, PolicySource: "global", WindowSource: "global"}
+	}
+	return s.updatePolicyService.Resolve(ctx, name, labels)
+}
+
+// RunningUpdatePolicies resolves the update policy and maintenance window of every
+// running container that has not opted out of updates.
+func (s *UpdaterService) RunningUpdatePolicies(ctx context.Context) ([]ResolvedUpdatePolicy, error) {
+	dcli, err := s.dockerService.CreateConnection(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("docker connect: %w", err)
+	}
+	defer dcli.Close()
+
+	list, err := dcli.ContainerList(ctx, container.ListOptions{All: false})
+	if err != nil {
+		return nil, fmt.Errorf("list containers: %w", err)
+	}
 
+	out := make([]ResolvedUpdatePolicy, 0, len(list))
+	for _, c := range list {
+		if s.isUpdateDisabled(c.Labels) {
+			continue
+		}
+		inspect, err := dcli.ContainerInspect(ctx, c.ID)
+		if err != nil || inspect.Config == nil || s.isUpdateDisabled(inspect.Config.Labels) {
+			continue
+		}
+		out = append(out, s.resolveUpdatePolicy(ctx, s.getContainerName(c), inspect.Config.Labels))
+	}
 	return out, nil
 }
 
+// updatePolicySkipReason explains why the policy forbids an update right now, or returns ""
+// when it may proceed.
+func updatePolicySkipReason(resolved ResolvedUpdatePolicy, tagChange bool, now time.Time) string {
+	if resolved.Policy == updatepolicy.PolicyPin {
+		return fmt.Sprintf("pinned by %s policy", resolved.PolicySource)
+	}
+	if tagChange && !resolved.Policy.AllowsTagChange() {
+		return fmt.Sprintf("%s policy does not allow tag changes", resolved.Policy)
+	}
+	open, err := resolved.Window.IsOpen(now)
+	if err != nil {
+		return "invalid maintenance window: " + err.Error()
+	}
+	if !open {
+		return fmt.Sprintf("outside %s maintenance window", resolved.WindowSource)
+	}
+	return ""
+}
+
+func policyDetails(resolved ResolvedUpdatePolicy, reason string) models.JSON {
+	return models.JSON{
+		"reason":       reason,
+		"policy":       string(resolved.Policy),
+		"policySource": resolved.PolicySource,
+		"windowSource": resolved.WindowSource,
+	}
+}
+
 func (s *UpdaterService) pruneImageIDs(ctx context.Context, ids []string) error {
 	if len(ids) == 0 {
 		return nil
@@ -368,10 +571,12 @@ func (s *UpdaterService) GetHistory(ctx context.Context, limit int) ([]models.Au
 
 // --- internals ---
 
-func (s *UpdaterService) updateContainer(ctx context.Context, cnt container.Summary, inspect container.InspectResponse, newRef string) error {
+// updateContainer recreates the container on newRef and returns the details needed to roll
+// the update back, including volume snapshots for containers that opt into them.
+func (s *UpdaterService) updateContainer(ctx context.Context, cnt container.Summary, inspect container.InspectResponse, newRef string) (models.JSON, error) {
 	dcli, err := s.dockerService.CreateConnection(ctx)
 	if err != nil {
-		return fmt.Errorf("docker connect: %w", err)
+		return nil, fmt.Errorf("docker connect: %w", err)
 	}
 	defer dcli.Close()
 
@@ -381,14 +586,33 @@ func (s *UpdaterService) updateContainer(ctx context.Context, cnt container.Summ
 	// stop
 	if err := dcli.ContainerStop(ctx, cnt.ID, container.StopOptions{}); err != nil {
 		slog.DebugContext(ctx, "updateContainer: stop failed", "containerId", cnt.ID, "err", err)
-		return fmt.Errorf("stop: %w", err)
+		return nil, fmt.Errorf("stop: %w", err)
 	}
 	_ = s.eventService.LogContainerEvent(ctx, models.EventTypeContainerStop, cnt.ID, name, systemUser.ID, systemUser.Username, "0", models.JSON{"action": "updater_stop"})
 
+	details := models.JSON{
+		"containerName":   name,
+		"previousImageId": inspect.Image,
+	}
+	if inspect.Config != nil {
+		details["previousImageRef"] = inspect.Config.Image
+	}
+	if inspect.Config != nil && s.isSnapshotEnabled(inspect.Config.Labels) {
+		snapshots, err := s.snapshotContainerVolumes(ctx, inspect)
+		if err != nil {
+			// Without a snapshot there is no safety net; leave the old container running.
+			if startErr := dcli.ContainerStart(ctx, cnt.ID, container.StartOptions{}); startErr != nil {
+				slog.WarnContext(ctx, "updateContainer: restart after failed snapshot failed", "containerId", cnt.ID, "err", startErr)
+			}
+			return nil, fmt.Errorf("volume snapshot: %w", err)
+		}
+		details["volumeSnapshots"] = snapshots
+	}
+
 	// remove
 	if err := dcli.ContainerRemove(ctx, cnt.ID, container.RemoveOptions{}); err != nil {
 		slog.DebugContext(ctx, "updateContainer: remove failed", "containerId", cnt.ID, "err", err)
-		return fmt.Errorf("remove: %w", err)
+		return details, fmt.Errorf("remove: %w", err)
 	}
 	_ = s.eventService.LogContainerEvent(ctx, models.EventTypeContainerDelete, cnt.ID, name, systemUser.ID, systemUser.Username, "0", models.JSON{"action": "updater_delete"})
 
@@ -398,17 +622,252 @@ func (s *UpdaterService) updateContainer(ctx context.Context, cnt container.Summ
 	resp, err := dcli.ContainerCreate(ctx, cfg, inspect.HostConfig, &network.NetworkingConfig{EndpointsConfig: inspect.NetworkSettings.Networks}, nil, inspect.Name)
 	if err != nil {
 		slog.DebugContext(ctx, "updateContainer: create failed", "containerName", inspect.Name, "err", err)
-		return fmt.Errorf("create: %w", err)
+		return details, fmt.Errorf("create: %w", err)
 	}
 	_ = s.eventService.LogContainerEvent(ctx, models.EventTypeContainerCreate, resp.ID, name, systemUser.ID, systemUser.Username, "0", models.JSON{"action": "updater_create", "newImageId": resp.ID})
 
 	if err := dcli.ContainerStart(ctx, resp.ID, container.StartOptions{}); err != nil {
 		slog.DebugContext(ctx, "updateContainer: start failed", "newContainerId", resp.ID, "err", err)
-		return fmt.Errorf("start: %w", err)
+		return details, fmt.Errorf("start: %w", err)
 	}
 	_ = s.eventService.LogContainerEvent(ctx, models.EventTypeContainerStart, resp.ID, name, systemUser.ID, systemUser.Username, "0", models.JSON{"action": "updater_start"})
 
 	slog.DebugContext(ctx, "updateContainer: update complete", "oldContainerId", cnt.ID, "newContainerId", resp.ID)
+	return details, nil
+}
+
+// checkUpdatedContainer waits for a freshly recreated container to prove itself, unless
+// automatic rollback is disabled.
+func (s *UpdaterService) checkUpdatedContainer(ctx context.Context, dcli *client.Client, name string) error {
+	if !s.settingsService.GetBoolSetting(ctx, "autoUpdateRollback", true) {
+		return nil
+	}
+	timeout := time.Duration(s.settingsService.GetIntSetting(ctx, "autoUpdateHealthTimeout", 120)) * time.Second
+	grace := time.Duration(s.settingsService.GetIntSetting(ctx, "autoUpdateGracePeriod", 30)) * time.Second
+	return waitForContainerHealth(ctx, dcli, name, timeout, grace, updaterHealthPollInterval)
+}
+
+// rollbackFailedUpdate recreates the container from its previous image after a failed health
+// check, records the outcome on res and notifies.
+func (s *UpdaterService) rollbackFailedUpdate(ctx context.Context, res *dto.AutoUpdateResourceResult, details models.JSON, newRef string, healthErr error) {
+	slog.WarnContext(ctx, "updated container failed health check, rolling back", "container", res.ResourceName, "err", healthErr)
+
+	res.Status = "failed"
+	res.Error = "health check failed: " + healthErr.Error()
+	details["rollbackReason"] = healthErr.Error()
+
+	rbErr := s.rollbackContainer(ctx, details, systemUser)
+	if rbErr != nil {
+		res.Error += "; rollback failed: " + rbErr.Error()
+	} else {
+		res.Status = string(models.AutoUpdateStatusRolledBack)
+		details["rolledBackAt"] = time.Now().UTC().Format(time.RFC3339)
+	}
+
+	if s.notificationService != nil {
+		if notifErr := s.notificationService.SendContainerRollbackNotification(ctx, res.ResourceName, newRef, res.NewImages["main"], res.OldImages["imageId"], healthErr.Error(), rbErr == nil); notifErr != nil {
+			slog.WarnContext(ctx, "Failed to send container rollback notification",
+				slog.String("containerName", res.ResourceName),
+				slog.String("error", notifErr.Error()))
+		}
+	}
+}
+
+// containerInspector is the part of the Docker client needed to watch container health.
+type containerInspector interface {
+	ContainerInspect(ctx context.Context, containerID string) (container.InspectResponse, error)
+}
+
+var updaterHealthPollInterval = 2 * time.Second
+
+// waitForContainerHealth watches a container after it was started. With a healthcheck it
+// must report healthy within timeout; without one it must keep running, and not restart,
+// for the whole grace period.
+func waitForContainerHealth(ctx context.Context, dcli containerInspector, containerID string, timeout, grace, interval time.Duration) error {
+	start := time.Now()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		inspect, err := dcli.ContainerInspect(ctx, containerID)
+		if err != nil {
+			return fmt.Errorf("inspect: %w", err)
+		}
+		state := inspect.State
+		if state == nil {
+			return fmt.Errorf("container state unavailable")
+		}
+		if state.Restarting {
+			return fmt.Errorf("container is restarting (exit code %d)", state.ExitCode)
+		}
+		if !state.Running {
+			return fmt.Errorf("container exited with code %d", state.ExitCode)
+		}
+
+		elapsed := time.Since(start)
+		if state.Health != nil {
+			switch state.Health.Status {
+			case container.Healthy:
+				return nil
+			case container.Unhealthy:
+				return fmt.Errorf("container is unhealthy%s", lastHealthOutput(state.Health))
+			}
+			if elapsed >= timeout {
+				return fmt.Errorf("container did not become healthy within %s", timeout)
+			}
+		} else if elapsed >= grace {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func lastHealthOutput(h *container.Health) string {
+	if h == nil || len(h.Log) == 0 {
+		return ""
+	}
+	out := strings.TrimSpace(h.Log[len(h.Log)-1].Output)
+	if out == "" {
+		return ""
+	}
+	if len(out) > 200 {
+		out = out[:200] + "..."
+	}
+	return ": " + out
+}
+
+// snapshotContainerVolumes backs up every named volume mounted by the (stopped) container
+// to the default backup location and returns one {volume, backupId} entry per volume.
+func (s *UpdaterService) snapshotContainerVolumes(ctx context.Context, inspect container.InspectResponse) ([]map[string]any, error) {
+	if s.volumeBackupService == nil {
+		return nil, fmt.Errorf("volume backups are not available")
+	}
+
+	snapshots := []map[string]any{}
+	for _, m := range inspect.Mounts {
+		if m.Type != mount.TypeVolume || m.Name == "" {
+			continue
+		}
+		backup, err := s.volumeBackupService.CreateBackup(ctx, m.Name, dto.CreateVolumeBackupDto{}, systemUser)
+		if err != nil {
+			return nil, fmt.Errorf("volume %s: %w", m.Name, err)
+		}
+		snapshots = append(snapshots, map[string]any{"volume": m.Name, "backupId": backup.ID})
+	}
+	return snapshots, nil
+}
+
+// Rollback reverts a container update recorded with volume snapshots: the container is
+// stopped, its volumes are restored from the snapshots and it is recreated on the previous
+// image. The container keeps referencing the previous image by ID, so later runs leave it
+// alone until it is redeployed.
+func (s *UpdaterService) Rollback(ctx context.Context, recordID string, user models.User) (*models.AutoUpdateRecord, error) {
+	var rec models.AutoUpdateRecord
+	if err := s.db.WithContext(ctx).Where("id = ?", recordID).First(&rec).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUpdateRecordNotFound
+		}
+		return nil, fmt.Errorf("get update record: %w", err)
+	}
+	if rec.ResourceType != "container" || rec.Status == models.AutoUpdateStatusRolledBack {
+		return nil, ErrRollbackUnavailable
+	}
+	// Without snapshots the previous image is pruned after the run and cannot be restored.
+	if _, ok := rec.Details["volumeSnapshots"]; !ok {
+		return nil, ErrRollbackUnavailable
+	}
+	if prev, _ := rec.Details["previousImageId"].(string); prev == "" {
+		return nil, ErrRollbackUnavailable
+	}
+
+	if err := s.rollbackContainer(ctx, rec.Details, user); err != nil {
+		s.logAutoUpdate(ctx, models.EventSeverityError, models.JSON{
+			"phase":     "rollback",
+			"container": rec.Details["containerName"],
+			"recordId":  rec.ID,
+			"status":    "failed",
+			"error":     err.Error(),
+		})
+		return nil, err
+	}
+	s.logAutoUpdate(ctx, models.EventSeveritySuccess, models.JSON{
+		"phase":     "rollback",
+		"container": rec.Details["containerName"],
+		"recordId":  rec.ID,
+		"status":    string(models.AutoUpdateStatusRolledBack),
+	})
+
+	rec.Details["rolledBackAt"] = time.Now().UTC().Format(time.RFC3339)
+	rec.Details["rolledBackBy"] = user.Username
+	rec.Status = models.AutoUpdateStatusRolledBack
+	if err := s.db.WithContext(ctx).Model(&rec).Updates(map[string]any{"status": rec.Status, "details": rec.Details}).Error; err != nil {
+		return nil, fmt.Errorf("update record: %w", err)
+	}
+	return &rec, nil
+}
+
+// rollbackContainer restores the volume snapshots listed in details and recreates the
+// container named there on its previous image ID.
+func (s *UpdaterService) rollbackContainer(ctx context.Context, details models.JSON, user models.User) error {
+	name, _ := details["containerName"].(string)
+	prevImage, _ := details["previousImageId"].(string)
+	if name == "" || prevImage == "" {
+		return ErrRollbackUnavailable
+	}
+
+	dcli, err := s.dockerService.CreateConnection(ctx)
+	if err != nil {
+		return fmt.Errorf("docker connect: %w", err)
+	}
+	defer dcli.Close()
+
+	inspect, err := dcli.ContainerInspect(ctx, name)
+	if err != nil {
+		return fmt.Errorf("inspect %s: %w", name, err)
+	}
+
+	if inspect.State != nil && inspect.State.Running {
+		if err := dcli.ContainerStop(ctx, inspect.ID, container.StopOptions{}); err != nil {
+			return fmt.Errorf("stop: %w", err)
+		}
+	}
+
+	snapshots, _ := details["volumeSnapshots"].([]any)
+	for _, raw := range snapshots {
+		snap, _ := raw.(map[string]any)
+		backupID, _ := snap["backupId"].(string)
+		if backupID == "" {
+			continue
+		}
+		if s.volumeBackupService == nil {
+			return fmt.Errorf("volume backups are not available")
+		}
+		if err := s.volumeBackupService.RestoreBackup(ctx, backupID, dto.RestoreVolumeBackupDto{Mode: RestoreModeOverwrite}, user); err != nil {
+			_ = dcli.ContainerStart(ctx, inspect.ID, container.StartOptions{})
+			return fmt.Errorf("restore volume %v: %w", snap["volume"], err)
+		}
+	}
+
+	if err := dcli.ContainerRemove(ctx, inspect.ID, container.RemoveOptions{}); err != nil {
+		return fmt.Errorf("remove: %w", err)
+	}
+
+	cfg := inspect.Config
+	cfg.Image = prevImage
+	resp, err := dcli.ContainerCreate(ctx, cfg, inspect.HostConfig, &network.NetworkingConfig{EndpointsConfig: inspect.NetworkSettings.Networks}, nil, inspect.Name)
+	if err != nil {
+		return fmt.Errorf("create: %w", err)
+	}
+	_ = s.eventService.LogContainerEvent(ctx, models.EventTypeContainerCreate, resp.ID, name, user.ID, user.Username, "0", models.JSON{"action": "updater_rollback", "image": prevImage})
+
+	if err := dcli.ContainerStart(ctx, resp.ID, container.StartOptions{}); err != nil {
+		return fmt.Errorf("start: %w", err)
+	}
 	return nil
 }
 
@@ -455,7 +914,11 @@ func (s *UpdaterService) stripDigest(ref string) string {
 	return ref
 }
 
-const arcaneUpdaterLabel = "com.ofkm.arcane.updater"
+const (
+	arcaneUpdaterLabel = "com.ofkm.arcane.updater"
+	// arcaneUpdaterSnapshotLabel opts a container into volume snapshots before each update.
+	arcaneUpdaterSnapshotLabel = "com.ofkm.arcane.updater.snapshot"
+)
 
 // isUpdateDisabled returns true if the special label is present and evaluates to false.
 // Accepts false/0/no/off (case-insensitive) as "disabled". Default is enabled.
@@ -476,6 +939,21 @@ func (s *UpdaterService) isUpdateDisabled(labels map[string]string) bool {
 	return false
 }
 
+// isSnapshotEnabled returns true if the snapshot label is set to true/1/yes/on
+// (case-insensitive). Default is disabled.
+func (s *UpdaterService) isSnapshotEnabled(labels map[string]string) bool {
+	for k, v := range labels {
+		if strings.EqualFold(k, arcaneUpdaterSnapshotLabel) {
+			switch strings.TrimSpace(strings.ToLower(v)) {
+			case "true", "1", "yes", "on":
+				return true
+			}
+			return false
+		}
+	}
+	return false
+}
+
 // collectUsedImagesFromContainers adds normalized image tags from non-opted-out running containers.
 func (s *UpdaterService) collectUsedImagesFromContainers(ctx context.Context, dcli *client.Client, out map[string]struct{}) error {
 	if dcli == nil {
@@ -648,6 +1126,10 @@ func (s *UpdaterService) recordRun(ctx context.Context, item dto.UpdaterItem) er
 		rec.NewImageVersions = newv
 	}
 
+	if len(item.Details) > 0 {
+		rec.Details = models.JSON(item.Details)
+	}
+
 	end := time.Now()
 	rec.EndTime = &end
 
@@ -737,37 +1219,69 @@ func (s *UpdaterService) restartContainersUsingOldIDs(ctx context.Context, oldID
 			ResourceName: name,
 			ResourceType: "container",
 			Status:       "checked",
-			OldImages:    map[string]string{"main": match},
+			OldImages:    map[string]string{"main": match, "imageId": inspect.Image},
 			NewImages:    map[string]string{"main": s.normalizeRef(newRef)},
 		}
 
-		if err := s.updateContainer(ctx, c, inspect, newRef); err != nil {
-			res.Status = "failed"
-			res.Error = err.Error()
-			slog.DebugContext(ctx, "restartContainersUsingOldIDs: update failed", "containerId", c.ID, "err", err)
-		} else {
-			res.Status = "updated"
+		var labels map[string]string
+		currentRef := newRef
+		if inspect.Config != nil {
+			labels = inspect.Config.Labels
+			currentRef = inspect.Config.Image
+		}
+		resolved := s.resolveUpdatePolicy(ctx, name, labels)
+		_, currentTag := s.parseRepoAndTag(currentRef)
+		_, newTag := s.parseRepoAndTag(newRef)
+		if reason := updatePolicySkipReason(resolved, currentTag != newTag, time.Now()); reason != "" {
+			slog.DebugContext(ctx, "restartContainersUsingOldIDs: skipped by update policy", "containerId", c.ID, "reason", reason)
+			res.Status = "skipped"
 			res.UpdateAvailable = true
-			res.UpdateApplied = true
-			slog.DebugContext(ctx, "restartContainersUsingOldIDs: update succeeded", "containerId", c.ID)
-
-			// Send notification after successful container update
-			if s.notificationService != nil {
-				if notifErr := s.notificationService.SendContainerUpdateNotification(ctx, name, newRef, match, s.normalizeRef(newRef)); notifErr != nil {
-					slog.WarnContext(ctx, "Failed to send container update notification",
-						slog.String("containerId", c.ID),
-						slog.String("containerName", name),
-						slog.String("imageRef", newRef),
-						slog.String("error", notifErr.Error()))
-				}
-			}
+			res.Details = policyDetails(resolved, reason)
+			results = append(results, res)
+			continue
 		}
+
+		s.applyContainerUpdate(ctx, dcli, c, inspect, &res, match, newRef)
 		results = append(results, res)
 	}
 	slog.DebugContext(ctx, "restartContainersUsingOldIDs: completed scanning", "results", len(results))
 	return results, nil
 }
 
+// applyContainerUpdate recreates the container on newRef, verifies its health (rolling back
+// on failure) and notifies about the outcome, recording everything on res.
+func (s *UpdaterService) applyContainerUpdate(ctx context.Context, dcli *client.Client, c container.Summary, inspect container.InspectResponse, res *dto.AutoUpdateResourceResult, oldRef, newRef string) {
+	name := res.ResourceName
+	details, err := s.updateContainer(ctx, c, inspect, newRef)
+	res.Details = details
+	if err != nil {
+		res.Status = "failed"
+		res.Error = err.Error()
+		slog.DebugContext(ctx, "applyContainerUpdate: update failed", "containerId", c.ID, "err", err)
+		return
+	}
+	if healthErr := s.checkUpdatedContainer(ctx, dcli, name); healthErr != nil {
+		s.rollbackFailedUpdate(ctx, res, details, newRef, healthErr)
+		return
+	}
+
+	res.Status = "updated"
+	res.UpdateAvailable = true
+	res.UpdateApplied = true
+	slog.DebugContext(ctx, "applyContainerUpdate: update succeeded", "containerId", c.ID)
+
+	// Send notification after successful container update
+	if s.notificationService != nil {
+		if notifErr := s.notificationService.SendContainerUpdateNotification(ctx, name, newRef, oldRef, s.normalizeRef(newRef)); notifErr != nil {
+			slog.WarnContext(ctx, "Failed to send container update notification",
+				slog.String("containerId", c.ID),
+				slog.String("containerName", name),
+				slog.String("imageRef", newRef),
+				slog.String("error", notifErr.Error()))
+		}
+	}
+}
+
 func (s *UpdaterService) getProjectContainers(ctx context.Context, dcli *client.Client, projectName string) ([]container.Summary, error) {
 	byID := map[string]container.Summary{}
 
@@ -836,6 +1350,9 @@ func (s *UpdaterService) logAutoUpdate(ctx context.Context, sev models.EventSeve
 		} else {
 			title = "Auto-update: project"
 		}
+	case "rollback":
+		name := fmt.Sprint(metadata["container"])
+		title = fmt.Sprintf("Auto-update: rollback %s", name)
 	case "complete":
 		title = "Auto-update run completed"
 	}
@@ -859,6 +1376,8 @@ func (s *UpdaterService) severityFromStatus(status string) models.EventSeverity
 	switch status {
 	case "failed":
 		return models.EventSeverityError
+	case string(models.AutoUpdateStatusRolledBack):
+		return models.EventSeverityWarning
 	case "updated":
 		return models.EventSeveritySuccess
 	default:
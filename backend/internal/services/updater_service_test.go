@@ -0,0 +1,102 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+
+	glsqlite "github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/ofkm/arcane-backend/internal/database"
+	"github.com/ofkm/arcane-backend/internal/models"
+)
+
+func TestUpdaterService_IsSnapshotEnabled(t *testing.T) {
+	s := &UpdaterService{}
+
+	require.False(t, s.isSnapshotEnabled(nil))
+	require.False(t, s.isSnapshotEnabled(map[string]string{arcaneUpdaterLabel: "true"}))
+	require.True(t, s.isSnapshotEnabled(map[string]string{arcaneUpdaterSnapshotLabel: "true"}))
+	require.True(t, s.isSnapshotEnabled(map[string]string{"COM.OFKM.ARCANE.UPDATER.SNAPSHOT": " Yes "}))
+	require.False(t, s.isSnapshotEnabled(map[string]string{arcaneUpdaterSnapshotLabel: "off"}))
+}
+
+func TestUpdaterService_RollbackRequiresSnapshotDetails(t *testing.T) {
+	ctx := context.Background()
+	gdb, err := gorm.Open(glsqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, gdb.AutoMigrate(&models.AutoUpdateRecord{}))
+	s := &UpdaterService{db: &database.DB{DB: gdb}}
+
+	_, err = s.Rollback(ctx, "missing", systemUser)
+	require.ErrorIs(t, err, ErrUpdateRecordNotFound)
+
+	plain := &models.AutoUpdateRecord{ResourceType: "container", ResourceName: "web", Status: "updated"}
+	require.NoError(t, gdb.Create(plain).Error)
+	_, err = s.Rollback(ctx, plain.ID, systemUser)
+	require.ErrorIs(t, err, ErrRollbackUnavailable)
+
+	noSnapshot := &models.AutoUpdateRecord{
+		ResourceType: "container",
+		ResourceName: "web",
+		Status:       "updated",
+		Details:      models.JSON{"containerName": "web", "previousImageId": "sha256:abc"},
+	}
+	require.NoError(t, gdb.Create(noSnapshot).Error)
+	_, err = s.Rollback(ctx, noSnapshot.ID, systemUser)
+	require.ErrorIs(t, err, ErrRollbackUnavailable)
+
+	done := &models.AutoUpdateRecord{
+		ResourceType: "container",
+		ResourceName: "web",
+		Status:       models.AutoUpdateStatusRolledBack,
+		Details:      models.JSON{"containerName": "web", "previousImageId": "sha256:abc", "volumeSnapshots": []any{}},
+	}
+	require.NoError(t, gdb.Create(done).Error)
+	_, err = s.Rollback(ctx, done.ID, systemUser)
+	require.ErrorIs(t, err, ErrRollbackUnavailable)
+}
+
+type fakeInspector struct {
+	states []*container.State
+	calls  int
+}
+
+func (f *fakeInspector) ContainerInspect(_ context.Context, _ string) (container.InspectResponse, error) {
+	st := f.states[min(f.calls, len(f.states)-1)]
+	f.calls++
+	return container.InspectResponse{ContainerJSONBase: &container.ContainerJSONBase{State: st}}, nil
+}
+
+func TestWaitForContainerHealth(t *testing.T) {
+	ctx := context.Background()
+	running := &container.State{Running: true}
+	starting := &container.State{Running: true, Health: &container.Health{Status: container.Starting}}
+	healthy := &container.State{Running: true, Health: &container.Health{Status: container.Healthy}}
+	unhealthy := &container.State{Running: true, Health: &container.Health{
+		Status: container.Unhealthy,
+		Log:    []*container.HealthcheckResult{{Output: "connection refused\n"}},
+	}}
+	exited := &container.State{Running: false, ExitCode: 1}
+	restarting := &container.State{Running: true, Restarting: true, ExitCode: 137}
+
+	require.NoError(t, waitForContainerHealth(ctx, &fakeInspector{states: []*container.State{starting, starting, healthy}}, "c", time.Second, 0, time.Millisecond))
+
+	err := waitForContainerHealth(ctx, &fakeInspector{states: []*container.State{starting, unhealthy}}, "c", time.Second, 0, time.Millisecond)
+	require.ErrorContains(t, err, "connection refused")
+
+	err = waitForContainerHealth(ctx, &fakeInspector{states: []*container.State{starting}}, "c", 5*time.Millisecond, 0, time.Millisecond)
+	require.ErrorContains(t, err, "did not become healthy")
+
+	require.NoError(t, waitForContainerHealth(ctx, &fakeInspector{states: []*container.State{running}}, "c", time.Second, 5*time.Millisecond, time.Millisecond))
+
+	err = waitForContainerHealth(ctx, &fakeInspector{states: []*container.State{running, exited}}, "c", time.Second, time.Second, time.Millisecond)
+	require.ErrorContains(t, err, "exited with code 1")
+
+	err = waitForContainerHealth(ctx, &fakeInspector{states: []*container.State{running, restarting}}, "c", time.Second, time.Second, time.Millisecond)
+	require.ErrorContains(t, err, "restarting")
+}
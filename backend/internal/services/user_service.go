@@ -43,12 +43,14 @@ func DefaultArgon2Params() *Argon2Params {
 type UserService struct {
 	db           *database.DB
 	argon2Params *Argon2Params
+	audit        *AuditService
 }
 
-func NewUserService(db *database.DB) *UserService {
+func NewUserService(db *database.DB, audit *AuditService) *UserService {
 	return &UserService{
 		db:           db,
 		argon2Params: DefaultArgon2Params(),
+		audit:        audit,
 	}
 }
 
@@ -134,6 +136,14 @@ func (s *UserService) CreateUser(ctx context.Context, user *models.User) (*model
 	if err := s.db.WithContext(ctx).Create(user).Error; err != nil {
 		return nil, fmt.Errorf("failed to create user: %w", err)
 	}
+
+	s.audit.Record(ctx, AuditChange{
+		Action:     auditActionCreate,
+		TargetType: auditTargetUser,
+		TargetID:   user.ID,
+		TargetName: user.Username,
+		After:      auditUserSnapshot(user),
+	})
 	return user, nil
 }
 
@@ -203,9 +213,28 @@ func (s *UserService) GetUserByEmail(ctx context.Context, email string) (*models
 }
 
 func (s *UserService) UpdateUser(ctx context.Context, user *models.User) (*models.User, error) {
+	// Callers mutate the user they loaded, so the stored row is the only before state.
+	var before *models.User
+	if s.audit != nil {
+		if existing, err := s.getUserInternal(ctx, user.ID, s.db.DB); err == nil {
+			before = existing
+		}
+	}
+
 	if err := s.db.WithContext(ctx).Save(user).Error; err != nil {
 		return nil, fmt.Errorf("failed to update user: %w", err)
 	}
+
+	if before != nil {
+		s.audit.Record(ctx, AuditChange{
+			Action:     auditActionUpdate,
+			TargetType: auditTargetUser,
+			TargetID:   user.ID,
+			TargetName: user.Username,
+			Before:     auditUserSnapshot(before),
+			After:      auditUserSnapshot(user),
+		})
+	}
 	return user, nil
 }
 
@@ -294,12 +323,53 @@ func (s *UserService) CreateDefaultAdmin() error {
 }
 
 func (s *UserService) DeleteUser(ctx context.Context, id string) error {
-	if err := s.db.WithContext(ctx).Delete(&models.User{}, "id = ?", id).Error; err != nil {
+	var before *models.User
+	if s.audit != nil {
+		if existing, err := s.getUserInternal(ctx, id, s.db.DB); err == nil {
+			before = existing
+		}
+	}
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&models.RoleBinding{}, "user_id = ?", id).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&models.ApiToken{}, "user_id = ?", id).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&models.WebauthnCredential{}, "user_id = ?", id).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&models.AuthSession{}, "user_id = ?", id).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.User{}, "id = ?", id).Error
+	})
+	if err != nil {
 		return fmt.Errorf("failed to delete user: %w", err)
 	}
+
+	if before != nil {
+		s.audit.Record(ctx, AuditChange{
+			Action:     auditActionDelete,
+			TargetType: auditTargetUser,
+			TargetID:   id,
+			TargetName: before.Username,
+			Before:     auditUserSnapshot(before),
+		})
+	}
 	return nil
 }
 
+// auditUserSnapshot exposes the password hash, which the user's JSON omits, so that
+// password changes appear (redacted) in the audit diff.
+func auditUserSnapshot(user *models.User) any {
+	return struct {
+		*models.User
+		Password string `json:"password,omitempty"`
+	}{user, user.PasswordHash}
+}
+
 func (s *UserService) HashPassword(password string) (string, error) {
 	return s.hashPassword(password)
 }
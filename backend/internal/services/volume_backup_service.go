@@ -0,0 +1,1055 @@
+package services
+
+import (
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/errdefs"
+	"github.com/robfig/cron/v3"
+	"gorm.io/gorm"
+
+	"github.com/ofkm/arcane-backend/internal/database"
+	"github.com/ofkm/arcane-backend/internal/dto"
+	"github.com/ofkm/arcane-backend/internal/models"
+	"github.com/ofkm/arcane-backend/internal/utils"
+	"github.com/ofkm/arcane-backend/internal/utils/docker"
+)
+
+var (
+	ErrBackupTargetNotFound   = errors.New("backup target not found")
+	ErrBackupTargetInUse      = errors.New("backup target is used by a schedule")
+	ErrBackupScheduleNotFound = errors.New("backup schedule not found")
+	ErrVolumeBackupNotFound   = errors.New("volume backup not found")
+	ErrVolumeBackupBusy       = errors.New("a backup or restore is already running for this volume")
+	ErrInvalidVolumeBackup    = errors.New("invalid volume backup request")
+)
+
+const (
+	RestoreModeOverwrite = "overwrite"
+	RestoreModeRecreate  = "recreate"
+
+	backupHelperLabel     = "com.ofkm.arcane.backup-helper"
+	backupVolumeMountPath = "/volume"
+)
+
+type VolumeBackupService struct {
+	db              *database.DB
+	dockerService   *DockerClientService
+	imageService    *ImageService
+	settingsService *SettingsService
+	eventService    *EventService
+	audit           *AuditService
+
+	mu sync.Mutex
+	// volume names with a backup or restore in progress
+	busy map[string]struct{}
+}
+
+func NewVolumeBackupService(db *database.DB, dockerService *DockerClientService, imageService *ImageService, settingsService *SettingsService, eventService *EventService, audit *AuditService) *VolumeBackupService {
+	return &VolumeBackupService{
+		db:              db,
+		dockerService:   dockerService,
+		imageService:    imageService,
+		settingsService: settingsService,
+		eventService:    eventService,
+		audit:           audit,
+		busy:            map[string]struct{}{},
+	}
+}
+
+// Targets
+
+func (s *VolumeBackupService) ListTargets(ctx context.Context) ([]models.BackupTarget, error) {
+	var targets []models.BackupTarget
+	if err := s.db.WithContext(ctx).Order("name ASC").Find(&targets).Error; err != nil {
+		return nil, fmt.Errorf("failed to list backup targets: %w", err)
+	}
+	return targets, nil
+}
+
+func (s *VolumeBackupService) GetTarget(ctx context.Context, id string) (*models.BackupTarget, error) {
+	var target models.BackupTarget
+	if err := s.db.WithContext(ctx).Where("id = ?", id).First(&target).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrBackupTargetNotFound
+		}
+		return nil, fmt.Errorf("failed to get backup target: %w", err)
+	}
+	return &target, nil
+}
+
+func (s *VolumeBackupService) CreateTarget(ctx context.Context, req dto.CreateBackupTargetDto) (*models.BackupTarget, error) {
+	target := &models.BackupTarget{
+		Name:        strings.TrimSpace(req.Name),
+		Type:        models.BackupTargetType(strings.ToLower(strings.TrimSpace(req.Type))),
+		Path:        strings.TrimSpace(req.Path),
+		Endpoint:    strings.TrimSpace(req.Endpoint),
+		Region:      strings.TrimSpace(req.Region),
+		Bucket:      strings.TrimSpace(req.Bucket),
+		Prefix:      strings.Trim(strings.TrimSpace(req.Prefix), "/"),
+		AccessKeyID: strings.TrimSpace(req.AccessKeyID),
+		PathStyle:   req.PathStyle,
+	}
+	if req.SecretAccessKey != nil && *req.SecretAccessKey != "" {
+		encrypted, err := utils.Encrypt(*req.SecretAccessKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encrypt secret access key: %w", err)
+		}
+		target.SecretAccessKey = encrypted
+	}
+	if err := s.validateBackupTarget(ctx, target); err != nil {
+		return nil, err
+	}
+
+	if err := s.db.WithContext(ctx).Create(target).Error; err != nil {
+		return nil, fmt.Errorf("failed to create backup target: %w", err)
+	}
+
+	s.audit.Record(ctx, AuditChange{
+		Action:     auditActionCreate,
+		TargetType: auditTargetBackupTarget,
+		TargetID:   target.ID,
+		TargetName: target.Name,
+		After:      target,
+	})
+	return target, nil
+}
+
+func (s *VolumeBackupService) UpdateTarget(ctx context.Context, id string, req dto.UpdateBackupTargetDto) (*models.BackupTarget, error) {
+	target, err := s.GetTarget(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	before := *target
+
+	if req.Name != nil {
+		target.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Path != nil {
+		target.Path = strings.TrimSpace(*req.Path)
+	}
+	if req.Endpoint != nil {
+		target.Endpoint = strings.TrimSpace(*req.Endpoint)
+	}
+	if req.Region != nil {
+		target.Region = strings.TrimSpace(*req.Region)
+	}
+	if req.Bucket != nil {
+		target.Bucket = strings.TrimSpace(*req.Bucket)
+	}
+	if req.Prefix != nil {
+		target.Prefix = strings.Trim(strings.TrimSpace(*req.Prefix), "/")
+	}
+	if req.AccessKeyID != nil {
+		target.AccessKeyID = strings.TrimSpace(*req.AccessKeyID)
+	}
+	if req.PathStyle != nil {
+		target.PathStyle = *req.PathStyle
+	}
+	if req.SecretAccessKey != nil {
+		target.SecretAccessKey = ""
+		if *req.SecretAccessKey != "" {
+			encrypted, err := utils.Encrypt(*req.SecretAccessKey)
+			if err != nil {
+				return nil, fmt.Errorf("failed to encrypt secret access key: %w", err)
+			}
+			target.SecretAccessKey = encrypted
+		}
+	}
+	if err := s.validateBackupTarget(ctx, target); err != nil {
+		return nil, err
+	}
+
+	if err := s.db.WithContext(ctx).Save(target).Error; err != nil {
+		return nil, fmt.Errorf("failed to update backup target: %w", err)
+	}
+
+	s.audit.Record(ctx, AuditChange{
+		Action:     auditActionUpdate,
+		TargetType: auditTargetBackupTarget,
+		TargetID:   target.ID,
+		TargetName: target.Name,
+		Before:     &before,
+		After:      target,
+	})
+	return target, nil
+}
+
+// DeleteTarget removes a target. Existing archives are left in place and their history
+// entries can no longer be restored or deleted through Arcane.
+func (s *VolumeBackupService) DeleteTarget(ctx context.Context, id string) error {
+	var inUse int64
+	if err := s.db.WithContext(ctx).Model(&models.VolumeBackupSchedule{}).Where("target_id = ?", id).Count(&inUse).Error; err != nil {
+		return fmt.Errorf("failed to check backup target usage: %w", err)
+	}
+	if inUse > 0 {
+		return ErrBackupTargetInUse
+	}
+
+	before, err := s.GetTarget(ctx, id)
+	if err != nil {
+		return err
+	}
+	result := s.db.WithContext(ctx).Where("id = ?", id).Delete(&models.BackupTarget{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete backup target: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrBackupTargetNotFound
+	}
+
+	s.audit.Record(ctx, AuditChange{
+		Action:     auditActionDelete,
+		TargetType: auditTargetBackupTarget,
+		TargetID:   id,
+		TargetName: before.Name,
+		Before:     before,
+	})
+	return nil
+}
+
+func (s *VolumeBackupService) validateBackupTarget(ctx context.Context, target *models.BackupTarget) error {
+	if target.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidVolumeBackup)
+	}
+	switch target.Type {
+	case models.BackupTargetLocal:
+		if target.Path != "" {
+			return s.checkLocalBackupPath(ctx, target.Path)
+		}
+	case models.BackupTargetS3:
+		if target.Bucket == "" {
+			return fmt.Errorf("%w: bucket is required for s3 targets", ErrInvalidVolumeBackup)
+		}
+	default:
+		return fmt.Errorf("%w: unsupported target type %q", ErrInvalidVolumeBackup, target.Type)
+	}
+	return nil
+}
+
+// checkLocalBackupPath fails unless dir lies inside the backup directory or one of the
+// directories listed in the backupAllowedPaths setting. Targets are managed by users who
+// cannot change settings, so this keeps them from writing archives anywhere on the host.
+func (s *VolumeBackupService) checkLocalBackupPath(ctx context.Context, dir string) error {
+	resolved, err := resolveBackupPath(dir)
+	if err != nil {
+		return fmt.Errorf("%w: invalid path %q: %w", ErrInvalidVolumeBackup, dir, err)
+	}
+
+	roots := []string{s.backupDirectory(ctx)}
+	for _, root := range strings.Split(s.settingsService.GetStringSetting(ctx, "backupAllowedPaths", ""), ",") {
+		if root = strings.TrimSpace(root); filepath.IsAbs(root) {
+			roots = append(roots, root)
+		}
+	}
+	for _, root := range roots {
+		resolvedRoot, err := resolveBackupPath(root)
+		if err != nil {
+			continue
+		}
+		rel, err := filepath.Rel(resolvedRoot, resolved)
+		if err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: path %q is outside the backup directory and the allowed backup paths", ErrInvalidVolumeBackup, dir)
+}
+
+// resolveBackupPath returns path made absolute, with symlinks resolved in the part of it
+// that exists, so a link inside an allowed directory cannot lead out of it.
+func resolveBackupPath(path string) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	existing, rest := abs, ""
+	for {
+		if resolved, err := filepath.EvalSymlinks(existing); err == nil {
+			return filepath.Join(resolved, rest), nil
+		}
+		parent := filepath.Dir(existing)
+		if parent == existing {
+			return abs, nil
+		}
+		rest = filepath.Join(filepath.Base(existing), rest)
+		existing = parent
+	}
+}
+
+// Schedules
+
+func (s *VolumeBackupService) ListSchedules(ctx context.Context) ([]models.VolumeBackupSchedule, error) {
+	var schedules []models.VolumeBackupSchedule
+	if err := s.db.WithContext(ctx).Order("volume_name ASC, created_at ASC").Find(&schedules).Error; err != nil {
+		return nil, fmt.Errorf("failed to list backup schedules: %w", err)
+	}
+	return schedules, nil
+}
+
+func (s *VolumeBackupService) GetSchedule(ctx context.Context, id string) (*models.VolumeBackupSchedule, error) {
+	var schedule models.VolumeBackupSchedule
+	if err := s.db.WithContext(ctx).Where("id = ?", id).First(&schedule).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrBackupScheduleNotFound
+		}
+		return nil, fmt.Errorf("failed to get backup schedule: %w", err)
+	}
+	return &schedule, nil
+}
+
+func (s *VolumeBackupService) CreateSchedule(ctx context.Context, req dto.CreateVolumeBackupScheduleDto) (*models.VolumeBackupSchedule, error) {
+	schedule := &models.VolumeBackupSchedule{
+		VolumeName:     strings.TrimSpace(req.VolumeName),
+		CronExpression: strings.TrimSpace(req.CronExpression),
+		TargetID:       emptyToNil(req.TargetID),
+		RetentionCount: req.RetentionCount,
+		RetentionDays:  req.RetentionDays,
+		StopContainers: req.StopContainers,
+		Enabled:        req.Enabled == nil || *req.Enabled,
+	}
+	if err := s.validateSchedule(ctx, schedule); err != nil {
+		return nil, err
+	}
+
+	if err := s.db.WithContext(ctx).Create(schedule).Error; err != nil {
+		return nil, fmt.Errorf("failed to create backup schedule: %w", err)
+	}
+
+	s.audit.Record(ctx, AuditChange{
+		Action:     auditActionCreate,
+		TargetType: auditTargetBackupSchedule,
+		TargetID:   schedule.ID,
+		TargetName: schedule.VolumeName,
+		After:      schedule,
+	})
+	return schedule, nil
+}
+
+func (s *VolumeBackupService) UpdateSchedule(ctx context.Context, id string, req dto.UpdateVolumeBackupScheduleDto) (*models.VolumeBackupSchedule, error) {
+	schedule, err := s.GetSchedule(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	before := *schedule
+
+	if req.CronExpression != nil {
+		schedule.CronExpression = strings.TrimSpace(*req.CronExpression)
+	}
+	if req.TargetID != nil {
+		schedule.TargetID = emptyToNil(req.TargetID)
+	}
+	if req.RetentionCount != nil {
+		schedule.RetentionCount = *req.RetentionCount
+	}
+	if req.RetentionDays != nil {
+		schedule.RetentionDays = *req.RetentionDays
+	}
+	if req.StopContainers != nil {
+		schedule.StopContainers = *req.StopContainers
+	}
+	if req.Enabled != nil {
+		schedule.Enabled = *req.Enabled
+	}
+	if err := s.validateSchedule(ctx, schedule); err != nil {
+		return nil, err
+	}
+
+	if err := s.db.WithContext(ctx).Save(schedule).Error; err != nil {
+		return nil, fmt.Errorf("failed to update backup schedule: %w", err)
+	}
+
+	s.audit.Record(ctx, AuditChange{
+		Action:     auditActionUpdate,
+		TargetType: auditTargetBackupSchedule,
+		TargetID:   schedule.ID,
+		TargetName: schedule.VolumeName,
+		Before:     &before,
+		After:      schedule,
+	})
+	return schedule, nil
+}
+
+func (s *VolumeBackupService) DeleteSchedule(ctx context.Context, id string) error {
+	before, err := s.GetSchedule(ctx, id)
+	if err != nil {
+		return err
+	}
+	result := s.db.WithContext(ctx).Where("id = ?", id).Delete(&models.VolumeBackupSchedule{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete backup schedule: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrBackupScheduleNotFound
+	}
+
+	s.audit.Record(ctx, AuditChange{
+		Action:     auditActionDelete,
+		TargetType: auditTargetBackupSchedule,
+		TargetID:   id,
+		TargetName: before.VolumeName,
+		Before:     before,
+	})
+	return nil
+}
+
+func (s *VolumeBackupService) validateSchedule(ctx context.Context, schedule *models.VolumeBackupSchedule) error {
+	if schedule.VolumeName == "" {
+		return fmt.Errorf("%w: volume name is required", ErrInvalidVolumeBackup)
+	}
+	if _, err := cron.ParseStandard(schedule.CronExpression); err != nil {
+		return fmt.Errorf("%w: invalid cron expression: %s", ErrInvalidVolumeBackup, err.Error())
+	}
+	if schedule.RetentionCount < 0 || schedule.RetentionDays < 0 {
+		return fmt.Errorf("%w: retention must not be negative", ErrInvalidVolumeBackup)
+	}
+	if schedule.TargetID != nil {
+		if _, err := s.GetTarget(ctx, *schedule.TargetID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RunDueSchedules starts every enabled schedule whose next cron time has passed since it
+// last ran. Backups run one after another so a slow volume delays, but never overlaps, the
+// next one.
+func (s *VolumeBackupService) RunDueSchedules(ctx context.Context) error {
+	var schedules []models.VolumeBackupSchedule
+	if err := s.db.WithContext(ctx).Where("enabled = ?", true).Find(&schedules).Error; err != nil {
+		return fmt.Errorf("failed to load backup schedules: %w", err)
+	}
+
+	now := time.Now()
+	for i := range schedules {
+		schedule := schedules[i]
+		if !scheduleIsDue(schedule, now) {
+			continue
+		}
+
+		if err := s.db.WithContext(ctx).Model(&models.VolumeBackupSchedule{}).Where("id = ?", schedule.ID).Update("last_run_at", now).Error; err != nil {
+			slog.WarnContext(ctx, "failed to record backup schedule run", "schedule", schedule.ID, "error", err)
+			continue
+		}
+
+		if _, err := s.runBackup(ctx, schedule.VolumeName, schedule.TargetID, schedule.StopContainers, &schedule.ID, systemUser); err != nil {
+			slog.ErrorContext(ctx, "scheduled volume backup failed", "schedule", schedule.ID, "volume", schedule.VolumeName, "error", err)
+			continue
+		}
+
+		if err := s.applyRetention(ctx, schedule, now); err != nil {
+			slog.WarnContext(ctx, "failed to apply backup retention", "schedule", schedule.ID, "error", err)
+		}
+	}
+	return nil
+}
+
+func scheduleIsDue(schedule models.VolumeBackupSchedule, now time.Time) bool {
+	sched, err := cron.ParseStandard(schedule.CronExpression)
+	if err != nil {
+		return false
+	}
+	last := schedule.CreatedAt
+	if schedule.LastRunAt != nil {
+		last = *schedule.LastRunAt
+	}
+	return !sched.Next(last).After(now)
+}
+
+// applyRetention deletes the schedule's successful backups beyond RetentionCount and those
+// older than RetentionDays. Failed attempts are kept for troubleshooting.
+func (s *VolumeBackupService) applyRetention(ctx context.Context, schedule models.VolumeBackupSchedule, now time.Time) error {
+	if schedule.RetentionCount <= 0 && schedule.RetentionDays <= 0 {
+		return nil
+	}
+
+	var backups []models.VolumeBackup
+	if err := s.db.WithContext(ctx).
+		Where("schedule_id = ? AND status = ?", schedule.ID, models.VolumeBackupStatusSuccess).
+		Order("started_at DESC").
+		Find(&backups).Error; err != nil {
+		return fmt.Errorf("failed to load backups for retention: %w", err)
+	}
+
+	cutoff := time.Time{}
+	if schedule.RetentionDays > 0 {
+		cutoff = now.AddDate(0, 0, -schedule.RetentionDays)
+	}
+
+	for i := range backups {
+		expiredByCount := schedule.RetentionCount > 0 && i >= schedule.RetentionCount
+		expiredByAge := !cutoff.IsZero() && backups[i].StartedAt.Before(cutoff)
+		if !expiredByCount && !expiredByAge {
+			continue
+		}
+		if err := s.removeBackup(ctx, &backups[i]); err != nil {
+			slog.WarnContext(ctx, "failed to prune volume backup", "backup", backups[i].ID, "error", err)
+		}
+	}
+	return nil
+}
+
+// History
+
+// ListBackups returns the backup history, newest first, optionally for a single volume.
+func (s *VolumeBackupService) ListBackups(ctx context.Context, volumeName string) ([]models.VolumeBackup, error) {
+	q := s.db.WithContext(ctx).Order("started_at DESC")
+	if volumeName != "" {
+		q = q.Where("volume_name = ?", volumeName)
+	}
+	var backups []models.VolumeBackup
+	if err := q.Find(&backups).Error; err != nil {
+		return nil, fmt.Errorf("failed to list volume backups: %w", err)
+	}
+	return backups, nil
+}
+
+func (s *VolumeBackupService) GetBackup(ctx context.Context, id string) (*models.VolumeBackup, error) {
+	var backup models.VolumeBackup
+	if err := s.db.WithContext(ctx).Where("id = ?", id).First(&backup).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrVolumeBackupNotFound
+		}
+		return nil, fmt.Errorf("failed to get volume backup: %w", err)
+	}
+	return &backup, nil
+}
+
+func (s *VolumeBackupService) DeleteBackup(ctx context.Context, id string, user models.User) error {
+	backup, err := s.GetBackup(ctx, id)
+	if err != nil {
+		return err
+	}
+	if backup.Status == models.VolumeBackupStatusRunning {
+		return ErrVolumeBackupBusy
+	}
+	if err := s.removeBackup(ctx, backup); err != nil {
+		return err
+	}
+
+	metadata := models.JSON{"action": "delete_backup", "backupId": backup.ID}
+	if logErr := s.eventService.LogVolumeEvent(ctx, models.EventTypeVolumeDelete, backup.VolumeName, backup.VolumeName, user.ID, user.Username, "0", metadata); logErr != nil {
+		slog.WarnContext(ctx, "could not log volume backup deletion", "error", logErr)
+	}
+	return nil
+}
+
+// removeBackup deletes the archive and then the history entry. When the target no longer
+// exists only the history entry is removed.
+func (s *VolumeBackupService) removeBackup(ctx context.Context, backup *models.VolumeBackup) error {
+	if backup.Location != "" {
+		store, err := s.storeForBackup(ctx, backup)
+		switch {
+		case errors.Is(err, ErrBackupTargetNotFound):
+			slog.WarnContext(ctx, "backup target is gone; removing history entry only", "backup", backup.ID)
+		case err != nil:
+			return err
+		default:
+			if err := store.Delete(ctx, backup.Location); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := s.db.WithContext(ctx).Delete(&models.VolumeBackup{}, "id = ?", backup.ID).Error; err != nil {
+		return fmt.Errorf("failed to delete volume backup record: %w", err)
+	}
+	return nil
+}
+
+// Backup and restore
+
+// CreateBackup archives a volume right away and returns the finished history entry.
+func (s *VolumeBackupService) CreateBackup(ctx context.Context, volumeName string, req dto.CreateVolumeBackupDto, user models.User) (*models.VolumeBackup, error) {
+	return s.runBackup(ctx, volumeName, emptyToNil(req.TargetID), req.StopContainers, nil, user)
+}
+
+func (s *VolumeBackupService) runBackup(ctx context.Context, volumeName string, targetID *string, stopContainers bool, scheduleID *string, user models.User) (*models.VolumeBackup, error) {
+	if !s.acquire(volumeName) {
+		return nil, ErrVolumeBackupBusy
+	}
+	defer s.release(volumeName)
+
+	store, targetType, err := s.storeForTarget(ctx, targetID)
+	if err != nil {
+		return nil, err
+	}
+
+	backup := &models.VolumeBackup{
+		VolumeName:  volumeName,
+		ScheduleID:  scheduleID,
+		TargetID:    targetID,
+		TargetType:  targetType,
+		Status:      models.VolumeBackupStatusRunning,
+		TriggeredBy: user.Username,
+		StartedAt:   time.Now(),
+	}
+	if err := s.db.WithContext(ctx).Create(backup).Error; err != nil {
+		return nil, fmt.Errorf("failed to record volume backup: %w", err)
+	}
+
+	runErr := s.writeBackup(ctx, backup, store, stopContainers)
+
+	now := time.Now()
+	backup.CompletedAt = &now
+	backup.Status = models.VolumeBackupStatusSuccess
+	if runErr != nil {
+		backup.Status = models.VolumeBackupStatusFailed
+		backup.Error = utils.Ptr(runErr.Error())
+	}
+	if err := s.db.WithContext(ctx).Save(backup).Error; err != nil {
+		slog.WarnContext(ctx, "failed to update volume backup record", "backup", backup.ID, "error", err)
+	}
+
+	metadata := models.JSON{
+		"action":     "backup",
+		"backupId":   backup.ID,
+		"targetType": string(targetType),
+	}
+	if scheduleID != nil {
+		metadata["scheduleId"] = *scheduleID
+	}
+	if runErr != nil {
+		s.eventService.LogErrorEvent(ctx, models.EventTypeVolumeError, "volume", volumeName, volumeName, user.ID, user.Username, "0", runErr, metadata)
+		return backup, runErr
+	}
+
+	metadata["sizeBytes"] = backup.SizeBytes
+	if logErr := s.eventService.LogVolumeEvent(ctx, models.EventTypeVolumeBackup, volumeName, volumeName, user.ID, user.Username, "0", metadata); logErr != nil {
+		slog.WarnContext(ctx, "could not log volume backup", "error", logErr)
+	}
+	return backup, nil
+}
+
+func (s *VolumeBackupService) writeBackup(ctx context.Context, backup *models.VolumeBackup, store backupStore, stopContainers bool) error {
+	dockerClient, err := s.dockerService.CreateConnection(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to connect to Docker: %w", err)
+	}
+	defer dockerClient.Close()
+
+	if _, err := dockerClient.VolumeInspect(ctx, backup.VolumeName); err != nil {
+		return fmt.Errorf("volume not found: %w", err)
+	}
+
+	if stopContainers {
+		restart, err := stopVolumeContainers(ctx, dockerClient, backup.VolumeName)
+		defer restart()
+		if err != nil {
+			return err
+		}
+	}
+
+	helperID, cleanup, err := s.createHelperContainer(ctx, dockerClient, backup.VolumeName, true, nil)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	reader, _, err := dockerClient.CopyFromContainer(ctx, helperID, backupVolumeMountPath)
+	if err != nil {
+		return fmt.Errorf("failed to read volume contents: %w", err)
+	}
+	defer reader.Close()
+
+	tmp, err := os.CreateTemp("", "arcane-volume-backup-*.tar.gz")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary archive: %w", err)
+	}
+	defer func() {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+	}()
+
+	hasher := sha256.New()
+	counter := &countingWriter{}
+	gz := gzip.NewWriter(io.MultiWriter(tmp, hasher, counter))
+	if _, err := io.Copy(gz, reader); err != nil {
+		return fmt.Errorf("failed to archive volume: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("failed to archive volume: %w", err)
+	}
+
+	backup.SizeBytes = counter.n
+	backup.Checksum = hex.EncodeToString(hasher.Sum(nil))
+
+	key := fmt.Sprintf("%s/%s-%s.tar.gz", backup.VolumeName, backup.VolumeName, backup.StartedAt.UTC().Format("20060102T150405Z"))
+	location, err := store.Put(ctx, key, tmp, backup.SizeBytes, backup.Checksum)
+	if err != nil {
+		return err
+	}
+	backup.Location = location
+	return nil
+}
+
+// RestoreBackup writes an archive back into its volume, or into req.TargetVolume. Running
+// containers using the destination are stopped for the duration and started again after.
+func (s *VolumeBackupService) RestoreBackup(ctx context.Context, backupID string, req dto.RestoreVolumeBackupDto, user models.User) error {
+	backup, err := s.GetBackup(ctx, backupID)
+	if err != nil {
+		return err
+	}
+	if backup.Status != models.VolumeBackupStatusSuccess {
+		return fmt.Errorf("%w: only successful backups can be restored", ErrInvalidVolumeBackup)
+	}
+
+	mode := strings.ToLower(strings.TrimSpace(req.Mode))
+	switch mode {
+	case "":
+		mode = RestoreModeOverwrite
+	case RestoreModeOverwrite, RestoreModeRecreate:
+	default:
+		return fmt.Errorf("%w: unsupported restore mode %q", ErrInvalidVolumeBackup, req.Mode)
+	}
+
+	dest := strings.TrimSpace(req.TargetVolume)
+	if dest == "" {
+		dest = backup.VolumeName
+	}
+
+	if !s.acquire(dest) {
+		return ErrVolumeBackupBusy
+	}
+	defer s.release(dest)
+
+	metadata := models.JSON{
+		"action":       "restore",
+		"backupId":     backup.ID,
+		"mode":         mode,
+		"sourceVolume": backup.VolumeName,
+	}
+
+	if err := s.restore(ctx, backup, dest, mode); err != nil {
+		s.eventService.LogErrorEvent(ctx, models.EventTypeVolumeError, "volume", dest, dest, user.ID, user.Username, "0", err, metadata)
+		return err
+	}
+
+	if logErr := s.eventService.LogVolumeEvent(ctx, models.EventTypeVolumeRestore, dest, dest, user.ID, user.Username, "0", metadata); logErr != nil {
+		slog.WarnContext(ctx, "could not log volume restore", "error", logErr)
+	}
+	return nil
+}
+
+func (s *VolumeBackupService) restore(ctx context.Context, backup *models.VolumeBackup, dest, mode string) error {
+	store, err := s.storeForBackup(ctx, backup)
+	if err != nil {
+		return err
+	}
+
+	archive, err := downloadVerifiedArchive(ctx, store, backup)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = archive.Close()
+		_ = os.Remove(archive.Name())
+	}()
+
+	dockerClient, err := s.dockerService.CreateConnection(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to connect to Docker: %w", err)
+	}
+	defer dockerClient.Close()
+
+	if err := prepareRestoreVolume(ctx, dockerClient, dest, mode); err != nil {
+		return err
+	}
+
+	restart, err := stopVolumeContainers(ctx, dockerClient, dest)
+	defer restart()
+	if err != nil {
+		return err
+	}
+
+	var wipe []string
+	if mode == RestoreModeOverwrite {
+		wipe = []string{"sh", "-c", "find " + backupVolumeMountPath + " -mindepth 1 -delete"}
+	}
+	helperID, cleanup, err := s.createHelperContainer(ctx, dockerClient, dest, false, wipe)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	if wipe != nil {
+		if err := runHelperToCompletion(ctx, dockerClient, helperID); err != nil {
+			return fmt.Errorf("failed to clear volume before restore: %w", err)
+		}
+	}
+
+	gz, err := gzip.NewReader(archive)
+	if err != nil {
+		return fmt.Errorf("failed to open backup archive: %w", err)
+	}
+	defer gz.Close()
+
+	// Archives hold a top-level "volume/" directory, so extracting at / lands in the mount.
+	if err := dockerClient.CopyToContainer(ctx, helperID, "/", gz, container.CopyToContainerOptions{}); err != nil {
+		return fmt.Errorf("failed to write volume contents: %w", err)
+	}
+	return nil
+}
+
+func prepareRestoreVolume(ctx context.Context, dockerClient *client.Client, name, mode string) error {
+	existing, err := dockerClient.VolumeInspect(ctx, name)
+	if err != nil {
+		if !errdefs.IsNotFound(err) {
+			return fmt.Errorf("failed to inspect volume: %w", err)
+		}
+		if _, err := dockerClient.VolumeCreate(ctx, volume.CreateOptions{Name: name}); err != nil {
+			return fmt.Errorf("failed to create volume: %w", err)
+		}
+		return nil
+	}
+
+	if mode != RestoreModeRecreate {
+		return nil
+	}
+
+	users, err := docker.GetContainersUsingVolume(ctx, dockerClient, name)
+	if err != nil {
+		return err
+	}
+	if len(users) > 0 {
+		return fmt.Errorf("%w: volume is used by %d container(s); use overwrite mode or remove them first", ErrInvalidVolumeBackup, len(users))
+	}
+
+	if err := dockerClient.VolumeRemove(ctx, name, false); err != nil {
+		return fmt.Errorf("failed to remove volume: %w", err)
+	}
+	opts := volume.CreateOptions{
+		Name:       name,
+		Driver:     existing.Driver,
+		DriverOpts: existing.Options,
+		Labels:     existing.Labels,
+	}
+	if _, err := dockerClient.VolumeCreate(ctx, opts); err != nil {
+		return fmt.Errorf("failed to recreate volume: %w", err)
+	}
+	docker.InvalidateVolumeUsageCache()
+	return nil
+}
+
+func downloadVerifiedArchive(ctx context.Context, store backupStore, backup *models.VolumeBackup) (*os.File, error) {
+	src, err := store.Open(ctx, backup.Location)
+	if err != nil {
+		return nil, err
+	}
+	defer src.Close()
+
+	tmp, err := os.CreateTemp("", "arcane-volume-restore-*.tar.gz")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary archive: %w", err)
+	}
+	fail := func(err error) (*os.File, error) {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return nil, err
+	}
+
+	hasher := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(tmp, hasher), src); err != nil {
+		return fail(fmt.Errorf("failed to download backup: %w", err))
+	}
+	if backup.Checksum != "" && hex.EncodeToString(hasher.Sum(nil)) != backup.Checksum {
+		return fail(fmt.Errorf("backup checksum mismatch; the archive may be corrupted"))
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return fail(fmt.Errorf("failed to rewind backup archive: %w", err))
+	}
+	return tmp, nil
+}
+
+// createHelperContainer creates, but does not start, a container with the volume mounted
+// at /volume. The archive API works on created containers, so cmd is only needed when the
+// helper has to run something.
+func (s *VolumeBackupService) createHelperContainer(ctx context.Context, dockerClient *client.Client, volumeName string, readOnly bool, cmd []string) (string, func(), error) {
+	helperImage := s.settingsService.GetStringSetting(ctx, "backupHelperImage", "busybox:stable")
+
+	exists, err := s.imageService.ImageExistsLocally(ctx, helperImage)
+	if err != nil {
+		return "", nil, err
+	}
+	if !exists {
+		if err := s.imageService.PullImage(ctx, helperImage, io.Discard, systemUser, nil); err != nil {
+			return "", nil, fmt.Errorf("failed to pull backup helper image: %w", err)
+		}
+	}
+
+	if cmd == nil {
+		cmd = []string{"true"}
+	}
+	resp, err := dockerClient.ContainerCreate(ctx,
+		&container.Config{
+			Image:  helperImage,
+			Cmd:    cmd,
+			Labels: map[string]string{backupHelperLabel: "true"},
+		},
+		&container.HostConfig{
+			Mounts: []mount.Mount{{
+				Type:     mount.TypeVolume,
+				Source:   volumeName,
+				Target:   backupVolumeMountPath,
+				ReadOnly: readOnly,
+			}},
+		},
+		nil, nil, "")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create backup helper container: %w", err)
+	}
+
+	cleanup := func() {
+		if err := dockerClient.ContainerRemove(context.WithoutCancel(ctx), resp.ID, container.RemoveOptions{Force: true}); err != nil {
+			slog.WarnContext(ctx, "failed to remove backup helper container", "container", resp.ID, "error", err)
+		}
+	}
+	return resp.ID, cleanup, nil
+}
+
+func runHelperToCompletion(ctx context.Context, dockerClient *client.Client, containerID string) error {
+	waitCh, errCh := dockerClient.ContainerWait(ctx, containerID, container.WaitConditionNextExit)
+	if err := dockerClient.ContainerStart(ctx, containerID, container.StartOptions{}); err != nil {
+		return err
+	}
+	select {
+	case res := <-waitCh:
+		if res.StatusCode != 0 {
+			return fmt.Errorf("helper exited with status %d", res.StatusCode)
+		}
+		return nil
+	case err := <-errCh:
+		return err
+	}
+}
+
+// stopVolumeContainers stops the running containers that mount the volume and returns a
+// function that starts them again. The function is safe to call even when stopping failed.
+func stopVolumeContainers(ctx context.Context, dockerClient *client.Client, volumeName string) (func(), error) {
+	var stopped []string
+	restart := func() {
+		for _, id := range stopped {
+			if err := dockerClient.ContainerStart(context.WithoutCancel(ctx), id, container.StartOptions{}); err != nil {
+				slog.WarnContext(ctx, "failed to restart container after volume backup", "container", id, "error", err)
+			}
+		}
+	}
+
+	ids, err := docker.GetContainersUsingVolume(ctx, dockerClient, volumeName)
+	if err != nil {
+		return restart, err
+	}
+	for _, id := range ids {
+		inspect, err := dockerClient.ContainerInspect(ctx, id)
+		if err != nil {
+			return restart, fmt.Errorf("failed to inspect container %s: %w", id, err)
+		}
+		if inspect.State == nil || !inspect.State.Running {
+			continue
+		}
+		if inspect.Config != nil && inspect.Config.Labels[backupHelperLabel] == "true" {
+			continue
+		}
+		if err := dockerClient.ContainerStop(ctx, id, container.StopOptions{}); err != nil {
+			return restart, fmt.Errorf("failed to stop container %s: %w", id, err)
+		}
+		stopped = append(stopped, id)
+	}
+	return restart, nil
+}
+
+func (s *VolumeBackupService) storeForTarget(ctx context.Context, targetID *string) (backupStore, models.BackupTargetType, error) {
+	if targetID == nil {
+		return s.defaultLocalStore(ctx), models.BackupTargetLocal, nil
+	}
+	target, err := s.GetTarget(ctx, *targetID)
+	if err != nil {
+		return nil, "", err
+	}
+	store, err := s.storeFor(ctx, target)
+	if err != nil {
+		return nil, "", err
+	}
+	return store, target.Type, nil
+}
+
+func (s *VolumeBackupService) storeForBackup(ctx context.Context, backup *models.VolumeBackup) (backupStore, error) {
+	if backup.TargetID == nil {
+		return s.defaultLocalStore(ctx), nil
+	}
+	target, err := s.GetTarget(ctx, *backup.TargetID)
+	if err != nil {
+		return nil, err
+	}
+	return s.storeFor(ctx, target)
+}
+
+func (s *VolumeBackupService) storeFor(ctx context.Context, target *models.BackupTarget) (backupStore, error) {
+	if target.Type == models.BackupTargetS3 {
+		return newS3BackupStore(target)
+	}
+	if target.Path == "" {
+		return s.defaultLocalStore(ctx), nil
+	}
+	// Targets saved before paths were restricted, or whose directory was since dropped
+	// from the allowed paths, are refused rather than written to.
+	if err := s.checkLocalBackupPath(ctx, target.Path); err != nil {
+		return nil, err
+	}
+	return &localBackupStore{dir: target.Path}, nil
+}
+
+func (s *VolumeBackupService) defaultLocalStore(ctx context.Context) backupStore {
+	return &localBackupStore{dir: s.backupDirectory(ctx)}
+}
+
+func (s *VolumeBackupService) backupDirectory(ctx context.Context) string {
+	return s.settingsService.GetStringSetting(ctx, "backupDirectory", "data/backups")
+}
+
+func (s *VolumeBackupService) acquire(volumeName string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.busy[volumeName]; ok {
+		return false
+	}
+	s.busy[volumeName] = struct{}{}
+	return true
+}
+
+func (s *VolumeBackupService) release(volumeName string) {
+	s.mu.Lock()
+	delete(s.busy, volumeName)
+	s.mu.Unlock()
+}
+
+type countingWriter struct {
+	n int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.n += int64(len(p))
+	return len(p), nil
+}
+
+func emptyToNil(s *string) *string {
+	if s == nil || strings.TrimSpace(*s) == "" {
+		return nil
+	}
+	v := strings.TrimSpace(*s)
+	return &v
+}
@@ -0,0 +1,202 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/ofkm/arcane-backend/internal/dto"
+	"github.com/ofkm/arcane-backend/internal/models"
+	"github.com/ofkm/arcane-backend/internal/utils/pagination"
+)
+
+// newVolumeBackupTestService returns a service whose backup directory is a fresh
+// temporary directory and which records its audit entries in the same database.
+func newVolumeBackupTestService(t *testing.T) (*VolumeBackupService, *gorm.DB) {
+	t.Helper()
+	ctx := context.Background()
+	audit := setupAuditService(t)
+	require.NoError(t, audit.db.AutoMigrate(&models.BackupTarget{}, &models.VolumeBackupSchedule{}, &models.VolumeBackup{}))
+	settings, err := NewSettingsService(ctx, audit.db, nil)
+	require.NoError(t, err)
+	require.NoError(t, settings.SetStringSetting(ctx, "backupDirectory", t.TempDir()))
+	return NewVolumeBackupService(audit.db, nil, nil, settings, nil, audit), audit.db.DB
+}
+
+func TestScheduleIsDue(t *testing.T) {
+	created := time.Date(2025, 1, 1, 10, 30, 0, 0, time.Local)
+	schedule := models.VolumeBackupSchedule{CronExpression: "0 * * * *"}
+	schedule.CreatedAt = created
+
+	require.False(t, scheduleIsDue(schedule, created.Add(10*time.Minute)))
+	require.True(t, scheduleIsDue(schedule, created.Add(30*time.Minute)))
+
+	lastRun := created.Add(30 * time.Minute)
+	schedule.LastRunAt = &lastRun
+	require.False(t, scheduleIsDue(schedule, lastRun.Add(59*time.Minute)))
+	require.True(t, scheduleIsDue(schedule, lastRun.Add(time.Hour)))
+
+	schedule.CronExpression = "not a cron"
+	require.False(t, scheduleIsDue(schedule, lastRun.Add(24*time.Hour)))
+}
+
+func TestVolumeBackupService_CreateScheduleValidates(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := newVolumeBackupTestService(t)
+
+	_, err := svc.CreateSchedule(ctx, dto.CreateVolumeBackupScheduleDto{VolumeName: "data", CronExpression: "every day"})
+	require.ErrorIs(t, err, ErrInvalidVolumeBackup)
+
+	missing := "nope"
+	_, err = svc.CreateSchedule(ctx, dto.CreateVolumeBackupScheduleDto{VolumeName: "data", CronExpression: "@daily", TargetID: &missing})
+	require.ErrorIs(t, err, ErrBackupTargetNotFound)
+
+	schedule, err := svc.CreateSchedule(ctx, dto.CreateVolumeBackupScheduleDto{VolumeName: "data", CronExpression: "@daily", RetentionCount: 3})
+	require.NoError(t, err)
+	require.True(t, schedule.Enabled)
+	require.Nil(t, schedule.TargetID)
+}
+
+func TestVolumeBackupService_ApplyRetention(t *testing.T) {
+	ctx := context.Background()
+	svc, gdb := newVolumeBackupTestService(t)
+
+	dir := filepath.Join(svc.backupDirectory(ctx), "archives")
+	require.NoError(t, os.MkdirAll(dir, 0o755))
+	target, err := svc.CreateTarget(ctx, dto.CreateBackupTargetDto{Name: "local", Type: "local", Path: dir})
+	require.NoError(t, err)
+
+	schedule, err := svc.CreateSchedule(ctx, dto.CreateVolumeBackupScheduleDto{
+		VolumeName:     "data",
+		CronExpression: "@daily",
+		TargetID:       &target.ID,
+		RetentionCount: 2,
+		RetentionDays:  10,
+	})
+	require.NoError(t, err)
+
+	now := time.Now()
+	makeBackup := func(age time.Duration, status models.VolumeBackupStatus) *models.VolumeBackup {
+		location := filepath.Join(dir, fmt.Sprintf("data-%d.tar.gz", int(age.Hours())))
+		require.NoError(t, os.WriteFile(location, []byte("archive"), 0o600))
+		b := &models.VolumeBackup{
+			VolumeName: "data",
+			ScheduleID: &schedule.ID,
+			TargetID:   &target.ID,
+			TargetType: models.BackupTargetLocal,
+			Location:   location,
+			Status:     status,
+			StartedAt:  now.Add(-age),
+		}
+		require.NoError(t, gdb.Create(b).Error)
+		return b
+	}
+
+	newest := makeBackup(time.Hour, models.VolumeBackupStatusSuccess)
+	failed := makeBackup(2*time.Hour, models.VolumeBackupStatusFailed)
+	second := makeBackup(24*time.Hour, models.VolumeBackupStatusSuccess)
+	third := makeBackup(48*time.Hour, models.VolumeBackupStatusSuccess)
+
+	require.NoError(t, svc.applyRetention(ctx, *schedule, now))
+
+	backups, err := svc.ListBackups(ctx, "data")
+	require.NoError(t, err)
+	ids := make([]string, 0, len(backups))
+	for _, b := range backups {
+		ids = append(ids, b.ID)
+	}
+	require.ElementsMatch(t, []string{newest.ID, failed.ID, second.ID}, ids)
+
+	_, err = os.Stat(third.Location)
+	require.True(t, os.IsNotExist(err))
+	_, err = os.Stat(second.Location)
+	require.NoError(t, err)
+
+	// Age-based pruning applies even when the count limit is not reached.
+	schedule.RetentionCount = 0
+	require.NoError(t, svc.applyRetention(ctx, *schedule, now.AddDate(0, 0, 9).Add(12*time.Hour)))
+	backups, err = svc.ListBackups(ctx, "data")
+	require.NoError(t, err)
+	require.Len(t, backups, 2)
+}
+
+func TestVolumeBackupService_DeleteTargetInUse(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := newVolumeBackupTestService(t)
+
+	target, err := svc.CreateTarget(ctx, dto.CreateBackupTargetDto{Name: "local", Type: "local"})
+	require.NoError(t, err)
+	_, err = svc.CreateSchedule(ctx, dto.CreateVolumeBackupScheduleDto{VolumeName: "data", CronExpression: "@hourly", TargetID: &target.ID})
+	require.NoError(t, err)
+
+	require.ErrorIs(t, svc.DeleteTarget(ctx, target.ID), ErrBackupTargetInUse)
+
+	_, err = svc.CreateTarget(ctx, dto.CreateBackupTargetDto{Name: "bucket", Type: "s3"})
+	require.ErrorIs(t, err, ErrInvalidVolumeBackup)
+}
+
+func TestVolumeBackupService_LocalTargetPathMustBeAllowed(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := newVolumeBackupTestService(t)
+	root := svc.backupDirectory(ctx)
+
+	_, err := svc.CreateTarget(ctx, dto.CreateBackupTargetDto{Name: "etc", Type: "local", Path: "/etc"})
+	require.ErrorIs(t, err, ErrInvalidVolumeBackup)
+	_, err = svc.CreateTarget(ctx, dto.CreateBackupTargetDto{Name: "dotdot", Type: "local", Path: filepath.Join(root, "..", "elsewhere")})
+	require.ErrorIs(t, err, ErrInvalidVolumeBackup)
+
+	outside := t.TempDir()
+	require.NoError(t, os.Symlink(outside, filepath.Join(root, "escape")))
+	_, err = svc.CreateTarget(ctx, dto.CreateBackupTargetDto{Name: "link", Type: "local", Path: filepath.Join(root, "escape", "nested")})
+	require.ErrorIs(t, err, ErrInvalidVolumeBackup, "a symlink inside the backup directory must not lead out of it")
+
+	_, err = svc.CreateTarget(ctx, dto.CreateBackupTargetDto{Name: "nested", Type: "local", Path: filepath.Join(root, "nightly")})
+	require.NoError(t, err)
+
+	require.NoError(t, svc.settingsService.SetStringSetting(ctx, "backupAllowedPaths", "relative/dir, "+outside))
+	target, err := svc.CreateTarget(ctx, dto.CreateBackupTargetDto{Name: "nas", Type: "local", Path: filepath.Join(outside, "nas")})
+	require.NoError(t, err)
+
+	// Removing the directory from the allowlist stops existing targets from being used.
+	require.NoError(t, svc.settingsService.SetStringSetting(ctx, "backupAllowedPaths", ""))
+	_, err = svc.storeFor(ctx, target)
+	require.ErrorIs(t, err, ErrInvalidVolumeBackup)
+}
+
+func TestVolumeBackupService_AuditsTargetAndScheduleChanges(t *testing.T) {
+	ctx := auditTestContext()
+	svc, _ := newVolumeBackupTestService(t)
+
+	target, err := svc.CreateTarget(ctx, dto.CreateBackupTargetDto{Name: "local", Type: "local"})
+	require.NoError(t, err)
+	renamed := "nightly"
+	_, err = svc.UpdateTarget(ctx, target.ID, dto.UpdateBackupTargetDto{Name: &renamed})
+	require.NoError(t, err)
+
+	schedule, err := svc.CreateSchedule(ctx, dto.CreateVolumeBackupScheduleDto{VolumeName: "data", CronExpression: "@daily", TargetID: &target.ID})
+	require.NoError(t, err)
+	cron := "@hourly"
+	_, err = svc.UpdateSchedule(ctx, schedule.ID, dto.UpdateVolumeBackupScheduleDto{CronExpression: &cron})
+	require.NoError(t, err)
+	require.NoError(t, svc.DeleteSchedule(ctx, schedule.ID))
+	require.NoError(t, svc.DeleteTarget(ctx, target.ID))
+
+	entries, _, err := svc.audit.ListAuditLogsPaginated(ctx, pagination.QueryParams{})
+	require.NoError(t, err)
+	byAction := map[string]models.AuditLog{}
+	for _, e := range entries {
+		byAction[e.Action] = e
+	}
+	require.Len(t, byAction, 6)
+
+	require.Equal(t, map[string]any{"before": "local", "after": "nightly"}, byAction["backup_target.update"].Changes["name"])
+	require.Equal(t, "nightly", *byAction["backup_target.delete"].TargetName)
+	require.Equal(t, map[string]any{"before": "@daily", "after": "@hourly"}, byAction["backup_schedule.update"].Changes["cronExpression"])
+	require.Equal(t, "data", *byAction["backup_schedule.delete"].TargetName)
+}
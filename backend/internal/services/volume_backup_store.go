@@ -0,0 +1,119 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/ofkm/arcane-backend/internal/models"
+	"github.com/ofkm/arcane-backend/internal/utils"
+	"github.com/ofkm/arcane-backend/internal/utils/s3"
+)
+
+// backupStore persists finished archives. Keys are slash-separated and relative; the
+// returned location is what gets recorded in the backup history.
+type backupStore interface {
+	Put(ctx context.Context, key string, file *os.File, size int64, checksum string) (string, error)
+	Open(ctx context.Context, location string) (io.ReadCloser, error)
+	Delete(ctx context.Context, location string) error
+}
+
+type localBackupStore struct {
+	dir string
+}
+
+func (l *localBackupStore) Put(_ context.Context, key string, file *os.File, _ int64, _ string) (string, error) {
+	dest := filepath.Join(l.dir, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return "", fmt.Errorf("failed to create backup directory: %w", err)
+	}
+
+	// Rename is cheap when the temp file is on the same filesystem; fall back to a copy.
+	if err := os.Rename(file.Name(), dest); err == nil {
+		return dest, nil
+	}
+
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return "", fmt.Errorf("failed to rewind backup archive: %w", err)
+	}
+	out, err := os.OpenFile(dest, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return "", fmt.Errorf("failed to create backup file: %w", err)
+	}
+	if _, err := io.Copy(out, file); err != nil {
+		_ = out.Close()
+		_ = os.Remove(dest)
+		return "", fmt.Errorf("failed to write backup file: %w", err)
+	}
+	if err := out.Close(); err != nil {
+		return "", fmt.Errorf("failed to write backup file: %w", err)
+	}
+	return dest, nil
+}
+
+func (l *localBackupStore) Open(_ context.Context, location string) (io.ReadCloser, error) {
+	f, err := os.Open(location)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open backup file: %w", err)
+	}
+	return f, nil
+}
+
+func (l *localBackupStore) Delete(_ context.Context, location string) error {
+	if err := os.Remove(location); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete backup file: %w", err)
+	}
+	return nil
+}
+
+type s3BackupStore struct {
+	client *s3.Client
+	prefix string
+}
+
+func (b *s3BackupStore) Put(ctx context.Context, key string, file *os.File, size int64, checksum string) (string, error) {
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return "", fmt.Errorf("failed to rewind backup archive: %w", err)
+	}
+	objectKey := path.Join(b.prefix, key)
+	if err := b.client.PutObject(ctx, objectKey, file, size, checksum); err != nil {
+		return "", fmt.Errorf("failed to upload backup: %w", err)
+	}
+	return objectKey, nil
+}
+
+func (b *s3BackupStore) Open(ctx context.Context, location string) (io.ReadCloser, error) {
+	return b.client.GetObject(ctx, location)
+}
+
+func (b *s3BackupStore) Delete(ctx context.Context, location string) error {
+	return b.client.DeleteObject(ctx, location)
+}
+
+func newS3BackupStore(target *models.BackupTarget) (*s3BackupStore, error) {
+	secret := ""
+	if target.SecretAccessKey != "" {
+		decrypted, err := utils.Decrypt(target.SecretAccessKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt secret access key: %w", err)
+		}
+		secret = decrypted
+	}
+
+	client, err := s3.NewClient(s3.Config{
+		Endpoint:        target.Endpoint,
+		Region:          target.Region,
+		Bucket:          target.Bucket,
+		AccessKeyID:     target.AccessKeyID,
+		SecretAccessKey: secret,
+		PathStyle:       target.PathStyle,
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &s3BackupStore{client: client, prefix: strings.Trim(target.Prefix, "/")}, nil
+}
@@ -0,0 +1,200 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Auth holds the plaintext credentials used for a single git invocation. Credentials are
+// passed per command and never written to the repository configuration.
+type Auth struct {
+	Username string
+	Token    string
+	SSHKey   string
+}
+
+// Clone performs a shallow clone of ref (a branch or tag; empty for the remote default)
+// into dir, which must be empty or not exist.
+func Clone(ctx context.Context, url, ref, dir string, auth Auth) error {
+	args := []string{"clone", "--depth", "1", "--single-branch"}
+	if ref != "" {
+		args = append(args, "--branch", ref)
+	}
+	args = append(args, "--end-of-options", url, dir)
+	_, err := run(ctx, "", auth, args...)
+	return err
+}
+
+// Fetch fetches ref from origin and returns the commit it points to without touching the
+// working tree.
+func Fetch(ctx context.Context, dir, ref string, auth Auth) (string, error) {
+	if ref == "" {
+		ref = "HEAD"
+	}
+	if _, err := run(ctx, dir, auth, "fetch", "--depth", "1", "--force", "--end-of-options", "origin", ref); err != nil {
+		return "", err
+	}
+	return RevParse(ctx, dir, "FETCH_HEAD")
+}
+
+// ValidateURL accepts https and ssh repository URLs, including the scp-like
+// user@host:path form. Local paths, file:// and transport helpers such as ext:: are
+// refused because they let the caller run commands or read files on this host.
+func ValidateURL(raw string) error {
+	if strings.Contains(raw, "::") {
+		return errors.New("transport helpers are not allowed")
+	}
+	if u, err := url.Parse(raw); err == nil && u.Scheme != "" && strings.Contains(raw, "://") {
+		switch u.Scheme {
+		case "https", "ssh":
+		default:
+			return fmt.Errorf("unsupported scheme %q, use https or ssh", u.Scheme)
+		}
+		if u.Hostname() == "" || strings.HasPrefix(u.Hostname(), "-") || strings.HasPrefix(u.User.Username(), "-") {
+			return errors.New("invalid repository host")
+		}
+		return nil
+	}
+	// scp-like syntax: [user@]host:path, where the host part has no slash.
+	hostPart, path, ok := strings.Cut(raw, ":")
+	if !ok || path == "" || hostPart == "" || strings.Contains(hostPart, "/") || strings.HasPrefix(hostPart, "-") {
+		return errors.New("repository URL must use https or ssh")
+	}
+	return nil
+}
+
+// ValidateRef rejects refs that could be mistaken for options or that git itself
+// considers malformed. An empty ref selects the remote default and is valid.
+func ValidateRef(ctx context.Context, ref string) error {
+	if ref == "" {
+		return nil
+	}
+	if strings.HasPrefix(ref, "-") {
+		return errors.New("ref must not start with '-'")
+	}
+	if _, err := run(ctx, "", Auth{}, "check-ref-format", "--allow-onelevel", ref); err != nil {
+		return fmt.Errorf("invalid ref %q", ref)
+	}
+	return nil
+}
+
+func Head(ctx context.Context, dir string) (string, error) {
+	return RevParse(ctx, dir, "HEAD")
+}
+
+func RevParse(ctx context.Context, dir, rev string) (string, error) {
+	out, err := run(ctx, dir, Auth{}, "rev-parse", "--verify", "--end-of-options", rev)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// ChangedFiles lists files that differ between two commits, limited to path when set.
+func ChangedFiles(ctx context.Context, dir, from, to, path string) ([]string, error) {
+	out, err := run(ctx, dir, Auth{}, diffArgs([]string{"diff", "--name-only"}, from, to, path)...)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, line := range strings.Split(out, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			files = append(files, line)
+		}
+	}
+	return files, nil
+}
+
+// Diff returns the unified diff between two commits, limited to path when set.
+func Diff(ctx context.Context, dir, from, to, path string) (string, error) {
+	return run(ctx, dir, Auth{}, diffArgs([]string{"diff", "--no-color"}, from, to, path)...)
+}
+
+// ResetHard moves the checkout to commit, discarding local modifications.
+func ResetHard(ctx context.Context, dir, commit string) error {
+	// Older git releases reject --end-of-options in reset, so refuse option-like
+	// commits here instead.
+	if strings.HasPrefix(commit, "-") {
+		return fmt.Errorf("invalid commit %q", commit)
+	}
+	_, err := run(ctx, dir, Auth{}, "reset", "--hard", commit, "--")
+	return err
+}
+
+func diffArgs(base []string, from, to, path string) []string {
+	args := append(base, "--end-of-options", from, to)
+	if path != "" && path != "." {
+		args = append(args, "--", path)
+	}
+	return args
+}
+
+func run(ctx context.Context, dir string, auth Auth, args ...string) (string, error) {
+	env := append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+
+	if auth.Token != "" {
+		username := auth.Username
+		if username == "" {
+			username = "git"
+		}
+		// Passed through the environment rather than -c so the token does not show up in
+		// the process list.
+		basic := base64.StdEncoding.EncodeToString([]byte(username + ":" + auth.Token))
+		env = append(env,
+			"GIT_CONFIG_COUNT=1",
+			"GIT_CONFIG_KEY_0=http.extraHeader",
+			"GIT_CONFIG_VALUE_0=Authorization: Basic "+basic,
+		)
+	}
+
+	if auth.SSHKey != "" {
+		keyFile, cleanup, err := writeSSHKey(auth.SSHKey)
+		if err != nil {
+			return "", err
+		}
+		defer cleanup()
+		env = append(env, "GIT_SSH_COMMAND=ssh -i "+keyFile+" -o IdentitiesOnly=yes -o StrictHostKeyChecking=accept-new -o BatchMode=yes")
+	}
+
+	cmd := exec.CommandContext(ctx, "git", args...) // #nosec G204: arguments are passed without a shell
+	cmd.Dir = dir
+	cmd.Env = env
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		return "", fmt.Errorf("git %s: %s", args[0], msg)
+	}
+	return stdout.String(), nil
+}
+
+func writeSSHKey(key string) (string, func(), error) {
+	tmpDir, err := os.MkdirTemp("", "arcane-git-")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create temp dir for ssh key: %w", err)
+	}
+	cleanup := func() { _ = os.RemoveAll(tmpDir) }
+
+	keyFile := filepath.Join(tmpDir, "id")
+	if !strings.HasSuffix(key, "\n") {
+		key += "\n"
+	}
+	if err := os.WriteFile(keyFile, []byte(key), 0o600); err != nil {
+		cleanup()
+		return "", nil, fmt.Errorf("failed to write ssh key: %w", err)
+	}
+	return keyFile, cleanup, nil
+}
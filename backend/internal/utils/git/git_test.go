@@ -0,0 +1,100 @@
+package git
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func commitFile(t *testing.T, repo, name, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(repo, name)), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(repo, name), []byte(content), 0o600))
+	for _, args := range [][]string{
+		{"add", "-A"},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "-m", "update " + name},
+	} {
+		out, err := exec.Command("git", append([]string{"-C", repo}, args...)...).CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+}
+
+func TestCloneFetchAndDiff(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	ctx := context.Background()
+
+	origin := t.TempDir()
+	out, err := exec.Command("git", "init", "-q", "-b", "main", origin).CombinedOutput()
+	require.NoError(t, err, string(out))
+	commitFile(t, origin, "stack/compose.yaml", "services: {}\n")
+	commitFile(t, origin, "README.md", "hello\n")
+
+	checkout := filepath.Join(t.TempDir(), "checkout")
+	require.NoError(t, Clone(ctx, "file://"+origin, "main", checkout, Auth{}))
+
+	head, err := Head(ctx, checkout)
+	require.NoError(t, err)
+
+	fetched, err := Fetch(ctx, checkout, "main", Auth{})
+	require.NoError(t, err)
+	require.Equal(t, head, fetched, "nothing new upstream")
+
+	commitFile(t, origin, "README.md", "changed\n")
+	commitFile(t, origin, "stack/compose.yaml", "services:\n  web:\n    image: nginx\n")
+
+	fetched, err = Fetch(ctx, checkout, "main", Auth{})
+	require.NoError(t, err)
+	require.NotEqual(t, head, fetched)
+
+	files, err := ChangedFiles(ctx, checkout, head, fetched, "stack")
+	require.NoError(t, err)
+	require.Equal(t, []string{"stack/compose.yaml"}, files)
+
+	diff, err := Diff(ctx, checkout, head, fetched, "stack")
+	require.NoError(t, err)
+	require.Contains(t, diff, "+    image: nginx")
+
+	require.NoError(t, ResetHard(ctx, checkout, fetched))
+	content, err := os.ReadFile(filepath.Join(checkout, "stack", "compose.yaml"))
+	require.NoError(t, err)
+	require.Contains(t, string(content), "nginx")
+}
+
+func TestValidateURL(t *testing.T) {
+	for _, u := range []string{
+		"https://github.com/getarcaneapp/arcane.git",
+		"ssh://git@github.com/getarcaneapp/arcane.git",
+		"git@github.com:getarcaneapp/arcane.git",
+	} {
+		require.NoError(t, ValidateURL(u), u)
+	}
+	for _, u := range []string{
+		"file:///etc",
+		"/srv/repo",
+		"ext::sh -c touch% /tmp/pwned",
+		"http://example.com/repo.git",
+		"ssh://-oProxyCommand=touch/repo",
+		"-oProxyCommand=touch:repo",
+	} {
+		require.Error(t, ValidateURL(u), u)
+	}
+}
+
+func TestValidateRef(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	ctx := context.Background()
+	for _, ref := range []string{"", "main", "v1.2.3", "release/2025"} {
+		require.NoError(t, ValidateRef(ctx, ref), ref)
+	}
+	for _, ref := range []string{"--upload-pack=touch /tmp/pwned", "-b", "main..dev", "bad ref", "a~1"} {
+		require.Error(t, ValidateRef(ctx, ref), ref)
+	}
+}
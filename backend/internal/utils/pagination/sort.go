@@ -38,3 +38,13 @@ func reverSortFn[T any](fn SortOption[T]) SortOption[T] {
 		return -1 * fn(a, b)
 	}
 }
+
+// WithDefaultSort returns params sorted by field in the given order when the request
+// did not ask for a sort of its own.
+func (p QueryParams) WithDefaultSort(field string, order SortOrder) QueryParams {
+	if p.sort == "" {
+		p.sort = field
+		p.order = order
+	}
+	return p
+}
@@ -0,0 +1,161 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// OCI annotation / label keys describing where an image was built from.
+const (
+	AnnotationSource   = "org.opencontainers.image.source"
+	AnnotationVersion  = "org.opencontainers.image.version"
+	AnnotationRevision = "org.opencontainers.image.revision"
+)
+
+// ImageMetadata is the provenance information an image publishes about itself.
+type ImageMetadata struct {
+	Source   string
+	Version  string
+	Revision string
+}
+
+func (m *ImageMetadata) complete() bool {
+	return m.Source != "" && m.Version != "" && m.Revision != ""
+}
+
+// fill sets every empty field from values, leaving fields that are already known alone.
+func (m *ImageMetadata) fill(values map[string]string) {
+	if m.Source == "" {
+		m.Source = values[AnnotationSource]
+	}
+	if m.Version == "" {
+		m.Version = values[AnnotationVersion]
+	}
+	if m.Revision == "" {
+		m.Revision = values[AnnotationRevision]
+	}
+}
+
+type manifestDescriptor struct {
+	MediaType string `json:"mediaType"`
+	Digest    string `json:"digest"`
+	Platform  *struct {
+		Architecture string `json:"architecture"`
+		OS           string `json:"os"`
+	} `json:"platform,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+type manifestDocument struct {
+	MediaType   string               `json:"mediaType"`
+	Manifests   []manifestDescriptor `json:"manifests,omitempty"`
+	Config      *manifestDescriptor  `json:"config,omitempty"`
+	Annotations map[string]string    `json:"annotations,omitempty"`
+}
+
+var manifestAcceptTypes = []string{
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+}
+
+// maxManifestBytes bounds manifest and config downloads; both are small JSON documents.
+const maxManifestBytes = 4 << 20
+
+// GetImageMetadata reads the OCI source/version/revision for reference (a tag or digest).
+// Manifest annotations are preferred; missing values fall back to the image config labels.
+// Multi-platform images are resolved to their linux/amd64 (or first) image manifest.
+func (c *Client) GetImageMetadata(ctx context.Context, registry, repository, reference, token string) (*ImageMetadata, error) {
+	meta := &ImageMetadata{}
+
+	var doc manifestDocument
+	if err := c.getRegistryJSON(ctx, fmt.Sprintf("%s/v2/%s/manifests/%s", c.GetRegistryURL(registry), repository, reference), token, manifestAcceptTypes, &doc); err != nil {
+		return nil, fmt.Errorf("manifest: %w", err)
+	}
+	meta.fill(doc.Annotations)
+
+	if len(doc.Manifests) > 0 {
+		desc := pickPlatformManifest(doc.Manifests)
+		if desc == nil {
+			return meta, nil
+		}
+		meta.fill(desc.Annotations)
+		doc = manifestDocument{}
+		if err := c.getRegistryJSON(ctx, fmt.Sprintf("%s/v2/%s/manifests/%s", c.GetRegistryURL(registry), repository, desc.Digest), token, manifestAcceptTypes, &doc); err != nil {
+			return meta, fmt.Errorf("platform manifest: %w", err)
+		}
+		meta.fill(doc.Annotations)
+	}
+
+	if meta.complete() || doc.Config == nil || doc.Config.Digest == "" {
+		return meta, nil
+	}
+
+	var cfg struct {
+		Config struct {
+			Labels map[string]string `json:"Labels"`
+		} `json:"config"`
+	}
+	if err := c.getRegistryJSON(ctx, fmt.Sprintf("%s/v2/%s/blobs/%s", c.GetRegistryURL(registry), repository, doc.Config.Digest), token, nil, &cfg); err != nil {
+		return meta, fmt.Errorf("config blob: %w", err)
+	}
+	meta.fill(cfg.Config.Labels)
+	return meta, nil
+}
+
+// pickPlatformManifest prefers linux/amd64 and skips attestation manifests.
+func pickPlatformManifest(manifests []manifestDescriptor) *manifestDescriptor {
+	var first *manifestDescriptor
+	for i := range manifests {
+		m := &manifests[i]
+		if m.Platform == nil || m.Platform.OS == "unknown" {
+			continue
+		}
+		if m.Platform.OS == "linux" && m.Platform.Architecture == "amd64" {
+			return m
+		}
+		if first == nil {
+			first = m
+		}
+	}
+	return first
+}
+
+func (c *Client) getRegistryJSON(ctx context.Context, url, token string, accept []string, out any) error {
+	reqCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(reqCtx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	for _, a := range accept {
+		req.Header.Add("Accept", a)
+	}
+	req.Header.Set("User-Agent", "Arcane")
+	if ah := buildAuthHeader(token); ah != "" {
+		req.Header.Set("Authorization", ah)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		if h := getHeaderCI(resp.Header, ChallengeHeader); h != "" {
+			return fmt.Errorf("unauthorized: %s", h)
+		}
+		return fmt.Errorf("request failed with status: 401")
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("request failed with status: %d", resp.StatusCode)
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, maxManifestBytes)).Decode(out)
+}
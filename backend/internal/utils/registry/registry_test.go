@@ -126,3 +126,60 @@ func TestGetImageTagsPagination(t *testing.T) {
 		t.Fatalf("tags %v", tags)
 	}
 }
+
+func TestGetImageTagsRelativeLink(t *testing.T) {
+	t.Parallel()
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.RawQuery {
+		case "":
+			w.Header().Set("Link", `</v2/org/repo/tags/list?last=b&n=2>; rel="next"`)
+			_, _ = w.Write([]byte(`{"tags":["a","b"]}`))
+		case "last=b&n=2":
+			_, _ = w.Write([]byte(`{"tags":["c"]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tags, err := NewClient().GetImageTags(ctx, server.URL, "org/repo", "")
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if strings.Join(tags, ",") != "a,b,c" {
+		t.Fatalf("tags %v", tags)
+	}
+}
+
+func TestGetImageMetadataFollowsIndexAndConfig(t *testing.T) {
+	t.Parallel()
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/org/app/manifests/1.2.0":
+			_, _ = w.Write([]byte(`{"mediaType":"application/vnd.oci.image.index.v1+json","manifests":[
+				{"digest":"sha256:att","platform":{"os":"unknown","architecture":"unknown"}},
+				{"digest":"sha256:arm","platform":{"os":"linux","architecture":"arm64"}},
+				{"digest":"sha256:amd","platform":{"os":"linux","architecture":"amd64"}}]}`))
+		case "/v2/org/app/manifests/sha256:amd":
+			_, _ = w.Write([]byte(`{"config":{"digest":"sha256:cfg"},"annotations":{"org.opencontainers.image.version":"1.2.0"}}`))
+		case "/v2/org/app/blobs/sha256:cfg":
+			_, _ = w.Write([]byte(`{"config":{"Labels":{"org.opencontainers.image.version":"ignored","org.opencontainers.image.source":"https://github.com/org/app","org.opencontainers.image.revision":"abc123"}}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	meta, err := NewClient().GetImageMetadata(context.Background(), server.URL, "org/app", "1.2.0", "")
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if meta.Version != "1.2.0" || meta.Source != "https://github.com/org/app" || meta.Revision != "abc123" {
+		t.Fatalf("metadata %+v", meta)
+	}
+}
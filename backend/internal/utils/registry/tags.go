@@ -5,15 +5,16 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
 	"strings"
 	"time"
 )
 
 func (c *Client) GetImageTags(ctx context.Context, registry, repository, token string) ([]string, error) {
-	url := fmt.Sprintf("%s/v2/%s/tags/list", c.GetRegistryURL(registry), repository)
+	tagsURL := fmt.Sprintf("%s/v2/%s/tags/list", c.GetRegistryURL(registry), repository)
 
 	var all []string
-	next := url
+	next := tagsURL
 	for next != "" {
 		reqCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
 		defer cancel()
@@ -56,7 +57,7 @@ func (c *Client) GetImageTags(ctx context.Context, registry, repository, token s
 				return
 			}
 			all = append(all, body.Tags...)
-			next = parseLinkHeader(resp.Header.Get("Link"))
+			next = resolveNextLink(resp.Request.URL, parseLinkHeader(resp.Header.Get("Link")))
 		}()
 		if err != nil {
 			return nil, err
@@ -65,6 +66,18 @@ func (c *Client) GetImageTags(ctx context.Context, registry, repository, token s
 	return all, nil
 }
 
+// resolveNextLink makes a relative pagination link (as returned by Docker Hub) absolute.
+func resolveNextLink(base *url.URL, link string) string {
+	if link == "" || base == nil {
+		return link
+	}
+	ref, err := url.Parse(link)
+	if err != nil {
+		return link
+	}
+	return base.ResolveReference(ref).String()
+}
+
 func parseLinkHeader(h string) string {
 	if h == "" {
 		return ""
@@ -0,0 +1,230 @@
+// Package releasenotes fetches the release matching an image version from the source
+// repository named in its OCI labels. GitHub and Gitea/Forgejo are supported.
+package releasenotes
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// ErrNotFound means the source has no release for the requested version.
+var ErrNotFound = errors.New("release not found")
+
+// ErrUnsupportedSource means the source URL does not point at a supported forge.
+var ErrUnsupportedSource = errors.New("unsupported release source")
+
+// MaxNotesLength caps the stored release body; long changelogs are truncated.
+const MaxNotesLength = 4000
+
+// defaultCacheSize bounds the cache; image labels are untrusted, so the set of
+// sources and versions looked up is not.
+const defaultCacheSize = 1024
+
+type Release struct {
+	TagName     string
+	Name        string
+	Body        string
+	URL         string
+	PublishedAt *time.Time
+}
+
+type cacheEntry struct {
+	release *Release
+	err     error
+	expires time.Time
+}
+
+// Client looks up releases and caches results, including misses, for cacheTTL so repeated
+// update checks do not exhaust anonymous API rate limits. At most cacheSize results are
+// kept; expired entries go first, then those closest to expiry.
+type Client struct {
+	http      *http.Client
+	cacheTTL  time.Duration
+	cacheSize int
+
+	// githubAPI is overridable in tests.
+	githubAPI string
+
+	mu    sync.Mutex
+	cache map[string]cacheEntry
+}
+
+func NewClient(httpClient *http.Client) *Client {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 15 * time.Second}
+	}
+	return &Client{
+		http:      httpClient,
+		cacheTTL:  6 * time.Hour,
+		cacheSize: defaultCacheSize,
+		githubAPI: "https://api.github.com",
+		cache:     map[string]cacheEntry{},
+	}
+}
+
+// Fetch returns the release for version from the repository at source, trying the version
+// both with and without a "v" prefix.
+func (c *Client) Fetch(ctx context.Context, source, version string) (*Release, error) {
+	version = strings.TrimSpace(version)
+	if version == "" {
+		return nil, ErrNotFound
+	}
+	key := source + "@" + version
+
+	c.mu.Lock()
+	if e, ok := c.cache[key]; ok && time.Now().Before(e.expires) {
+		c.mu.Unlock()
+		return e.release, e.err
+	}
+	c.mu.Unlock()
+
+	rel, err := c.fetch(ctx, source, version)
+	// Only cache definite answers; transient failures are retried on the next check.
+	if err == nil || errors.Is(err, ErrNotFound) || errors.Is(err, ErrUnsupportedSource) {
+		c.store(key, cacheEntry{release: rel, err: err, expires: time.Now().Add(c.cacheTTL)})
+	}
+	return rel, err
+}
+
+func (c *Client) store(key string, entry cacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.cache[key]; !ok && len(c.cache) >= c.cacheSize {
+		now := time.Now()
+		for k, e := range c.cache {
+			if !now.Before(e.expires) {
+				delete(c.cache, k)
+			}
+		}
+		for len(c.cache) >= c.cacheSize {
+			var oldest string
+			for k, e := range c.cache {
+				if oldest == "" || e.expires.Before(c.cache[oldest].expires) {
+					oldest = k
+				}
+			}
+			delete(c.cache, oldest)
+		}
+	}
+	c.cache[key] = entry
+}
+
+func (c *Client) fetch(ctx context.Context, source, version string) (*Release, error) {
+	host, owner, repo, err := parseSource(source)
+	if err != nil {
+		return nil, err
+	}
+
+	var base string
+	if host == "github.com" {
+		base = fmt.Sprintf("%s/repos/%s/%s/releases/tags/", c.githubAPI, owner, repo)
+	} else {
+		base = fmt.Sprintf("https://%s/api/v1/repos/%s/%s/releases/tags/", host, owner, repo)
+	}
+
+	for _, tag := range tagCandidates(version) {
+		rel, err := c.getRelease(ctx, base+url.PathEscape(tag))
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		return rel, err
+	}
+	return nil, ErrNotFound
+}
+
+func (c *Client) getRelease(ctx context.Context, endpoint string) (*Release, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "Arcane")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("release request failed with status: %d", resp.StatusCode)
+	}
+
+	var body struct {
+		TagName     string     `json:"tag_name"`
+		Name        string     `json:"name"`
+		Body        string     `json:"body"`
+		HTMLURL     string     `json:"html_url"`
+		PublishedAt *time.Time `json:"published_at"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&body); err != nil {
+		return nil, fmt.Errorf("decode release: %w", err)
+	}
+	return &Release{
+		TagName:     body.TagName,
+		Name:        body.Name,
+		Body:        truncate(strings.TrimSpace(body.Body), MaxNotesLength),
+		URL:         body.HTMLURL,
+		PublishedAt: body.PublishedAt,
+	}, nil
+}
+
+// parseSource accepts https URLs as well as the "git@host:owner/repo.git" form.
+func parseSource(source string) (host, owner, repo string, err error) {
+	s := strings.TrimSpace(source)
+	if rest, ok := strings.CutPrefix(s, "git@"); ok {
+		s = "https://" + strings.Replace(rest, ":", "/", 1)
+	}
+	if !strings.Contains(s, "://") {
+		s = "https://" + s
+	}
+	u, perr := url.Parse(s)
+	if perr != nil || u.Host == "" {
+		return "", "", "", fmt.Errorf("%w: %s", ErrUnsupportedSource, source)
+	}
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", "", fmt.Errorf("%w: %s", ErrUnsupportedSource, source)
+	}
+	host = strings.ToLower(u.Host)
+	// Image labels are untrusted; never let them aim requests at local or literal addresses.
+	if h := u.Hostname(); h == "localhost" || net.ParseIP(h) != nil || !strings.Contains(h, ".") {
+		return "", "", "", fmt.Errorf("%w: %s", ErrUnsupportedSource, source)
+	}
+	if strings.Contains(host, "gitlab") || host == "bitbucket.org" {
+		return "", "", "", fmt.Errorf("%w: %s", ErrUnsupportedSource, source)
+	}
+	return host, parts[0], strings.TrimSuffix(parts[1], ".git"), nil
+}
+
+func tagCandidates(version string) []string {
+	if rest, ok := strings.CutPrefix(version, "v"); ok && rest != "" {
+		return []string{version, rest}
+	}
+	return []string{version, "v" + version}
+}
+
+func truncate(s string, limit int) string {
+	if len(s) <= limit {
+		return s
+	}
+	s = s[:limit]
+	for !utf8.ValidString(s) {
+		s = s[:len(s)-1]
+	}
+	return s + "\n…"
+}
@@ -0,0 +1,84 @@
+package releasenotes
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSource(t *testing.T) {
+	host, owner, repo, err := parseSource("https://github.com/ofkm/arcane.git")
+	require.NoError(t, err)
+	require.Equal(t, []string{"github.com", "ofkm", "arcane"}, []string{host, owner, repo})
+
+	host, owner, repo, err = parseSource("git@codeberg.org:forgejo/forgejo.git")
+	require.NoError(t, err)
+	require.Equal(t, []string{"codeberg.org", "forgejo", "forgejo"}, []string{host, owner, repo})
+
+	_, _, _, err = parseSource("https://gitlab.com/group/project")
+	require.ErrorIs(t, err, ErrUnsupportedSource)
+	_, _, _, err = parseSource("http://10.0.0.5/owner/repo")
+	require.ErrorIs(t, err, ErrUnsupportedSource)
+	_, _, _, err = parseSource("https://example.com")
+	require.ErrorIs(t, err, ErrUnsupportedSource)
+}
+
+func TestFetchTriesPrefixedTagAndCaches(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		switch r.URL.Path {
+		case "/repos/acme/app/releases/tags/v1.5.0":
+			_, _ = w.Write([]byte(`{"tag_name":"v1.5.0","name":"1.5.0","body":"- fixed things","html_url":"https://github.com/acme/app/releases/tag/v1.5.0"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	c := NewClient(server.Client())
+	c.githubAPI = server.URL
+
+	rel, err := c.Fetch(context.Background(), "https://github.com/acme/app", "1.5.0")
+	require.NoError(t, err)
+	require.Equal(t, "v1.5.0", rel.TagName)
+	require.Equal(t, "- fixed things", rel.Body)
+	require.Equal(t, 2, calls)
+
+	_, err = c.Fetch(context.Background(), "https://github.com/acme/app", "1.5.0")
+	require.NoError(t, err)
+	require.Equal(t, 2, calls)
+
+	_, err = c.Fetch(context.Background(), "https://github.com/acme/app", "9.9.9")
+	require.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestCacheIsBounded(t *testing.T) {
+	c := NewClient(nil)
+	c.cacheSize = 2
+	now := time.Now()
+
+	c.store("expired", cacheEntry{expires: now.Add(-time.Minute)})
+	c.store("soon", cacheEntry{expires: now.Add(time.Minute)})
+	c.store("later", cacheEntry{expires: now.Add(time.Hour)})
+	require.Len(t, c.cache, 2)
+	require.NotContains(t, c.cache, "expired", "expired entries are dropped first")
+
+	c.store("latest", cacheEntry{expires: now.Add(2 * time.Hour)})
+	require.Len(t, c.cache, 2)
+	require.NotContains(t, c.cache, "soon", "then the entry closest to expiry")
+	require.Contains(t, c.cache, "later")
+
+	c.store("later", cacheEntry{expires: now.Add(3 * time.Hour)})
+	require.Len(t, c.cache, 2, "replacing an entry evicts nothing")
+	require.Contains(t, c.cache, "latest")
+}
+
+func TestTruncateKeepsValidUTF8(t *testing.T) {
+	out := truncate("ääää", 3)
+	require.Equal(t, "ä\n…", out)
+}
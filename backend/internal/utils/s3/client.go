@@ -0,0 +1,148 @@
+package s3
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+)
+
+// emptyPayloadHash is the SHA-256 of an empty body, used when signing GET and DELETE.
+const emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+// Config describes an S3-compatible bucket. Endpoint defaults to AWS for Region;
+// PathStyle should be set for most self-hosted services such as MinIO.
+type Config struct {
+	Endpoint        string
+	Region          string
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+	PathStyle       bool
+}
+
+// Client is a minimal S3 client covering the object operations needed for backups.
+type Client struct {
+	cfg        Config
+	signer     *v4.Signer
+	httpClient *http.Client
+}
+
+func NewClient(cfg Config, httpClient *http.Client) (*Client, error) {
+	if strings.TrimSpace(cfg.Bucket) == "" {
+		return nil, fmt.Errorf("s3 bucket is required")
+	}
+	if cfg.Region == "" {
+		cfg.Region = "us-east-1"
+	}
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = "https://s3." + cfg.Region + ".amazonaws.com"
+	}
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 30 * time.Minute}
+	}
+
+	signer := v4.NewSigner(func(o *v4.SignerOptions) {
+		// S3 expects object keys to be escaped exactly once.
+		o.DisableURIPathEscaping = true
+	})
+	return &Client{cfg: cfg, signer: signer, httpClient: httpClient}, nil
+}
+
+// PutObject uploads size bytes from body. payloadHash is the hex SHA-256 of the body.
+func (c *Client) PutObject(ctx context.Context, key string, body io.Reader, size int64, payloadHash string) error {
+	req, err := c.newRequest(ctx, http.MethodPut, key, body)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Type", "application/gzip")
+
+	resp, err := c.do(req, payloadHash)
+	if err != nil {
+		return err
+	}
+	_ = resp.Body.Close()
+	return nil
+}
+
+// GetObject returns the object body; the caller must close it.
+func (c *Client) GetObject(ctx context.Context, key string) (io.ReadCloser, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, key, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.do(req, emptyPayloadHash)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+func (c *Client) DeleteObject(ctx context.Context, key string) error {
+	req, err := c.newRequest(ctx, http.MethodDelete, key, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.do(req, emptyPayloadHash)
+	if err != nil {
+		return err
+	}
+	_ = resp.Body.Close()
+	return nil
+}
+
+func (c *Client) newRequest(ctx context.Context, method, key string, body io.Reader) (*http.Request, error) {
+	endpoint, err := url.Parse(strings.TrimRight(c.cfg.Endpoint, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid s3 endpoint: %w", err)
+	}
+
+	segments := strings.Split(strings.TrimLeft(key, "/"), "/")
+	escaped := make([]string, len(segments))
+	for i, seg := range segments {
+		escaped[i] = url.PathEscape(seg)
+	}
+	objectPath := strings.Join(segments, "/")
+	objectRawPath := strings.Join(escaped, "/")
+
+	if c.cfg.PathStyle {
+		endpoint.Path += "/" + c.cfg.Bucket + "/" + objectPath
+		endpoint.RawPath += "/" + url.PathEscape(c.cfg.Bucket) + "/" + objectRawPath
+	} else {
+		endpoint.Host = c.cfg.Bucket + "." + endpoint.Host
+		endpoint.Path += "/" + objectPath
+		endpoint.RawPath += "/" + objectRawPath
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint.String(), body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build s3 request: %w", err)
+	}
+	return req, nil
+}
+
+func (c *Client) do(req *http.Request, payloadHash string) (*http.Response, error) {
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	creds := aws.Credentials{AccessKeyID: c.cfg.AccessKeyID, SecretAccessKey: c.cfg.SecretAccessKey}
+	if err := c.signer.SignHTTP(req.Context(), creds, req, payloadHash, "s3", c.cfg.Region, time.Now().UTC()); err != nil {
+		return nil, fmt.Errorf("failed to sign s3 request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("s3 %s request failed: %w", req.Method, err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("s3 %s returned %s: %s", req.Method, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return resp, nil
+}
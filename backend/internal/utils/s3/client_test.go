@@ -0,0 +1,54 @@
+package s3
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientPathStyleRoundTrip(t *testing.T) {
+	objects := map[string]string{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.True(t, strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKID/"))
+		require.NotEmpty(t, r.Header.Get("X-Amz-Content-Sha256"))
+		switch r.Method {
+		case http.MethodPut:
+			body, _ := io.ReadAll(r.Body)
+			objects[r.URL.EscapedPath()] = string(body)
+		case http.MethodGet:
+			body, ok := objects[r.URL.EscapedPath()]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = io.WriteString(w, body)
+		case http.MethodDelete:
+			delete(objects, r.URL.EscapedPath())
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer srv.Close()
+
+	client, err := NewClient(Config{Endpoint: srv.URL, Bucket: "backups", AccessKeyID: "AKID", SecretAccessKey: "secret", PathStyle: true}, srv.Client())
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	key := "arcane/pg data/backup.tar.gz"
+	require.NoError(t, client.PutObject(ctx, key, strings.NewReader("archive"), 7, "UNSIGNED-PAYLOAD"))
+	require.Contains(t, objects, "/backups/arcane/pg%20data/backup.tar.gz")
+
+	body, err := client.GetObject(ctx, key)
+	require.NoError(t, err)
+	data, _ := io.ReadAll(body)
+	_ = body.Close()
+	require.Equal(t, "archive", string(data))
+
+	require.NoError(t, client.DeleteObject(ctx, key))
+	_, err = client.GetObject(ctx, key)
+	require.ErrorContains(t, err, "404")
+}
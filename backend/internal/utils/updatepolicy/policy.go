@@ -0,0 +1,234 @@
+// Package updatepolicy decides which image updates the auto-updater may apply to a
+// container and when.
+package updatepolicy
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/robfig/cron/v3"
+)
+
+type Policy string
+
+const (
+	// PolicyPin never touches the container.
+	PolicyPin Policy = "pin"
+	// PolicyDigest only follows new digests of the current tag.
+	PolicyDigest Policy = "digest"
+	// PolicyPatch, PolicyMinor and PolicyMajor additionally move to newer semver tags
+	// within the same minor line, the same major line, or anywhere.
+	PolicyPatch Policy = "patch"
+	PolicyMinor Policy = "minor"
+	PolicyMajor Policy = "major"
+)
+
+// Parse normalizes a policy name. The empty string is not a valid policy.
+func Parse(s string) (Policy, bool) {
+	p := Policy(strings.ToLower(strings.TrimSpace(s)))
+	switch p {
+	case PolicyPin, PolicyDigest, PolicyPatch, PolicyMinor, PolicyMajor:
+		return p, true
+	}
+	return "", false
+}
+
+// AllowsTagChange reports whether the policy may move a container to a different tag.
+func (p Policy) AllowsTagChange() bool {
+	return p == PolicyPatch || p == PolicyMinor || p == PolicyMajor
+}
+
+// LatestAllowedTag returns the highest tag in tags that the policy allows moving to from
+// current, or false when current is not a semver tag or nothing newer qualifies. Only tags
+// written the same way are considered: the same "v" prefix, the same number of version
+// components and the same pre-release suffix (so "1.2.3-alpine" only moves to other
+// "-alpine" tags).
+func LatestAllowedTag(current string, tags []string, policy Policy) (string, bool) {
+	if !policy.AllowsTagChange() {
+		return "", false
+	}
+	cur, curShape, ok := parseTag(current)
+	if !ok {
+		return "", false
+	}
+
+	var (
+		best    *semver.Version
+		bestTag string
+	)
+	for _, tag := range tags {
+		v, shape, ok := parseTag(tag)
+		if !ok || shape != curShape || !v.GreaterThan(cur) {
+			continue
+		}
+		switch policy {
+		case PolicyPatch:
+			if v.Major() != cur.Major() || v.Minor() != cur.Minor() {
+				continue
+			}
+		case PolicyMinor:
+			if v.Major() != cur.Major() {
+				continue
+			}
+		case PolicyMajor, PolicyPin, PolicyDigest:
+		}
+		if best == nil || v.GreaterThan(best) {
+			best, bestTag = v, tag
+		}
+	}
+	return bestTag, best != nil
+}
+
+// IsVersionTag reports whether tag is a semver-style tag such as "1.4", "v2.0.1" or
+// "1.4.2-alpine".
+func IsVersionTag(tag string) bool {
+	_, _, ok := parseTag(tag)
+	return ok
+}
+
+// NewerTags returns every tag in tags written like current (see LatestAllowedTag) with a
+// higher version, newest first.
+func NewerTags(current string, tags []string) []string {
+	cur, curShape, ok := parseTag(current)
+	if !ok {
+		return nil
+	}
+
+	type candidate struct {
+		tag string
+		v   *semver.Version
+	}
+	var found []candidate
+	for _, tag := range tags {
+		v, shape, ok := parseTag(tag)
+		if ok && shape == curShape && v.GreaterThan(cur) {
+			found = append(found, candidate{tag: tag, v: v})
+		}
+	}
+	sort.SliceStable(found, func(i, j int) bool { return found[i].v.GreaterThan(found[j].v) })
+
+	out := make([]string, len(found))
+	for i, c := range found {
+		out[i] = c.tag
+	}
+	return out
+}
+
+// tagShape captures how a tag is written so only comparable tags are matched.
+type tagShape struct {
+	vPrefix    bool
+	components int
+	suffix     string
+}
+
+func parseTag(tag string) (*semver.Version, tagShape, bool) {
+	shape := tagShape{}
+	raw := tag
+	if strings.HasPrefix(raw, "v") {
+		shape.vPrefix = true
+		raw = raw[1:]
+	}
+	core, suffix, _ := strings.Cut(raw, "-")
+	if meta := strings.Index(core, "+"); meta != -1 {
+		return nil, shape, false
+	}
+	parts := strings.Split(core, ".")
+	if len(parts) == 0 || len(parts) > 3 {
+		return nil, shape, false
+	}
+	for _, p := range parts {
+		if p == "" || strings.Trim(p, "0123456789") != "" {
+			return nil, shape, false
+		}
+	}
+	shape.components = len(parts)
+	shape.suffix = suffix
+
+	v, err := semver.NewVersion(core)
+	if err != nil {
+		return nil, shape, false
+	}
+	return v, shape, true
+}
+
+// Window is a recurring maintenance window: it opens at every time matched by Cron, in
+// Timezone, and stays open for Duration. A zero Window is always open.
+type Window struct {
+	Cron     string
+	Timezone string
+	Duration time.Duration
+}
+
+const DefaultWindowDuration = time.Hour
+
+func (w Window) IsZero() bool {
+	return strings.TrimSpace(w.Cron) == ""
+}
+
+// Validate checks the cron expression and timezone.
+func (w Window) Validate() error {
+	if w.IsZero() {
+		return nil
+	}
+	if _, err := w.schedule(); err != nil {
+		return err
+	}
+	if w.Duration < 0 {
+		return fmt.Errorf("window duration must not be negative")
+	}
+	return nil
+}
+
+// IsOpen reports whether now falls inside the window.
+func (w Window) IsOpen(now time.Time) (bool, error) {
+	if w.IsZero() {
+		return true, nil
+	}
+	_, open, err := w.OpenedAt(now)
+	return open, err
+}
+
+// OpenedAt returns when the window containing now opened, and false when now falls
+// outside the window. A zero Window has no openings.
+func (w Window) OpenedAt(now time.Time) (time.Time, bool, error) {
+	if w.IsZero() {
+		return time.Time{}, false, nil
+	}
+	sched, err := w.schedule()
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	d := w.Duration
+	if d <= 0 {
+		d = DefaultWindowDuration
+	}
+	// The first start after now-d is at or before now exactly when a window that began
+	// within the last d is still running.
+	start := sched.Next(now.Add(-d))
+	if start.After(now) {
+		return time.Time{}, false, nil
+	}
+	return start, true, nil
+}
+
+func (w Window) schedule() (cron.Schedule, error) {
+	loc := time.Local
+	if tz := strings.TrimSpace(w.Timezone); tz != "" {
+		l, err := time.LoadLocation(tz)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timezone %q: %w", tz, err)
+		}
+		loc = l
+	}
+	sched, err := cron.ParseStandard(strings.TrimSpace(w.Cron))
+	if err != nil {
+		return nil, fmt.Errorf("invalid cron expression: %w", err)
+	}
+	if spec, ok := sched.(*cron.SpecSchedule); ok {
+		spec.Location = loc
+	}
+	return sched, nil
+}
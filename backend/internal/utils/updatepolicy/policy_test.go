@@ -0,0 +1,71 @@
+package updatepolicy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLatestAllowedTag(t *testing.T) {
+	tags := []string{"latest", "1.2.3", "1.2.4", "1.2.10", "1.3.0", "1.3.1-rc1", "2.0.0", "1.4", "v1.9.9", "1.5.0-alpine", "1.2.9-alpine"}
+
+	tag, ok := LatestAllowedTag("1.2.3", tags, PolicyPatch)
+	require.True(t, ok)
+	require.Equal(t, "1.2.10", tag)
+
+	tag, ok = LatestAllowedTag("1.2.3", tags, PolicyMinor)
+	require.True(t, ok)
+	require.Equal(t, "1.3.0", tag)
+
+	tag, ok = LatestAllowedTag("1.2.3", tags, PolicyMajor)
+	require.True(t, ok)
+	require.Equal(t, "2.0.0", tag)
+
+	tag, ok = LatestAllowedTag("1.2.3-alpine", tags, PolicyMinor)
+	require.True(t, ok)
+	require.Equal(t, "1.5.0-alpine", tag)
+
+	_, ok = LatestAllowedTag("2.0.0", tags, PolicyMajor)
+	require.False(t, ok)
+	_, ok = LatestAllowedTag("latest", tags, PolicyMajor)
+	require.False(t, ok)
+	_, ok = LatestAllowedTag("1.2.3", tags, PolicyDigest)
+	require.False(t, ok)
+}
+
+func TestNewerTags(t *testing.T) {
+	tags := []string{"1.4.1", "1.4.3", "1.5.0", "1.5.0-alpine", "v1.6.0", "1.10.0", "latest"}
+
+	require.Equal(t, []string{"1.10.0", "1.5.0", "1.4.3"}, NewerTags("1.4.2", tags))
+	require.Equal(t, []string{"1.5.0-alpine"}, NewerTags("1.4.2-alpine", tags))
+	require.Empty(t, NewerTags("latest", tags))
+	require.True(t, IsVersionTag("v1.2"))
+	require.False(t, IsVersionTag("stable"))
+}
+
+func TestWindowIsOpen(t *testing.T) {
+	w := Window{Cron: "0 2 * * *", Timezone: "Europe/Berlin", Duration: 2 * time.Hour}
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	open, err := w.IsOpen(time.Date(2025, 6, 1, 3, 30, 0, 0, berlin))
+	require.NoError(t, err)
+	require.True(t, open)
+
+	open, err = w.IsOpen(time.Date(2025, 6, 1, 4, 30, 0, 0, berlin))
+	require.NoError(t, err)
+	require.False(t, open)
+
+	// 01:30 UTC is 03:30 in Berlin during summer time.
+	open, err = w.IsOpen(time.Date(2025, 6, 1, 1, 30, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.True(t, open)
+
+	open, err = Window{}.IsOpen(time.Now())
+	require.NoError(t, err)
+	require.True(t, open)
+
+	require.Error(t, Window{Cron: "0 2 * * *", Timezone: "Mars/Olympus"}.Validate())
+	require.Error(t, Window{Cron: "whenever"}.Validate())
+}
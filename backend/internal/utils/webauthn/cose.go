@@ -0,0 +1,138 @@
+package webauthn
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"fmt"
+	"math/big"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+// COSE algorithm, key type and curve identifiers (RFC 9053).
+const (
+	algES256 = -7
+	algEdDSA = -8
+	algRS256 = -257
+
+	ktyOKP = 1
+	ktyEC2 = 2
+	ktyRSA = 3
+
+	crvP256    = 1
+	crvEd25519 = 6
+
+	coseKty = 1
+	coseAlg = 3
+	// Parameters -1, -2 and -3 mean crv/x/y for EC2 keys, crv/x for OKP and n/e for RSA.
+	coseParam1 = -1
+	coseParam2 = -2
+	coseParam3 = -3
+)
+
+type publicKey struct {
+	ec  *ecdsa.PublicKey
+	ed  ed25519.PublicKey
+	rsa *rsa.PublicKey
+}
+
+// parsePublicKey decodes a COSE_Key for one of the algorithms offered in
+// CreationOptions.
+func parsePublicKey(raw []byte) (*publicKey, error) {
+	var m map[int64]cbor.RawMessage
+	if err := cbor.Unmarshal(raw, &m); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrUnsupportedKey, err)
+	}
+	var kty, alg int64
+	if err := decodeParam(m, coseKty, &kty); err != nil {
+		return nil, err
+	}
+	if err := decodeParam(m, coseAlg, &alg); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case kty == ktyEC2 && alg == algES256:
+		var crv int64
+		var x, y []byte
+		if err := decodeParam(m, coseParam1, &crv); err != nil {
+			return nil, err
+		}
+		if err := decodeParam(m, coseParam2, &x); err != nil {
+			return nil, err
+		}
+		if err := decodeParam(m, coseParam3, &y); err != nil {
+			return nil, err
+		}
+		if crv != crvP256 || len(x) != 32 || len(y) != 32 {
+			return nil, ErrUnsupportedKey
+		}
+		key := &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if _, err := key.ECDH(); err != nil {
+			return nil, fmt.Errorf("%w: point is not on the curve", ErrUnsupportedKey)
+		}
+		return &publicKey{ec: key}, nil
+
+	case kty == ktyOKP && alg == algEdDSA:
+		var crv int64
+		var x []byte
+		if err := decodeParam(m, coseParam1, &crv); err != nil {
+			return nil, err
+		}
+		if err := decodeParam(m, coseParam2, &x); err != nil {
+			return nil, err
+		}
+		if crv != crvEd25519 || len(x) != ed25519.PublicKeySize {
+			return nil, ErrUnsupportedKey
+		}
+		return &publicKey{ed: ed25519.PublicKey(x)}, nil
+
+	case kty == ktyRSA && alg == algRS256:
+		var n, e []byte
+		if err := decodeParam(m, coseParam1, &n); err != nil {
+			return nil, err
+		}
+		if err := decodeParam(m, coseParam2, &e); err != nil {
+			return nil, err
+		}
+		exponent := new(big.Int).SetBytes(e)
+		if len(n) < 256 || !exponent.IsInt64() || exponent.Int64() < 3 || exponent.Int64() > 1<<31-1 {
+			return nil, ErrUnsupportedKey
+		}
+		return &publicKey{rsa: &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exponent.Int64())}}, nil
+	}
+	return nil, fmt.Errorf("%w: key type %d, algorithm %d", ErrUnsupportedKey, kty, alg)
+}
+
+func decodeParam(m map[int64]cbor.RawMessage, label int64, v any) error {
+	raw, ok := m[label]
+	if !ok {
+		return fmt.Errorf("%w: missing parameter %d", ErrUnsupportedKey, label)
+	}
+	if err := cbor.Unmarshal(raw, v); err != nil {
+		return fmt.Errorf("%w: parameter %d: %w", ErrUnsupportedKey, label, err)
+	}
+	return nil
+}
+
+func (k *publicKey) verify(data, signature []byte) error {
+	var ok bool
+	switch {
+	case k.ec != nil:
+		digest := sha256.Sum256(data)
+		ok = ecdsa.VerifyASN1(k.ec, digest[:], signature)
+	case k.ed != nil:
+		ok = ed25519.Verify(k.ed, data, signature)
+	case k.rsa != nil:
+		digest := sha256.Sum256(data)
+		ok = rsa.VerifyPKCS1v15(k.rsa, crypto.SHA256, digest[:], signature) == nil
+	}
+	if !ok {
+		return ErrInvalidSignature
+	}
+	return nil
+}
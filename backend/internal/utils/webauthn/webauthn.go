@@ -0,0 +1,388 @@
+// Package webauthn implements the relying party side of WebAuthn (passkeys and security
+// keys): building the options passed to navigator.credentials.create/get and verifying
+// what the browser sends back.
+//
+// Attestation is requested as "none" and attestation statements are not verified; any
+// authenticator the user owns is accepted. User verification (PIN, biometrics) is
+// required, so a passkey sign in counts as multi-factor on its own.
+package webauthn
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+const (
+	Timeout = 5 * time.Minute
+
+	flagUserPresent    = 0x01
+	flagUserVerified   = 0x04
+	flagBackupEligible = 0x08
+	flagBackedUp       = 0x10
+	flagAttestedData   = 0x40
+
+	challengeSize = 32
+)
+
+var (
+	ErrInvalidResponse    = errors.New("invalid WebAuthn response")
+	ErrChallengeMismatch  = errors.New("WebAuthn challenge does not match")
+	ErrOriginMismatch     = errors.New("WebAuthn origin does not match")
+	ErrUserNotVerified    = errors.New("authenticator did not verify the user")
+	ErrInvalidSignature   = errors.New("invalid WebAuthn signature")
+	ErrUnsupportedKey     = errors.New("unsupported credential public key")
+	ErrSignCountRegressed = errors.New("authenticator signature counter went backwards, the credential may be cloned")
+)
+
+var encoding = base64.RawURLEncoding
+
+// RelyingParty identifies this server to authenticators. ID is the domain credentials
+// are scoped to and Origin the exact origin the browser reports.
+type RelyingParty struct {
+	ID     string
+	Name   string
+	Origin string
+}
+
+// NewRelyingParty derives the relying party from the URL users open Arcane at.
+func NewRelyingParty(appURL, name string) (*RelyingParty, error) {
+	u, err := url.Parse(appURL)
+	if err != nil || u.Scheme == "" || u.Hostname() == "" {
+		return nil, fmt.Errorf("invalid application URL %q", appURL)
+	}
+	return &RelyingParty{ID: u.Hostname(), Name: name, Origin: u.Scheme + "://" + u.Host}, nil
+}
+
+// NewChallenge returns a random base64url challenge.
+func NewChallenge() (string, error) {
+	b := make([]byte, challengeSize)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate WebAuthn challenge: %w", err)
+	}
+	return encoding.EncodeToString(b), nil
+}
+
+// CredentialDescriptor references an existing credential.
+type CredentialDescriptor struct {
+	Type       string   `json:"type"`
+	ID         string   `json:"id"`
+	Transports []string `json:"transports,omitempty"`
+}
+
+type rpEntity struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type userEntity struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName"`
+}
+
+type credentialParameter struct {
+	Type string `json:"type"`
+	Alg  int64  `json:"alg"`
+}
+
+type authenticatorSelection struct {
+	ResidentKey        string `json:"residentKey"`
+	RequireResidentKey bool   `json:"requireResidentKey"`
+	UserVerification   string `json:"userVerification"`
+}
+
+// CreationOptions is the JSON form of PublicKeyCredentialCreationOptions, as accepted by
+// PublicKeyCredential.parseCreationOptionsFromJSON.
+type CreationOptions struct {
+	Challenge              string                 `json:"challenge"`
+	RP                     rpEntity               `json:"rp"`
+	User                   userEntity             `json:"user"`
+	PubKeyCredParams       []credentialParameter  `json:"pubKeyCredParams"`
+	Timeout                int64                  `json:"timeout"`
+	Attestation            string                 `json:"attestation"`
+	AuthenticatorSelection authenticatorSelection `json:"authenticatorSelection"`
+	ExcludeCredentials     []CredentialDescriptor `json:"excludeCredentials"`
+}
+
+// RequestOptions is the JSON form of PublicKeyCredentialRequestOptions. AllowCredentials
+// is left empty so the browser offers any discoverable credential for this site.
+type RequestOptions struct {
+	Challenge        string                 `json:"challenge"`
+	RPID             string                 `json:"rpId"`
+	Timeout          int64                  `json:"timeout"`
+	UserVerification string                 `json:"userVerification"`
+	AllowCredentials []CredentialDescriptor `json:"allowCredentials"`
+}
+
+// CreationOptions builds registration options for a discoverable credential. userHandle
+// is stored on the authenticator and returned on sign in to identify the account.
+func (rp *RelyingParty) CreationOptions(challenge string, userHandle []byte, name, displayName string, exclude []CredentialDescriptor) *CreationOptions {
+	if exclude == nil {
+		exclude = []CredentialDescriptor{}
+	}
+	return &CreationOptions{
+		Challenge: challenge,
+		RP:        rpEntity{ID: rp.ID, Name: rp.Name},
+		User:      userEntity{ID: encoding.EncodeToString(userHandle), Name: name, DisplayName: displayName},
+		PubKeyCredParams: []credentialParameter{
+			{Type: "public-key", Alg: algES256},
+			{Type: "public-key", Alg: algEdDSA},
+			{Type: "public-key", Alg: algRS256},
+		},
+		Timeout:     Timeout.Milliseconds(),
+		Attestation: "none",
+		AuthenticatorSelection: authenticatorSelection{
+			ResidentKey:        "required",
+			RequireResidentKey: true,
+			UserVerification:   "required",
+		},
+		ExcludeCredentials: exclude,
+	}
+}
+
+// RequestOptions builds sign-in options.
+func (rp *RelyingParty) RequestOptions(challenge string) *RequestOptions {
+	return &RequestOptions{
+		Challenge:        challenge,
+		RPID:             rp.ID,
+		Timeout:          Timeout.Milliseconds(),
+		UserVerification: "required",
+		AllowCredentials: []CredentialDescriptor{},
+	}
+}
+
+// RegistrationResponse is the JSON a browser produces for a new credential
+// (PublicKeyCredential.toJSON()).
+type RegistrationResponse struct {
+	ID       string `json:"id"`
+	RawID    string `json:"rawId"`
+	Type     string `json:"type"`
+	Response struct {
+		ClientDataJSON    string   `json:"clientDataJSON"`
+		AttestationObject string   `json:"attestationObject"`
+		Transports        []string `json:"transports,omitempty"`
+	} `json:"response"`
+}
+
+// AssertionResponse is the JSON a browser produces when signing in with a credential.
+type AssertionResponse struct {
+	ID       string `json:"id"`
+	RawID    string `json:"rawId"`
+	Type     string `json:"type"`
+	Response struct {
+		ClientDataJSON    string `json:"clientDataJSON"`
+		AuthenticatorData string `json:"authenticatorData"`
+		Signature         string `json:"signature"`
+		UserHandle        string `json:"userHandle,omitempty"`
+	} `json:"response"`
+}
+
+// Credential is a verified new credential, ready to be stored.
+type Credential struct {
+	ID         []byte
+	PublicKey  []byte
+	AAGUID     []byte
+	SignCount  uint32
+	BackedUp   bool
+	Transports []string
+}
+
+// Assertion is the result of a verified sign in.
+type Assertion struct {
+	SignCount uint32
+	BackedUp  bool
+}
+
+// DecodeID decodes a base64url credential ID or user handle.
+func DecodeID(s string) ([]byte, error) {
+	b, err := encoding.DecodeString(strings.TrimRight(s, "="))
+	if err != nil {
+		return nil, ErrInvalidResponse
+	}
+	return b, nil
+}
+
+// EncodeID encodes a credential ID or user handle as base64url.
+func EncodeID(b []byte) string {
+	return encoding.EncodeToString(b)
+}
+
+// VerifyRegistration checks a registration response against the challenge that was
+// issued for it and returns the new credential.
+func (rp *RelyingParty) VerifyRegistration(resp *RegistrationResponse, challenge string) (*Credential, error) {
+	if resp.Type != "public-key" {
+		return nil, ErrInvalidResponse
+	}
+	rawID, err := DecodeID(resp.RawID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := rp.verifyClientData(resp.Response.ClientDataJSON, "webauthn.create", challenge); err != nil {
+		return nil, err
+	}
+
+	attObj, err := DecodeID(resp.Response.AttestationObject)
+	if err != nil {
+		return nil, err
+	}
+	var att struct {
+		Fmt      string          `cbor:"fmt"`
+		AttStmt  cbor.RawMessage `cbor:"attStmt"`
+		AuthData []byte          `cbor:"authData"`
+	}
+	if err := cbor.Unmarshal(attObj, &att); err != nil {
+		return nil, fmt.Errorf("%w: attestation object: %w", ErrInvalidResponse, err)
+	}
+
+	data, err := rp.verifyAuthenticatorData(att.AuthData)
+	if err != nil {
+		return nil, err
+	}
+	if data.credentialID == nil {
+		return nil, fmt.Errorf("%w: no attested credential data", ErrInvalidResponse)
+	}
+	if !bytes.Equal(data.credentialID, rawID) {
+		return nil, fmt.Errorf("%w: credential ID mismatch", ErrInvalidResponse)
+	}
+	if _, err := parsePublicKey(data.publicKey); err != nil {
+		return nil, err
+	}
+
+	return &Credential{
+		ID:         data.credentialID,
+		PublicKey:  data.publicKey,
+		AAGUID:     data.aaguid,
+		SignCount:  data.signCount,
+		BackedUp:   data.flags&flagBackedUp != 0,
+		Transports: resp.Response.Transports,
+	}, nil
+}
+
+// VerifyAssertion checks a sign-in response against the issued challenge and the stored
+// public key and sign count of the credential it claims to come from.
+func (rp *RelyingParty) VerifyAssertion(resp *AssertionResponse, challenge string, publicKey []byte, storedSignCount uint32) (*Assertion, error) {
+	if resp.Type != "public-key" {
+		return nil, ErrInvalidResponse
+	}
+	clientData, err := rp.verifyClientData(resp.Response.ClientDataJSON, "webauthn.get", challenge)
+	if err != nil {
+		return nil, err
+	}
+	authData, err := DecodeID(resp.Response.AuthenticatorData)
+	if err != nil {
+		return nil, err
+	}
+	data, err := rp.verifyAuthenticatorData(authData)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := DecodeID(resp.Response.Signature)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := parsePublicKey(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	clientDataHash := sha256.Sum256(clientData)
+	signed := append(append([]byte{}, authData...), clientDataHash[:]...)
+	if err := key.verify(signed, signature); err != nil {
+		return nil, err
+	}
+
+	// Authenticators that keep a counter must increase it; passkeys synced between
+	// devices usually report 0 every time.
+	if (data.signCount != 0 || storedSignCount != 0) && data.signCount <= storedSignCount {
+		return nil, ErrSignCountRegressed
+	}
+
+	return &Assertion{SignCount: data.signCount, BackedUp: data.flags&flagBackedUp != 0}, nil
+}
+
+func (rp *RelyingParty) verifyClientData(encoded, ceremony, challenge string) ([]byte, error) {
+	raw, err := DecodeID(encoded)
+	if err != nil {
+		return nil, err
+	}
+	var clientData struct {
+		Type      string `json:"type"`
+		Challenge string `json:"challenge"`
+		Origin    string `json:"origin"`
+	}
+	if err := json.Unmarshal(raw, &clientData); err != nil {
+		return nil, fmt.Errorf("%w: client data: %w", ErrInvalidResponse, err)
+	}
+	if clientData.Type != ceremony {
+		return nil, fmt.Errorf("%w: unexpected type %q", ErrInvalidResponse, clientData.Type)
+	}
+	if subtle.ConstantTimeCompare([]byte(strings.TrimRight(clientData.Challenge, "=")), []byte(challenge)) != 1 {
+		return nil, ErrChallengeMismatch
+	}
+	if clientData.Origin != rp.Origin {
+		return nil, fmt.Errorf("%w: got %q, expected %q", ErrOriginMismatch, clientData.Origin, rp.Origin)
+	}
+	return raw, nil
+}
+
+type authenticatorData struct {
+	flags        byte
+	signCount    uint32
+	aaguid       []byte
+	credentialID []byte
+	publicKey    []byte
+}
+
+func (rp *RelyingParty) verifyAuthenticatorData(b []byte) (*authenticatorData, error) {
+	if len(b) < 37 {
+		return nil, fmt.Errorf("%w: authenticator data too short", ErrInvalidResponse)
+	}
+	rpIDHash := sha256.Sum256([]byte(rp.ID))
+	if subtle.ConstantTimeCompare(b[:32], rpIDHash[:]) != 1 {
+		return nil, fmt.Errorf("%w: relying party ID mismatch", ErrInvalidResponse)
+	}
+
+	data := &authenticatorData{flags: b[32], signCount: binary.BigEndian.Uint32(b[33:37])}
+	if data.flags&flagUserPresent == 0 {
+		return nil, fmt.Errorf("%w: user not present", ErrInvalidResponse)
+	}
+	if data.flags&flagUserVerified == 0 {
+		return nil, ErrUserNotVerified
+	}
+	if data.flags&flagBackedUp != 0 && data.flags&flagBackupEligible == 0 {
+		return nil, fmt.Errorf("%w: invalid backup flags", ErrInvalidResponse)
+	}
+
+	if data.flags&flagAttestedData != 0 {
+		rest := b[37:]
+		if len(rest) < 18 {
+			return nil, fmt.Errorf("%w: attested credential data too short", ErrInvalidResponse)
+		}
+		data.aaguid = rest[:16]
+		idLen := int(binary.BigEndian.Uint16(rest[16:18]))
+		rest = rest[18:]
+		if idLen == 0 || len(rest) < idLen {
+			return nil, fmt.Errorf("%w: invalid credential ID length", ErrInvalidResponse)
+		}
+		data.credentialID = rest[:idLen]
+
+		var key cbor.RawMessage
+		if _, err := cbor.UnmarshalFirst(rest[idLen:], &key); err != nil {
+			return nil, fmt.Errorf("%w: credential public key: %w", ErrInvalidResponse, err)
+		}
+		data.publicKey = []byte(key)
+	}
+	return data, nil
+}
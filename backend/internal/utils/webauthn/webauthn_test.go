@@ -0,0 +1,207 @@
+package webauthn
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/stretchr/testify/require"
+)
+
+// softAuthenticator is a minimal ES256 authenticator for exercising the verifier.
+type softAuthenticator struct {
+	key       *ecdsa.PrivateKey
+	id        []byte
+	signCount uint32
+}
+
+func newSoftAuthenticator(t *testing.T) *softAuthenticator {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	return &softAuthenticator{key: key, id: []byte("credential-1")}
+}
+
+func (a *softAuthenticator) coseKey(t *testing.T) []byte {
+	t.Helper()
+	pub, err := a.key.PublicKey.Bytes()
+	require.NoError(t, err)
+	b, err := cbor.Marshal(map[int64]any{
+		coseKty: ktyEC2, coseAlg: algES256, coseParam1: crvP256, coseParam2: pub[1:33], coseParam3: pub[33:],
+	})
+	require.NoError(t, err)
+	return b
+}
+
+func (a *softAuthenticator) authData(rpID string, flags byte, attested []byte) []byte {
+	hash := sha256.Sum256([]byte(rpID))
+	b := append([]byte{}, hash[:]...)
+	b = append(b, flags)
+	b = binary.BigEndian.AppendUint32(b, a.signCount)
+	return append(b, attested...)
+}
+
+func clientDataJSON(t *testing.T, typ, challenge, origin string) string {
+	t.Helper()
+	b, err := json.Marshal(map[string]any{"type": typ, "challenge": challenge, "origin": origin})
+	require.NoError(t, err)
+	return EncodeID(b)
+}
+
+func (a *softAuthenticator) register(t *testing.T, rp *RelyingParty, challenge, origin string, flags byte) *RegistrationResponse {
+	t.Helper()
+	attested := make([]byte, 16)
+	attested = binary.BigEndian.AppendUint16(attested, uint16(len(a.id)))
+	attested = append(attested, a.id...)
+	attested = append(attested, a.coseKey(t)...)
+	attObj, err := cbor.Marshal(map[string]any{"fmt": "none", "attStmt": map[string]any{}, "authData": a.authData(rp.ID, flags|flagAttestedData, attested)})
+	require.NoError(t, err)
+
+	resp := &RegistrationResponse{ID: EncodeID(a.id), RawID: EncodeID(a.id), Type: "public-key"}
+	resp.Response.ClientDataJSON = clientDataJSON(t, "webauthn.create", challenge, origin)
+	resp.Response.AttestationObject = EncodeID(attObj)
+	resp.Response.Transports = []string{"usb"}
+	return resp
+}
+
+func (a *softAuthenticator) assert(t *testing.T, rp *RelyingParty, challenge string) *AssertionResponse {
+	t.Helper()
+	return a.assertWith(t, rp.ID, rp.Origin, challenge, flagUserPresent|flagUserVerified)
+}
+
+// assertWith signs an assertion with the given RP ID, origin and flags, so tests can
+// produce responses that are validly signed but wrong in one field.
+func (a *softAuthenticator) assertWith(t *testing.T, rpID, origin, challenge string, flags byte) *AssertionResponse {
+	t.Helper()
+	authData := a.authData(rpID, flags, nil)
+	clientData := clientDataJSON(t, "webauthn.get", challenge, origin)
+	raw, err := DecodeID(clientData)
+	require.NoError(t, err)
+	hash := sha256.Sum256(raw)
+	digest := sha256.Sum256(append(append([]byte{}, authData...), hash[:]...))
+	sig, err := ecdsa.SignASN1(rand.Reader, a.key, digest[:])
+	require.NoError(t, err)
+
+	resp := &AssertionResponse{ID: EncodeID(a.id), RawID: EncodeID(a.id), Type: "public-key"}
+	resp.Response.ClientDataJSON = clientData
+	resp.Response.AuthenticatorData = EncodeID(authData)
+	resp.Response.Signature = EncodeID(sig)
+	return resp
+}
+
+func TestNewRelyingParty(t *testing.T) {
+	rp, err := NewRelyingParty("https://arcane.example.com:8443/app", "Arcane")
+	require.NoError(t, err)
+	require.Equal(t, "arcane.example.com", rp.ID)
+	require.Equal(t, "https://arcane.example.com:8443", rp.Origin)
+
+	_, err = NewRelyingParty("not a url", "Arcane")
+	require.Error(t, err)
+}
+
+func TestRegistrationAndAssertion(t *testing.T) {
+	rp, err := NewRelyingParty("https://arcane.example.com", "Arcane")
+	require.NoError(t, err)
+	auth := newSoftAuthenticator(t)
+	challenge, err := NewChallenge()
+	require.NoError(t, err)
+
+	_, err = rp.VerifyRegistration(auth.register(t, rp, challenge, "https://evil.example.com", flagUserPresent|flagUserVerified), challenge)
+	require.ErrorIs(t, err, ErrOriginMismatch)
+	_, err = rp.VerifyRegistration(auth.register(t, rp, challenge, rp.Origin, flagUserPresent), challenge)
+	require.ErrorIs(t, err, ErrUserNotVerified)
+	other, err := NewChallenge()
+	require.NoError(t, err)
+	_, err = rp.VerifyRegistration(auth.register(t, rp, other, rp.Origin, flagUserPresent|flagUserVerified), challenge)
+	require.ErrorIs(t, err, ErrChallengeMismatch)
+
+	cred, err := rp.VerifyRegistration(auth.register(t, rp, challenge, rp.Origin, flagUserPresent|flagUserVerified), challenge)
+	require.NoError(t, err)
+	require.Equal(t, auth.id, cred.ID)
+	require.Equal(t, []string{"usb"}, cred.Transports)
+
+	auth.signCount = 5
+	assertion, err := rp.VerifyAssertion(auth.assert(t, rp, challenge), challenge, cred.PublicKey, cred.SignCount)
+	require.NoError(t, err)
+	require.Equal(t, uint32(5), assertion.SignCount)
+
+	_, err = rp.VerifyAssertion(auth.assert(t, rp, challenge), challenge, cred.PublicKey, assertion.SignCount)
+	require.ErrorIs(t, err, ErrSignCountRegressed)
+
+	auth.signCount = 6
+	tampered := auth.assert(t, rp, challenge)
+	tampered.Response.Signature = auth.assert(t, rp, other).Response.Signature
+	_, err = rp.VerifyAssertion(tampered, challenge, cred.PublicKey, assertion.SignCount)
+	require.ErrorIs(t, err, ErrInvalidSignature)
+
+	otherKey := newSoftAuthenticator(t)
+	_, err = rp.VerifyAssertion(auth.assert(t, rp, challenge), challenge, otherKey.coseKey(t), assertion.SignCount)
+	require.ErrorIs(t, err, ErrInvalidSignature)
+}
+
+func TestVerificationRejectsForgedResponses(t *testing.T) {
+	rp, err := NewRelyingParty("https://arcane.example.com", "Arcane")
+	require.NoError(t, err)
+	evil, err := NewRelyingParty("https://evil.example.com", "Evil")
+	require.NoError(t, err)
+	auth := newSoftAuthenticator(t)
+	challenge, err := NewChallenge()
+	require.NoError(t, err)
+	uv := byte(flagUserPresent | flagUserVerified)
+
+	// A credential created for another relying party, relayed through our origin.
+	_, err = rp.VerifyRegistration(auth.register(t, evil, challenge, rp.Origin, uv), challenge)
+	require.ErrorIs(t, err, ErrInvalidResponse)
+	require.ErrorContains(t, err, "relying party ID mismatch")
+
+	auth.signCount = 10
+	cred, err := rp.VerifyRegistration(auth.register(t, rp, challenge, rp.Origin, uv), challenge)
+	require.NoError(t, err)
+	require.Equal(t, uint32(10), cred.SignCount)
+
+	t.Run("wrong RP ID hash", func(t *testing.T) {
+		auth.signCount = 11
+		_, err := rp.VerifyAssertion(auth.assertWith(t, evil.ID, rp.Origin, challenge, uv), challenge, cred.PublicKey, cred.SignCount)
+		require.ErrorIs(t, err, ErrInvalidResponse)
+		require.ErrorContains(t, err, "relying party ID mismatch")
+	})
+
+	t.Run("missing UV flag", func(t *testing.T) {
+		auth.signCount = 11
+		_, err := rp.VerifyAssertion(auth.assertWith(t, rp.ID, rp.Origin, challenge, flagUserPresent), challenge, cred.PublicKey, cred.SignCount)
+		require.ErrorIs(t, err, ErrUserNotVerified)
+	})
+
+	t.Run("missing UP flag", func(t *testing.T) {
+		auth.signCount = 11
+		_, err := rp.VerifyAssertion(auth.assertWith(t, rp.ID, rp.Origin, challenge, flagUserVerified), challenge, cred.PublicKey, cred.SignCount)
+		require.ErrorIs(t, err, ErrInvalidResponse)
+	})
+
+	t.Run("mismatched origin", func(t *testing.T) {
+		auth.signCount = 11
+		_, err := rp.VerifyAssertion(auth.assertWith(t, rp.ID, evil.Origin, challenge, uv), challenge, cred.PublicKey, cred.SignCount)
+		require.ErrorIs(t, err, ErrOriginMismatch)
+	})
+
+	t.Run("sign counter regression", func(t *testing.T) {
+		auth.signCount = 9
+		_, err := rp.VerifyAssertion(auth.assert(t, rp, challenge), challenge, cred.PublicKey, cred.SignCount)
+		require.ErrorIs(t, err, ErrSignCountRegressed)
+
+		// An authenticator that had a counter cannot start reporting 0.
+		auth.signCount = 0
+		_, err = rp.VerifyAssertion(auth.assert(t, rp, challenge), challenge, cred.PublicKey, cred.SignCount)
+		require.ErrorIs(t, err, ErrSignCountRegressed)
+	})
+
+	auth.signCount = 11
+	assertion, err := rp.VerifyAssertion(auth.assert(t, rp, challenge), challenge, cred.PublicKey, cred.SignCount)
+	require.NoError(t, err)
+	require.Equal(t, uint32(11), assertion.SignCount)
+}
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_volume_backups_schedule_id;
+DROP INDEX IF EXISTS idx_volume_backups_volume_name;
+DROP TABLE IF EXISTS volume_backups;
+DROP TABLE IF EXISTS volume_backup_schedules;
+DROP TABLE IF EXISTS backup_targets;
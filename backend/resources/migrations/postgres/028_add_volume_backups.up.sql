@@ -0,0 +1,50 @@
+CREATE TABLE IF NOT EXISTS backup_targets (
+    id TEXT PRIMARY KEY,
+    name TEXT NOT NULL,
+    type TEXT NOT NULL DEFAULT 'local',
+    path TEXT NOT NULL DEFAULT '',
+    endpoint TEXT NOT NULL DEFAULT '',
+    region TEXT NOT NULL DEFAULT '',
+    bucket TEXT NOT NULL DEFAULT '',
+    prefix TEXT NOT NULL DEFAULT '',
+    access_key_id TEXT NOT NULL DEFAULT '',
+    secret_access_key TEXT NOT NULL DEFAULT '',
+    path_style BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ
+);
+
+CREATE TABLE IF NOT EXISTS volume_backup_schedules (
+    id TEXT PRIMARY KEY,
+    volume_name TEXT NOT NULL,
+    cron_expression TEXT NOT NULL,
+    target_id TEXT,
+    retention_count INTEGER NOT NULL DEFAULT 0,
+    retention_days INTEGER NOT NULL DEFAULT 0,
+    stop_containers BOOLEAN NOT NULL DEFAULT false,
+    enabled BOOLEAN NOT NULL DEFAULT true,
+    last_run_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ
+);
+
+CREATE TABLE IF NOT EXISTS volume_backups (
+    id TEXT PRIMARY KEY,
+    volume_name TEXT NOT NULL,
+    schedule_id TEXT,
+    target_id TEXT,
+    target_type TEXT NOT NULL DEFAULT 'local',
+    location TEXT NOT NULL DEFAULT '',
+    size_bytes BIGINT NOT NULL DEFAULT 0,
+    checksum TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL,
+    error TEXT,
+    triggered_by TEXT NOT NULL DEFAULT '',
+    started_at TIMESTAMPTZ NOT NULL,
+    completed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_volume_backups_volume_name ON volume_backups(volume_name);
+CREATE INDEX IF NOT EXISTS idx_volume_backups_schedule_id ON volume_backups(schedule_id);
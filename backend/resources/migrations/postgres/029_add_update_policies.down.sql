@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_update_policies_scope;
+DROP TABLE IF EXISTS update_policies;
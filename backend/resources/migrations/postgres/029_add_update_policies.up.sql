@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS update_policies (
+    id TEXT PRIMARY KEY,
+    scope_type TEXT NOT NULL,
+    scope_name TEXT NOT NULL,
+    policy TEXT NOT NULL DEFAULT '',
+    window_cron TEXT NOT NULL DEFAULT '',
+    window_timezone TEXT NOT NULL DEFAULT '',
+    window_duration INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_update_policies_scope ON update_policies(scope_type, scope_name);
@@ -0,0 +1,2 @@
+ALTER TABLE IF EXISTS image_updates
+    DROP COLUMN IF EXISTS candidate_versions;
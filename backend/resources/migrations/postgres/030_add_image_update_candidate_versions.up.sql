@@ -0,0 +1,2 @@
+ALTER TABLE IF EXISTS image_updates
+    ADD COLUMN IF NOT EXISTS candidate_versions TEXT;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_metric_samples_resolution;
+DROP INDEX IF EXISTS idx_metric_samples_series;
+DROP TABLE IF EXISTS metric_samples;
@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS metric_samples (
+    id TEXT PRIMARY KEY,
+    scope TEXT NOT NULL,
+    container_id TEXT NOT NULL DEFAULT '',
+    container_name TEXT NOT NULL DEFAULT '',
+    timestamp TIMESTAMPTZ NOT NULL,
+    resolution INTEGER NOT NULL DEFAULT 0,
+    sample_count INTEGER NOT NULL DEFAULT 1,
+    cpu_percent DOUBLE PRECISION NOT NULL DEFAULT 0,
+    cpu_percent_max DOUBLE PRECISION NOT NULL DEFAULT 0,
+    memory_used BIGINT NOT NULL DEFAULT 0,
+    memory_used_max BIGINT NOT NULL DEFAULT 0,
+    memory_limit BIGINT NOT NULL DEFAULT 0,
+    disk_used BIGINT NOT NULL DEFAULT 0,
+    disk_total BIGINT NOT NULL DEFAULT 0,
+    network_rx BIGINT NOT NULL DEFAULT 0,
+    network_tx BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_metric_samples_series ON metric_samples(scope, container_name, timestamp);
+CREATE INDEX IF NOT EXISTS idx_metric_samples_resolution ON metric_samples(resolution, timestamp);
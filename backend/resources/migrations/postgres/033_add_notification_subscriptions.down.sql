@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_notification_deliveries_status;
+DROP INDEX IF EXISTS idx_notification_deliveries_event_id;
+DROP INDEX IF EXISTS idx_notification_deliveries_subscription_id;
+DROP TABLE IF EXISTS notification_deliveries;
+DROP TABLE IF EXISTS notification_subscriptions;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_notification_queue_provider;
+DROP TABLE IF EXISTS notification_queue;
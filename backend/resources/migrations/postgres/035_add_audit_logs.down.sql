@@ -0,0 +1,7 @@
+DROP TRIGGER IF EXISTS trg_audit_logs_immutable ON audit_logs;
+DROP FUNCTION IF EXISTS audit_logs_immutable();
+DROP INDEX IF EXISTS idx_audit_logs_user_id;
+DROP INDEX IF EXISTS idx_audit_logs_target;
+DROP INDEX IF EXISTS idx_audit_logs_action;
+DROP INDEX IF EXISTS idx_audit_logs_timestamp;
+DROP TABLE IF EXISTS audit_logs;
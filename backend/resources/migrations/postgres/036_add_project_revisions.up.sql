@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS project_revisions (
+    id TEXT PRIMARY KEY,
+    project_id TEXT NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    revision INTEGER NOT NULL,
+    compose_content TEXT NOT NULL DEFAULT '',
+    env_content TEXT NOT NULL DEFAULT '',
+    include_files TEXT,
+    message TEXT NOT NULL DEFAULT '',
+    user_id TEXT,
+    username TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_project_revisions_project_revision ON project_revisions(project_id, revision);
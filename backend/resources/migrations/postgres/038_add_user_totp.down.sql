@@ -0,0 +1,5 @@
+ALTER TABLE IF EXISTS users
+  DROP COLUMN IF EXISTS totp_enabled,
+  DROP COLUMN IF EXISTS totp_secret,
+  DROP COLUMN IF EXISTS totp_last_used_step,
+  DROP COLUMN IF EXISTS totp_recovery_codes;
@@ -0,0 +1,5 @@
+ALTER TABLE IF EXISTS users
+  ADD COLUMN IF NOT EXISTS totp_enabled BOOLEAN NOT NULL DEFAULT false,
+  ADD COLUMN IF NOT EXISTS totp_secret TEXT,
+  ADD COLUMN IF NOT EXISTS totp_last_used_step BIGINT NOT NULL DEFAULT 0,
+  ADD COLUMN IF NOT EXISTS totp_recovery_codes TEXT;
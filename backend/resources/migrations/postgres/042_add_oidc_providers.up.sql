@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS oidc_providers (
+    id TEXT PRIMARY KEY,
+    slug TEXT NOT NULL,
+    name TEXT NOT NULL,
+    issuer_url TEXT NOT NULL,
+    client_id TEXT NOT NULL,
+    client_secret TEXT NOT NULL DEFAULT '',
+    scopes TEXT NOT NULL DEFAULT '',
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_oidc_providers_slug ON oidc_providers(slug);
+
+CREATE TABLE IF NOT EXISTS oidc_role_mappings (
+    id TEXT PRIMARY KEY,
+    provider TEXT NOT NULL DEFAULT '',
+    claim TEXT NOT NULL,
+    value TEXT NOT NULL,
+    role_name TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_oidc_role_mappings_provider ON oidc_role_mappings(provider);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_notification_queue_subscription_id;
+
+ALTER TABLE notification_queue DROP COLUMN IF EXISTS delivery_id;
+ALTER TABLE notification_queue DROP COLUMN IF EXISTS subscription_id;
+ALTER TABLE notification_deliveries DROP COLUMN IF EXISTS next_attempt_at;
@@ -0,0 +1 @@
+ALTER TABLE apprise_settings DROP COLUMN IF EXISTS delivery;
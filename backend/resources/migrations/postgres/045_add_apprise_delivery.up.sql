@@ -0,0 +1 @@
+ALTER TABLE apprise_settings ADD COLUMN IF NOT EXISTS delivery JSONB;
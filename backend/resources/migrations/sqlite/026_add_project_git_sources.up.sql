@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS project_git_sources (
+    id TEXT PRIMARY KEY,
+    project_id TEXT NOT NULL UNIQUE,
+    repository_url TEXT NOT NULL,
+    ref TEXT NOT NULL DEFAULT '',
+    sub_path TEXT NOT NULL DEFAULT '',
+    checkout_path TEXT NOT NULL,
+    auth_type TEXT NOT NULL DEFAULT 'none',
+    username TEXT,
+    credential TEXT NOT NULL DEFAULT '',
+    auto_sync BOOLEAN NOT NULL DEFAULT false,
+    sync_interval INTEGER NOT NULL DEFAULT 5,
+    auto_deploy BOOLEAN NOT NULL DEFAULT true,
+    last_commit TEXT,
+    last_sync_at DATETIME,
+    last_sync_error TEXT,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME,
+    FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE
+);
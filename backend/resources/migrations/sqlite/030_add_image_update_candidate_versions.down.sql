@@ -0,0 +1 @@
+-- no-op: dropping columns in SQLite requires table rebuild; intentionally left empty
@@ -0,0 +1 @@
+ALTER TABLE image_updates ADD COLUMN candidate_versions TEXT;
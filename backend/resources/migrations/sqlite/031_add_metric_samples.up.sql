@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS metric_samples (
+    id TEXT PRIMARY KEY,
+    scope TEXT NOT NULL,
+    container_id TEXT NOT NULL DEFAULT '',
+    container_name TEXT NOT NULL DEFAULT '',
+    timestamp DATETIME NOT NULL,
+    resolution INTEGER NOT NULL DEFAULT 0,
+    sample_count INTEGER NOT NULL DEFAULT 1,
+    cpu_percent REAL NOT NULL DEFAULT 0,
+    cpu_percent_max REAL NOT NULL DEFAULT 0,
+    memory_used INTEGER NOT NULL DEFAULT 0,
+    memory_used_max INTEGER NOT NULL DEFAULT 0,
+    memory_limit INTEGER NOT NULL DEFAULT 0,
+    disk_used INTEGER NOT NULL DEFAULT 0,
+    disk_total INTEGER NOT NULL DEFAULT 0,
+    network_rx INTEGER NOT NULL DEFAULT 0,
+    network_tx INTEGER NOT NULL DEFAULT 0,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME
+);
+
+CREATE INDEX IF NOT EXISTS idx_metric_samples_series ON metric_samples(scope, container_name, timestamp);
+CREATE INDEX IF NOT EXISTS idx_metric_samples_resolution ON metric_samples(resolution, timestamp);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_alert_states_status;
+DROP INDEX IF EXISTS idx_alert_states_rule_key;
+DROP TABLE IF EXISTS alert_states;
+DROP TABLE IF EXISTS alert_rules;
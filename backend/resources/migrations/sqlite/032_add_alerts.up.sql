@@ -0,0 +1,31 @@
+CREATE TABLE IF NOT EXISTS alert_rules (
+    id TEXT PRIMARY KEY,
+    name TEXT NOT NULL,
+    type TEXT NOT NULL,
+    enabled BOOLEAN NOT NULL DEFAULT true,
+    target TEXT NOT NULL DEFAULT '',
+    threshold REAL NOT NULL DEFAULT 0,
+    duration_minutes INTEGER NOT NULL DEFAULT 0,
+    cooldown_minutes INTEGER NOT NULL DEFAULT 60,
+    notify_resolved BOOLEAN NOT NULL DEFAULT true,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME
+);
+
+CREATE TABLE IF NOT EXISTS alert_states (
+    id TEXT PRIMARY KEY,
+    rule_id TEXT NOT NULL REFERENCES alert_rules(id) ON DELETE CASCADE,
+    key TEXT NOT NULL,
+    status TEXT NOT NULL,
+    value REAL NOT NULL DEFAULT 0,
+    message TEXT NOT NULL DEFAULT '',
+    pending_since DATETIME,
+    fired_at DATETIME,
+    resolved_at DATETIME,
+    last_notified_at DATETIME,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_alert_states_rule_key ON alert_states(rule_id, key);
+CREATE INDEX IF NOT EXISTS idx_alert_states_status ON alert_states(status);
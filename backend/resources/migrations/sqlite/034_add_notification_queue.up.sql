@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS notification_queue (
+    id TEXT PRIMARY KEY,
+    provider VARCHAR(50) NOT NULL,
+    event_type TEXT NOT NULL,
+    message TEXT,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME
+);
+
+CREATE INDEX IF NOT EXISTS idx_notification_queue_provider ON notification_queue(provider);
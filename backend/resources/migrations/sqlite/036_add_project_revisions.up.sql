@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS project_revisions (
+    id TEXT PRIMARY KEY,
+    project_id TEXT NOT NULL,
+    revision INTEGER NOT NULL,
+    compose_content TEXT NOT NULL DEFAULT '',
+    env_content TEXT NOT NULL DEFAULT '',
+    include_files TEXT,
+    message TEXT NOT NULL DEFAULT '',
+    user_id TEXT,
+    username TEXT,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME,
+    FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_project_revisions_project_revision ON project_revisions(project_id, revision);
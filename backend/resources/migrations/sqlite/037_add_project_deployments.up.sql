@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS project_deployments (
+    id TEXT PRIMARY KEY,
+    project_id TEXT NOT NULL,
+    number INTEGER NOT NULL,
+    revision_id TEXT,
+    compose_hash TEXT NOT NULL DEFAULT '',
+    images TEXT,
+    trigger_type TEXT NOT NULL DEFAULT 'user',
+    trigger_detail TEXT,
+    user_id TEXT,
+    username TEXT,
+    rollback_of TEXT,
+    status TEXT NOT NULL,
+    error TEXT,
+    started_at DATETIME NOT NULL,
+    finished_at DATETIME NOT NULL,
+    duration_ms INTEGER NOT NULL DEFAULT 0,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME,
+    FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_project_deployments_project_number ON project_deployments(project_id, number);
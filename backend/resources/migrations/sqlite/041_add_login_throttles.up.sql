@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS login_throttles (
+    id TEXT PRIMARY KEY,
+    scope TEXT NOT NULL,
+    identifier TEXT NOT NULL,
+    failures INTEGER NOT NULL DEFAULT 0,
+    last_failure_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    locked_until DATETIME,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_login_throttles_scope_identifier ON login_throttles(scope, identifier);
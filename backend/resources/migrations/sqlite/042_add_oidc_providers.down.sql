@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS oidc_role_mappings;
+DROP TABLE IF EXISTS oidc_providers;
@@ -0,0 +1 @@
+ALTER TABLE oidc_providers DROP COLUMN manage_default_role;
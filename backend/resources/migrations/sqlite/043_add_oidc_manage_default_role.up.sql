@@ -0,0 +1 @@
+ALTER TABLE oidc_providers ADD COLUMN manage_default_role BOOLEAN NOT NULL DEFAULT FALSE;
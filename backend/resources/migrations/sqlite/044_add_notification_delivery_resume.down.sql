@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_notification_queue_subscription_id;
+
+ALTER TABLE notification_queue DROP COLUMN delivery_id;
+ALTER TABLE notification_queue DROP COLUMN subscription_id;
+ALTER TABLE notification_deliveries DROP COLUMN next_attempt_at;
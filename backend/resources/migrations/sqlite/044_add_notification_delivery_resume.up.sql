@@ -0,0 +1,5 @@
+ALTER TABLE notification_deliveries ADD COLUMN next_attempt_at DATETIME;
+ALTER TABLE notification_queue ADD COLUMN subscription_id TEXT;
+ALTER TABLE notification_queue ADD COLUMN delivery_id TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_notification_queue_subscription_id ON notification_queue(subscription_id);
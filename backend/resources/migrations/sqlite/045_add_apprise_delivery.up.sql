@@ -0,0 +1 @@
+ALTER TABLE apprise_settings ADD COLUMN delivery TEXT;
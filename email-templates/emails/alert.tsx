@@ -0,0 +1,214 @@
+import { Column, Hr, Row, Section, Text } from '@react-email/components';
+import { BaseTemplate } from '../components/base-template';
+import CardHeader from '../components/card-header';
+import { sharedPreviewProps, sharedTemplateProps } from '../props';
+
+interface AlertEmailProps {
+  logoURL: string;
+  appURL: string;
+  title: string;
+  titleUpper: string;
+  ruleName: string;
+  resource: string;
+  status: string;
+  statusColor: string;
+  message: string;
+  value: string;
+  threshold: string;
+  eventTime: string;
+  footer: string;
+}
+
+export const AlertEmail = ({
+  logoURL,
+  appURL,
+  title,
+  ruleName,
+  resource,
+  status,
+  statusColor,
+  message,
+  value,
+  threshold,
+  eventTime,
+  footer,
+}: AlertEmailProps) => {
+  return (
+    <BaseTemplate logoURL={logoURL} appURL={appURL}>
+      <CardHeader title={title} />
+
+      <Section style={{ marginTop: '24px' }}>
+        <Text style={mainTextStyle}>{message}</Text>
+      </Section>
+
+      <Section style={infoSectionStyle}>
+        <Row style={infoRowStyle}>
+          <Column style={labelColumnStyle}>
+            <Text style={labelStyle}>Rule:</Text>
+          </Column>
+          <Column>
+            <Text style={valueStyle}>{ruleName}</Text>
+          </Column>
+        </Row>
+
+        <Hr style={dividerStyle} />
+
+        <Row style={infoRowStyle}>
+          <Column style={labelColumnStyle}>
+            <Text style={labelStyle}>Resource:</Text>
+          </Column>
+          <Column>
+            <Text style={valueStyle}>{resource}</Text>
+          </Column>
+        </Row>
+
+        <Hr style={dividerStyle} />
+
+        <Row style={infoRowStyle}>
+          <Column style={labelColumnStyle}>
+            <Text style={labelStyle}>Status:</Text>
+          </Column>
+          <Column>
+            <Text style={{ ...statusStyle, color: statusColor }}>{status}</Text>
+          </Column>
+        </Row>
+
+        <Hr style={dividerStyle} />
+
+        <Row style={infoRowStyle}>
+          <Column style={labelColumnStyle}>
+            <Text style={labelStyle}>Value:</Text>
+          </Column>
+          <Column>
+            <Text style={metricStyle}>{value}</Text>
+          </Column>
+        </Row>
+
+        <Hr style={dividerStyle} />
+
+        <Row style={infoRowStyle}>
+          <Column style={labelColumnStyle}>
+            <Text style={labelStyle}>Threshold:</Text>
+          </Column>
+          <Column>
+            <Text style={metricStyle}>{threshold}</Text>
+          </Column>
+        </Row>
+
+        <Hr style={dividerStyle} />
+
+        <Row style={infoRowStyle}>
+          <Column style={labelColumnStyle}>
+            <Text style={labelStyle}>Checked At:</Text>
+          </Column>
+          <Column>
+            <Text style={valueStyle}>{eventTime}</Text>
+          </Column>
+        </Row>
+      </Section>
+
+      <Section style={{ marginTop: '24px' }}>
+        <Text style={footerStyle}>This is an automated notification from Arcane. {footer}</Text>
+      </Section>
+    </BaseTemplate>
+  );
+};
+
+export default AlertEmail;
+
+const mainTextStyle = {
+  fontSize: '16px',
+  lineHeight: '24px',
+  color: '#cbd5e1',
+  margin: '0 0 16px 0',
+};
+
+const infoSectionStyle = {
+  marginTop: '20px',
+  backgroundColor: 'rgba(15, 23, 42, 0.5)',
+  border: '1px solid rgba(148, 163, 184, 0.1)',
+  padding: '20px',
+  borderRadius: '12px',
+};
+
+const infoRowStyle = {
+  marginBottom: '0',
+};
+
+const labelColumnStyle = {
+  width: '140px',
+  verticalAlign: 'top' as const,
+  paddingRight: '12px',
+};
+
+const labelStyle = {
+  fontSize: '14px',
+  fontWeight: '600' as const,
+  color: '#94a3b8',
+  margin: '8px 0',
+};
+
+const valueStyle = {
+  fontSize: '14px',
+  color: '#e2e8f0',
+  margin: '8px 0',
+  wordBreak: 'break-word' as const,
+};
+
+const metricStyle = {
+  fontSize: '13px',
+  color: '#e2e8f0',
+  fontFamily: "'Courier New', Courier, monospace",
+  margin: '8px 0',
+};
+
+const statusStyle = {
+  fontSize: '14px',
+  fontWeight: '600' as const,
+  margin: '8px 0',
+};
+
+const dividerStyle = {
+  borderColor: 'rgba(148, 163, 184, 0.2)',
+  margin: '4px 0',
+};
+
+const footerStyle = {
+  fontSize: '13px',
+  lineHeight: '20px',
+  color: '#94a3b8',
+  margin: '0',
+};
+
+// The plain-text export upper-cases headings, which would mangle {{.Title}}; the text
+// template uses {{.TitleUpper}} in its place. User-entered values are escaped in the
+// HTML output and written as-is in the text template.
+AlertEmail.TemplateProps = {
+  ...sharedTemplateProps,
+  title: '{{html .Title}}',
+  titleUpper: '{{.TitleUpper}}',
+  ruleName: '{{html .RuleName}}',
+  resource: '{{html .Resource}}',
+  status: '{{.Status}}',
+  statusColor: '{{.StatusColor}}',
+  message: '{{html .Message}}',
+  value: '{{.Value}}',
+  threshold: '{{.Threshold}}',
+  eventTime: '{{.EventTime}}',
+  footer: '{{.Footer}}',
+};
+
+AlertEmail.PreviewProps = {
+  ...sharedPreviewProps,
+  title: 'Alert Firing: High CPU',
+  titleUpper: 'ALERT FIRING: HIGH CPU',
+  ruleName: 'High CPU',
+  resource: 'my-app-container',
+  status: '● Firing',
+  statusColor: '#f87171',
+  message: 'CPU usage of my-app-container has been above 90% for 5 minutes.',
+  value: '97.3%',
+  threshold: '90%',
+  eventTime: '2025-10-27 15:30:00 UTC',
+  footer: 'You will be notified again when the alert resolves.',
+};
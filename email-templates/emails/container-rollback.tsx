@@ -0,0 +1,217 @@
+import { Column, Hr, Row, Section, Text } from '@react-email/components';
+import { BaseTemplate } from '../components/base-template';
+import CardHeader from '../components/card-header';
+import { sharedPreviewProps, sharedTemplateProps } from '../props';
+
+interface ContainerRollbackEmailProps {
+  logoURL: string;
+  appURL: string;
+  title: string;
+  titleUpper: string;
+  containerName: string;
+  imageRef: string;
+  status: string;
+  reason: string;
+  failedDigest: string;
+  restoredDigest: string;
+  eventTime: string;
+  footer: string;
+}
+
+export const ContainerRollbackEmail = ({
+  logoURL,
+  appURL,
+  title,
+  containerName,
+  imageRef,
+  status,
+  reason,
+  failedDigest,
+  restoredDigest,
+  eventTime,
+  footer,
+}: ContainerRollbackEmailProps) => {
+  const truncateDigest = (digest: string) => {
+    if (digest.length > 19) {
+      return digest.substring(0, 19) + '...';
+    }
+    return digest;
+  };
+
+  return (
+    <BaseTemplate logoURL={logoURL} appURL={appURL}>
+      <CardHeader title={title} />
+
+      <Section style={{ marginTop: '24px' }}>
+        <Text style={mainTextStyle}>The updated container did not pass its health check: {reason}</Text>
+      </Section>
+
+      <Section style={infoSectionStyle}>
+        <Row style={infoRowStyle}>
+          <Column style={labelColumnStyle}>
+            <Text style={labelStyle}>Container:</Text>
+          </Column>
+          <Column>
+            <Text style={valueStyle}>{containerName}</Text>
+          </Column>
+        </Row>
+
+        <Hr style={dividerStyle} />
+
+        <Row style={infoRowStyle}>
+          <Column style={labelColumnStyle}>
+            <Text style={labelStyle}>Image:</Text>
+          </Column>
+          <Column>
+            <Text style={valueStyle}>{imageRef}</Text>
+          </Column>
+        </Row>
+
+        <Hr style={dividerStyle} />
+
+        <Row style={infoRowStyle}>
+          <Column style={labelColumnStyle}>
+            <Text style={labelStyle}>Status:</Text>
+          </Column>
+          <Column>
+            <Text style={statusStyle}>{status}</Text>
+          </Column>
+        </Row>
+
+        <Hr style={dividerStyle} />
+
+        <Row style={infoRowStyle}>
+          <Column style={labelColumnStyle}>
+            <Text style={labelStyle}>Failed Version:</Text>
+          </Column>
+          <Column>
+            <Text style={digestStyle}>{truncateDigest(failedDigest)}</Text>
+          </Column>
+        </Row>
+
+        <Hr style={dividerStyle} />
+
+        <Row style={infoRowStyle}>
+          <Column style={labelColumnStyle}>
+            <Text style={labelStyle}>Restored Version:</Text>
+          </Column>
+          <Column>
+            <Text style={digestStyle}>{truncateDigest(restoredDigest)}</Text>
+          </Column>
+        </Row>
+
+        <Hr style={dividerStyle} />
+
+        <Row style={infoRowStyle}>
+          <Column style={labelColumnStyle}>
+            <Text style={labelStyle}>Checked At:</Text>
+          </Column>
+          <Column>
+            <Text style={valueStyle}>{eventTime}</Text>
+          </Column>
+        </Row>
+      </Section>
+
+      <Section style={{ marginTop: '24px' }}>
+        <Text style={footerStyle}>This is an automated notification from Arcane. {footer}</Text>
+      </Section>
+    </BaseTemplate>
+  );
+};
+
+export default ContainerRollbackEmail;
+
+const mainTextStyle = {
+  fontSize: '16px',
+  lineHeight: '24px',
+  color: '#cbd5e1',
+  margin: '0 0 16px 0',
+};
+
+const infoSectionStyle = {
+  marginTop: '20px',
+  backgroundColor: 'rgba(15, 23, 42, 0.5)',
+  border: '1px solid rgba(148, 163, 184, 0.1)',
+  padding: '20px',
+  borderRadius: '12px',
+};
+
+const infoRowStyle = {
+  marginBottom: '0',
+};
+
+const labelColumnStyle = {
+  width: '140px',
+  verticalAlign: 'top' as const,
+  paddingRight: '12px',
+};
+
+const labelStyle = {
+  fontSize: '14px',
+  fontWeight: '600' as const,
+  color: '#94a3b8',
+  margin: '8px 0',
+};
+
+const valueStyle = {
+  fontSize: '14px',
+  color: '#e2e8f0',
+  margin: '8px 0',
+  wordBreak: 'break-word' as const,
+};
+
+const digestStyle = {
+  fontSize: '13px',
+  color: '#e2e8f0',
+  fontFamily: "'Courier New', Courier, monospace",
+  margin: '8px 0',
+};
+
+const statusStyle = {
+  fontSize: '14px',
+  fontWeight: '600' as const,
+  color: '#f87171',
+  margin: '8px 0',
+};
+
+const dividerStyle = {
+  borderColor: 'rgba(148, 163, 184, 0.2)',
+  margin: '4px 0',
+};
+
+const footerStyle = {
+  fontSize: '13px',
+  lineHeight: '20px',
+  color: '#94a3b8',
+  margin: '0',
+};
+
+// The plain-text export upper-cases headings, which would mangle {{.Title}}; the text
+// template uses {{.TitleUpper}} in its place.
+ContainerRollbackEmail.TemplateProps = {
+  ...sharedTemplateProps,
+  title: '{{.Title}}',
+  titleUpper: '{{.TitleUpper}}',
+  containerName: '{{.ContainerName}}',
+  imageRef: '{{.ImageRef}}',
+  status: '{{.Status}}',
+  reason: '{{.Reason}}',
+  failedDigest: '{{.FailedDigest}}',
+  restoredDigest: '{{.RestoredDigest}}',
+  eventTime: '{{.EventTime}}',
+  footer: '{{.Footer}}',
+};
+
+ContainerRollbackEmail.PreviewProps = {
+  ...sharedPreviewProps,
+  title: 'Container Update Rolled Back',
+  titleUpper: 'CONTAINER UPDATE ROLLED BACK',
+  containerName: 'my-app-container',
+  imageRef: 'nginx:latest',
+  status: '↩ Rolled back to previous image',
+  reason: 'container is unhealthy: connection refused',
+  failedDigest: 'sha256:xyz789ghi012345678901234567890123456',
+  restoredDigest: 'sha256:abc123def456789012345678901234567890',
+  eventTime: '2025-10-27 15:30:00 UTC',
+  footer: 'The container has been recreated from its previous image.',
+};
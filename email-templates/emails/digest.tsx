@@ -0,0 +1,174 @@
+import { Column, Hr, Row, Section, Text } from '@react-email/components';
+import { BaseTemplate } from '../components/base-template';
+import CardHeader from '../components/card-header';
+import { sharedPreviewProps, sharedTemplateProps } from '../props';
+
+interface DigestEmailProps {
+  logoURL: string;
+  appURL: string;
+  title: string;
+  titleUpper: string;
+  summary: string;
+  itemEventLabel: string;
+  itemTime: string;
+  itemTitle: string;
+  itemMessage: string;
+  itemSeverityColor: string;
+  moreCount: string;
+  sentAt: string;
+}
+
+// The item props are rendered once, inside a Go range over .Items, so the backend repeats
+// the row for every queued notification.
+export const DigestEmail = ({
+  logoURL,
+  appURL,
+  title,
+  summary,
+  itemEventLabel,
+  itemTime,
+  itemTitle,
+  itemMessage,
+  itemSeverityColor,
+  moreCount,
+  sentAt,
+}: DigestEmailProps) => {
+  return (
+    <BaseTemplate logoURL={logoURL} appURL={appURL}>
+      <CardHeader title={title} />
+
+      <Section style={{ marginTop: '24px' }}>
+        <Text style={mainTextStyle}>{summary}</Text>
+      </Section>
+
+      <Section style={infoSectionStyle}>
+        {rangeOpen}
+        <Hr style={dividerStyle} />
+        {ifClose}
+        <Row style={infoRowStyle}>
+          <Column style={labelColumnStyle}>
+            <Text style={labelStyle}>{itemEventLabel}</Text>
+            <Text style={timeStyle}>{itemTime}</Text>
+          </Column>
+          <Column>
+            <Text style={{ ...valueStyle, fontWeight: '600', color: itemSeverityColor }}>{itemTitle}</Text>
+            <Text style={valueStyle}>{itemMessage}</Text>
+          </Column>
+        </Row>
+        {rangeClose}
+
+        {ifOpen('MoreCount')}
+        <Hr style={dividerStyle} />
+        <Text style={moreStyle}>…and {moreCount} more</Text>
+        {ifClose}
+      </Section>
+
+      <Section style={{ marginTop: '24px' }}>
+        <Text style={footerStyle}>This digest was sent by Arcane at {sentAt} according to this provider's delivery settings.</Text>
+      </Section>
+    </BaseTemplate>
+  );
+};
+
+export default DigestEmail;
+
+// Every row but the first is preceded by a divider.
+const rangeOpen = '{{range $i, $item := .Items}}{{if $i}}';
+const rangeClose = '{{end}}';
+const ifOpen = (field: string) => `{{if .${field}}}`;
+const ifClose = '{{end}}';
+
+const mainTextStyle = {
+  fontSize: '16px',
+  lineHeight: '24px',
+  color: '#cbd5e1',
+  margin: '0 0 16px 0',
+};
+
+const infoSectionStyle = {
+  marginTop: '20px',
+  backgroundColor: 'rgba(15, 23, 42, 0.5)',
+  border: '1px solid rgba(148, 163, 184, 0.1)',
+  padding: '20px',
+  borderRadius: '12px',
+};
+
+const infoRowStyle = {
+  marginBottom: '0',
+};
+
+const labelColumnStyle = {
+  width: '140px',
+  verticalAlign: 'top' as const,
+  paddingRight: '12px',
+};
+
+const labelStyle = {
+  fontSize: '14px',
+  fontWeight: '600' as const,
+  color: '#94a3b8',
+  margin: '8px 0',
+};
+
+const timeStyle = {
+  fontSize: '12px',
+  lineHeight: '16px',
+  color: '#64748b',
+  margin: '0 0 8px 0',
+};
+
+const valueStyle = {
+  fontSize: '14px',
+  color: '#e2e8f0',
+  margin: '8px 0',
+  wordBreak: 'break-word' as const,
+};
+
+const moreStyle = {
+  fontSize: '14px',
+  color: '#94a3b8',
+  margin: '8px 0',
+};
+
+const dividerStyle = {
+  borderColor: 'rgba(148, 163, 184, 0.2)',
+  margin: '4px 0',
+};
+
+const footerStyle = {
+  fontSize: '13px',
+  lineHeight: '20px',
+  color: '#94a3b8',
+  margin: '0',
+};
+
+// The plain-text export upper-cases headings, which would mangle {{.Title}}; the text
+// template uses {{.TitleUpper}} in its place. Item titles and messages can carry image
+// and container names and are escaped in the HTML output.
+DigestEmail.TemplateProps = {
+  ...sharedTemplateProps,
+  title: '{{html .Title}}',
+  titleUpper: '{{.TitleUpper}}',
+  summary: '{{.Summary}}',
+  itemEventLabel: '{{.EventLabel}}',
+  itemTime: '{{.Time}}',
+  itemTitle: '{{html .Title}}',
+  itemMessage: '{{html .Message}}',
+  itemSeverityColor: '{{.SeverityColor}}',
+  moreCount: '{{.MoreCount}}',
+  sentAt: '{{.SentAt}}',
+};
+
+DigestEmail.PreviewProps = {
+  ...sharedPreviewProps,
+  title: 'Arcane Digest: 40 Notification(s)',
+  titleUpper: 'ARCANE DIGEST: 40 NOTIFICATION(S)',
+  summary: 'Image Updates: 38 · Container Updates: 2',
+  itemEventLabel: 'Image Updates',
+  itemTime: 'Mon, 27 Oct 2025 15:30:00 UTC',
+  itemTitle: 'Container Image Update Available: nginx:latest',
+  itemMessage: 'A new update has been detected for nginx:latest',
+  itemSeverityColor: '#60a5fa',
+  moreCount: '15',
+  sentAt: 'Mon, 27 Oct 2025 16:00:00 UTC',
+};
@@ -0,0 +1,214 @@
+import { Column, Hr, Row, Section, Text } from '@react-email/components';
+import { BaseTemplate } from '../components/base-template';
+import CardHeader from '../components/card-header';
+import { sharedPreviewProps, sharedTemplateProps } from '../props';
+
+interface EventEmailProps {
+  logoURL: string;
+  appURL: string;
+  title: string;
+  titleUpper: string;
+  eventType: string;
+  resource: string;
+  severity: string;
+  severityColor: string;
+  message: string;
+  environment: string;
+  user: string;
+  eventTime: string;
+  footer: string;
+}
+
+export const EventEmail = ({
+  logoURL,
+  appURL,
+  title,
+  eventType,
+  resource,
+  severity,
+  severityColor,
+  message,
+  environment,
+  user,
+  eventTime,
+  footer,
+}: EventEmailProps) => {
+  return (
+    <BaseTemplate logoURL={logoURL} appURL={appURL}>
+      <CardHeader title={title} />
+
+      <Section style={{ marginTop: '24px' }}>
+        <Text style={mainTextStyle}>{message}</Text>
+      </Section>
+
+      <Section style={infoSectionStyle}>
+        <Row style={infoRowStyle}>
+          <Column style={labelColumnStyle}>
+            <Text style={labelStyle}>Event:</Text>
+          </Column>
+          <Column>
+            <Text style={valueStyle}>{eventType}</Text>
+          </Column>
+        </Row>
+
+        <Hr style={dividerStyle} />
+
+        <Row style={infoRowStyle}>
+          <Column style={labelColumnStyle}>
+            <Text style={labelStyle}>Resource:</Text>
+          </Column>
+          <Column>
+            <Text style={valueStyle}>{resource}</Text>
+          </Column>
+        </Row>
+
+        <Hr style={dividerStyle} />
+
+        <Row style={infoRowStyle}>
+          <Column style={labelColumnStyle}>
+            <Text style={labelStyle}>Severity:</Text>
+          </Column>
+          <Column>
+            <Text style={{ ...statusStyle, color: severityColor }}>{severity}</Text>
+          </Column>
+        </Row>
+
+        <Hr style={dividerStyle} />
+
+        <Row style={infoRowStyle}>
+          <Column style={labelColumnStyle}>
+            <Text style={labelStyle}>Environment:</Text>
+          </Column>
+          <Column>
+            <Text style={metricStyle}>{environment}</Text>
+          </Column>
+        </Row>
+
+        <Hr style={dividerStyle} />
+
+        <Row style={infoRowStyle}>
+          <Column style={labelColumnStyle}>
+            <Text style={labelStyle}>User:</Text>
+          </Column>
+          <Column>
+            <Text style={valueStyle}>{user}</Text>
+          </Column>
+        </Row>
+
+        <Hr style={dividerStyle} />
+
+        <Row style={infoRowStyle}>
+          <Column style={labelColumnStyle}>
+            <Text style={labelStyle}>Occurred At:</Text>
+          </Column>
+          <Column>
+            <Text style={valueStyle}>{eventTime}</Text>
+          </Column>
+        </Row>
+      </Section>
+
+      <Section style={{ marginTop: '24px' }}>
+        <Text style={footerStyle}>This is an automated notification from Arcane. {footer}</Text>
+      </Section>
+    </BaseTemplate>
+  );
+};
+
+export default EventEmail;
+
+const mainTextStyle = {
+  fontSize: '16px',
+  lineHeight: '24px',
+  color: '#cbd5e1',
+  margin: '0 0 16px 0',
+};
+
+const infoSectionStyle = {
+  marginTop: '20px',
+  backgroundColor: 'rgba(15, 23, 42, 0.5)',
+  border: '1px solid rgba(148, 163, 184, 0.1)',
+  padding: '20px',
+  borderRadius: '12px',
+};
+
+const infoRowStyle = {
+  marginBottom: '0',
+};
+
+const labelColumnStyle = {
+  width: '140px',
+  verticalAlign: 'top' as const,
+  paddingRight: '12px',
+};
+
+const labelStyle = {
+  fontSize: '14px',
+  fontWeight: '600' as const,
+  color: '#94a3b8',
+  margin: '8px 0',
+};
+
+const valueStyle = {
+  fontSize: '14px',
+  color: '#e2e8f0',
+  margin: '8px 0',
+  wordBreak: 'break-word' as const,
+};
+
+const metricStyle = {
+  fontSize: '13px',
+  color: '#e2e8f0',
+  fontFamily: "'Courier New', Courier, monospace",
+  margin: '8px 0',
+};
+
+const statusStyle = {
+  fontSize: '14px',
+  fontWeight: '600' as const,
+  margin: '8px 0',
+};
+
+const dividerStyle = {
+  borderColor: 'rgba(148, 163, 184, 0.2)',
+  margin: '4px 0',
+};
+
+const footerStyle = {
+  fontSize: '13px',
+  lineHeight: '20px',
+  color: '#94a3b8',
+  margin: '0',
+};
+
+// The plain-text export upper-cases headings, which would mangle {{.Title}}; the text
+// template uses {{.TitleUpper}} in its place. Event titles, descriptions, resource names,
+// usernames and subscription names are escaped in the HTML output.
+EventEmail.TemplateProps = {
+  ...sharedTemplateProps,
+  title: '{{html .Title}}',
+  titleUpper: '{{.TitleUpper}}',
+  eventType: '{{.EventType}}',
+  resource: '{{html .Resource}}',
+  severity: '{{.Severity}}',
+  severityColor: '{{.SeverityColor}}',
+  message: '{{html .Message}}',
+  environment: '{{html .Environment}}',
+  user: '{{html .User}}',
+  eventTime: '{{.EventTime}}',
+  footer: '{{html .Footer}}',
+};
+
+EventEmail.PreviewProps = {
+  ...sharedPreviewProps,
+  title: 'Project deployment failed: my-stack',
+  titleUpper: 'PROJECT DEPLOYMENT FAILED: MY-STACK',
+  eventType: 'project.error',
+  resource: 'my-stack',
+  severity: 'error',
+  severityColor: '#f87171',
+  message: 'Failed to deploy project my-stack: image pull failed.',
+  environment: 'Local Docker',
+  user: 'admin',
+  eventTime: '2025-10-27 15:30:00 UTC',
+  footer: 'You are receiving this because of the "Failed deploys" notification subscription.',
+};
@@ -1,4 +1,4 @@
-import { Column, Hr, Row, Section, Text } from '@react-email/components';
+import { Column, Hr, Link, Row, Section, Text } from '@react-email/components';
 import { BaseTemplate } from '../components/base-template';
 import CardHeader from '../components/card-header';
 import { sharedPreviewProps, sharedTemplateProps } from '../props';
@@ -12,6 +12,10 @@ interface ImageUpdateEmailProps {
   currentDigest: string;
   latestDigest: string;
   checkTime: string;
+  newVersion?: string;
+  releaseTitle?: string;
+  releaseURL?: string;
+  releaseNotes?: string;
 }
 
 export const ImageUpdateEmail = ({
@@ -23,6 +27,10 @@ export const ImageUpdateEmail = ({
   currentDigest,
   latestDigest,
   checkTime,
+  newVersion,
+  releaseTitle,
+  releaseURL,
+  releaseNotes,
 }: ImageUpdateEmailProps) => {
   const truncateDigest = (digest: string) => {
     if (digest.length > 19) {
@@ -108,6 +116,52 @@ export const ImageUpdateEmail = ({
           </>
         )}
 
+        {newVersion && (
+          <>
+            {ifOpen('NewVersion')}
+            <Hr style={dividerStyle} />
+            <Row style={infoRowStyle}>
+              <Column style={labelColumnStyle}>
+                <Text style={labelStyle}>New Version:</Text>
+              </Column>
+              <Column>
+                <Text style={valueStyle}>{newVersion}</Text>
+              </Column>
+            </Row>
+            {ifClose}
+          </>
+        )}
+
+        {releaseURL && (
+          <>
+            {ifOpen('ReleaseURL')}
+            <Hr style={dividerStyle} />
+            <Row style={infoRowStyle}>
+              <Column style={labelColumnStyle}>
+                <Text style={labelStyle}>Changelog:</Text>
+              </Column>
+              <Column>
+                <Text style={valueStyle}>
+                  <Link href={releaseURL} style={linkStyle}>
+                    {releaseTitle || releaseURL}
+                  </Link>
+                </Text>
+              </Column>
+            </Row>
+            {ifClose}
+          </>
+        )}
+
+        {releaseNotes && (
+          <>
+            {ifOpen('ReleaseNotes')}
+            <Hr style={dividerStyle} />
+            <Text style={labelStyle}>Release Notes:</Text>
+            <Text style={notesStyle}>{releaseNotes}</Text>
+            {ifClose}
+          </>
+        )}
+
         {checkTime && (
           <>
             <Hr style={dividerStyle} />
@@ -135,6 +189,25 @@ export const ImageUpdateEmail = ({
 
 export default ImageUpdateEmail;
 
+// Optional rows are wrapped in Go template conditionals so they only render when the
+// backend provides a value.
+const ifOpen = (field: string) => `{{if .${field}}}`;
+const ifClose = '{{end}}';
+
+const linkStyle = {
+  color: '#a78bfa',
+  textDecoration: 'none',
+};
+
+const notesStyle = {
+  fontSize: '13px',
+  lineHeight: '20px',
+  color: '#e2e8f0',
+  margin: '8px 0',
+  whiteSpace: 'pre-wrap' as const,
+  wordBreak: 'break-word' as const,
+};
+
 const mainTextStyle = {
   fontSize: '16px',
   lineHeight: '24px',
@@ -215,6 +288,10 @@ ImageUpdateEmail.TemplateProps = {
   currentDigest: '{{.CurrentDigest}}',
   latestDigest: '{{.LatestDigest}}',
   checkTime: '{{.CheckTime}}',
+  newVersion: '{{html .NewVersion}}',
+  releaseTitle: '{{if .ReleaseTitle}}{{html .ReleaseTitle}}{{else}}{{html .ReleaseURL}}{{end}}',
+  releaseURL: '{{html .ReleaseURL}}',
+  releaseNotes: '{{html .ReleaseNotes}}',
 };
 
 ImageUpdateEmail.PreviewProps = {
@@ -225,4 +302,8 @@ ImageUpdateEmail.PreviewProps = {
   currentDigest: 'sha256:abc123def456789012345678901234567890',
   latestDigest: 'sha256:xyz789ghi012345678901234567890123456',
   checkTime: '2025-10-18 15:30:00 UTC',
+  newVersion: '1.27.3',
+  releaseTitle: 'nginx 1.27.3',
+  releaseURL: 'https://github.com/nginx/nginx/releases/tag/release-1.27.3',
+  releaseNotes: '- Bugfix: worker processes could crash on reload\n- Feature: new directive',
 };
@@ -2,7 +2,7 @@ import { goto, invalidateAll } from '$app/navigation';
 import BaseAPIService from './api-service';
 import userStore from '$lib/stores/user-store';
 import type { User } from '$lib/types/user.type';
-import type { OidcStatusInfo } from '$lib/types/settings.type';
+import type { OidcLoginProvider, OidcStatusInfo } from '$lib/types/settings.type';
 import type { OidcUserInfo, LoginCredentials, LoginResponseData } from '$lib/types/auth.type';
 
 const REFRESH_TOKEN_KEY = 'arcane_refresh_token';
@@ -145,13 +145,17 @@ export class AuthService extends BaseAPIService {
 		}
 	}
 
-	async getAuthUrl(redirectUri: string): Promise<string> {
-		const response = (await this.handleResponse(this.api.post('/oidc/url', { redirectUri }))) as {
+	async getAuthUrl(redirectUri: string, provider?: string): Promise<string> {
+		const response = (await this.handleResponse(this.api.post('/oidc/url', { redirectUri, provider }))) as {
 			authUrl: string;
 		};
 		return response.authUrl;
 	}
 
+	async getLoginProviders(): Promise<OidcLoginProvider[]> {
+		return this.handleResponse<OidcLoginProvider[]>(this.api.get('/oidc/login-providers'));
+	}
+
 	async handleCallback(
 		code: string,
 		state: string
@@ -0,0 +1,20 @@
+export interface AuditLogChange {
+	before?: unknown;
+	after?: unknown;
+}
+
+export interface AuditLog {
+	id: string;
+	timestamp: string;
+	userId?: string;
+	username?: string;
+	sourceIp?: string;
+	userAgent?: string;
+	action: string;
+	targetType: string;
+	targetId?: string;
+	targetName?: string;
+	changes?: Record<string, AuditLogChange>;
+	createdAt: string;
+	updatedAt?: string;
+}
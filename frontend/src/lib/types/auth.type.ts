@@ -24,3 +24,58 @@ export type LoginResponseData = {
 	user: User;
 	requirePasswordChange?: boolean;
 };
+
+export type LoginTotpChallenge = {
+	totpRequired: true;
+	enrollmentRequired: boolean;
+	challengeToken: string;
+	expiresAt: string;
+};
+
+export type LoginTotpResponseData = LoginResponseData & {
+	recoveryCodes?: string[];
+};
+
+export interface TotpSetup {
+	secret: string;
+	uri: string;
+}
+
+export interface Passkey {
+	id: string;
+	name: string;
+	aaguid?: string;
+	transports?: string[];
+	backedUp: boolean;
+	lastUsedAt?: string;
+	createdAt: string;
+}
+
+// Options are in the JSON form accepted by PublicKeyCredential.parseCreationOptionsFromJSON
+// and parseRequestOptionsFromJSON; the session token is sent back with the result.
+export interface PasskeyCeremony<TOptions> {
+	options: TOptions;
+	sessionToken: string;
+}
+
+export interface Session {
+	id: string;
+	method: string;
+	userAgent: string;
+	ipAddress: string;
+	lastSeenAt: string;
+	expiresAt: string;
+	createdAt: string;
+	current: boolean;
+}
+
+// A username or client IP that cannot sign in until lockedUntil because of repeated
+// failed attempts.
+export interface Lockout {
+	id: string;
+	scope: 'username' | 'ip';
+	identifier: string;
+	failures: number;
+	lastFailureAt: string;
+	lockedUntil: string;
+}
@@ -3,6 +3,7 @@ export interface ImageUpdateInfoDto {
 	updateType: string;
 	currentVersion: string;
 	latestVersion: string;
+	candidateVersions?: string[];
 	currentDigest: string;
 	latestDigest: string;
 	checkTime: string;
@@ -12,6 +13,17 @@ export interface ImageUpdateInfoDto {
 	authUsername?: string;
 	authRegistry?: string;
 	usedCredential?: boolean;
+	release?: ImageReleaseInfo;
+}
+
+export interface ImageReleaseInfo {
+	source?: string;
+	version?: string;
+	revision?: string;
+	title?: string;
+	notes?: string;
+	url?: string;
+	publishedAt?: string;
 }
 
 export interface ImageUsageCounts {
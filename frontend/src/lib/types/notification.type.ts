@@ -1,4 +1,4 @@
-export type NotificationProvider = 'discord' | 'email';
+export type NotificationProvider = 'discord' | 'email' | 'webhook' | 'slack' | 'teams' | 'ntfy' | 'gotify';
 export type EmailTLSMode = 'none' | 'starttls' | 'ssl';
 
 export interface DiscordConfig {
@@ -17,7 +17,58 @@ export interface EmailConfig {
 	tlsMode: EmailTLSMode;
 }
 
-export type NotificationConfig = DiscordConfig | EmailConfig;
+export interface WebhookConfig {
+	url: string;
+	method?: 'POST' | 'PUT' | 'PATCH';
+	headers?: Record<string, string>;
+	contentType?: string;
+	bodyTemplate?: string;
+}
+
+export interface SlackConfig {
+	webhookUrl: string;
+	channel?: string;
+	username?: string;
+	iconEmoji?: string;
+}
+
+export interface TeamsConfig {
+	webhookUrl: string;
+}
+
+export interface NtfyConfig {
+	serverUrl?: string;
+	topic: string;
+	accessToken?: string;
+	username?: string;
+	password?: string;
+	priority?: number;
+	tags?: string[];
+}
+
+export interface GotifyConfig {
+	serverUrl: string;
+	appToken: string;
+	priority?: number;
+}
+
+export type NotificationConfig = DiscordConfig | EmailConfig | WebhookConfig | SlackConfig | TeamsConfig | NtfyConfig | GotifyConfig;
+
+export type NotificationDeliveryMode = 'immediate' | 'hourly' | 'daily';
+
+export interface NotificationQuietHours {
+	enabled: boolean;
+	start: string;
+	end: string;
+}
+
+// Stored under the "delivery" key of any provider config.
+export interface NotificationDeliveryConfig {
+	mode?: NotificationDeliveryMode;
+	digestTime?: string;
+	quietHours?: NotificationQuietHours;
+	timezone?: string;
+}
 
 export interface NotificationSettings {
 	provider: NotificationProvider;
@@ -31,6 +82,7 @@ export interface AppriseSettings {
 	enabled: boolean;
 	imageUpdateTag: string;
 	containerUpdateTag: string;
+	delivery?: NotificationDeliveryConfig;
 }
 
 export interface TestNotificationResponse {
@@ -38,3 +90,33 @@ export interface TestNotificationResponse {
 	message?: string;
 	error?: string;
 }
+
+export interface NotificationSubscription {
+	id: string;
+	name: string;
+	provider: NotificationProvider;
+	enabled: boolean;
+	config?: Record<string, any>;
+	eventTypes: string[];
+	severities: string[];
+	environmentIds: string[];
+	resourcePatterns: string[];
+	createdAt: string;
+	updatedAt?: string;
+}
+
+export type NotificationDeliveryStatus = 'pending' | 'queued' | 'success' | 'failed';
+
+export interface NotificationDelivery {
+	id: string;
+	subscriptionId: string;
+	eventId: string;
+	eventType: string;
+	provider: NotificationProvider;
+	status: NotificationDeliveryStatus;
+	attempts: number;
+	lastError?: string;
+	nextAttemptAt?: string;
+	deliveredAt?: string;
+	createdAt: string;
+}
@@ -50,3 +50,58 @@ export interface ProjectStatusCounts {
 	stoppedProjects: number;
 	totalProjects: number;
 }
+
+export interface ProjectRevisionSummary {
+	id: string;
+	projectId: string;
+	revision: number;
+	message: string;
+	userId?: string;
+	username?: string;
+	createdAt: string;
+}
+
+export interface ProjectRevision extends ProjectRevisionSummary {
+	composeContent: string;
+	envContent: string;
+	includeFiles?: Record<string, string>;
+}
+
+export interface ProjectRevisionFileDiff {
+	file: string;
+	diff: string;
+}
+
+export interface ProjectRevisionDiff {
+	fromRevision: number;
+	toRevision: number;
+	files: ProjectRevisionFileDiff[];
+}
+
+export type DeploymentTrigger = 'user' | 'webhook' | 'git' | 'rollback' | 'system';
+
+export interface DeploymentImage {
+	image: string;
+	imageId: string;
+	pinned: string;
+}
+
+export interface ProjectDeployment {
+	id: string;
+	projectId: string;
+	number: number;
+	revisionId?: string;
+	composeHash: string;
+	images?: Record<string, DeploymentImage>;
+	trigger: DeploymentTrigger;
+	triggerDetail?: string;
+	userId?: string;
+	username?: string;
+	rollbackOf?: string;
+	status: 'success' | 'failed';
+	error?: string;
+	startedAt: string;
+	finishedAt: string;
+	durationMs: number;
+	createdAt: string;
+}
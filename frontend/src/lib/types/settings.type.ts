@@ -12,9 +12,30 @@ export type Settings = {
 	diskUsagePath: string;
 	autoUpdate: boolean;
 	autoUpdateInterval: number;
+	autoUpdateRollback: boolean;
+	autoUpdateHealthTimeout: number;
+	autoUpdateGracePeriod: number;
+	autoUpdatePolicy: 'pin' | 'digest' | 'patch' | 'minor' | 'major';
+	autoUpdateWindow: string;
+	autoUpdateWindowTimezone: string;
+	autoUpdateWindowDuration: number;
 	pollingEnabled: boolean;
 	pollingInterval: number;
 	environmentHealthInterval: number;
+	metricsEnabled: boolean;
+	metricsInterval: number;
+	metricsRetentionDays: number;
+	eventSinkSyslogEnabled: boolean;
+	eventSinkSyslogProtocol: 'udp' | 'tcp' | 'tls';
+	eventSinkSyslogAddress: string;
+	eventSinkLokiEnabled: boolean;
+	eventSinkLokiUrl: string;
+	eventSinkLokiLabels: string;
+	eventSinkLokiTenantId: string;
+	eventSinkFileEnabled: boolean;
+	eventSinkFilePath: string;
+	eventSinkFileMaxSize: number;
+	eventSinkFileMaxBackups: number;
 	dockerPruneMode: 'all' | 'dangling';
 	maxImageUploadSize: number;
 	baseServerUrl: string;
@@ -22,14 +43,23 @@ export type Settings = {
 	uiConfigDisabled: boolean;
 	defaultShell: string;
 	dockerHost: string;
+	backupDirectory: string;
+	backupAllowedPaths: string;
+	backupHelperImage: string;
 	accentColor: string;
 
 	authLocalEnabled: boolean;
 	authOidcEnabled: boolean;
 	authOidcMergeAccounts: boolean;
+	authOidcManageDefaultRole: boolean;
 	authSessionTimeout: number;
 	authPasswordPolicy: 'basic' | 'standard' | 'strong';
+	authRequireTotp: boolean;
+	authPasskeysEnabled: boolean;
+	authLockoutThreshold: number;
+	authLockoutDuration: number;
 	authOidcConfig: string;
+	auditLogRetentionDays: number;
 
 	onboardingCompleted: boolean;
 	onboardingSteps: {
@@ -57,6 +87,7 @@ export interface RegistryCredential {
 }
 
 export interface OidcConfig {
+	name?: string; // login button label
 	clientId: string;
 	clientSecret?: string;
 	issuerUrl: string;
@@ -66,6 +97,35 @@ export interface OidcConfig {
 	adminValue?: string; // e.g., "admin" (comma-separated accepted values)
 }
 
+// A provider offered on the login page; name is empty for an unnamed default provider.
+export interface OidcLoginProvider {
+	slug: string;
+	name: string;
+}
+
+export interface OidcProvider {
+	id: string;
+	slug: string;
+	name: string;
+	issuerUrl: string;
+	clientId: string;
+	scopes: string;
+	enabled: boolean;
+	hasClientSecret: boolean;
+	createdAt: string;
+}
+
+// Grants roleName to users whose claim (a dotted path such as "groups") contains value.
+// An empty provider applies to every provider.
+export interface OidcRoleMapping {
+	id: string;
+	provider: string;
+	claim: string;
+	value: string;
+	roleName: string;
+	createdAt: string;
+}
+
 export interface OidcStatusInfo {
 	envForced: boolean;
 	envConfigured: boolean;
@@ -13,11 +13,12 @@ export type User = {
 	oidcSubjectId?: string;
 	locale?: Locale;
 	requiresPasswordChange?: boolean;
+	totpEnabled?: boolean;
 };
 
 export type CreateUser = Omit<
 	User,
-	'id' | 'createdAt' | 'updatedAt' | 'lastLogin' | 'oidcSubjectId' | 'passwordHash' | 'requiresPasswordChange' | 'roles'
+	'id' | 'createdAt' | 'updatedAt' | 'lastLogin' | 'oidcSubjectId' | 'passwordHash' | 'requiresPasswordChange' | 'roles' | 'totpEnabled'
 > & {
 	password: string;
 	roles?: string[];
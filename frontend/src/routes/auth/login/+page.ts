@@ -1,4 +1,6 @@
 import { redirect } from '@sveltejs/kit';
+import { authService } from '$lib/services/auth-service';
+import type { OidcLoginProvider } from '$lib/types/settings.type';
 
 export const load = async ({ parent, url }) => {
 	const data = await parent();
@@ -11,10 +13,16 @@ export const load = async ({ parent, url }) => {
 
 	const error = url.searchParams.get('error');
 
+	let oidcProviders: OidcLoginProvider[] = [];
+	if (data.settings?.authOidcEnabled) {
+		oidcProviders = await authService.getLoginProviders().catch(() => []);
+	}
+
 	return {
 		settings: data.settings,
 		redirectTo,
 		error,
+		oidcProviders,
 		versionInformation: data.versionInformation
 	};
 };